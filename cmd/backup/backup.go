@@ -0,0 +1,244 @@
+// Package backup provides the command for listing and restoring backups and
+// for rotating the backup encryption key
+package backup
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/backup/targets"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Command creates the backup parent command
+func Command(settings *conf.Settings) *cobra.Command {
+	backupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "List and restore backups and manage the backup encryption key",
+	}
+
+	backupCmd.AddCommand(listCommand(settings), restoreCommand(settings), rotateKeyCommand(settings))
+
+	return backupCmd
+}
+
+// listCommand creates the backup list subcommand
+func listCommand(settings *conf.Settings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List backups held by the configured backup targets",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager, err := newManager(settings)
+			if err != nil {
+				return err
+			}
+
+			backups, err := manager.ListBackups(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to list backups: %w", err)
+			}
+			if len(backups) == 0 {
+				_, err := fmt.Fprintln(cmd.OutOrStdout(), "No backups found")
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "ID\tTARGET\tCREATED\tSIZE\tENCRYPTED")
+			for i := range backups {
+				b := &backups[i]
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n",
+					b.ID, b.Target, b.Timestamp.Local().Format("2006-01-02 15:04:05"), formatSize(b.Size), b.Encrypted)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// rotateKeyCommand creates the backup rotate-key subcommand
+func rotateKeyCommand(settings *conf.Settings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Replace the backup encryption key with a new one",
+		Long: `Replace the backup encryption key with a new one.
+
+New backups are encrypted with the new key. The previous key is kept in the
+encryption-keys directory next to it, so existing backups can still be
+restored. Keep a copy of the new encryption.key file somewhere safe: backups
+made from now on cannot be restored without it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager, err := newManager(settings)
+			if err != nil {
+				return err
+			}
+
+			keyID, err := manager.RotateEncryptionKey()
+			if err != nil {
+				return fmt.Errorf("failed to rotate encryption key: %w", err)
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "New encryption key %s is now active\n", keyID)
+			return err
+		},
+	}
+}
+
+// restoreCommand creates the backup restore subcommand
+func restoreCommand(settings *conf.Settings) *cobra.Command {
+	var (
+		archivePath   string
+		dbPath        string
+		dryRun        bool
+		restoreConfig bool
+		force         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore [backup-id]",
+		Short: "Verify and restore a backup",
+		Long: `Verify and restore a backup of the SQLite database.
+
+The backup is downloaded from the target holding it, decrypted and checked
+against the stored checksums and the SQLite integrity check before anything is
+changed. The replaced database is kept next to it with a .pre-restore suffix.
+
+BirdNET-Go must be stopped while the database is replaced. To restore while it
+runs, use the web interface, which swaps the database in on the next restart.
+
+Examples:
+  # Verify a backup without restoring it
+  birdnet-go backup restore sqlite-20260101-030000 --dry-run
+
+  # Restore a backup and the configuration stored with it
+  birdnet-go backup restore sqlite-20260101-030000 --restore-config
+
+  # Restore a downloaded archive, e.g. from an FTP or rsync target
+  birdnet-go backup restore --file ./sqlite-20260101-030000.tar.enc`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := &backup.RestoreOptions{
+				ArchivePath:  archivePath,
+				DatabasePath: dbPath,
+				DryRun:       dryRun,
+			}
+			if len(args) == 1 {
+				opts.BackupID = args[0]
+			}
+			if (opts.BackupID == "") == (opts.ArchivePath == "") {
+				return fmt.Errorf("specify either a backup ID or --file")
+			}
+			if opts.DatabasePath == "" {
+				opts.DatabasePath = settings.Output.SQLite.Path
+			}
+			if !dryRun && opts.DatabasePath == "" {
+				return fmt.Errorf("no SQLite database configured, use --db to set the database path")
+			}
+			if restoreConfig && !dryRun {
+				configPath, err := conf.FindConfigFile()
+				if err != nil {
+					return fmt.Errorf("failed to locate the configuration file: %w", err)
+				}
+				opts.ConfigPath = configPath
+			}
+
+			if !dryRun && !force {
+				if err := backup.CheckDatabaseIdle(cmd.Context(), opts.DatabasePath); err != nil {
+					return fmt.Errorf("%w; stop BirdNET-Go before restoring, or use --force", err)
+				}
+			}
+
+			manager, err := newManager(settings)
+			if err != nil {
+				return err
+			}
+
+			result, err := manager.Restore(cmd.Context(), opts)
+			if result != nil {
+				printResult(cmd.OutOrStdout(), result)
+			}
+			if err != nil {
+				return fmt.Errorf("restore failed: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&archivePath, "file", "", "Restore from a downloaded backup archive instead of a backup ID")
+	cmd.Flags().StringVar(&dbPath, "db", "", "Database to replace (defaults to the configured SQLite path)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Verify the backup without changing anything")
+	cmd.Flags().BoolVar(&restoreConfig, "restore-config", false, "Also restore the configuration file, keeping the current secrets")
+	cmd.Flags().BoolVar(&force, "force", false, "Skip the check that the database is not in use")
+
+	return cmd
+}
+
+// newManager creates a backup manager with the configured targets. Targets
+// are registered even when scheduled backups are disabled so existing
+// backups can still be restored.
+func newManager(settings *conf.Settings) (*backup.Manager, error) {
+	log := backup.GetLogger()
+
+	stateManager, err := backup.NewStateManager(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize backup state: %w", err)
+	}
+	manager, err := backup.NewManager(settings, log, stateManager, settings.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize backup manager: %w", err)
+	}
+	if err := targets.RegisterConfigured(manager, &settings.Backup, log); err != nil {
+		// Backups on the remaining targets can still be listed and restored
+		log.Warn("Some backup targets could not be initialized", logger.Error(err))
+	}
+	return manager, nil
+}
+
+// printResult writes a summary of a restore to w
+func printResult(w io.Writer, result *backup.RestoreResult) {
+	_, _ = fmt.Fprintf(w, "Backup:           %s (%s)\n", result.Metadata.ID, result.Metadata.Timestamp.Local().Format("2006-01-02 15:04:05"))
+	_, _ = fmt.Fprintf(w, "Archive checksum: %s\n", verifiedLabel(result.ArchiveVerified))
+	_, _ = fmt.Fprintf(w, "Data checksum:    %s\n", verifiedLabel(result.DataVerified))
+	_, _ = fmt.Fprintf(w, "Integrity check:  %s\n", result.Integrity)
+	if result.DryRun {
+		_, _ = fmt.Fprintln(w, "Dry run, nothing was changed")
+		return
+	}
+	if result.DatabasePath != "" {
+		_, _ = fmt.Fprintf(w, "Restored database %s\n", result.DatabasePath)
+	}
+	if result.PreviousDatabase != "" {
+		_, _ = fmt.Fprintf(w, "Previous database kept as %s\n", result.PreviousDatabase)
+	}
+	if result.ConfigPath != "" {
+		_, _ = fmt.Fprintf(w, "Restored configuration %s\n", result.ConfigPath)
+	}
+	if result.PreviousConfig != "" {
+		_, _ = fmt.Fprintf(w, "Previous configuration kept as %s\n", result.PreviousConfig)
+	}
+}
+
+// verifiedLabel describes whether a checksum was verified
+func verifiedLabel(verified bool) string {
+	if verified {
+		return "verified"
+	}
+	return "not recorded, skipped"
+}
+
+// formatSize formats a byte count for display
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,48 @@
+// Package directory implements the directory command for offline batch analysis.
+package directory
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/cmd/file"
+	"github.com/tphakala/birdnet-go/internal/analysis"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Command creates the directory command which analyses every recording in a directory.
+func Command(settings *conf.Settings) *cobra.Command {
+	var saveToDB bool
+
+	cmd := &cobra.Command{
+		Use:   "directory [path]",
+		Short: "Analyze all audio files in a directory",
+		Long: `Analyze every WAV and FLAC recording in a directory, for example the
+contents of an SD card collected from an unattended recorder.
+
+Files are processed in name order. Files that cannot be decoded are reported
+and skipped. With --watch the command keeps running after the initial pass and
+analyzes new recordings once they have finished copying.
+
+Result tables written to --output keep the subdirectory of each recording.
+Detections saved with --db are matched against those already stored, so
+re-running a directory does not import the same detections twice.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings.Input.Path = args[0]
+			settings.Output.File.Enabled = !saveToDB || cmd.Flags().Changed("output") || cmd.Flags().Changed("type")
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return analysis.DirectoryAnalysis(ctx, settings, saveToDB)
+		},
+	}
+
+	file.SetupOutputFlags(cmd, settings, &saveToDB)
+	cmd.Flags().BoolVarP(&settings.Input.Recursive, "recursive", "r", false, "Recursively analyze subdirectories")
+	cmd.Flags().BoolVarP(&settings.Input.Watch, "watch", "w", false, "Watch the directory for new files after the initial pass")
+
+	return cmd
+}
@@ -0,0 +1,49 @@
+// Package file implements the file command for offline analysis of a single recording.
+package file
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/analysis"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Command creates the file command which analyses a single WAV or FLAC file.
+func Command(settings *conf.Settings) *cobra.Command {
+	var saveToDB bool
+
+	cmd := &cobra.Command{
+		Use:   "file [input.wav|input.flac]",
+		Short: "Analyze an audio file",
+		Long: `Analyze a single WAV or FLAC recording with the configured BirdNET model.
+
+Detections are written as a Raven selection table (default) or BirdNET-Analyzer
+style CSV next to the input file, or into --output when given. With --db the
+detections are also stored in the configured database as file source detections.
+Recording time is taken from a YYYYMMDD_HHMMSS stamp in the file name when
+present, otherwise from the file modification time.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings.Input.Path = args[0]
+			settings.Output.File.Enabled = !saveToDB || cmd.Flags().Changed("output") || cmd.Flags().Changed("type")
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return analysis.FileAnalysis(ctx, settings, saveToDB)
+		},
+	}
+
+	SetupOutputFlags(cmd, settings, &saveToDB)
+
+	return cmd
+}
+
+// SetupOutputFlags registers the output flags shared by the offline analysis commands.
+func SetupOutputFlags(cmd *cobra.Command, settings *conf.Settings, saveToDB *bool) {
+	cmd.Flags().StringVarP(&settings.Output.File.Path, "output", "o", "", "Directory for result files (default: next to each input file)")
+	cmd.Flags().StringVar(&settings.Output.File.Type, "type", "table", "Result file type: table (Raven selection table) or csv")
+	cmd.Flags().BoolVar(saveToDB, "db", false, "Save detections to the configured database; result files are then only written when --output or --type is also set")
+}
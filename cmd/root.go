@@ -8,7 +8,10 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/tphakala/birdnet-go/cmd/authors"
+	"github.com/tphakala/birdnet-go/cmd/backup"
 	"github.com/tphakala/birdnet-go/cmd/benchmark"
+	"github.com/tphakala/birdnet-go/cmd/directory"
+	"github.com/tphakala/birdnet-go/cmd/file"
 	"github.com/tphakala/birdnet-go/cmd/importstage"
 	"github.com/tphakala/birdnet-go/cmd/license"
 	"github.com/tphakala/birdnet-go/cmd/notify"
@@ -34,6 +37,8 @@ func RootCommand(settings *conf.Settings) *cobra.Command {
 
 	// Add sub-commands to the root command.
 	serveCmd := serve.Command(settings)
+	fileCmd := file.Command(settings)
+	directoryCmd := directory.Command(settings)
 	authorsCmd := authors.Command()
 	licenseCmd := license.Command()
 	rangeCmd := rangefilter.Command(settings)
@@ -41,9 +46,12 @@ func RootCommand(settings *conf.Settings) *cobra.Command {
 	benchmarkCmd := benchmark.Command(settings)
 	notifyCmd := notify.Command(settings)
 	importStageCmd := importstage.Command(settings)
+	backupCmd := backup.Command(settings)
 
 	subcommands := []*cobra.Command{
 		serveCmd,
+		fileCmd,
+		directoryCmd,
 		authorsCmd,
 		licenseCmd,
 		rangeCmd,
@@ -51,6 +59,7 @@ func RootCommand(settings *conf.Settings) *cobra.Command {
 		benchmarkCmd,
 		notifyCmd,
 		importStageCmd,
+		backupCmd,
 	}
 
 	rootCmd.AddCommand(subcommands...)
@@ -197,6 +197,7 @@ export type TranslationKey =
   | 'pageTitle.analyticsSoundscape'
   | 'navigation.dashboard'
   | 'navigation.liveAudio'
+  | 'navigation.soundscapes'
   | 'navigation.settingsMenu'
   | 'navigation.theme'
   | 'navigation.github'
@@ -857,6 +858,20 @@ export type TranslationKey =
   | 'spectrogram.colorMaps.viridis'
   | 'spectrogram.colorMaps.grayscale'
   | 'spectrogram.labels.toggle'
+  | 'soundscapes.page.title'
+  | 'soundscapes.page.description'
+  | 'soundscapes.filters.source'
+  | 'soundscapes.filters.allSources'
+  | 'soundscapes.table.play'
+  | 'soundscapes.table.playAriaLabel'
+  | 'soundscapes.table.start'
+  | 'soundscapes.table.source'
+  | 'soundscapes.table.duration'
+  | 'soundscapes.table.size'
+  | 'soundscapes.empty.title'
+  | 'soundscapes.empty.description'
+  | 'soundscapes.error.loadFailed'
+  | 'soundscapes.error.unavailable'
   | 'system.title'
   | 'system.refreshData'
   | 'system.aria.refreshData'
@@ -1106,6 +1121,14 @@ export type TranslationKey =
   | 'system.database.dashboard.innodb.avgLockWait'
   | 'system.database.dashboard.innodb.tableLocksWaited'
   | 'system.database.dashboard.innodb.tableLocksImmediate'
+  | 'system.database.dashboard.postgres.title'
+  | 'system.database.dashboard.postgres.version'
+  | 'system.database.dashboard.postgres.backends'
+  | 'system.database.dashboard.postgres.cacheHitRate'
+  | 'system.database.dashboard.postgres.commits'
+  | 'system.database.dashboard.postgres.rollbacks'
+  | 'system.database.dashboard.postgres.deadlocks'
+  | 'system.database.dashboard.postgres.tempBytes'
   | 'system.database.dashboard.detectionRate.title'
   | 'system.database.dashboard.detectionRate.chartLabel'
   | 'system.database.dashboard.detectionRate.detectionsTooltip' // params: count
@@ -1118,6 +1141,7 @@ export type TranslationKey =
   | 'system.database.dashboard.detectionRate.createBackup'
   | 'system.database.dashboard.detectionRate.mysqlHost'
   | 'system.database.dashboard.detectionRate.mysqlBackupNote'
+  | 'system.database.dashboard.detectionRate.postgresBackupNote'
   | 'system.database.dashboard.tables.title'
   | 'system.database.dashboard.tables.total'
   | 'system.database.dashboard.tables.table'
@@ -1132,6 +1156,7 @@ export type TranslationKey =
   | 'system.database.backup.cancel'
   | 'system.database.backup.retry'
   | 'system.database.backup.mysqlNote'
+  | 'system.database.backup.postgresNote'
   | 'system.database.backup.history.title'
   | 'system.database.backup.history.creating'
   | 'system.database.backup.history.createBackup'
@@ -1280,8 +1305,10 @@ export type TranslationKey =
   | 'system.database.migration.prerequisites.checks.legacy_accessible.name'
   | 'system.database.migration.prerequisites.checks.sqlite_integrity.name'
   | 'system.database.migration.prerequisites.checks.mysql_table_health.name'
+  | 'system.database.migration.prerequisites.checks.postgres_table_health.name'
   | 'system.database.migration.prerequisites.checks.write_permission.name'
   | 'system.database.migration.prerequisites.checks.mysql_permissions.name'
+  | 'system.database.migration.prerequisites.checks.postgres_permissions.name'
   | 'system.database.migration.prerequisites.checks.record_count.name'
   | 'system.database.migration.prerequisites.checks.existing_v2_data.name'
   | 'system.database.migration.prerequisites.checks.no_active_locks.name'
@@ -1824,6 +1851,24 @@ export type TranslationKey =
   | 'settings.main.sections.database.mysql.database.label'
   | 'settings.main.sections.database.mysql.database.placeholder'
   | 'settings.main.sections.database.mysql.database.helpText'
+  | 'settings.main.sections.database.postgres.title'
+  | 'settings.main.sections.database.postgres.description'
+  | 'settings.main.sections.database.postgres.host.label'
+  | 'settings.main.sections.database.postgres.host.placeholder'
+  | 'settings.main.sections.database.postgres.host.helpText'
+  | 'settings.main.sections.database.postgres.port.label'
+  | 'settings.main.sections.database.postgres.port.helpText'
+  | 'settings.main.sections.database.postgres.username.label'
+  | 'settings.main.sections.database.postgres.username.placeholder'
+  | 'settings.main.sections.database.postgres.username.helpText'
+  | 'settings.main.sections.database.postgres.password.label'
+  | 'settings.main.sections.database.postgres.password.placeholder'
+  | 'settings.main.sections.database.postgres.password.helpText'
+  | 'settings.main.sections.database.postgres.database.label'
+  | 'settings.main.sections.database.postgres.database.placeholder'
+  | 'settings.main.sections.database.postgres.database.helpText'
+  | 'settings.main.sections.database.postgres.sslMode.label'
+  | 'settings.main.sections.database.postgres.sslMode.helpText'
   | 'settings.main.sections.database.stats.title'
   | 'settings.main.sections.database.stats.description'
   | 'settings.main.sections.database.stats.loading'
@@ -2227,6 +2272,14 @@ export type TranslationKey =
   | 'settings.filters.privacyFiltering.disabled'
   | 'settings.filters.privacyFiltering.confidenceLabel'
   | 'settings.filters.privacyFiltering.confidenceHelp'
+  | 'settings.filters.privacyFiltering.modeLabel'
+  | 'settings.filters.privacyFiltering.modeHelp'
+  | 'settings.filters.privacyFiltering.modes.discard'
+  | 'settings.filters.privacyFiltering.modes.redact'
+  | 'settings.filters.privacyFiltering.redactFillLabel'
+  | 'settings.filters.privacyFiltering.redactFillHelp'
+  | 'settings.filters.privacyFiltering.fills.silence'
+  | 'settings.filters.privacyFiltering.fills.noise'
   | 'settings.filters.falsePositivePrevention.title'
   | 'settings.filters.falsePositivePrevention.description'
   | 'settings.filters.falsePositivePrevention.enableDogBark'
@@ -2339,6 +2392,7 @@ export type TranslationKey =
   | 'settings.integration.weather.provider.options.yrno'
   | 'settings.integration.weather.provider.options.openweather'
   | 'settings.integration.weather.provider.options.wunderground'
+  | 'settings.integration.weather.provider.options.station'
   | 'settings.integration.weather.wunderground.apiKey.label'
   | 'settings.integration.weather.wunderground.apiKey.helpText'
   | 'settings.integration.weather.wunderground.stationId.label'
@@ -2347,11 +2401,24 @@ export type TranslationKey =
   | 'settings.integration.weather.wunderground.endpoint.helpText'
   | 'settings.integration.weather.wunderground.units.label'
   | 'settings.integration.weather.wunderground.units.helpText'
+  | 'settings.integration.weather.station.interval.label'
+  | 'settings.integration.weather.station.interval.helpText'
+  | 'settings.integration.weather.station.passKey.label'
+  | 'settings.integration.weather.station.passKey.helpText'
+  | 'settings.integration.weather.station.mqtt.enabled.label'
+  | 'settings.integration.weather.station.mqtt.enabled.helpText'
+  | 'settings.integration.weather.station.mqtt.broker.label'
+  | 'settings.integration.weather.station.mqtt.broker.helpText'
+  | 'settings.integration.weather.station.mqtt.topic.label'
+  | 'settings.integration.weather.station.mqtt.topic.helpText'
+  | 'settings.integration.weather.station.mqtt.username.label'
+  | 'settings.integration.weather.station.mqtt.password.label'
   | 'settings.integration.weather.notes.none'
   | 'settings.integration.weather.notes.yrno.description'
   | 'settings.integration.weather.notes.yrno.freeService'
   | 'settings.integration.weather.notes.openweather'
   | 'settings.integration.weather.notes.wunderground'
+  | 'settings.integration.weather.notes.station'
   | 'settings.integration.weather.apiKey.label'
   | 'settings.integration.weather.apiKey.helpText'
   | 'settings.integration.weather.units.label'
@@ -2641,6 +2708,8 @@ export type TranslationKey =
   | 'settings.audio.clipRecording.preCaptureHelp' // params: max
   | 'settings.audio.clipRecording.gainLabel'
   | 'settings.audio.clipRecording.gainHelp'
+  | 'settings.audio.clipRecording.embeddings'
+  | 'settings.audio.clipRecording.embeddingsHelp'
   | 'settings.audio.clipRecording.normalization'
   | 'settings.audio.clipRecording.normalizationEnable'
   | 'settings.audio.clipRecording.normalizationHelp'
@@ -2712,12 +2781,45 @@ export type TranslationKey =
   | 'settings.audio.audioClipRetention.policies.none'
   | 'settings.audio.audioClipRetention.policies.age'
   | 'settings.audio.audioClipRetention.policies.usage'
+  | 'settings.audio.audioClipRetention.policies.tier'
   | 'settings.audio.audioClipRetention.noRetentionTitle'
   | 'settings.audio.audioClipRetention.noRetentionDescription'
   | 'settings.audio.audioClipRetention.ageRetentionTitle'
   | 'settings.audio.audioClipRetention.ageRetentionDescription'
   | 'settings.audio.audioClipRetention.usageRetentionTitle'
   | 'settings.audio.audioClipRetention.usageRetentionDescription'
+  | 'settings.audio.audioClipRetention.tierRetentionTitle'
+  | 'settings.audio.audioClipRetention.tierRetentionDescription'
+  | 'settings.audio.audioClipRetention.tier.targetLabel'
+  | 'settings.audio.audioClipRetention.tier.targetHelp'
+  | 'settings.audio.audioClipRetention.tier.targets.local'
+  | 'settings.audio.audioClipRetention.tier.targets.s3'
+  | 'settings.audio.audioClipRetention.tier.targets.sftp'
+  | 'settings.audio.audioClipRetention.tier.localPathLabel'
+  | 'settings.audio.audioClipRetention.tier.localPathHelp'
+  | 'settings.audio.audioClipRetention.tier.s3.endpointLabel'
+  | 'settings.audio.audioClipRetention.tier.s3.endpointHelp'
+  | 'settings.audio.audioClipRetention.tier.s3.regionLabel'
+  | 'settings.audio.audioClipRetention.tier.s3.bucketLabel'
+  | 'settings.audio.audioClipRetention.tier.s3.prefixLabel'
+  | 'settings.audio.audioClipRetention.tier.s3.prefixHelp'
+  | 'settings.audio.audioClipRetention.tier.s3.accessKeyIdLabel'
+  | 'settings.audio.audioClipRetention.tier.s3.accessKeyIdHelp'
+  | 'settings.audio.audioClipRetention.tier.s3.secretAccessKeyLabel'
+  | 'settings.audio.audioClipRetention.tier.s3.useSSL'
+  | 'settings.audio.audioClipRetention.tier.s3.pathStyle'
+  | 'settings.audio.audioClipRetention.tier.s3.pathStyleHelp'
+  | 'settings.audio.audioClipRetention.tier.sftp.hostLabel'
+  | 'settings.audio.audioClipRetention.tier.sftp.portLabel'
+  | 'settings.audio.audioClipRetention.tier.sftp.usernameLabel'
+  | 'settings.audio.audioClipRetention.tier.sftp.passwordLabel'
+  | 'settings.audio.audioClipRetention.tier.sftp.passwordHelp'
+  | 'settings.audio.audioClipRetention.tier.sftp.privateKeyPathLabel'
+  | 'settings.audio.audioClipRetention.tier.sftp.privateKeyPathHelp'
+  | 'settings.audio.audioClipRetention.tier.sftp.knownHostsFileLabel'
+  | 'settings.audio.audioClipRetention.tier.sftp.knownHostsFileHelp'
+  | 'settings.audio.audioClipRetention.tier.sftp.pathLabel'
+  | 'settings.audio.audioClipRetention.tier.sftp.pathHelp'
   | 'settings.audio.formats.wav'
   | 'settings.audio.formats.flac'
   | 'settings.audio.formats.aac'
@@ -3277,6 +3379,7 @@ export type TranslationKey =
   | 'settings.alerts.schema.events.device_stopped'
   | 'settings.alerts.schema.events.device_error'
   | 'settings.alerts.schema.metrics.system_cpu_usage'
+  | 'settings.alerts.schema.metrics.system_cpu_temperature'
   | 'settings.alerts.schema.metrics.system_memory_usage'
   | 'settings.alerts.schema.metrics.system_disk_usage'
   | 'settings.alerts.schema.properties.value'
@@ -3291,6 +3394,9 @@ export type TranslationKey =
   | 'settings.alerts.schema.properties.device_name'
   | 'settings.alerts.schema.properties.error'
   | 'settings.alerts.schema.properties.broker'
+  | 'settings.alerts.schema.properties.time_of_day'
+  | 'settings.alerts.schema.properties.sun_window'
+  | 'settings.alerts.schema.properties.event_count'
   | 'settings.alerts.schema.operators.is'
   | 'settings.alerts.schema.operators.is_not'
   | 'settings.alerts.schema.operators.in'
@@ -3301,6 +3407,8 @@ export type TranslationKey =
   | 'settings.alerts.schema.operators.less_than'
   | 'settings.alerts.schema.operators.greater_or_equal'
   | 'settings.alerts.schema.operators.less_or_equal'
+  | 'settings.alerts.schema.operators.between'
+  | 'settings.alerts.schema.operators.not_between'
   | 'settings.alerts.v2Required'
   | 'settings.alerts.v2RequiredLink'
   | 'settings.alerts.builtInRules.newSpecies.name'
@@ -3616,6 +3724,8 @@ export type TranslationKey =
   | 'errors.alert.duplicateName'
   | 'errors.alert.invalidJSON'
   | 'errors.alert.invalidEscalation'
+  | 'errors.alert.invalidAction'
+  | 'errors.alert.invalidConditions'
   | 'errors.alert.engineUnavailable'
   | 'errors.detection.invalidDate' // params: paramName
   | 'errors.backup.invalidType'
@@ -3882,6 +3992,7 @@ export type TranslationKey =
   | 'restart.reasons.database'
   | 'restart.reasons.logging'
   | 'restart.reasons.tlsCertificate'
+  | 'restart.reasons.backupRestore'
   | 'help.title'
   | 'help.subtitle'
   | 'help.reportBug.description'
@@ -4134,6 +4245,7 @@ export type TranslationParams = {
   'detections.errors.loadFailed': { status: string | number };
   'species.rarity.basedOnLocation': { latitude: string | number; longitude: string | number };
   'spectrogram.gain.level': { value: string | number };
+  'soundscapes.table.playAriaLabel': { time: string | number };
   'system.systemInfo.temperatureValue': { temp: string | number };
   'system.errors.systemInfo': { error: string | number };
   'system.errors.diskUsage': { error: string | number };
@@ -36,6 +36,15 @@ const CONFIG_ENDPOINT = '/api/v2/app/config';
 /** Maximum number of retry attempts for config fetch */
 export const MAX_RETRIES = 3;
 
+/**
+ * Role of the current user. Roles are ordered: every role includes the
+ * permissions of the roles before it.
+ */
+export type UserRole = 'viewer' | 'reviewer' | 'admin';
+
+/** Rank of each role, lowest first */
+const ROLE_RANK: Record<UserRole, number> = { viewer: 1, reviewer: 2, admin: 3 };
+
 /** Retry delays in milliseconds (exponential backoff) */
 const RETRY_DELAYS = [1000, 2000, 4000];
 
@@ -58,6 +67,8 @@ interface AppConfigResponse {
       liveAudio: boolean;
     };
     privateMode?: boolean;
+    /** Role of the current user; absent when access is not allowed */
+    role?: UserRole;
   };
   version: string;
   /** Dataset version for the per-locale species-name dictionary. Used as a cache-buster. */
@@ -151,6 +162,8 @@ interface AppState {
       liveAudio: boolean;
     };
     privateMode: boolean;
+    /** Role of the current user; null when access is not allowed */
+    role: UserRole | null;
   };
 }
 
@@ -200,6 +213,7 @@ const DEFAULT_STATE: AppState = {
       liveAudio: false,
     },
     privateMode: false,
+    role: null,
   },
 };
 
@@ -310,6 +324,7 @@ export async function initApp(): Promise<boolean> {
           liveAudio: config.security.publicAccess?.liveAudio ?? false,
         },
         privateMode: config.security.privateMode ?? false,
+        role: config.security.role ?? null,
       };
 
       appState.freshInstall = config.freshInstall ?? false;
@@ -464,6 +479,19 @@ export function isGuestMode(): boolean {
   return appState.security.enabled && !appState.security.accessAllowed;
 }
 
+/**
+ * Checks if the current user's role includes the given role. Everyone is an
+ * admin when security is disabled.
+ *
+ * @param role The minimum role required
+ * @returns True if the user holds role or a higher one
+ */
+export function hasRole(role: UserRole): boolean {
+  if (!appState.security.enabled) return true;
+  const current = appState.security.role;
+  return current !== null && ROLE_RANK[current] >= ROLE_RANK[role];
+}
+
 /**
  * Gets the authentication configuration.
  *
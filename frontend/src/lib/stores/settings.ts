@@ -138,9 +138,28 @@ export interface MySQLSettings {
   port: string;
 }
 
+export type PostgresSSLMode =
+  | 'disable'
+  | 'allow'
+  | 'prefer'
+  | 'require'
+  | 'verify-ca'
+  | 'verify-full';
+
+export interface PostgresSettings {
+  enabled: boolean;
+  username: string;
+  password: string;
+  database: string;
+  host: string;
+  port: string;
+  sslMode: PostgresSSLMode;
+}
+
 export interface OutputSettings {
   sqlite: SQLiteSettings;
   mysql: MySQLSettings;
+  postgres: PostgresSettings;
 }
 
 // AudioSourceConfig represents a single sound card audio source
@@ -149,9 +168,24 @@ export interface AudioSourceConfig {
   device: string;
   gain: number;
   sampleRate?: number; // capture sample rate in Hz; 0 or undefined means 48000
+  channels?: number[]; // 1-based card channels mixed into this source; undefined means all channels
   models: string[]; // e.g. ["birdnet", "perch_v2"]
   equalizer?: EqualizerSettings;
   quietHours?: QuietHoursConfig;
+  deployment?: DeploymentConfig;
+  soundscape?: boolean; // record a continuous soundscape (see AudioSettings.soundscape)
+  species?: SpeciesSettings; // per-source species overrides; undefined means global only
+}
+
+// DeploymentConfig matches backend conf.DeploymentConfig: where a source's
+// microphone is installed. Sources without coordinates are at the station.
+export interface DeploymentConfig {
+  site?: string; // Site name grouping sources, e.g. "North Meadow"
+  latitude?: number; // Decimal degrees (0 with longitude 0 = station location)
+  longitude?: number; // Decimal degrees
+  habitat?: string; // Habitat description, e.g. "wetland edge"
+  microphoneModel?: string; // Microphone make and model
+  microphoneHeight?: number; // Meters above ground
 }
 
 export interface AudioSettings {
@@ -162,6 +196,7 @@ export interface AudioSettings {
   streamTransport?: string;
   export: ExportSettings;
   soundLevel: SoundLevelSettings;
+  soundscape?: SoundscapeSettings;
   useAudioCore?: boolean;
   equalizer: EqualizerSettings;
   quietHours?: QuietHoursConfig;
@@ -172,6 +207,19 @@ export interface SoundLevelSettings {
   interval: number;
 }
 
+// SoundscapeSettings matches backend conf.SoundscapeSettings: continuous
+// recording for sources with soundscape enabled.
+export interface SoundscapeSettings {
+  path: string; // directory for soundscape segments
+  format: 'flac' | 'opus';
+  bitrate: number; // Opus bitrate in kbit/s
+  segmentLength: number; // segment length in seconds (60-900)
+  retention: {
+    maxAge: string; // e.g. "14d"; empty = no age limit
+    maxSizeGB: number; // 0 = no size limit
+  };
+}
+
 // Audio gain bounds in dB, shared by the sound card, stream, and export gain
 // controls. Mirrors the backend MinAudioGain/MaxAudioGain validation range so
 // the frontend clamp and sliders stay in sync if the range ever changes.
@@ -230,6 +278,9 @@ export interface StreamConfig {
   equalizer?: EqualizerSettings; // Per-stream EQ (undefined = use global)
   quietHours?: QuietHoursConfig; // Quiet hours configuration
   gain?: number; // Input gain in dB (0 = no adjustment)
+  deployment?: DeploymentConfig; // Per-stream deployment metadata
+  soundscape?: boolean; // Record a continuous soundscape
+  species?: SpeciesSettings; // Per-stream species overrides (undefined = global only)
 }
 
 // ChannelEnergy represents the energy level of a single audio channel
@@ -294,8 +345,19 @@ export interface ExportSettings {
   preCapture: number; // pre-capture in seconds
   gain: number; // gain in dB for audio capture
   normalization: NormalizationSettings; // audio normalization settings (EBU R128)
+  embeddings?: EmbeddingSettings; // per-clip embeddings for similar recording search
+}
+
+export interface EmbeddingSettings {
+  enabled: boolean; // true to compute and store an embedding for each saved clip
+  modelPath: string; // BirdNET v2.4 embeddings ONNX model; empty uses the bat embedding model
 }
 
+export const DEFAULT_EMBEDDING_SETTINGS: EmbeddingSettings = {
+  enabled: false,
+  modelPath: '',
+};
+
 export interface NormalizationSettings {
   enabled: boolean; // true to enable loudness normalization
   targetLUFS: number; // target integrated loudness in LUFS (default: -23)
@@ -309,10 +371,64 @@ export interface RetentionSettings {
   maxUsage: string;
   minClips: number;
   keepSpectrograms: boolean;
+  tier?: ClipTierSettings; // secondary store for the 'tier' policy
   enabled?: boolean; // legacy, might be present in old data
   maxSize?: number; // legacy, might be present in old data
 }
 
+// ClipTierSettings configures the secondary store the 'tier' retention policy
+// moves older clips to. Secrets arrive redacted from the API.
+export interface ClipTierSettings {
+  target: 'local' | 's3' | 'sftp';
+  local: {
+    path: string; // archive directory, typically a mounted NAS share
+  };
+  s3: {
+    endpoint: string;
+    region: string;
+    bucket: string;
+    prefix: string;
+    accessKeyId: string;
+    secretAccessKey: string;
+    useSSL: boolean;
+    pathStyle: boolean;
+  };
+  sftp: {
+    host: string;
+    port: number;
+    username: string;
+    password: string;
+    privateKeyPath: string;
+    knownHostsFile: string;
+    path: string;
+  };
+}
+
+// Defaults mirror internal/conf/defaults.go for the tier retention policy.
+export const DEFAULT_CLIP_TIER_SETTINGS: ClipTierSettings = {
+  target: 'local',
+  local: { path: '' },
+  s3: {
+    endpoint: 's3.amazonaws.com',
+    region: 'us-east-1',
+    bucket: '',
+    prefix: 'birdnet-go/clips',
+    accessKeyId: '',
+    secretAccessKey: '',
+    useSSL: true,
+    pathStyle: false,
+  },
+  sftp: {
+    host: '',
+    port: 22,
+    username: '',
+    password: '',
+    privateKeyPath: '',
+    knownHostsFile: '',
+    path: '',
+  },
+};
+
 export interface FilterSettings {
   privacy: PrivacyFilterSettings;
   dogBark: DogBarkFilterSettings;
@@ -322,6 +438,8 @@ export interface PrivacyFilterSettings {
   enabled: boolean;
   confidence: number;
   debug: boolean;
+  mode?: 'discard' | 'redact'; // discard detections near human voice, or keep them and blank the voice in saved clips
+  redactFill?: 'silence' | 'noise'; // what replaces redacted voice
 }
 
 export interface PrivacyFilter {
@@ -392,6 +510,7 @@ export interface MQTTSettings {
     skipVerify: boolean;
   };
   homeAssistant?: HomeAssistantSettings;
+  commands?: boolean; // accept control commands on <topic>/cmd/#
 }
 
 export interface ObservabilitySettings {
@@ -417,12 +536,27 @@ export interface WundergroundSettings {
   units: 'm' | 'e' | 'h'; // m=metric, e=imperial/english, h=UK hybrid
 }
 
+export interface StationMQTTSettings {
+  enabled: boolean;
+  broker: string; // empty = use the MQTT integration broker and credentials
+  topic: string;
+  username: string;
+  password: string;
+}
+
+export interface StationSettings {
+  interval: number; // minutes of readings averaged into one record
+  passKey: string;
+  mqtt: StationMQTTSettings;
+}
+
 export interface WeatherSettings {
-  provider: 'none' | 'yrno' | 'openweather' | 'wunderground';
+  provider: 'none' | 'yrno' | 'openweather' | 'wunderground' | 'station';
   pollInterval: number;
   debug: boolean;
   openWeather: OpenWeatherSettings;
   wunderground: WundergroundSettings;
+  station: StationSettings;
 }
 
 // New array-based OAuth provider configuration
@@ -667,6 +801,12 @@ export type SpectrogramStyle = 'default' | 'scientific_dark' | 'high_contrast_da
 // Lower values = higher contrast (weak signals visible), higher values = more detail
 export type SpectrogramDynamicRange = '80' | '100' | '120';
 
+// Spectrogram renderer: in-process ('native') or external Sox with FFmpeg fallback
+export type SpectrogramRenderer = 'native' | 'sox';
+
+// Frequency axis of the native renderer
+export type SpectrogramScale = 'linear' | 'log' | 'mel';
+
 // SpectrogramPreRender contains settings for spectrogram generation modes.
 // Three modes control when and how spectrograms are generated:
 //   - "auto": Generate on-demand when API is called (default, suitable for most systems)
@@ -679,6 +819,8 @@ export interface SpectrogramPreRender {
   raw: boolean; // Generate raw spectrogram without axes/legend (default: true)
   style?: SpectrogramStyle; // Visual style preset (default: 'default')
   dynamicRange?: SpectrogramDynamicRange; // Dynamic range in dB: 80 (high contrast), 100 (standard), 120 (extended)
+  renderer?: SpectrogramRenderer; // Renderer (default: 'native')
+  scale?: SpectrogramScale; // Frequency axis of the native renderer (default: 'linear')
 }
 
 // Default spectrogram settings
@@ -689,6 +831,8 @@ export const DEFAULT_SPECTROGRAM_SETTINGS: SpectrogramPreRender = {
   raw: true,
   style: 'default',
   dynamicRange: '100',
+  renderer: 'native',
+  scale: 'linear',
 } as const;
 
 // Log config
@@ -920,6 +1064,7 @@ function createEmptySettings(): SettingsFormData {
             maxUsage: '80%',
             minClips: 10,
             keepSpectrograms: false,
+            tier: DEFAULT_CLIP_TIER_SETTINGS,
           },
           length: 15, // Default 15 seconds capture length
           preCapture: 3, // Default 3 seconds pre-capture
@@ -930,6 +1075,7 @@ function createEmptySettings(): SettingsFormData {
             loudnessRange: 7.0, // Typical range for broadcast
             truePeak: -2.0, // Headroom to prevent clipping
           },
+          embeddings: DEFAULT_EMBEDDING_SETTINGS,
         },
         soundLevel: {
           enabled: false,
@@ -944,6 +1090,8 @@ function createEmptySettings(): SettingsFormData {
         enabled: false,
         confidence: 0.5,
         debug: false,
+        mode: 'discard',
+        redactFill: 'silence',
       },
       dogBarkFilter: {
         enabled: false,
@@ -1067,6 +1215,15 @@ function createEmptySettings(): SettingsFormData {
         host: 'localhost',
         port: '3306',
       },
+      postgres: {
+        enabled: false,
+        username: '',
+        password: '',
+        database: '',
+        host: 'localhost',
+        port: '5432',
+        sslMode: 'prefer',
+      },
     },
     notification: {
       push: {
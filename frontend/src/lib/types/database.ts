@@ -36,6 +36,21 @@ export interface MySQLDetails {
   table_locks_immediate: number;
 }
 
+/** PostgreSQL engine-specific details */
+export interface PostgresDetails {
+  engine_version: string;
+  active_connections: number;
+  idle_connections: number;
+  max_connections: number;
+  backends: number;
+  server_max_conns: number;
+  cache_hit_rate: number;
+  commits: number;
+  rollbacks: number;
+  deadlocks: number;
+  temp_bytes: number;
+}
+
 /** Per-table statistics */
 export interface TableStats {
   name: string;
@@ -64,7 +79,7 @@ export interface HourlyCount {
 
 /** Top-level database overview response */
 export interface DatabaseOverviewResponse {
-  engine: 'sqlite' | 'mysql';
+  engine: 'sqlite' | 'mysql' | 'postgres';
   status: string;
   location: string;
   size_bytes: number;
@@ -72,6 +87,7 @@ export interface DatabaseOverviewResponse {
   total_tables: number;
   sqlite?: SQLiteDetails;
   mysql?: MySQLDetails;
+  postgres?: PostgresDetails;
   tables: TableStats[] | null;
   performance: PerformanceStats;
   detection_rate_24h: HourlyCount[] | null;
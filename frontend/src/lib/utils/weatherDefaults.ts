@@ -6,6 +6,7 @@
 
 import type {
   OpenWeatherSettings,
+  StationSettings,
   WundergroundSettings,
   WeatherSettings,
 } from '$lib/stores/settings';
@@ -31,6 +32,21 @@ export const wundergroundDefaults: WundergroundSettings = {
   units: 'm', // m=metric, e=imperial, h=UK hybrid
 };
 
+/**
+ * Default configuration for a local weather station
+ */
+export const stationDefaults: StationSettings = {
+  interval: 5,
+  passKey: '',
+  mqtt: {
+    enabled: false,
+    broker: '',
+    topic: 'weather/loop',
+    username: '',
+    password: '',
+  },
+};
+
 /**
  * Complete default weather configuration
  */
@@ -40,6 +56,7 @@ export const weatherDefaults: WeatherSettings = {
   debug: false,
   openWeather: openWeatherDefaults,
   wunderground: wundergroundDefaults,
+  station: stationDefaults,
 };
 
 /**
@@ -47,12 +64,14 @@ export const weatherDefaults: WeatherSettings = {
  */
 export function getProviderDefaults(
   provider: WeatherSettings['provider']
-): OpenWeatherSettings | WundergroundSettings | null {
+): OpenWeatherSettings | WundergroundSettings | StationSettings | null {
   switch (provider) {
     case 'openweather':
       return openWeatherDefaults;
     case 'wunderground':
       return wundergroundDefaults;
+    case 'station':
+      return stationDefaults;
     case 'none':
     case 'yrno':
       return null;
@@ -0,0 +1,559 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// mqttActionTimeout bounds a single MQTT publish.
+	mqttActionTimeout = 10 * time.Second
+	// webhookActionTimeout bounds a webhook request, including the response.
+	webhookActionTimeout = 10 * time.Second
+	// controlActionTimeout bounds handing a control action to the pipeline.
+	controlActionTimeout = 10 * time.Second
+	// defaultScriptTimeout is used for scripts without a configured timeout.
+	defaultScriptTimeout = 30 * time.Second
+	// saveResultTimeout is the context deadline for persisting an action result.
+	saveResultTimeout = 3 * time.Second
+	// maxWebhookResponseBytes is how much of a webhook response is drained
+	// so the connection can be reused.
+	maxWebhookResponseBytes = 64 << 10
+	// maxResultDetailLength and maxResultErrorLength match the column sizes
+	// of entities.AlertActionResult.
+	maxResultDetailLength = 500
+	maxResultErrorLength  = 1000
+	// scriptEnvPrefix starts the environment variables passed to scripts.
+	scriptEnvPrefix = "BIRDNET_ALERT_"
+)
+
+var (
+	errMQTTUnavailable    = errors.NewStd("MQTT is not connected")
+	errControlUnavailable = errors.NewStd("internal controls are not available")
+	errNoSourceID         = errors.NewStd("event has no source_id to restart")
+)
+
+// webhookMethods are the HTTP methods a webhook action may use.
+var webhookMethods = []string{http.MethodPost, http.MethodPut, http.MethodGet}
+
+// controlActions are the internal controls a control action may run.
+var controlActions = []string{ControlRestartAudioSource, ControlRestartAnalysis, ControlReloadModel, ControlRebuildFilter}
+
+// MQTTPublisher publishes alert payloads to the MQTT broker.
+type MQTTPublisher interface {
+	Publish(ctx context.Context, topic, payload string) error
+}
+
+// ControlFunc runs an internal control action such as
+// ControlRestartAudioSource. arg is the action argument, for example the ID of
+// the source to restart.
+type ControlFunc func(ctx context.Context, action, arg string) error
+
+// ActionResultStore persists the outcome of dispatched actions.
+type ActionResultStore interface {
+	SaveActionResult(ctx context.Context, result *entities.AlertActionResult) error
+}
+
+// DispatcherOption configures an optional ActionDispatcher dependency.
+type DispatcherOption func(*ActionDispatcher)
+
+// WithMQTTPublisher sets how MQTT actions find the broker connection.
+// resolve is called for every action because the client is created, and
+// replaced on reconfiguration, after the dispatcher; it returns nil while
+// MQTT is disabled.
+func WithMQTTPublisher(resolve func() MQTTPublisher) DispatcherOption {
+	return func(d *ActionDispatcher) {
+		d.mqtt = resolve
+	}
+}
+
+// WithControlFunc sets the function that runs control actions.
+func WithControlFunc(fn ControlFunc) DispatcherOption {
+	return func(d *ActionDispatcher) {
+		d.control = fn
+	}
+}
+
+// WithActionResultStore records the outcome of every automation action in
+// store, linked to the alert history entry of the firing.
+func WithActionResultStore(store ActionResultStore) DispatcherOption {
+	return func(d *ActionDispatcher) {
+		d.results = store
+	}
+}
+
+// WithScripts overrides where script actions look up configured scripts.
+// By default they are read from the current settings on every dispatch.
+func WithScripts(resolve func() []conf.AlertScript) DispatcherOption {
+	return func(d *ActionDispatcher) {
+		d.scripts = resolve
+	}
+}
+
+// IsAutomationTarget reports whether target is one of the automation action
+// targets, as opposed to a notification target.
+func IsAutomationTarget(target string) bool {
+	switch target {
+	case TargetMQTT, TargetWebhook, TargetScript, TargetControl:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidateAction checks that an automation action has the fields its target
+// needs. Notification actions are not checked: their target may name any
+// push provider.
+func ValidateAction(action *entities.AlertAction) error {
+	switch action.Target {
+	case TargetMQTT:
+		if strings.TrimSpace(action.Topic) == "" {
+			return fmt.Errorf("mqtt action requires a topic")
+		}
+		if strings.ContainsAny(action.Topic, "+#") {
+			return fmt.Errorf("mqtt action topic must not contain wildcards")
+		}
+	case TargetWebhook:
+		if err := validateWebhookURL(action.URL); err != nil {
+			return err
+		}
+		if action.Method != "" && !slices.Contains(webhookMethods, strings.ToUpper(action.Method)) {
+			return fmt.Errorf("webhook method must be one of %s", strings.Join(webhookMethods, ", "))
+		}
+	case TargetScript:
+		if strings.TrimSpace(action.Script) == "" {
+			return fmt.Errorf("script action requires a script name")
+		}
+	case TargetControl:
+		if !slices.Contains(controlActions, action.Control) {
+			return fmt.Errorf("control action must be one of %s", strings.Join(controlActions, ", "))
+		}
+	}
+	return nil
+}
+
+// validateWebhookURL accepts absolute http and https URLs. Template variables
+// are allowed anywhere in the URL.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook action requires an absolute http or https URL")
+	}
+	return nil
+}
+
+// ScriptNames returns the names of the scripts configured for alert actions.
+func ScriptNames() []string {
+	scripts := configuredScripts()
+	names := make([]string, 0, len(scripts))
+	for i := range scripts {
+		names = append(names, scripts[i].Name)
+	}
+	return names
+}
+
+// configuredScripts is the default script lookup.
+func configuredScripts() []conf.AlertScript {
+	if settings := conf.GetSettings(); settings != nil {
+		return settings.Alerting.Scripts
+	}
+	return nil
+}
+
+// actionPayload is the default body of MQTT, webhook and script actions.
+type actionPayload struct {
+	RuleID     uint           `json:"rule_id"`
+	RuleName   string         `json:"rule_name"`
+	ObjectType string         `json:"object_type"`
+	EventName  string         `json:"event_name,omitempty"`
+	MetricName string         `json:"metric_name,omitempty"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Timestamp  time.Time      `json:"timestamp"`
+	Test       bool           `json:"test,omitempty"`
+}
+
+// renderBody renders the payload of an automation action. Without a template
+// the event is sent as JSON. A template that looks like JSON has its values
+// escaped so event properties cannot break the document.
+func renderBody(tmpl string, rule *entities.AlertRule, event *AlertEvent, isTest bool) (string, error) {
+	trimmed := strings.TrimSpace(tmpl)
+	if trimmed == "" {
+		data, err := json.Marshal(actionPayload{
+			RuleID:     rule.ID,
+			RuleName:   rule.Name,
+			ObjectType: event.ObjectType,
+			EventName:  event.EventName,
+			MetricName: event.MetricName,
+			Properties: event.Properties,
+			Timestamp:  event.Timestamp,
+			Test:       isTest,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to encode event payload: %w", err)
+		}
+		return string(data), nil
+	}
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return renderTemplateEscaped(tmpl, rule, event, jsonEscape), nil
+	}
+	return renderTemplate(tmpl, rule, event), nil
+}
+
+// jsonEscape escapes s for use inside a JSON string literal.
+func jsonEscape(s string) string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return ""
+	}
+	return string(data[1 : len(data)-1])
+}
+
+// contentTypeFor guesses the content type of a rendered body.
+func contentTypeFor(body string) string {
+	if json.Valid([]byte(body)) {
+		return "application/json"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// dispatchAutomation runs an automation action in the background so a slow
+// webhook or script never holds up the event bus, then records the outcome.
+func (d *ActionDispatcher) dispatchAutomation(action *entities.AlertAction, rule *entities.AlertRule, event *AlertEvent, isTest bool) {
+	act := *action
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				d.log.Error("panic in alert action",
+					logger.String("target", act.Target),
+					logger.Uint64("rule_id", uint64(rule.ID)))
+				d.telemetry.ReportPanic(r, debug.Stack())
+			}
+		}()
+
+		start := time.Now()
+		status, detail, err := d.runAutomation(&act, rule, event, isTest)
+		if err != nil {
+			d.log.Warn("alert action failed",
+				logger.String("target", act.Target),
+				logger.Uint64("rule_id", uint64(rule.ID)),
+				logger.String("rule_name", rule.Name),
+				logger.Error(err))
+		}
+		d.recordResult(&act, event, isTest, status, detail, err, time.Since(start))
+	}()
+}
+
+// Wait blocks until all background actions have finished.
+func (d *ActionDispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// runAutomation runs one automation action and returns its result status and
+// a short detail for the history.
+func (d *ActionDispatcher) runAutomation(action *entities.AlertAction, rule *entities.AlertRule, event *AlertEvent, isTest bool) (status, detail string, err error) {
+	switch action.Target {
+	case TargetMQTT:
+		detail, err = d.runMQTT(action, rule, event, isTest)
+	case TargetWebhook:
+		detail, err = d.runWebhook(action, rule, event, isTest)
+	case TargetScript:
+		detail, err = d.runScript(action, rule, event, isTest)
+	case TargetControl:
+		return d.runControl(action, rule, event, isTest)
+	default:
+		err = fmt.Errorf("unknown automation target %q", action.Target)
+	}
+	if err != nil {
+		return ActionStatusFailed, detail, err
+	}
+	return ActionStatusSuccess, detail, nil
+}
+
+// runMQTT publishes the rendered body to the rendered topic.
+func (d *ActionDispatcher) runMQTT(action *entities.AlertAction, rule *entities.AlertRule, event *AlertEvent, isTest bool) (string, error) {
+	var client MQTTPublisher
+	if d.mqtt != nil {
+		client = d.mqtt()
+	}
+	if client == nil {
+		return "", errMQTTUnavailable
+	}
+
+	topic := renderTemplate(action.Topic, rule, event)
+	body, err := renderBody(action.TemplateBody, rule, event, isTest)
+	if err != nil {
+		return topic, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mqttActionTimeout)
+	defer cancel()
+	return topic, client.Publish(ctx, topic, body)
+}
+
+// runWebhook sends the rendered body to the rendered URL. Any status other
+// than 2xx is a failure.
+func (d *ActionDispatcher) runWebhook(action *entities.AlertAction, rule *entities.AlertRule, event *AlertEvent, isTest bool) (string, error) {
+	target := renderTemplateEscaped(action.URL, rule, event, url.QueryEscape)
+	if err := validateWebhookURL(target); err != nil {
+		return "", err
+	}
+	method := strings.ToUpper(action.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	var body io.Reader
+	var contentType string
+	if method != http.MethodGet {
+		rendered, err := renderBody(action.TemplateBody, rule, event, isTest)
+		if err != nil {
+			return "", err
+		}
+		body = strings.NewReader(rendered)
+		contentType = contentTypeFor(rendered)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookActionTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		// The URL may carry a secret webhook ID; keep it out of the history.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookResponseBytes))
+
+	detail := fmt.Sprintf("HTTP %d", resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return detail, fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return detail, nil
+}
+
+// runScript runs a configured script with the rendered body on stdin and the
+// event described in BIRDNET_ALERT_* environment variables.
+func (d *ActionDispatcher) runScript(action *entities.AlertAction, rule *entities.AlertRule, event *AlertEvent, isTest bool) (string, error) {
+	var script *conf.AlertScript
+	scripts := d.scripts()
+	for i := range scripts {
+		if scripts[i].Name == action.Script {
+			script = &scripts[i]
+			break
+		}
+	}
+	if script == nil {
+		return "", fmt.Errorf("script %q is not configured", action.Script)
+	}
+	if err := checkScriptCommand(script.Command); err != nil {
+		return "", err
+	}
+
+	body, err := renderBody(action.TemplateBody, rule, event, isTest)
+	if err != nil {
+		return "", err
+	}
+
+	timeout := defaultScriptTimeout
+	if script.Timeout > 0 {
+		timeout = time.Duration(script.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, script.Command) //nolint:gosec // G204: the command comes from the configuration file, never from the rule
+	cmd.Stdin = strings.NewReader(body)
+	cmd.Env = scriptEnvironment(rule, event, isTest)
+	output, err := cmd.CombinedOutput()
+
+	detail := ""
+	if cmd.ProcessState != nil {
+		detail = fmt.Sprintf("exit code %d", cmd.ProcessState.ExitCode())
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return detail, fmt.Errorf("script %s timed out after %s", script.Name, timeout)
+		}
+		return detail, fmt.Errorf("script %s failed: %w: %s", script.Name, err, strings.TrimSpace(string(output)))
+	}
+	return detail, nil
+}
+
+// checkScriptCommand ensures a configured script is an absolute path to an
+// executable file.
+func checkScriptCommand(command string) error {
+	if !filepath.IsAbs(command) {
+		return fmt.Errorf("script command must be an absolute path")
+	}
+	info, err := os.Stat(command)
+	if err != nil {
+		return fmt.Errorf("script command not found: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("script command is a directory")
+	}
+	if runtime.GOOS != "windows" && info.Mode()&0o111 == 0 {
+		return fmt.Errorf("script command is not executable")
+	}
+	return nil
+}
+
+// scriptEnvironment returns a minimal environment plus the rule and event.
+// Scalar event properties are passed as BIRDNET_ALERT_PROP_<NAME>.
+func scriptEnvironment(rule *entities.AlertRule, event *AlertEvent, isTest bool) []string {
+	env := []string{
+		"PATH=" + os.Getenv("PATH"),
+		"TEMP=" + os.Getenv("TEMP"),
+		"TMP=" + os.Getenv("TMP"),
+	}
+	if runtime.GOOS == "windows" {
+		env = append(env, "SystemRoot="+os.Getenv("SystemRoot"))
+	}
+	env = append(env,
+		scriptEnvPrefix+"RULE_ID="+strconv.FormatUint(uint64(rule.ID), 10),
+		scriptEnvPrefix+"RULE_NAME="+sanitizeEnvValue(rule.Name),
+		scriptEnvPrefix+"OBJECT_TYPE="+event.ObjectType,
+		scriptEnvPrefix+"EVENT_NAME="+event.EventName,
+		scriptEnvPrefix+"METRIC_NAME="+event.MetricName,
+		scriptEnvPrefix+"TEST="+strconv.FormatBool(isTest),
+	)
+	for key, value := range event.Properties {
+		if !isEnvName(key) {
+			continue
+		}
+		switch value.(type) {
+		case string, bool, int, int64, uint, uint64, float32, float64:
+			env = append(env, scriptEnvPrefix+"PROP_"+strings.ToUpper(key)+"="+sanitizeEnvValue(fmt.Sprintf("%v", value)))
+		}
+	}
+	return env
+}
+
+// isEnvName reports whether name only contains characters that are safe in
+// an environment variable name.
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitizeEnvValue removes control characters from an environment value.
+func sanitizeEnvValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 32 {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// runControl runs an internal control. A restart_audio_source action without
+// an argument restarts the source of the event. Test fires only check that
+// the control could run; they never restart anything.
+func (d *ActionDispatcher) runControl(action *entities.AlertAction, rule *entities.AlertRule, event *AlertEvent, isTest bool) (status, detail string, err error) {
+	if !slices.Contains(controlActions, action.Control) {
+		return ActionStatusFailed, "", fmt.Errorf("unknown control action %q", action.Control)
+	}
+	if d.control == nil {
+		return ActionStatusFailed, action.Control, errControlUnavailable
+	}
+
+	arg := action.ControlArg
+	if arg == "" && action.Control == ControlRestartAudioSource {
+		arg = "{{" + PropertySourceID + "}}"
+	}
+	arg = renderTemplate(arg, rule, event)
+	detail = strings.TrimSpace(action.Control + " " + arg)
+	if action.Control == ControlRestartAudioSource && (arg == "" || strings.Contains(arg, "{{")) {
+		if isTest {
+			return ActionStatusSkipped, action.Control + ": test fire, no source to restart", nil
+		}
+		return ActionStatusFailed, action.Control, errNoSourceID
+	}
+	if isTest {
+		return ActionStatusSkipped, detail + ": test fire, not run", nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), controlActionTimeout)
+	defer cancel()
+	if err := d.control(ctx, action.Control, arg); err != nil {
+		return ActionStatusFailed, detail, err
+	}
+	return ActionStatusSuccess, detail, nil
+}
+
+// recordResult stores the outcome of an action for the firing's history
+// entry. Firings whose history could not be saved have no entry to link to.
+func (d *ActionDispatcher) recordResult(action *entities.AlertAction, event *AlertEvent, isTest bool, status, detail string, runErr error, elapsed time.Duration) {
+	if d.results == nil || event.HistoryID == 0 {
+		return
+	}
+	result := &entities.AlertActionResult{
+		HistoryID:  event.HistoryID,
+		ActionID:   action.ID,
+		Target:     action.Target,
+		Status:     status,
+		Detail:     truncateRunes(detail, maxResultDetailLength),
+		Test:       isTest,
+		DurationMs: elapsed.Milliseconds(),
+	}
+	if runErr != nil {
+		result.Error = truncateRunes(runErr.Error(), maxResultErrorLength)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), saveResultTimeout)
+	defer cancel()
+	if err := d.results.SaveActionResult(ctx, result); err != nil {
+		d.log.Error("failed to save alert action result",
+			logger.Uint64("history_id", uint64(event.HistoryID)),
+			logger.String("target", action.Target),
+			logger.Error(err))
+		d.telemetry.ReportDBWriteFailed("save_action_result", err.Error())
+	}
+}
+
+// truncateRunes shortens s to at most limit runes.
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
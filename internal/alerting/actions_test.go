@@ -0,0 +1,390 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+type fakeMQTTPublisher struct {
+	mu       sync.Mutex
+	topics   []string
+	payloads []string
+}
+
+func (f *fakeMQTTPublisher) Publish(_ context.Context, topic, payload string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.topics = append(f.topics, topic)
+	f.payloads = append(f.payloads, payload)
+	return nil
+}
+
+type controlCall struct {
+	action string
+	arg    string
+}
+
+type fakeControl struct {
+	mu    sync.Mutex
+	calls []controlCall
+}
+
+func (f *fakeControl) run(_ context.Context, action, arg string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, controlCall{action, arg})
+	return nil
+}
+
+func streamDisconnectEvent() *AlertEvent {
+	return &AlertEvent{
+		ObjectType: ObjectTypeStream,
+		EventName:  EventStreamDisconnected,
+		Properties: map[string]any{
+			PropertySourceID:   "rtsp_1",
+			PropertyStreamName: `Garden "north"`,
+		},
+		Timestamp: time.Now(),
+		HistoryID: 7,
+	}
+}
+
+func TestDispatcher_WebhookAction(t *testing.T) {
+	var gotBody, gotContentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+		gotContentType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	repo := newMockRepo()
+	dispatcher := NewActionDispatcher(&mockNotifCreator{}, dispatchTestLogger(), nil, WithActionResultStore(repo))
+
+	rule := &entities.AlertRule{
+		ID:   3,
+		Name: "Stream Down",
+		Actions: []entities.AlertAction{
+			{ID: 11, Target: TargetWebhook, URL: srv.URL, TemplateBody: `{"stream": "{{stream_name}}", "rule": "{{rule_name}}"}`},
+		},
+	}
+
+	dispatcher.Dispatch(rule, streamDisconnectEvent())
+	dispatcher.Wait()
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal([]byte(gotBody), &body), "template values must be JSON-escaped")
+	assert.Equal(t, `Garden "north"`, body["stream"])
+	assert.Equal(t, "Stream Down", body["rule"])
+	assert.Equal(t, "application/json", gotContentType)
+
+	results := repo.savedResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, uint(7), results[0].HistoryID)
+	assert.Equal(t, uint(11), results[0].ActionID)
+	assert.Equal(t, TargetWebhook, results[0].Target)
+	assert.Equal(t, ActionStatusSuccess, results[0].Status)
+	assert.Equal(t, "HTTP 204", results[0].Detail)
+	assert.False(t, results[0].Test)
+}
+
+func TestDispatcher_WebhookErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	repo := newMockRepo()
+	dispatcher := NewActionDispatcher(&mockNotifCreator{}, dispatchTestLogger(), nil, WithActionResultStore(repo))
+
+	rule := &entities.AlertRule{
+		ID:      3,
+		Name:    "Stream Down",
+		Actions: []entities.AlertAction{{Target: TargetWebhook, URL: srv.URL + "/hook/secret"}},
+	}
+
+	dispatcher.Dispatch(rule, streamDisconnectEvent())
+	dispatcher.Wait()
+
+	results := repo.savedResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, ActionStatusFailed, results[0].Status)
+	assert.Equal(t, "HTTP 500", results[0].Detail)
+	assert.Contains(t, results[0].Error, "HTTP 500")
+	assert.NotContains(t, results[0].Error, "secret", "webhook URL must not be stored in the history")
+}
+
+func TestDispatcher_MQTTAction(t *testing.T) {
+	publisher := &fakeMQTTPublisher{}
+	repo := newMockRepo()
+	dispatcher := NewActionDispatcher(&mockNotifCreator{}, dispatchTestLogger(), nil,
+		WithActionResultStore(repo),
+		WithMQTTPublisher(func() MQTTPublisher { return publisher }))
+
+	rule := &entities.AlertRule{
+		ID:      5,
+		Name:    "Stream Down",
+		Actions: []entities.AlertAction{{Target: TargetMQTT, Topic: "birdnet/alerts/{{source_id}}"}},
+	}
+
+	dispatcher.Dispatch(rule, streamDisconnectEvent())
+	dispatcher.Wait()
+
+	require.Len(t, publisher.topics, 1)
+	assert.Equal(t, "birdnet/alerts/rtsp_1", publisher.topics[0])
+
+	var payload actionPayload
+	require.NoError(t, json.Unmarshal([]byte(publisher.payloads[0]), &payload), "default payload should be the event as JSON")
+	assert.Equal(t, uint(5), payload.RuleID)
+	assert.Equal(t, EventStreamDisconnected, payload.EventName)
+	assert.Equal(t, "rtsp_1", payload.Properties[PropertySourceID])
+
+	results := repo.savedResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, ActionStatusSuccess, results[0].Status)
+	assert.Equal(t, "birdnet/alerts/rtsp_1", results[0].Detail)
+}
+
+func TestDispatcher_MQTTAction_NotConnected(t *testing.T) {
+	repo := newMockRepo()
+	dispatcher := NewActionDispatcher(&mockNotifCreator{}, dispatchTestLogger(), nil,
+		WithActionResultStore(repo),
+		WithMQTTPublisher(func() MQTTPublisher { return nil }))
+
+	rule := &entities.AlertRule{
+		ID:      5,
+		Actions: []entities.AlertAction{{Target: TargetMQTT, Topic: "birdnet/alerts"}},
+	}
+
+	dispatcher.Dispatch(rule, streamDisconnectEvent())
+	dispatcher.Wait()
+
+	results := repo.savedResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, ActionStatusFailed, results[0].Status)
+	assert.Equal(t, errMQTTUnavailable.Error(), results[0].Error)
+}
+
+func TestDispatcher_ControlRestartsEventSource(t *testing.T) {
+	control := &fakeControl{}
+	repo := newMockRepo()
+	dispatcher := NewActionDispatcher(&mockNotifCreator{}, dispatchTestLogger(), nil,
+		WithActionResultStore(repo),
+		WithControlFunc(control.run))
+
+	rule := &entities.AlertRule{
+		ID:      9,
+		Name:    "Restart on disconnect",
+		Actions: []entities.AlertAction{{Target: TargetControl, Control: ControlRestartAudioSource}},
+	}
+
+	dispatcher.Dispatch(rule, streamDisconnectEvent())
+	dispatcher.Wait()
+
+	require.Len(t, control.calls, 1)
+	assert.Equal(t, controlCall{ControlRestartAudioSource, "rtsp_1"}, control.calls[0],
+		"an empty argument should restart the source of the event")
+
+	results := repo.savedResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, ActionStatusSuccess, results[0].Status)
+	assert.Equal(t, "restart_audio_source rtsp_1", results[0].Detail)
+}
+
+func TestDispatcher_ControlTestFireIsSkipped(t *testing.T) {
+	control := &fakeControl{}
+	repo := newMockRepo()
+	dispatcher := NewActionDispatcher(&mockNotifCreator{}, dispatchTestLogger(), nil,
+		WithActionResultStore(repo),
+		WithControlFunc(control.run))
+
+	rule := &entities.AlertRule{
+		ID:      9,
+		Actions: []entities.AlertAction{{Target: TargetControl, Control: ControlReloadModel}},
+	}
+
+	dispatcher.DispatchTest(rule, streamDisconnectEvent())
+	dispatcher.Wait()
+
+	assert.Empty(t, control.calls, "test fires must not run controls")
+	results := repo.savedResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, ActionStatusSkipped, results[0].Status)
+	assert.True(t, results[0].Test)
+}
+
+func TestDispatcher_ControlWithoutSourceFails(t *testing.T) {
+	control := &fakeControl{}
+	repo := newMockRepo()
+	dispatcher := NewActionDispatcher(&mockNotifCreator{}, dispatchTestLogger(), nil,
+		WithActionResultStore(repo),
+		WithControlFunc(control.run))
+
+	rule := &entities.AlertRule{
+		ID:      9,
+		Actions: []entities.AlertAction{{Target: TargetControl, Control: ControlRestartAudioSource}},
+	}
+	event := &AlertEvent{ObjectType: ObjectTypeSystem, MetricName: MetricCPUUsage, Timestamp: time.Now(), HistoryID: 1}
+
+	dispatcher.Dispatch(rule, event)
+	dispatcher.Wait()
+
+	assert.Empty(t, control.calls)
+	results := repo.savedResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, ActionStatusFailed, results[0].Status)
+	assert.Equal(t, errNoSourceID.Error(), results[0].Error)
+}
+
+func TestDispatcher_ScriptAction(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not available on Windows")
+	}
+
+	dir := t.TempDir()
+	outFile := filepath.Join(dir, "out.txt")
+	scriptPath := filepath.Join(dir, "alert.sh")
+	script := "#!/bin/sh\n{ cat; echo; echo \"$BIRDNET_ALERT_RULE_NAME|$BIRDNET_ALERT_PROP_SOURCE_ID\"; } > " + outFile + "\n"
+	require.NoError(t, os.WriteFile(scriptPath, []byte(script), 0o700)) //nolint:gosec // G306: test script must be executable
+
+	repo := newMockRepo()
+	dispatcher := NewActionDispatcher(&mockNotifCreator{}, dispatchTestLogger(), nil,
+		WithActionResultStore(repo),
+		WithScripts(func() []conf.AlertScript {
+			return []conf.AlertScript{{Name: "notify", Command: scriptPath}}
+		}))
+
+	rule := &entities.AlertRule{
+		ID:      4,
+		Name:    "Stream Down",
+		Actions: []entities.AlertAction{{Target: TargetScript, Script: "notify", TemplateBody: "lost {{stream_name}}"}},
+	}
+
+	dispatcher.Dispatch(rule, streamDisconnectEvent())
+	dispatcher.Wait()
+
+	results := repo.savedResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, ActionStatusSuccess, results[0].Status, results[0].Error)
+	assert.Equal(t, "exit code 0", results[0].Detail)
+
+	data, err := os.ReadFile(outFile) //nolint:gosec // G304: path is inside t.TempDir()
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `lost Garden "north"`, lines[0])
+	assert.Equal(t, "Stream Down|rtsp_1", lines[1])
+}
+
+func TestDispatcher_ScriptNotConfigured(t *testing.T) {
+	repo := newMockRepo()
+	dispatcher := NewActionDispatcher(&mockNotifCreator{}, dispatchTestLogger(), nil,
+		WithActionResultStore(repo),
+		WithScripts(func() []conf.AlertScript { return nil }))
+
+	rule := &entities.AlertRule{
+		ID:      4,
+		Actions: []entities.AlertAction{{Target: TargetScript, Script: "missing"}},
+	}
+
+	dispatcher.Dispatch(rule, streamDisconnectEvent())
+	dispatcher.Wait()
+
+	results := repo.savedResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, ActionStatusFailed, results[0].Status)
+	assert.Contains(t, results[0].Error, `script "missing" is not configured`)
+}
+
+func TestDispatcher_NoResultWithoutHistory(t *testing.T) {
+	control := &fakeControl{}
+	repo := newMockRepo()
+	dispatcher := NewActionDispatcher(&mockNotifCreator{}, dispatchTestLogger(), nil,
+		WithActionResultStore(repo),
+		WithControlFunc(control.run))
+
+	rule := &entities.AlertRule{
+		ID:      9,
+		Actions: []entities.AlertAction{{Target: TargetControl, Control: ControlReloadModel}},
+	}
+	event := streamDisconnectEvent()
+	event.HistoryID = 0
+
+	dispatcher.Dispatch(rule, event)
+	dispatcher.Wait()
+
+	assert.Len(t, control.calls, 1, "the action should still run")
+	assert.Empty(t, repo.savedResults(), "results need a history entry to link to")
+}
+
+func TestRenderBody(t *testing.T) {
+	rule := &entities.AlertRule{ID: 1, Name: "Rule"}
+	event := streamDisconnectEvent()
+
+	body, err := renderBody(`{"name": "{{stream_name}}"}`, rule, event, false)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "Garden \"north\""}`, body)
+
+	body, err = renderBody("Stream {{stream_name}} lost", rule, event, false)
+	require.NoError(t, err)
+	assert.Equal(t, `Stream Garden "north" lost`, body, "plain text templates are not escaped")
+
+	body, err = renderBody("", rule, event, true)
+	require.NoError(t, err)
+	var payload actionPayload
+	require.NoError(t, json.Unmarshal([]byte(body), &payload))
+	assert.True(t, payload.Test)
+	assert.Equal(t, "Rule", payload.RuleName)
+}
+
+func TestValidateAction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		action  entities.AlertAction
+		wantErr string
+	}{
+		{name: "bell needs nothing", action: entities.AlertAction{Target: TargetBell}},
+		{name: "mqtt topic", action: entities.AlertAction{Target: TargetMQTT, Topic: "birdnet/alerts"}},
+		{name: "mqtt without topic", action: entities.AlertAction{Target: TargetMQTT}, wantErr: "topic"},
+		{name: "mqtt wildcard", action: entities.AlertAction{Target: TargetMQTT, Topic: "birdnet/#"}, wantErr: "wildcard"},
+		{name: "webhook post", action: entities.AlertAction{Target: TargetWebhook, URL: "https://example.com/hook"}},
+		{name: "webhook get", action: entities.AlertAction{Target: TargetWebhook, URL: "http://ha.local/api", Method: "get"}},
+		{name: "webhook bad scheme", action: entities.AlertAction{Target: TargetWebhook, URL: "ftp://example.com"}, wantErr: "http"},
+		{name: "webhook bad method", action: entities.AlertAction{Target: TargetWebhook, URL: "https://example.com", Method: "DELETE"}, wantErr: "method"},
+		{name: "script", action: entities.AlertAction{Target: TargetScript, Script: "notify"}},
+		{name: "script without name", action: entities.AlertAction{Target: TargetScript}, wantErr: "script"},
+		{name: "control", action: entities.AlertAction{Target: TargetControl, Control: ControlRestartAudioSource}},
+		{name: "unknown control", action: entities.AlertAction{Target: TargetControl, Control: "shutdown"}, wantErr: "control"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateAction(&tt.action)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package alerting
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
+)
+
+const (
+	// maxConditionGroupDepth limits how deeply condition groups may nest.
+	maxConditionGroupDepth = 5
+	// maxSunOffsetMinutes bounds the offset of a sun window edge.
+	maxSunOffsetMinutes = 12 * 60
+	// maxCountWindow is the longest window an event_count condition may use.
+	maxCountWindow = 24 * time.Hour
+	// maxCountedEvents caps the events remembered per counting key, and so
+	// the highest count a condition can compare against.
+	maxCountedEvents = 1000
+)
+
+// sunEvents are the sun events accepted in sun_window values.
+var sunEvents = []string{SunEventDawn, SunEventSunrise, SunEventSunset, SunEventDusk}
+
+// windowOperators are the operators of the time_of_day and sun_window
+// context properties.
+var windowOperators = []string{OperatorBetween, OperatorNotBetween}
+
+// ConditionFunc evaluates a single condition of a rule.
+type ConditionFunc func(cond *entities.AlertCondition) bool
+
+// EvaluateConditionGroups evaluates a rule's conditions as a tree of AND/OR
+// groups, calling eval for each condition. The root group uses
+// rule.ConditionMatch; conditions and groups that refer to an unknown group
+// belong to the root. A group without members matches.
+func EvaluateConditionGroups(rule *entities.AlertRule, eval ConditionFunc) bool {
+	known := make(map[int]bool, len(rule.ConditionGroups))
+	for _, g := range rule.ConditionGroups {
+		known[g.Key] = true
+	}
+	parentOf := func(key int) int {
+		if known[key] {
+			return key
+		}
+		return 0
+	}
+	return evaluateGroup(rule, 0, rule.ConditionMatch, parentOf, eval, 0)
+}
+
+func evaluateGroup(rule *entities.AlertRule, key int, match string, parentOf func(int) int, eval ConditionFunc, depth int) bool {
+	if depth > maxConditionGroupDepth {
+		return false
+	}
+	anyMode := match == MatchAny
+	members := 0
+	for i := range rule.Conditions {
+		cond := &rule.Conditions[i]
+		if parentOf(cond.GroupKey) != key {
+			continue
+		}
+		members++
+		if eval(cond) == anyMode {
+			return anyMode
+		}
+	}
+	for _, g := range rule.ConditionGroups {
+		if g.Key == key || g.Key == 0 || parentOf(g.Parent) != key {
+			continue
+		}
+		members++
+		if evaluateGroup(rule, g.Key, g.Match, parentOf, eval, depth+1) == anyMode {
+			return anyMode
+		}
+	}
+	return !anyMode || members == 0
+}
+
+// timeOfDayWindow is a daily window in minutes since midnight. A window
+// whose end is before its start wraps past midnight.
+type timeOfDayWindow struct {
+	start, end int
+}
+
+func parseTimeOfDayWindow(value string) (timeOfDayWindow, error) {
+	startStr, endStr, ok := strings.Cut(value, ",")
+	if !ok {
+		return timeOfDayWindow{}, fmt.Errorf("time window must be \"HH:MM,HH:MM\", got %q", value)
+	}
+	start, err := parseClock(startStr)
+	if err != nil {
+		return timeOfDayWindow{}, err
+	}
+	end, err := parseClock(endStr)
+	if err != nil {
+		return timeOfDayWindow{}, err
+	}
+	if start == end {
+		return timeOfDayWindow{}, fmt.Errorf("time window %q is empty", value)
+	}
+	return timeOfDayWindow{start: start, end: end}, nil
+}
+
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", strings.TrimSpace(value))
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (w timeOfDayWindow) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.start < w.end {
+		return minute >= w.start && minute < w.end
+	}
+	return minute >= w.start || minute < w.end
+}
+
+// sunEdge is one edge of a sun window: a sun event plus an offset.
+type sunEdge struct {
+	event  string
+	offset time.Duration
+}
+
+// sunWindow is a window between two sun events on the day of the event. A
+// window whose end is before its start, such as sunset to sunrise, wraps
+// past midnight.
+type sunWindow struct {
+	start, end sunEdge
+}
+
+func parseSunWindow(value string) (sunWindow, error) {
+	startStr, endStr, ok := strings.Cut(value, ",")
+	if !ok {
+		return sunWindow{}, fmt.Errorf("sun window must be \"<event>[+-minutes],<event>[+-minutes]\", got %q", value)
+	}
+	start, err := parseSunEdge(startStr)
+	if err != nil {
+		return sunWindow{}, err
+	}
+	end, err := parseSunEdge(endStr)
+	if err != nil {
+		return sunWindow{}, err
+	}
+	if start == end {
+		return sunWindow{}, fmt.Errorf("sun window %q is empty", value)
+	}
+	return sunWindow{start: start, end: end}, nil
+}
+
+func parseSunEdge(value string) (sunEdge, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	name, offsetStr := value, ""
+	if i := strings.IndexAny(value, "+-"); i >= 0 {
+		name, offsetStr = value[:i], value[i:]
+	}
+	if !slices.Contains(sunEvents, name) {
+		return sunEdge{}, fmt.Errorf("unknown sun event %q, expected one of %s", name, strings.Join(sunEvents, ", "))
+	}
+	var minutes int
+	if offsetStr != "" {
+		n, err := strconv.Atoi(offsetStr)
+		if err != nil || n < -maxSunOffsetMinutes || n > maxSunOffsetMinutes {
+			return sunEdge{}, fmt.Errorf("sun event offset %q must be minutes between -%d and %d", offsetStr, maxSunOffsetMinutes, maxSunOffsetMinutes)
+		}
+		minutes = n
+	}
+	return sunEdge{event: name, offset: time.Duration(minutes) * time.Minute}, nil
+}
+
+func (e sunEdge) at(times *suncalc.SunEventTimes) time.Time {
+	var base time.Time
+	switch e.event {
+	case SunEventDawn:
+		base = times.CivilDawn
+	case SunEventSunrise:
+		base = times.Sunrise
+	case SunEventSunset:
+		base = times.Sunset
+	case SunEventDusk:
+		base = times.CivilDusk
+	}
+	if base.IsZero() {
+		return base
+	}
+	return base.Add(e.offset)
+}
+
+// contains reports whether t falls in the window. ok is false when a sun
+// event does not occur on that day, as in polar summer or winter.
+func (w sunWindow) contains(t time.Time, times *suncalc.SunEventTimes) (in, ok bool) {
+	start, end := w.start.at(times), w.end.at(times)
+	if start.IsZero() || end.IsZero() {
+		return false, false
+	}
+	if start.Before(end) {
+		return !t.Before(start) && t.Before(end), true
+	}
+	return !t.Before(start) || t.Before(end), true
+}
+
+// evaluateWindow applies a between or not_between operator to a window test.
+func evaluateWindow(operator string, in bool) bool {
+	switch operator {
+	case OperatorBetween:
+		return in
+	case OperatorNotBetween:
+		return !in
+	default:
+		return false
+	}
+}
+
+// hasCountConditions reports whether a rule uses event_count conditions.
+func hasCountConditions(rule *entities.AlertRule) bool {
+	for i := range rule.Conditions {
+		if rule.Conditions[i].Property == PropertyEventCount {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateConditions checks a rule's condition groups and the conditions
+// that have a fixed format: time and sun windows, counts and sustained
+// metric conditions.
+func ValidateConditions(rule *entities.AlertRule) error {
+	if err := validateMatch(rule.ConditionMatch, true); err != nil {
+		return err
+	}
+	if err := validateConditionGroups(rule.ConditionGroups); err != nil {
+		return err
+	}
+
+	groups := make(map[int]bool, len(rule.ConditionGroups))
+	for _, g := range rule.ConditionGroups {
+		groups[g.Key] = true
+	}
+	for i := range rule.Conditions {
+		cond := &rule.Conditions[i]
+		if cond.GroupKey != 0 && !groups[cond.GroupKey] {
+			return fmt.Errorf("condition on %q refers to unknown group %d", cond.Property, cond.GroupKey)
+		}
+		if err := validateCondition(rule, cond); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateMatch(match string, allowEmpty bool) error {
+	if (match == "" && allowEmpty) || match == MatchAll || match == MatchAny {
+		return nil
+	}
+	return fmt.Errorf("condition match must be %q or %q, got %q", MatchAll, MatchAny, match)
+}
+
+func validateConditionGroups(groups []entities.AlertConditionGroup) error {
+	parents := make(map[int]int, len(groups))
+	for _, g := range groups {
+		if g.Key <= 0 {
+			return fmt.Errorf("condition group key must be positive, got %d", g.Key)
+		}
+		if _, dup := parents[g.Key]; dup {
+			return fmt.Errorf("duplicate condition group key %d", g.Key)
+		}
+		if err := validateMatch(g.Match, false); err != nil {
+			return err
+		}
+		parents[g.Key] = g.Parent
+	}
+	for _, g := range groups {
+		depth := 1
+		for parent := g.Parent; parent != 0; parent = parents[parent] {
+			if _, ok := parents[parent]; !ok {
+				return fmt.Errorf("condition group %d refers to unknown parent %d", g.Key, parent)
+			}
+			depth++
+			if depth > maxConditionGroupDepth {
+				return fmt.Errorf("condition groups nest deeper than %d levels or form a cycle", maxConditionGroupDepth)
+			}
+		}
+	}
+	return nil
+}
+
+func validateCondition(rule *entities.AlertRule, cond *entities.AlertCondition) error {
+	switch cond.Property {
+	case PropertyTimeOfDay:
+		if !slices.Contains(windowOperators, cond.Operator) {
+			return fmt.Errorf("%s condition requires operator %s or %s", cond.Property, OperatorBetween, OperatorNotBetween)
+		}
+		_, err := parseTimeOfDayWindow(cond.Value)
+		return err
+	case PropertySunWindow:
+		if !slices.Contains(windowOperators, cond.Operator) {
+			return fmt.Errorf("%s condition requires operator %s or %s", cond.Property, OperatorBetween, OperatorNotBetween)
+		}
+		_, err := parseSunWindow(cond.Value)
+		return err
+	case PropertyEventCount:
+		return validateCountCondition(rule, cond)
+	}
+
+	if slices.Contains(windowOperators, cond.Operator) {
+		return fmt.Errorf("operator %s only applies to %s and %s", cond.Operator, PropertyTimeOfDay, PropertySunWindow)
+	}
+	if rule.TriggerType == TriggerTypeMetric && cond.DurationSec > 0 {
+		if !slices.Contains(numericOperators, cond.Operator) {
+			return fmt.Errorf("sustained condition on %q requires a numeric operator", cond.Property)
+		}
+		if time.Duration(cond.DurationSec)*time.Second > maxSampleAge {
+			return fmt.Errorf("sustained condition duration must not exceed %d seconds", int(maxSampleAge.Seconds()))
+		}
+	}
+	return nil
+}
+
+// validateCountCondition checks an event_count condition. Counting happens
+// after the rest of the rule has matched, so count conditions must be ANDed
+// at the root of the rule.
+func validateCountCondition(rule *entities.AlertRule, cond *entities.AlertCondition) error {
+	if cond.GroupKey != 0 || rule.ConditionMatch == MatchAny {
+		return fmt.Errorf("%s conditions must be in the root group of a rule matching all conditions", PropertyEventCount)
+	}
+	if !slices.Contains(numericOperators, cond.Operator) {
+		return fmt.Errorf("%s condition requires a numeric operator", PropertyEventCount)
+	}
+	n, err := strconv.ParseFloat(cond.Value, 64)
+	if err != nil || n < 0 || n > maxCountedEvents {
+		return fmt.Errorf("%s value must be a number between 0 and %d", PropertyEventCount, maxCountedEvents)
+	}
+	if cond.DurationSec <= 0 || time.Duration(cond.DurationSec)*time.Second > maxCountWindow {
+		return fmt.Errorf("%s condition requires a window between 1 and %d seconds", PropertyEventCount, int(maxCountWindow.Seconds()))
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
+)
+
+// evalByValue treats a condition's Value as its result, so group logic can be
+// tested without events.
+func evalByValue(cond *entities.AlertCondition) bool {
+	return cond.Value == "true"
+}
+
+func TestEvaluateConditionGroups(t *testing.T) {
+	t.Parallel()
+
+	cond := func(result bool, group int) entities.AlertCondition {
+		value := "false"
+		if result {
+			value = "true"
+		}
+		return entities.AlertCondition{Property: "p", Value: value, GroupKey: group}
+	}
+
+	tests := []struct {
+		name string
+		rule entities.AlertRule
+		want bool
+	}{
+		{
+			name: "no conditions match",
+			rule: entities.AlertRule{},
+			want: true,
+		},
+		{
+			name: "root all requires every condition",
+			rule: entities.AlertRule{Conditions: []entities.AlertCondition{cond(true, 0), cond(false, 0)}},
+			want: false,
+		},
+		{
+			name: "root any needs one condition",
+			rule: entities.AlertRule{ConditionMatch: MatchAny, Conditions: []entities.AlertCondition{cond(false, 0), cond(true, 0)}},
+			want: true,
+		},
+		{
+			name: "or group inside and root",
+			rule: entities.AlertRule{
+				ConditionGroups: []entities.AlertConditionGroup{{Key: 1, Match: MatchAny}},
+				Conditions:      []entities.AlertCondition{cond(true, 0), cond(false, 1), cond(true, 1)},
+			},
+			want: true,
+		},
+		{
+			name: "failing or group fails and root",
+			rule: entities.AlertRule{
+				ConditionGroups: []entities.AlertConditionGroup{{Key: 1, Match: MatchAny}},
+				Conditions:      []entities.AlertCondition{cond(true, 0), cond(false, 1), cond(false, 1)},
+			},
+			want: false,
+		},
+		{
+			name: "nested and group under or root",
+			rule: entities.AlertRule{
+				ConditionMatch: MatchAny,
+				ConditionGroups: []entities.AlertConditionGroup{
+					{Key: 1, Match: MatchAll},
+					{Key: 2, Parent: 1, Match: MatchAny},
+				},
+				Conditions: []entities.AlertCondition{cond(false, 0), cond(true, 1), cond(false, 2), cond(true, 2)},
+			},
+			want: true,
+		},
+		{
+			name: "empty group matches",
+			rule: entities.AlertRule{
+				ConditionGroups: []entities.AlertConditionGroup{{Key: 1, Match: MatchAny}},
+				Conditions:      []entities.AlertCondition{cond(true, 0)},
+			},
+			want: true,
+		},
+		{
+			name: "unknown group belongs to root",
+			rule: entities.AlertRule{Conditions: []entities.AlertCondition{cond(true, 0), cond(false, 7)}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, EvaluateConditionGroups(&tt.rule, evalByValue))
+		})
+	}
+}
+
+func TestTimeOfDayWindow(t *testing.T) {
+	t.Parallel()
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 5, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	day, err := parseTimeOfDayWindow("08:00,17:30")
+	require.NoError(t, err)
+	assert.True(t, day.contains(at(8, 0)))
+	assert.True(t, day.contains(at(17, 29)))
+	assert.False(t, day.contains(at(17, 30)), "end is exclusive")
+	assert.False(t, day.contains(at(7, 59)))
+
+	night, err := parseTimeOfDayWindow("22:00, 06:00")
+	require.NoError(t, err)
+	assert.True(t, night.contains(at(23, 15)))
+	assert.True(t, night.contains(at(2, 0)))
+	assert.False(t, night.contains(at(12, 0)))
+
+	for _, bad := range []string{"", "08:00", "8am,5pm", "25:00,06:00", "10:00,10:00"} {
+		_, err := parseTimeOfDayWindow(bad)
+		assert.Error(t, err, "value %q should be rejected", bad)
+	}
+}
+
+func TestSunWindow(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	times := suncalc.SunEventTimes{
+		CivilDawn: day.Add(5 * time.Hour),
+		Sunrise:   day.Add(5*time.Hour + 40*time.Minute),
+		Sunset:    day.Add(20 * time.Hour),
+		CivilDusk: day.Add(20*time.Hour + 40*time.Minute),
+	}
+
+	night, err := parseSunWindow("sunset-30,sunrise+30")
+	require.NoError(t, err)
+
+	in, ok := night.contains(day.Add(19*time.Hour+45*time.Minute), &times)
+	require.True(t, ok)
+	assert.True(t, in, "30 minutes before sunset is inside")
+
+	in, _ = night.contains(day.Add(3*time.Hour), &times)
+	assert.True(t, in, "night window wraps past midnight")
+
+	in, _ = night.contains(day.Add(12*time.Hour), &times)
+	assert.False(t, in)
+
+	dawnChorus, err := parseSunWindow("Dawn,Sunrise+60")
+	require.NoError(t, err)
+	in, _ = dawnChorus.contains(day.Add(6*time.Hour), &times)
+	assert.True(t, in)
+
+	_, ok = dawnChorus.contains(day, &suncalc.SunEventTimes{Sunrise: times.Sunrise})
+	assert.False(t, ok, "missing sun events cannot be evaluated")
+
+	for _, bad := range []string{"sunset", "noon,sunrise", "sunset+x,sunrise", "sunset+900,sunrise", "sunrise,sunrise"} {
+		_, err := parseSunWindow(bad)
+		assert.Error(t, err, "value %q should be rejected", bad)
+	}
+}
+
+func TestValidateConditions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rule    entities.AlertRule
+		wantErr string
+	}{
+		{
+			name: "plain conditions",
+			rule: entities.AlertRule{TriggerType: TriggerTypeEvent, Conditions: []entities.AlertCondition{
+				{Property: PropertySpeciesName, Operator: OperatorIs, Value: "Robin"},
+			}},
+		},
+		{
+			name: "groups and windows",
+			rule: entities.AlertRule{
+				TriggerType:     TriggerTypeEvent,
+				ConditionMatch:  MatchAll,
+				ConditionGroups: []entities.AlertConditionGroup{{Key: 1, Match: MatchAny}},
+				Conditions: []entities.AlertCondition{
+					{Property: PropertyTimeOfDay, Operator: OperatorBetween, Value: "22:00,06:00", GroupKey: 1},
+					{Property: PropertySunWindow, Operator: OperatorNotBetween, Value: "sunrise,sunset", GroupKey: 1},
+				},
+			},
+		},
+		{
+			name:    "invalid root match",
+			rule:    entities.AlertRule{ConditionMatch: "some"},
+			wantErr: "condition match",
+		},
+		{
+			name:    "duplicate group key",
+			rule:    entities.AlertRule{ConditionGroups: []entities.AlertConditionGroup{{Key: 1, Match: MatchAll}, {Key: 1, Match: MatchAny}}},
+			wantErr: "duplicate",
+		},
+		{
+			name:    "unknown parent",
+			rule:    entities.AlertRule{ConditionGroups: []entities.AlertConditionGroup{{Key: 1, Parent: 4, Match: MatchAll}}},
+			wantErr: "unknown parent",
+		},
+		{
+			name: "group cycle",
+			rule: entities.AlertRule{ConditionGroups: []entities.AlertConditionGroup{
+				{Key: 1, Parent: 2, Match: MatchAll},
+				{Key: 2, Parent: 1, Match: MatchAny},
+			}},
+			wantErr: "cycle",
+		},
+		{
+			name: "condition in unknown group",
+			rule: entities.AlertRule{Conditions: []entities.AlertCondition{
+				{Property: PropertySpeciesName, Operator: OperatorIs, Value: "Robin", GroupKey: 3},
+			}},
+			wantErr: "unknown group",
+		},
+		{
+			name: "bad time window",
+			rule: entities.AlertRule{Conditions: []entities.AlertCondition{
+				{Property: PropertyTimeOfDay, Operator: OperatorBetween, Value: "late"},
+			}},
+			wantErr: "HH:MM",
+		},
+		{
+			name: "window operator on event property",
+			rule: entities.AlertRule{Conditions: []entities.AlertCondition{
+				{Property: PropertySpeciesName, Operator: OperatorBetween, Value: "a,b"},
+			}},
+			wantErr: "only applies",
+		},
+		{
+			name: "sustained metric condition too long",
+			rule: entities.AlertRule{TriggerType: TriggerTypeMetric, Conditions: []entities.AlertCondition{
+				{Property: PropertyValue, Operator: OperatorGreaterThan, Value: "75", DurationSec: 7200},
+			}},
+			wantErr: "must not exceed",
+		},
+		{
+			name: "count condition",
+			rule: entities.AlertRule{TriggerType: TriggerTypeEvent, Conditions: []entities.AlertCondition{
+				{Property: PropertyEventCount, Operator: OperatorGreaterThan, Value: "5", DurationSec: 3600},
+			}},
+		},
+		{
+			name: "count condition without window",
+			rule: entities.AlertRule{Conditions: []entities.AlertCondition{
+				{Property: PropertyEventCount, Operator: OperatorGreaterThan, Value: "5"},
+			}},
+			wantErr: "window",
+		},
+		{
+			name: "count condition in or rule",
+			rule: entities.AlertRule{ConditionMatch: MatchAny, Conditions: []entities.AlertCondition{
+				{Property: PropertyEventCount, Operator: OperatorGreaterThan, Value: "5", DurationSec: 3600},
+			}},
+			wantErr: "root group",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateConditions(&tt.rule)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -43,9 +43,10 @@ const (
 
 // Metric names identify threshold-based metrics.
 const (
-	MetricCPUUsage    = "system.cpu_usage"
-	MetricMemoryUsage = "system.memory_usage"
-	MetricDiskUsage   = "system.disk_usage"
+	MetricCPUUsage       = "system.cpu_usage"
+	MetricCPUTemperature = "system.cpu_temperature"
+	MetricMemoryUsage    = "system.memory_usage"
+	MetricDiskUsage      = "system.disk_usage"
 )
 
 // Condition operators define how property values are compared.
@@ -60,6 +61,15 @@ const (
 	OperatorLessThan       = "less_than"
 	OperatorGreaterOrEqual = "greater_or_equal"
 	OperatorLessOrEqual    = "less_or_equal"
+	OperatorBetween        = "between"
+	OperatorNotBetween     = "not_between"
+)
+
+// Condition group match modes for AlertRule.ConditionMatch and
+// AlertConditionGroup.Match.
+const (
+	MatchAll = "all"
+	MatchAny = "any"
 )
 
 // Condition properties identify event fields available for condition evaluation.
@@ -71,6 +81,7 @@ const (
 	PropertyLocation       = "location"
 	PropertyStreamName     = "stream_name"
 	PropertyStreamURL      = "stream_url"
+	PropertySourceID       = "source_id"
 	PropertyDeviceName     = "device_name"
 	PropertyError          = "error"
 	PropertyPath           = "path"
@@ -88,10 +99,53 @@ const (
 	PropertyIsNewSpecies        = "is_new_species"
 )
 
-// Action targets identify where notifications are sent.
+// Context properties are evaluated by the engine instead of being read from
+// the event, so they are available to every trigger.
+const (
+	// PropertyTimeOfDay matches the local time of the event against a
+	// "HH:MM,HH:MM" window.
+	PropertyTimeOfDay = "time_of_day"
+	// PropertySunWindow matches the event time against a window between two
+	// sun events with minute offsets, e.g. "sunset-30,sunrise+30".
+	PropertySunWindow = "sun_window"
+	// PropertyEventCount is the number of the rule's matching events within
+	// the condition's DurationSec, including the current one.
+	PropertyEventCount = "event_count"
+)
+
+// Sun events accepted in PropertySunWindow values.
+const (
+	SunEventDawn    = "dawn"
+	SunEventSunrise = "sunrise"
+	SunEventSunset  = "sunset"
+	SunEventDusk    = "dusk"
+)
+
+// Action targets identify where notifications are sent or which automation
+// runs when a rule fires.
+const (
+	TargetBell    = "bell"
+	TargetPush    = "push"
+	TargetMQTT    = "mqtt"
+	TargetWebhook = "webhook"
+	TargetScript  = "script"
+	TargetControl = "control"
+)
+
+// Control actions available to TargetControl actions. The names match the
+// actions of the /api/v2/control endpoints.
+const (
+	ControlRestartAudioSource = "restart_audio_source"
+	ControlRestartAnalysis    = "restart_analysis"
+	ControlReloadModel        = "reload_model"
+	ControlRebuildFilter      = "rebuild_filter"
+)
+
+// Action result statuses recorded in AlertActionResult.Status.
 const (
-	TargetBell = "bell"
-	TargetPush = "push"
+	ActionStatusSuccess = "success"
+	ActionStatusFailed  = "failed"
+	ActionStatusSkipped = "skipped"
 )
 
 // Built-in rule i18n key constants.
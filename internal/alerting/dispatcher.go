@@ -3,8 +3,11 @@ package alerting
 import (
 	"fmt"
 	"math"
+	"net/http"
 	"strings"
+	"sync"
 
+	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/notification"
@@ -29,20 +32,36 @@ type NotificationCreator interface {
 }
 
 // ActionDispatcher routes alert rule actions to the notification bell
-// and/or external targets.
+// and/or external targets. Notification actions are dispatched inline;
+// automation actions (MQTT, webhook, script, control) run in the background
+// and record their outcome through the ActionResultStore.
 type ActionDispatcher struct {
 	notifCreator NotificationCreator
 	log          logger.Logger
 	telemetry    *AlertingTelemetry
+
+	mqtt       func() MQTTPublisher
+	control    ControlFunc
+	results    ActionResultStore
+	scripts    func() []conf.AlertScript
+	httpClient *http.Client
+	wg         sync.WaitGroup
 }
 
-// NewActionDispatcher creates a new ActionDispatcher.
-func NewActionDispatcher(notifCreator NotificationCreator, log logger.Logger, at *AlertingTelemetry) *ActionDispatcher {
-	return &ActionDispatcher{
+// NewActionDispatcher creates a new ActionDispatcher. Automation targets
+// whose dependency is not configured through opts record a failed result.
+func NewActionDispatcher(notifCreator NotificationCreator, log logger.Logger, at *AlertingTelemetry, opts ...DispatcherOption) *ActionDispatcher {
+	d := &ActionDispatcher{
 		notifCreator: notifCreator,
 		log:          log,
 		telemetry:    at,
+		scripts:      configuredScripts,
+		httpClient:   &http.Client{Timeout: webhookActionTimeout},
+	}
+	for _, opt := range opts {
+		opt(d)
 	}
+	return d
 }
 
 // Dispatch implements ActionFunc — called by the engine when a rule fires.
@@ -76,6 +95,8 @@ func (d *ActionDispatcher) dispatchInternal(rule *entities.AlertRule, event *Ale
 			if !hasCustomTemplate {
 				defaultDispatchedForTarget[action.Target] = true
 			}
+		case TargetMQTT, TargetWebhook, TargetScript, TargetControl:
+			d.dispatchAutomation(action, rule, event, isTest)
 		default:
 			d.log.Warn("unknown alert action target",
 				logger.String("target", action.Target),
@@ -167,15 +188,25 @@ func renderMessage(tmpl string, rule *entities.AlertRule, event *AlertEvent) str
 
 // renderTemplate substitutes template variables in a string.
 func renderTemplate(tmpl string, rule *entities.AlertRule, event *AlertEvent) string {
+	return renderTemplateEscaped(tmpl, rule, event, nil)
+}
+
+// renderTemplateEscaped is renderTemplate with escape applied to every
+// substituted value, so values cannot break out of a JSON string or a URL.
+// A nil escape substitutes values unchanged.
+func renderTemplateEscaped(tmpl string, rule *entities.AlertRule, event *AlertEvent, escape func(string) string) string {
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
 	pairs := make([]string, 0, 8+len(event.Properties)*2)
 	pairs = append(pairs,
-		"{{rule_name}}", rule.Name,
-		"{{event_name}}", event.EventName,
-		"{{metric_name}}", event.MetricName,
-		"{{object_type}}", event.ObjectType,
+		"{{rule_name}}", escape(rule.Name),
+		"{{event_name}}", escape(event.EventName),
+		"{{metric_name}}", escape(event.MetricName),
+		"{{object_type}}", escape(event.ObjectType),
 	)
 	for k, v := range event.Properties {
-		pairs = append(pairs, fmt.Sprintf("{{%s}}", k), fmt.Sprintf("%v", v))
+		pairs = append(pairs, fmt.Sprintf("{{%s}}", k), escape(fmt.Sprintf("%v", v)))
 	}
 	return strings.NewReplacer(pairs...).Replace(tmpl)
 }
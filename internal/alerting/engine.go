@@ -7,14 +7,17 @@ import (
 	"maps"
 	"runtime/debug"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
 	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
 )
 
 const (
@@ -30,6 +33,8 @@ const (
 	cleanupBaseDelay = 100 * time.Millisecond
 	// unknownDetectionSpeciesKey is used when detection events lack species metadata.
 	unknownDetectionSpeciesKey = "unknown"
+	// countsSweepInterval is how often stale event counts are swept.
+	countsSweepInterval = 1 * time.Minute
 )
 
 // ActionFunc is called when a rule fires. Receives the rule and triggering event.
@@ -56,6 +61,23 @@ type Engine struct {
 	escalations   map[string]float64
 	escalationsMu sync.RWMutex
 
+	// Event counting for event_count conditions: maps the cooldown key →
+	// times of the rule's matching events, oldest first. countWindow is the
+	// longest event_count window of the loaded rules; keys without events in
+	// it are swept at most once per countsSweepInterval.
+	counts      map[string][]time.Time
+	countWindow time.Duration
+	countsSwept time.Time
+	countsMu    sync.Mutex
+
+	// location returns the station coordinates for sun_window conditions.
+	// sun is the calculator for sunLat/sunLon, rebuilt when the location
+	// changes; without a location those conditions never match.
+	location       func() (lat, lon float64)
+	sun            *suncalc.SunCalc
+	sunLat, sunLon float64
+	sunMu          sync.Mutex
+
 	// Cached rules (refreshed periodically)
 	rules   []entities.AlertRule
 	rulesMu sync.RWMutex
@@ -74,6 +96,8 @@ func NewEngine(repo repository.AlertRuleRepository, actionFunc ActionFunc, log l
 		telemetry:     at,
 		cooldowns:     make(map[string]time.Time),
 		escalations:   make(map[string]float64),
+		counts:        make(map[string][]time.Time),
+		location:      settingsLocation,
 	}
 }
 
@@ -83,8 +107,42 @@ func (e *Engine) SetTestActionFunc(fn ActionFunc) {
 	e.testActionFunc = fn
 }
 
+// SetLocationFunc sets how sun_window conditions find the station location.
+// By default it is read from the published settings on each evaluation, so a
+// location change takes effect without restarting the engine. Call it before
+// the engine receives events.
+func (e *Engine) SetLocationFunc(fn func() (lat, lon float64)) {
+	e.location = fn
+}
+
+// settingsLocation returns the station location from the published settings.
+func settingsLocation() (lat, lon float64) {
+	settings := conf.GetSettings()
+	if settings == nil {
+		return 0, 0
+	}
+	return settings.BirdNET.Latitude, settings.BirdNET.Longitude
+}
+
+// sunCalc returns the calculator for the current station location, or nil
+// when the location is unknown.
+func (e *Engine) sunCalc() *suncalc.SunCalc {
+	lat, lon := e.location()
+	if lat == 0 && lon == 0 {
+		return nil
+	}
+	e.sunMu.Lock()
+	defer e.sunMu.Unlock()
+	if e.sun == nil || e.sunLat != lat || e.sunLon != lon {
+		e.sun = suncalc.NewSunCalc(lat, lon)
+		e.sunLat, e.sunLon = lat, lon
+	}
+	return e.sun
+}
+
 // RefreshRules reloads enabled rules from the database.
 // Call this on startup and whenever rules are modified via API.
+// Event counts are reset, since the rules they belong to may have changed.
 func (e *Engine) RefreshRules(ctx context.Context) error {
 	rules, err := e.repo.GetEnabledRules(ctx)
 	if err != nil {
@@ -93,6 +151,19 @@ func (e *Engine) RefreshRules(ctx context.Context) error {
 	e.rulesMu.Lock()
 	e.rules = rules
 	e.rulesMu.Unlock()
+
+	var window time.Duration
+	for i := range rules {
+		for j := range rules[i].Conditions {
+			if cond := &rules[i].Conditions[j]; cond.Property == PropertyEventCount {
+				window = max(window, time.Duration(cond.DurationSec)*time.Second)
+			}
+		}
+	}
+	e.countsMu.Lock()
+	clear(e.counts)
+	e.countWindow = window
+	e.countsMu.Unlock()
 	return nil
 }
 
@@ -504,34 +575,154 @@ func (e *Engine) ruleMatches(rule *entities.AlertRule, event *AlertEvent) bool {
 		return false
 	}
 
-	// For metric triggers with duration conditions, check sustained threshold
-	if rule.TriggerType == TriggerTypeMetric {
-		return e.evaluateMetricConditions(rule, event)
+	if !EvaluateConditionGroups(rule, func(cond *entities.AlertCondition) bool {
+		return e.evaluateRuleCondition(rule, event, cond)
+	}) {
+		return false
 	}
+	if hasCountConditions(rule) {
+		return e.countMatches(rule, event)
+	}
+	return true
+}
+
+// evaluateRuleCondition evaluates one condition of a rule. Context
+// properties are resolved by the engine, and metric conditions with a
+// duration must hold for that long. Count conditions are checked by
+// countMatches once the rest of the rule has matched.
+func (e *Engine) evaluateRuleCondition(rule *entities.AlertRule, event *AlertEvent, cond *entities.AlertCondition) bool {
+	switch cond.Property {
+	case PropertyTimeOfDay:
+		window, err := parseTimeOfDayWindow(cond.Value)
+		if err != nil {
+			e.log.Debug("Invalid time window in alert condition",
+				logger.Uint64("rule_id", uint64(rule.ID)),
+				logger.Error(err))
+			return false
+		}
+		return evaluateWindow(cond.Operator, window.contains(eventTime(event).Local()))
+	case PropertySunWindow:
+		return e.evaluateSunWindow(rule, event, cond)
+	case PropertyEventCount:
+		return true
+	}
+
+	if rule.TriggerType == TriggerTypeMetric && cond.DurationSec > 0 {
+		// Metric sample already recorded in HandleEvent
+		trackerKey := metricBufferKey(rule.MetricName, event.Properties)
+		duration := time.Duration(cond.DurationSec) * time.Second
+		return e.metricTracker.IsSustained(trackerKey, cond.Operator, cond.Value, duration, event.Timestamp)
+	}
+	return evaluateCondition(cond, event.Properties)
+}
 
-	// For event triggers, evaluate conditions directly
-	return EvaluateConditions(rule.Conditions, event.Properties)
+func (e *Engine) evaluateSunWindow(rule *entities.AlertRule, event *AlertEvent, cond *entities.AlertCondition) bool {
+	sun := e.sunCalc()
+	if sun == nil {
+		return false
+	}
+	window, err := parseSunWindow(cond.Value)
+	if err != nil {
+		e.log.Debug("Invalid sun window in alert condition",
+			logger.Uint64("rule_id", uint64(rule.ID)),
+			logger.Error(err))
+		return false
+	}
+	at := eventTime(event)
+	times, err := sun.GetSunEventTimes(at)
+	if err != nil {
+		e.log.Debug("Failed to get sun times for alert condition",
+			logger.Uint64("rule_id", uint64(rule.ID)),
+			logger.Error(err))
+		return false
+	}
+	in, ok := window.contains(at, &times)
+	if !ok {
+		return false
+	}
+	return evaluateWindow(cond.Operator, in)
 }
 
-func (e *Engine) evaluateMetricConditions(rule *entities.AlertRule, event *AlertEvent) bool {
-	// Evaluate each condition (metric sample already recorded in HandleEvent)
-	trackerKey := metricBufferKey(rule.MetricName, event.Properties)
+// countMatches records the event for the rule's event_count conditions and
+// compares the number of matching events within each condition's window.
+// Events are counted per cooldown key, so species-scoped detection rules
+// count each species separately.
+func (e *Engine) countMatches(rule *entities.AlertRule, event *AlertEvent) bool {
+	var longest time.Duration
+	for i := range rule.Conditions {
+		if cond := &rule.Conditions[i]; cond.Property == PropertyEventCount {
+			longest = max(longest, time.Duration(cond.DurationSec)*time.Second)
+		}
+	}
+	now := eventTime(event)
+	key := cooldownKey(rule, event)
+
+	e.countsMu.Lock()
+	defer e.countsMu.Unlock()
+	times := append(e.counts[key], now)
+	start := 0
+	for start < len(times) && now.Sub(times[start]) >= longest {
+		start++
+	}
+	times = times[max(start, len(times)-maxCountedEvents):]
+	if len(times) == 0 {
+		delete(e.counts, key)
+	} else {
+		e.counts[key] = times
+	}
+	e.sweepCountsLocked(now)
+
 	for i := range rule.Conditions {
 		cond := &rule.Conditions[i]
-		if cond.DurationSec > 0 {
-			// Check sustained threshold
-			duration := time.Duration(cond.DurationSec) * time.Second
-			if !e.metricTracker.IsSustained(trackerKey, cond.Operator, cond.Value, duration, event.Timestamp) {
-				return false
+		if cond.Property != PropertyEventCount {
+			continue
+		}
+		threshold, err := strconv.ParseFloat(cond.Value, 64)
+		if err != nil {
+			e.log.Warn("Alert count condition has unparseable value",
+				logger.Uint64("rule_id", uint64(rule.ID)),
+				logger.String("condition_value", cond.Value),
+				logger.Error(err))
+			return false
+		}
+		window := time.Duration(cond.DurationSec) * time.Second
+		n := 0
+		for _, t := range times {
+			if now.Sub(t) < window {
+				n++
 			}
-		} else if !evaluateCondition(cond, event.Properties) {
-			// Instant check
+		}
+		if !compareFloat(float64(n), cond.Operator, threshold) {
 			return false
 		}
 	}
 	return true
 }
 
+// sweepCountsLocked drops the event counts of keys whose newest event has
+// left the longest count window, so species or metric instances that stop
+// occurring do not accumulate. Caller must hold countsMu.
+func (e *Engine) sweepCountsLocked(now time.Time) {
+	if now.Sub(e.countsSwept) < countsSweepInterval {
+		return
+	}
+	e.countsSwept = now
+	for key, times := range e.counts {
+		if len(times) == 0 || now.Sub(times[len(times)-1]) >= e.countWindow {
+			delete(e.counts, key)
+		}
+	}
+}
+
+// eventTime returns the event timestamp, or the current time for events
+// published without one.
+func eventTime(event *AlertEvent) time.Time {
+	if event.Timestamp.IsZero() {
+		return time.Now()
+	}
+	return event.Timestamp
+}
+
 // tryAcquireCooldown atomically checks whether key is in cooldown and, if not,
 // records the current time so subsequent calls observe the cooldown. This avoids
 // the TOCTOU race where a separate read-lock check + write-lock set would let
@@ -605,9 +796,12 @@ func (e *Engine) fireRuleInternal(rule *entities.AlertRule, event *AlertEvent, a
 		e.telemetry.ReportDBWriteFailed("save_history", err.Error())
 	}
 
-	// Dispatch actions
+	// Dispatch actions. The event is copied because HandleEvent passes the
+	// same event to every matching rule, and each firing has its own history.
 	if actionFn != nil {
-		actionFn(rule, event)
+		fired := *event
+		fired.HistoryID = history.ID
+		actionFn(rule, &fired)
 	}
 }
 
@@ -15,12 +15,14 @@ import (
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
 	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
 )
 
 // mockAlertRuleRepo is a minimal in-memory mock of AlertRuleRepository.
 type mockAlertRuleRepo struct {
 	rules   []entities.AlertRule
 	history []*entities.AlertHistory
+	results []*entities.AlertActionResult
 	mu      sync.Mutex
 }
 
@@ -43,10 +45,28 @@ func (m *mockAlertRuleRepo) GetEnabledRules(_ context.Context) ([]entities.Alert
 func (m *mockAlertRuleRepo) SaveHistory(_ context.Context, h *entities.AlertHistory) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	h.ID = uint(len(m.history)) + 1 //nolint:gosec // test mock, no overflow risk
 	m.history = append(m.history, h)
 	return nil
 }
 
+func (m *mockAlertRuleRepo) SaveActionResult(_ context.Context, r *entities.AlertActionResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = append(m.results, r)
+	return nil
+}
+
+func (m *mockAlertRuleRepo) savedResults() []entities.AlertActionResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]entities.AlertActionResult, len(m.results))
+	for i, r := range m.results {
+		out[i] = *r
+	}
+	return out
+}
+
 // Unused methods — satisfy interface.
 func (m *mockAlertRuleRepo) ListRules(_ context.Context, _ repository.AlertRuleFilter) ([]entities.AlertRule, error) {
 	return []entities.AlertRule{}, nil
@@ -351,6 +371,34 @@ func TestEngine_ActionDispatchRecordsHistory(t *testing.T) {
 	assert.NotEmpty(t, repo.history[0].Actions)
 }
 
+func TestEngine_DispatchedEventCarriesHistoryID(t *testing.T) {
+	rules := []entities.AlertRule{
+		{ID: 1, Enabled: true, ObjectType: ObjectTypeStream, TriggerType: TriggerTypeEvent, EventName: EventStreamDisconnected},
+		{ID: 2, Enabled: true, ObjectType: ObjectTypeStream, TriggerType: TriggerTypeEvent, EventName: EventStreamDisconnected},
+	}
+	repo := newMockRepo(rules...)
+
+	historyIDs := map[uint]uint{}
+	engine := NewEngine(repo, func(rule *entities.AlertRule, event *AlertEvent) {
+		historyIDs[rule.ID] = event.HistoryID
+	}, testLogger(), nil)
+
+	require.NoError(t, engine.RefreshRules(t.Context()))
+
+	event := &AlertEvent{
+		ObjectType: ObjectTypeStream,
+		EventName:  EventStreamDisconnected,
+		Timestamp:  time.Now(),
+	}
+	engine.HandleEvent(event)
+
+	require.Len(t, historyIDs, 2)
+	assert.NotEqual(t, historyIDs[1], historyIDs[2], "each firing should link to its own history entry")
+	assert.NotZero(t, historyIDs[1])
+	assert.NotZero(t, historyIDs[2])
+	assert.Zero(t, event.HistoryID, "the shared event must not be modified")
+}
+
 func TestEngine_MetricRuleWithSustainedDuration(t *testing.T) {
 	rule := entities.AlertRule{
 		ID:          1,
@@ -460,7 +508,7 @@ func TestEngine_DiskMetricPathIsolation(t *testing.T) {
 
 func TestEngine_DiskMetricPathIsolation_Sustained(t *testing.T) {
 	// Uses DurationSec > 0 to exercise the per-path MetricTracker buffer isolation
-	// in evaluateMetricConditions via metricBufferKey().
+	// in evaluateRuleCondition via metricBufferKey().
 	rule := entities.AlertRule{
 		ID:          1,
 		Enabled:     true,
@@ -1308,3 +1356,275 @@ func TestEngine_MultipleRulesSameMetricNoDuplicateRecording(t *testing.T) {
 	engine.metricTracker.mu.RUnlock()
 	assert.Len(t, samples, 1, "metric should be recorded once per event, not once per rule")
 }
+
+func TestEngine_AnyGroupFires(t *testing.T) {
+	rule := entities.AlertRule{
+		ID:              1,
+		Enabled:         true,
+		ObjectType:      ObjectTypeDetection,
+		TriggerType:     TriggerTypeEvent,
+		EventName:       EventDetectionOccurred,
+		ConditionGroups: []entities.AlertConditionGroup{{Key: 1, Match: MatchAny}},
+		Conditions: []entities.AlertCondition{
+			{Property: PropertyConfidence, Operator: OperatorGreaterThan, Value: "0.5"},
+			{Property: PropertySpeciesName, Operator: OperatorIs, Value: "Eurasian Eagle-Owl", GroupKey: 1},
+			{Property: PropertyConfidence, Operator: OperatorGreaterThan, Value: "0.95", GroupKey: 1},
+		},
+	}
+	repo := newMockRepo(rule)
+
+	var fireCount int
+	engine := NewEngine(repo, func(_ *entities.AlertRule, _ *AlertEvent) {
+		fireCount++
+	}, testLogger(), nil)
+	require.NoError(t, engine.RefreshRules(t.Context()))
+
+	detection := func(species string, confidence float64) *AlertEvent {
+		return &AlertEvent{
+			ObjectType: ObjectTypeDetection,
+			EventName:  EventDetectionOccurred,
+			Properties: map[string]any{PropertySpeciesName: species, PropertyConfidence: confidence},
+			Timestamp:  time.Now(),
+		}
+	}
+
+	engine.HandleEvent(detection("Eurasian Eagle-Owl", 0.7))
+	engine.HandleEvent(detection("Great Tit", 0.97))
+	engine.HandleEvent(detection("Great Tit", 0.7))
+	engine.HandleEvent(detection("Eurasian Eagle-Owl", 0.3))
+
+	assert.Equal(t, 2, fireCount, "rule should fire when the root and one member of the OR group match")
+}
+
+func TestEngine_TimeOfDayCondition(t *testing.T) {
+	rule := entities.AlertRule{
+		ID:          1,
+		Enabled:     true,
+		ObjectType:  ObjectTypeDetection,
+		TriggerType: TriggerTypeEvent,
+		EventName:   EventDetectionOccurred,
+		Conditions: []entities.AlertCondition{
+			{Property: PropertyTimeOfDay, Operator: OperatorBetween, Value: "22:00,06:00"},
+		},
+	}
+	repo := newMockRepo(rule)
+
+	var fireCount int
+	engine := NewEngine(repo, func(_ *entities.AlertRule, _ *AlertEvent) {
+		fireCount++
+	}, testLogger(), nil)
+	require.NoError(t, engine.RefreshRules(t.Context()))
+
+	at := func(hour int) *AlertEvent {
+		return &AlertEvent{
+			ObjectType: ObjectTypeDetection,
+			EventName:  EventDetectionOccurred,
+			Properties: map[string]any{},
+			Timestamp:  time.Date(2026, 5, 1, hour, 30, 0, 0, time.Local),
+		}
+	}
+
+	engine.HandleEvent(at(12))
+	assert.Equal(t, 0, fireCount, "midday detection is outside the window")
+
+	engine.HandleEvent(at(23))
+	engine.HandleEvent(at(3))
+	assert.Equal(t, 2, fireCount, "night detections are inside the window")
+}
+
+func TestEngine_SunWindowCondition(t *testing.T) {
+	rule := entities.AlertRule{
+		ID:          1,
+		Enabled:     true,
+		ObjectType:  ObjectTypeDetection,
+		TriggerType: TriggerTypeEvent,
+		EventName:   EventDetectionOccurred,
+		Conditions: []entities.AlertCondition{
+			{Property: PropertySunWindow, Operator: OperatorBetween, Value: "sunset,sunrise"},
+		},
+	}
+	repo := newMockRepo(rule)
+
+	var fireCount int
+	engine := NewEngine(repo, func(_ *entities.AlertRule, _ *AlertEvent) {
+		fireCount++
+	}, testLogger(), nil)
+	var lat, lon float64
+	engine.SetLocationFunc(func() (float64, float64) { return lat, lon })
+	require.NoError(t, engine.RefreshRules(t.Context()))
+
+	at := func(ts time.Time) *AlertEvent {
+		return &AlertEvent{
+			ObjectType: ObjectTypeDetection,
+			EventName:  EventDetectionOccurred,
+			Properties: map[string]any{},
+			Timestamp:  ts,
+		}
+	}
+
+	sc := suncalc.NewSunCalc(60.17, 24.94)
+	times, err := sc.GetSunEventTimes(time.Date(2026, 3, 20, 12, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+
+	engine.HandleEvent(at(times.Sunset.Add(time.Hour)))
+	assert.Equal(t, 0, fireCount, "sun windows never match without a location")
+
+	lat, lon = 60.17, 24.94
+	engine.HandleEvent(at(times.Sunrise.Add(3 * time.Hour)))
+	assert.Equal(t, 0, fireCount, "daytime detection is outside the window")
+
+	engine.HandleEvent(at(times.Sunset.Add(time.Hour)))
+	assert.Equal(t, 1, fireCount, "detection after sunset is inside the window")
+}
+
+func TestEngine_SunWindowFollowsLocationChanges(t *testing.T) {
+	engine := NewEngine(newMockRepo(), func(_ *entities.AlertRule, _ *AlertEvent) {}, testLogger(), nil)
+	lat, lon := 60.17, 24.94
+	engine.SetLocationFunc(func() (float64, float64) { return lat, lon })
+
+	helsinki := engine.sunCalc()
+	require.NotNil(t, helsinki)
+	assert.Same(t, helsinki, engine.sunCalc(), "calculator is reused while the location is unchanged")
+
+	lat, lon = -33.87, 151.21
+	sydney := engine.sunCalc()
+	require.NotNil(t, sydney)
+	assert.NotSame(t, helsinki, sydney, "calculator is rebuilt when the location changes")
+	assert.InDelta(t, -33.87, engine.sunLat, 1e-9)
+	assert.InDelta(t, 151.21, engine.sunLon, 1e-9)
+
+	lat, lon = 0, 0
+	assert.Nil(t, engine.sunCalc(), "a cleared location disables sun windows")
+}
+
+func TestEngine_EventCountKeysArePruned(t *testing.T) {
+	countRule := func(id uint, species string) entities.AlertRule {
+		return entities.AlertRule{
+			ID:          id,
+			Enabled:     true,
+			ObjectType:  ObjectTypeDetection,
+			TriggerType: TriggerTypeEvent,
+			EventName:   EventDetectionOccurred,
+			Conditions: []entities.AlertCondition{
+				{Property: PropertySpeciesName, Operator: OperatorIs, Value: species},
+				{Property: PropertyEventCount, Operator: OperatorGreaterThan, Value: "5", DurationSec: 3600},
+			},
+		}
+	}
+	repo := newMockRepo(countRule(1, "Common Crane"), countRule(2, "Great Tit"), countRule(3, "Eurasian Wren"))
+	engine := NewEngine(repo, func(_ *entities.AlertRule, _ *AlertEvent) {}, testLogger(), nil)
+	require.NoError(t, engine.RefreshRules(t.Context()))
+
+	base := time.Now().Add(-5 * time.Hour)
+	detection := func(species string, offset time.Duration) *AlertEvent {
+		return &AlertEvent{
+			ObjectType: ObjectTypeDetection,
+			EventName:  EventDetectionOccurred,
+			Properties: map[string]any{PropertySpeciesName: species},
+			Timestamp:  base.Add(offset),
+		}
+	}
+	countKeys := func() int {
+		engine.countsMu.Lock()
+		defer engine.countsMu.Unlock()
+		return len(engine.counts)
+	}
+
+	engine.HandleEvent(detection("Common Crane", 0))
+	engine.HandleEvent(detection("Great Tit", time.Minute))
+	assert.Equal(t, 2, countKeys())
+
+	// Both earlier events have left the one-hour window when the next sweep runs
+	engine.HandleEvent(detection("Eurasian Wren", 2*time.Hour))
+	assert.Equal(t, 1, countKeys(), "keys without events in the window are swept")
+
+	require.NoError(t, engine.RefreshRules(t.Context()))
+	assert.Equal(t, 0, countKeys(), "reloading rules clears the counts")
+}
+
+func TestEngine_EventCountCondition(t *testing.T) {
+	rule := entities.AlertRule{
+		ID:          1,
+		Enabled:     true,
+		ObjectType:  ObjectTypeDetection,
+		TriggerType: TriggerTypeEvent,
+		EventName:   EventDetectionOccurred,
+		Conditions: []entities.AlertCondition{
+			{Property: PropertySpeciesName, Operator: OperatorIs, Value: "Common Crane"},
+			{Property: PropertyEventCount, Operator: OperatorGreaterThan, Value: "2", DurationSec: 3600},
+		},
+	}
+	repo := newMockRepo(rule)
+
+	var fireCount int
+	engine := NewEngine(repo, func(_ *entities.AlertRule, _ *AlertEvent) {
+		fireCount++
+	}, testLogger(), nil)
+	require.NoError(t, engine.RefreshRules(t.Context()))
+
+	base := time.Now().Add(-3 * time.Hour)
+	detection := func(species string, offset time.Duration) *AlertEvent {
+		return &AlertEvent{
+			ObjectType: ObjectTypeDetection,
+			EventName:  EventDetectionOccurred,
+			Properties: map[string]any{PropertySpeciesName: species},
+			Timestamp:  base.Add(offset),
+		}
+	}
+
+	engine.HandleEvent(detection("Common Crane", 0))
+	engine.HandleEvent(detection("Common Crane", 10*time.Minute))
+	engine.HandleEvent(detection("Great Tit", 15*time.Minute))
+	assert.Equal(t, 0, fireCount, "two matching detections are not enough")
+
+	engine.HandleEvent(detection("Common Crane", 20*time.Minute))
+	assert.Equal(t, 1, fireCount, "third matching detection within the window should fire")
+
+	// The first three detections have left the window by now
+	engine.HandleEvent(detection("Common Crane", 2*time.Hour))
+	assert.Equal(t, 1, fireCount, "detections outside the window are not counted")
+}
+
+func TestEngine_SustainedConditionInGroup(t *testing.T) {
+	rule := entities.AlertRule{
+		ID:              1,
+		Enabled:         true,
+		ObjectType:      ObjectTypeSystem,
+		TriggerType:     TriggerTypeMetric,
+		MetricName:      MetricCPUTemperature,
+		ConditionGroups: []entities.AlertConditionGroup{{Key: 1, Match: MatchAny}},
+		Conditions: []entities.AlertCondition{
+			{Property: PropertyValue, Operator: OperatorGreaterThan, Value: "75", DurationSec: 600, GroupKey: 1},
+			{Property: PropertyValue, Operator: OperatorGreaterThan, Value: "90", GroupKey: 1},
+		},
+	}
+	repo := newMockRepo(rule)
+
+	var fireCount int
+	engine := NewEngine(repo, func(_ *entities.AlertRule, _ *AlertEvent) {
+		fireCount++
+	}, testLogger(), nil)
+	require.NoError(t, engine.RefreshRules(t.Context()))
+
+	base := time.Now().Add(-20 * time.Minute)
+	sample := func(minute int, celsius float64) *AlertEvent {
+		return &AlertEvent{
+			ObjectType: ObjectTypeSystem,
+			MetricName: MetricCPUTemperature,
+			Properties: map[string]any{PropertyValue: celsius},
+			Timestamp:  base.Add(time.Duration(minute) * time.Minute),
+		}
+	}
+
+	for minute := range 6 {
+		engine.HandleEvent(sample(minute, 80))
+	}
+	assert.Equal(t, 0, fireCount, "80 °C for five minutes is not yet sustained")
+
+	engine.HandleEvent(sample(10, 80))
+	assert.Equal(t, 1, fireCount, "80 °C for ten minutes should fire")
+
+	engine.HandleEvent(sample(11, 60))
+	engine.HandleEvent(sample(12, 95))
+	assert.Equal(t, 2, fireCount, "a spike above 90 °C fires without a duration")
+}
@@ -13,6 +13,7 @@ type AlertEvent struct {
 	MetricName string         // For metric triggers (e.g., "system.cpu_usage")
 	Properties map[string]any // Event-specific properties for condition evaluation
 	Timestamp  time.Time
+	HistoryID  uint // Alert history entry of the firing; set by the engine before dispatch
 }
 
 // AlertEventHandler processes alert events.
@@ -301,12 +301,14 @@ func renderDetectionTemplates(props map[string]any) (title, message string) {
 
 // Initialize creates and starts the alerting engine.
 // It seeds default rules if none exist, creates the engine with the
-// action dispatcher, subscribes to the event bus, and loads rules.
+// action dispatcher, subscribes to the event bus, and loads rules. opts
+// supply the MQTT client and internal controls used by automation actions.
 func Initialize(
 	repo repository.AlertRuleRepository,
 	eventBus *AlertEventBus,
 	log logger.Logger,
 	at *AlertingTelemetry,
+	opts ...DispatcherOption,
 ) (*Engine, error) {
 	ctx := context.Background()
 
@@ -316,8 +318,10 @@ func Initialize(
 		return nil, err
 	}
 
-	// Create dispatcher and engine (adapter lazily resolves notification service)
-	dispatcher := NewActionDispatcher(&notificationAdapter{}, log, at)
+	// Create dispatcher and engine (adapter lazily resolves notification service).
+	// Automation action results are stored next to the alert history.
+	opts = append([]DispatcherOption{WithActionResultStore(repo)}, opts...)
+	dispatcher := NewActionDispatcher(&notificationAdapter{}, log, at, opts...)
 	engine := NewEngine(repo, dispatcher.Dispatch, log, at)
 	engine.SetTestActionFunc(dispatcher.DispatchTest)
 
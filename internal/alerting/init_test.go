@@ -67,6 +67,9 @@ func (m *initMockRepo) SaveHistory(_ context.Context, h *entities.AlertHistory)
 	m.history = append(m.history, *h)
 	return nil
 }
+func (m *initMockRepo) SaveActionResult(_ context.Context, _ *entities.AlertActionResult) error {
+	return nil
+}
 func (m *initMockRepo) ListHistory(_ context.Context, _ repository.AlertHistoryFilter) ([]entities.AlertHistory, int64, error) {
 	return m.history, int64(len(m.history)), nil
 }
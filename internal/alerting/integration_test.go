@@ -83,6 +83,10 @@ func (r *integrationRepo) SaveHistory(_ context.Context, h *entities.AlertHistor
 	return nil
 }
 
+func (r *integrationRepo) SaveActionResult(_ context.Context, _ *entities.AlertActionResult) error {
+	return nil
+}
+
 func (r *integrationRepo) ListHistory(_ context.Context, _ repository.AlertHistoryFilter) ([]entities.AlertHistory, int64, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -9,10 +9,12 @@ import (
 )
 
 const (
-	// maxSamplesPerMetric is the maximum number of samples retained per metric.
-	maxSamplesPerMetric = 120
-	// maxSampleAge is the maximum age of a sample before eviction.
-	maxSampleAge = 30 * time.Minute
+	// maxSamplesPerMetric is the maximum number of samples retained per metric,
+	// enough for maxSampleAge at a 5 second collection interval.
+	maxSamplesPerMetric = 720
+	// maxSampleAge is the maximum age of a sample before eviction, and so the
+	// longest duration a sustained condition can use.
+	maxSampleAge = 60 * time.Minute
 )
 
 // metricSample is a single timestamped metric value.
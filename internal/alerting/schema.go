@@ -2,8 +2,12 @@ package alerting
 
 // Schema describes the full catalog of alertable object types, events, and metrics.
 type Schema struct {
-	ObjectTypes []ObjectTypeSchema `json:"objectTypes"`
-	Operators   []OperatorSchema   `json:"operators"`
+	ObjectTypes       []ObjectTypeSchema `json:"objectTypes"`
+	Operators         []OperatorSchema   `json:"operators"`
+	ContextProperties []PropertySchema   `json:"contextProperties"` // Evaluated by the engine; valid for every trigger
+	GroupMatches      []string           `json:"groupMatches"`      // Match modes of condition groups
+	SunEvents         []string           `json:"sunEvents"`         // Sun events accepted in sun_window values
+	Actions           []ActionSchema     `json:"actions"`
 }
 
 // ObjectTypeSchema describes an object type and its available triggers.
@@ -34,8 +38,10 @@ type MetricSchema struct {
 type PropertySchema struct {
 	Name      string   `json:"name"`
 	Label     string   `json:"label"`
-	Type      string   `json:"type"` // "string" or "number"
+	Type      string   `json:"type"` // "string", "number" or "window"
 	Operators []string `json:"operators"`
+	Format    string   `json:"format,omitempty"`   // Value format for window properties
+	Duration  string   `json:"duration,omitempty"` // duration_sec meaning: "sustained" (optional hold time) or "window" (required counting window)
 }
 
 // OperatorSchema describes an operator for the UI.
@@ -45,6 +51,15 @@ type OperatorSchema struct {
 	Type  string `json:"type"` // "string", "number", or "all"
 }
 
+// ActionSchema describes an action target and the action fields it uses.
+type ActionSchema struct {
+	Target   string   `json:"target"`
+	Label    string   `json:"label"`
+	Fields   []string `json:"fields"`
+	Controls []string `json:"controls,omitempty"` // Control actions (control target only)
+	Scripts  []string `json:"scripts,omitempty"`  // Configured script names (script target only)
+}
+
 // stringOperators are operators valid for string properties.
 var stringOperators = []string{OperatorIs, OperatorIsNot, OperatorIn, OperatorNotIn, OperatorContains, OperatorNotContains}
 
@@ -104,6 +119,7 @@ func GetSchema() Schema {
 				Label: "System",
 				Metrics: []MetricSchema{
 					{Name: MetricCPUUsage, Label: "CPU Usage", Unit: "%", Properties: numericValueProperties()},
+					{Name: MetricCPUTemperature, Label: "CPU Temperature", Unit: "°C", Properties: numericValueProperties()},
 					{Name: MetricMemoryUsage, Label: "Memory Usage", Unit: "%", Properties: numericValueProperties()},
 					{Name: MetricDiskUsage, Label: "Disk Usage", Unit: "%", Properties: numericValueProperties()},
 				},
@@ -120,6 +136,19 @@ func GetSchema() Schema {
 			{Name: OperatorLessThan, Label: "less than", Type: "number"},
 			{Name: OperatorGreaterOrEqual, Label: "greater or equal", Type: "number"},
 			{Name: OperatorLessOrEqual, Label: "less or equal", Type: "number"},
+			{Name: OperatorBetween, Label: "between", Type: "window"},
+			{Name: OperatorNotBetween, Label: "not between", Type: "window"},
+		},
+		ContextProperties: contextProperties(),
+		GroupMatches:      []string{MatchAll, MatchAny},
+		SunEvents:         sunEvents,
+		Actions: []ActionSchema{
+			{Target: TargetBell, Label: "In-App Notification", Fields: []string{"template_title", "template_message"}},
+			{Target: TargetPush, Label: "Push Notification", Fields: []string{"template_title", "template_message"}},
+			{Target: TargetMQTT, Label: "MQTT Publish", Fields: []string{"topic", "template_body"}},
+			{Target: TargetWebhook, Label: "Webhook", Fields: []string{"url", "method", "template_body"}},
+			{Target: TargetScript, Label: "Run Script", Fields: []string{"script", "template_body"}, Scripts: ScriptNames()},
+			{Target: TargetControl, Label: "Internal Control", Fields: []string{"control", "control_arg"}, Controls: controlActions},
 		},
 	}
 }
@@ -128,6 +157,7 @@ func streamProperties() []PropertySchema {
 	return []PropertySchema{
 		{Name: PropertyStreamName, Label: "Stream Name", Type: "string", Operators: stringOperators},
 		{Name: PropertyStreamURL, Label: "Stream URL", Type: "string", Operators: stringOperators},
+		{Name: PropertySourceID, Label: "Source ID", Type: "string", Operators: stringOperators},
 	}
 }
 
@@ -181,6 +211,14 @@ func deviceErrorProperties() []PropertySchema {
 
 func numericValueProperties() []PropertySchema {
 	return []PropertySchema{
-		{Name: PropertyValue, Label: "Value", Type: "number", Operators: numericOperators},
+		{Name: PropertyValue, Label: "Value", Type: "number", Operators: numericOperators, Duration: "sustained"},
+	}
+}
+
+func contextProperties() []PropertySchema {
+	return []PropertySchema{
+		{Name: PropertyTimeOfDay, Label: "Time of Day", Type: "window", Operators: windowOperators, Format: "HH:MM,HH:MM"},
+		{Name: PropertySunWindow, Label: "Sun Window", Type: "window", Operators: windowOperators, Format: "sunset-30,sunrise+30"},
+		{Name: PropertyEventCount, Label: "Matching Events", Type: "number", Operators: numericOperators, Duration: "window"},
 	}
 }
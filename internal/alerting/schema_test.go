@@ -45,7 +45,7 @@ func TestGetSchema_AllMetricsPresent(t *testing.T) {
 			allMetrics = append(allMetrics, m.Name)
 		}
 	}
-	assert.ElementsMatch(t, []string{MetricCPUUsage, MetricMemoryUsage, MetricDiskUsage}, allMetrics)
+	assert.ElementsMatch(t, []string{MetricCPUUsage, MetricCPUTemperature, MetricMemoryUsage, MetricDiskUsage}, allMetrics)
 }
 
 func TestGetSchema_AllOperatorsPresent(t *testing.T) {
@@ -57,6 +57,7 @@ func TestGetSchema_AllOperatorsPresent(t *testing.T) {
 	assert.ElementsMatch(t, []string{
 		OperatorIs, OperatorIsNot, OperatorIn, OperatorNotIn, OperatorContains, OperatorNotContains,
 		OperatorGreaterThan, OperatorLessThan, OperatorGreaterOrEqual, OperatorLessOrEqual,
+		OperatorBetween, OperatorNotBetween,
 	}, names)
 }
 
@@ -99,3 +100,33 @@ func TestGetSchema_LabelsNotEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestGetSchema_AllActionTargetsPresent(t *testing.T) {
+	schema := GetSchema()
+	targets := make([]string, len(schema.Actions))
+	for i, a := range schema.Actions {
+		targets[i] = a.Target
+		assert.NotEmpty(t, a.Label, "action %s should have a label", a.Target)
+	}
+	assert.ElementsMatch(t, []string{
+		TargetBell, TargetPush, TargetMQTT, TargetWebhook, TargetScript, TargetControl,
+	}, targets)
+
+	for _, a := range schema.Actions {
+		if a.Target == TargetControl {
+			assert.Equal(t, controlActions, a.Controls)
+		}
+	}
+}
+
+func TestGetSchema_ConditionContext(t *testing.T) {
+	schema := GetSchema()
+	assert.Equal(t, []string{MatchAll, MatchAny}, schema.GroupMatches)
+	assert.Equal(t, sunEvents, schema.SunEvents)
+
+	names := make([]string, len(schema.ContextProperties))
+	for i, p := range schema.ContextProperties {
+		names[i] = p.Name
+	}
+	assert.ElementsMatch(t, []string{PropertyTimeOfDay, PropertySunWindow, PropertyEventCount}, names)
+}
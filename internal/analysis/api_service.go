@@ -10,6 +10,8 @@ import (
 	"github.com/tphakala/birdnet-go/internal/audiocore"
 	"github.com/tphakala/birdnet-go/internal/audiocore/engine"
 	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/backup/sources"
+	"github.com/tphakala/birdnet-go/internal/backup/targets"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/health"
@@ -397,6 +399,17 @@ func initializeBackupSystem(settings *conf.Settings, backupLog logger.Logger) (*
 		backupLog.Info("Backup system is disabled.")
 	}
 
+	// Register the configured sources and targets. Targets are also what
+	// restores list and download backups from.
+	if settings.Backup.Enabled {
+		if err := sources.RegisterConfigured(backupManager, settings, backupLog); err != nil {
+			backupLog.Error("Failed to register backup sources", logger.Error(err))
+		}
+		if err := targets.RegisterConfigured(backupManager, &settings.Backup, backupLog); err != nil {
+			backupLog.Error("Failed to register backup targets", logger.Error(err))
+		}
+	}
+
 	// Start backupManager and backupScheduler if backup is enabled
 	if settings.Backup.Enabled {
 		backupLog.Info("Starting backup manager")
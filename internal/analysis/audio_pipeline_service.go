@@ -21,14 +21,18 @@ import (
 	"github.com/tphakala/birdnet-go/internal/audiocore/schedule"
 	"github.com/tphakala/birdnet-go/internal/audiocore/soundlevel"
 	"github.com/tphakala/birdnet-go/internal/classifier"
+	"github.com/tphakala/birdnet-go/internal/cliptier"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
+	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
 	"github.com/tphakala/birdnet-go/internal/diskmanager"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/notification"
 	"github.com/tphakala/birdnet-go/internal/observability"
 	"github.com/tphakala/birdnet-go/internal/privacy"
+	"github.com/tphakala/birdnet-go/internal/similarity"
 	"github.com/tphakala/birdnet-go/internal/weather"
 )
 
@@ -82,6 +86,21 @@ type AudioPipelineService struct {
 	// active for that source. Populated by registerSoundLevelConsumers, drained
 	// by removeAllSoundLevelConsumers.
 	soundLevelConsumers map[string]string
+
+	// soundscapes indexes continuous soundscape segments. Nil unless the
+	// enhanced (v2) database is active; soundscape recording needs the index
+	// for retention and playback, so sources that enable it are skipped
+	// without one.
+	soundscapes repository.SoundscapeRepository
+
+	// archivedClips indexes clips the tier retention policy moved to its
+	// secondary store. Nil unless the enhanced (v2) database is active; the
+	// tier policy is skipped without it.
+	archivedClips repository.ArchivedClipRepository
+
+	// embeddingIndexer computes clip embeddings for the similar recordings
+	// search. Nil unless the enhanced (v2) database is active.
+	embeddingIndexer *similarity.Indexer
 }
 
 // NewAudioPipelineService creates a new AudioPipelineService with the given dependencies.
@@ -211,6 +230,28 @@ func (p *AudioPipelineService) Start(_ context.Context) error {
 		logHLSCleanup(nil)
 	}
 
+	// Soundscape segments and archived clips are indexed in the enhanced
+	// database.
+	if v2 := p.dbService.V2Manager(); v2 != nil && datastoreV2.IsEnhancedDatabase() {
+		p.soundscapes = repository.NewSoundscapeRepository(v2.DB(), nil)
+		p.archivedClips = repository.NewArchivedClipRepository(v2.DB(), nil)
+	}
+
+	// Clip embeddings are stored in the enhanced database too. The indexer
+	// runs whenever the index exists and loads the model only once the
+	// option is enabled.
+	if v2 := p.dbService.V2Manager(); v2 != nil && datastoreV2.IsEnhancedDatabase() {
+		p.embeddingIndexer = newEmbeddingIndexer(bn, repository.NewEmbeddingRepository(v2.DB(), nil))
+		p.embeddingIndexer.Start()
+		p.apiService.Processor().SetEmbeddingIndexer(p.embeddingIndexer)
+	}
+
+	// The privacy filter records the voice it redacts from saved clips in
+	// the enhanced database.
+	if v2 := p.dbService.V2Manager(); v2 != nil && datastoreV2.IsEnhancedDatabase() {
+		p.apiService.Processor().SetRedactionStore(repository.NewClipRedactionRepository(v2.DB(), nil))
+	}
+
 	// Initialize channels.
 	p.soundLevelChan = make(chan soundlevel.SoundLevelData, 100)
 	p.restartChan = make(chan struct{}, 10)
@@ -327,10 +368,12 @@ func (p *AudioPipelineService) Start(_ context.Context) error {
 	)
 	p.watchdog.Start()
 
-	// Expose the watchdog and source restarter to the API controller.
+	// Expose the watchdog, source restarter and quiet hours scheduler to the
+	// API controller.
 	if ctrl := p.apiService.APIController(); ctrl != nil {
 		ctrl.SetAudioWatchdog(p.watchdog)
 		ctrl.SetSourceRestarter(p.RestartSource)
+		ctrl.SetSourceScheduler(p.quietHoursScheduler)
 	}
 
 	// Inject suncalc into the orchestrator for bat nighttime scheduling.
@@ -353,9 +396,10 @@ func (p *AudioPipelineService) Start(_ context.Context) error {
 	// Start clip cleanup monitor.
 	// Uses conf.Setting() instead of local settings for hot-reload support:
 	// retention policy can be changed at runtime via the web UI.
+	archivedClips := p.archivedClips
 	if conf.Setting().Realtime.Audio.Export.Retention.Policy != policyNone {
 		p.wg.Go(func() {
-			clipCleanupMonitor(p.done, dataStore)
+			clipCleanupMonitor(p.done, dataStore, archivedClips)
 		})
 	}
 
@@ -363,10 +407,25 @@ func (p *AudioPipelineService) Start(_ context.Context) error {
 	// policy and regardless of whether audio export is enabled) because orphaned
 	// clip_name references persist across runtime toggling of export, and clearing
 	// them keeps clip_name a truthful per-detection signal for the media API and UI.
+	// Archived clips are passed in so clips the tier policy moved away are not
+	// mistaken for orphans, even after the policy is switched off.
 	p.wg.Go(func() {
-		clipReconcileMonitor(p.done, dataStore)
+		var archive diskmanager.ArchivedClipLookup
+		if archivedClips != nil {
+			archive = archivedClips
+		}
+		clipReconcileMonitor(p.done, dataStore, archive)
 	})
 
+	// Start soundscape retention monitor. Runs whenever the index exists so
+	// segments recorded before a source was switched off still expire.
+	if p.soundscapes != nil {
+		soundscapes := p.soundscapes
+		p.wg.Go(func() {
+			soundscapeRetentionMonitor(p.done, soundscapes)
+		})
+	}
+
 	// Start weather polling.
 	if settings.Realtime.Weather.Provider != policyNone {
 		p.startWeatherPolling(metrics)
@@ -454,6 +513,12 @@ func (p *AudioPipelineService) Stop(ctx context.Context) error {
 		p.audioLevelStats.Stop()
 	}
 
+	// Stop the clip embedding indexer; queued clips are dropped.
+	if p.embeddingIndexer != nil {
+		p.embeddingIndexer.Stop()
+		p.embeddingIndexer = nil
+	}
+
 	// Close done channel to signal restart loop and clip cleanup goroutines.
 	// Protected by sync.Once to prevent panic on double-close.
 	p.doneOnce.Do(func() {
@@ -894,8 +959,9 @@ func (p *AudioPipelineService) removeAllSoundLevelConsumers(operation string) {
 	}
 }
 
-// registerConsumersForSources registers BufferConsumer and AudioLevelConsumer
-// on the AudioRouter for each source ID. The sourceModelMap carries the
+// registerConsumersForSources registers BufferConsumer and AudioLevelConsumer,
+// plus a soundscape recorder for sources that record one, on the AudioRouter
+// for each source ID. The sourceModelMap carries the
 // config-level model IDs for each source so that buffer consumers fan out to
 // only the models assigned to that source. When a source has no configured
 // models (empty slice), the primary model is used as a fallback.
@@ -1008,6 +1074,10 @@ func (p *AudioPipelineService) registerConsumersForSources(sourceIDs []string, s
 				logger.String("source_id", sid), logger.Error(routeErr), logger.String("operation", operation))
 		}
 
+		if currentSettings.SoundscapeEnabledFor(sourceName) {
+			p.registerSoundscapeRecorder(sid, sourceName, sourceSampleRate, gainDB, operation)
+		}
+
 		alc, alcOutCh := NewAudioLevelConsumer("audio_level_"+sid, sourceSampleRate, conf.BitDepth, 1)
 		alcChain := equalizer.ResolveAndBuildFilterChain(currentSettings, sourceName, sourceSampleRate)
 		if routeErr := p.engine.Router().AddRoute(sid, alc, sourceSampleRate, gainDB, alcChain); routeErr != nil {
@@ -1215,6 +1285,15 @@ func (p *AudioPipelineService) reconfigureChangedSources(audioLevelChan chan aud
 					logger.String("operation", "reconfigure_diff"))
 				registry.UpdateGain(src.ID, scm.config.Gain)
 				gainChangedIDs = append(gainChangedIDs, src.ID)
+
+			case p.soundscapeRouteChanged(src.ID, scm.soundscape):
+				// Soundscape recording toggled: rebuild routes like a gain
+				// change so the recorder is added or closed.
+				log.Info("soundscape recording toggled for kept source, rebuilding routes",
+					logger.String("source_id", src.ID),
+					logger.Bool("soundscape", scm.soundscape),
+					logger.String("operation", "reconfigure_diff"))
+				gainChangedIDs = append(gainChangedIDs, src.ID)
 			}
 
 			// Sync display name if the config name changed (e.g., stream renamed in UI).
@@ -1284,8 +1363,8 @@ func (p *AudioPipelineService) reconfigureChangedSources(audioLevelChan chan aud
 		p.registerSoundLevelConsumers(reconfiguredIDs, "reconfigure_params")
 	}
 
-	// Rebuild routes for sources whose gain changed. The capture device
-	// stays running; only the routes are torn down and re-created so
+	// Rebuild routes for sources whose gain changed or whose soundscape
+	// recording was toggled. The capture device stays running; only the routes are torn down and re-created so
 	// drainRoute picks up the new gainLinear value.
 	if len(gainChangedIDs) > 0 {
 		for _, sid := range gainChangedIDs {
@@ -1341,8 +1420,9 @@ func (p *AudioPipelineService) reconfigureChangedSources(audioLevelChan chan aud
 // model IDs assigned to that source. This allows the pipeline to build
 // per-source model targets when registering buffer consumers.
 type sourceConfigWithModels struct {
-	config   *audiocore.SourceConfig
-	modelIDs []string // config-level IDs, e.g., ["birdnet", "perch_v2"]
+	config     *audiocore.SourceConfig
+	modelIDs   []string // config-level IDs, e.g., ["birdnet", "perch_v2"]
+	soundscape bool     // record a continuous soundscape
 }
 
 // buildSourceConfigsWithModels constructs audiocore.SourceConfig entries from
@@ -1395,7 +1475,8 @@ func (p *AudioPipelineService) buildSourceConfigsWithModels() []sourceConfigWith
 				MediaMode:        string(stream.MediaMode),
 				Gain:             stream.Gain,
 			},
-			modelIDs: stream.Models,
+			modelIDs:   stream.Models,
+			soundscape: stream.Soundscape,
 		})
 	}
 
@@ -1431,7 +1512,12 @@ func (p *AudioPipelineService) buildSourceConfigsWithModels() []sourceConfigWith
 		// device is actually a network stream URL. This keeps a misplaced
 		// stream URL from being opened as an ALSA device (which fails and
 		// breaks live audio) even before the config migration relocates it.
+		//
+		// A channel selection splits a multichannel card into logical sources;
+		// it is carried in the connection string so each one registers with
+		// its own source ID and the device manager can share the card.
 		sourceType := audiocore.SourceTypeAudioCard
+		connStr := conf.DeviceChannelsConnection(device, src.Channels)
 		if streamType, isStream := audiocore.StreamSourceType(device); isStream {
 			if _, dup := streamConns[device]; dup {
 				// Already produced from rtsp.streams; skip the duplicate so the
@@ -1439,19 +1525,21 @@ func (p *AudioPipelineService) buildSourceConfigsWithModels() []sourceConfigWith
 				continue
 			}
 			sourceType = streamType
+			connStr = device
 		}
 
 		result = append(result, sourceConfigWithModels{
 			config: &audiocore.SourceConfig{
 				DisplayName:      src.Name,
 				Type:             sourceType,
-				ConnectionString: device,
+				ConnectionString: connStr,
 				SampleRate:       sampleRate,
 				BitDepth:         conf.BitDepth,
 				Channels:         1,
 				Gain:             src.Gain,
 			},
-			modelIDs: src.Models,
+			modelIDs:   src.Models,
+			soundscape: src.Soundscape,
 		})
 	}
 
@@ -1692,7 +1780,9 @@ func classifyExportDir(path string) (exportDirState, error) {
 }
 
 // clipCleanupMonitor monitors the database and deletes clips that meet the retention policy.
-func clipCleanupMonitor(quitChan chan struct{}, dataStore datastore.Interface) {
+// Under the tier policy clips are moved to the secondary store instead, which
+// needs the archived clip index; archivedClips is nil without the enhanced database.
+func clipCleanupMonitor(quitChan chan struct{}, dataStore datastore.Interface, archivedClips repository.ArchivedClipRepository) {
 	log := GetLogger()
 
 	// Read initial interval for the startup log message.
@@ -1813,10 +1903,51 @@ func clipCleanupMonitor(quitChan chan struct{}, dataStore datastore.Interface) {
 						logger.String("operation", "usage_based_cleanup"))
 				}
 			}
+
+			if currentPolicy == conf.RetentionPolicyTier {
+				runTierCleanup(quitChan, dataStore, archivedClips, exportCfg.Path, exportCfg.Retention)
+			}
 		}
 	}
 }
 
+// runTierCleanup runs one tier retention pass. The secondary store is built
+// from the current settings on every pass, so target changes take effect
+// without a restart, and closed afterwards.
+func runTierCleanup(quitChan chan struct{}, dataStore datastore.Interface, archivedClips repository.ArchivedClipRepository, baseDir string, retention conf.RetentionSettings) {
+	log := GetLogger()
+	if archivedClips == nil {
+		log.Warn("tier retention requires the enhanced database, skipping cleanup",
+			logger.String("operation", "tier_cleanup"))
+		return
+	}
+
+	store, err := cliptier.NewStore(&retention.Tier)
+	if err != nil {
+		log.Error("failed to create clip tier store",
+			logger.String("target", retention.Tier.Target),
+			logger.Error(err),
+			logger.String("operation", "tier_cleanup"))
+		return
+	}
+	defer func() { _ = store.Close() }()
+
+	archiver := cliptier.NewArchiver(store, archivedClips, baseDir)
+	result := diskmanager.TierCleanup(quitChan, dataStore, archiver, baseDir, &retention, time.Now())
+	if result.Err != nil {
+		log.Error("tier cleanup failed",
+			logger.String("target", store.Name()),
+			logger.Error(result.Err),
+			logger.String("operation", "tier_cleanup"))
+		return
+	}
+	log.Info("tier cleanup completed",
+		logger.String("target", store.Name()),
+		logger.Int("clips_moved", result.ClipsRemoved),
+		logger.Int("disk_utilization_percent", result.DiskUtilization),
+		logger.String("operation", "tier_cleanup"))
+}
+
 // cleanupHLSWithTimeout runs HLS cleanup asynchronously with a timeout to prevent blocking shutdown
 func cleanupHLSWithTimeout(ctx context.Context) {
 	// Create a channel to signal completion
@@ -28,8 +28,9 @@ const clipReconcilePassInterval = 1 * time.Hour
 // references persist across runtime toggling of the export setting. It reads the
 // export path via conf.Setting() each pass so hot-reload takes effect, and the
 // underlying diskmanager pass applies fail-safe guards so a detached/unmounted
-// export volume never causes mass clearing.
-func clipReconcileMonitor(quitChan <-chan struct{}, dataStore datastore.Interface) {
+// export volume never causes mass clearing. archive, when non-nil, reports clips
+// the tier retention policy moved to its secondary store so they are not cleared.
+func clipReconcileMonitor(quitChan <-chan struct{}, dataStore datastore.Interface, archive diskmanager.ArchivedClipLookup) {
 	log := GetLogger()
 	log.Info("clip reconcile monitor initialized",
 		logger.String("operation", "clip_reconcile_init"))
@@ -45,7 +46,7 @@ func clipReconcileMonitor(quitChan <-chan struct{}, dataStore datastore.Interfac
 			log.Debug("skipping clip reconcile: export path not configured",
 				logger.String("operation", "clip_reconcile_skip"))
 		} else {
-			result := diskmanager.ReconcileClipOrphansPass(quitChan, dataStore, baseDir)
+			result := diskmanager.ReconcileClipOrphansPass(quitChan, dataStore, baseDir, archive)
 			switch {
 			case result.ShutdownRequested:
 				return
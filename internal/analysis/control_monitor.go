@@ -508,6 +508,7 @@ func (cm *ControlMonitor) handleReconfigureMQTT() {
 		// Register Home Assistant discovery handler before connecting
 		// so the OnConnect handler fires on the initial connection
 		cm.proc.RegisterHomeAssistantDiscovery(newClient, settings)
+		cm.proc.RegisterMQTTCommands(newClient, settings)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		if err := newClient.Connect(ctx); err != nil {
@@ -21,10 +21,10 @@ import (
 
 // migrationSetupConfig holds configuration for migration infrastructure setup.
 type migrationSetupConfig struct {
-	manager     datastoreV2.Manager // Satisfies both SQLite and MySQL managers
+	manager     datastoreV2.Manager // Satisfies the SQLite, MySQL and PostgreSQL managers
 	ds          datastore.Interface
 	log         logger.Logger
-	useV2Prefix bool   // true for MySQL migration (v2_ prefix), false for SQLite (separate file)
+	useV2Prefix bool   // true for MySQL/PostgreSQL migration (v2_ prefix), false for SQLite (separate file)
 	opName      string // For log messages: "initialize_migration_infrastructure" or "initialize_mysql_migration"
 }
 
@@ -34,6 +34,9 @@ type migrationSetupConfig struct {
 func setupMigrationWorker(cfg *migrationSetupConfig) error {
 	// Get dialect from manager interface (avoids redundant parameter)
 	isMySQL := cfg.manager.IsMySQL()
+	isPostgres := cfg.manager.IsPostgres()
+	// Server databases share the larger batch and batch-insert settings
+	isServerDB := isMySQL || isPostgres
 
 	// Create the state manager
 	stateManager := datastoreV2.NewStateManager(cfg.manager.DB())
@@ -92,10 +95,10 @@ func setupMigrationWorker(cfg *migrationSetupConfig) error {
 	legacyRepo := datastore.NewDetectionRepository(cfg.ds, time.Local)
 
 	// Determine batch size and sleep duration based on database type
-	// MySQL handles larger batches and concurrent access better than SQLite
+	// MySQL and PostgreSQL handle larger batches and concurrent access better than SQLite
 	batchSize := migration.DefaultBatchSize
 	sleepBetweenBatches := migration.DefaultSleepBetweenBatches
-	if isMySQL {
+	if isServerDB {
 		batchSize = migration.MySQLBatchSize
 		sleepBetweenBatches = migration.MySQLSleepBetweenBatches
 	}
@@ -134,8 +137,11 @@ func setupMigrationWorker(cfg *migrationSetupConfig) error {
 
 	// Determine database type for telemetry
 	dbType := "sqlite"
-	if isMySQL {
+	switch {
+	case isMySQL:
 		dbType = "mysql"
+	case isPostgres:
+		dbType = "postgres"
 	}
 	migrationTelemetry := migration.NewMigrationTelemetry(dbType)
 
@@ -153,7 +159,7 @@ func setupMigrationWorker(cfg *migrationSetupConfig) error {
 		BatchSize:           batchSize,
 		SleepBetweenBatches: sleepBetweenBatches,
 		Timezone:            time.Local,
-		UseBatchMode:        isMySQL, // Use efficient batch inserts for MySQL
+		UseBatchMode:        isServerDB, // Use efficient batch inserts for MySQL and PostgreSQL
 		SpeciesLabelTypeID:  speciesLabelType.ID,
 		AvesClassID:         &avesClass.ID,
 		Telemetry:           migrationTelemetry,
@@ -223,6 +229,10 @@ func initializeMigrationInfrastructure(settings *conf.Settings, ds datastore.Int
 		// MySQL uses v2_ prefixed tables in the same database
 		mgr, err := initializeMySQLMigrationInfrastructure(settings, ds, log)
 		return mgr, err
+	case settings.Output.Postgres.Enabled:
+		// PostgreSQL shares the database the same way as MySQL
+		mgr, err := initializePostgresMigrationInfrastructure(settings, ds, log)
+		return mgr, err
 	default:
 		log.Debug("no database configured, skipping migration infrastructure",
 			logger.String("operation", "initialize_migration_infrastructure"))
@@ -402,6 +412,55 @@ func initializeMySQLMigrationInfrastructure(settings *conf.Settings, ds datastor
 	return v2Manager, nil
 }
 
+// initializePostgresMigrationInfrastructure sets up migration infrastructure for
+// PostgreSQL. Like MySQL, the v2 tables live in the legacy database under the
+// v2_ prefix.
+func initializePostgresMigrationInfrastructure(settings *conf.Settings, ds datastore.Interface, log logger.Logger) (datastoreV2.Manager, error) {
+	v2Manager, err := datastoreV2.NewPostgresManager(&datastoreV2.PostgresConfig{
+		PostgresConnection: datastore.PostgresConnectionFromSettings(settings),
+		UseV2Prefix:        true, // v2_ prefix avoids collisions with legacy auxiliary tables
+		Debug:              settings.Debug,
+		Logger:             log,
+	})
+	if err != nil {
+		return nil, errors.New(err).
+			Component("analysis").
+			Category(errors.CategoryDatabase).
+			Context("operation", "create_postgres_v2_manager").
+			Build()
+	}
+
+	if err := v2Manager.Initialize(); err != nil {
+		if closeErr := v2Manager.Close(); closeErr != nil {
+			log.Warn("failed to close PostgreSQL v2 manager after initialization failure",
+				logger.Error(closeErr),
+				logger.String("operation", "initialize_postgres_migration"))
+		}
+		return nil, errors.New(err).
+			Component("analysis").
+			Category(errors.CategoryDatabase).
+			Context("operation", "initialize_postgres_v2_schema").
+			Build()
+	}
+
+	if err := setupMigrationWorker(&migrationSetupConfig{
+		manager:     v2Manager,
+		ds:          ds,
+		log:         log,
+		useV2Prefix: true,
+		opName:      "initialize_postgres_migration",
+	}); err != nil {
+		if closeErr := v2Manager.Close(); closeErr != nil {
+			log.Warn("failed to close PostgreSQL v2 manager after worker setup failure",
+				logger.Error(closeErr),
+				logger.String("operation", "initialize_postgres_migration"))
+		}
+		return nil, err
+	}
+
+	return v2Manager, nil
+}
+
 // initializeV2OnlyMode creates a V2OnlyDatastore when migration is complete.
 // This allows the application to run without opening the legacy database.
 // It handles both:
@@ -465,6 +524,21 @@ func initializeV2OnlyMode(settings *conf.Settings) (*v2only.Datastore, error) {
 			Logger:      log,
 		})
 
+	case settings.Output.Postgres.Enabled:
+		isFreshV2 := datastoreV2.CheckPostgresHasFreshV2Schema(settings)
+		useV2Prefix = !isFreshV2
+
+		log.Debug("PostgreSQL v2 mode configuration",
+			logger.Bool("use_v2_prefix", useV2Prefix),
+			logger.Bool("is_fresh_v2", isFreshV2))
+
+		v2Manager, err = datastoreV2.NewPostgresManager(&datastoreV2.PostgresConfig{
+			PostgresConnection: datastore.PostgresConnectionFromSettings(settings),
+			UseV2Prefix:        useV2Prefix,
+			Debug:              settings.Debug,
+			Logger:             log,
+		})
+
 	default:
 		return nil, errors.Newf("no database configured").
 			Component("analysis").
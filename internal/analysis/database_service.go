@@ -11,6 +11,7 @@ import (
 	"github.com/getsentry/sentry-go"
 	importsapi "github.com/tphakala/birdnet-go/internal/api/v2/imports"
 	"github.com/tphakala/birdnet-go/internal/app"
+	"github.com/tphakala/birdnet-go/internal/backup"
 	"github.com/tphakala/birdnet-go/internal/classifier"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
@@ -118,6 +119,16 @@ func (d *DatabaseService) Start(_ context.Context) error {
 		}
 	}()
 
+	// Swap in a database staged by a restore from backup before anything
+	// opens it. A failed swap leaves the current database in place.
+	if settings.Output.SQLite.Enabled {
+		if _, err := backup.ApplyPendingRestore(settings.Output.SQLite.Path); err != nil {
+			GetLogger().Error("failed to apply pending database restore",
+				logger.Error(err),
+				logger.String("operation", "apply_pending_restore"))
+		}
+	}
+
 	// Check for unmigrated legacy records from a potential hard crash during tail sync.
 	// Must run BEFORE consolidation to prevent renaming the legacy DB while it still
 	// has unmigrated records that the worker needs to sync.
@@ -248,11 +259,11 @@ func (d *DatabaseService) Start(_ context.Context) error {
 			importsapi.SetV2OnlyMode()
 			// Set the v2 database manager
 			d.v2Manager = v2OnlyDatastore.Manager()
-			// Journal the fresh-database creation. A MySQL fresh install has
-			// no file path; journal an empty path rather than the unused
-			// sqlite setting.
+			// Journal the fresh-database creation. A MySQL or PostgreSQL
+			// fresh install has no file path; journal an empty path rather
+			// than the unused sqlite setting.
 			freshDBPath := ""
-			if !settings.Output.MySQL.Enabled {
+			if !settings.Output.MySQL.Enabled && !settings.Output.Postgres.Enabled {
 				freshDBPath = settings.Output.SQLite.Path
 			}
 			diagnostics.RecordFreshDB(diagnostics.Default(),
@@ -359,6 +370,10 @@ func bootParamsFromStartup(settings *conf.Settings, st datastoreV2.StartupState)
 		p.Dialect = datastore.DialectMySQL
 		return p
 	}
+	if settings.Output.Postgres.Enabled {
+		p.Dialect = datastore.DialectPostgres
+		return p
+	}
 	p.Dialect = datastore.DialectSQLite
 	p.ConfiguredDBPath = settings.Output.SQLite.Path
 	p.V2SidecarPath = datastoreV2.V2MigrationPathFromConfigured(settings.Output.SQLite.Path)
@@ -603,15 +618,18 @@ func (d *DatabaseService) closeV2Database(log logger.Logger) {
 
 	// Determine database type for logging.
 	dbType := "SQLite"
-	if manager.IsMySQL() {
+	switch {
+	case manager.IsMySQL():
 		dbType = "MySQL"
+	case manager.IsPostgres():
+		dbType = "PostgreSQL"
 	}
 
 	// Stash path before close (may not be available after).
 	dbPath := manager.Path()
 
-	// Perform WAL checkpoint before closing (SQLite only, no-op for MySQL).
-	if !manager.IsMySQL() {
+	// Perform WAL checkpoint before closing (SQLite only, no-op for MySQL and PostgreSQL).
+	if !manager.IsMySQL() && !manager.IsPostgres() {
 		log.Info("performing v2 SQLite WAL checkpoint",
 			logger.String("operation", "v2_wal_checkpoint_before_shutdown"))
 
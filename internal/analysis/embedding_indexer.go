@@ -0,0 +1,33 @@
+// embedding_indexer.go - clip embeddings for the similar recordings search.
+package analysis
+
+import (
+	"context"
+
+	"github.com/tphakala/birdnet-go/internal/classifier"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/similarity"
+)
+
+// embeddingThreads is the ONNX Runtime thread count of the clip embeddings
+// model. One thread keeps indexing from competing with live inference; a
+// clip takes well under a second either way.
+const embeddingThreads = 1
+
+// newEmbeddingIndexer creates the indexer that stores an embedding for each
+// saved clip. The model path is resolved through the orchestrator so the
+// installed bat embeddings model is used when none is configured.
+func newEmbeddingIndexer(bn *classifier.Orchestrator, store similarity.Store) *similarity.Indexer {
+	return similarity.NewIndexer(context.Background(), similarity.Config{
+		Store:     store,
+		ModelPath: bn.EmbeddingModelPath,
+		Load: func(settings *conf.Settings, modelPath string) (similarity.Embedder, error) {
+			return classifier.NewEmbedder(&classifier.EmbedderConfig{
+				ModelPath:       modelPath,
+				Labels:          settings.BirdNET.Labels,
+				ONNXRuntimePath: settings.BirdNET.ONNXRuntimePath,
+				Threads:         embeddingThreads,
+			})
+		},
+	})
+}
@@ -0,0 +1,100 @@
+// file.go wires offline file and directory analysis for the CLI commands.
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/analysis/fileanalysis"
+	"github.com/tphakala/birdnet-go/internal/classifier"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// FileAnalysis classifies the single audio file at settings.Input.Path and
+// writes the results to the configured outputs.
+func FileAnalysis(ctx context.Context, settings *conf.Settings, saveToDB bool) error {
+	return runOffline(ctx, settings, saveToDB, func(a *fileanalysis.Analyzer) (fileanalysis.Summary, error) {
+		res, err := a.AnalyzeFile(ctx, settings.Input.Path)
+		if err != nil {
+			return fileanalysis.Summary{Failed: 1}, err
+		}
+		return fileanalysis.Summary{Files: 1, Detections: len(res.Detections)}, nil
+	})
+}
+
+// DirectoryAnalysis classifies every audio file in the directory at
+// settings.Input.Path. With settings.Input.Watch it keeps running and analyses
+// new recordings as they appear until ctx is cancelled.
+func DirectoryAnalysis(ctx context.Context, settings *conf.Settings, saveToDB bool) error {
+	return runOffline(ctx, settings, saveToDB, func(a *fileanalysis.Analyzer) (fileanalysis.Summary, error) {
+		if settings.Input.Watch {
+			return a.Watch(ctx, settings.Input.Path, settings.Input.Recursive)
+		}
+		return a.AnalyzeDirectory(ctx, settings.Input.Path, settings.Input.Recursive)
+	})
+}
+
+// runOffline loads the classifier and the requested outputs, then runs fn.
+func runOffline(ctx context.Context, settings *conf.Settings, saveToDB bool,
+	fn func(*fileanalysis.Analyzer) (fileanalysis.Summary, error)) error {
+	ApplyMemoryPolicy(settings)
+
+	var sinks []fileanalysis.Sink
+	if settings.Output.File.Enabled {
+		sink, err := fileanalysis.NewTableSink(settings.Output.File.Path, settings.Output.File.Type)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+	if saveToDB {
+		metrics, err := InitializeMetrics()
+		if err != nil {
+			return err
+		}
+		dbService := NewDatabaseService(settings, metrics)
+		if err := dbService.Start(ctx); err != nil {
+			return err
+		}
+		defer func() { _ = dbService.Stop(context.WithoutCancel(ctx)) }()
+		sinks = append(sinks, fileanalysis.NewDatastoreSink(datastore.NewDetectionRepository(dbService.DataStore(), time.Local)))
+	}
+	if len(sinks) == 0 {
+		return errors.Newf("no output selected: enable file output or the datastore").
+			Component("analysis.file").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "offline_analysis").
+			Build()
+	}
+
+	bn, err := classifier.NewOrchestrator(settings)
+	if err != nil {
+		return fmt.Errorf("failed to initialize classifier: %w", err)
+	}
+	defer bn.Delete()
+
+	modelID := bn.ModelInfo.ID
+	spec, ok := bn.ModelSpecFor(modelID)
+	if !ok {
+		return errors.Newf("model %s is not loaded", modelID).
+			Component("analysis.file").
+			Category(errors.CategoryModelInit).
+			Context("operation", "offline_analysis").
+			Build()
+	}
+
+	sum, err := fn(fileanalysis.New(settings, bn, modelID, spec, sinks...))
+	GetLogger().Info("offline analysis finished",
+		logger.String("path", settings.Input.Path),
+		logger.Int("files", sum.Files),
+		logger.Int("failed", sum.Failed),
+		logger.Int("detections", sum.Detections))
+	if ctx.Err() != nil {
+		return ErrAnalysisCanceled
+	}
+	return err
+}
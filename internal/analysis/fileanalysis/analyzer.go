@@ -0,0 +1,357 @@
+// Package fileanalysis runs the classifier over recorded audio files, the
+// offline counterpart of the realtime pipeline. It backs the `file` and
+// `directory` CLI commands used to process SD cards from unattended field
+// recorders.
+//
+// Files are decoded in streaming fashion (see audiocore/audiofile), split into
+// model-sized windows and classified one window at a time. Detections for a
+// file are handed to one or more Sinks once the file has been analysed
+// completely, so a failed file never leaves partial output behind.
+package fileanalysis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/audiocore/audiofile"
+	"github.com/tphakala/birdnet-go/internal/classifier"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/detection"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/labels/nonbird"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/openfauna"
+)
+
+const componentName = "fileanalysis"
+
+// Classifier is the subset of classifier.Orchestrator used for file analysis.
+type Classifier interface {
+	Predict(ctx context.Context, sample [][]float32) ([]datastore.Results, error)
+	EnrichResultWithTaxonomy(speciesLabel string) (scientific, common, code string)
+}
+
+// RangeFilter is implemented by classifiers that can predict the species
+// expected at the configured location on a given date. classifier.Orchestrator
+// implements it; when the classifier does not, no range filtering is applied.
+type RangeFilter interface {
+	GetProbableSpeciesWithSettings(date time.Time, week float32, settings *conf.Settings) ([]classifier.SpeciesScore, error)
+}
+
+// Detection is a single species detection within an analysed file.
+type Detection struct {
+	Result detection.Result
+	Offset time.Duration // start of the analysis window within the file
+	Length time.Duration // length of the analysis window
+}
+
+// FileResult holds the outcome of analysing one file.
+type FileResult struct {
+	Path       string
+	RelPath    string // path relative to the analysed directory; empty for single files
+	Info       audiofile.Info
+	Start      time.Time // wall clock time of the first sample
+	Detections []Detection
+}
+
+// Sink receives the detections of each completely analysed file.
+type Sink interface {
+	WriteFile(ctx context.Context, res *FileResult) error
+}
+
+// Analyzer classifies audio files with a single model.
+type Analyzer struct {
+	settings *conf.Settings
+	bn       Classifier
+	modelID  string
+	spec     classifier.ModelSpec
+	sinks    []Sink
+	log      logger.Logger
+
+	// included caches the range filter result per recording day, keyed by
+	// date, as a set of canonical lowercased scientific names.
+	included map[string]map[string]struct{}
+}
+
+// New creates an Analyzer for the model identified by modelID. spec supplies
+// the sample rate and window length the model expects.
+func New(settings *conf.Settings, bn Classifier, modelID string, spec classifier.ModelSpec, sinks ...Sink) *Analyzer {
+	return &Analyzer{
+		settings: settings,
+		bn:       bn,
+		modelID:  modelID,
+		spec:     spec,
+		sinks:    sinks,
+		log:      logger.Global().Module(componentName),
+	}
+}
+
+// AnalyzeFile classifies the audio file at path and passes the result to every sink.
+func (a *Analyzer) AnalyzeFile(ctx context.Context, path string) (*FileResult, error) {
+	return a.analyzeFile(ctx, path, "")
+}
+
+// analyzeFile analyses one file. root is the directory being analysed, used to
+// record the file's relative path; it is empty for single files.
+func (a *Analyzer) analyzeFile(ctx context.Context, path, root string) (*FileResult, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, errors.New(err).
+			Component(componentName).
+			Category(errors.CategoryFileIO).
+			Context("operation", "resolve_input_path").
+			Build()
+	}
+
+	info, err := audiofile.Probe(absPath)
+	if err != nil {
+		return nil, err
+	}
+	res := &FileResult{
+		Path:  absPath,
+		Info:  info,
+		Start: recordingStart(absPath, info.Duration()),
+	}
+	if root != "" {
+		if rel, err := filepath.Rel(root, path); err == nil {
+			res.RelPath = rel
+		}
+	}
+	source := fileAudioSource(absPath, a.spec.SampleRate)
+	model := classifier.DetectionModelInfoForID(a.modelID)
+	included, err := a.includedSpecies(res.Start, model)
+	if err != nil {
+		return nil, err
+	}
+
+	a.log.Info("analyzing file",
+		logger.String("path", absPath),
+		logger.String("format", info.Format),
+		logger.Int("sample_rate", info.SampleRate),
+		logger.Int("channels", info.Channels),
+		logger.Duration("duration", info.Duration()))
+
+	opts := audiofile.ChunkOptions{
+		SampleRate: a.spec.SampleRate,
+		ClipLength: a.spec.ClipLength,
+		Overlap:    a.overlap(),
+	}
+	started := time.Now()
+	_, err = audiofile.ReadChunks(ctx, absPath, opts, func(c audiofile.Chunk) error {
+		predictStart := time.Now()
+		results, err := a.bn.Predict(ctx, [][]float32{c.Samples})
+		if err != nil {
+			return err
+		}
+		elapsed := time.Since(predictStart)
+		begin := res.Start.Add(c.Offset)
+		for i := range results {
+			d, ok := a.toDetection(&results[i], begin, source, model, included, elapsed)
+			if !ok {
+				continue
+			}
+			res.Detections = append(res.Detections, Detection{
+				Result: d,
+				Offset: c.Offset,
+				Length: a.spec.ClipLength,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.log.Info("file analysis complete",
+		logger.String("path", absPath),
+		logger.Int("detections", len(res.Detections)),
+		logger.Duration("elapsed", time.Since(started)))
+
+	for _, sink := range a.sinks {
+		if err := sink.WriteFile(ctx, res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// overlap returns the configured window overlap, clamped so that consecutive
+// windows always advance by at least 100 ms for the active model.
+func (a *Analyzer) overlap() time.Duration {
+	ov := time.Duration(a.settings.BirdNET.Overlap * float64(time.Second))
+	if ov <= 0 {
+		return 0
+	}
+	if limit := a.spec.ClipLength - 100*time.Millisecond; ov > limit {
+		return max(limit, 0)
+	}
+	return ov
+}
+
+// toDetection applies the filters of the realtime processor to a single
+// prediction: the human voice privacy filter, the exclude list, the species or
+// global confidence threshold and the range filter, which also carries the
+// include list. included is nil when no range filter applies.
+func (a *Analyzer) toDetection(r *datastore.Results, begin time.Time, source detection.AudioSource,
+	model detection.ModelInfo, included map[string]struct{}, elapsed time.Duration) (detection.Result, bool) {
+	if isHumanVoice(r.Species) {
+		return detection.Result{}, false
+	}
+
+	scientific, common, code := a.bn.EnrichResultWithTaxonomy(r.Species)
+	if scientific == "" {
+		return detection.Result{}, false
+	}
+	if common == "" {
+		common = scientific
+	}
+	if a.isExcluded(scientific, common) {
+		return detection.Result{}, false
+	}
+
+	confidence := float64(r.Confidence)
+	threshold := a.threshold(scientific, common)
+	if confidence <= threshold {
+		return detection.Result{}, false
+	}
+	if included != nil {
+		if _, ok := included[canonicalScientific(scientific)]; !ok {
+			return detection.Result{}, false
+		}
+	}
+
+	return detection.Result{
+		Timestamp:   begin,
+		SourceNode:  a.settings.Main.Name,
+		AudioSource: source,
+		BeginTime:   begin,
+		EndTime:     begin.Add(a.spec.ClipLength),
+		Species: detection.Species{
+			ScientificName: scientific,
+			CommonName:     common,
+			Code:           code,
+		},
+		Confidence:     confidence,
+		Latitude:       a.settings.BirdNET.Latitude,
+		Longitude:      a.settings.BirdNET.Longitude,
+		Threshold:      threshold,
+		Sensitivity:    a.settings.BirdNET.Sensitivity,
+		ProcessingTime: elapsed,
+		Model:          model,
+		RawLabel:       r.Species,
+	}, true
+}
+
+// threshold returns the species specific threshold, falling back to the global one.
+func (a *Analyzer) threshold(scientific, common string) float64 {
+	cfg := a.settings.Realtime.Species.Config
+	for _, key := range []string{strings.ToLower(common), strings.ToLower(scientific)} {
+		if sc, ok := cfg[key]; ok && sc.Threshold > 0 {
+			return sc.Threshold
+		}
+	}
+	return a.settings.BirdNET.Threshold
+}
+
+// isExcluded reports whether the species is on the configured exclude list.
+// Scientific names are compared in canonical form, so an entry keyed on a
+// reclassified name still matches.
+func (a *Analyzer) isExcluded(scientific, common string) bool {
+	canonical := openfauna.CanonicalName(scientific)
+	for _, name := range a.settings.Realtime.Species.Exclude {
+		if strings.EqualFold(name, common) || strings.EqualFold(openfauna.CanonicalName(name), canonical) {
+			return true
+		}
+	}
+	return false
+}
+
+// includedSpecies returns the species the range filter expects on the day of
+// start, or nil when the range filter does not apply: the location is not
+// configured, the model is not filtered in realtime either, or the classifier
+// has no range filter. Results are cached per day because recorder cards hold
+// many recordings from the same few days.
+func (a *Analyzer) includedSpecies(start time.Time, model detection.ModelInfo) (map[string]struct{}, error) {
+	rf, ok := a.bn.(RangeFilter)
+	if !ok || !a.settings.BirdNET.LocationConfigured {
+		return nil, nil
+	}
+	if model.Name != detection.DefaultModelName && model.Name != classifier.DetectionNamePerch {
+		return nil, nil
+	}
+
+	day := start.Format(time.DateOnly)
+	if set, ok := a.included[day]; ok {
+		return set, nil
+	}
+	scores, err := rf.GetProbableSpeciesWithSettings(conf.LocalNoon(start), 0, a.settings)
+	if err != nil {
+		return nil, errors.New(err).
+			Component(componentName).
+			Category(errors.CategoryModelInit).
+			Context("operation", "range_filter").
+			Context("date", day).
+			Build()
+	}
+	set := make(map[string]struct{}, len(scores))
+	for _, s := range scores {
+		set[canonicalScientific(s.Label)] = struct{}{}
+	}
+	if a.included == nil {
+		a.included = make(map[string]map[string]struct{})
+	}
+	a.included[day] = set
+	return set, nil
+}
+
+// canonicalScientific returns the lowercased canonical scientific name of a
+// label in "Scientific name_Common name" or scientific-name-only form.
+func canonicalScientific(label string) string {
+	sci, _, _ := strings.Cut(strings.TrimSpace(label), "_")
+	return strings.ToLower(openfauna.CanonicalName(sci))
+}
+
+// isHumanVoice reports whether a raw label is a human vocalization, which the
+// realtime processor discards for privacy. It mirrors the processor's check:
+// the shared nonbird human classes, the Homo sapiens taxon and BirdNET's
+// locale-independent "Human ..." labels.
+func isHumanVoice(rawLabel string) bool {
+	if cat, ok := nonbird.CategoryOf(rawLabel); ok && cat == nonbird.CategoryHuman {
+		return true
+	}
+	lowered := strings.ToLower(rawLabel)
+	return lowered == "homo sapiens" || strings.HasPrefix(lowered, "human ")
+}
+
+// fileAudioSource describes a recording as a file audio source. Recordings in
+// the same directory share one source, which normally corresponds to a single
+// recorder deployment.
+func fileAudioSource(absPath string, sampleRate int) detection.AudioSource {
+	dir := filepath.Dir(absPath)
+	return detection.NewAudioSourceWithDetails(dir, detection.DetermineSourceType(dir), filepath.Base(dir), dir, sampleRate)
+}
+
+// timestampPattern matches the YYYYMMDD_HHMMSS stamp used in file names by
+// AudioMoth, Song Meter and most other autonomous recorders.
+var timestampPattern = regexp.MustCompile(`(\d{8})[_-](\d{6})`)
+
+// recordingStart determines the wall clock time of the first sample. The
+// timestamp embedded in the file name is preferred because copying files off
+// an SD card resets their modification time; otherwise the end of recording
+// is assumed to be the modification time.
+func recordingStart(path string, duration time.Duration) time.Time {
+	if m := timestampPattern.FindStringSubmatch(filepath.Base(path)); m != nil {
+		if t, err := time.ParseInLocation("20060102150405", m[1]+m[2], time.Local); err == nil {
+			return t
+		}
+	}
+	if fi, err := os.Stat(path); err == nil {
+		return fi.ModTime().Add(-duration)
+	}
+	return time.Now().Add(-duration)
+}
@@ -0,0 +1,350 @@
+package fileanalysis
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/classifier"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/detection"
+)
+
+const testRate = 48000
+
+var testSpec = classifier.ModelSpec{SampleRate: testRate, ClipLength: 3 * time.Second}
+
+// fakeClassifier returns the same predictions for every window.
+type fakeClassifier struct {
+	mu      sync.Mutex
+	calls   int
+	results []datastore.Results
+}
+
+func (f *fakeClassifier) Predict(_ context.Context, sample [][]float32) ([]datastore.Results, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if len(sample) != 1 || len(sample[0]) != testRate*3 {
+		return nil, assert.AnError
+	}
+	return f.results, nil
+}
+
+func (f *fakeClassifier) EnrichResultWithTaxonomy(label string) (scientific, common, code string) {
+	sci, com, _ := strings.Cut(label, "_")
+	return sci, com, strings.ToLower(strings.ReplaceAll(com, " ", ""))[:min(6, len(com))]
+}
+
+type fakeSaver struct {
+	mu    sync.Mutex
+	saved []detection.Result
+}
+
+func (f *fakeSaver) Save(_ context.Context, result *detection.Result, _ []detection.AdditionalResult) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result.ID = uint(len(f.saved) + 1) //nolint:gosec // test counter
+	f.saved = append(f.saved, *result)
+	return nil
+}
+
+// searchingSaver is a fakeSaver that also implements DetectionSearcher. Stored
+// start times are truncated to the second like a MySQL DATETIME column.
+type searchingSaver struct {
+	fakeSaver
+}
+
+func (f *searchingSaver) Search(_ context.Context, filters *datastore.DetectionFilters) ([]*detection.Result, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []*detection.Result
+	for i := range f.saved {
+		r := f.saved[i]
+		r.BeginTime = r.BeginTime.Truncate(time.Second)
+		if slices.Contains(filters.Species, r.Species.ScientificName) && slices.Contains(filters.Location, r.SourceNode) {
+			out = append(out, &r)
+		}
+	}
+	end := min(filters.Offset+filters.Limit, len(out))
+	if filters.Offset >= len(out) {
+		return nil, int64(len(out)), nil
+	}
+	return out[filters.Offset:end], int64(len(out)), nil
+}
+
+// rangeClassifier is a fakeClassifier with a range filter that only expects
+// the robin.
+type rangeClassifier struct {
+	*fakeClassifier
+	days []time.Time
+}
+
+func (f *rangeClassifier) GetProbableSpeciesWithSettings(date time.Time, _ float32, _ *conf.Settings) ([]classifier.SpeciesScore, error) {
+	f.days = append(f.days, date)
+	return []classifier.SpeciesScore{{Label: "Erithacus rubecula_European Robin", Score: 0.9}}, nil
+}
+
+func testSettings() *conf.Settings {
+	s := &conf.Settings{}
+	s.Main.Name = "field-node"
+	s.BirdNET.Threshold = 0.5
+	s.BirdNET.Sensitivity = 1.0
+	s.BirdNET.Latitude = 60.1
+	s.BirdNET.Longitude = 24.9
+	s.Realtime.Species.Config = map[string]conf.SpeciesConfig{
+		"eurasian blackbird": {Threshold: 0.95},
+	}
+	s.Realtime.Species.Exclude = []string{"Pica pica"}
+	return s
+}
+
+func newFake() *fakeClassifier {
+	return &fakeClassifier{results: []datastore.Results{
+		{Species: "Erithacus rubecula_European Robin", Confidence: 0.8},
+		{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.9}, // below species threshold
+		{Species: "Pica pica_Eurasian Magpie", Confidence: 0.99},       // excluded
+		{Species: "Parus major_Great Tit", Confidence: 0.3},            // below global threshold
+	}}
+}
+
+// writeSilentWAV writes a mono 16-bit WAV file of the given length.
+func writeSilentWAV(t *testing.T, path string, d time.Duration) {
+	t.Helper()
+	payload := make([]byte, int(d.Seconds()*testRate)*2)
+	hdr := make([]byte, 0, 44)
+	hdr = append(hdr, "RIFF"...)
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(36+len(payload))) //nolint:gosec // test data is small
+	hdr = append(hdr, "WAVEfmt "...)
+	hdr = binary.LittleEndian.AppendUint32(hdr, 16)
+	hdr = binary.LittleEndian.AppendUint16(hdr, 1)
+	hdr = binary.LittleEndian.AppendUint16(hdr, 1)
+	hdr = binary.LittleEndian.AppendUint32(hdr, testRate)
+	hdr = binary.LittleEndian.AppendUint32(hdr, testRate*2)
+	hdr = binary.LittleEndian.AppendUint16(hdr, 2)
+	hdr = binary.LittleEndian.AppendUint16(hdr, 16)
+	hdr = append(hdr, "data"...)
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(len(payload))) //nolint:gosec // test data is small
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, append(hdr, payload...), 0o600))
+}
+
+func TestAnalyzeFile_FiltersAndTiming(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "SMA01234_20240501_053000.wav")
+	writeSilentWAV(t, path, 6*time.Second)
+
+	saver := &fakeSaver{}
+	bn := newFake()
+	a := New(testSettings(), bn, classifier.BirdNET_V2_4, testSpec, NewDatastoreSink(saver))
+	res, err := a.AnalyzeFile(t.Context(), path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, bn.calls)
+	require.Len(t, res.Detections, 2, "only the robin passes filters, once per window")
+	wantStart := time.Date(2024, 5, 1, 5, 30, 0, 0, time.Local)
+	assert.Equal(t, wantStart, res.Start)
+
+	second := res.Detections[1]
+	assert.Equal(t, 3*time.Second, second.Offset)
+	r := second.Result
+	assert.Equal(t, "Erithacus rubecula", r.Species.ScientificName)
+	assert.Equal(t, "European Robin", r.Species.CommonName)
+	assert.Equal(t, wantStart.Add(3*time.Second), r.BeginTime)
+	assert.Equal(t, wantStart.Add(6*time.Second), r.EndTime)
+	assert.Equal(t, r.BeginTime, r.Timestamp)
+	assert.Equal(t, "field-node", r.SourceNode)
+	assert.Equal(t, "file", r.AudioSource.Type)
+	assert.Equal(t, dir, r.AudioSource.SafeString)
+	assert.InDelta(t, 60.1, r.Latitude, 1e-9)
+
+	require.Len(t, saver.saved, 2)
+	assert.Equal(t, uint(2), saver.saved[1].ID)
+}
+
+func TestAnalyzeFile_RealtimeFilters(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "20240501_053000.wav")
+	writeSilentWAV(t, path, 6*time.Second)
+
+	settings := testSettings()
+	settings.BirdNET.LocationConfigured = true
+	settings.Realtime.Species.Config = map[string]conf.SpeciesConfig{
+		"european robin": {Threshold: 0.7},
+	}
+	bn := &rangeClassifier{fakeClassifier: &fakeClassifier{results: []datastore.Results{
+		{Species: "Erithacus rubecula_European Robin", Confidence: 0.8},
+		{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.9}, // not expected by the range filter
+		{Species: "Human vocal_Human vocal", Confidence: 0.99},         // privacy filter
+		{Species: "Parus major_Great Tit", Confidence: 0.5},            // not above the global threshold
+	}}}
+	a := New(settings, bn, classifier.BirdNET_V2_4, testSpec)
+	res, err := a.AnalyzeFile(t.Context(), path)
+	require.NoError(t, err)
+
+	require.Len(t, res.Detections, 2)
+	for _, d := range res.Detections {
+		assert.Equal(t, "Erithacus rubecula", d.Result.Species.ScientificName)
+		assert.InDelta(t, 0.7, d.Result.Threshold, 1e-9, "the species threshold that was applied is recorded")
+	}
+	require.Len(t, bn.days, 1, "the range filter runs once per recording day")
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local), bn.days[0])
+}
+
+func TestDatastoreSink_SkipsStoredDetections(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "20240501_053000.wav")
+	writeSilentWAV(t, path, 6*time.Second)
+
+	settings := testSettings()
+	settings.BirdNET.Overlap = 2.5 // windows start every 500 ms, two per second
+	saver := &searchingSaver{}
+	a := New(settings, newFake(), classifier.BirdNET_V2_4, testSpec, NewDatastoreSink(saver))
+
+	res, err := a.AnalyzeFile(t.Context(), path)
+	require.NoError(t, err)
+	require.Len(t, saver.saved, len(res.Detections))
+	stored := len(saver.saved)
+
+	_, err = a.AnalyzeFile(t.Context(), path)
+	require.NoError(t, err)
+	assert.Len(t, saver.saved, stored, "a second run must not import the same detections again")
+}
+
+func TestAnalyzeFile_StartFromModTime(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "recording.wav")
+	writeSilentWAV(t, path, 3*time.Second)
+	mod := time.Date(2024, 6, 1, 12, 0, 3, 0, time.Local)
+	require.NoError(t, os.Chtimes(path, mod, mod))
+
+	a := New(testSettings(), newFake(), classifier.BirdNET_V2_4, testSpec)
+	res, err := a.AnalyzeFile(t.Context(), path)
+	require.NoError(t, err)
+	assert.Equal(t, mod.Add(-3*time.Second), res.Start, "recording ends at its modification time")
+}
+
+func TestTableSink(t *testing.T) {
+	t.Parallel()
+	in := filepath.Join(t.TempDir(), "20240501_053000.flac")
+	out := t.TempDir()
+	res := &FileResult{
+		Path: in,
+		Detections: []Detection{{
+			Result: detection.Result{
+				Species:    detection.Species{ScientificName: "Erithacus rubecula", CommonName: "European Robin", Code: "eurrob1"},
+				Confidence: 0.8,
+			},
+			Offset: 3 * time.Second,
+			Length: 3 * time.Second,
+		}},
+	}
+
+	table, err := NewTableSink(out, "")
+	require.NoError(t, err)
+	require.NoError(t, table.WriteFile(t.Context(), res))
+	data, err := os.ReadFile(filepath.Join(out, "20240501_053000.BirdNET.selection.table.txt"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "\t3.000\t6.000\t0.0\t15000.0\tEuropean Robin\teurrob1\t0.8000\t"+in)
+
+	csvSink, err := NewTableSink("", "CSV")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Dir(in), "20240501_053000.BirdNET.results.csv"), csvSink.OutputPath(res))
+	require.NoError(t, csvSink.WriteFile(t.Context(), res))
+	f, err := os.Open(csvSink.OutputPath(res))
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "Erithacus rubecula", records[1][2])
+
+	_, err = NewTableSink(out, "json")
+	require.Error(t, err)
+}
+
+func TestAnalyzeDirectory(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeSilentWAV(t, filepath.Join(root, "a.wav"), 3*time.Second)
+	writeSilentWAV(t, filepath.Join(root, "nested", "b.wav"), 3*time.Second)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "broken.wav"), []byte("garbage"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "notes.txt"), []byte("ignored"), 0o600))
+
+	a := New(testSettings(), newFake(), classifier.BirdNET_V2_4, testSpec)
+
+	sum, err := a.AnalyzeDirectory(t.Context(), root, false)
+	require.NoError(t, err)
+	assert.Equal(t, Summary{Files: 1, Failed: 1, Detections: 1}, sum)
+
+	sum, err = a.AnalyzeDirectory(t.Context(), root, true)
+	require.NoError(t, err)
+	assert.Equal(t, Summary{Files: 2, Failed: 1, Detections: 2}, sum)
+}
+
+func TestAnalyzeDirectory_OutputMirrorsSubdirectories(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeSilentWAV(t, filepath.Join(root, "a", "rec.wav"), 3*time.Second)
+	writeSilentWAV(t, filepath.Join(root, "b", "rec.wav"), 3*time.Second)
+	out := t.TempDir()
+
+	sink, err := NewTableSink(out, OutputTable)
+	require.NoError(t, err)
+	a := New(testSettings(), newFake(), classifier.BirdNET_V2_4, testSpec, sink)
+	sum, err := a.AnalyzeDirectory(t.Context(), root, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sum.Files)
+
+	for _, sub := range []string{"a", "b"} {
+		data, err := os.ReadFile(filepath.Join(out, sub, "rec"+tableSuffix))
+		require.NoError(t, err, "each recording keeps its own table")
+		assert.Contains(t, string(data), filepath.Join(root, sub, "rec.wav"))
+	}
+}
+
+func TestWatch_PicksUpNewFiles(t *testing.T) { //nolint:paralleltest // modifies package-level pollInterval
+	orig := pollInterval
+	pollInterval = 20 * time.Millisecond
+	t.Cleanup(func() { pollInterval = orig })
+
+	root := t.TempDir()
+	writeSilentWAV(t, filepath.Join(root, "existing.wav"), 3*time.Second)
+
+	saver := &fakeSaver{}
+	a := New(testSettings(), newFake(), classifier.BirdNET_V2_4, testSpec, NewDatastoreSink(saver))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan Summary, 1)
+	go func() {
+		sum, _ := a.Watch(ctx, root, false)
+		done <- sum
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	writeSilentWAV(t, filepath.Join(root, "new.wav"), 3*time.Second)
+	time.Sleep(300 * time.Millisecond)
+	cancel()
+
+	select {
+	case sum := <-done:
+		assert.Equal(t, 2, sum.Files)
+		assert.Equal(t, 2, sum.Detections)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not stop after cancellation")
+	}
+}
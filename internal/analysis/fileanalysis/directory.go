@@ -0,0 +1,149 @@
+package fileanalysis
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/audiocore/audiofile"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// pollInterval is how often Watch rescans the directory. Polling is used
+// instead of filesystem notifications because SD card readers, network shares
+// and FUSE mounts frequently do not deliver them.
+var pollInterval = 5 * time.Second
+
+// Summary reports the outcome of a directory run.
+type Summary struct {
+	Files      int // files analysed successfully
+	Failed     int // files that could not be analysed
+	Detections int
+}
+
+// AnalyzeDirectory analyses every supported audio file in root, descending
+// into subdirectories when recursive is set. Files are processed in lexical
+// order, which for timestamped recorder output is chronological. A file that
+// fails to decode is logged and skipped so one corrupt recording does not stop
+// a whole card from being processed.
+func (a *Analyzer) AnalyzeDirectory(ctx context.Context, root string, recursive bool) (Summary, error) {
+	files, err := listAudioFiles(root, recursive)
+	if err != nil {
+		return Summary{}, err
+	}
+	a.log.Info("analyzing directory",
+		logger.String("path", root),
+		logger.Bool("recursive", recursive),
+		logger.Int("files", len(files)))
+
+	var sum Summary
+	for _, path := range files {
+		if err := ctx.Err(); err != nil {
+			return sum, err
+		}
+		a.analyzeInto(ctx, root, path, &sum)
+	}
+	return sum, ctx.Err()
+}
+
+// Watch analyses the existing files in root and then keeps polling for new
+// recordings until ctx is cancelled. A new file is only analysed once its size
+// has stayed the same for one poll interval, so files still being copied onto
+// disk are not picked up early.
+func (a *Analyzer) Watch(ctx context.Context, root string, recursive bool) (Summary, error) {
+	var sum Summary
+	done := make(map[string]struct{})
+	pending := make(map[string]int64) // path -> size seen on the previous scan
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	a.log.Info("watching directory for new recordings",
+		logger.String("path", root),
+		logger.Bool("recursive", recursive),
+		logger.Duration("poll_interval", pollInterval))
+
+	for first := true; ; first = false {
+		files, err := listAudioFiles(root, recursive)
+		if err != nil {
+			return sum, err
+		}
+		for _, path := range files {
+			if _, ok := done[path]; ok {
+				continue
+			}
+			fi, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			// Files present at startup are complete; later arrivals must be stable.
+			if prev, seen := pending[path]; !first && (!seen || prev != fi.Size()) {
+				pending[path] = fi.Size()
+				continue
+			}
+			delete(pending, path)
+			done[path] = struct{}{}
+			a.analyzeInto(ctx, root, path, &sum)
+			if ctx.Err() != nil {
+				return sum, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return sum, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// analyzeInto analyses one file below root and records the outcome in sum.
+func (a *Analyzer) analyzeInto(ctx context.Context, root, path string, sum *Summary) {
+	res, err := a.analyzeFile(ctx, path, root)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		sum.Failed++
+		a.log.Error("file analysis failed",
+			logger.String("path", path),
+			logger.Error(err))
+		return
+	}
+	sum.Files++
+	sum.Detections += len(res.Detections)
+}
+
+// listAudioFiles returns the supported audio files below root in lexical order.
+func listAudioFiles(root string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if audiofile.IsSupported(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New(err).
+			Component(componentName).
+			Category(errors.CategoryFileIO).
+			Context("operation", "list_audio_files").
+			Context("path", root).
+			Build()
+	}
+	slices.Sort(files)
+	return files, nil
+}
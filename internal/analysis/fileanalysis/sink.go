@@ -0,0 +1,252 @@
+package fileanalysis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/annotation"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/detection"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Output file types accepted by NewTableSink, matching Settings.Output.File.Type.
+const (
+	OutputTable = "table"
+	OutputCSV   = "csv"
+)
+
+// Output file suffixes follow BirdNET-Analyzer so existing tooling picks them up.
+const (
+	tableSuffix = ".BirdNET.selection.table.txt"
+	csvSuffix   = ".BirdNET.results.csv"
+)
+
+// TableSink writes one Raven selection table or CSV file per analysed recording.
+type TableSink struct {
+	dir    string
+	format string
+}
+
+// NewTableSink returns a sink writing files of the given type (OutputTable or
+// OutputCSV) into dir. When dir is empty, results are written next to each
+// recording.
+func NewTableSink(dir, format string) (*TableSink, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = OutputTable
+	}
+	if format != OutputTable && format != OutputCSV {
+		return nil, errors.Newf("unsupported output type %q: must be %q or %q", format, OutputTable, OutputCSV).
+			Component(componentName).
+			Category(errors.CategoryValidation).
+			Context("operation", "create_table_sink").
+			Build()
+	}
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, errors.New(err).
+				Component(componentName).
+				Category(errors.CategoryFileIO).
+				Context("operation", "create_output_dir").
+				Build()
+		}
+	}
+	return &TableSink{dir: dir, format: format}, nil
+}
+
+// OutputPath returns the result file written for an analysed recording. With
+// an output directory, recordings from a directory run keep their relative
+// subdirectory so same-named files from different folders do not overwrite
+// each other's results.
+func (s *TableSink) OutputPath(res *FileResult) string {
+	dir := s.dir
+	if dir == "" {
+		dir = filepath.Dir(res.Path)
+	} else if res.RelPath != "" {
+		dir = filepath.Join(dir, filepath.Dir(res.RelPath))
+	}
+	base := strings.TrimSuffix(filepath.Base(res.Path), filepath.Ext(res.Path))
+	if s.format == OutputCSV {
+		return filepath.Join(dir, base+csvSuffix)
+	}
+	return filepath.Join(dir, base+tableSuffix)
+}
+
+// WriteFile implements Sink. The file is written to a temporary name and
+// renamed into place so a watcher never sees a half written table.
+func (s *TableSink) WriteFile(_ context.Context, res *FileResult) error {
+	selections := make([]annotation.Selection, 0, len(res.Detections))
+	for i := range res.Detections {
+		d := &res.Detections[i]
+		selections = append(selections, annotation.Selection{
+			File:           res.Path,
+			Begin:          d.Offset,
+			End:            d.Offset + d.Length,
+			LowFreq:        annotation.DefaultLowFreq,
+			HighFreq:       annotation.DefaultHighFreq,
+			ScientificName: d.Result.Species.ScientificName,
+			CommonName:     d.Result.Species.CommonName,
+			SpeciesCode:    d.Result.Species.Code,
+			Confidence:     d.Result.Confidence,
+		})
+	}
+
+	out := s.OutputPath(res)
+	if err := os.MkdirAll(filepath.Dir(out), 0o750); err != nil {
+		return fileIOError(err, "create_output_dir", out)
+	}
+	tmp := out + ".tmp"
+	f, err := os.Create(tmp) //nolint:gosec // G304: output path derived from operator-supplied directory
+	if err != nil {
+		return fileIOError(err, "create_result_file", out)
+	}
+	if s.format == OutputCSV {
+		err = annotation.WriteCSV(f, selections)
+	} else {
+		err = annotation.WriteRavenTable(f, selections)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, out)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fileIOError(err, "write_result_file", out)
+	}
+	return nil
+}
+
+// DetectionSaver is the subset of datastore.DetectionRepository used by DatastoreSink.
+type DetectionSaver interface {
+	Save(ctx context.Context, result *detection.Result, additionalResults []detection.AdditionalResult) error
+}
+
+// DetectionSearcher is implemented by savers that can look up stored
+// detections. datastore.DetectionRepository implements it; DatastoreSink uses
+// it to skip detections saved by an earlier run over the same files.
+type DetectionSearcher interface {
+	Search(ctx context.Context, filters *datastore.DetectionFilters) ([]*detection.Result, int64, error)
+}
+
+// existingPageSize is the page size used when looking up stored detections.
+const existingPageSize = 1000
+
+// DatastoreSink saves detections to the configured database.
+type DatastoreSink struct {
+	repo DetectionSaver
+}
+
+// NewDatastoreSink returns a sink that persists detections through repo,
+// normally a datastore.DetectionRepository.
+func NewDatastoreSink(repo DetectionSaver) *DatastoreSink {
+	return &DatastoreSink{repo: repo}
+}
+
+// WriteFile implements Sink. Re-running a directory, or restarting a watch,
+// analyses the same files again; detections already stored for the same
+// station, species and start time are skipped so they are not imported twice.
+func (s *DatastoreSink) WriteFile(ctx context.Context, res *FileResult) error {
+	existing, err := s.existing(ctx, res)
+	if err != nil {
+		return errors.New(err).
+			Component(componentName).
+			Category(errors.CategoryDatabase).
+			Context("operation", "find_file_detections").
+			Context("file", res.Path).
+			Build()
+	}
+	for i := range res.Detections {
+		key := detectionKey(&res.Detections[i].Result)
+		if existing[key] > 0 {
+			existing[key]--
+			continue
+		}
+		if err := s.repo.Save(ctx, &res.Detections[i].Result, nil); err != nil {
+			return errors.New(err).
+				Component(componentName).
+				Category(errors.CategoryDatabase).
+				Context("operation", "save_file_detection").
+				Context("file", res.Path).
+				Build()
+		}
+	}
+	return nil
+}
+
+// existing counts the stored detections that coincide with the detections of
+// res, by detectionKey. Start times are compared to the second because not
+// every database keeps fractional seconds; the counts keep several windows
+// starting within the same second apart.
+func (s *DatastoreSink) existing(ctx context.Context, res *FileResult) (map[string]int, error) {
+	searcher, ok := s.repo.(DetectionSearcher)
+	if !ok || len(res.Detections) == 0 {
+		return nil, nil
+	}
+
+	first, last := res.Detections[0].Result.BeginTime, res.Detections[0].Result.BeginTime
+	var species []string
+	seen := make(map[string]struct{})
+	node := res.Detections[0].Result.SourceNode
+	for i := range res.Detections {
+		r := &res.Detections[i].Result
+		if r.BeginTime.Before(first) {
+			first = r.BeginTime
+		}
+		if r.BeginTime.After(last) {
+			last = r.BeginTime
+		}
+		if _, ok := seen[r.Species.ScientificName]; !ok {
+			seen[r.Species.ScientificName] = struct{}{}
+			species = append(species, r.Species.ScientificName)
+		}
+	}
+
+	filters := &datastore.DetectionFilters{
+		Species:       species,
+		StartDate:     first.Format(time.DateOnly),
+		EndDate:       last.Format(time.DateOnly),
+		Limit:         existingPageSize,
+		SortAscending: true,
+	}
+	if node != "" {
+		filters.Location = []string{node}
+	}
+
+	counts := make(map[string]int)
+	for {
+		found, _, err := searcher.Search(ctx, filters)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range found {
+			if !r.BeginTime.Before(first.Truncate(time.Second)) && !r.BeginTime.After(last) {
+				counts[detectionKey(r)]++
+			}
+		}
+		if len(found) < existingPageSize {
+			return counts, nil
+		}
+		filters.Offset += len(found)
+	}
+}
+
+// detectionKey identifies a detection by species and start second.
+func detectionKey(r *detection.Result) string {
+	return strings.ToLower(r.Species.ScientificName) + "|" + strconv.FormatInt(r.BeginTime.Unix(), 10)
+}
+
+func fileIOError(err error, operation, path string) error {
+	return errors.New(err).
+		Component(componentName).
+		Category(errors.CategoryFileIO).
+		Context("operation", operation).
+		Context("path", path).
+		Build()
+}
@@ -61,7 +61,7 @@ func (a *LogAction) Execute(_ context.Context, data any) error {
 	defer a.mu.Unlock()
 
 	// Check if the event should be handled for this species (supports scientific name lookup)
-	if !a.EventTracker.TrackEventWithNames(a.Result.Species.CommonName, a.Result.Species.ScientificName, LogToFile) {
+	if !a.EventTracker.TrackSourceEvent(a.Result.AudioSource.ID, a.Result.Species.CommonName, a.Result.Species.ScientificName, LogToFile) {
 		return nil
 	}
 
@@ -103,7 +103,7 @@ func (a *DatabaseAction) ExecuteContext(ctx context.Context, _ any) error {
 	defer a.mu.Unlock()
 
 	// Check event frequency (supports scientific name lookup)
-	if !a.EventTracker.TrackEventWithNames(a.Result.Species.CommonName, a.Result.Species.ScientificName, DatabaseSave) {
+	if !a.EventTracker.TrackSourceEvent(a.Result.AudioSource.ID, a.Result.Species.CommonName, a.Result.Species.ScientificName, DatabaseSave) {
 		return nil
 	}
 
@@ -421,6 +421,15 @@ func (a *SaveAudioAction) Execute(ctx context.Context, _ any) error {
 		return nil
 	}
 
+	// Blank human voice before the clip is written, pre-rendered or
+	// embedded. The capture buffer holds audio at the source rate.
+	captureRate := a.sourceSampleRate
+	if captureRate <= 0 {
+		captureRate = conf.SampleRate
+	}
+	var redacted []entities.RedactedSegment
+	a.pcmData, redacted = a.redactor.apply(a.pcmData, captureRate)
+
 	// Resolve NoteID from DetectionContext (set by DatabaseAction).
 	if a.DetectionCtx != nil {
 		const noteIDWaitTimeout = 5 * time.Second
@@ -490,6 +499,15 @@ func (a *SaveAudioAction) Execute(ctx context.Context, _ any) error {
 		a.DetectionCtx.ClipSaved.Store(true)
 	}
 
+	if len(redacted) > 0 {
+		GetLogger().Info("Human voice redacted from audio clip",
+			logger.String("component", "analysis.processor.actions"),
+			logger.String("detection_id", a.CorrelationID),
+			logger.Int("segments", len(redacted)),
+			logger.String("operation", "privacy_redaction"))
+		a.redactor.record(ctx, a.NoteID, redacted)
+	}
+
 	// Submit for pre-rendering if enabled
 	if a.Settings.Realtime.Dashboard.Spectrogram.Enabled && a.PreRenderer != nil {
 		// Create pre-render job using local DTO (avoids direct spectrogram dependency)
@@ -514,9 +532,33 @@ func (a *SaveAudioAction) Execute(ctx context.Context, _ any) error {
 		}
 	}
 
+	a.submitEmbedding(exportRate)
+
 	return nil
 }
 
+// submitEmbedding queues the saved clip for its similar recordings embedding
+// when embeddings are enabled. Bat clips are skipped: the embeddings model
+// hears only the audible range. The detection window starts after the
+// pre-capture padding.
+func (a *SaveAudioAction) submitEmbedding(sampleRate int) {
+	if !a.Settings.Realtime.Audio.Export.Embeddings.Enabled || a.Embeddings == nil || a.NoteID == 0 {
+		return
+	}
+	if detection.ResolveModelType(a.modelName, "") == entities.ModelTypeBat {
+		return
+	}
+	offset := time.Duration(a.Settings.Realtime.Audio.Export.PreCapture) * time.Second
+	if err := a.Embeddings.Submit(a.NoteID, a.pcmData, sampleRate, offset); err != nil {
+		GetLogger().Warn("Failed to submit clip embedding job",
+			logger.String("component", "analysis.processor.actions"),
+			logger.String("detection_id", a.CorrelationID),
+			logger.Any("note_id", a.NoteID),
+			logger.Error(err),
+			logger.String("operation", "embedding_submit"))
+	}
+}
+
 // encodeClip writes the captured PCM to outputPath in the resolved format and
 // returns the encoder tag for the success log.
 //
@@ -44,12 +44,12 @@ type NoteWithBirdImage struct {
 }
 
 // Execute sends the note to the BirdWeather API
-func (a *BirdWeatherAction) Execute(_ context.Context, data any) error {
+func (a *BirdWeatherAction) Execute(ctx context.Context, data any) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	// Check event frequency (supports scientific name lookup)
-	if !a.EventTracker.TrackEventWithNames(a.Result.Species.CommonName, a.Result.Species.ScientificName, BirdWeatherSubmit) {
+	if !a.EventTracker.TrackSourceEvent(a.Result.AudioSource.ID, a.Result.Species.CommonName, a.Result.Species.ScientificName, BirdWeatherSubmit) {
 		return nil
 	}
 
@@ -73,6 +73,19 @@ func (a *BirdWeatherAction) Execute(_ context.Context, data any) error {
 		return nil
 	}
 
+	// Blank human voice before the audio leaves the station. Done once, so
+	// retries upload the same audio.
+	if a.redactor != nil {
+		a.pcmData, _ = a.redactor.apply(a.pcmData, a.pcmRate)
+		a.redactor = nil
+	}
+
+	// With the durable outbox, queue the upload; its worker uploads it with the
+	// current client and retries across restarts and network outages.
+	if a.Outbox != nil {
+		return a.enqueue(ctx)
+	}
+
 	// Safe check for nil BwClient
 	if a.BwClient == nil {
 		// Client initialization failures indicate configuration issues that require
@@ -200,7 +213,7 @@ func (a *BirdWeatherAction) Execute(_ context.Context, data any) error {
 // Transient connection errors (EOF, not connected) are logged as warnings and
 // do NOT fail the CompositeAction — the detection is already saved to the database.
 // This eliminates the TOCTOU race at Layer 2 (GitHub #2397).
-func (a *MqttAction) Execute(_ context.Context, data any) error {
+func (a *MqttAction) Execute(actionCtx context.Context, data any) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -210,7 +223,7 @@ func (a *MqttAction) Execute(_ context.Context, data any) error {
 	// The Publish() method returns a clear error if the client is disconnected.
 
 	// Check event frequency (supports scientific name lookup)
-	if !a.EventTracker.TrackEventWithNames(a.Result.Species.CommonName, a.Result.Species.ScientificName, MQTTPublish) {
+	if !a.EventTracker.TrackSourceEvent(a.Result.AudioSource.ID, a.Result.Species.CommonName, a.Result.Species.ScientificName, MQTTPublish) {
 		return nil
 	}
 
@@ -279,6 +292,12 @@ func (a *MqttAction) Execute(_ context.Context, data any) error {
 		return err
 	}
 
+	// With the durable outbox, queue the message; its worker publishes it once
+	// the broker is reachable, in detection order.
+	if a.Outbox != nil {
+		return a.enqueue(actionCtx, noteJson)
+	}
+
 	// Create a context with timeout for publishing
 	ctx, cancel := context.WithTimeout(context.Background(), MQTTPublishTimeout)
 	defer cancel()
@@ -425,7 +444,7 @@ func (a *SSEAction) Execute(_ context.Context, data any) error {
 	}
 
 	// Check event frequency (supports scientific name lookup)
-	if !a.EventTracker.TrackEventWithNames(a.Result.Species.CommonName, a.Result.Species.ScientificName, SSEBroadcast) {
+	if !a.EventTracker.TrackSourceEvent(a.Result.AudioSource.ID, a.Result.Species.CommonName, a.Result.Species.ScientificName, SSEBroadcast) {
 		return nil
 	}
 
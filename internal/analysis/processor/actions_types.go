@@ -19,6 +19,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/detection"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
 	"github.com/tphakala/birdnet-go/internal/mqtt"
+	"github.com/tphakala/birdnet-go/internal/outbox"
 )
 
 // Timeout and interval constants
@@ -110,6 +111,8 @@ type SaveAudioAction struct {
 	modelName        string            // Detection model name (e.g. "BattyBirdNET") for export strategy
 	NoteID           uint              // Note ID for correlation logging with pre-renderer
 	PreRenderer      PreRendererSubmit // Injected from processor
+	Embeddings       EmbeddingSubmit   // Clip embedding indexer; nil skips embeddings
+	redactor         *voiceRedactor    // Blanks human voice before saving; nil unless the privacy filter redacts
 	DetectionCtx     *DetectionContext // Shared context to signal ClipSaved to late consumers
 	EventTracker     *EventTracker
 	Description      string
@@ -150,16 +153,26 @@ type PreRendererSubmit interface {
 	Stop() // Graceful shutdown
 }
 
+// EmbeddingSubmit queues a saved clip for its similar recordings embedding.
+// pcm is s16le mono at sampleRate and offset is where the detection window
+// starts in the clip. Implemented by *similarity.Indexer.
+type EmbeddingSubmit interface {
+	Submit(detectionID uint, pcm []byte, sampleRate int, offset time.Duration) error
+}
+
 type BirdWeatherAction struct {
 	Settings      *conf.Settings
 	Result        detection.Result // Domain model (single source of truth)
 	pcmData       []byte
+	pcmRate       int            // Sample rate of pcmData
+	redactor      *voiceRedactor // Blanks human voice before upload; nil unless the privacy filter redacts
 	BwClient      *birdweather.BwClient
 	EventTracker  *EventTracker
 	RetryConfig   jobqueue.RetryConfig // Configuration for retry behavior
 	Description   string
-	CorrelationID string     // Detection correlation ID for log tracking
-	mu            sync.Mutex // Protect concurrent access to Result and pcmData
+	CorrelationID string         // Detection correlation ID for log tracking
+	Outbox        *outbox.Outbox // Durable delivery queue; nil uploads directly
+	mu            sync.Mutex     // Protect concurrent access to Result and pcmData
 }
 
 type MqttAction struct {
@@ -171,8 +184,9 @@ type MqttAction struct {
 	DetectionCtx   *DetectionContext    // Shared context from DatabaseAction
 	RetryConfig    jobqueue.RetryConfig // Configuration for retry behavior
 	Description    string
-	CorrelationID  string     // Detection correlation ID for log tracking
-	mu             sync.Mutex // Protect concurrent access to Result
+	CorrelationID  string         // Detection correlation ID for log tracking
+	Outbox         *outbox.Outbox // Durable delivery queue; nil publishes directly
+	mu             sync.Mutex     // Protect concurrent access to Result
 }
 
 type UpdateRangeFilterAction struct {
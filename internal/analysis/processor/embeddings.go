@@ -0,0 +1,25 @@
+// embeddings.go: clip embeddings for the similar recordings search
+package processor
+
+import (
+	"github.com/tphakala/birdnet-go/internal/similarity"
+)
+
+// Compile-time assertion to ensure *similarity.Indexer implements EmbeddingSubmit
+var _ EmbeddingSubmit = (*similarity.Indexer)(nil)
+
+// SetEmbeddingIndexer routes saved clips to the embedding indexer so they
+// can be found by the similar recordings search. Actions created before the
+// call save their clips without embeddings.
+func (p *Processor) SetEmbeddingIndexer(ix *similarity.Indexer) {
+	p.embeddingIndexer.Store(ix)
+}
+
+// embeddingSubmit returns the embedding indexer as an EmbeddingSubmit, or a
+// nil interface when none is set so SaveAudioAction's nil check holds.
+func (p *Processor) embeddingSubmit() EmbeddingSubmit {
+	if ix := p.embeddingIndexer.Load(); ix != nil {
+		return ix
+	}
+	return nil
+}
@@ -86,6 +86,11 @@ type EventTracker struct {
 	SpeciesConfigs  map[string]conf.SpeciesConfig // Add this: Store species-specific configurations
 	DefaultInterval time.Duration                 // Add this: Store the global default interval
 	Mutex           sync.RWMutex                  // Mutex to ensure thread-safe access
+
+	// SourceSpecies resolves the species overrides of a detection source, whose
+	// config entries replace SpeciesConfigs for that source. Nil when no source
+	// has overrides.
+	SourceSpecies func(sourceID string) *conf.SpeciesSettings
 }
 
 // Add this new struct to hold configuration
@@ -144,6 +149,13 @@ func (et *EventTracker) TrackEvent(species string, eventType EventType) bool {
 // TrackEventWithNames checks if an event for a given species (by common or scientific name) should be processed.
 // This method supports lookup by both common name and scientific name, consistent with include/exclude matching.
 func (et *EventTracker) TrackEventWithNames(commonName, scientificName string, eventType EventType) bool {
+	return et.TrackSourceEvent("", commonName, scientificName, eventType)
+}
+
+// TrackSourceEvent is TrackEventWithNames for a detection from the given
+// source: a species interval configured on the source wins over the global
+// one. Events are still rate-limited per species across all sources.
+func (et *EventTracker) TrackSourceEvent(sourceID, commonName, scientificName string, eventType EventType) bool {
 	// Determine tracking key: prefer common name, fall back to scientific name.
 	// This ensures events are rate-limited per-species even if common name is missing.
 	trackingKey := commonName
@@ -158,6 +170,13 @@ func (et *EventTracker) TrackEventWithNames(commonName, scientificName string, e
 
 	normalizedTrackingKey := strings.ToLower(trackingKey)
 
+	// Resolve the source's overrides before taking the lock; the resolver reads
+	// the current settings.
+	var sourceSpecies *conf.SpeciesSettings
+	if sourceID != "" && et.SourceSpecies != nil {
+		sourceSpecies = et.SourceSpecies(sourceID)
+	}
+
 	et.Mutex.RLock()
 
 	handler, exists := et.Handlers[eventType]
@@ -170,7 +189,14 @@ func (et *EventTracker) TrackEventWithNames(commonName, scientificName string, e
 	effectiveTimeout := et.DefaultInterval
 
 	// Use lookupSpeciesConfig to support both common name and scientific name
-	if speciesConfig, found := lookupSpeciesConfig(et.SpeciesConfigs, commonName, scientificName); found {
+	speciesConfig, found := conf.SpeciesConfig{}, false
+	if sourceSpecies != nil {
+		speciesConfig, found = lookupSpeciesConfig(sourceSpecies.Config, commonName, scientificName)
+	}
+	if !found {
+		speciesConfig, found = lookupSpeciesConfig(et.SpeciesConfigs, commonName, scientificName)
+	}
+	if found {
 		if speciesConfig.Interval > 0 {
 			effectiveTimeout = time.Duration(speciesConfig.Interval) * time.Second
 		} else if speciesConfig.Interval < 0 {
@@ -235,7 +235,7 @@ func (m *ActionMockDatastore) CountSpeciesDetections(_, _, _ string, _ int) (int
 func (m *ActionMockDatastore) Transaction(_ func(tx *gorm.DB) error) error {
 	return nil
 }
-func (m *ActionMockDatastore) LockNote(_ string) error {
+func (m *ActionMockDatastore) LockNote(_, _ string) error {
 	return nil
 }
 func (m *ActionMockDatastore) UnlockNote(_ string) error {
@@ -521,8 +521,8 @@ func (m *MockDetectionRepository) GetByDateRange(_ context.Context, _, _ string,
 func (m *MockDetectionRepository) GetHourly(_ context.Context, _, _ string, _, _, _ int) ([]*detection.Result, int64, error) {
 	return nil, 0, nil
 }
-func (m *MockDetectionRepository) Lock(_ context.Context, _ string) error   { return nil }
-func (m *MockDetectionRepository) Unlock(_ context.Context, _ string) error { return nil }
+func (m *MockDetectionRepository) Lock(_ context.Context, _, _ string) error { return nil }
+func (m *MockDetectionRepository) Unlock(_ context.Context, _ string) error  { return nil }
 func (m *MockDetectionRepository) IsLocked(_ context.Context, _ string) (bool, error) {
 	return false, nil
 }
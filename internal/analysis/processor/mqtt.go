@@ -131,6 +131,7 @@ func (p *Processor) initializeMQTT(settings *conf.Settings) {
 	if settings.Realtime.MQTT.HomeAssistant.Enabled {
 		p.registerHomeAssistantDiscovery(mqttClient, settings)
 	}
+	p.RegisterMQTTCommands(mqttClient, settings)
 
 	// Create a context with a timeout for the connection attempt
 	ctx, cancel := context.WithTimeout(context.Background(), mqttConnectionTimeout)
@@ -151,6 +152,43 @@ func (p *Processor) initializeMQTT(settings *conf.Settings) {
 	p.SetMQTTClient(mqttClient)
 }
 
+// SetMQTTCommandHandler sets the function that executes commands received on
+// the MQTT command topics. The API controller injects it after the MQTT client
+// is created, so the client's handler looks it up for every command.
+func (p *Processor) SetMQTTCommandHandler(handler mqtt.CommandHandler) {
+	if handler == nil {
+		p.mqttCommandHandler.Store(nil)
+		return
+	}
+	p.mqttCommandHandler.Store(&handler)
+}
+
+// RegisterMQTTCommands subscribes the client to the command topics when MQTT
+// commands are enabled. Call it before Connect so the initial connection
+// subscribes too.
+func (p *Processor) RegisterMQTTCommands(client mqtt.Client, settings *conf.Settings) {
+	if client == nil || settings == nil || !settings.Realtime.MQTT.Commands {
+		return
+	}
+	client.SetCommandHandler(p.handleMQTTCommand)
+	GetLogger().Info("MQTT command topics enabled",
+		logger.String("topic", mqtt.CommandTopic(settings.Realtime.MQTT.Topic)))
+}
+
+// handleMQTTCommand passes a command to the injected command handler.
+func (p *Processor) handleMQTTCommand(ctx context.Context, cmd mqtt.Command) error {
+	handler := p.mqttCommandHandler.Load()
+	if handler == nil {
+		return errors.Newf("MQTT command handler not initialized").
+			Component("analysis.processor").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "mqtt_command").
+			Context("command", cmd.Name).
+			Build()
+	}
+	return (*handler)(ctx, cmd)
+}
+
 // RegisterHomeAssistantDiscovery registers the OnConnect handler for Home Assistant discovery.
 // This is called during MQTT initialization and after MQTT reconfiguration.
 func (p *Processor) RegisterHomeAssistantDiscovery(client mqtt.Client, settings *conf.Settings) {
@@ -204,6 +242,7 @@ func (p *Processor) publishHomeAssistantDiscovery(ctx context.Context, client mq
 		DeviceName:      haSettings.DeviceName,
 		NodeID:          settings.Main.Name,
 		Version:         settings.Version,
+		Commands:        settings.Realtime.MQTT.Commands,
 	}
 
 	publisher := mqtt.NewDiscoveryPublisher(client, &discoveryConfig)
@@ -78,6 +78,7 @@ func (m *MockMQTTClient) Disconnect() {
 func (m *MockMQTTClient) TestConnection(_ context.Context, _ chan<- mqtt.TestResult) {}
 func (m *MockMQTTClient) SetControlChannel(_ chan string)                            {}
 func (m *MockMQTTClient) RegisterOnConnectHandler(_ mqtt.OnConnectHandler)           {}
+func (m *MockMQTTClient) SetCommandHandler(_ mqtt.CommandHandler)                    {}
 
 // GetPublishedPayload returns the last published payload.
 func (m *MockMQTTClient) GetPublishedPayload() string {
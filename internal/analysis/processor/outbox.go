@@ -0,0 +1,109 @@
+// outbox.go: durable delivery of BirdWeather uploads and MQTT publishes
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/outbox"
+)
+
+// birdWeatherDelivery is the outbox payload of a BirdWeather upload.
+type birdWeatherDelivery struct {
+	Note datastore.Note `json:"note"`
+	PCM  []byte         `json:"pcm"`
+}
+
+// mqttDelivery is the outbox payload of an MQTT publish.
+type mqttDelivery struct {
+	Topic   string `json:"topic"`
+	Message string `json:"message"`
+}
+
+// SetOutbox routes BirdWeather uploads and MQTT publishes of new detections
+// through the durable outbox and registers their deliverers on it. Actions
+// created before the call still deliver directly.
+func (p *Processor) SetOutbox(o *outbox.Outbox) {
+	o.Register(outbox.KindBirdWeather, p.deliverBirdWeather)
+	o.Register(outbox.KindMQTT, p.deliverMQTT)
+	p.deliveryOutbox.Store(o)
+}
+
+// deliverBirdWeather uploads a queued detection with the current BirdWeather
+// client. Uploads queued before BirdWeather was disabled are dropped.
+func (p *Processor) deliverBirdWeather(_ context.Context, _ string, payload []byte) error {
+	if !p.currentSettings().Realtime.Birdweather.Enabled {
+		return nil
+	}
+	var delivery birdWeatherDelivery
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return outbox.Permanent(fmt.Errorf("decode BirdWeather delivery: %w", err))
+	}
+	client := p.GetBwClient()
+	if client == nil {
+		return errors.Newf("BirdWeather client is not initialized").
+			Component("analysis.processor").
+			Category(errors.CategoryIntegration).
+			Context("operation", "birdweather_outbox_upload").
+			Build()
+	}
+	if err := client.Publish(&delivery.Note, delivery.PCM); err != nil {
+		// BirdWeather does not know the species; retrying will not change that.
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// deliverMQTT publishes a queued message with the current MQTT client.
+// Messages queued before MQTT was disabled are dropped.
+func (p *Processor) deliverMQTT(ctx context.Context, _ string, payload []byte) error {
+	if !p.currentSettings().Realtime.MQTT.Enabled {
+		return nil
+	}
+	var delivery mqttDelivery
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return outbox.Permanent(fmt.Errorf("decode MQTT delivery: %w", err))
+	}
+	client := p.GetMQTTClient()
+	if client == nil {
+		return ErrMQTTClientNotReady
+	}
+	ctx, cancel := context.WithTimeout(ctx, MQTTPublishTimeout)
+	defer cancel()
+	return client.Publish(ctx, delivery.Topic, delivery.Message)
+}
+
+// enqueue queues the upload in the outbox.
+func (a *BirdWeatherAction) enqueue(ctx context.Context) error {
+	payload, err := json.Marshal(birdWeatherDelivery{
+		Note: datastore.NoteFromResult(&a.Result),
+		PCM:  a.pcmData,
+	})
+	if err != nil {
+		return err
+	}
+	return a.Outbox.Enqueue(ctx, outbox.KindBirdWeather, deliverySummary(a.Result.Species.CommonName, a.Result.Confidence), payload)
+}
+
+// enqueue queues the message in the outbox.
+func (a *MqttAction) enqueue(ctx context.Context, message []byte) error {
+	payload, err := json.Marshal(mqttDelivery{
+		Topic:   a.Settings.Realtime.MQTT.Topic,
+		Message: string(message),
+	})
+	if err != nil {
+		return err
+	}
+	return a.Outbox.Enqueue(ctx, outbox.KindMQTT, deliverySummary(a.Result.Species.CommonName, a.Result.Confidence), payload)
+}
+
+// deliverySummary describes a queued detection for the outbox listing.
+func deliverySummary(commonName string, confidence float64) string {
+	return fmt.Sprintf("%s (%.2f)", commonName, confidence)
+}
@@ -0,0 +1,121 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/outbox"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+func newTestOutboxRepo(t *testing.T) repository.OutboxRepository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "outbox.db")), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+	require.NoError(t, db.AutoMigrate(&entities.OutboxItem{}))
+	return repository.NewOutboxRepository(db, nil)
+}
+
+// TestMqttAction_Execute_QueuesInOutbox verifies that with an outbox the
+// action queues the message instead of publishing it, and that the processor
+// publishes the queued message.
+func TestMqttAction_Execute_QueuesInOutbox(t *testing.T) {
+	conf.StoreSettings(nil)
+	t.Cleanup(func() { conf.StoreSettings(nil) })
+
+	settings := &conf.Settings{}
+	settings.Realtime.MQTT.Enabled = true
+	settings.Realtime.MQTT.Topic = testMQTTTopic
+
+	repo := newTestOutboxRepo(t)
+	box := outbox.New(repo)
+	mockClient := NewMockMQTTClient()
+	p := &Processor{Settings: settings}
+	p.SetMQTTClient(mockClient)
+	p.SetOutbox(box)
+
+	action := &MqttAction{
+		Settings:     settings,
+		Result:       testDetection().Result,
+		MqttClient:   mockClient,
+		EventTracker: NewEventTracker(testEventTrackerInterval),
+		Outbox:       box,
+	}
+	require.NoError(t, action.Execute(t.Context(), nil))
+	assert.Zero(t, mockClient.GetPublishCalls(), "the action only queues")
+
+	heads, err := repo.PendingHeads(t.Context())
+	require.NoError(t, err)
+	require.Len(t, heads, 1)
+	assert.Equal(t, outbox.KindMQTT, heads[0].Destination)
+	assert.Contains(t, heads[0].Summary, action.Result.Species.CommonName)
+
+	require.NoError(t, p.deliverMQTT(t.Context(), "", heads[0].Payload))
+	assert.Equal(t, testMQTTTopic, mockClient.GetPublishedTopic())
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal([]byte(mockClient.GetPublishedPayload()), &payload))
+	assert.Equal(t, action.Result.Species.CommonName, payload["CommonName"])
+}
+
+// TestProcessor_DeliverMQTT verifies how queued MQTT messages are handled
+// when the client is missing or MQTT has been disabled.
+func TestProcessor_DeliverMQTT(t *testing.T) {
+	conf.StoreSettings(nil)
+	t.Cleanup(func() { conf.StoreSettings(nil) })
+
+	settings := &conf.Settings{}
+	settings.Realtime.MQTT.Enabled = true
+	p := &Processor{Settings: settings}
+	payload := []byte(`{"topic":"birdnet","message":"{}"}`)
+
+	require.ErrorIs(t, p.deliverMQTT(context.Background(), "", payload), ErrMQTTClientNotReady,
+		"a missing client is retried")
+	assert.True(t, outbox.IsPermanent(p.deliverMQTT(context.Background(), "", []byte("not json"))))
+
+	settings.Realtime.MQTT.Enabled = false
+	require.NoError(t, p.deliverMQTT(context.Background(), "", payload), "disabled MQTT drops the message")
+}
+
+// TestBirdWeatherAction_Execute_QueuesInOutbox verifies that with an outbox
+// the upload, including its audio, is queued.
+func TestBirdWeatherAction_Execute_QueuesInOutbox(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Realtime.Birdweather.Enabled = true
+
+	repo := newTestOutboxRepo(t)
+	det := testDetection()
+	action := &BirdWeatherAction{
+		Settings:     settings,
+		Result:       det.Result,
+		pcmData:      []byte{1, 2, 3, 4},
+		EventTracker: NewEventTracker(testEventTrackerInterval),
+		Outbox:       outbox.New(repo),
+	}
+	require.NoError(t, action.Execute(t.Context(), nil))
+
+	heads, err := repo.PendingHeads(t.Context())
+	require.NoError(t, err)
+	require.Len(t, heads, 1)
+	assert.Equal(t, outbox.KindBirdWeather, heads[0].Destination)
+
+	var delivery birdWeatherDelivery
+	require.NoError(t, json.Unmarshal(heads[0].Payload, &delivery))
+	assert.Equal(t, []byte{1, 2, 3, 4}, delivery.PCM)
+	assert.Equal(t, det.Result.Species.ScientificName, delivery.Note.ScientificName)
+}
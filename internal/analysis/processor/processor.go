@@ -32,8 +32,10 @@ import (
 	"github.com/tphakala/birdnet-go/internal/notification"
 	"github.com/tphakala/birdnet-go/internal/observability"
 	"github.com/tphakala/birdnet-go/internal/openfauna"
+	"github.com/tphakala/birdnet-go/internal/outbox"
 	"github.com/tphakala/birdnet-go/internal/privacy"
 	"github.com/tphakala/birdnet-go/internal/securefs"
+	"github.com/tphakala/birdnet-go/internal/similarity"
 	"github.com/tphakala/birdnet-go/internal/spectrogram"
 	"github.com/tphakala/birdnet-go/internal/suncalc"
 )
@@ -81,7 +83,8 @@ type Processor struct {
 	pendingDetections    map[string]PendingDetection
 	pendingMutex         sync.RWMutex // RWMutex to protect access to pendingDetections (RLock for snapshots)
 	dogDetectionMutex    sync.Mutex
-	detectionMutex       sync.RWMutex // Mutex to protect LastDogDetection and LastHumanDetection maps
+	detectionMutex       sync.RWMutex           // Mutex to protect LastDogDetection, LastHumanDetection and humanSpans
+	humanSpans           map[string][]humanSpan // human voice spans per audio source, for privacy redaction
 	controlChan          chan string
 	JobQueue             *jobqueue.JobQueue // Queue for managing job retries
 	workerCancel         context.CancelFunc // Function to cancel worker goroutines
@@ -114,6 +117,26 @@ type Processor struct {
 	discoveryDebounceMu     sync.Mutex
 	defaultDiscoveryCleanup sync.Once // ensures stale "default" discovery cleanup runs at most once
 
+	// MQTT command handler, injected by the API controller once the control
+	// domain is built (see SetMQTTCommandHandler).
+	mqttCommandHandler atomic.Pointer[mqtt.CommandHandler]
+
+	// Durable delivery outbox for BirdWeather uploads and MQTT publishes,
+	// injected by the API controller when the v2 database is active (see
+	// SetOutbox). Nil sends directly with in-memory job queue retries.
+	deliveryOutbox atomic.Pointer[outbox.Outbox]
+
+	// Clip embedding indexer for the similar recordings search, injected by
+	// the audio pipeline when the v2 database is active (see
+	// SetEmbeddingIndexer). Nil saves clips without embeddings.
+	embeddingIndexer atomic.Pointer[similarity.Indexer]
+
+	// Store for the segments the privacy filter redacts from saved clips,
+	// injected by the audio pipeline when the v2 database is active (see
+	// SetRedactionStore). Nil redacts without recording the segments.
+	redactionStore   RedactionStore
+	redactionStoreMu sync.RWMutex
+
 	// BufferMgr provides access to capture buffers for audio clip extraction.
 	// Set once during pipeline initialization (audio_pipeline_service.go) and never replaced;
 	// no synchronization needed for concurrent reads.
@@ -193,6 +216,8 @@ func (p *Processor) currentSettings() *conf.Settings {
 type Detections struct {
 	CorrelationID string                       // Unique detection identifier for log correlation
 	pcmData3s     []byte                       // 3s PCM data containing the detection
+	pcmFrom3s     time.Time                    // capture time of pcmData3s's first sample
+	pcmRate3s     int                          // sample rate of pcmData3s
 	Result        detection.Result             // Detection result containing highest match
 	Results       []detection.AdditionalResult // Additional BirdNET prediction results
 }
@@ -512,6 +537,8 @@ func New(settings *conf.Settings, ds datastore.Interface, bn *classifier.Orchest
 		JobQueue:           jobqueue.NewJobQueue(), // Initialize the job queue
 	}
 
+	p.EventTracker.SourceSpecies = p.currentSourceSpecies
+
 	// Initialize log deduplicator with configuration from settings
 	p.logDedup = initLogDeduplicator(settings)
 
@@ -828,6 +855,8 @@ func (p *Processor) processResults(settings *conf.Settings, item classifier.Resu
 	// throttle.
 	feedThrottle := detectionThrottle(classifier.ModelRegistry[item.ModelID].Spec.ClipLength)
 	feedTime := time.Now().Add(-detection.DetectionTimeOffset)
+	deployment := p.sourceDeployment(settings, item.Source.ID)
+	sourceSpecies := p.sourceSpecies(settings, item.Source.ID)
 
 	// Process each result in item.Results
 	for _, result := range item.Results {
@@ -854,7 +883,7 @@ func (p *Processor) processResults(settings *conf.Settings, item classifier.Resu
 		p.handleHumanDetection(settings, item, result)
 
 		// Determine confidence threshold and check filters
-		baseThreshold := p.getBaseConfidenceThreshold(settings, commonName, scientificName, item.ModelID)
+		baseThreshold := p.getSourceConfidenceThreshold(settings, sourceSpecies, commonName, scientificName, item.ModelID)
 
 		// Check if detection should be filtered
 		shouldSkip, _ := p.shouldFilterDetection(settings, result, commonName, scientificName, speciesLowercase, baseThreshold, item.Source.ID, item.ModelID)
@@ -865,7 +894,8 @@ func (p *Processor) processResults(settings *conf.Settings, item classifier.Resu
 		// species passes the range filter. This is independent of whether the
 		// detection is saved below (saved detections also clear this bar).
 		if result.Confidence > baseThreshold {
-			inRange := !shouldApplyRangeFilter(item.ModelID, settings) || settings.IsSpeciesIncluded(result.Species)
+			inRange := !shouldApplyRangeFilter(item.ModelID, settings) || isSourceIncluded(sourceSpecies, commonName, scientificName) ||
+				settings.IsSpeciesIncludedAt(deployment, result.Species)
 			p.updateLastDetection(item.ModelID, commonName, scientificName, float64(result.Confidence), feedTime, inRange, feedThrottle)
 		}
 
@@ -1022,8 +1052,11 @@ func (p *Processor) shouldFilterDetection(settings *conf.Settings, result datast
 	// This is the authoritative per-detection check. The range filter also excludes these
 	// species when building the included list, but that only works when the range filter
 	// model is active and location is configured. This check ensures excluded species are
-	// always filtered regardless of range filter state.
-	if isSpeciesExcluded(commonName, scientificName, settings.Realtime.Species.Exclude) {
+	// always filtered regardless of range filter state. A source's own exclude list adds
+	// to the global one.
+	sourceSpecies := p.sourceSpecies(settings, source)
+	if isSpeciesExcluded(commonName, scientificName, settings.Realtime.Species.Exclude) ||
+		(sourceSpecies != nil && isSpeciesExcluded(commonName, scientificName, sourceSpecies.Exclude)) {
 		if settings.Debug {
 			GetLogger().Debug("Detection filtered: species is on exclude list",
 				logger.String("species", result.Species),
@@ -1037,8 +1070,8 @@ func (p *Processor) shouldFilterDetection(settings *conf.Settings, result datast
 	if settings.Realtime.DynamicThreshold.Enabled {
 		// Check if this species has a custom user-configured threshold (> 0)
 		// Species may be in Config only for custom actions/interval without threshold set
-		// Use lookupSpeciesConfig to support both common name and scientific name lookups
-		config, exists := lookupSpeciesConfig(settings.Realtime.Species.Config, commonName, scientificName)
+		// The source's own config entry for the species takes precedence over the global one
+		config, exists := lookupSourceSpeciesConfig(settings, sourceSpecies, commonName, scientificName)
 		isCustomThreshold := exists && config.Threshold > 0
 		confidenceThreshold = p.getAdjustedConfidenceThreshold(modelID, speciesLowercase, baseThreshold, isCustomThreshold)
 	} else {
@@ -1059,7 +1092,9 @@ func (p *Processor) shouldFilterDetection(settings *conf.Settings, result datast
 		return true, confidenceThreshold
 	}
 
-	if shouldApplyRangeFilter(modelID, settings) && !settings.IsSpeciesIncluded(result.Species) {
+	// Species on the source's include list bypass the range filter for that source
+	if shouldApplyRangeFilter(modelID, settings) && !isSourceIncluded(sourceSpecies, commonName, scientificName) &&
+		!settings.IsSpeciesIncludedAt(p.sourceDeployment(settings, source), result.Species) {
 		if settings.Debug {
 			GetLogger().Debug("species not on included list",
 				logger.String("species", result.Species),
@@ -1077,16 +1112,22 @@ func (p *Processor) shouldFilterDetection(settings *conf.Settings, result datast
 // in the exclude list. Matching is case-insensitive and supports either name form, consistent
 // with the range filter's matchesSpecies logic (see birdnet/range_filter.go).
 func isSpeciesExcluded(commonName, scientificName string, excludeList []string) bool {
-	// Canonicalize the detection's scientific name once so an exclude entry the user
+	return isSpeciesListed(commonName, scientificName, excludeList)
+}
+
+// isSpeciesListed checks if a species (by common or scientific name) matches any
+// entry in a species list, see isSpeciesExcluded.
+func isSpeciesListed(commonName, scientificName string, list []string) bool {
+	// Canonicalize the detection's scientific name once so an entry the user
 	// keyed on a legacy/alias scientific name still matches a detection that now
 	// carries the canonical name (and vice versa). CanonicalName is identity for
 	// non-aliased names, so non-reclassified species behave exactly as before.
 	canonicalSci := openfauna.CanonicalName(scientificName)
-	for _, excluded := range excludeList {
-		if strings.EqualFold(commonName, excluded) {
+	for _, listed := range list {
+		if strings.EqualFold(commonName, listed) {
 			return true
 		}
-		if scientificName != "" && strings.EqualFold(canonicalSci, openfauna.CanonicalName(excluded)) {
+		if scientificName != "" && strings.EqualFold(canonicalSci, openfauna.CanonicalName(listed)) {
 			return true
 		}
 	}
@@ -1144,9 +1185,19 @@ func (p *Processor) createDetection(settings *conf.Settings, item classifier.Res
 	// Generate unique correlation ID for detection tracking
 	correlationID := p.generateCorrelationID(commonName, item.StartTime)
 
+	// The analysis chunk ends when its audio was captured; its length
+	// follows from the model's sample rate.
+	pcmRate := classifier.ModelRegistry[item.ModelID].Spec.SampleRate
+	if pcmRate <= 0 {
+		pcmRate = conf.SampleRate
+	}
+	pcmFrom := item.AudioCapturedAt.Add(-time.Duration(len(item.PCMdata)/conf.BytesPerSample) * time.Second / time.Duration(pcmRate))
+
 	return Detections{
 		CorrelationID: correlationID,
 		pcmData3s:     item.PCMdata,
+		pcmFrom3s:     pcmFrom,
+		pcmRate3s:     pcmRate,
 		Result:        detectionResult,
 		Results:       additionalResults,
 	}
@@ -1168,6 +1219,12 @@ func (p *Processor) createDetectionResult(settings *conf.Settings,
 	// Resolve audio source info from registry
 	audioSource := p.resolveAudioSource(source)
 
+	// Sources deployed away from the station report their own coordinates.
+	latitude, longitude := settings.BirdNET.Latitude, settings.BirdNET.Longitude
+	if audioSource.Deployment = p.sourceDeployment(settings, source.ID); audioSource.Deployment != nil && audioSource.Deployment.HasLocation() {
+		latitude, longitude = audioSource.Deployment.Latitude, audioSource.Deployment.Longitude
+	}
+
 	return detection.Result{
 		Timestamp:   detectionTime,
 		SourceNode:  settings.Main.Name,
@@ -1181,8 +1238,8 @@ func (p *Processor) createDetectionResult(settings *conf.Settings,
 			RawScientificName: rawScientificName,
 		},
 		Confidence:     math.Round(confidence*100) / 100,
-		Latitude:       settings.BirdNET.Latitude,
-		Longitude:      settings.BirdNET.Longitude,
+		Latitude:       latitude,
+		Longitude:      longitude,
 		Threshold:      settings.BirdNET.Threshold,
 		Sensitivity:    settings.BirdNET.Sensitivity,
 		ClipName:       clipName,
@@ -1232,6 +1289,23 @@ func (p *Processor) resolveAudioSource(source datastore.AudioSource) detection.A
 	return audioSource
 }
 
+// sourceDeployment returns the deployment metadata of the source with the
+// given ID, or nil when the source has none. The ID is a registry source ID or,
+// for legacy callers, the connection string itself.
+func (p *Processor) sourceDeployment(settings *conf.Settings, sourceID string) *conf.DeploymentConfig {
+	if d := settings.SourceDeployment(sourceID); d != nil {
+		return d
+	}
+	registry := p.Registry()
+	if registry == nil {
+		return nil
+	}
+	if connStr, ok := registry.ConnectionStringByID(sourceID); ok {
+		return settings.SourceDeployment(connStr)
+	}
+	return nil
+}
+
 // convertToAdditionalResults converts a slice of datastore.Results to detection.AdditionalResult,
 // deduplicating by scientific name and keeping the highest confidence for each species.
 // The primary species is excluded since it's already stored as Detection.LabelID.
@@ -1333,6 +1407,11 @@ func (p *Processor) handleHumanDetection(settings *conf.Settings, item classifie
 		p.detectionMutex.Lock()
 		p.LastHumanDetection[item.Source.ID] = item.StartTime
 		p.detectionMutex.Unlock()
+		// In redact mode the voice is blanked from clips instead, which
+		// needs the exact stretch of audio it was heard in.
+		if settings.Realtime.PrivacyFilter.Redacting() {
+			p.recordHumanSpan(item)
+		}
 	}
 }
 
@@ -1341,8 +1420,15 @@ func (p *Processor) handleHumanDetection(settings *conf.Settings, item classifie
 // The modelID parameter selects which global threshold to use when no per-species config exists:
 // bat models use settings.Bat.Threshold, all others use settings.BirdNET.Threshold.
 func (p *Processor) getBaseConfidenceThreshold(settings *conf.Settings, commonName, scientificName, modelID string) float32 {
+	return p.getSourceConfidenceThreshold(settings, nil, commonName, scientificName, modelID)
+}
+
+// getSourceConfidenceThreshold is getBaseConfidenceThreshold for a source with
+// species overrides; the source's config entry for the species wins over the
+// global one. A nil sourceSpecies uses the global settings only.
+func (p *Processor) getSourceConfidenceThreshold(settings *conf.Settings, sourceSpecies *conf.SpeciesSettings, commonName, scientificName, modelID string) float32 {
 	// Check if species has a custom threshold using both common and scientific name lookup
-	if config, exists := lookupSpeciesConfig(settings.Realtime.Species.Config, commonName, scientificName); exists {
+	if config, exists := lookupSourceSpeciesConfig(settings, sourceSpecies, commonName, scientificName); exists {
 		if settings.Debug {
 			GetLogger().Debug("using custom confidence threshold",
 				logger.String("commonName", commonName),
@@ -1466,8 +1552,9 @@ func (p *Processor) shouldDiscardDetection(item *PendingDetection, settings *con
 		return true, fmt.Sprintf("false positive, matched %d/%d times", item.Count, minDetections)
 	}
 
-	// Check privacy filter
-	if settings.Realtime.PrivacyFilter.Enabled {
+	// Check privacy filter. In redact mode the detection is kept and the
+	// voice is blanked from its clip instead.
+	if settings.Realtime.PrivacyFilter.Enabled && !settings.Realtime.PrivacyFilter.Redacting() {
 		p.detectionMutex.RLock()
 		lastHumanDetection, exists := p.LastHumanDetection[item.Source]
 		p.detectionMutex.RUnlock()
@@ -1853,8 +1940,10 @@ func (p *Processor) pendingDetectionsFlusher() {
 func (p *Processor) getActionsForItem(det *Detections) []Action {
 	settings := p.currentSettings()
 
-	// Check if species has custom configuration using both common and scientific name lookup
-	if speciesConfig, exists := lookupSpeciesConfig(settings.Realtime.Species.Config, det.Result.Species.CommonName, det.Result.Species.ScientificName); exists {
+	// Check if species has custom configuration using both common and scientific name lookup,
+	// preferring the detection source's own entry over the global one
+	sourceSpecies := p.sourceSpecies(settings, det.Result.AudioSource.ID)
+	if speciesConfig, exists := lookupSourceSpeciesConfig(settings, sourceSpecies, det.Result.Species.CommonName, det.Result.Species.ScientificName); exists {
 		if settings.Debug {
 			GetLogger().Debug("species config exists for custom actions",
 				logger.String("commonName", det.Result.Species.CommonName),
@@ -2022,7 +2111,7 @@ func (p *Processor) getDefaultActions(det *Detections) []Action {
 	}
 
 	// Create DatabaseAction if database is enabled
-	if settings.Output.SQLite.Enabled || settings.Output.MySQL.Enabled {
+	if settings.Output.SQLite.Enabled || settings.Output.MySQL.Enabled || settings.Output.Postgres.Enabled {
 		p.speciesTrackerMu.RLock()
 		tracker := p.NewSpeciesTracker
 		p.speciesTrackerMu.RUnlock()
@@ -2068,9 +2157,11 @@ func (p *Processor) getDefaultActions(det *Detections) []Action {
 	// NOTE: We intentionally do NOT check IsConnected() here. The connection state
 	// at action-creation time is stale by the time the action executes from the job queue
 	// (TOCTOU Layer 1, GitHub #2397). The publish path handles disconnected state gracefully.
+	// With the durable outbox the action only queues the message, so it is
+	// created even while the client is down and delivered once it is back.
 	if settings.Realtime.MQTT.Enabled {
 		mqttClient := p.GetMQTTClient()
-		if mqttClient != nil {
+		if mqttClient != nil || p.deliveryOutbox.Load() != nil {
 			mqttRetryConfig := retryConfigFromSettings(settings.Realtime.MQTT.RetrySettings)
 
 			mqttAction = &MqttAction{
@@ -2082,6 +2173,7 @@ func (p *Processor) getDefaultActions(det *Detections) []Action {
 				BirdImageCache: p.BirdImageCache,
 				RetryConfig:    mqttRetryConfig,
 				CorrelationID:  det.CorrelationID,
+				Outbox:         p.deliveryOutbox.Load(),
 			}
 		}
 	}
@@ -2156,8 +2248,11 @@ func (p *Processor) getDefaultActions(det *Detections) []Action {
 				BwClient:      bwClient,
 				Result:        det.Result, // Domain model (single source of truth)
 				pcmData:       det.pcmData3s,
+				pcmRate:       det.pcmRate3s,
+				redactor:      p.newVoiceRedactor(settings, det.Result.AudioSource.ID, det.pcmFrom3s),
 				RetryConfig:   bwRetryConfig,
 				CorrelationID: det.CorrelationID,
+				Outbox:        p.deliveryOutbox.Load(),
 			})
 		}
 	}
@@ -2244,6 +2339,8 @@ func (p *Processor) buildSaveAudioAction(det *Detections, detectionCtx *Detectio
 			modelName:        det.Result.Model.Name,
 			NoteID:           det.Result.ID, // May be 0 here; updated after DB save via DetectionCtx
 			PreRenderer:      p.preRenderer,
+			Embeddings:       p.embeddingSubmit(),
+			redactor:         p.newVoiceRedactor(settings, det.Result.AudioSource.ID, det.Result.BeginTime),
 			DetectionCtx:     detectionCtx,
 			CorrelationID:    det.CorrelationID,
 		}
@@ -2270,6 +2367,8 @@ func (p *Processor) buildSaveAudioAction(det *Detections, detectionCtx *Detectio
 			modelName:        det.Result.Model.Name,
 			NoteID:           det.Result.ID, // May be 0 here; updated after DB save via DetectionCtx
 			PreRenderer:      p.preRenderer,
+			Embeddings:       p.embeddingSubmit(),
+			redactor:         p.newVoiceRedactor(settings, det.Result.AudioSource.ID, det.Result.BeginTime),
 			DetectionCtx:     detectionCtx,
 			CorrelationID:    det.CorrelationID,
 		}
@@ -2283,6 +2382,8 @@ func (p *Processor) buildSaveAudioAction(det *Detections, detectionCtx *Detectio
 		modelName:        det.Result.Model.Name,
 		NoteID:           det.Result.ID, // May be 0 here; updated after DB save via DetectionCtx
 		PreRenderer:      p.preRenderer,
+		Embeddings:       p.embeddingSubmit(),
+		redactor:         p.newVoiceRedactor(settings, det.Result.AudioSource.ID, det.Result.BeginTime),
 		DetectionCtx:     detectionCtx,
 		CorrelationID:    det.CorrelationID,
 	}
@@ -2330,6 +2431,9 @@ func (p *Processor) DisconnectBwClient() {
 
 // SetEventTracker safely replaces the current EventTracker
 func (p *Processor) SetEventTracker(tracker *EventTracker) {
+	if tracker != nil && tracker.SourceSpecies == nil {
+		tracker.SourceSpecies = p.currentSourceSpecies
+	}
 	p.eventTrackerMu.Lock()
 	defer p.eventTrackerMu.Unlock()
 	p.EventTracker = tracker
@@ -2722,18 +2826,21 @@ func (p *Processor) initPreRenderer() {
 			return
 		}
 
-		// Validate Sox binary is configured and exists
-		if p.Settings.Realtime.Audio.SoxPath == "" {
-			GetLogger().Error("Sox binary not configured, disabling pre-rendering",
-				logger.String("operation", "prerenderer_init"))
-			return
-		}
-		if _, err := exec.LookPath(p.Settings.Realtime.Audio.SoxPath); err != nil {
-			GetLogger().Error("Sox binary not found, disabling pre-rendering",
-				logger.String("path", p.Settings.Realtime.Audio.SoxPath),
-				logger.Error(err),
-				logger.String("operation", "prerenderer_init"))
-			return
+		// Validate Sox binary is configured and exists; the native renderer
+		// does not need it
+		if !p.Settings.Realtime.Dashboard.Spectrogram.UsesNativeRenderer() {
+			if p.Settings.Realtime.Audio.SoxPath == "" {
+				GetLogger().Error("Sox binary not configured, disabling pre-rendering",
+					logger.String("operation", "prerenderer_init"))
+				return
+			}
+			if _, err := exec.LookPath(p.Settings.Realtime.Audio.SoxPath); err != nil {
+				GetLogger().Error("Sox binary not found, disabling pre-rendering",
+					logger.String("path", p.Settings.Realtime.Audio.SoxPath),
+					logger.Error(err),
+					logger.String("operation", "prerenderer_init"))
+				return
+			}
 		}
 
 		// Create SecureFS for path validation
@@ -67,3 +67,43 @@ func TestCreateDetectionResult_ModelInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateDetectionResult_DeploymentLocation(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.BirdNET.Latitude, settings.BirdNET.Longitude = 60.17, 24.94
+	settings.Realtime.RTSP.Streams = []conf.StreamConfig{
+		{Name: "Meadow", URL: "rtsp://meadow/stream", Deployment: conf.DeploymentConfig{Site: "North Meadow", Latitude: 61.5, Longitude: 23.8}},
+		{Name: "Yard", URL: "rtsp://yard/stream", Deployment: conf.DeploymentConfig{Site: "Yard"}},
+	}
+	p := &Processor{Settings: settings}
+
+	create := func(sourceID string) (lat, lon float64, deployment *conf.DeploymentConfig) {
+		result := p.createDetectionResult(settings,
+			time.Now(),
+			time.Now(), time.Now().Add(3*time.Second),
+			"Parus major", "Great Tit", "gretit1", "",
+			0.95,
+			datastore.AudioSource{ID: sourceID, DisplayName: "Test"},
+			"clip.wav",
+			100*time.Millisecond, 0.5,
+			"",
+			"Parus major_Great Tit_gretit1",
+		)
+		return result.Latitude, result.Longitude, result.AudioSource.Deployment
+	}
+
+	lat, lon, deployment := create("rtsp://meadow/stream")
+	assert.InDelta(t, 61.5, lat, 0, "a source with its own location reports it")
+	assert.InDelta(t, 23.8, lon, 0)
+	assert.Equal(t, "North Meadow", deployment.Site)
+
+	lat, lon, deployment = create("rtsp://yard/stream")
+	assert.InDelta(t, 60.17, lat, 0, "a source without coordinates is at the station")
+	assert.InDelta(t, 24.94, lon, 0)
+	assert.Equal(t, "Yard", deployment.Site)
+
+	_, _, deployment = create("hw:0,0")
+	assert.Nil(t, deployment)
+}
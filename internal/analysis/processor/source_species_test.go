@@ -0,0 +1,105 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// sourceSpeciesSettings has a feeder stream with a low finch threshold, a
+// roadside mic excluding a traffic-noise species and a yard stream without
+// overrides.
+func sourceSpeciesSettings() *conf.Settings {
+	settings := &conf.Settings{}
+	settings.BirdNET.Threshold = 0.8
+	settings.BirdNET.LocationConfigured = true
+	settings.BirdNET.RangeFilter.Model = "latest"
+	settings.BirdNET.RangeFilter.IncludedScientificNames = map[string]struct{}{
+		"spinus spinus": {},
+		"pica pica":     {},
+	}
+	settings.Realtime.Species.Config = map[string]conf.SpeciesConfig{
+		"eurasian siskin": {Threshold: 0.9, Interval: 600},
+	}
+	settings.Realtime.RTSP.Streams = []conf.StreamConfig{
+		{Name: "Feeder", URL: "rtsp://feeder/stream", Species: &conf.SpeciesSettings{
+			Include: []string{"Loxia curvirostra"},
+			Config:  map[string]conf.SpeciesConfig{"eurasian siskin": {Threshold: 0.3, Interval: 1}},
+		}},
+		{Name: "Yard", URL: "rtsp://yard/stream"},
+	}
+	settings.Realtime.Audio.Sources = []conf.AudioSourceConfig{
+		{Name: "Roadside", Device: "hw:1,0", Species: &conf.SpeciesSettings{Exclude: []string{"Eurasian Magpie"}}},
+	}
+	return settings
+}
+
+func TestSourceConfidenceThreshold(t *testing.T) {
+	t.Parallel()
+
+	settings := sourceSpeciesSettings()
+	p := &Processor{}
+
+	feeder := p.sourceSpecies(settings, "rtsp://feeder/stream")
+	require.NotNil(t, feeder)
+	assert.InDelta(t, 0.3, p.getSourceConfidenceThreshold(settings, feeder, "Eurasian Siskin", "Spinus spinus", ""), 0.001,
+		"the source entry replaces the global one")
+	assert.InDelta(t, 0.9, p.getSourceConfidenceThreshold(settings, nil, "Eurasian Siskin", "Spinus spinus", ""), 0.001)
+	assert.InDelta(t, 0.8, p.getSourceConfidenceThreshold(settings, feeder, "Eurasian Magpie", "Pica pica", ""), 0.001,
+		"species without an entry use the global threshold")
+	assert.Nil(t, p.sourceSpecies(settings, "rtsp://yard/stream"))
+}
+
+func TestShouldFilterDetection_SourceSpecies(t *testing.T) {
+	t.Parallel()
+
+	settings := sourceSpeciesSettings()
+	p := &Processor{}
+
+	tests := []struct {
+		name           string
+		source         string
+		species        string
+		commonName     string
+		scientificName string
+		expectedFilter bool
+	}{
+		{"source exclude list", "hw:1,0", "Pica pica_Eurasian Magpie", "Eurasian Magpie", "Pica pica", true},
+		{"other sources keep the species", "rtsp://yard/stream", "Pica pica_Eurasian Magpie", "Eurasian Magpie", "Pica pica", false},
+		{"source include bypasses range filter", "rtsp://feeder/stream", "Loxia curvirostra_Red Crossbill", "Red Crossbill", "Loxia curvirostra", false},
+		{"range filter applies elsewhere", "rtsp://yard/stream", "Loxia curvirostra_Red Crossbill", "Red Crossbill", "Loxia curvirostra", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := datastore.Results{Species: tt.species, Confidence: 0.85}
+			shouldFilter, _ := p.shouldFilterDetection(settings, result, tt.commonName, tt.scientificName, "", 0.8, tt.source, "")
+			assert.Equal(t, tt.expectedFilter, shouldFilter)
+		})
+	}
+}
+
+func TestEventTracker_TrackSourceEvent(t *testing.T) {
+	t.Parallel()
+
+	settings := sourceSpeciesSettings()
+	p := &Processor{}
+	tracker := NewEventTrackerWithConfig(60*time.Second, settings.Realtime.Species.Config)
+	tracker.SourceSpecies = func(sourceID string) *conf.SpeciesSettings {
+		return p.sourceSpecies(settings, sourceID)
+	}
+
+	// The feeder's 1 second interval wins over the global 600 seconds
+	require.True(t, tracker.TrackSourceEvent("rtsp://feeder/stream", "Eurasian Siskin", "Spinus spinus", DatabaseSave))
+	require.False(t, tracker.TrackSourceEvent("rtsp://feeder/stream", "Eurasian Siskin", "Spinus spinus", DatabaseSave))
+	time.Sleep(1100 * time.Millisecond)
+	assert.True(t, tracker.TrackSourceEvent("rtsp://feeder/stream", "Eurasian Siskin", "Spinus spinus", DatabaseSave))
+	assert.False(t, tracker.TrackSourceEvent("rtsp://yard/stream", "Eurasian Siskin", "Spinus spinus", DatabaseSave),
+		"other sources use the global interval")
+}
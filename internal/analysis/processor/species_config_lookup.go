@@ -59,3 +59,46 @@ func lookupSpeciesConfig(configMap map[string]conf.SpeciesConfig, commonName, sc
 
 	return conf.SpeciesConfig{}, false
 }
+
+// lookupSourceSpeciesConfig looks up a species configuration in a source's
+// species overrides first and falls back to the global species config. The
+// source's entry replaces the global entry for that species, so a source can
+// change the threshold, interval or actions of one species without inheriting
+// the rest of the global entry. A nil sourceSpecies uses the global config only.
+func lookupSourceSpeciesConfig(settings *conf.Settings, sourceSpecies *conf.SpeciesSettings, commonName, scientificName string) (conf.SpeciesConfig, bool) {
+	if sourceSpecies != nil {
+		if config, exists := lookupSpeciesConfig(sourceSpecies.Config, commonName, scientificName); exists {
+			return config, true
+		}
+	}
+	return lookupSpeciesConfig(settings.Realtime.Species.Config, commonName, scientificName)
+}
+
+// isSourceIncluded reports whether a species is on the source's include list.
+// Such species are always reported from that source, whatever the range filter says.
+func isSourceIncluded(sourceSpecies *conf.SpeciesSettings, commonName, scientificName string) bool {
+	return sourceSpecies != nil && isSpeciesListed(commonName, scientificName, sourceSpecies.Include)
+}
+
+// sourceSpecies returns the species overrides of the source with the given ID,
+// or nil when the source uses the global species settings. The ID is a
+// registry source ID or, for legacy callers, the connection string itself.
+func (p *Processor) sourceSpecies(settings *conf.Settings, sourceID string) *conf.SpeciesSettings {
+	if species := settings.SourceSpecies(sourceID); species != nil {
+		return species
+	}
+	registry := p.Registry()
+	if registry == nil {
+		return nil
+	}
+	if connStr, ok := registry.ConnectionStringByID(sourceID); ok {
+		return settings.SourceSpecies(connStr)
+	}
+	return nil
+}
+
+// currentSourceSpecies resolves a source's species overrides against the
+// current settings, so event trackers follow settings hot reloads.
+func (p *Processor) currentSourceSpecies(sourceID string) *conf.SpeciesSettings {
+	return p.sourceSpecies(p.currentSettings(), sourceID)
+}
@@ -118,7 +118,7 @@ func (m *MockDatastore) CountSpeciesDetections(string, string, string, int) (int
 	return 0, nil
 }
 func (m *MockDatastore) Transaction(func(*gorm.DB) error) error { return nil }
-func (m *MockDatastore) LockNote(string) error                  { return nil }
+func (m *MockDatastore) LockNote(string, string) error          { return nil }
 func (m *MockDatastore) UnlockNote(string) error                { return nil }
 func (m *MockDatastore) GetNoteLock(string) (*datastore.NoteLock, error) {
 	return nil, datastore.ErrNoteLockNotFound
@@ -0,0 +1,217 @@
+// voice_redaction.go: privacy filter redaction of human voice in saved and streamed audio
+package processor
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/classifier"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// humanSpanRetention is how long human voice spans are kept per source.
+	// It outlasts the capture buffer, so every clip that can still be read
+	// from the buffer finds the spans it overlaps.
+	humanSpanRetention = 10 * time.Minute
+
+	// redactNoiseAmplitude is the peak sample value of the noise fill, about
+	// -54 dBFS: audible as a steady hiss, far below speech.
+	redactNoiseAmplitude = 64
+
+	// liveRedactionMargin covers inference and queueing time on top of the
+	// clip length in LiveRedactionDelay.
+	liveRedactionMargin = 3 * time.Second
+)
+
+// RedactionStore records which stretches of saved clips were redacted.
+// Implemented by the v2 ClipRedactionRepository.
+type RedactionStore interface {
+	Save(ctx context.Context, detectionID uint, fill string, segments []entities.RedactedSegment) error
+}
+
+// humanSpan is a stretch of captured audio classified as human voice.
+type humanSpan struct {
+	start, end time.Time
+}
+
+// SetRedactionStore records the segments redacted from saved clips so the
+// clip metadata shows them. Nil redacts without recording.
+func (p *Processor) SetRedactionStore(store RedactionStore) {
+	p.redactionStoreMu.Lock()
+	defer p.redactionStoreMu.Unlock()
+	p.redactionStore = store
+}
+
+// recordHumanSpan remembers the analysis chunk of a human voice detection
+// for redaction. The chunk ends when its audio was captured and lasts the
+// model's clip length. Overlapping chunks are merged and spans older than
+// humanSpanRetention are dropped.
+//
+//nolint:gocritic // hugeParam: matches handleHumanDetection
+func (p *Processor) recordHumanSpan(item classifier.Results) {
+	clipLength := classifier.ModelRegistry[item.ModelID].Spec.ClipLength
+	if clipLength <= 0 {
+		clipLength = 3 * time.Second
+	}
+	end := item.AudioCapturedAt
+	if end.IsZero() {
+		end = time.Now()
+	}
+	span := humanSpan{start: end.Add(-clipLength), end: end}
+	cutoff := end.Add(-humanSpanRetention)
+
+	p.detectionMutex.Lock()
+	defer p.detectionMutex.Unlock()
+	if p.humanSpans == nil {
+		p.humanSpans = make(map[string][]humanSpan)
+	}
+	spans := slices.DeleteFunc(p.humanSpans[item.Source.ID], func(s humanSpan) bool {
+		return s.end.Before(cutoff)
+	})
+	if n := len(spans); n > 0 && !span.start.After(spans[n-1].end) {
+		// Chunks arrive in capture order, so only the last span can overlap.
+		if span.end.After(spans[n-1].end) {
+			spans[n-1].end = span.end
+		}
+		if span.start.Before(spans[n-1].start) {
+			spans[n-1].start = span.start
+		}
+	} else {
+		spans = append(spans, span)
+	}
+	p.humanSpans[item.Source.ID] = spans
+}
+
+// humanSpansBetween returns the human voice spans of a source that overlap
+// [start, end).
+func (p *Processor) humanSpansBetween(sourceID string, start, end time.Time) []humanSpan {
+	p.detectionMutex.RLock()
+	defer p.detectionMutex.RUnlock()
+	var out []humanSpan
+	for _, s := range p.humanSpans[sourceID] {
+		if s.end.After(start) && s.start.Before(end) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// voiceRedactor blanks human voice in one piece of detection audio before it
+// is saved or uploaded. Actions carry a nil redactor unless the privacy
+// filter is in redact mode.
+type voiceRedactor struct {
+	p         *Processor
+	sourceID  string
+	fill      string
+	audioFrom time.Time // capture time of the audio's first sample
+}
+
+// newVoiceRedactor returns a redactor for audio of sourceID captured from
+// audioFrom, or nil when the privacy filter is not redacting.
+func (p *Processor) newVoiceRedactor(settings *conf.Settings, sourceID string, audioFrom time.Time) *voiceRedactor {
+	if !settings.Realtime.PrivacyFilter.Redacting() {
+		return nil
+	}
+	fill := settings.Realtime.PrivacyFilter.RedactFill
+	if fill == "" {
+		fill = conf.RedactFillSilence
+	}
+	return &voiceRedactor{p: p, sourceID: sourceID, fill: fill, audioFrom: audioFrom}
+}
+
+// apply returns pcm (s16le mono at sampleRate) with the human voice blanked
+// and the blanked stretches. pcm is never modified, since the same chunk can
+// back several detections; a copy is made only when something is redacted.
+// A nil redactor returns pcm unchanged.
+func (r *voiceRedactor) apply(pcm []byte, sampleRate int) ([]byte, []entities.RedactedSegment) {
+	if r == nil || len(pcm) == 0 || sampleRate <= 0 {
+		return pcm, nil
+	}
+	duration := time.Duration(len(pcm)/conf.BytesPerSample) * time.Second / time.Duration(sampleRate)
+	spans := r.p.humanSpansBetween(r.sourceID, r.audioFrom, r.audioFrom.Add(duration))
+	if len(spans) == 0 {
+		return pcm, nil
+	}
+	out := slices.Clone(pcm)
+	segments := redactSpans(out, sampleRate, r.audioFrom, spans, r.fill)
+	if len(segments) == 0 {
+		return pcm, nil
+	}
+	return out, segments
+}
+
+// record stores the redacted segments of a saved clip. Failures are logged;
+// the clip itself is already redacted.
+func (r *voiceRedactor) record(ctx context.Context, detectionID uint, segments []entities.RedactedSegment) {
+	if r == nil || detectionID == 0 || len(segments) == 0 {
+		return
+	}
+	r.p.redactionStoreMu.RLock()
+	store := r.p.redactionStore
+	r.p.redactionStoreMu.RUnlock()
+	if store == nil {
+		return
+	}
+	if err := store.Save(ctx, detectionID, r.fill, segments); err != nil {
+		GetLogger().Warn("Failed to record clip redaction",
+			logger.Any("note_id", detectionID),
+			logger.Error(err),
+			logger.String("operation", "privacy_redaction_record"))
+	}
+}
+
+// LiveRedactionDelay is how long live audio must be held back before it is
+// streamed in redact mode. Voice in a sample is only known once every
+// analysis chunk holding it has been captured and analysed, which takes up
+// to the longest model clip length plus inference time.
+func LiveRedactionDelay() time.Duration {
+	var clipLength time.Duration
+	for _, info := range classifier.ModelRegistry {
+		clipLength = max(clipLength, info.Spec.ClipLength)
+	}
+	return clipLength + liveRedactionMargin
+}
+
+// RedactLiveAudio blanks human voice in live audio (s16le mono at
+// sampleRate) of sourceID captured from audioFrom, as saved clips are
+// redacted. The audio must be at least LiveRedactionDelay old for the voice
+// in it to be known. pcm is never modified; it is returned unchanged when
+// the privacy filter is not redacting or no voice overlaps it.
+func (p *Processor) RedactLiveAudio(pcm []byte, sampleRate int, sourceID string, audioFrom time.Time) []byte {
+	out, _ := p.newVoiceRedactor(p.currentSettings(), sourceID, audioFrom).apply(pcm, sampleRate)
+	return out
+}
+
+// redactSpans overwrites the parts of pcm that fall inside spans with
+// silence or low-level noise and returns them as offsets from audioFrom.
+func redactSpans(pcm []byte, sampleRate int, audioFrom time.Time, spans []humanSpan, fill string) []entities.RedactedSegment {
+	numSamples := len(pcm) / conf.BytesPerSample
+	var segments []entities.RedactedSegment
+	for _, s := range spans {
+		first := max(0, int(s.start.Sub(audioFrom).Seconds()*float64(sampleRate)))
+		last := min(numSamples, int(s.end.Sub(audioFrom).Seconds()*float64(sampleRate)))
+		if first >= last {
+			continue
+		}
+		region := pcm[first*conf.BytesPerSample : last*conf.BytesPerSample]
+		if fill == conf.RedactFillNoise {
+			for i := 0; i+1 < len(region); i += conf.BytesPerSample {
+				v := int16(rand.IntN(2*redactNoiseAmplitude+1) - redactNoiseAmplitude) //nolint:gosec // G404: noise fill, not security sensitive
+				binary.LittleEndian.PutUint16(region[i:], uint16(v))                   //nolint:gosec // G115: two's complement sample bits
+			}
+		} else {
+			clear(region)
+		}
+		segments = append(segments, entities.RedactedSegment{
+			Start: float64(first) / float64(sampleRate),
+			End:   float64(last) / float64(sampleRate),
+		})
+	}
+	return segments
+}
@@ -0,0 +1,172 @@
+package processor
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/classifier"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// redactionTestRate keeps the test PCM small: 100 samples per second.
+const redactionTestRate = 100
+
+// constantPCM returns seconds of s16le mono audio with every sample at value.
+func constantPCM(seconds int, value int16) []byte {
+	pcm := make([]byte, seconds*redactionTestRate*conf.BytesPerSample)
+	for i := 0; i < len(pcm); i += conf.BytesPerSample {
+		binary.LittleEndian.PutUint16(pcm[i:], uint16(value))
+	}
+	return pcm
+}
+
+// sampleAt returns the sample at second offset of pcm.
+func sampleAt(pcm []byte, second float64) int16 {
+	i := int(second*redactionTestRate) * conf.BytesPerSample
+	return int16(binary.LittleEndian.Uint16(pcm[i:]))
+}
+
+// humanChunk builds analysis results for a BirdNET chunk captured at end.
+func humanChunk(source string, end time.Time) classifier.Results {
+	return classifier.Results{
+		AudioCapturedAt: end,
+		Source:          datastore.AudioSource{ID: source},
+		ModelID:         "BirdNET_V2.4",
+	}
+}
+
+type fakeRedactionStore struct {
+	detectionID uint
+	fill        string
+	segments    []entities.RedactedSegment
+}
+
+func (s *fakeRedactionStore) Save(_ context.Context, detectionID uint, fill string, segments []entities.RedactedSegment) error {
+	s.detectionID, s.fill, s.segments = detectionID, fill, segments
+	return nil
+}
+
+func TestRecordHumanSpan_MergesOverlappingChunks(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 6, 11, 8, 0, 0, 0, time.UTC)
+	p := &Processor{}
+
+	// 50% overlapping 3s chunks merge into one span.
+	p.recordHumanSpan(humanChunk("src1", base.Add(3*time.Second)))
+	p.recordHumanSpan(humanChunk("src1", base.Add(4500*time.Millisecond)))
+	// A later, separate chunk starts a new span.
+	p.recordHumanSpan(humanChunk("src1", base.Add(20*time.Second)))
+	p.recordHumanSpan(humanChunk("src2", base.Add(3*time.Second)))
+
+	spans := p.humanSpansBetween("src1", base, base.Add(time.Minute))
+	require.Len(t, spans, 2)
+	assert.Equal(t, humanSpan{start: base, end: base.Add(4500 * time.Millisecond)}, spans[0])
+	assert.Equal(t, humanSpan{start: base.Add(17 * time.Second), end: base.Add(20 * time.Second)}, spans[1])
+
+	assert.Empty(t, p.humanSpansBetween("src1", base.Add(5*time.Second), base.Add(16*time.Second)), "gap between spans")
+
+	// Spans older than the retention are dropped on the next record.
+	p.recordHumanSpan(humanChunk("src1", base.Add(humanSpanRetention+time.Minute)))
+	assert.Len(t, p.humanSpansBetween("src1", base, base.Add(2*humanSpanRetention)), 1)
+}
+
+func TestVoiceRedactor_BlanksOverlappingVoice(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 6, 11, 8, 0, 0, 0, time.UTC)
+	settings := &conf.Settings{}
+	settings.Realtime.PrivacyFilter.Enabled = true
+
+	p := &Processor{}
+	assert.Nil(t, p.newVoiceRedactor(settings, "src1", base), "discard mode does not redact")
+
+	settings.Realtime.PrivacyFilter.Mode = conf.PrivacyModeRedact
+	// Voice from 2s to 5s into a 10s clip.
+	p.recordHumanSpan(humanChunk("src1", base.Add(5*time.Second)))
+
+	r := p.newVoiceRedactor(settings, "src1", base)
+	require.NotNil(t, r)
+	assert.Equal(t, conf.RedactFillSilence, r.fill, "empty fill means silence")
+
+	pcm := constantPCM(10, 1000)
+	out, segments := r.apply(pcm, redactionTestRate)
+
+	assert.Equal(t, []entities.RedactedSegment{{Start: 2, End: 5}}, segments)
+	assert.Equal(t, int16(1000), sampleAt(out, 1.9))
+	assert.Equal(t, int16(0), sampleAt(out, 2))
+	assert.Equal(t, int16(0), sampleAt(out, 4.99))
+	assert.Equal(t, int16(1000), sampleAt(out, 5))
+	assert.Equal(t, int16(1000), sampleAt(pcm, 3), "input is not modified")
+
+	// Audio from another source is untouched.
+	other := p.newVoiceRedactor(settings, "src2", base)
+	out, segments = other.apply(pcm, redactionTestRate)
+	assert.Empty(t, segments)
+	assert.Equal(t, int16(1000), sampleAt(out, 3))
+
+	// The segments are recorded against the detection.
+	store := &fakeRedactionStore{}
+	p.SetRedactionStore(store)
+	r.record(t.Context(), 42, []entities.RedactedSegment{{Start: 2, End: 5}})
+	assert.Equal(t, uint(42), store.detectionID)
+	assert.Equal(t, conf.RedactFillSilence, store.fill)
+	assert.Len(t, store.segments, 1)
+}
+
+func TestLiveRedactionDelay_CoversLongestClip(t *testing.T) {
+	t.Parallel()
+
+	for id, info := range classifier.ModelRegistry {
+		assert.Greater(t, LiveRedactionDelay(), info.Spec.ClipLength,
+			"live audio must be held back until %s has analysed it", id)
+	}
+}
+
+func TestVoiceRedactor_NoiseFill(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 6, 11, 8, 0, 0, 0, time.UTC)
+	settings := &conf.Settings{}
+	settings.Realtime.PrivacyFilter.Enabled = true
+	settings.Realtime.PrivacyFilter.Mode = conf.PrivacyModeRedact
+	settings.Realtime.PrivacyFilter.RedactFill = conf.RedactFillNoise
+
+	p := &Processor{}
+	// Voice runs past the end of the clip.
+	p.recordHumanSpan(humanChunk("src1", base.Add(12*time.Second)))
+
+	out, segments := p.newVoiceRedactor(settings, "src1", base).apply(constantPCM(10, 10000), redactionTestRate)
+
+	assert.Equal(t, []entities.RedactedSegment{{Start: 9, End: 10}}, segments)
+	assert.Equal(t, int16(10000), sampleAt(out, 8.99))
+	for s := 9.0; s < 10; s += 0.01 {
+		v := sampleAt(out, s)
+		assert.LessOrEqual(t, v, int16(redactNoiseAmplitude))
+		assert.GreaterOrEqual(t, v, int16(-redactNoiseAmplitude))
+	}
+}
+
+func TestShouldDiscardDetection_RedactModeKeepsDetection(t *testing.T) {
+	t.Parallel()
+
+	const source = "src1"
+	base := time.Date(2026, 6, 11, 8, 0, 0, 0, time.UTC)
+	settings := &conf.Settings{}
+	settings.Realtime.PrivacyFilter.Enabled = true
+	settings.Realtime.PrivacyFilter.Mode = conf.PrivacyModeRedact
+
+	p := &Processor{
+		LastHumanDetection: map[string]time.Time{source: base},
+	}
+	discard, reason := p.shouldDiscardDetection(newPrivacyFilterDetection(source, base), settings, 1)
+
+	assert.False(t, discard)
+	assert.Empty(t, reason)
+}
@@ -0,0 +1,109 @@
+// soundscape_monitor.go - continuous soundscape recorders and their retention
+// monitor.
+package analysis
+
+import (
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/audiocore"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/diskmanager"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/soundscape"
+)
+
+// soundscapeRetentionStartupDelay defers the first retention pass past boot.
+const soundscapeRetentionStartupDelay = 5 * time.Minute
+
+// soundscapeRetentionInterval is the wait between retention passes. A pass
+// deletes at most a bounded number of segments, so it runs often enough to
+// keep up with several sources writing short segments.
+const soundscapeRetentionInterval = 15 * time.Minute
+
+// registerSoundscapeRecorder routes a source to a new soundscape recorder.
+// The route gets no equalizer chain: soundscapes are kept as the raw signal
+// so they remain usable for acoustic indices, only the source gain applies.
+func (p *AudioPipelineService) registerSoundscapeRecorder(sid, sourceName string, sampleRate int, gainDB float64, operation string) {
+	log := audiocore.GetLogger()
+	if p.soundscapes == nil {
+		log.Warn("soundscape recording requires the enhanced database, skipping source",
+			logger.String("source_id", sid),
+			logger.String("source_name", sourceName),
+			logger.String("operation", operation))
+		return
+	}
+
+	rec := soundscape.NewRecorder(sid, sourceName, sampleRate, p.soundscapes)
+	if err := p.engine.Router().AddRoute(sid, rec, sampleRate, gainDB, nil); err != nil {
+		_ = rec.Close()
+		log.Warn("failed to add soundscape route",
+			logger.String("source_id", sid), logger.Error(err), logger.String("operation", operation))
+		return
+	}
+	log.Info("soundscape recording started",
+		logger.String("source_id", sid),
+		logger.String("source_name", sourceName),
+		logger.Int("sample_rate", sampleRate),
+		logger.String("operation", operation))
+}
+
+// soundscapeRouteChanged reports whether a kept source's soundscape route
+// disagrees with the desired setting. Without the enhanced database a wanted
+// recorder is never registered, so that case does not count as a change.
+func (p *AudioPipelineService) soundscapeRouteChanged(sid string, want bool) bool {
+	if want && p.soundscapes == nil {
+		return false
+	}
+	have := false
+	consumerID := soundscape.ConsumerID(sid)
+	for _, route := range p.engine.Router().Routes(sid) {
+		if route.ConsumerID == consumerID {
+			have = true
+			break
+		}
+	}
+	return have != want
+}
+
+// soundscapeRetentionMonitor periodically deletes the oldest soundscape
+// segments that exceed the configured age or total size. It reads the
+// settings via conf.Setting() each pass so hot-reload takes effect.
+func soundscapeRetentionMonitor(quitChan <-chan struct{}, store repository.SoundscapeRepository) {
+	log := GetLogger()
+	log.Info("soundscape retention monitor initialized",
+		logger.String("operation", "soundscape_retention_init"))
+
+	if !reconcileMonitorWait(quitChan, soundscapeRetentionStartupDelay) {
+		return
+	}
+
+	for {
+		cfg := conf.Setting().Realtime.Audio.Soundscape
+		maxAge, err := cfg.Retention.MaxAgeDuration()
+		baseDir := strings.TrimSpace(cfg.Path)
+		switch {
+		case err != nil:
+			log.Warn("skipping soundscape retention: invalid max age",
+				logger.String("max_age", cfg.Retention.MaxAge),
+				logger.Error(err),
+				logger.String("operation", "soundscape_retention_skip"))
+		case baseDir == "":
+			log.Debug("skipping soundscape retention: path not configured",
+				logger.String("operation", "soundscape_retention_skip"))
+		default:
+			result := diskmanager.SoundscapeCleanup(quitChan, store, baseDir, maxAge, cfg.Retention.MaxSizeBytes(), time.Now())
+			if result.Err != nil {
+				log.Warn("soundscape retention pass failed",
+					logger.Error(result.Err),
+					logger.Int("segments_removed", result.ClipsRemoved),
+					logger.String("operation", "soundscape_retention_pass"))
+			}
+		}
+
+		if !reconcileMonitorWait(quitChan, soundscapeRetentionInterval) {
+			return
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Package annotation reads and writes detection annotations in the tabular
+// formats used by bioacoustics tooling, such as Raven selection tables and
+// BirdNET-Analyzer style CSV results.
+//
+// Annotations are expressed as Selections: a time span within a recording,
+// an optional frequency band and the species label assigned to it. Times are
+// relative to the start of the recording the selection belongs to.
+package annotation
+
+import (
+	"strconv"
+	"time"
+)
+
+const componentName = "annotation"
+
+// Default frequency band used when a detector does not localise a call in
+// frequency. BirdNET analyses 0-15 kHz, so the whole band is selected.
+const (
+	DefaultLowFreq  = 0.0
+	DefaultHighFreq = 15000.0
+)
+
+// Selection is a single annotated region of a recording.
+type Selection struct {
+	File           string        // path of the recording the selection refers to
+	Begin          time.Duration // offset of the selection start within File
+	End            time.Duration // offset of the selection end within File
+	LowFreq        float64       // Hz
+	HighFreq       float64       // Hz
+	ScientificName string
+	CommonName     string
+	SpeciesCode    string
+	Confidence     float64 // 0..1
+}
+
+// formatSeconds renders a duration as fractional seconds the way Raven and
+// BirdNET-Analyzer write them.
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,91 @@
+package annotation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSelections() []Selection {
+	return []Selection{
+		{
+			File:           "/cards/A01/20240501_053000.wav",
+			Begin:          3 * time.Second,
+			End:            6 * time.Second,
+			LowFreq:        DefaultLowFreq,
+			HighFreq:       DefaultHighFreq,
+			ScientificName: "Turdus merula",
+			CommonName:     "Eurasian Blackbird",
+			SpeciesCode:    "eurbla",
+			Confidence:     0.91234,
+		},
+		{
+			File:           "/cards/A01/20240501_053000.wav",
+			Begin:          1500 * time.Millisecond,
+			End:            4500 * time.Millisecond,
+			HighFreq:       DefaultHighFreq,
+			ScientificName: "Erithacus rubecula",
+			CommonName:     "European\tRobin",
+			Confidence:     0.5,
+		},
+	}
+}
+
+func TestWriteRavenTable(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	require.NoError(t, WriteRavenTable(&buf, testSelections()))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, strings.Join(ravenHeader, "\t"), lines[0])
+	assert.Equal(t,
+		"1\tSpectrogram 1\t1\t3.000\t6.000\t0.0\t15000.0\tEurasian Blackbird\teurbla\t0.9123\t/cards/A01/20240501_053000.wav\t3.000",
+		lines[1])
+
+	fields := strings.Split(lines[2], "\t")
+	require.Len(t, fields, len(ravenHeader), "tabs inside names must not add columns")
+	assert.Equal(t, "2", fields[0])
+	assert.Equal(t, "European Robin", fields[7])
+	assert.Equal(t, "1.500", fields[3])
+}
+
+func TestWriteRavenTable_Empty(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	require.NoError(t, WriteRavenTable(&buf, nil))
+	assert.Equal(t, strings.Join(ravenHeader, "\t")+"\n", buf.String(), "an empty table still has a header")
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, testSelections()))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{"3.000", "6.000", "Turdus merula", "Eurasian Blackbird", "0.9123", "/cards/A01/20240501_053000.wav"}, records[1])
+	assert.Equal(t, "European\tRobin", records[2][3], "CSV quoting preserves the original name")
+}
+
+func TestWriteAudacityLabels(t *testing.T) {
+	t.Parallel()
+	selections := testSelections()
+	selections[1].CommonName = ""
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteAudacityLabels(&buf, selections))
+	assert.Equal(t,
+		"3.000\t6.000\tEurasian Blackbird 0.91\n"+
+			"\\\t0.0\t15000.0\n"+
+			"1.500\t4.500\tErithacus rubecula 0.50\n"+
+			"\\\t0.0\t15000.0\n",
+		buf.String(), "labels without a common name fall back to the scientific name")
+}
@@ -0,0 +1,42 @@
+package annotation
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// WriteAudacityLabels writes selections as an Audacity label track. Each label
+// line is followed by the "\" line Audacity uses for a spectral selection, so
+// imported labels keep their frequency band.
+func WriteAudacityLabels(w io.Writer, selections []Selection) error {
+	bw := bufio.NewWriter(w)
+	for i := range selections {
+		s := &selections[i]
+		_, _ = bw.WriteString(formatSeconds(s.Begin) + "\t" + formatSeconds(s.End) + "\t" + audacityLabel(s) + "\n")
+		_, _ = bw.WriteString("\\\t" + strconv.FormatFloat(s.LowFreq, 'f', 1, 64) + "\t" +
+			strconv.FormatFloat(s.HighFreq, 'f', 1, 64) + "\n")
+	}
+
+	// bufio.Writer latches the first write error and reports it on Flush.
+	if err := bw.Flush(); err != nil {
+		return errors.New(err).
+			Component(componentName).
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_audacity_labels").
+			Build()
+	}
+	return nil
+}
+
+// audacityLabel names a selection by common name, falling back to the
+// scientific name, followed by its confidence.
+func audacityLabel(s *Selection) string {
+	name := s.CommonName
+	if name == "" {
+		name = s.ScientificName
+	}
+	return sanitizeField(name) + " " + strconv.FormatFloat(s.Confidence, 'f', 2, 64)
+}
@@ -0,0 +1,40 @@
+package annotation
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// csvHeader matches the BirdNET-Analyzer "csv" result type.
+var csvHeader = []string{"Start (s)", "End (s)", "Scientific name", "Common name", "Confidence", "File"}
+
+// WriteCSV writes selections as BirdNET-Analyzer compatible CSV.
+func WriteCSV(w io.Writer, selections []Selection) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write(csvHeader)
+	for i := range selections {
+		s := &selections[i]
+		_ = cw.Write([]string{
+			formatSeconds(s.Begin),
+			formatSeconds(s.End),
+			s.ScientificName,
+			s.CommonName,
+			strconv.FormatFloat(s.Confidence, 'f', 4, 64),
+			s.File,
+		})
+	}
+
+	// csv.Writer buffers and reports the first write error via Error.
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return errors.New(err).
+			Component(componentName).
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_csv").
+			Build()
+	}
+	return nil
+}
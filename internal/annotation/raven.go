@@ -0,0 +1,84 @@
+package annotation
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// ravenHeader lists the selection table columns in the order BirdNET-Analyzer
+// writes them, so tables produced here open in Raven Pro and can be merged
+// with tables from other BirdNET tools.
+var ravenHeader = []string{
+	"Selection",
+	"View",
+	"Channel",
+	"Begin Time (s)",
+	"End Time (s)",
+	"Low Freq (Hz)",
+	"High Freq (Hz)",
+	"Common Name",
+	"Species Code",
+	"Confidence",
+	"Begin Path",
+	"File Offset (s)",
+}
+
+const (
+	ravenView    = "Spectrogram 1"
+	ravenChannel = "1"
+)
+
+// WriteRavenTable writes selections as a tab-separated Raven selection table.
+// Selections are numbered from 1 in the order given.
+func WriteRavenTable(w io.Writer, selections []Selection) error {
+	bw := bufio.NewWriter(w)
+	writeRow := func(fields []string) {
+		_, _ = bw.WriteString(strings.Join(fields, "\t"))
+		_ = bw.WriteByte('\n')
+	}
+
+	writeRow(ravenHeader)
+	for i := range selections {
+		s := &selections[i]
+		begin := formatSeconds(s.Begin)
+		writeRow([]string{
+			strconv.Itoa(i + 1),
+			ravenView,
+			ravenChannel,
+			begin,
+			formatSeconds(s.End),
+			strconv.FormatFloat(s.LowFreq, 'f', 1, 64),
+			strconv.FormatFloat(s.HighFreq, 'f', 1, 64),
+			sanitizeField(s.CommonName),
+			sanitizeField(s.SpeciesCode),
+			strconv.FormatFloat(s.Confidence, 'f', 4, 64),
+			sanitizeField(s.File),
+			begin,
+		})
+	}
+
+	// bufio.Writer latches the first write error and reports it on Flush.
+	if err := bw.Flush(); err != nil {
+		return errors.New(err).
+			Component(componentName).
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_raven_table").
+			Build()
+	}
+	return nil
+}
+
+// sanitizeField removes characters that would break the tab-separated layout.
+func sanitizeField(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '\t', '\n', '\r':
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
+	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/security"
 )
@@ -19,6 +20,16 @@ import (
 // SecurityAdapter adapts the security package to our API auth interface
 type SecurityAdapter struct {
 	OAuth2Server *security.OAuth2Server
+
+	// apiKeys verifies API keys presented as bearer tokens; nil when API keys
+	// are unavailable.
+	apiKeys *APIKeyStore
+}
+
+// SetAPIKeyStore lets IsAuthenticated accept API keys. It must be called
+// before the adapter serves requests.
+func (a *SecurityAdapter) SetAPIKeyStore(store *APIKeyStore) {
+	a.apiKeys = store
 }
 
 // NewSecurityAdapter creates a new adapter for the security package
@@ -119,6 +130,29 @@ func (a *SecurityAdapter) GetAuthMethod(c echo.Context) AuthMethod {
 	return AuthMethodNone // Use None for explicitly no authentication
 }
 
+// GetIdentity returns the user and role behind an authenticated request.
+// Clients that do not need to authenticate (auth disabled or subnet bypass)
+// are admins. A request without a valid token or session yields the zero
+// Identity, whose empty role allows nothing.
+func (a *SecurityAdapter) GetIdentity(c echo.Context) security.Identity {
+	if !a.IsAuthRequired(c) {
+		return security.Identity{Username: a.GetUsername(c), Role: security.RoleAdmin}
+	}
+
+	if authHeader := c.Request().Header.Get("Authorization"); authHeader != "" {
+		parts := strings.Fields(authHeader)
+		if len(parts) == 2 && strings.EqualFold(parts[0], "bearer") {
+			if id, err := a.OAuth2Server.TokenIdentity(parts[1]); err == nil {
+				return id
+			}
+		}
+		return security.Identity{}
+	}
+
+	id, _ := a.OAuth2Server.SessionIdentity(c)
+	return id
+}
+
 // AuthMethodFromString converts a string representation to its AuthMethod constant.
 // Returns AuthMethodUnknown if the string does not match any known method.
 func AuthMethodFromString(s string) AuthMethod {
@@ -151,9 +185,11 @@ func (a *SecurityAdapter) ValidateToken(token string) error {
 }
 
 // AuthenticateBasic handles basic authentication with username/password.
-// NOTE: This application does not support multiple user accounts or authorization levels.
-// Basic authentication relies on a single, fixed username/password combination
-// configured in settings (Security.BasicAuth.ClientID and Security.BasicAuth.Password).
+// The username/password combination configured in settings
+// (Security.BasicAuth.ClientID and Security.BasicAuth.Password) is the station
+// admin. When the user store is available, other credentials are checked
+// against the user accounts and the resulting session carries that account's
+// role.
 //
 // Username validation behavior:
 // - If ClientID is configured (non-empty): username MUST match ClientID
@@ -189,11 +225,23 @@ func (a *SecurityAdapter) AuthenticateBasic(c echo.Context, username, password s
 	userMatch := a.validateUsername(username, storedClientID)
 	passMatch := a.validatePassword(password, storedPassword)
 
-	if !userMatch || !passMatch {
-		return "", a.handleAuthFailure(userMatch, username)
+	if userMatch && passMatch {
+		return a.generateAuthCodeOnSuccess(username, false)
 	}
 
-	return a.generateAuthCodeOnSuccess(username)
+	if users := a.OAuth2Server.UserDirectory(); users != nil {
+		id, err := users.Authenticate(c.Request().Context(), username, password)
+		if err == nil {
+			return a.generateAuthCodeOnSuccess(id.Username, true)
+		}
+		if !errors.Is(err, security.ErrInvalidUserCredentials) {
+			a.log().Error("User account lookup failed during basic auth",
+				logger.Username(username),
+				logger.Error(err))
+		}
+	}
+
+	return "", a.handleAuthFailure(userMatch, username)
 }
 
 // validateBasicAuthEnabled checks if basic auth is enabled. The enabled flag is
@@ -249,11 +297,14 @@ func (a *SecurityAdapter) handleAuthFailure(userMatch bool, username string) err
 }
 
 // generateAuthCodeOnSuccess generates an auth code after successful authentication.
-func (a *SecurityAdapter) generateAuthCodeOnSuccess(username string) (string, error) {
+// account marks username as a user-store account rather than the configured admin.
+func (a *SecurityAdapter) generateAuthCodeOnSuccess(username string, account bool) (string, error) {
 	log := a.log()
-	log.Info("Credentials validated successfully", logger.Username(username))
+	log.Info("Credentials validated successfully",
+		logger.Username(username),
+		logger.Bool("user_account", account))
 
-	authCode, err := a.OAuth2Server.GenerateAuthCode()
+	authCode, err := a.OAuth2Server.GenerateAuthCodeFor(username, account)
 	if err != nil {
 		log.Error("Failed to generate auth code during basic auth",
 			logger.Username(username),
@@ -411,10 +462,18 @@ func (a *SecurityAdapter) IsAuthenticated(c echo.Context) bool {
 		return true // Bypassed auth is treated as authenticated for data access
 	}
 
+	// The auth middleware has already authenticated this request
+	if authenticated, _ := c.Get(CtxKeyIsAuthenticated).(bool); authenticated {
+		return true
+	}
+
 	// Try token auth from Authorization header
 	if authHeader := c.Request().Header.Get("Authorization"); authHeader != "" {
 		parts := strings.Fields(authHeader)
 		if len(parts) == 2 && strings.EqualFold(parts[0], "bearer") {
+			if IsAPIKey(parts[1]) {
+				return a.authenticateAPIKey(c, parts[1])
+			}
 			if a.ValidateToken(parts[1]) == nil {
 				return true
 			}
@@ -424,3 +483,22 @@ func (a *SecurityAdapter) IsAuthenticated(c echo.Context) bool {
 	// Try session auth
 	return a.CheckAccess(c) == nil
 }
+
+// authenticateAPIKey verifies an API key bearer token and records the key as
+// the request principal, as the auth middleware does.
+func (a *SecurityAdapter) authenticateAPIKey(c echo.Context, token string) bool {
+	if a.apiKeys == nil {
+		return false
+	}
+	key, err := a.apiKeys.Verify(c.Request().Context(), token)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidAPIKey) {
+			a.log().Error("API key verification failed",
+				logger.String("path", c.Request().URL.Path),
+				logger.Error(err))
+		}
+		return false
+	}
+	setAPIKeyPrincipal(c, key)
+	return true
+}
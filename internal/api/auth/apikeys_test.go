@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/security"
+	"github.com/tphakala/birdnet-go/internal/security/securitytest"
+)
+
+// newAPIKeyStore creates a store over a temporary SQLite database.
+func newAPIKeyStore(t *testing.T) (*APIKeyStore, repository.APIKeyRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "keys.db")), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+	require.NoError(t, db.AutoMigrate(&entities.APIKey{}))
+
+	repo := repository.NewAPIKeyRepository(db, nil)
+	return NewAPIKeyStore(repo), repo
+}
+
+func TestAPIKeyStore_CreateAndVerify(t *testing.T) {
+	t.Parallel()
+	store, repo := newAPIKeyStore(t)
+	ctx := t.Context()
+
+	key, entity, err := store.Create(ctx, "grafana", []security.Scope{security.ScopeDetectionsRead}, nil, "admin")
+	require.NoError(t, err)
+	assert.True(t, IsAPIKey(key))
+	assert.True(t, strings.HasPrefix(key, entity.Prefix))
+	assert.NotContains(t, entity.KeyHash, key, "the key must not be stored in clear text")
+
+	got, err := store.Verify(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ID, got.ID)
+	assert.Equal(t, []security.Scope{security.ScopeDetectionsRead}, APIKeyScopes(got))
+	assert.Equal(t, "apikey:"+strconv.FormatUint(uint64(entity.ID), 10), APIKeyUsername(got))
+
+	stored, err := repo.GetByID(ctx, entity.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.LastUsedAt, "use of the key should be recorded")
+
+	_, err = store.Verify(ctx, key+"x")
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+	_, err = store.Verify(ctx, "not-a-key")
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	require.NoError(t, store.Revoke(ctx, entity.ID))
+	_, err = store.Verify(ctx, key)
+	require.ErrorIs(t, err, ErrInvalidAPIKey, "revoked keys must be rejected")
+}
+
+// TestAPIKeyUsername_FitsUsernameColumns checks that a key with the longest
+// allowed name still yields a username that fits the 100 character
+// reviewed_by and locked_by columns.
+func TestAPIKeyUsername_FitsUsernameColumns(t *testing.T) {
+	t.Parallel()
+	key := &entities.APIKey{ID: math.MaxUint32, Name: strings.Repeat("n", 100)}
+	assert.LessOrEqual(t, len(APIKeyUsername(key)), 100)
+}
+
+func TestAPIKeyStore_Expiry(t *testing.T) {
+	t.Parallel()
+	store, _ := newAPIKeyStore(t)
+	ctx := t.Context()
+
+	expires := time.Now().Add(time.Hour)
+	key, _, err := store.Create(ctx, "script", []security.Scope{security.ScopeControl}, &expires, "")
+	require.NoError(t, err)
+
+	_, err = store.Verify(ctx, key)
+	require.NoError(t, err)
+
+	store.now = func() time.Time { return expires.Add(time.Second) }
+	_, err = store.Verify(ctx, key)
+	require.ErrorIs(t, err, ErrInvalidAPIKey, "expired keys must be rejected")
+}
+
+// TestMiddleware_APIKeyScopes checks API keys against role and scope
+// restricted middleware.
+//
+// Not parallel: NewOAuth2ServerForTesting publishes the global settings.
+func TestMiddleware_APIKeyScopes(t *testing.T) {
+	settings := &conf.Settings{}
+	settings.Security.BasicAuth.Enabled = true
+	settings.Security.BasicAuth.ClientID = "admin"
+	settings.Security.BasicAuth.Password = "correct-horse"
+	server := securitytest.NewOAuth2ServerForTesting(t, settings)
+
+	store, _ := newAPIKeyStore(t)
+	mw := NewMiddleware(NewSecurityAdapter(server))
+	mw.SetAPIKeyStore(store)
+	e := echo.New()
+
+	readKey, _, err := store.Create(t.Context(), "grafana", []security.Scope{security.ScopeDetectionsRead}, nil, "")
+	require.NoError(t, err)
+	controlKey, controlEntity, err := store.Create(t.Context(), "script", []security.Scope{security.ScopeControl, security.ScopeReviewsWrite}, nil, "")
+	require.NoError(t, err)
+
+	call := func(key string, guard echo.MiddlewareFunc) (status int, username string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/detections", http.NoBody)
+		req.Header.Set("Authorization", "Bearer "+key)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		err := guard(func(c echo.Context) error {
+			username = UsernameFromContext(c)
+			return c.NoContent(http.StatusNoContent)
+		})(c)
+		require.NoError(t, err)
+		return rec.Code, username
+	}
+
+	tests := []struct {
+		name  string
+		key   string
+		guard echo.MiddlewareFunc
+		want  int
+	}{
+		{"read key on viewer route", readKey, mw.RequireRole(security.RoleViewer), http.StatusNoContent},
+		{"read key on review route", readKey, mw.RequireRole(security.RoleReviewer), http.StatusForbidden},
+		{"read key on control route", readKey, mw.RequireScope(security.ScopeControl), http.StatusForbidden},
+		{"control key on control route", controlKey, mw.RequireScope(security.ScopeControl), http.StatusNoContent},
+		{"control key on review route", controlKey, mw.RequireRole(security.RoleReviewer), http.StatusNoContent},
+		{"control key on settings route", controlKey, mw.RequireScope(security.ScopeSettings), http.StatusForbidden},
+		{"control key on viewer route", controlKey, mw.RequireRole(security.RoleViewer), http.StatusForbidden},
+		{"keys never reach plain admin routes", controlKey, mw.RequireRole(security.RoleAdmin), http.StatusForbidden},
+		{"unknown key", APIKeyPrefix + "unknown", mw.RequireRole(security.RoleViewer), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _ := call(tt.key, tt.guard)
+			assert.Equal(t, tt.want, status)
+		})
+	}
+
+	_, username := call(controlKey, mw.RequireScope(security.ScopeControl))
+	assert.Equal(t, "apikey:"+strconv.FormatUint(uint64(controlEntity.ID), 10), username)
+
+	require.NoError(t, store.Revoke(t.Context(), controlEntity.ID))
+	status, _ := call(controlKey, mw.RequireScope(security.ScopeControl))
+	assert.Equal(t, http.StatusUnauthorized, status, "revocation takes effect immediately")
+}
+
+// TestSecurityAdapter_IsAuthenticatedAcceptsAPIKeys checks that handlers
+// gating on IsAuthenticated accept API keys once the store is configured.
+//
+// Not parallel: NewOAuth2ServerForTesting publishes the global settings.
+func TestSecurityAdapter_IsAuthenticatedAcceptsAPIKeys(t *testing.T) {
+	settings := &conf.Settings{}
+	settings.Security.BasicAuth.Enabled = true
+	settings.Security.BasicAuth.Password = "correct-horse"
+	server := securitytest.NewOAuth2ServerForTesting(t, settings)
+
+	store, _ := newAPIKeyStore(t)
+	key, entity, err := store.Create(t.Context(), "grafana", []security.Scope{security.ScopeDetectionsRead}, nil, "")
+	require.NoError(t, err)
+
+	e := echo.New()
+	newContext := func(token string) echo.Context {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/audio/1", http.NoBody)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return e.NewContext(req, httptest.NewRecorder())
+	}
+
+	adapter := NewSecurityAdapter(server)
+	assert.False(t, adapter.IsAuthenticated(newContext(key)), "API keys need the store")
+
+	adapter.SetAPIKeyStore(store)
+	c := newContext(key)
+	assert.True(t, adapter.IsAuthenticated(c))
+	assert.Equal(t, "apikey:"+strconv.FormatUint(uint64(entity.ID), 10), UsernameFromContext(c))
+	assert.False(t, adapter.IsAuthenticated(newContext(APIKeyPrefix+"unknown")))
+
+	c = newContext("not-checked")
+	c.Set(CtxKeyIsAuthenticated, true)
+	assert.True(t, adapter.IsAuthenticated(c), "a principal set by the middleware is accepted")
+}
@@ -0,0 +1,133 @@
+// internal/api/auth/directory.go
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/security"
+)
+
+// MinPasswordLength is the shortest password accepted for a user account.
+const MinPasswordLength = 8
+
+// ErrPasswordTooShort is returned by HashPassword for passwords shorter than
+// MinPasswordLength.
+var ErrPasswordTooShort = errors.NewStd("password too short")
+
+// dummyPasswordHash is compared against when an account does not exist so a
+// failed login takes the same time whether or not the username is known.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("birdnet-go-dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// HashPassword returns the bcrypt hash stored for a user account password.
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.New(err).
+			Component("auth").
+			Category(errors.CategorySystem).
+			Context("operation", "hash_password").
+			Build()
+	}
+	return string(hash), nil
+}
+
+// NormalizeUsername returns the canonical form of a username. Usernames are
+// case-insensitive so "Anna" and "anna" cannot be two different accounts.
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// UserDirectory implements security.UserDirectory on top of the v2 user
+// account repository.
+type UserDirectory struct {
+	repo repository.UserAccountRepository
+}
+
+// NewUserDirectory creates a UserDirectory backed by repo.
+func NewUserDirectory(repo repository.UserAccountRepository) *UserDirectory {
+	return &UserDirectory{repo: repo}
+}
+
+// Authenticate verifies a username and password against the stored accounts.
+func (d *UserDirectory) Authenticate(ctx context.Context, username, password string) (security.Identity, error) {
+	account, err := d.repo.GetByUsername(ctx, NormalizeUsername(username))
+	if err != nil {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		if !errors.Is(err, repository.ErrUserAccountNotFound) {
+			return security.Identity{}, err
+		}
+		return security.Identity{}, security.ErrInvalidUserCredentials
+	}
+	if account.PasswordHash == "" {
+		// Accounts without a password can only sign in through OAuth.
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return security.Identity{}, security.ErrInvalidUserCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(password)) != nil {
+		return security.Identity{}, security.ErrInvalidUserCredentials
+	}
+
+	id, ok := accountIdentity(account)
+	if !ok {
+		return security.Identity{}, security.ErrInvalidUserCredentials
+	}
+	d.touch(ctx, account)
+	return id, nil
+}
+
+// LookupUser returns the current identity of an enabled account.
+func (d *UserDirectory) LookupUser(ctx context.Context, username string) (security.Identity, bool) {
+	account, err := d.repo.GetByUsername(ctx, NormalizeUsername(username))
+	if err != nil {
+		return security.Identity{}, false
+	}
+	return accountIdentity(account)
+}
+
+// LookupExternal returns the enabled account linked to any of the given
+// OAuth identities. It runs on every request of an OAuth session, so unlike
+// Authenticate it does not record a login.
+func (d *UserDirectory) LookupExternal(ctx context.Context, externalIDs ...string) (security.Identity, bool) {
+	account, err := d.repo.GetByExternalID(ctx, externalIDs...)
+	if err != nil {
+		return security.Identity{}, false
+	}
+	return accountIdentity(account)
+}
+
+// touch records the sign-in time; failures are logged and otherwise ignored.
+func (d *UserDirectory) touch(ctx context.Context, account *entities.UserAccount) {
+	if err := d.repo.TouchLastLogin(ctx, account.ID, time.Now()); err != nil {
+		GetLogger().Warn("Failed to record user login",
+			logger.Username(account.Username),
+			logger.Error(err))
+	}
+}
+
+// accountIdentity converts an account into an identity. Disabled accounts
+// and accounts with an unknown role are rejected.
+func accountIdentity(account *entities.UserAccount) (security.Identity, bool) {
+	if account.Disabled {
+		return security.Identity{}, false
+	}
+	role := security.Role(account.Role)
+	if !role.Valid() {
+		return security.Identity{}, false
+	}
+	return security.Identity{Username: account.Username, Role: role}, true
+}
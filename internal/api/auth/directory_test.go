@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/security"
+	"github.com/tphakala/birdnet-go/internal/security/securitytest"
+)
+
+// newUserDirectory creates a directory over a temporary SQLite user store
+// seeded with the given username/role pairs, all with password "volunteer-pw".
+func newUserDirectory(t *testing.T, accounts map[string]security.Role) (*UserDirectory, repository.UserAccountRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "users.db")), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+	require.NoError(t, db.AutoMigrate(&entities.UserAccount{}))
+
+	repo := repository.NewUserAccountRepository(db, nil)
+	hash, err := HashPassword("volunteer-pw")
+	require.NoError(t, err)
+	for username, role := range accounts {
+		require.NoError(t, repo.Create(t.Context(), &entities.UserAccount{
+			Username:     username,
+			PasswordHash: hash,
+			Role:         string(role),
+		}))
+	}
+	return NewUserDirectory(repo), repo
+}
+
+func TestUserDirectory_Authenticate(t *testing.T) {
+	t.Parallel()
+	dir, repo := newUserDirectory(t, map[string]security.Role{"anna": security.RoleReviewer})
+	ctx := t.Context()
+
+	id, err := dir.Authenticate(ctx, "Anna", "volunteer-pw")
+	require.NoError(t, err)
+	assert.Equal(t, security.Identity{Username: "anna", Role: security.RoleReviewer}, id)
+
+	account, err := repo.GetByUsername(ctx, "anna")
+	require.NoError(t, err)
+	assert.NotNil(t, account.LastLoginAt, "successful login should be recorded")
+
+	_, err = dir.Authenticate(ctx, "anna", "wrong-password")
+	require.ErrorIs(t, err, security.ErrInvalidUserCredentials)
+	_, err = dir.Authenticate(ctx, "bob", "volunteer-pw")
+	require.ErrorIs(t, err, security.ErrInvalidUserCredentials)
+
+	account.Disabled = true
+	require.NoError(t, repo.Update(ctx, account))
+	_, err = dir.Authenticate(ctx, "anna", "volunteer-pw")
+	require.ErrorIs(t, err, security.ErrInvalidUserCredentials)
+	_, ok := dir.LookupUser(ctx, "anna")
+	assert.False(t, ok, "disabled accounts must not resolve")
+}
+
+func TestHashPassword_TooShort(t *testing.T) {
+	t.Parallel()
+	_, err := HashPassword("short")
+	require.ErrorIs(t, err, ErrPasswordTooShort)
+}
+
+// TestRequireRole signs in through basic auth as the configured admin and as
+// user accounts, then checks each token against role-restricted middleware.
+//
+// Not parallel: NewOAuth2ServerForTesting publishes the global settings.
+func TestRequireRole(t *testing.T) {
+	settings := &conf.Settings{}
+	settings.Security.BasicAuth.Enabled = true
+	settings.Security.BasicAuth.ClientID = "admin"
+	settings.Security.BasicAuth.Password = "correct-horse"
+	settings.Security.BasicAuth.AuthCodeExp = 10 * time.Minute
+	settings.Security.BasicAuth.AccessTokenExp = time.Hour
+	server := securitytest.NewOAuth2ServerForTesting(t, settings)
+	dir, _ := newUserDirectory(t, map[string]security.Role{
+		"victor": security.RoleViewer,
+		"rita":   security.RoleReviewer,
+	})
+	server.SetUserDirectory(dir)
+
+	adapter := NewSecurityAdapter(server)
+	mw := NewMiddleware(adapter)
+	e := echo.New()
+
+	login := func(username, password string) string {
+		t.Helper()
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/v2/auth/login", http.NoBody), httptest.NewRecorder())
+		code, err := adapter.AuthenticateBasic(c, username, password)
+		require.NoError(t, err)
+		token, err := adapter.ExchangeAuthCode(t.Context(), code)
+		require.NoError(t, err)
+		return token
+	}
+
+	call := func(token string, role security.Role) (status int, username string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/detections/1/review", http.NoBody)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		err := mw.RequireRole(role)(func(c echo.Context) error {
+			username = UsernameFromContext(c)
+			return c.NoContent(http.StatusNoContent)
+		})(c)
+		require.NoError(t, err)
+		return rec.Code, username
+	}
+
+	adminToken := login("admin", "correct-horse")
+	viewerToken := login("victor", "volunteer-pw")
+	reviewerToken := login("rita", "volunteer-pw")
+
+	tests := []struct {
+		name     string
+		token    string
+		role     security.Role
+		want     int
+		wantUser string
+	}{
+		{"admin on admin route", adminToken, security.RoleAdmin, http.StatusNoContent, "admin"},
+		{"reviewer on review route", reviewerToken, security.RoleReviewer, http.StatusNoContent, "rita"},
+		{"reviewer on admin route", reviewerToken, security.RoleAdmin, http.StatusForbidden, ""},
+		{"viewer on viewer route", viewerToken, security.RoleViewer, http.StatusNoContent, "victor"},
+		{"viewer on review route", viewerToken, security.RoleReviewer, http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, username := call(tt.token, tt.role)
+			assert.Equal(t, tt.want, status)
+			assert.Equal(t, tt.wantUser, username)
+		})
+	}
+
+	_, err := adapter.AuthenticateBasic(e.NewContext(httptest.NewRequest(http.MethodPost, "/", http.NoBody), httptest.NewRecorder()), "rita", "wrong")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
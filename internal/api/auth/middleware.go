@@ -4,9 +4,11 @@ package auth
 import (
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/security"
 )
@@ -24,11 +26,26 @@ const (
 	CtxKeyAuthMethod = "auth:authMethod"
 	// CtxKeyUsername contains the authenticated user's username (if available).
 	CtxKeyUsername = "auth:username"
+	// CtxKeyRole contains the authenticated user's security.Role.
+	CtxKeyRole = "auth:role"
+	// CtxKeyScopes contains the []security.Scope of an API key. It is only
+	// set for requests authenticated with an API key.
+	CtxKeyScopes = "auth:scopes"
 )
 
 // Middleware provides authentication middleware with the Service
 type Middleware struct {
 	AuthService Service
+
+	// apiKeys verifies API keys; nil when the v2 datastore is unavailable,
+	// in which case API keys are rejected like any invalid token.
+	apiKeys *APIKeyStore
+}
+
+// SetAPIKeyStore enables API key authentication. It must be called before
+// the middleware serves requests.
+func (m *Middleware) SetAPIKeyStore(store *APIKeyStore) {
+	m.apiKeys = store
 }
 
 // NewMiddleware creates a new auth middleware
@@ -102,16 +119,14 @@ func (m *Middleware) shouldBypassAuth(c echo.Context) bool {
 			logger.String("path", c.Request().URL.Path))
 		c.Set(CtxKeyIsAuthenticated, true) // Bypassed = effectively authenticated
 		c.Set(CtxKeyAuthMethod, AuthMethodNone)
+		c.Set(CtxKeyRole, security.RoleAdmin)
 		return true
 	}
 	return false
 }
 
 // tryTokenAuth attempts to authenticate using a Bearer token from the Authorization header.
-// Note: Token-based authentication does not currently provide username information.
-// The AccessToken structure only contains the token and expiry, not user identity.
-// This is a known limitation - downstream handlers should not rely on username
-// being populated for token-authenticated requests.
+// Tokens issued to the configured admin credentials may carry an empty username.
 func (m *Middleware) tryTokenAuth(c echo.Context) authResult {
 	authHeader := c.Request().Header.Get("Authorization")
 	if authHeader == "" {
@@ -131,6 +146,9 @@ func (m *Middleware) tryTokenAuth(c echo.Context) authResult {
 	}
 
 	token := strings.TrimSpace(parts[1])
+	if IsAPIKey(token) {
+		return m.tryAPIKeyAuth(c, token, path, ip)
+	}
 	if err := m.AuthService.ValidateToken(token); err != nil {
 		return m.handleInvalidToken(c, path, ip)
 	}
@@ -138,15 +156,39 @@ func (m *Middleware) tryTokenAuth(c echo.Context) authResult {
 	log.Debug("Token authentication successful",
 		logger.String("path", path),
 		logger.String("ip", ip))
+	id := m.AuthService.GetIdentity(c)
 	c.Set(CtxKeyIsAuthenticated, true)
-	// Note: Username is not available for token auth as tokens don't store user identity.
-	// The current AccessToken struct only contains token string and expiry.
-	// TODO: Consider adding username to AccessToken struct to support this use case.
-	c.Set(CtxKeyUsername, "")
+	c.Set(CtxKeyUsername, id.Username)
+	c.Set(CtxKeyRole, id.Role)
 	c.Set(CtxKeyAuthMethod, AuthMethodToken)
 	return authResult{handled: true, aborted: false, err: nil}
 }
 
+// tryAPIKeyAuth authenticates a request carrying an API key. The key's scopes
+// replace the role check; see RequireRole.
+func (m *Middleware) tryAPIKeyAuth(c echo.Context, token, path, ip string) authResult {
+	if m.apiKeys == nil {
+		return m.handleInvalidToken(c, path, ip)
+	}
+	key, err := m.apiKeys.Verify(c.Request().Context(), token)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidAPIKey) {
+			m.log().Error("API key verification failed",
+				logger.String("path", path),
+				logger.String("ip", ip),
+				logger.Error(err))
+		}
+		return m.handleInvalidToken(c, path, ip)
+	}
+
+	m.log().Debug("API key authentication successful",
+		logger.String("path", path),
+		logger.String("ip", ip),
+		logger.Uint64("key_id", uint64(key.ID)))
+	setAPIKeyPrincipal(c, key)
+	return authResult{handled: true, aborted: false, err: nil}
+}
+
 // handleMalformedAuthHeader returns an error response for malformed Authorization headers.
 func (m *Middleware) handleMalformedAuthHeader(c echo.Context, path, ip string) authResult {
 	m.log().Warn("Malformed Authorization header",
@@ -194,12 +236,84 @@ func (m *Middleware) trySessionAuth(c echo.Context) bool {
 	log.Debug("Session authentication successful",
 		logger.String("path", path),
 		logger.String("ip", ip))
+	id := m.AuthService.GetIdentity(c)
+	username := id.Username
+	if username == "" {
+		username = m.AuthService.GetUsername(c)
+	}
 	c.Set(CtxKeyIsAuthenticated, true)
 	c.Set(CtxKeyAuthMethod, m.AuthService.GetAuthMethod(c))
-	c.Set(CtxKeyUsername, m.AuthService.GetUsername(c))
+	c.Set(CtxKeyUsername, username)
+	c.Set(CtxKeyRole, id.Role)
 	return true
 }
 
+// RequireRole returns middleware that authenticates the request like
+// Authenticate and then rejects users whose role does not include role.
+// API keys need the scope matching the role (security.ScopeForRole); admin
+// routes are closed to API keys unless they are guarded by RequireScope.
+func (m *Middleware) RequireRole(role security.Role) echo.MiddlewareFunc {
+	return m.require(role, security.ScopeForRole(role))
+}
+
+// RequireScope returns middleware for routes an API key may reach with scope.
+// Signed-in users need the role implied by the scope.
+func (m *Middleware) RequireScope(scope security.Scope) echo.MiddlewareFunc {
+	return m.require(scope.Role(), scope)
+}
+
+// require authenticates the request and checks the role of a user, or the
+// scopes of an API key.
+func (m *Middleware) require(role security.Role, scope security.Scope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return m.Authenticate(func(c echo.Context) error {
+			allowed := RoleFromContext(c).Allows(role)
+			if scopes, isKey := c.Get(CtxKeyScopes).([]security.Scope); isKey {
+				allowed = scope != "" && slices.Contains(scopes, scope)
+			}
+			if !allowed {
+				m.log().Warn("Insufficient role for request",
+					logger.String("path", c.Request().URL.Path),
+					logger.String("ip", c.RealIP()),
+					logger.String("required_role", string(role)),
+					logger.String("required_scope", string(scope)))
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "Insufficient permissions",
+				})
+			}
+			return next(c)
+		})
+	}
+}
+
+// RoleFromContext returns the role stored by the authentication middleware,
+// or an empty role when the request was not authenticated.
+func RoleFromContext(c echo.Context) security.Role {
+	role, _ := c.Get(CtxKeyRole).(security.Role)
+	return role
+}
+
+// ScopesFromContext returns the scopes of the API key that authenticated the
+// request, or nil for any other authentication method.
+func ScopesFromContext(c echo.Context) []security.Scope {
+	scopes, _ := c.Get(CtxKeyScopes).([]security.Scope)
+	return scopes
+}
+
+// IsAPIKeyRequest reports whether the request was authenticated with an API
+// key.
+func IsAPIKeyRequest(c echo.Context) bool {
+	method, _ := c.Get(CtxKeyAuthMethod).(AuthMethod)
+	return method == AuthMethodAPIKey
+}
+
+// UsernameFromContext returns the username stored by the authentication
+// middleware, or an empty string when it is not known.
+func UsernameFromContext(c echo.Context) string {
+	username, _ := c.Get(CtxKeyUsername).(string)
+	return username
+}
+
 // log returns the auth package logger.
 func (m *Middleware) log() logger.Logger {
 	return GetLogger()
@@ -7,6 +7,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/security"
 )
 
 // GetLogger returns the auth package logger. Authentication events (basic-auth
@@ -61,6 +62,9 @@ type Service interface {
 	// GetAuthMethod returns the authentication method used as a defined constant.
 	GetAuthMethod(c echo.Context) AuthMethod
 
+	// GetIdentity returns the user and role behind an authenticated request.
+	GetIdentity(c echo.Context) security.Identity
+
 	// ValidateToken checks if a bearer token is valid.
 	// Returns nil on success, or ErrInvalidToken on failure.
 	ValidateToken(token string) error
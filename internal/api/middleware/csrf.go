@@ -153,6 +153,12 @@ func DefaultCSRFSkipper(c echo.Context) bool {
 		return true
 	}
 
+	// Skip for local weather station uploads (stations cannot fetch a CSRF
+	// token; uploads are checked against the station PASSKEY instead)
+	if path == "/api/v2/weather/station" {
+		return true
+	}
+
 	return false
 }
 
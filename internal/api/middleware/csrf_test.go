@@ -170,6 +170,10 @@ func TestDefaultCSRFSkipper(t *testing.T) {
 		// Logout — skipped (must work even with expired CSRF tokens)
 		{"POST auth logout", http.MethodPost, "/api/v2/auth/logout", true},
 
+		// Weather station uploads — skipped (stations authenticate with a PASSKEY)
+		{"POST weather station", http.MethodPost, "/api/v2/weather/station", true},
+		{"POST weather latest", http.MethodPost, "/api/v2/weather/latest", false},
+
 		// Regular API paths — never skipped
 		{"GET detections", http.MethodGet, "/api/v2/detections/1", false},
 		{"POST settings", http.MethodPost, "/api/v2/settings", false},
@@ -29,6 +29,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
 	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/health"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
@@ -68,8 +69,10 @@ type Server struct {
 	metrics        *observability.Metrics
 
 	// Auth components (owned by server, injected into controllers)
-	authService    auth.Service
-	authMiddleware echo.MiddlewareFunc
+	authService     auth.Service
+	authMiddleware  echo.MiddlewareFunc
+	roleMiddleware  func(security.Role) echo.MiddlewareFunc
+	scopeMiddleware func(security.Scope) echo.MiddlewareFunc
 
 	// Audio engine (unified audio subsystem)
 	engine *engine.AudioEngine
@@ -340,11 +343,25 @@ func (s *Server) initAuth() {
 	}
 
 	// Create auth service adapter (uses centralized logger internally)
-	s.authService = auth.NewSecurityAdapter(s.oauth2Server)
+	adapter := auth.NewSecurityAdapter(s.oauth2Server)
+	s.authService = adapter
 
 	// Create auth middleware (uses centralized logger internally)
-	authMw := auth.NewMiddleware(s.authService)
-	s.authMiddleware = authMw.Authenticate
+	authMw := auth.NewMiddleware(adapter)
+	s.authMiddleware = authMw.RequireRole(security.RoleAdmin)
+	s.roleMiddleware = authMw.RequireRole
+	s.scopeMiddleware = authMw.RequireScope
+
+	// Station user accounts and API keys live in the v2 datastore; without it
+	// only the configured admin identity can sign in.
+	if s.v2Manager != nil && datastoreV2.IsEnhancedDatabase() {
+		repo := repository.NewUserAccountRepository(s.v2Manager.DB(), nil)
+		s.oauth2Server.SetUserDirectory(auth.NewUserDirectory(repo))
+		apiKeys := auth.NewAPIKeyStore(repository.NewAPIKeyRepository(s.v2Manager.DB(), nil))
+		authMw.SetAPIKeyStore(apiKeys)
+		adapter.SetAPIKeyStore(apiKeys)
+		s.slogger.Info("User accounts and API keys enabled")
+	}
 
 	s.slogger.Info("Auth middleware initialized at server level")
 }
@@ -510,6 +527,8 @@ func (s *Server) setupRoutes() error {
 	// Build the list of v2 controller options.
 	v2Opts := []apiv2.Option{
 		apiv2.WithAuthMiddleware(s.authMiddleware),
+		apiv2.WithRoleMiddleware(s.roleMiddleware),
+		apiv2.WithScopeMiddleware(s.scopeMiddleware),
 		apiv2.WithAuthService(s.authService),
 		apiv2.WithV2Manager(s.v2Manager),
 		apiv2.WithMetricsStore(observability.NewMemoryStore(apiv2.MetricsHistoryMaxPoints)),
@@ -57,6 +57,10 @@ type Handler struct {
 	alertRuleRepo  repository.AlertRuleRepository
 	alertEngine    *alerting.Engine
 	alertEngineErr error
+
+	// controlFunc runs the internal controls of control actions. It is
+	// injected by the facade from the control domain before RegisterRoutes.
+	controlFunc alerting.ControlFunc
 }
 
 // New builds an alerts Handler around the shared core. The alert-rule repository
@@ -65,6 +69,26 @@ func New(core *apicore.Core) *Handler {
 	return &Handler{Core: core}
 }
 
+// SetControlFunc injects the function that runs control actions of alert
+// rules. It must be called before RegisterRoutes starts the engine.
+func (c *Handler) SetControlFunc(fn alerting.ControlFunc) {
+	c.controlFunc = fn
+}
+
+// mqttPublisher returns the processor's current MQTT client, or nil while
+// MQTT is disabled. It is resolved per action because the client is created
+// and replaced after the routes are registered.
+func (c *Handler) mqttPublisher() alerting.MQTTPublisher {
+	if c.Processor == nil {
+		return nil
+	}
+	client := c.Processor.GetMQTTClient()
+	if client == nil {
+		return nil
+	}
+	return client
+}
+
 // RegisterRoutes registers alert rule API endpoints and starts the alerting engine.
 //
 // The routes register UNCONDITIONALLY so the documented behavior holds on every
@@ -118,7 +142,9 @@ func (c *Handler) RegisterRoutes(g *echo.Group) {
 	// Initialize the alerting engine - seeds default rules and starts event processing
 	alertTelemetry := alerting.NewAlertingTelemetry()
 	eventBus := alerting.NewAlertEventBus(alertTelemetry)
-	engine, err := alerting.Initialize(c.alertRuleRepo, eventBus, apicore.GetLogger(), alertTelemetry)
+	engine, err := alerting.Initialize(c.alertRuleRepo, eventBus, apicore.GetLogger(), alertTelemetry,
+		alerting.WithMQTTPublisher(c.mqttPublisher),
+		alerting.WithControlFunc(c.controlFunc))
 	if err != nil {
 		apicore.GetLogger().Error("failed to initialize alerting engine", logger.Error(err))
 		eventBus.Stop() // Stop the bus goroutine since Initialize didn't set it as global
@@ -197,6 +223,16 @@ func validateEscalationSteps(steps []float64) error {
 	return nil
 }
 
+// validateActions checks the fields of the rule's automation actions.
+func validateActions(actions []entities.AlertAction) error {
+	for i := range actions {
+		if err := alerting.ValidateAction(&actions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // bindAndValidateAlertRule binds and validates the alert rule from the request body.
 // On validation failure, it writes the error response and returns nil with the written error.
 // Callers should check: if rule == nil { return err }
@@ -214,6 +250,12 @@ func (c *Handler) bindAndValidateAlertRule(ctx echo.Context) (*entities.AlertRul
 	if err := validateEscalationSteps(rule.EscalationSteps); err != nil {
 		return nil, c.HandleErrorWithKey(ctx, err, err.Error(), http.StatusBadRequest, notification.MsgErrAlertInvalidEscalation, nil)
 	}
+	if err := alerting.ValidateConditions(&rule); err != nil {
+		return nil, c.HandleErrorWithKey(ctx, err, err.Error(), http.StatusBadRequest, notification.MsgErrAlertInvalidConditions, nil)
+	}
+	if err := validateActions(rule.Actions); err != nil {
+		return nil, c.HandleErrorWithKey(ctx, err, err.Error(), http.StatusBadRequest, notification.MsgErrAlertInvalidAction, nil)
+	}
 	return &rule, nil
 }
 
@@ -597,6 +639,16 @@ func (c *Handler) ImportAlertRules(ctx echo.Context) error {
 				logger.String("name", rule.Name), logger.Error(err))
 			continue
 		}
+		if err := alerting.ValidateConditions(rule); err != nil {
+			c.LogErrorIfEnabled("skipping imported rule with invalid conditions",
+				logger.String("name", rule.Name), logger.Error(err))
+			continue
+		}
+		if err := validateActions(rule.Actions); err != nil {
+			c.LogErrorIfEnabled("skipping imported rule with invalid actions",
+				logger.String("name", rule.Name), logger.Error(err))
+			continue
+		}
 
 		if err := c.alertRuleRepo.CreateRule(reqCtx, rule); err != nil {
 			c.LogErrorIfEnabled("failed to import rule",
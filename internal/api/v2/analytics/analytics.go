@@ -1896,10 +1896,12 @@ func (c *Handler) GetSpeciesAccumulation(ctx echo.Context) error {
 
 // analyticsSourceItem is one audio source on the analytics source/mic filter wire payload: a stable
 // opaque id (string form of the numeric source id), a display label (anonymized for unauthenticated
-// clients), and the source's in-range detection count.
+// clients), the source's deployment site (omitted for unauthenticated clients) and its in-range
+// detection count.
 type analyticsSourceItem struct {
 	ID    string `json:"id"`
 	Name  string `json:"name"`
+	Site  string `json:"site,omitempty"`
 	Count int    `json:"count"`
 }
 
@@ -1955,12 +1957,15 @@ func analyticsSourceLabel(src *datastore.AudioSourceSummary, authenticated bool)
 // covers all history. Powers the analytics hub's source/mic filter option list. The metric is v2only
 // (the legacy schema does not persist a detection's source); the legacy datastore returns an empty
 // list. Source names are anonymized for unauthenticated clients (the analytics page is public); the
-// opaque numeric id is safe to expose and is what the source filter round-trips in the URL.
+// opaque numeric id is safe to expose and is what the source filter round-trips in the URL. The
+// optional `site` param narrows the list to the sources deployed at that site, so the hub can filter a
+// multi-site station by site.
 func (c *Handler) GetAnalyticsSources(ctx echo.Context) error {
 	const operation = "analytics sources"
 
 	startDate := ctx.QueryParam("start_date")
 	endDate := ctx.QueryParam("end_date")
+	site := strings.TrimSpace(ctx.QueryParam("site"))
 
 	// Dates are optional (omitted = all history); validate only what is supplied.
 	if startDate != "" {
@@ -2003,11 +2008,18 @@ func (c *Handler) GetAnalyticsSources(ctx echo.Context) error {
 	authenticated := c.isClientAuthenticated(ctx)
 	resp := analyticsSourceListResponse{Sources: make([]analyticsSourceItem, 0, len(sources))}
 	for i := range sources {
-		resp.Sources = append(resp.Sources, analyticsSourceItem{
+		if site != "" && !strings.EqualFold(sources[i].Site, site) {
+			continue
+		}
+		item := analyticsSourceItem{
 			ID:    strconv.FormatUint(uint64(sources[i].ID), 10),
 			Name:  analyticsSourceLabel(&sources[i], authenticated),
 			Count: sources[i].Count,
-		})
+		}
+		if authenticated {
+			item.Site = sources[i].Site
+		}
+		resp.Sources = append(resp.Sources, item)
 	}
 
 	c.LogInfoIfEnabled("Analytics audio sources retrieved",
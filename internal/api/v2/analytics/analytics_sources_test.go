@@ -19,6 +19,7 @@ import (
 type analyticsSourceJSON struct {
 	ID    string `json:"id"`
 	Name  string `json:"name"`
+	Site  string `json:"site"`
 	Count int    `json:"count"`
 }
 
@@ -29,7 +30,7 @@ type analyticsSourcesRespJSON struct {
 
 func sampleAudioSources() []datastore.AudioSourceSummary {
 	return []datastore.AudioSourceSummary{
-		{ID: 7, DisplayName: "Backyard", NodeName: "node-a", SourceType: "rtsp", Count: 42},
+		{ID: 7, DisplayName: "Backyard", NodeName: "node-a", SourceType: "rtsp", Site: "North Meadow", Count: 42},
 		{ID: 3, DisplayName: "", NodeName: "node-b", SourceType: "alsa", Count: 9},
 	}
 }
@@ -62,6 +63,7 @@ func TestGetAnalyticsSources_ShapeAnonymizedWhenUnauthenticated(t *testing.T) {
 	// Order preserved (server already sorts by count desc).
 	assert.Equal(t, "7", resp.Sources[0].ID)
 	assert.Equal(t, "camera-7", resp.Sources[0].Name) // rtsp -> camera-N, never the configured "Backyard"
+	assert.Empty(t, resp.Sources[0].Site, "the configured site is not exposed")
 	assert.Equal(t, 42, resp.Sources[0].Count)
 
 	assert.Equal(t, "3", resp.Sources[1].ID)
@@ -71,6 +73,24 @@ func TestGetAnalyticsSources_ShapeAnonymizedWhenUnauthenticated(t *testing.T) {
 	mockDS.AssertExpectations(t)
 }
 
+func TestGetAnalyticsSources_SiteFilter(t *testing.T) {
+	t.Parallel()
+	e, mockDS, controller := setupAnalyticsTestEnvironment(t)
+
+	mockDS.On("GetAudioSources", mock.Anything, "", "").
+		Return(sampleAudioSources(), nil)
+
+	c, rec := newAnalyticsSourcesContext(e, "/api/v2/analytics/sources?site=north%20meadow")
+	require.NoError(t, controller.GetAnalyticsSources(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp analyticsSourcesRespJSON
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Sources, 1, "only sources deployed at the site are listed")
+	assert.Equal(t, "7", resp.Sources[0].ID)
+	mockDS.AssertExpectations(t)
+}
+
 func TestGetAnalyticsSources_EmptyArrayNotNull(t *testing.T) {
 	t.Parallel()
 	e, mockDS, controller := setupAnalyticsTestEnvironment(t)
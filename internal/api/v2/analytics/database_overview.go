@@ -12,7 +12,7 @@ import (
 // DatabaseOverviewResponse is the response for GET /api/v2/system/database/overview.
 // It adapts to the active database engine via the Engine field.
 type DatabaseOverviewResponse struct {
-	Engine           string                     `json:"engine"`           // "sqlite" or "mysql"
+	Engine           string                     `json:"engine"`           // "sqlite", "mysql" or "postgres"
 	Status           string                     `json:"status"`           // "connected" or "disconnected"
 	Location         string                     `json:"location"`         // file path or host:port/db
 	SizeBytes        int64                      `json:"size_bytes"`       // total database size
@@ -20,6 +20,7 @@ type DatabaseOverviewResponse struct {
 	TotalTables      int                        `json:"total_tables"`     // number of user tables
 	SQLite           *datastore.SQLiteDetails   `json:"sqlite,omitempty"`
 	MySQL            *datastore.MySQLDetails    `json:"mysql,omitempty"`
+	Postgres         *datastore.PostgresDetails `json:"postgres,omitempty"`
 	Tables           []datastore.TableStats     `json:"tables"`
 	Performance      datastore.PerformanceStats `json:"performance"`
 	DetectionRate24h []datastore.HourlyCount    `json:"detection_rate_24h"`
@@ -79,6 +80,7 @@ func (c *Handler) GetDatabaseOverview(ctx echo.Context) error {
 		} else {
 			resp.SQLite = details.SQLite
 			resp.MySQL = details.MySQL
+			resp.Postgres = details.Postgres
 		}
 
 		// Table stats
@@ -24,18 +24,22 @@ import (
 	"github.com/tphakala/birdnet-go/internal/api/v2/control"
 	"github.com/tphakala/birdnet-go/internal/api/v2/detections"
 	"github.com/tphakala/birdnet-go/internal/api/v2/dynamicthresholds"
+	"github.com/tphakala/birdnet-go/internal/api/v2/exports"
 	"github.com/tphakala/birdnet-go/internal/api/v2/filesystem"
 	importsapi "github.com/tphakala/birdnet-go/internal/api/v2/imports"
 	"github.com/tphakala/birdnet-go/internal/api/v2/integrations"
 	mediaapi "github.com/tphakala/birdnet-go/internal/api/v2/media"
 	"github.com/tphakala/birdnet-go/internal/api/v2/models"
 	"github.com/tphakala/birdnet-go/internal/api/v2/notifications"
+	outboxapi "github.com/tphakala/birdnet-go/internal/api/v2/outbox"
 	rangeapi "github.com/tphakala/birdnet-go/internal/api/v2/range"
+	"github.com/tphakala/birdnet-go/internal/api/v2/soundscapes"
 	"github.com/tphakala/birdnet-go/internal/api/v2/species"
 	"github.com/tphakala/birdnet-go/internal/api/v2/sse"
 	"github.com/tphakala/birdnet-go/internal/api/v2/support"
 	"github.com/tphakala/birdnet-go/internal/api/v2/system"
 	tlsapi "github.com/tphakala/birdnet-go/internal/api/v2/tls"
+	"github.com/tphakala/birdnet-go/internal/api/v2/users"
 	"github.com/tphakala/birdnet-go/internal/api/v2/weather"
 	"github.com/tphakala/birdnet-go/internal/audiocore"
 	"github.com/tphakala/birdnet-go/internal/audiocore/engine"
@@ -48,6 +52,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/notification"
 	"github.com/tphakala/birdnet-go/internal/observability"
+	"github.com/tphakala/birdnet-go/internal/security"
 	"github.com/tphakala/birdnet-go/internal/suncalc"
 )
 
@@ -146,6 +151,31 @@ type Controller struct {
 	// global event bus.
 	alerts *alerts.Handler
 
+	// users serves the admin-only /api/v2/users/* endpoints that manage the
+	// station's user accounts. It builds its repository lazily in
+	// RegisterRoutes when the enhanced v2 database schema is active.
+	users *users.Handler
+
+	// exports serves the /api/v2/exports/* endpoints that build bulk dataset
+	// files (Darwin Core Archives) from the v2 detections in the background. It
+	// builds its repositories lazily in RegisterRoutes when the enhanced v2
+	// database schema is active, and the facade calls c.exports.Shutdown()
+	// during teardown to cancel running jobs and delete their files.
+	exports *exports.Handler
+
+	// outbox serves the admin /api/v2/outbox/* endpoints that inspect, retry
+	// and purge queued BirdWeather, MQTT and webhook deliveries. When the
+	// enhanced v2 database schema is active RegisterRoutes builds the durable
+	// outbox, routes the processor and push webhooks through it and starts its
+	// worker; the facade calls c.outbox.Shutdown() during teardown to stop it.
+	outbox *outboxapi.Handler
+
+	// soundscapes serves the /api/v2/soundscapes/* endpoints that list and
+	// stream the continuous soundscape segments recorded by the audio
+	// pipeline. It builds its repository lazily in RegisterRoutes when the
+	// enhanced v2 database schema is active.
+	soundscapes *soundscapes.Handler
+
 	// control serves the /api/v2/control/* endpoints (restart analysis, reload
 	// model, rebuild range filter, restart server/container, restart a single
 	// audio source, and list actions). Beyond the shared *apicore.Core it OWNS
@@ -303,6 +333,22 @@ func WithAuthMiddleware(mw echo.MiddlewareFunc) Option {
 	}
 }
 
+// WithRoleMiddleware sets the builder for role-restricted authentication
+// middleware used by routes that are open to viewers and reviewers.
+func WithRoleMiddleware(mw func(security.Role) echo.MiddlewareFunc) Option {
+	return func(c *Controller) {
+		c.RoleMiddleware = mw
+	}
+}
+
+// WithScopeMiddleware sets the builder for authentication middleware used by
+// admin routes that scoped API keys may also reach.
+func WithScopeMiddleware(mw func(security.Scope) echo.MiddlewareFunc) Option {
+	return func(c *Controller) {
+		c.ScopeMiddleware = mw
+	}
+}
+
 // WithAuthService sets the authentication service for the controller.
 func WithAuthService(svc auth.Service) Option {
 	return func(c *Controller) {
@@ -484,6 +530,18 @@ func NewWithOptions(e *echo.Echo, ds datastore.Interface, settings *conf.Setting
 	// the shared core here (V2Manager, auth middleware, and the error/log helpers
 	// all promote from it).
 	c.alerts = alerts.New(c.Core)
+	// The users handler needs only the shared core (V2Manager, auth middleware,
+	// and the error/log helpers all promote from it).
+	c.users = users.New(c.Core)
+	// The exports handler needs the facade-owned common-name map for vernacular
+	// names; everything else promotes from the shared core.
+	c.exports = exports.New(c.Core, c.loadCommonNameMap)
+	// The outbox handler needs only the shared core (V2Manager, Processor, auth
+	// middleware, and the error/log helpers all promote from it).
+	c.outbox = outboxapi.New(c.Core)
+	// The soundscapes handler needs only the shared core (V2Manager, settings,
+	// role middleware, and the error/log helpers all promote from it).
+	c.soundscapes = soundscapes.New(c.Core)
 	// The control handler owns its sourceRestarter and receives the shared
 	// control-signal channel as a send-only injection. c.controlChan is already
 	// set in the Controller literal above; passing it here narrows it to a
@@ -491,6 +549,14 @@ func NewWithOptions(e *echo.Echo, ds datastore.Interface, settings *conf.Setting
 	// internal/analysis owns. The remaining deps (Engine, ShutdownRequester, and
 	// the error/log helpers) all promote from the shared core.
 	c.control = control.New(c.Core, c.controlChan)
+	// Alert rules run internal controls (restart a stream, reload the model)
+	// through the control domain.
+	c.alerts.SetControlFunc(c.control.RunAction)
+	// MQTT command topics run the same controls. The processor owns the MQTT
+	// client and forwards each command to the control domain.
+	if c.Processor != nil {
+		c.Processor.SetMQTTCommandHandler(c.control.HandleMQTTCommand)
+	}
 
 	// Apply functional options (auth middleware and service injected from server)
 	for _, opt := range opts {
@@ -526,7 +592,9 @@ func NewWithOptions(e *echo.Echo, ds datastore.Interface, settings *conf.Setting
 	// falling back to the process-global singleton when nil), mirroring the
 	// notifications handler above. Its import lifecycle manager is created in New;
 	// the legacy-cleanup tracker is created lazily in RegisterLegacyCleanupRoutes.
-	c.imports = importsapi.New(c.Core, c.notificationService)
+	// Result imports resolve common-name-only sources through the cached name maps.
+	c.imports = importsapi.New(c.Core, c.notificationService,
+		c.loadCommonNameMap, c.loadCommonToScientificMap)
 
 	// Construct the app/debug domain handler AFTER the functional options are
 	// applied so it captures the post-option authService (the /app/config
@@ -605,6 +673,13 @@ func (c *Controller) SetSourceRestarter(fn control.SourceRestarterFunc) {
 	c.control.SetSourceRestarter(fn)
 }
 
+// SetSourceScheduler injects the quiet hours scheduler used by the pause,
+// resume and quiet hours control actions. Like SetSourceRestarter, the audio
+// pipeline calls it during Start() and it delegates to the control domain.
+func (c *Controller) SetSourceScheduler(s control.SourceScheduler) {
+	c.control.SetSourceScheduler(s)
+}
+
 // initRoutes registers all API endpoints
 func (c *Controller) initRoutes() {
 	// Health check endpoint - publicly accessible
@@ -649,6 +724,10 @@ func (c *Controller) initRoutes() {
 		{"species routes", func() { c.species.RegisterRoutes(c.Group) }},
 		{"dynamic threshold routes", func() { c.dynamicThresholds.RegisterRoutes(c.Group) }},
 		{"alert routes", func() { c.alerts.RegisterRoutes(c.Group) }},
+		{"user routes", func() { c.users.RegisterRoutes(c.Group) }},
+		{"export routes", func() { c.exports.RegisterRoutes(c.Group) }},
+		{"outbox routes", func() { c.outbox.RegisterRoutes(c.Group) }},
+		{"soundscape routes", func() { c.soundscapes.RegisterRoutes(c.Group) }},
 		{"model routes", func() { c.models.RegisterRoutes(c.Group) }},
 		{"insights routes", c.initInsightsRoutes},
 		{"tls routes", func() { c.tlsHandler.RegisterRoutes(c.Group) }},
@@ -792,6 +871,16 @@ func (c *Controller) Shutdown() {
 		c.alerts.Shutdown()
 	}
 
+	// Cancel running exports and delete their temp files.
+	if c.exports != nil {
+		c.exports.Shutdown()
+	}
+
+	// Stop the delivery outbox worker; queued items stay in the database.
+	if c.outbox != nil {
+		c.outbox.Shutdown()
+	}
+
 	// Cancel context to stop all goroutines, then wait for them to finish.
 	c.Cancel()
 	c.Wait()
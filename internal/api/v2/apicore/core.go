@@ -26,6 +26,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/observability"
 	"github.com/tphakala/birdnet-go/internal/securefs"
+	"github.com/tphakala/birdnet-go/internal/security"
 	"github.com/tphakala/birdnet-go/internal/suncalc"
 )
 
@@ -82,6 +83,17 @@ type Core struct {
 	// per-route protected-group registrations.
 	AuthMiddleware echo.MiddlewareFunc
 
+	// RoleMiddleware builds authentication middleware that additionally
+	// requires a minimum user role (injected from server via
+	// WithRoleMiddleware). AuthMiddleware itself requires the admin role, so
+	// routes opened to viewers and reviewers use RequireRole instead.
+	RoleMiddleware func(security.Role) echo.MiddlewareFunc
+
+	// ScopeMiddleware builds authentication middleware for admin routes that
+	// API keys with the given scope may also reach (injected from server via
+	// WithScopeMiddleware).
+	ScopeMiddleware func(security.Scope) echo.MiddlewareFunc
+
 	// MetricsStore holds the metrics history store for sparkline data and the
 	// inference-topology broadcast (BroadcastInferenceTopologyChanged).
 	MetricsStore observability.MetricsStore
@@ -9,6 +9,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/privacy"
+	"github.com/tphakala/birdnet-go/internal/security"
 )
 
 // tunnelProviderUnknown is the tunnel provider label for unknown providers.
@@ -190,10 +191,30 @@ func (c *Core) PrivateModeAuth(next echo.HandlerFunc) echo.HandlerFunc {
 		if c.privateModeExempt != nil && c.privateModeExempt(ctx.Request().Method, ctx.Path()) {
 			return next(ctx)
 		}
-		return c.AuthMiddleware(next)(ctx)
+		return c.RequireRole(security.RoleViewer)(next)(ctx)
 	}
 }
 
+// RequireRole returns authentication middleware that requires at least role.
+// It falls back to AuthMiddleware when no role middleware was injected, so a
+// route never ends up less protected than an admin route.
+func (c *Core) RequireRole(role security.Role) echo.MiddlewareFunc {
+	if c.RoleMiddleware == nil {
+		return c.AuthMiddleware
+	}
+	return c.RoleMiddleware(role)
+}
+
+// RequireScope returns authentication middleware for an admin route that API
+// keys holding scope may also reach. Without injected scope middleware it
+// falls back to AuthMiddleware.
+func (c *Core) RequireScope(scope security.Scope) echo.MiddlewareFunc {
+	if c.ScopeMiddleware == nil {
+		return c.AuthMiddleware
+	}
+	return c.ScopeMiddleware(scope)
+}
+
 // GetAuthMiddleware returns the authentication middleware function injected from server.
 //
 // Returns nil if no middleware was configured via WithAuthMiddleware option.
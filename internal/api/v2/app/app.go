@@ -12,6 +12,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
 	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/security"
 	"github.com/tphakala/birdnet-go/internal/speciesdict"
 	"github.com/tphakala/birdnet-go/internal/telemetry"
 )
@@ -81,6 +82,10 @@ type SecurityConfigDTO struct {
 	// redirect to login on any 401 instead of silently treating gated
 	// endpoints as graceful guest-mode limitations.
 	PrivateMode bool `json:"privateMode"`
+	// Role is the role of the current user ("viewer", "reviewer" or "admin")
+	// so the frontend can hide pages the user cannot use. Empty when access
+	// is not allowed.
+	Role security.Role `json:"role,omitempty"`
 }
 
 // PublicAccessDTO exposes which features are accessible without authentication.
@@ -193,6 +198,7 @@ func (c *Handler) GetAppConfig(ctx echo.Context) error {
 				LiveAudio: settings.Security.PublicAccess.LiveAudio,
 			},
 			PrivateMode: settings.Security.PrivateMode,
+			Role:        c.determineRole(ctx, securityEnabled, accessAllowed),
 		},
 		Version:            settings.Version,
 		SpeciesDictVersion: speciesdict.Version(),
@@ -404,6 +410,18 @@ func (c *Handler) determineAccessAllowed(ctx echo.Context, securityEnabled bool)
 	return c.authService.IsAuthenticated(ctx)
 }
 
+// determineRole returns the role of the current request's user. Without
+// security everyone is an admin; a request without access has no role.
+func (c *Handler) determineRole(ctx echo.Context, securityEnabled, accessAllowed bool) security.Role {
+	if !securityEnabled {
+		return security.RoleAdmin
+	}
+	if !accessAllowed || c.authService == nil {
+		return ""
+	}
+	return c.authService.GetIdentity(ctx).Role
+}
+
 // requestBasePath returns the effective base path prefix for the current request.
 // Priority: X-Ingress-Path header > X-Forwarded-Prefix header > config BasePath > empty.
 //
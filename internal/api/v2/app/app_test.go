@@ -28,6 +28,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/branding"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+	"github.com/tphakala/birdnet-go/internal/security"
 	"github.com/tphakala/birdnet-go/internal/security/securitytest"
 	"github.com/tphakala/birdnet-go/internal/speciesdict"
 )
@@ -143,6 +144,7 @@ func TestGetAppConfig_NoSecurity(t *testing.T) {
 	// When no security is configured, accessAllowed should be true
 	assert.False(t, response.Security.Enabled, "Security should be disabled")
 	assert.True(t, response.Security.AccessAllowed, "Access should be allowed when security is disabled")
+	assert.Equal(t, security.RoleAdmin, response.Security.Role, "Everyone is an admin when security is disabled")
 	assert.Equal(t, "1.0.0-test", response.Version)
 
 	// All auth methods should be disabled
@@ -552,6 +554,7 @@ func TestGetAppConfig_NilAuthService(t *testing.T) {
 	// With nil auth service, should fail closed (deny access)
 	assert.True(t, response.Security.Enabled)
 	assert.False(t, response.Security.AccessAllowed, "Should deny access when auth service is nil")
+	assert.Empty(t, response.Security.Role, "A request without access has no role")
 }
 
 // =============================================================================
@@ -955,6 +958,7 @@ func TestGetAppConfig_NoExtraFields(t *testing.T) {
 		"authConfig":    true,
 		"publicAccess":  true,
 		"privateMode":   true,
+		"role":          true,
 	}
 
 	for key := range securityObj {
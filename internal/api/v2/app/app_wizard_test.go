@@ -68,6 +68,7 @@ func (f *fakeV2Manager) CheckpointWAL() error { return nil }
 func (f *fakeV2Manager) Delete() error        { return nil }
 func (f *fakeV2Manager) Exists() bool         { return true }
 func (f *fakeV2Manager) IsMySQL() bool        { return false }
+func (f *fakeV2Manager) IsPostgres() bool     { return false }
 func (f *fakeV2Manager) TablePrefix() string  { return f.tablePrefix }
 
 // newFakeV2ManagerWithDetections creates a fakeV2Manager backed by an
@@ -25,6 +25,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
 	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
 	"github.com/tphakala/birdnet-go/internal/audiocore"
 	"github.com/tphakala/birdnet-go/internal/audiocore/engine"
@@ -35,6 +36,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/privacy"
 	"github.com/tphakala/birdnet-go/internal/securefs"
+	"github.com/tphakala/birdnet-go/internal/security"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -112,6 +114,7 @@ type HLSStreamInfo struct {
 	ctx          context.Context    // Stream lifecycle context
 	cancel       context.CancelFunc // Cancel function for cleanup
 	streamEpoch  time.Time          // Wall-clock time corresponding to HLS stream position 0
+	liveDelay    time.Duration      // Hold-back of the audio for privacy voice redaction, zero when not redacting
 
 	// pdtOffset corrects FFmpeg's PROGRAM_DATE_TIME timestamps to align with
 	// wall-clock time. FFmpeg sets the PDT epoch at process init (av_gettime),
@@ -222,8 +225,8 @@ func hlsPlaylistURL(token string) string {
 
 // RegisterHLSRoutes registers HLS streaming endpoints
 func (c *Handler) RegisterHLSRoutes(g *echo.Group) {
-	// Get authentication middleware
-	authMiddleware := c.AuthMiddleware
+	// Listening is open to every signed-in user, including viewers
+	authMiddleware := c.RequireRole(security.RoleViewer)
 
 	// HLS base group (no auth by default)
 	hlsGroup := g.Group(HLSGroupPath)
@@ -254,15 +257,15 @@ func (c *Handler) RegisterHLSRoutes(g *echo.Group) {
 
 // publicLiveAudioAuth is a dynamic middleware that checks PublicAccess.LiveAudio
 // on each request. When enabled, the request proceeds without authentication.
-// When disabled, the standard auth middleware is applied. This allows the setting
-// to take effect immediately without a server restart.
+// When disabled, any signed-in user may listen. This allows the setting to take
+// effect immediately without a server restart.
 func (c *Handler) publicLiveAudioAuth(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		isPublic := c.CurrentSettings().Security.PublicAccess.LiveAudio
 		if isPublic {
 			return next(ctx)
 		}
-		return c.AuthMiddleware(next)(ctx)
+		return c.RequireRole(security.RoleViewer)(next)(ctx)
 	}
 }
 
@@ -743,10 +746,11 @@ func (c *Handler) correctPlaylistPDT(stream *HLSStreamInfo, playlist string) str
 			return playlist
 		}
 		// The last segment's end-time (PDT + duration) should approximately
-		// equal "now" minus a small serving delay. Any difference is the offset.
+		// equal "now" minus a small serving delay and the redaction hold-back,
+		// since its audio was captured that long ago. Any difference is the offset.
 		last := segments[len(segments)-1]
 		hlsNow := last.pdt.Add(time.Duration(last.duration * float64(time.Second)))
-		wallNow := time.Now()
+		wallNow := time.Now().Add(-stream.liveDelay)
 		offset := wallNow.Sub(hlsNow)
 		// Only the first goroutine to succeed sets the offset
 		if stream.pdtOffsetComputed.CompareAndSwap(false, true) {
@@ -1172,6 +1176,9 @@ func (c *Handler) createHLSStream(sourceID string) (*HLSStreamInfo, error) {
 	// Generate filesystem-safe name
 	filesystemSafeID := generateFilesystemSafeName(sourceID)
 
+	// In privacy redact mode the audio is held back until voice in it is known
+	liveDelay := c.liveRedactionDelay()
+
 	// Create stream context from controller's lifecycle context, NOT from HTTP request context.
 	// Using request context would cause the stream to be cleaned up when the /start request completes.
 	// The stream must persist beyond the initial request lifetime.
@@ -1237,7 +1244,7 @@ func (c *Handler) createHLSStream(sourceID string) (*HLSStreamInfo, error) {
 
 	// Setup Windows-specific stdin pipe handling
 	if runtime.GOOS == osWindows {
-		if err := c.setupWindowsAudioFeed(streamCtx, sourceID, cmd); err != nil {
+		if err := c.setupWindowsAudioFeed(streamCtx, sourceID, cmd, liveDelay); err != nil {
 			streamCancel()
 			return nil, err
 		}
@@ -1251,7 +1258,7 @@ func (c *Handler) createHLSStream(sourceID string) (*HLSStreamInfo, error) {
 	var feedResources *audioFeedResources
 	if runtime.GOOS != osWindows {
 		var prepErr error
-		feedResources, prepErr = c.prepareAudioFeed(sourceID, pipeName)
+		feedResources, prepErr = c.prepareAudioFeed(sourceID, pipeName, liveDelay)
 		if prepErr != nil {
 			streamCancel()
 			return nil, fmt.Errorf("failed to prepare audio feed: %w", prepErr)
@@ -1279,6 +1286,7 @@ func (c *Handler) createHLSStream(sourceID string) (*HLSStreamInfo, error) {
 		ctx:          streamCtx,
 		cancel:       streamCancel,
 		streamEpoch:  time.Now(),
+		liveDelay:    liveDelay,
 	}
 
 	// Register the stream (singleflight guarantees no concurrent creation for this sourceID)
@@ -1493,7 +1501,7 @@ func (c *Handler) buildFFmpegArgs(inputSource, outputDir, playlistPath string) [
 }
 
 // setupWindowsAudioFeed sets up audio feeding via stdin for Windows
-func (c *Handler) setupWindowsAudioFeed(ctx context.Context, sourceID string, cmd *exec.Cmd) error {
+func (c *Handler) setupWindowsAudioFeed(ctx context.Context, sourceID string, cmd *exec.Cmd, liveDelay time.Duration) error {
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		return fmt.Errorf("failed to create stdin pipe: %w", err)
@@ -1507,7 +1515,7 @@ func (c *Handler) setupWindowsAudioFeed(ctx context.Context, sourceID string, cm
 		}()
 		apicore.GetLogger().Debug("Starting audio feed via stdin", logger.String("source_id", privacy.SanitizeRTSPUrl(sourceID)))
 
-		audioChan, cleanup, err := c.setupAudioCallback(sourceID)
+		audioChan, cleanup, err := c.setupAudioCallback(sourceID, liveDelay)
 		if err != nil {
 			apicore.GetLogger().Error("Error setting up audio callback", logger.Error(err))
 			return
@@ -1582,6 +1590,13 @@ type hlsConsumer struct {
 	channels int
 	closed   atomic.Bool
 
+	// Privacy voice redaction. With a non-zero delay frames are held back
+	// that long, then passed through redact before they are streamed.
+	delay  time.Duration
+	redact func(pcm []byte, sampleRate int, audioFrom time.Time) []byte
+	held   []heldFrame
+	heldMu sync.Mutex
+
 	// Drop tracking for diagnostics
 	dropCount   int64
 	lastDropLog time.Time
@@ -1611,9 +1626,60 @@ func (h *hlsConsumer) Write(frame audiocore.AudioFrame) error { //nolint:gocriti
 		return audiocore.ErrConsumerClosed
 	}
 
-	// Copy once up front. Both select arms below need an owned slice.
+	// Copy once up front. The channel and the hold-back both need an owned slice.
 	buf := slices.Clone(frame.Data)
 
+	if h.delay > 0 {
+		for _, out := range h.release(buf, frame.Timestamp) {
+			h.send(out)
+		}
+		return nil
+	}
+	// Redaction was turned on after the stream started without a hold-back:
+	// stream silence rather than unredacted voice until it is restarted
+	if conf.Setting().Realtime.PrivacyFilter.Redacting() {
+		clear(buf)
+	}
+	h.send(buf)
+	return nil
+}
+
+// heldFrame is a frame held back for privacy voice redaction.
+type heldFrame struct {
+	data       []byte
+	capturedAt time.Time
+}
+
+// release holds buf back and returns the held frames, redacted, that are
+// now at least delay old, oldest first.
+func (h *hlsConsumer) release(buf []byte, capturedAt time.Time) [][]byte {
+	now := time.Now()
+	if capturedAt.IsZero() {
+		capturedAt = now
+	}
+	cutoff := now.Add(-h.delay)
+
+	h.heldMu.Lock()
+	defer h.heldMu.Unlock()
+	h.held = append(h.held, heldFrame{data: buf, capturedAt: capturedAt})
+	n := 0
+	for n < len(h.held) && !h.held[n].capturedAt.After(cutoff) {
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	out := make([][]byte, n)
+	for i, f := range h.held[:n] {
+		out[i] = h.redact(f.data, h.rate, f.capturedAt)
+	}
+	h.held = slices.Delete(h.held, 0, n)
+	return out
+}
+
+// send delivers buf to the HLS channel, dropping the oldest queued chunk
+// when the channel is full.
+func (h *hlsConsumer) send(buf []byte) {
 	select {
 	case h.ch <- buf:
 	default:
@@ -1646,7 +1712,6 @@ func (h *hlsConsumer) Write(frame audiocore.AudioFrame) error { //nolint:gocriti
 			h.dropMu.Unlock()
 		}
 	}
-	return nil
 }
 
 // Close marks the consumer as closed.
@@ -1656,7 +1721,9 @@ func (h *hlsConsumer) Close() error {
 }
 
 // setupAudioCallback sets up the audio callback channel using the AudioRouter.
-func (c *Handler) setupAudioCallback(sourceID string) (audioChan chan []byte, cleanup func(), err error) {
+// A non-zero liveDelay holds the audio back that long and redacts human voice
+// from it (see liveRedactionDelay).
+func (c *Handler) setupAudioCallback(sourceID string, liveDelay time.Duration) (audioChan chan []byte, cleanup func(), err error) {
 	audioChan = make(chan []byte, defaultReadBufferSize)
 
 	consumerID := fmt.Sprintf("hls_%s_%s", privacy.SanitizeStreamUrl(sourceID), uuid.New().String()[:8])
@@ -1675,6 +1742,10 @@ func (c *Handler) setupAudioCallback(sourceID string) (audioChan chan []byte, cl
 		depth:    conf.BitDepth,
 		channels: 1,
 	}
+	if liveDelay > 0 {
+		consumer.delay = liveDelay
+		consumer.redact = c.liveVoiceRedactor(sourceID)
+	}
 
 	// Load the engine once for all registry/router operations in this section.
 	eng := c.Engine.Load()
@@ -1719,6 +1790,30 @@ func (c *Handler) setupAudioCallback(sourceID string) (audioChan chan []byte, cl
 	return audioChan, cleanup, nil
 }
 
+// liveRedactionDelay returns how long live audio is held back so the privacy
+// filter can redact human voice from it, or zero when it is not redacting.
+func (c *Handler) liveRedactionDelay() time.Duration {
+	if !c.CurrentSettings().Realtime.PrivacyFilter.Redacting() {
+		return 0
+	}
+	return processor.LiveRedactionDelay()
+}
+
+// liveVoiceRedactor returns the redaction applied to held-back live audio of
+// sourceID. Without a processor to tell where the voice is, all audio is
+// silenced rather than streamed unredacted.
+func (c *Handler) liveVoiceRedactor(sourceID string) func(pcm []byte, sampleRate int, audioFrom time.Time) []byte {
+	if proc := c.Processor; proc != nil {
+		return func(pcm []byte, sampleRate int, audioFrom time.Time) []byte {
+			return proc.RedactLiveAudio(pcm, sampleRate, sourceID, audioFrom)
+		}
+	}
+	return func(pcm []byte, _ int, _ time.Time) []byte {
+		clear(pcm)
+		return pcm
+	}
+}
+
 // writeToFIFO performs a context-aware write to the FIFO pipe.
 // If the context is cancelled or the write exceeds fifoWriteTimeout,
 // it returns immediately. The orphaned write goroutine is cleaned up
@@ -1758,7 +1853,7 @@ type audioFeedResources struct {
 // It must be called BEFORE FFmpeg starts so that audio data begins buffering
 // in the channel immediately. The returned resources are consumed by
 // runAudioFeedLoop, which should be started as a goroutine after cmd.Start().
-func (c *Handler) prepareAudioFeed(sourceID, pipePath string) (*audioFeedResources, error) {
+func (c *Handler) prepareAudioFeed(sourceID, pipePath string, liveDelay time.Duration) (*audioFeedResources, error) {
 	sanitizedID := privacy.SanitizeRTSPUrl(sourceID)
 	apicore.GetLogger().Debug("Preparing audio feed", logger.String("source_id", sanitizedID), logger.String("pipe_path", pipePath))
 
@@ -1774,7 +1869,7 @@ func (c *Handler) prepareAudioFeed(sourceID, pipePath string) (*audioFeedResourc
 
 	// Register the audio callback first so audio chunks start buffering
 	// in the channel while we open the FIFO and before FFmpeg starts.
-	audioChan, callbackCleanup, err := c.setupAudioCallback(sourceID)
+	audioChan, callbackCleanup, err := c.setupAudioCallback(sourceID, liveDelay)
 	if err != nil {
 		if closeErr := secFS.Close(); closeErr != nil {
 			apicore.GetLogger().Error("Failed to close secure filesystem", logger.Error(closeErr))
@@ -635,6 +635,44 @@ func TestHLSConsumer_WriteDoesNotRetainFrameData(t *testing.T) {
 	}
 }
 
+// TestHLSConsumer_HoldsBackAndRedacts verifies that in privacy redact mode
+// frames are held back for the redaction delay and passed through the
+// redactor before they reach FFmpeg.
+func TestHLSConsumer_HoldsBackAndRedacts(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan []byte, 4)
+	var redactedFrom []time.Time
+	h := &hlsConsumer{
+		id:       "test",
+		sourceID: "src",
+		ch:       ch,
+		rate:     48000,
+		depth:    16,
+		channels: 1,
+		delay:    time.Minute,
+		redact: func(pcm []byte, sampleRate int, audioFrom time.Time) []byte {
+			assert.Equal(t, 48000, sampleRate)
+			redactedFrom = append(redactedFrom, audioFrom)
+			return make([]byte, len(pcm))
+		},
+	}
+
+	now := time.Now()
+	old := now.Add(-2 * time.Minute)
+
+	// A frame older than the delay is released at once, redacted
+	require.NoError(t, h.Write(audiocore.AudioFrame{Data: []byte{1, 1}, Timestamp: old}))
+	require.Len(t, ch, 1)
+	assert.Equal(t, []byte{0, 0}, <-ch)
+	assert.Equal(t, []time.Time{old}, redactedFrom)
+
+	// A fresh frame is held back until its voice is known
+	require.NoError(t, h.Write(audiocore.AudioFrame{Data: []byte{2, 2}, Timestamp: now}))
+	assert.Empty(t, ch)
+	assert.Len(t, h.held, 1)
+}
+
 // TestStartHLSStream_NoCaptureBuffer verifies that starting a live-audio (HLS)
 // stream for a source that has no capture buffer returns a diagnostic 404 rather
 // than the old opaque "Audio source not found" with a nil error (issue #3766).
@@ -451,7 +451,7 @@ func (c *Handler) getAudioCardSources(registry *audiocore.SourceRegistry) []*aud
 		if src.Device == "" {
 			continue
 		}
-		if audioSrc, ok := registry.GetByConnection(src.Device); ok {
+		if audioSrc, ok := registry.GetByConnection(src.ConnectionString()); ok {
 			sources = append(sources, audioSrc)
 		}
 	}
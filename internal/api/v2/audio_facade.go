@@ -9,6 +9,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/api/v2/app"
 	audioapi "github.com/tphakala/birdnet-go/internal/api/v2/audio"
 	authapi "github.com/tphakala/birdnet-go/internal/api/v2/auth"
+	"github.com/tphakala/birdnet-go/internal/api/v2/weather"
 	"github.com/tphakala/birdnet-go/internal/audiocore"
 )
 
@@ -35,6 +36,8 @@ func (c *Controller) isClientAuthenticated(ctx echo.Context) bool {
 //     PublicAccess.LiveAudio carve-out: when LiveAudio is enabled the route stays
 //     public, when it is disabled the per-route middleware applies authMiddleware
 //     as before.
+//  3. The local weather station upload, which stations cannot authenticate
+//     for; the handler checks the configured station PASSKEY instead.
 //
 // The allow-list is keyed on method + path so any future handler added at one of
 // these paths under a different verb is fail-closed by default. It is injected
@@ -46,6 +49,7 @@ func isPrivateModeExempt(method, path string) bool {
 		authBase     = apiV2Prefix + authapi.AuthGroupPath
 		hlsBase      = apiV2Prefix + audioapi.HLSGroupPath
 		hlsTokenBase = hlsBase + audioapi.HLSTokenGroupPath
+		stationPath  = apiV2Prefix + weather.WeatherGroupPath + weather.StationUploadPath
 	)
 	switch {
 	case method == http.MethodGet && path == apiV2Prefix+app.AppConfigEndpoint:
@@ -64,6 +68,8 @@ func isPrivateModeExempt(method, path string) bool {
 		return true
 	case method == http.MethodGet && path == hlsTokenBase+audioapi.HLSContentPath:
 		return true
+	case (method == http.MethodGet || method == http.MethodPost) && path == stationPath:
+		return true
 	}
 	return false
 }
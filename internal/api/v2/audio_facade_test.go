@@ -16,6 +16,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/api/v2/app"
 	audioapi "github.com/tphakala/birdnet-go/internal/api/v2/audio"
 	authapi "github.com/tphakala/birdnet-go/internal/api/v2/auth"
+	"github.com/tphakala/birdnet-go/internal/api/v2/weather"
 )
 
 // TestIsPrivateModeExempt verifies the (method, route) allow-list that
@@ -42,6 +43,8 @@ func TestIsPrivateModeExempt(t *testing.T) {
 		{http.MethodGet, apiV2Prefix + audioapi.HLSGroupPath + audioapi.HLSStatusPath},
 		{http.MethodGet, apiV2Prefix + audioapi.HLSGroupPath + audioapi.HLSTokenGroupPath + audioapi.HLSPlaylistPath},
 		{http.MethodGet, apiV2Prefix + audioapi.HLSGroupPath + audioapi.HLSTokenGroupPath + audioapi.HLSContentPath},
+		{http.MethodGet, apiV2Prefix + weather.WeatherGroupPath + weather.StationUploadPath},
+		{http.MethodPost, apiV2Prefix + weather.WeatherGroupPath + weather.StationUploadPath},
 	}
 	for _, tt := range exempt {
 		t.Run("exempt/"+tt.method+"_"+tt.path, func(t *testing.T) {
@@ -70,6 +73,8 @@ func TestIsPrivateModeExempt(t *testing.T) {
 		{http.MethodGet, apiV2Prefix + authapi.AuthGroupPath + authapi.AuthLoginPath}, // login is POST-only
 		{http.MethodPost, apiV2Prefix + app.AppConfigEndpoint},                        // config is GET-only
 		{http.MethodDelete, apiV2Prefix + app.AppConfigEndpoint},
+		{http.MethodDelete, apiV2Prefix + weather.WeatherGroupPath + weather.StationUploadPath},
+		{http.MethodGet, apiV2Prefix + weather.WeatherGroupPath + "/latest"},
 	}
 	for _, tt := range notExempt {
 		t.Run("not_exempt/"+tt.method+"_"+tt.path, func(t *testing.T) {
@@ -116,6 +121,12 @@ func TestPrivateModeExemptPathsAreRegisteredRoutes(t *testing.T) {
 	hlsTokenGroup.GET(audioapi.HLSPlaylistPath, noop)
 	hlsTokenGroup.GET(audioapi.HLSContentPath, noop)
 
+	// Weather station uploads (mirrors the weather domain's RegisterRoutes).
+	weatherGroup := g.Group(weather.WeatherGroupPath)
+	weatherGroup.GET(weather.StationUploadPath, noop)
+	weatherGroup.POST(weather.StationUploadPath, noop)
+	weatherGroup.GET("/latest", noop) // registered but NOT exempt under PrivateMode
+
 	key := func(method, path string) string { return method + " " + path }
 
 	// Expected exempt set, with paths composed from the same constants the
@@ -129,6 +140,8 @@ func TestPrivateModeExemptPathsAreRegisteredRoutes(t *testing.T) {
 		key(http.MethodGet, apiV2Prefix+audioapi.HLSGroupPath+audioapi.HLSStatusPath):                              true,
 		key(http.MethodGet, apiV2Prefix+audioapi.HLSGroupPath+audioapi.HLSTokenGroupPath+audioapi.HLSPlaylistPath): true,
 		key(http.MethodGet, apiV2Prefix+audioapi.HLSGroupPath+audioapi.HLSTokenGroupPath+audioapi.HLSContentPath):  true,
+		key(http.MethodGet, apiV2Prefix+weather.WeatherGroupPath+weather.StationUploadPath):                        true,
+		key(http.MethodPost, apiV2Prefix+weather.WeatherGroupPath+weather.StationUploadPath):                       true,
 	}
 
 	registered := make(map[string]bool)
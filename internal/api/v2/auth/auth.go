@@ -1,5 +1,6 @@
 // Package authapi is the api/v2 auth domain handler. It owns the
-// /api/v2/auth/* endpoints (login, OAuth callback, logout, and auth status).
+// /api/v2/auth/* endpoints (login, OAuth callback, logout, auth status and API
+// key management).
 // The Handler embeds *apicore.Core by pointer so the shared dependencies and
 // helpers (HandleError, HandleErrorWithKey, the logging/security-logging
 // helpers, and the AuthMiddleware field) promote onto it.
@@ -81,9 +82,11 @@ type AuthResponse struct {
 
 // AuthStatus represents the current authentication status
 type AuthStatus struct {
-	Authenticated bool   `json:"authenticated"`
-	Username      string `json:"username,omitempty"`
-	Method        string `json:"auth_method,omitempty"`
+	Authenticated bool     `json:"authenticated"`
+	Username      string   `json:"username,omitempty"`
+	Method        string   `json:"auth_method,omitempty"`
+	Role          string   `json:"role,omitempty"`   // viewer, reviewer or admin
+	Scopes        []string `json:"scopes,omitempty"` // only for API keys
 }
 
 // Auth route path fragments, registered relative to the v2 API group in
@@ -109,6 +112,10 @@ type Handler struct {
 	// there via the WithAuthService functional option). The handlers nil-guard
 	// it; an unconfigured service degrades exactly as in the monolith.
 	authService auth.Service
+
+	// apiKeys manages API keys; nil until registerKeyRoutes builds it, which
+	// only happens when the enhanced v2 database is active.
+	apiKeys *auth.APIKeyStore
 }
 
 // New constructs the auth domain handler around the shared core and the
@@ -171,9 +178,11 @@ func (c *Handler) RegisterRoutes(g *echo.Group) {
 	authGroup.GET(AuthCallbackPath, c.OAuthCallback)
 
 	// Routes that require authentication
-	protectedGroup := authGroup.Group("", c.AuthMiddleware)
+	protectedGroup := authGroup.Group("", c.RequireRole(security.RoleViewer))
 	protectedGroup.POST(AuthLogoutPath, c.Logout)
 	protectedGroup.GET(AuthStatusPath, c.GetAuthStatus)
+
+	c.registerKeyRoutes(authGroup)
 }
 
 // Login handles POST /api/v2/auth/login
@@ -396,12 +405,17 @@ func (c *Handler) GetAuthStatus(ctx echo.Context) error {
 		Authenticated: isAuthenticated,
 		Username:      username,
 		Method:        authMethod,
+		Role:          string(auth.RoleFromContext(ctx)),
+	}
+	for _, scope := range auth.ScopesFromContext(ctx) {
+		status.Scopes = append(status.Scopes, string(scope))
 	}
 
 	c.LogSecurityInfoIfEnabled("Auth status check",
 		logger.Bool("authenticated", status.Authenticated),
 		logger.Username(status.Username),
 		logger.String("method", status.Method),
+		logger.String("role", status.Role),
 		logger.String("ip", ctx.RealIP()),
 		logger.String("path", ctx.Request().URL.Path),
 		logger.String("user_agent", ctx.Request().Header.Get("User-Agent")),
@@ -27,5 +27,9 @@ func TestRegisterRoutesRegistration(t *testing.T) {
 		"GET /api/v2/auth/callback",
 		"POST /api/v2/auth/logout",
 		"GET /api/v2/auth/status",
+		"GET /api/v2/auth/keys",
+		"POST /api/v2/auth/keys",
+		"GET /api/v2/auth/keys/scopes",
+		"DELETE /api/v2/auth/keys/:id",
 	})
 }
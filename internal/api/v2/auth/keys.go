@@ -0,0 +1,211 @@
+package authapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/security"
+)
+
+// AuthKeysPath is the API key management route fragment, relative to
+// AuthGroupPath.
+const AuthKeysPath = "/keys"
+
+// maxAPIKeyNameLength matches the size of the api_keys.name column.
+const maxAPIKeyNameLength = 100
+
+// API key states reported by APIKeyResponse.Status.
+const (
+	apiKeyStatusActive  = "active"
+	apiKeyStatusExpired = "expired"
+	apiKeyStatusRevoked = "revoked"
+)
+
+// CreateAPIKeyRequest is the body of POST /api/v2/auth/keys. ExpiresAt is
+// optional; a key without it is valid until revoked.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyResponse describes a stored API key. The secret itself is never
+// returned after creation.
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Status     string     `json:"status"`
+	CreatedBy  string     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateAPIKeyResponse is returned once, when a key is created. Key is the
+// only copy of the secret.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// registerKeyRoutes registers the API key management endpoints. They require
+// the admin role; API keys cannot manage other API keys. Authentication runs
+// before the store check so unauthenticated callers get 401 rather than
+// learning about the database state.
+func (c *Handler) registerKeyRoutes(authGroup *echo.Group) {
+	keys := authGroup.Group(AuthKeysPath, c.AuthMiddleware, c.requireAPIKeyStore)
+	keys.GET("", c.ListAPIKeys)
+	keys.POST("", c.CreateAPIKey)
+	keys.GET("/scopes", c.ListAPIKeyScopes)
+	keys.DELETE("/:id", c.RevokeAPIKey)
+
+	if c.apiKeys == nil && c.V2Manager != nil && datastoreV2.IsEnhancedDatabase() {
+		c.apiKeys = auth.NewAPIKeyStore(repository.NewAPIKeyRepository(c.V2Manager.DB(), nil))
+	}
+}
+
+// requireAPIKeyStore answers 409 Conflict when the v2 datastore, which holds
+// the keys, is unavailable.
+func (c *Handler) requireAPIKeyStore(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if c.apiKeys == nil {
+			return c.HandleError(ctx, nil, "API keys require the enhanced (v2) database", http.StatusConflict)
+		}
+		return next(ctx)
+	}
+}
+
+// ListAPIKeyScopes handles GET /api/v2/auth/keys/scopes
+func (c *Handler) ListAPIKeyScopes(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"scopes": security.Scopes,
+	})
+}
+
+// ListAPIKeys handles GET /api/v2/auth/keys
+func (c *Handler) ListAPIKeys(ctx echo.Context) error {
+	keys, err := c.apiKeys.List(ctx.Request().Context())
+	if err != nil {
+		c.LogErrorIfEnabled("failed to list API keys", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to list API keys", http.StatusInternalServerError)
+	}
+
+	now := time.Now()
+	resp := make([]APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		resp = append(resp, newAPIKeyResponse(&keys[i], now))
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"keys":  resp,
+		"count": len(resp),
+	})
+}
+
+// CreateAPIKey handles POST /api/v2/auth/keys
+func (c *Handler) CreateAPIKey(ctx echo.Context) error {
+	var req CreateAPIKeyRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return c.HandleError(ctx, nil, "Name is required", http.StatusBadRequest)
+	}
+	if len(name) > maxAPIKeyNameLength {
+		return c.HandleError(ctx, nil, "Name is too long", http.StatusBadRequest)
+	}
+	scopes, err := security.ParseScopes(req.Scopes)
+	if err != nil {
+		return c.HandleError(ctx, err, "Scopes must be one or more of detections:read, reviews:write, control or settings", http.StatusBadRequest)
+	}
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return c.HandleError(ctx, nil, "Expiry must be in the future", http.StatusBadRequest)
+	}
+
+	createdBy := auth.UsernameFromContext(ctx)
+	key, entity, err := c.apiKeys.Create(ctx.Request().Context(), name, scopes, req.ExpiresAt, createdBy)
+	if err != nil {
+		c.LogErrorIfEnabled("failed to create API key", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to create API key", http.StatusInternalServerError)
+	}
+
+	c.LogSecurityInfoIfEnabled("API key created",
+		logger.Uint64("key_id", uint64(entity.ID)),
+		logger.String("name", entity.Name),
+		logger.String("scopes", entity.Scopes),
+		logger.Username(createdBy),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusCreated, CreateAPIKeyResponse{
+		APIKeyResponse: newAPIKeyResponse(entity, now),
+		Key:            key,
+	})
+}
+
+// RevokeAPIKey handles DELETE /api/v2/auth/keys/:id. The key stops working
+// immediately but stays listed as revoked.
+func (c *Handler) RevokeAPIKey(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid API key ID", http.StatusBadRequest)
+	}
+
+	if err := c.apiKeys.Revoke(ctx.Request().Context(), uint(id)); err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return c.HandleError(ctx, err, "API key not found", http.StatusNotFound)
+		}
+		c.LogErrorIfEnabled("failed to revoke API key", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to revoke API key", http.StatusInternalServerError)
+	}
+
+	c.LogSecurityInfoIfEnabled("API key revoked",
+		logger.Uint64("key_id", id),
+		logger.Username(auth.UsernameFromContext(ctx)),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// newAPIKeyResponse converts a stored key for the API.
+func newAPIKeyResponse(key *entities.APIKey, now time.Time) APIKeyResponse {
+	status := apiKeyStatusActive
+	switch {
+	case key.RevokedAt != nil:
+		status = apiKeyStatusRevoked
+	case !auth.APIKeyActive(key, now):
+		status = apiKeyStatusExpired
+	}
+
+	scopes := auth.APIKeyScopes(key)
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     names,
+		Status:     status,
+		CreatedBy:  key.CreatedBy,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
@@ -0,0 +1,133 @@
+package authapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/api/v2/apitest"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+)
+
+// setupKeysHandler registers the auth routes on a fresh Echo with the API key
+// store backed by a temporary SQLite database.
+func setupKeysHandler(t *testing.T, withStore bool) (*echo.Echo, *auth.APIKeyStore) {
+	t.Helper()
+	e := echo.New()
+	core := apitest.NewCore(t, apitest.WithEcho(e))
+	core.AuthMiddleware = func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+
+	h := New(core, nil)
+	if withStore {
+		db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "keys.db")), &gorm.Config{
+			Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+		})
+		require.NoError(t, err)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+		require.NoError(t, db.AutoMigrate(&entities.APIKey{}))
+		h.apiKeys = auth.NewAPIKeyStore(repository.NewAPIKeyRepository(db, nil))
+	}
+	h.RegisterRoutes(core.Group)
+	return e, h.apiKeys
+}
+
+func doKeysRequest(t *testing.T, e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAPIKeyRoutesReturn409WithoutV2(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, _ := setupKeysHandler(t, false)
+	rec := doKeysRequest(t, e, http.MethodGet, "/api/v2/auth/keys", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestAPIKeyRoutesAuthenticateBeforeStoreCheck(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e := echo.New()
+	core := apitest.NewCore(t, apitest.WithEcho(e))
+	core.AuthMiddleware = func(echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			return ctx.NoContent(http.StatusUnauthorized)
+		}
+	}
+	New(core, nil).RegisterRoutes(core.Group)
+
+	rec := doKeysRequest(t, e, http.MethodGet, "/api/v2/auth/keys", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, store := setupKeysHandler(t, true)
+
+	rec := doKeysRequest(t, e, http.MethodPost, "/api/v2/auth/keys",
+		`{"name":"Grafana","scopes":["detections:read"]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var created CreateAPIKeyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.True(t, auth.IsAPIKey(created.Key))
+	assert.Equal(t, []string{"detections:read"}, created.Scopes)
+	assert.Equal(t, apiKeyStatusActive, created.Status)
+
+	_, err := store.Verify(t.Context(), created.Key)
+	require.NoError(t, err)
+
+	rec = doKeysRequest(t, e, http.MethodGet, "/api/v2/auth/keys", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"count":1`)
+	assert.NotContains(t, rec.Body.String(), created.Key, "the secret is only returned on creation")
+
+	rec = doKeysRequest(t, e, http.MethodDelete, "/api/v2/auth/keys/"+strconv.FormatUint(uint64(created.ID), 10), "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	_, err = store.Verify(t.Context(), created.Key)
+	require.ErrorIs(t, err, auth.ErrInvalidAPIKey)
+
+	rec = doKeysRequest(t, e, http.MethodGet, "/api/v2/auth/keys", "")
+	assert.Contains(t, rec.Body.String(), `"status":"revoked"`)
+
+	rec = doKeysRequest(t, e, http.MethodDelete, "/api/v2/auth/keys/999", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestCreateAPIKeyValidation(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, _ := setupKeysHandler(t, true)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing name", `{"scopes":["control"]}`},
+		{"missing scopes", `{"name":"script"}`},
+		{"unknown scope", `{"name":"script","scopes":["admin"]}`},
+		{"expiry in the past", `{"name":"script","scopes":["control"],"expires_at":"2000-01-01T00:00:00Z"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doKeysRequest(t, e, http.MethodPost, "/api/v2/auth/keys", tt.body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
@@ -16,8 +16,10 @@
 package control
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -26,6 +28,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/restart"
+	"github.com/tphakala/birdnet-go/internal/security"
 	"github.com/tphakala/birdnet-go/internal/sysinfo"
 	"github.com/tphakala/birdnet-go/internal/telemetry"
 )
@@ -54,6 +57,16 @@ const (
 	ActionRestartAudioSource = "restart_audio_source"
 )
 
+// Runtime control actions. They are run through RunAction only (alert rules
+// and the MQTT command topics) and change runtime state, not the configuration.
+const (
+	ActionPauseAudioSource  = "pause_audio_source"
+	ActionResumeAudioSource = "resume_audio_source"
+	ActionEnableQuietHours  = "enable_quiet_hours"
+	ActionSuspendQuietHours = "suspend_quiet_hours"
+	ActionRunBackup         = "run_backup"
+)
+
 // Control channel signals
 const (
 	SignalRestartAnalysis = "restart_analysis"
@@ -64,6 +77,21 @@ const (
 // SourceRestarterFunc restarts a single audio source identified by sourceID.
 type SourceRestarterFunc func(sourceID string) error
 
+// SourceScheduler pauses audio sources and suspends quiet hours at runtime.
+// The quiet hours scheduler implements it; sources are identified by their
+// connection string (stream URL or sound card device).
+type SourceScheduler interface {
+	PauseSource(connection string)
+	ResumeSource(connection string)
+	SetQuietHoursSuspended(suspended bool)
+}
+
+// backupTrigger runs an immediate backup. The backup scheduler held by the
+// processor implements it.
+type backupTrigger interface {
+	TriggerBackup(ctx context.Context) error
+}
+
 // Handler serves the control domain endpoints. It embeds *apicore.Core BY
 // POINTER so the shared Core members promote onto it without re-wiring; Core
 // carries atomic/lock-bearing fields and must never be copied by value.
@@ -79,6 +107,9 @@ type Handler struct {
 
 	controlChan     chan<- string
 	sourceRestarter atomic.Pointer[SourceRestarterFunc]
+
+	schedulerMu sync.RWMutex
+	scheduler   SourceScheduler
 }
 
 // New builds a control Handler around the shared core and the shared
@@ -98,8 +129,8 @@ func New(core *apicore.Core, controlChan chan<- string) *Handler {
 func (c *Handler) RegisterRoutes(g *echo.Group) {
 	c.LogInfoIfEnabled("Initializing control routes")
 
-	// Create control API group with auth middleware
-	controlGroup := g.Group("/control", c.AuthMiddleware)
+	// Create control API group; API keys need the control scope
+	controlGroup := g.Group("/control", c.RequireScope(security.ScopeControl))
 
 	// Control routes
 	controlGroup.POST("/restart", c.RestartAnalysis)
@@ -331,6 +362,21 @@ func (c *Handler) SetSourceRestarter(fn SourceRestarterFunc) {
 	c.sourceRestarter.Store(&fn)
 }
 
+// SetSourceScheduler injects the scheduler used by the pause, resume and quiet
+// hours actions.
+func (c *Handler) SetSourceScheduler(s SourceScheduler) {
+	c.schedulerMu.Lock()
+	defer c.schedulerMu.Unlock()
+	c.scheduler = s
+}
+
+// sourceScheduler returns the injected scheduler, or nil before injection.
+func (c *Handler) sourceScheduler() SourceScheduler {
+	c.schedulerMu.RLock()
+	defer c.schedulerMu.RUnlock()
+	return c.scheduler
+}
+
 // RestartAudioSource handles POST /api/v2/control/restart-source/:id
 // Restarts a single audio source without affecting the rest of the pipeline.
 func (c *Handler) RestartAudioSource(ctx echo.Context) error {
@@ -384,3 +430,136 @@ func (c *Handler) RestartAudioSource(ctx echo.Context) error {
 		Timestamp: time.Now(),
 	})
 }
+
+// RunAction performs a control action outside of an HTTP request. Alert rules
+// use it to restart a failing stream or reload the model. Only the actions
+// that leave the process running are supported; restarting the server or
+// container is left to the HTTP endpoints. For the restart, pause and resume
+// source actions, arg is the ID of the source.
+func (c *Handler) RunAction(ctx context.Context, action, arg string) error {
+	switch action {
+	case ActionRestartAnalysis:
+		return c.sendControlSignal(ctx, SignalRestartAnalysis)
+	case ActionReloadModel:
+		return c.sendControlSignal(ctx, SignalReloadModel)
+	case ActionRebuildFilter:
+		return c.sendControlSignal(ctx, SignalRebuildFilter)
+	case ActionRestartAudioSource:
+		return c.restartSource(arg)
+	case ActionPauseAudioSource:
+		return c.pauseSource(arg, true)
+	case ActionResumeAudioSource:
+		return c.pauseSource(arg, false)
+	case ActionEnableQuietHours:
+		return c.suspendQuietHours(false)
+	case ActionSuspendQuietHours:
+		return c.suspendQuietHours(true)
+	case ActionRunBackup:
+		return c.runBackup(ctx)
+	default:
+		return fmt.Errorf("unsupported control action %q", action)
+	}
+}
+
+// sendControlSignal sends signal on the control channel, giving up when ctx
+// ends or the server shuts down.
+func (c *Handler) sendControlSignal(ctx context.Context, signal string) error {
+	if c.controlChan == nil {
+		return fmt.Errorf("control channel not initialized")
+	}
+	select {
+	case c.controlChan <- signal:
+		c.LogInfoIfEnabled("Control signal sent", logger.String("signal", signal))
+		return nil
+	case <-c.Context().Done():
+		return c.Context().Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// restartSource restarts a single audio source through the injected
+// source restarter.
+func (c *Handler) restartSource(sourceID string) error {
+	if sourceID == "" {
+		return fmt.Errorf("source ID is required")
+	}
+	eng := c.Engine.Load()
+	if eng == nil {
+		return fmt.Errorf("audio engine not initialized")
+	}
+	if _, ok := eng.Registry().Get(sourceID); !ok {
+		return fmt.Errorf("source %s not found", sourceID)
+	}
+	fn := c.sourceRestarter.Load()
+	if fn == nil {
+		return fmt.Errorf("source restarter not initialized")
+	}
+	if err := (*fn)(sourceID); err != nil {
+		return err
+	}
+	c.LogInfoIfEnabled("Audio source restarted", logger.String("source_id", sourceID))
+	return nil
+}
+
+// pauseSource pauses or resumes a single audio source through the quiet hours
+// scheduler. A paused source stays stopped until it is resumed or the process
+// restarts.
+func (c *Handler) pauseSource(sourceID string, pause bool) error {
+	if sourceID == "" {
+		return fmt.Errorf("source ID is required")
+	}
+	sched := c.sourceScheduler()
+	if sched == nil {
+		return fmt.Errorf("source scheduler not initialized")
+	}
+	eng := c.Engine.Load()
+	if eng == nil {
+		return fmt.Errorf("audio engine not initialized")
+	}
+	connection, ok := eng.Registry().ConnectionStringByID(sourceID)
+	if !ok {
+		return fmt.Errorf("source %s not found", sourceID)
+	}
+
+	if pause {
+		sched.PauseSource(connection)
+		c.LogInfoIfEnabled("Audio source paused", logger.String("source_id", sourceID))
+	} else {
+		sched.ResumeSource(connection)
+		c.LogInfoIfEnabled("Audio source resumed", logger.String("source_id", sourceID))
+	}
+	return nil
+}
+
+// suspendQuietHours suspends or re-enables quiet hours without changing the
+// configuration.
+func (c *Handler) suspendQuietHours(suspend bool) error {
+	sched := c.sourceScheduler()
+	if sched == nil {
+		return fmt.Errorf("source scheduler not initialized")
+	}
+	sched.SetQuietHoursSuspended(suspend)
+	c.LogInfoIfEnabled("Quiet hours toggled", logger.Bool("suspended", suspend))
+	return nil
+}
+
+// runBackup runs an immediate backup through the backup scheduler. It blocks
+// until the backup completes.
+func (c *Handler) runBackup(ctx context.Context) error {
+	if settings := c.CurrentSettings(); settings == nil || !settings.Backup.Enabled {
+		return fmt.Errorf("backups are disabled")
+	}
+	if c.Processor == nil {
+		return fmt.Errorf("processor not initialized")
+	}
+	trigger, ok := c.Processor.GetBackupScheduler().(backupTrigger)
+	if !ok {
+		return fmt.Errorf("backup system not initialized")
+	}
+	if err := trigger.TriggerBackup(ctx); err != nil {
+		return err
+	}
+	c.LogInfoIfEnabled("Backup completed")
+	return nil
+}
@@ -0,0 +1,73 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/mqtt"
+)
+
+// HandleMQTTCommand runs a command received on the MQTT command topics by
+// mapping it onto a control action. Buttons ignore the payload; switches
+// expect ON or OFF.
+func (c *Handler) HandleMQTTCommand(ctx context.Context, cmd mqtt.Command) error {
+	action, arg, err := mqttCommandAction(cmd)
+	if err != nil {
+		return err
+	}
+	return c.RunAction(ctx, action, arg)
+}
+
+// mqttCommandAction returns the control action and argument of an MQTT command.
+func mqttCommandAction(cmd mqtt.Command) (action, arg string, err error) {
+	if cmd.SourceID != "" {
+		switch cmd.Name {
+		case mqtt.CommandRestartSource:
+			return ActionRestartAudioSource, cmd.SourceID, nil
+		case mqtt.CommandCapture:
+			on, err := switchPayload(cmd.Payload)
+			if err != nil {
+				return "", "", err
+			}
+			if on {
+				return ActionResumeAudioSource, cmd.SourceID, nil
+			}
+			return ActionPauseAudioSource, cmd.SourceID, nil
+		default:
+			return "", "", fmt.Errorf("unknown MQTT source command %q", cmd.Name)
+		}
+	}
+
+	switch cmd.Name {
+	case mqtt.CommandRestartAnalysis:
+		return ActionRestartAnalysis, "", nil
+	case mqtt.CommandReloadModel:
+		return ActionReloadModel, "", nil
+	case mqtt.CommandBackup:
+		return ActionRunBackup, "", nil
+	case mqtt.CommandQuietHours:
+		on, err := switchPayload(cmd.Payload)
+		if err != nil {
+			return "", "", err
+		}
+		if on {
+			return ActionEnableQuietHours, "", nil
+		}
+		return ActionSuspendQuietHours, "", nil
+	default:
+		return "", "", fmt.Errorf("unknown MQTT command %q", cmd.Name)
+	}
+}
+
+// switchPayload parses an ON/OFF switch payload, case-insensitively.
+func switchPayload(payload string) (bool, error) {
+	switch {
+	case strings.EqualFold(payload, mqtt.PayloadOn):
+		return true, nil
+	case strings.EqualFold(payload, mqtt.PayloadOff):
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid switch payload %q, expected %s or %s", payload, mqtt.PayloadOn, mqtt.PayloadOff)
+	}
+}
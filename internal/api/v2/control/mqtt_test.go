@@ -0,0 +1,114 @@
+package control
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/audiocore"
+	"github.com/tphakala/birdnet-go/internal/audiocore/engine"
+	"github.com/tphakala/birdnet-go/internal/mqtt"
+)
+
+// fakeScheduler records the calls of the source scheduler.
+type fakeScheduler struct {
+	mu        sync.Mutex
+	paused    map[string]bool
+	suspended bool
+}
+
+func (f *fakeScheduler) PauseSource(connection string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paused[connection] = true
+}
+
+func (f *fakeScheduler) ResumeSource(connection string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.paused, connection)
+}
+
+func (f *fakeScheduler) SetQuietHoursSuspended(suspended bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.suspended = suspended
+}
+
+func TestMQTTCommandAction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cmd     mqtt.Command
+		action  string
+		arg     string
+		wantErr bool
+	}{
+		{"reload model", mqtt.Command{Name: mqtt.CommandReloadModel, Payload: mqtt.PayloadPress}, ActionReloadModel, "", false},
+		{"restart analysis", mqtt.Command{Name: mqtt.CommandRestartAnalysis}, ActionRestartAnalysis, "", false},
+		{"backup", mqtt.Command{Name: mqtt.CommandBackup}, ActionRunBackup, "", false},
+		{"quiet hours on", mqtt.Command{Name: mqtt.CommandQuietHours, Payload: "on"}, ActionEnableQuietHours, "", false},
+		{"quiet hours off", mqtt.Command{Name: mqtt.CommandQuietHours, Payload: mqtt.PayloadOff}, ActionSuspendQuietHours, "", false},
+		{"quiet hours bad payload", mqtt.Command{Name: mqtt.CommandQuietHours, Payload: "toggle"}, "", "", true},
+		{"restart source", mqtt.Command{Name: mqtt.CommandRestartSource, SourceID: "rtsp_1"}, ActionRestartAudioSource, "rtsp_1", false},
+		{"pause source", mqtt.Command{Name: mqtt.CommandCapture, SourceID: "rtsp_1", Payload: mqtt.PayloadOff}, ActionPauseAudioSource, "rtsp_1", false},
+		{"resume source", mqtt.Command{Name: mqtt.CommandCapture, SourceID: "rtsp_1", Payload: mqtt.PayloadOn}, ActionResumeAudioSource, "rtsp_1", false},
+		{"unknown command", mqtt.Command{Name: "shutdown"}, "", "", true},
+		{"station command on source", mqtt.Command{Name: mqtt.CommandBackup, SourceID: "rtsp_1"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			action, arg, err := mqttCommandAction(tt.cmd)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.action, action)
+			assert.Equal(t, tt.arg, arg)
+		})
+	}
+}
+
+func TestHandleMQTTCommand_PauseAndQuietHours(t *testing.T) {
+	t.Parallel()
+	c := newControlHandler(t, echo.New())
+
+	cmd := mqtt.Command{Name: mqtt.CommandCapture, SourceID: "rtsp_1", Payload: mqtt.PayloadOff}
+	require.Error(t, c.HandleMQTTCommand(t.Context(), cmd), "fails before the scheduler is injected")
+
+	sched := &fakeScheduler{paused: make(map[string]bool)}
+	c.SetSourceScheduler(sched)
+	eng := engine.New(t.Context(), &engine.Config{}, nil)
+	defer eng.Stop()
+	c.Engine.Store(eng)
+	_, err := eng.Registry().Register(&audiocore.SourceConfig{
+		ID:               "rtsp_1",
+		DisplayName:      "Garden",
+		Type:             audiocore.SourceTypeRTSP,
+		ConnectionString: "rtsp://cam/garden",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, c.HandleMQTTCommand(t.Context(), cmd))
+	assert.True(t, sched.paused["rtsp://cam/garden"], "sources are paused by connection string")
+
+	cmd.Payload = mqtt.PayloadOn
+	require.NoError(t, c.HandleMQTTCommand(t.Context(), cmd))
+	assert.Empty(t, sched.paused)
+
+	require.Error(t, c.HandleMQTTCommand(t.Context(), mqtt.Command{Name: mqtt.CommandCapture, SourceID: "missing", Payload: mqtt.PayloadOff}))
+
+	require.NoError(t, c.HandleMQTTCommand(t.Context(), mqtt.Command{Name: mqtt.CommandQuietHours, Payload: mqtt.PayloadOff}))
+	assert.True(t, sched.suspended)
+}
+
+func TestRunAction_BackupDisabled(t *testing.T) {
+	t.Parallel()
+	c := newControlHandler(t, echo.New())
+	require.ErrorContains(t, c.RunAction(t.Context(), ActionRunBackup, ""), "backups are disabled")
+}
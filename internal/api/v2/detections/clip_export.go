@@ -0,0 +1,266 @@
+package detections
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/tphakala/birdnet-go/internal/annotation"
+	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/spectrogram"
+)
+
+// maxClipExportDetections caps how many detections a search clip export may
+// bundle. Clips are read from disk and streamed into one archive, so larger
+// result sets must be narrowed by the search filters first.
+const maxClipExportDetections = 500
+
+// Annotation file suffixes. The Raven suffix is the name Raven Pro gives a
+// saved selection table, so Raven opens the table together with its clip.
+const (
+	ravenTableSuffix    = ".Table.1.selections.txt"
+	audacityLabelSuffix = ".labels.txt"
+)
+
+// clipExportEntry is a detection clip resolved for export.
+type clipExportEntry struct {
+	relPath   string // clip path relative to the clips SecureFS root
+	name      string // clip file name inside the archive
+	selection annotation.Selection
+}
+
+// ExportDetectionClip returns a ZIP with the detection's audio clip, a Raven
+// selection table and an Audacity label track marking where the detection
+// sits inside the clip.
+//
+// GET /api/v2/detections/:id/clip-export
+func (c *Handler) ExportDetectionClip(ctx echo.Context) error {
+	id := ctx.Param("id")
+	note, err := c.DS.Get(id)
+	if err != nil {
+		return c.HandleError(ctx, err, "Detection not found", http.StatusNotFound)
+	}
+
+	entries := c.resolveClipExportEntries([]datastore.Note{note})
+	if len(entries) == 0 {
+		return c.HandleError(ctx, fmt.Errorf("detection %s has no audio clip", id),
+			"No audio clip available for this detection", http.StatusNotFound)
+	}
+	return c.writeClipExport(ctx, fmt.Sprintf("detection-%s-clip.zip", id), entries)
+}
+
+// ExportSearchClips runs a detection search and returns a ZIP with the clip,
+// Raven selection table and Audacity label track of every matching
+// detection. The request body is the same as POST /api/v2/search; pagination
+// is ignored and the whole result set, up to maxClipExportDetections, is
+// exported.
+//
+// POST /api/v2/search/clip-export
+func (c *Handler) ExportSearchClips(ctx echo.Context) error {
+	ip := ctx.RealIP()
+	path := ctx.Request().URL.Path
+
+	var req SearchRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+	if err := c.validateAndNormalizeSearchRequest(ctx, &req); err != nil {
+		return c.HandleError(ctx, err, "Invalid search parameters", http.StatusBadRequest)
+	}
+	c.resolveSearchSpecies(&req, path, ip)
+
+	ctxTimeout, cancel := context.WithTimeout(ctx.Request().Context(), defaultSearchTimeout)
+	defer cancel()
+
+	filters := c.buildSearchFilters(&req, ctxTimeout)
+	filters.Page = 1
+	filters.PerPage = maxClipExportDetections
+	results, total, err := c.DS.SearchDetections(&filters)
+	if err != nil {
+		return c.HandleError(ctx, err, "Search failed", http.StatusInternalServerError)
+	}
+	if total > maxClipExportDetections {
+		return c.HandleError(ctx,
+			fmt.Errorf("search matched %d detections, export limit is %d", total, maxClipExportDetections),
+			fmt.Sprintf("Search matches too many detections for a clip export, narrow it to at most %d", maxClipExportDetections),
+			http.StatusBadRequest)
+	}
+
+	notes := make([]datastore.Note, 0, len(results))
+	for i := range results {
+		note, err := c.DS.Get(results[i].ID)
+		if err != nil {
+			c.LogWarnIfEnabled("Skipping detection in clip export",
+				logger.String("detection_id", results[i].ID),
+				logger.Error(err))
+			continue
+		}
+		notes = append(notes, note)
+	}
+
+	entries := c.resolveClipExportEntries(notes)
+	if len(entries) == 0 {
+		return c.HandleError(ctx, fmt.Errorf("no clips in %d search results", len(results)),
+			"No audio clips available for the search results", http.StatusNotFound)
+	}
+
+	c.LogInfoIfEnabled("Exporting search result clips",
+		logger.Int("detections", len(results)),
+		logger.Int("clips", len(entries)),
+		logger.String("path", path),
+		logger.String("ip", ip))
+	return c.writeClipExport(ctx, fmt.Sprintf("detection-clips-%s.zip", time.Now().Format("20060102-150405")), entries)
+}
+
+// resolveClipExportEntries maps notes to the clips on disk. Notes without a
+// clip, or whose clip is missing or outside the clips directory, are skipped.
+// Archive names are the clip file names, prefixed with the detection ID when
+// two clips share a name.
+func (c *Handler) resolveClipExportEntries(notes []datastore.Note) []clipExportEntry {
+	settings := c.CurrentSettings()
+	clipsPrefix := settings.Realtime.Audio.Export.Path
+	seen := make(map[string]bool, len(notes))
+
+	entries := make([]clipExportEntry, 0, len(notes))
+	for i := range notes {
+		note := &notes[i]
+		if note.ClipName == "" {
+			continue
+		}
+		relPath, err := c.SFS.ValidateRelativePath(apicore.NormalizeClipPath(note.ClipName, clipsPrefix))
+		if err == nil {
+			_, err = c.SFS.StatRel(relPath)
+		}
+		if err != nil {
+			c.LogWarnIfEnabled("Skipping clip in clip export",
+				logger.Uint64("detection_id", uint64(note.ID)),
+				logger.String("clip", note.ClipName),
+				logger.Error(err))
+			continue
+		}
+
+		name := filepath.Base(relPath)
+		if seen[name] {
+			name = fmt.Sprintf("%d_%s", note.ID, name)
+		}
+		seen[name] = true
+
+		entries = append(entries, clipExportEntry{
+			relPath:   relPath,
+			name:      name,
+			selection: clipSelection(note, name, settings),
+		})
+	}
+	return entries
+}
+
+// clipSelection places a detection inside its saved clip. The clip is read
+// from the note's BeginTime, which the analysis pipeline sets Export.PreCapture
+// seconds before the analysed audio, so the detection starts at the
+// pre-capture offset and spans the note's BeginTime..EndTime window, capped at
+// the captured clip length. The frequency band is the detection model's
+// spectrogram frequency profile.
+func clipSelection(note *datastore.Note, file string, settings *conf.Settings) annotation.Selection {
+	win, _ := settings.DetectionCaptureWindow(note.BeginTime, note.EndTime)
+	clipLength := time.Duration(win.Length) * time.Second
+
+	begin := min(time.Duration(settings.Realtime.Audio.Export.PreCapture)*time.Second, clipLength)
+	end := clipLength
+	if !note.BeginTime.IsZero() && note.EndTime.After(note.BeginTime) {
+		end = min(begin+note.EndTime.Sub(note.BeginTime), clipLength)
+	}
+
+	return annotation.Selection{
+		File:           file,
+		Begin:          begin,
+		End:            end,
+		LowFreq:        0,
+		HighFreq:       spectrogram.ProfileForModelType(note.Model.ModelType).MaxFrequency(),
+		ScientificName: note.ScientificName,
+		CommonName:     note.CommonName,
+		SpeciesCode:    note.SpeciesCode,
+		Confidence:     note.Confidence,
+	}
+}
+
+// writeClipExport streams the ZIP archive. Each clip is followed by its Raven
+// selection table and Audacity label track, named after the clip. Once the
+// response is committed, errors can only be logged.
+func (c *Handler) writeClipExport(ctx echo.Context, filename string, entries []clipExportEntry) error {
+	resp := ctx.Response()
+	resp.Header().Set(echo.HeaderContentType, "application/zip")
+	resp.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	resp.WriteHeader(http.StatusOK)
+
+	zw := zip.NewWriter(resp)
+	for i := range entries {
+		if err := ctx.Request().Context().Err(); err != nil {
+			return err
+		}
+		if err := c.writeClipExportEntry(zw, &entries[i]); err != nil {
+			c.LogErrorIfEnabled("Clip export aborted", logger.String("clip", entries[i].relPath), logger.Error(err))
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		c.LogErrorIfEnabled("Failed to finish clip export archive", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// writeClipExportEntry adds one clip and its annotation files to the archive.
+func (c *Handler) writeClipExportEntry(zw *zip.Writer, entry *clipExportEntry) error {
+	f, err := c.SFS.Open(filepath.Join(c.SFS.BaseDir(), entry.relPath))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	// Clips are already compressed audio or small WAVs, so store them as is.
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: entry.name, Method: zip.Store, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, f); err != nil {
+		return err
+	}
+
+	stem := strings.TrimSuffix(entry.name, filepath.Ext(entry.name))
+	selections := []annotation.Selection{entry.selection}
+
+	var buf bytes.Buffer
+	if err := annotation.WriteRavenTable(&buf, selections); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, stem+ravenTableSuffix, buf.Bytes()); err != nil {
+		return err
+	}
+
+	buf.Reset()
+	if err := annotation.WriteAudacityLabels(&buf, selections); err != nil {
+		return err
+	}
+	return writeZipFile(zw, stem+audacityLabelSuffix, buf.Bytes())
+}
+
+// writeZipFile adds a deflated file with the given contents to the archive.
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,189 @@
+package detections
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/api/v2/apitest"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+	"github.com/tphakala/birdnet-go/internal/detection"
+)
+
+// setupClipExportEnvironment is setupTestEnvironment with a 15 s clip length
+// and 3 s pre-capture padding.
+func setupClipExportEnvironment(t *testing.T) (*echo.Echo, *mocks.MockInterface, *Handler) {
+	t.Helper()
+	e := echo.New()
+	mockDS := mocks.NewMockInterface(t)
+	core := apitest.NewCore(t, apitest.WithEcho(e), apitest.WithDatastore(mockDS),
+		apitest.WithSettingsFunc(func(s *conf.Settings) {
+			s.Realtime.Audio.Export.Length = 15
+			s.Realtime.Audio.Export.PreCapture = 3
+		}))
+	return e, mockDS, buildTestHandler(t, core, map[string]string{}, map[string]string{})
+}
+
+// clipExportNote returns a detection whose clip exists under the handler's
+// clips directory.
+func clipExportNote(t *testing.T, h *Handler, id uint, clip string) datastore.Note {
+	t.Helper()
+	full := filepath.Join(h.SFS.BaseDir(), clip)
+	require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o750))
+	require.NoError(t, os.WriteFile(full, []byte("RIFF-clip-"+clip), 0o600))
+
+	begin := time.Date(2026, 5, 1, 5, 30, 0, 0, time.UTC)
+	return datastore.Note{
+		ID:             id,
+		ClipName:       clip,
+		BeginTime:      begin,
+		EndTime:        begin.Add(12 * time.Second),
+		ScientificName: "Turdus merula",
+		CommonName:     "Eurasian Blackbird",
+		SpeciesCode:    "eurbla",
+		Confidence:     0.91,
+	}
+}
+
+// readZip returns the archive's files by name.
+func readZip(t *testing.T, body []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	files := make(map[string]string, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		_ = rc.Close()
+		files[f.Name] = string(data)
+	}
+	return files
+}
+
+func TestClipSelection(t *testing.T) {
+	t.Parallel()
+	settings := &conf.Settings{}
+	settings.Realtime.Audio.Export.Length = 15
+	settings.Realtime.Audio.Export.PreCapture = 3
+
+	begin := time.Date(2026, 5, 1, 5, 30, 0, 0, time.UTC)
+	note := datastore.Note{BeginTime: begin, EndTime: begin.Add(12 * time.Second), Confidence: 0.5}
+
+	sel := clipSelection(&note, "clip.wav", settings)
+	assert.Equal(t, 3*time.Second, sel.Begin, "the detection starts after the pre-capture padding")
+	assert.Equal(t, 15*time.Second, sel.End)
+	assert.InDelta(t, 12000.0, sel.HighFreq, 0, "bird detections use the 0-12 kHz profile")
+	assert.Equal(t, "clip.wav", sel.File)
+
+	note.Model = detection.ModelInfo{ModelType: "bat"}
+	note.EndTime = begin.Add(2 * time.Second)
+	sel = clipSelection(&note, "clip.wav", settings)
+	assert.Equal(t, 5*time.Second, sel.End)
+	assert.InDelta(t, 128000.0, sel.HighFreq, 0, "bat detections use the 0-128 kHz profile")
+
+	note.EndTime = begin.Add(10 * time.Minute)
+	sel = clipSelection(&note, "clip.wav", settings)
+	assert.Equal(t, time.Duration(conf.DefaultCaptureBufferSeconds)*time.Second, sel.End,
+		"the window is capped at the captured clip length")
+}
+
+func TestExportDetectionClip(t *testing.T) {
+	t.Parallel()
+	e, mockDS, h := setupClipExportEnvironment(t)
+	note := clipExportNote(t, h, 7, "2026/05/turdus_merula_91p_20260501T053000Z.wav")
+	mockDS.On("Get", "7").Return(note, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/detections/7/clip-export", http.NoBody)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("7")
+
+	require.NoError(t, h.ExportDetectionClip(ctx))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "detection-7-clip.zip")
+
+	files := readZip(t, rec.Body.Bytes())
+	require.Len(t, files, 3)
+	assert.Equal(t, "RIFF-clip-"+note.ClipName, files["turdus_merula_91p_20260501T053000Z.wav"])
+
+	raven := strings.Split(strings.TrimSpace(files["turdus_merula_91p_20260501T053000Z.Table.1.selections.txt"]), "\n")
+	require.Len(t, raven, 2)
+	assert.Equal(t,
+		"1\tSpectrogram 1\t1\t3.000\t15.000\t0.0\t12000.0\tEurasian Blackbird\teurbla\t0.9100\tturdus_merula_91p_20260501T053000Z.wav\t3.000",
+		raven[1])
+	assert.Equal(t, "3.000\t15.000\tEurasian Blackbird 0.91\n\\\t0.0\t12000.0\n",
+		files["turdus_merula_91p_20260501T053000Z.labels.txt"])
+}
+
+func TestExportDetectionClip_NoClip(t *testing.T) {
+	t.Parallel()
+	e, mockDS, h := setupClipExportEnvironment(t)
+	mockDS.On("Get", "8").Return(datastore.Note{ID: 8, ClipName: "missing.wav"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/detections/8/clip-export", http.NoBody)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("8")
+
+	_ = h.ExportDetectionClip(ctx)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestExportSearchClips(t *testing.T) {
+	t.Parallel()
+	e, mockDS, h := setupClipExportEnvironment(t)
+	first := clipExportNote(t, h, 1, "2026/05/a/clip.wav")
+	second := clipExportNote(t, h, 2, "2026/05/b/clip.wav")
+	mockDS.On("SearchDetections", mock.MatchedBy(func(f *datastore.SearchFilters) bool {
+		return f.Page == 1 && f.PerPage == maxClipExportDetections
+	})).Return([]datastore.DetectionRecord{{ID: "1"}, {ID: "2"}, {ID: "3"}}, 3, nil)
+	mockDS.On("Get", "1").Return(first, nil)
+	mockDS.On("Get", "2").Return(second, nil)
+	mockDS.On("Get", "3").Return(datastore.Note{ID: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/search/clip-export", strings.NewReader(`{"species":"Turdus merula","page":4}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, h.ExportSearchClips(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	files := readZip(t, rec.Body.Bytes())
+	assert.Len(t, files, 6, "detections without a clip are skipped")
+	assert.Contains(t, files, "clip.wav")
+	assert.Contains(t, files, "2_clip.wav", "clashing clip names are prefixed with the detection ID")
+	assert.Contains(t, files["2_clip.Table.1.selections.txt"], "\t2_clip.wav\t")
+}
+
+func TestExportSearchClips_TooManyResults(t *testing.T) {
+	t.Parallel()
+	e, mockDS, h := setupClipExportEnvironment(t)
+	mockDS.On("SearchDetections", mock.Anything).
+		Return([]datastore.DetectionRecord{}, maxClipExportDetections+1, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/search/clip-export", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	_ = h.ExportSearchClips(e.NewContext(req, rec))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
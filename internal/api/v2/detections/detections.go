@@ -2,6 +2,7 @@
 package detections
 
 import (
+	"context"
 	"fmt"
 	"maps"
 	"net/http"
@@ -15,10 +16,13 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/patrickmn/go-cache"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
 	"github.com/tphakala/birdnet-go/internal/api/v2/weather"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
+	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
 	detectionPkg "github.com/tphakala/birdnet-go/internal/detection"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
@@ -156,9 +160,12 @@ type DetectionResponse struct {
 	ClipName           string            `json:"clipName,omitempty"`  // Audio clip filename (basename only, no path); empty when no clip exists
 	ModelType          string            `json:"modelType,omitempty"` // AI model type (e.g. "bird", "bat"); drives the spectrogram frequency range
 	Verified           string            `json:"verified"`
+	ReviewedBy         string            `json:"reviewedBy,omitempty"` // Username of the last reviewer; authenticated clients only
 	Locked             bool              `json:"locked"`
+	LockedBy           string            `json:"lockedBy,omitempty"` // Username that locked the detection; authenticated clients only
 	Unlikely           bool              `json:"unlikely,omitempty"`
 	Comments           []CommentResponse `json:"comments,omitempty"`
+	Redactions         []RedactedSegment `json:"redactions,omitempty"` // Clip stretches blanked by the privacy filter; single detection only
 	Weather            *WeatherInfo      `json:"weather,omitempty"`
 	TimeOfDay          string            `json:"timeOfDay,omitempty"`
 	IsNewSpecies       bool              `json:"isNewSpecies,omitempty"`       // First seen within tracking window
@@ -172,6 +179,13 @@ type DetectionResponse struct {
 	CurrentSeason   string `json:"currentSeason,omitempty"`   // Current season name
 }
 
+// RedactedSegment is a stretch of the detection's clip, in seconds from the
+// clip start, that the privacy filter blanked because it held human voice.
+type RedactedSegment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
 // SourceInfo describes the audio source of a detection.
 type SourceInfo struct {
 	ID          string `json:"id"`
@@ -1210,12 +1224,53 @@ func (c *Handler) GetDetection(ctx echo.Context) error {
 	// For single detection, include weather data by default
 	weatherCache := make(map[string][]datastore.HourlyWeather)
 	detection := c.noteToDetectionResponse(&note, true, weatherCache)
-	if !c.isClientAuthenticated(ctx) {
+	if c.isClientAuthenticated(ctx) {
+		c.addAttribution(id, &detection)
+	} else {
 		detection.Source = nil
 	}
+	c.addRedactions(ctx.Request().Context(), note.ID, &detection)
 	return ctx.JSON(http.StatusOK, detection)
 }
 
+// initRedactions wires the clip redaction records. They only exist in the
+// enhanced (v2) database.
+func (c *Handler) initRedactions() {
+	if c.redactions == nil && c.V2Manager != nil && datastoreV2.IsEnhancedDatabase() {
+		c.redactions = repository.NewClipRedactionRepository(c.V2Manager.DB(), nil)
+	}
+}
+
+// addRedactions fills in the clip stretches the privacy filter blanked.
+// Lookup failures only leave the field empty.
+func (c *Handler) addRedactions(ctx context.Context, id uint, detection *DetectionResponse) {
+	if c.redactions == nil {
+		return
+	}
+	redaction, err := c.redactions.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	for _, s := range redaction.Segments {
+		detection.Redactions = append(detection.Redactions, RedactedSegment{Start: s.Start, End: s.End})
+	}
+}
+
+// addAttribution fills in who reviewed and locked a detection. Lookup
+// failures only leave the fields empty.
+func (c *Handler) addAttribution(id string, detection *DetectionResponse) {
+	if detection.Verified == VerificationStatusCorrect || detection.Verified == VerificationStatusFalsePositive {
+		if review, err := c.DS.GetNoteReview(id); err == nil && review != nil {
+			detection.ReviewedBy = review.ReviewedBy
+		}
+	}
+	if detection.Locked {
+		if lock, err := c.DS.GetNoteLock(id); err == nil && lock != nil {
+			detection.LockedBy = lock.LockedBy
+		}
+	}
+}
+
 // GetRecentDetections returns the most recent detections
 // Query parameters:
 // - limit: number of detections to return (default: 10)
@@ -1387,39 +1442,6 @@ func (c *Handler) invalidateDetectionCache() {
 	c.DetectionCache.Flush()
 }
 
-// checkAndHandleLock verifies if a detection is locked and manages lock state
-// Returns the note and error if any
-func (c *Handler) checkAndHandleLock(idStr string, shouldLock bool) (*datastore.Note, error) {
-	// Get the note
-	note, err := c.DS.Get(idStr)
-	if err != nil {
-		return nil, fmt.Errorf("detection not found: %w", err)
-	}
-
-	// Check if the note is already locked in memory
-	if note.Locked {
-		return nil, fmt.Errorf("detection is locked")
-	}
-
-	// Check if the note is locked in the database
-	isLocked, err := c.DS.IsNoteLocked(idStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check lock status: %w", err)
-	}
-	if isLocked {
-		return nil, fmt.Errorf("detection is locked")
-	}
-
-	// If we should lock the note, try to acquire lock
-	if shouldLock {
-		if err := c.DS.LockNote(idStr); err != nil {
-			return nil, fmt.Errorf("failed to acquire lock: %w", err)
-		}
-	}
-
-	return &note, nil
-}
-
 // ReviewDetection updates a detection with verification status and optional comment
 func (c *Handler) ReviewDetection(ctx echo.Context) error {
 	idStr := ctx.Param("id")
@@ -1464,7 +1486,7 @@ func (c *Handler) ReviewDetection(ctx echo.Context) error {
 
 	if verification.IsSet {
 		// Save review using the datastore method for reviews
-		if err := c.AddReview(note.ID, verification.Verified); err != nil {
+		if err := c.AddReview(note.ID, verification.Verified, auth.UsernameFromContext(ctx)); err != nil {
 			return c.HandleError(ctx, err, "Failed to update verification", http.StatusInternalServerError)
 		}
 
@@ -1485,7 +1507,7 @@ func (c *Handler) ReviewDetection(ctx echo.Context) error {
 			logger.String("ip", ctx.RealIP()),
 		)
 
-		err = c.AddLock(note.ID, newLocked)
+		err = c.AddLock(note.ID, newLocked, auth.UsernameFromContext(ctx))
 		if err != nil {
 			// Log the lock operation failure
 			c.LogErrorIfEnabled("Failed to update lock status",
@@ -1532,7 +1554,7 @@ func (c *Handler) LockDetection(ctx echo.Context) error {
 	}
 
 	// Lock/unlock the detection
-	err = c.AddLock(note.ID, req.Locked)
+	err = c.AddLock(note.ID, req.Locked, auth.UsernameFromContext(ctx))
 	if err != nil {
 		return c.HandleError(ctx, err, "Failed to update lock status", http.StatusInternalServerError)
 	}
@@ -1777,8 +1799,8 @@ func (c *Handler) AddComment(noteID uint, commentText string) error {
 	return c.DS.SaveNoteComment(comment)
 }
 
-// AddReview creates or updates a review for a note
-func (c *Handler) AddReview(noteID uint, verified bool) error {
+// AddReview creates or updates a review for a note on behalf of reviewedBy
+func (c *Handler) AddReview(noteID uint, verified bool, reviewedBy string) error {
 	// Convert bool to string value
 	verifiedStr := map[bool]string{
 		true:  VerificationStatusCorrect,
@@ -1786,21 +1808,22 @@ func (c *Handler) AddReview(noteID uint, verified bool) error {
 	}[verified]
 
 	review := &datastore.NoteReview{
-		NoteID:    noteID,
-		Verified:  verifiedStr,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		NoteID:     noteID,
+		Verified:   verifiedStr,
+		ReviewedBy: reviewedBy,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
 	}
 
 	return c.DS.SaveNoteReview(review)
 }
 
-// AddLock creates or removes a lock for a note
-func (c *Handler) AddLock(noteID uint, locked bool) error {
+// AddLock creates or removes a lock for a note on behalf of lockedBy
+func (c *Handler) AddLock(noteID uint, locked bool, lockedBy string) error {
 	noteIDStr := strconv.FormatUint(uint64(noteID), 10)
 
 	if locked {
-		return c.DS.LockNote(noteIDStr)
+		return c.DS.LockNote(noteIDStr, lockedBy)
 	} else {
 		return c.DS.UnlockNote(noteIDStr)
 	}
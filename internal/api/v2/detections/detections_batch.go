@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
 
@@ -142,6 +143,7 @@ func (c *Handler) BatchReviewDetections(ctx echo.Context) error {
 	}
 
 	ids := deduplicateIDs(req.IDs)
+	reviewedBy := auth.UsernameFromContext(ctx)
 	var processed, skipped int
 	for _, idStr := range ids {
 		note, err := c.DS.Get(idStr)
@@ -158,7 +160,7 @@ func (c *Handler) BatchReviewDetections(ctx echo.Context) error {
 			continue
 		}
 
-		if err := c.AddReview(note.ID, verification.Verified); err != nil {
+		if err := c.AddReview(note.ID, verification.Verified, reviewedBy); err != nil {
 			c.LogWarnIfEnabled("Batch review: failed to set verification",
 				logger.String("id", idStr),
 				logger.Error(err))
@@ -193,6 +195,7 @@ func (c *Handler) BatchLockDetections(ctx echo.Context) error {
 	}
 
 	ids := deduplicateIDs(req.IDs)
+	lockedBy := auth.UsernameFromContext(ctx)
 	var processed, skipped int
 	for _, idStr := range ids {
 		note, err := c.DS.Get(idStr)
@@ -209,7 +212,7 @@ func (c *Handler) BatchLockDetections(ctx echo.Context) error {
 			continue
 		}
 
-		if err := c.AddLock(note.ID, req.Locked); err != nil {
+		if err := c.AddLock(note.ID, req.Locked, lockedBy); err != nil {
 			c.LogWarnIfEnabled("Batch lock: failed to set lock state",
 				logger.String("id", idStr),
 				logger.Error(err))
@@ -214,7 +214,7 @@ func TestBatchLockDetections(t *testing.T) {
 			body: BatchLockRequest{IDs: []string{"1", "2"}, Locked: true},
 			mockSetup: func(m *mock.Mock) {
 				m.On("Get", "1").Return(datastore.Note{ID: 1, Locked: false}, nil)
-				m.On("LockNote", "1").Return(nil)
+				m.On("LockNote", "1", "").Return(nil)
 				m.On("Get", "2").Return(datastore.Note{ID: 2, Locked: true}, nil)
 			},
 			expectedStatus: http.StatusOK,
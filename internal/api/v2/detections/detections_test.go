@@ -27,6 +27,11 @@ import (
 	"github.com/tphakala/birdnet-go/internal/api/v2/apitest"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
 )
 
 // executeNoteCommentsHandler simulates the handler behavior for getting comments.
@@ -762,6 +767,46 @@ func TestGetDetection_ClipNameSerialized(t *testing.T) {
 	})
 }
 
+// TestGetDetection_Redactions verifies a single detection lists the clip
+// stretches the privacy filter blanked, and omits the field otherwise.
+func TestGetDetection_Redactions(t *testing.T) {
+	e, mockDS, controller := setupTestEnvironment(t)
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&entities.ClipRedaction{}))
+	controller.redactions = repository.NewClipRedactionRepository(db, nil)
+	require.NoError(t, controller.redactions.Save(t.Context(), 7, "silence",
+		[]entities.RedactedSegment{{Start: 2, End: 5}}))
+
+	get := func(id string, noteID uint) map[string]any {
+		mockDS.ExpectedCalls = nil
+		mockDS.On("Get", id).Return(datastore.Note{ID: noteID, Date: "2025-03-07", Time: "08:15:00"}, nil)
+		mockDS.On("GetHourlyWeather", "2025-03-07").Return([]datastore.HourlyWeather{}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/detections/"+id, http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, controller.GetDetection(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &payload))
+		return payload
+	}
+
+	assert.Equal(t, []any{map[string]any{"start": 2.0, "end": 5.0}}, get("7", 7)["redactions"])
+	_, ok := get("8", 8)["redactions"]
+	assert.False(t, ok, "redactions must be omitted when nothing was redacted")
+}
+
 // TestGetRecentDetections_ClipNameSerialized covers the list converter path so
 // the per-detection clipName signal is present in list responses too.
 func TestGetRecentDetections_ClipNameSerialized(t *testing.T) {
@@ -1531,7 +1576,7 @@ func TestLockDetection(t *testing.T) {
 			mockSetup: func(m *mock.Mock) {
 				m.On("Get", "1").Return(datastore.Note{ID: 1, Locked: false}, nil)
 				m.On("IsNoteLocked", "1").Return(false, nil)
-				m.On("LockNote", "1").Return(nil)
+				m.On("LockNote", "1", "").Return(nil)
 			},
 			expectedStatus: http.StatusNoContent,
 		},
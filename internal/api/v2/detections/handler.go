@@ -33,6 +33,8 @@ import (
 
 	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
 	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/security"
 )
 
 // queryValueTrue is the canonical "true" query-parameter value parsed by the
@@ -70,6 +72,15 @@ type Handler struct {
 	isClientAuthenticated     func(ctx echo.Context) bool
 	loadCommonNameMap         func() map[string]string
 	loadCommonToScientificMap func() map[string]string
+
+	// embeddings is the clip embedding index behind the similar recordings
+	// search, built in RegisterDetectionRoutes. Nil without the enhanced (v2)
+	// database.
+	embeddings repository.EmbeddingRepository
+
+	// redactions records the clip stretches blanked by the privacy filter,
+	// built in RegisterDetectionRoutes. Nil without the enhanced (v2) database.
+	redactions repository.ClipRedactionRepository
 }
 
 // New constructs the detections domain handler around the shared core and the
@@ -119,6 +130,9 @@ func (c *Handler) RegisterSearchRoutes(g *echo.Group) {
 	// Search endpoints - publicly accessible
 	g.POST("/search", c.HandleSearch)
 
+	// Clip export bundles audio files, so it requires authentication
+	g.POST("/search/clip-export", c.ExportSearchClips, c.AuthMiddleware)
+
 	c.LogInfoIfEnabled("Search routes initialized successfully")
 }
 
@@ -142,6 +156,7 @@ func (c *Handler) RegisterDetectionRoutes(g *echo.Group) {
 	// Note: Detection data is decoupled from weather data by design.
 	// To get weather information for a specific detection, use the
 	// /api/v2/weather/detection/:id endpoint after fetching the detection.
+	c.initRedactions()
 	g.GET("/detections", c.GetDetections)
 	g.GET("/detections/:id", c.GetDetection)
 	g.GET("/detections/recent", c.GetRecentDetections)
@@ -150,17 +165,28 @@ func (c *Handler) RegisterDetectionRoutes(g *echo.Group) {
 	// Protected detection management endpoints
 	detectionGroup := g.Group("/detections", c.AuthMiddleware)
 	detectionGroup.DELETE("/:id", c.DeleteDetection)
-	detectionGroup.POST("/:id/review", c.ReviewDetection)
-	detectionGroup.POST("/:id/lock", c.LockDetection)
 	detectionGroup.POST("/ignore", c.IgnoreSpecies)
 	detectionGroup.GET("/ignored", c.GetExcludedSpecies)
+	detectionGroup.GET("/:id/clip-export", c.ExportDetectionClip)
+
+	// Review endpoints are open to reviewers as well as admins
+	reviewGroup := g.Group("/detections", c.RequireRole(security.RoleReviewer))
+	reviewGroup.POST("/:id/review", c.ReviewDetection)
+	reviewGroup.POST("/:id/lock", c.LockDetection)
+
+	// Similar recordings search scans every stored clip embedding, so it is
+	// kept off the public routes.
+	c.initEmbeddings()
+	reviewGroup.GET("/:id/similar", c.GetSimilarDetections)
 
 	// Batch operation endpoints
 	batchGroup := detectionGroup.Group("/batch")
 	batchGroup.POST("/delete", c.BatchDeleteDetections)
-	batchGroup.POST("/review", c.BatchReviewDetections)
-	batchGroup.POST("/lock", c.BatchLockDetections)
-	batchGroup.POST("/resolve", c.BatchResolveDetections)
+
+	batchReviewGroup := reviewGroup.Group("/batch")
+	batchReviewGroup.POST("/review", c.BatchReviewDetections)
+	batchReviewGroup.POST("/lock", c.BatchLockDetections)
+	batchReviewGroup.POST("/resolve", c.BatchResolveDetections)
 }
 
 // validateDateOrder validates that start date is not after end date.
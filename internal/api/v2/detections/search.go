@@ -70,30 +70,7 @@ func (c *Handler) HandleSearch(ctx echo.Context) error {
 		return c.HandleError(ctx, err, "Invalid search parameters", http.StatusBadRequest)
 	}
 
-	// Bound and clean the client-resolved scientific name list before it reaches
-	// the datastore (public endpoint; each name is a label lookup).
-	req.SpeciesScientific = sanitizeSpeciesScientific(req.SpeciesScientific)
-
-	originalSpecies := req.Species
-	resolved, hit := c.resolveSpeciesToScientific(req.Species)
-	req.Species = resolved
-	if hit {
-		c.LogDebugIfEnabled("Resolved common-name query to scientific name",
-			logger.String("input", originalSpecies),
-			logger.String("resolved", req.Species),
-			logger.String("path", path),
-			logger.String("ip", ip),
-		)
-	} else if originalSpecies != "" {
-		// The species term did not map to a known scientific name; the query falls
-		// back to substring/LIKE. Log it so "unresolvable name" is distinguishable
-		// from "resolved but no detections" when triaging an empty result.
-		c.LogDebugIfEnabled("Species query did not resolve to a scientific name, using substring search",
-			logger.String("input", originalSpecies),
-			logger.String("path", path),
-			logger.String("ip", ip),
-		)
-	}
+	c.resolveSearchSpecies(&req, path, ip)
 
 	// Log validated request parameters
 	c.logValidatedRequest(path, ip, &req)
@@ -137,6 +114,36 @@ func (c *Handler) HandleSearch(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, resp)
 }
 
+// resolveSearchSpecies bounds the client-resolved scientific name list and
+// resolves a common-name species term to its scientific name before the
+// request reaches the datastore.
+func (c *Handler) resolveSearchSpecies(req *SearchRequest, path, ip string) {
+	// Bound and clean the client-resolved scientific name list before it reaches
+	// the datastore (public endpoint; each name is a label lookup).
+	req.SpeciesScientific = sanitizeSpeciesScientific(req.SpeciesScientific)
+
+	originalSpecies := req.Species
+	resolved, hit := c.resolveSpeciesToScientific(req.Species)
+	req.Species = resolved
+	if hit {
+		c.LogDebugIfEnabled("Resolved common-name query to scientific name",
+			logger.String("input", originalSpecies),
+			logger.String("resolved", req.Species),
+			logger.String("path", path),
+			logger.String("ip", ip),
+		)
+	} else if originalSpecies != "" {
+		// The species term did not map to a known scientific name; the query falls
+		// back to substring/LIKE. Log it so "unresolvable name" is distinguishable
+		// from "resolved but no detections" when triaging an empty result.
+		c.LogDebugIfEnabled("Species query did not resolve to a scientific name, using substring search",
+			logger.String("input", originalSpecies),
+			logger.String("path", path),
+			logger.String("ip", ip),
+		)
+	}
+}
+
 // logValidatedRequest logs the validated parameters for debugging.
 func (c *Handler) logValidatedRequest(path, ip string, req *SearchRequest) {
 	c.Debug("Validated Search request: Species='%s', DateStart='%s', DateEnd='%s', ConfidenceMin=%f, ConfidenceMax=%f, VerifiedStatus='%s', LockedStatus='%s', TimeOfDay='%s', Page=%d, SortBy='%s'",
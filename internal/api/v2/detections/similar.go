@@ -0,0 +1,112 @@
+package detections
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Result bounds for the similar detections endpoint.
+const (
+	defaultSimilarLimit = 20
+	maxSimilarLimit     = 100
+)
+
+// SimilarDetectionResponse is a detection returned by the similar
+// recordings search with its cosine similarity to the queried clip.
+type SimilarDetectionResponse struct {
+	DetectionResponse
+	Similarity float64 `json:"similarity"`
+}
+
+// SimilarDetectionsResponse is the response of GetSimilarDetections.
+type SimilarDetectionsResponse struct {
+	DetectionID uint                       `json:"detectionId"`
+	Model       string                     `json:"model"` // embeddings model the vectors were computed with
+	Results     []SimilarDetectionResponse `json:"results"`
+}
+
+// initEmbeddings wires the clip embedding index. It stays nil without the
+// enhanced (v2) database, which stores the vectors.
+func (c *Handler) initEmbeddings() {
+	if c.embeddings == nil && c.V2Manager != nil && datastoreV2.IsEnhancedDatabase() {
+		c.embeddings = repository.NewEmbeddingRepository(c.V2Manager.DB(), nil)
+	}
+}
+
+// GetSimilarDetections returns the past detections whose clips sound most
+// like the given detection's clip, best match first. Only clips saved while
+// clip embeddings were enabled can be found.
+// Query parameters:
+// - limit: number of detections to return (default: 20, max: 100)
+// - minSimilarity: drop matches below this cosine similarity (-1 to 1, default: no limit)
+func (c *Handler) GetSimilarDetections(ctx echo.Context) error {
+	if c.embeddings == nil {
+		return c.HandleError(ctx, nil, "Similar recordings search requires the enhanced (v2) database", http.StatusConflict)
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		return c.HandleError(ctx, err, "Invalid detection ID", http.StatusBadRequest)
+	}
+
+	limit := defaultSimilarLimit
+	if v := ctx.QueryParam("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxSimilarLimit {
+			return c.HandleError(ctx, err, "limit must be between 1 and 100", http.StatusBadRequest)
+		}
+	}
+	minSimilarity := -1.0
+	if v := ctx.QueryParam("minSimilarity"); v != "" {
+		minSimilarity, err = strconv.ParseFloat(v, 64)
+		if err != nil || minSimilarity < -1 || minSimilarity > 1 {
+			return c.HandleError(ctx, err, "minSimilarity must be between -1 and 1", http.StatusBadRequest)
+		}
+	}
+
+	reqCtx := ctx.Request().Context()
+	vector, model, err := c.embeddings.Get(reqCtx, uint(id))
+	if errors.Is(err, repository.ErrEmbeddingNotFound) {
+		return c.HandleError(ctx, err, "No embedding is stored for this detection; enable clip embeddings to index new clips", http.StatusNotFound)
+	}
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to load detection embedding", http.StatusInternalServerError)
+	}
+
+	matches, err := c.embeddings.Nearest(reqCtx, model, vector, limit, uint(id))
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to search similar detections", http.StatusInternalServerError)
+	}
+
+	weatherCache := make(map[string][]datastore.HourlyWeather)
+	authenticated := c.isClientAuthenticated(ctx)
+	results := make([]SimilarDetectionResponse, 0, len(matches))
+	for _, m := range matches {
+		if m.Similarity < minSimilarity {
+			break // matches are sorted best first
+		}
+		note, err := c.DS.Get(strconv.FormatUint(uint64(m.DetectionID), 10))
+		if err != nil {
+			// Deleted between the scan and the lookup; its vector goes with it.
+			continue
+		}
+		detection := c.noteToDetectionResponse(&note, false, weatherCache)
+		if !authenticated {
+			detection.Source = nil
+		}
+		results = append(results, SimilarDetectionResponse{DetectionResponse: detection, Similarity: m.Similarity})
+	}
+
+	return ctx.JSON(http.StatusOK, SimilarDetectionsResponse{
+		DetectionID: uint(id),
+		Model:       model,
+		Results:     results,
+	})
+}
@@ -0,0 +1,94 @@
+package detections
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+)
+
+// TestGetSimilarDetections verifies the similar recordings search returns the
+// nearest clips best first, honors minSimilarity, and reports detections
+// without a stored embedding and a missing index.
+func TestGetSimilarDetections(t *testing.T) {
+	e, mockDS, h := setupTestEnvironment(t)
+
+	get := func(id, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/detections/"+id+"/similar"+query, http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		_ = h.GetSimilarDetections(c)
+		return rec
+	}
+
+	t.Run("enhanced database required", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, get("1", "").Code)
+	})
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&entities.DetectionEmbedding{}))
+
+	h.embeddings = repository.NewEmbeddingRepository(db, nil)
+	ctx := t.Context()
+	require.NoError(t, h.embeddings.Save(ctx, 1, "birdnet-v24", []float32{1, 0, 0}))
+	require.NoError(t, h.embeddings.Save(ctx, 2, "birdnet-v24", []float32{0.9, 0.1, 0}))
+	require.NoError(t, h.embeddings.Save(ctx, 3, "birdnet-v24", []float32{0, 1, 0}))
+
+	mockDS.On("Get", "2").Return(datastore.Note{ID: 2, CommonName: "Great Tit", ScientificName: "Parus major"}, nil)
+	mockDS.On("Get", "3").Return(datastore.Note{ID: 3, CommonName: "Blue Tit", ScientificName: "Cyanistes caeruleus"}, nil)
+
+	t.Run("nearest clips best first", func(t *testing.T) {
+		rec := get("1", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var resp SimilarDetectionsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, uint(1), resp.DetectionID)
+		assert.Equal(t, "birdnet-v24", resp.Model)
+		require.Len(t, resp.Results, 2)
+		assert.Equal(t, uint(2), resp.Results[0].ID)
+		assert.Equal(t, "Great Tit", resp.Results[0].CommonName)
+		assert.InDelta(t, 0.99, resp.Results[0].Similarity, 0.01)
+		assert.Equal(t, uint(3), resp.Results[1].ID)
+	})
+
+	t.Run("minSimilarity drops weak matches", func(t *testing.T) {
+		rec := get("1", "?minSimilarity=0.5")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp SimilarDetectionsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Results, 1)
+		assert.Equal(t, uint(2), resp.Results[0].ID)
+	})
+
+	t.Run("detection without embedding", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, get("9", "").Code)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("abc", "").Code)
+		assert.Equal(t, http.StatusBadRequest, get("1", "?limit=0").Code)
+		assert.Equal(t, http.StatusBadRequest, get("1", "?limit=500").Code)
+		assert.Equal(t, http.StatusBadRequest, get("1", "?minSimilarity=2").Code)
+	})
+}
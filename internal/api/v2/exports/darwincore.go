@@ -0,0 +1,433 @@
+package exports
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/darwincore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// formatDarwinCore is the job format of Darwin Core Archive exports.
+	formatDarwinCore = "dwca"
+	// exportPageSize is the number of detections read per query.
+	exportPageSize = 1000
+	// maxSpeciesFilter bounds the species list of an export request.
+	maxSpeciesFilter = 500
+)
+
+// DarwinCoreRequest is the body of POST /api/v2/exports/darwin-core. Every
+// field is optional; an empty request exports every detection that has not
+// been marked as a false positive.
+type DarwinCoreRequest struct {
+	StartDate             string   `json:"start_date"` // YYYY-MM-DD, inclusive
+	EndDate               string   `json:"end_date"`   // YYYY-MM-DD, inclusive
+	Species               []string `json:"species"`    // scientific names
+	MinConfidence         *float64 `json:"min_confidence"`
+	VerifiedOnly          bool     `json:"verified_only"`
+	IncludeFalsePositives bool     `json:"include_false_positives"`
+	Site                  string   `json:"site"` // deployment site of the recording sources
+	Title                 string   `json:"title"`
+	License               string   `json:"license"`
+}
+
+// darwinCoreExport is a validated export request.
+type darwinCoreExport struct {
+	filters  repository.SearchFilters
+	idPrefix string
+	station  *location
+	dataset  darwincore.Dataset
+}
+
+// location is a pair of coordinates.
+type location struct {
+	lat, lon float64
+}
+
+// StartDarwinCoreExport handles POST /api/v2/exports/darwin-core. It starts
+// building a Darwin Core Archive of the matching detections and answers 202
+// with the job to poll.
+func (c *Handler) StartDarwinCoreExport(ctx echo.Context) error {
+	var req DarwinCoreRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+
+	export, err := c.prepareDarwinCoreExport(ctx.Request().Context(), &req)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	job, err := c.startJob(formatDarwinCore, ".zip", func(jobCtx context.Context, job *Job) error {
+		return c.writeDarwinCoreArchive(jobCtx, job, export)
+	})
+	if errors.Is(err, errExportInProgress) {
+		return c.HandleError(ctx, err, "An export is already in progress", http.StatusConflict)
+	}
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to start export", http.StatusInternalServerError)
+	}
+
+	c.LogInfoIfEnabled("Darwin Core export started", logger.String("job_id", job.ID))
+	return ctx.JSON(http.StatusAccepted, job.snapshot())
+}
+
+// prepareDarwinCoreExport validates a request and resolves its filters and
+// dataset metadata.
+func (c *Handler) prepareDarwinCoreExport(ctx context.Context, req *DarwinCoreRequest) (*darwinCoreExport, error) {
+	export := &darwinCoreExport{
+		filters: repository.SearchFilters{
+			SortBy:                repository.SortFieldDetectedAt,
+			ExcludeFalsePositives: !req.IncludeFalsePositives,
+		},
+	}
+
+	start, end, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if !start.IsZero() {
+		startUnix := start.Unix()
+		export.filters.StartTime = &startUnix
+	}
+	if !end.IsZero() {
+		endUnix := end.Unix()
+		export.filters.EndTime = &endUnix
+	}
+
+	if req.MinConfidence != nil {
+		if *req.MinConfidence < 0 || *req.MinConfidence > 1 {
+			return nil, fmt.Errorf("min_confidence must be between 0 and 1")
+		}
+		export.filters.MinConfidence = req.MinConfidence
+	}
+	if req.VerifiedOnly {
+		verified := repository.VerificationFilter(entities.VerificationCorrect)
+		export.filters.Verified = &verified
+	}
+
+	if len(req.Species) > 0 {
+		labelIDs, err := c.resolveSpecies(ctx, req.Species)
+		if err != nil {
+			return nil, err
+		}
+		export.filters.LabelIDs = labelIDs
+	}
+
+	if site := strings.TrimSpace(req.Site); site != "" {
+		sourceIDs, err := c.resolveSite(ctx, site)
+		if err != nil {
+			return nil, err
+		}
+		export.filters.AudioSourceIDs = sourceIDs
+	}
+
+	license := req.License
+	if license == "" {
+		license = darwincore.DefaultLicense
+	}
+	if !slices.Contains(darwincore.Licenses, license) {
+		return nil, fmt.Errorf("license must be one of %s", strings.Join(darwincore.Licenses, ", "))
+	}
+
+	settings := c.CurrentSettings()
+	station := stationName(settings)
+	export.idPrefix = "urn:birdnet-go:" + stationKey(station)
+	if settings != nil && (settings.BirdNET.LocationConfigured || settings.BirdNET.Latitude != 0 || settings.BirdNET.Longitude != 0) {
+		export.station = &location{lat: settings.BirdNET.Latitude, lon: settings.BirdNET.Longitude}
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = station + " acoustic detections"
+	}
+	export.dataset = darwincore.Dataset{
+		ID:          export.idPrefix + ":dataset",
+		Title:       title,
+		Description: describeDataset(station, start, end),
+		Creator:     station,
+		License:     license,
+	}
+	if export.station != nil {
+		export.dataset.Latitude = export.station.lat
+		export.dataset.Longitude = export.station.lon
+		export.dataset.HasLocation = true
+	}
+	return export, nil
+}
+
+// parseDateRange parses an inclusive local date range. Either end may be
+// empty, leaving that side open.
+func parseDateRange(startDate, endDate string) (start, end time.Time, err error) {
+	if startDate != "" {
+		start, err = time.ParseInLocation(time.DateOnly, startDate, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("start_date must be YYYY-MM-DD")
+		}
+	}
+	if endDate != "" {
+		day, err := time.ParseInLocation(time.DateOnly, endDate, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("end_date must be YYYY-MM-DD")
+		}
+		end = day.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end_date must not be before start_date")
+	}
+	return start, end, nil
+}
+
+// resolveSpecies maps scientific names to the label IDs of every model.
+func (c *Handler) resolveSpecies(ctx context.Context, species []string) ([]uint, error) {
+	if len(species) > maxSpeciesFilter {
+		return nil, fmt.Errorf("at most %d species may be selected", maxSpeciesFilter)
+	}
+	names := make([]string, 0, len(species))
+	for _, name := range species {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	labels, err := c.labels.GetByScientificNames(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve species: %w", err)
+	}
+
+	var ids []uint
+	var unknown []string
+	for _, name := range names {
+		matches := labels[name]
+		if len(matches) == 0 {
+			unknown = append(unknown, name)
+			continue
+		}
+		for _, label := range matches {
+			ids = append(ids, label.ID)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("no detections of species: %s", strings.Join(unknown, ", "))
+	}
+	return ids, nil
+}
+
+// resolveSite maps a deployment site to the IDs of its audio sources.
+func (c *Handler) resolveSite(ctx context.Context, site string) ([]uint, error) {
+	sources, err := c.sources.GetBySite(ctx, site)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve site: %w", err)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no audio sources at site: %s", site)
+	}
+	ids := make([]uint, len(sources))
+	for i, src := range sources {
+		ids[i] = src.ID
+	}
+	return ids, nil
+}
+
+// writeDarwinCoreArchive pages through the matching detections and writes
+// them to the job's file.
+func (c *Handler) writeDarwinCoreArchive(ctx context.Context, job *Job, export *darwinCoreExport) error {
+	f, err := os.OpenFile(job.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("create export file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	export.dataset.Created = time.Now()
+	w, err := darwincore.NewWriter(f, export.dataset)
+	if err != nil {
+		return err
+	}
+
+	var commonNames map[string]string
+	if c.loadCommonNameMap != nil {
+		commonNames = c.loadCommonNameMap()
+	}
+	modelNames := make(map[uint]string)
+
+	filters := export.filters
+	filters.Limit = exportPageSize
+	scanned := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		filters.Offset = scanned
+		dets, total, err := c.detections.Search(ctx, &filters)
+		if err != nil {
+			return fmt.Errorf("query detections: %w", err)
+		}
+		if scanned == 0 {
+			job.setTotal(total)
+		}
+		if len(dets) == 0 {
+			break
+		}
+
+		ids := make([]uint, len(dets))
+		labelIDs := make([]uint, 0, len(dets))
+		var sourceIDs []uint
+		for i, det := range dets {
+			ids[i] = det.ID
+			labelIDs = append(labelIDs, det.LabelID)
+			if det.SourceID != nil {
+				sourceIDs = append(sourceIDs, *det.SourceID)
+			}
+		}
+		labels, err := c.labels.GetByIDs(ctx, labelIDs)
+		if err != nil {
+			return fmt.Errorf("load labels: %w", err)
+		}
+		var sources map[uint]*entities.AudioSource
+		if len(sourceIDs) > 0 {
+			if sources, err = c.sources.GetByIDs(ctx, sourceIDs); err != nil {
+				return fmt.Errorf("load audio sources: %w", err)
+			}
+		}
+		reviews, err := c.detections.GetReviewsByDetectionIDs(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("load reviews: %w", err)
+		}
+
+		for _, det := range dets {
+			label := labels[det.LabelID]
+			if label == nil {
+				continue
+			}
+			occ := darwincore.Occurrence{
+				ID:                 fmt.Sprintf("%s:detection:%d", export.idPrefix, det.ID),
+				EventDate:          time.Unix(det.DetectedAt, 0).In(time.Local),
+				ScientificName:     label.ScientificName,
+				VernacularName:     commonNames[label.ScientificName],
+				Confidence:         det.Confidence,
+				IdentifiedBy:       c.modelName(ctx, modelNames, det.ModelID),
+				VerificationStatus: verificationStatus(reviews[det.ID]),
+			}
+			var deployment *entities.SourceDeployment
+			if det.SourceID != nil && sources[*det.SourceID] != nil {
+				deployment = &sources[*det.SourceID].Deployment
+				occ.Locality = derefString(deployment.Site)
+				occ.Habitat = derefString(deployment.Habitat)
+			}
+			switch {
+			case det.Latitude != nil && det.Longitude != nil:
+				occ.Latitude, occ.Longitude, occ.HasLocation = *det.Latitude, *det.Longitude, true
+			case deployment != nil && deployment.Latitude != nil && deployment.Longitude != nil:
+				occ.Latitude, occ.Longitude, occ.HasLocation = *deployment.Latitude, *deployment.Longitude, true
+			case export.station != nil:
+				occ.Latitude, occ.Longitude, occ.HasLocation = export.station.lat, export.station.lon, true
+			}
+			if err := w.Write(&occ); err != nil {
+				return err
+			}
+		}
+
+		scanned += len(dets)
+		job.setProgress(w.Count(), scanned)
+		if len(dets) < exportPageSize {
+			break
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close export file: %w", err)
+	}
+	job.setProgress(w.Count(), scanned)
+	return nil
+}
+
+// modelName returns the display name of a model, caching lookups for the
+// duration of an export.
+func (c *Handler) modelName(ctx context.Context, cache map[uint]string, id uint) string {
+	if name, ok := cache[id]; ok {
+		return name
+	}
+	var name string
+	if model, err := c.models.GetByID(ctx, id); err == nil {
+		name = strings.TrimSpace(model.Name + " " + model.Version)
+	}
+	cache[id] = name
+	return name
+}
+
+// derefString returns the value of s, or "" when s is nil.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// verificationStatus maps a review to the identificationVerificationStatus
+// term.
+func verificationStatus(review *entities.DetectionReview) string {
+	if review == nil {
+		return darwincore.StatusUnverified
+	}
+	switch review.Verified {
+	case entities.VerificationCorrect:
+		return darwincore.StatusVerified
+	case entities.VerificationFalsePositive:
+		return darwincore.StatusFalsePositive
+	default:
+		return darwincore.StatusUnverified
+	}
+}
+
+// stationName returns the configured node name, or a generic name.
+func stationName(settings *conf.Settings) string {
+	if settings != nil {
+		if name := strings.TrimSpace(settings.Main.Name); name != "" {
+			return name
+		}
+	}
+	return "BirdNET-Go"
+}
+
+// stationKey turns a station name into an identifier segment that is safe
+// in occurrence IDs.
+func stationKey(name string) string {
+	key := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, name)
+	return strings.Trim(key, "-")
+}
+
+// describeDataset writes the dataset abstract.
+func describeDataset(station string, start, end time.Time) string {
+	desc := "Bird and wildlife sounds identified automatically by BirdNET-Go at the " + station + " recording station"
+	switch {
+	case !start.IsZero() && !end.IsZero():
+		desc += fmt.Sprintf(" from %s to %s", start.Format(time.DateOnly), end.Format(time.DateOnly))
+	case !start.IsZero():
+		desc += " since " + start.Format(time.DateOnly)
+	case !end.IsZero():
+		desc += " until " + end.Format(time.DateOnly)
+	}
+	return desc + ". Confidence scores are recorded in dynamicProperties."
+}
@@ -0,0 +1,177 @@
+// Package exports is the api/v2 bulk export domain. It owns the
+// /api/v2/exports/* endpoints, which build dataset files from the v2
+// detections in the background and serve them for download. The only format
+// today is a Darwin Core Archive for publishing occurrences to GBIF and
+// similar portals.
+//
+// The Handler embeds *apicore.Core by pointer so the shared dependencies and
+// helpers (settings, V2Manager, the role middleware, HandleError, the logging
+// helpers and the Context/Go lifecycle) promote onto it. Exports read the v2
+// detection store, so like the alerts domain every route answers 409 Conflict
+// while the enhanced database is unavailable.
+package exports
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
+	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/security"
+)
+
+// apiV2Prefix is the v2 API path prefix, used to build download URLs.
+const apiV2Prefix = "/api/v2"
+
+// Handler serves the export endpoints. The repositories are nil until
+// RegisterRoutes builds them, which only happens when the enhanced v2
+// database is active.
+type Handler struct {
+	*apicore.Core
+
+	// loadCommonNameMap returns the facade-owned scientific -> common name map
+	// used for vernacular names.
+	loadCommonNameMap func() map[string]string
+
+	detections repository.DetectionRepository
+	labels     repository.LabelRepository
+	models     repository.ModelRepository
+	sources    repository.AudioSourceRepository
+
+	jobs *jobManager
+}
+
+// New builds an exports Handler around the shared core.
+func New(core *apicore.Core, loadCommonNameMap func() map[string]string) *Handler {
+	return &Handler{
+		Core:              core,
+		loadCommonNameMap: loadCommonNameMap,
+		jobs:              newJobManager(os.TempDir()),
+	}
+}
+
+// RegisterRoutes registers the export endpoints. Exports only read
+// detections, so the viewer role (or an API key with detections:read) is
+// enough.
+func (c *Handler) RegisterRoutes(g *echo.Group) {
+	exports := g.Group("/exports", c.RequireRole(security.RoleViewer), c.requireV2Middleware)
+	exports.POST("/darwin-core", c.StartDarwinCoreExport)
+	exports.GET("/jobs", c.ListExportJobs)
+	exports.GET("/jobs/:id", c.GetExportJob)
+	exports.GET("/jobs/:id/download", c.DownloadExport)
+	exports.DELETE("/jobs/:id", c.DeleteExportJob)
+
+	if c.V2Manager != nil && datastoreV2.IsEnhancedDatabase() {
+		db := c.V2Manager.DB()
+		isMySQL := c.V2Manager.IsMySQL()
+		var useV2Prefix bool
+		if tp, ok := c.V2Manager.(interface{ TablePrefix() string }); ok {
+			useV2Prefix = tp.TablePrefix() != ""
+		}
+		c.detections = repository.NewDetectionRepository(db, nil, useV2Prefix, isMySQL)
+		c.labels = repository.NewLabelRepository(db, nil, useV2Prefix, isMySQL)
+		c.models = repository.NewModelRepository(db, nil, useV2Prefix, isMySQL)
+		c.sources = repository.NewAudioSourceRepository(db, nil, useV2Prefix, isMySQL)
+	}
+}
+
+// Shutdown cancels running exports and deletes the export files.
+func (c *Handler) Shutdown() {
+	c.jobs.removeAll()
+}
+
+// requireV2Middleware answers 409 Conflict when the v2 detection store is
+// unavailable.
+func (c *Handler) requireV2Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if c.detections == nil {
+			return c.HandleError(ctx, nil, "Exports require the enhanced (v2) database", http.StatusConflict)
+		}
+		return next(ctx)
+	}
+}
+
+// ListExportJobs handles GET /api/v2/exports/jobs.
+func (c *Handler) ListExportJobs(ctx echo.Context) error {
+	jobs := c.jobs.list()
+	out := make([]*Job, len(jobs))
+	for i, job := range jobs {
+		out[i] = job.snapshot()
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{"jobs": out})
+}
+
+// GetExportJob handles GET /api/v2/exports/jobs/:id.
+func (c *Handler) GetExportJob(ctx echo.Context) error {
+	job, ok := c.jobs.get(ctx.Param("id"))
+	if !ok {
+		return c.HandleError(ctx, nil, "Export job not found or expired", http.StatusNotFound)
+	}
+	return ctx.JSON(http.StatusOK, job.snapshot())
+}
+
+// DownloadExport handles GET /api/v2/exports/jobs/:id/download.
+func (c *Handler) DownloadExport(ctx echo.Context) error {
+	job, ok := c.jobs.get(ctx.Param("id"))
+	if !ok {
+		return c.HandleError(ctx, nil, "Export job not found or expired", http.StatusNotFound)
+	}
+	if job.snapshot().Status != StatusCompleted {
+		return c.HandleError(ctx, nil, "Export not ready for download", http.StatusConflict)
+	}
+	if _, err := os.Stat(job.path); err != nil {
+		return c.HandleError(ctx, err, "Export file not found - job may have expired", http.StatusGone)
+	}
+	return ctx.Attachment(job.path, job.filename)
+}
+
+// DeleteExportJob handles DELETE /api/v2/exports/jobs/:id. A running export
+// is cancelled.
+func (c *Handler) DeleteExportJob(ctx echo.Context) error {
+	id := ctx.Param("id")
+	if !c.jobs.remove(id) {
+		return c.HandleError(ctx, nil, "Export job not found", http.StatusNotFound)
+	}
+	c.LogInfoIfEnabled("Export job deleted", logger.String("job_id", id))
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Export job deleted"})
+}
+
+// startJob registers a job and runs it in a tracked goroutine. It returns
+// errExportInProgress when another export is running.
+func (c *Handler) startJob(format, extension string, run func(ctx context.Context, job *Job) error) (*Job, error) {
+	jobCtx, cancel := context.WithCancel(c.Context())
+	job, err := c.jobs.create(format, extension, cancel)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	c.Go(func() {
+		defer cancel()
+		job.setStatus(StatusInProgress, "")
+		if err := run(jobCtx, job); err != nil {
+			_ = os.Remove(job.path)
+			if jobCtx.Err() != nil {
+				job.setStatus(StatusFailed, "Export cancelled")
+				return
+			}
+			c.LogWarnIfEnabled("Export job failed",
+				logger.String("job_id", job.ID),
+				logger.String("format", format),
+				logger.Error(err))
+			job.setStatus(StatusFailed, "Export failed")
+			return
+		}
+		job.setStatus(StatusCompleted, "")
+		snap := job.snapshot()
+		c.LogInfoIfEnabled("Export job completed",
+			logger.String("job_id", job.ID),
+			logger.String("format", format),
+			logger.Int("records", snap.Records))
+	})
+	return job, nil
+}
@@ -0,0 +1,312 @@
+package exports
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+
+	"github.com/tphakala/birdnet-go/internal/api/v2/apitest"
+	"github.com/tphakala/birdnet-go/internal/darwincore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/security"
+)
+
+// passthroughMiddleware stands in for the auth middleware.
+func passthroughMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
+
+// setupExportsHandler registers the export routes on a fresh Echo. When db is
+// non-nil the repositories are backed by it.
+func setupExportsHandler(t *testing.T, db *gorm.DB) (*echo.Echo, *Handler) {
+	t.Helper()
+	e := echo.New()
+	core := apitest.NewCore(t, apitest.WithEcho(e))
+	core.AuthMiddleware = passthroughMiddleware
+	core.RoleMiddleware = func(security.Role) echo.MiddlewareFunc { return passthroughMiddleware }
+
+	h := New(core, func() map[string]string {
+		return map[string]string{"Turdus merula": "Eurasian Blackbird"}
+	})
+	h.jobs = newJobManager(t.TempDir())
+	if db != nil {
+		h.detections = repository.NewDetectionRepository(db, nil, false, false)
+		h.labels = repository.NewLabelRepository(db, nil, false, false)
+		h.models = repository.NewModelRepository(db, nil, false, false)
+		h.sources = repository.NewAudioSourceRepository(db, nil, false, false)
+	}
+	h.RegisterRoutes(core.Group)
+	t.Cleanup(h.Shutdown)
+	return e, h
+}
+
+// seedDetections creates a SQLite database holding four detections: two
+// blackbirds (one verified, one false positive), a tawny owl and a blackbird
+// outside the test date range.
+func seedDetections(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "exports.db")), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+	require.NoError(t, db.AutoMigrate(&entities.AIModel{}, &entities.LabelType{}, &entities.Label{},
+		&entities.AudioSource{}, &entities.Detection{}, &entities.DetectionReview{}, &entities.DetectionLock{}))
+
+	model := entities.AIModel{Name: "BirdNET", Version: "2.4", ModelType: entities.ModelTypeBird}
+	require.NoError(t, db.Create(&model).Error)
+	labelType := entities.LabelType{Name: "species"}
+	require.NoError(t, db.Create(&labelType).Error)
+	blackbird := entities.Label{ScientificName: "Turdus merula", ModelID: model.ID, LabelTypeID: labelType.ID}
+	owl := entities.Label{ScientificName: "Strix aluco", ModelID: model.ID, LabelTypeID: labelType.ID}
+	require.NoError(t, db.Create(&blackbird).Error)
+	require.NoError(t, db.Create(&owl).Error)
+
+	day := time.Date(2026, 5, 2, 0, 0, 0, 0, time.Local)
+	lat, lon := 51.5, -0.12
+	detections := []entities.Detection{
+		{ModelID: model.ID, LabelID: blackbird.ID, DetectedAt: day.Add(5 * time.Hour).Unix(), Confidence: 0.91, Latitude: &lat, Longitude: &lon},
+		{ModelID: model.ID, LabelID: blackbird.ID, DetectedAt: day.Add(6 * time.Hour).Unix(), Confidence: 0.72},
+		{ModelID: model.ID, LabelID: owl.ID, DetectedAt: day.Add(23 * time.Hour).Unix(), Confidence: 0.8},
+		{ModelID: model.ID, LabelID: blackbird.ID, DetectedAt: day.AddDate(0, 0, 3).Unix(), Confidence: 0.9},
+	}
+	require.NoError(t, db.Create(&detections).Error)
+	require.NoError(t, db.Create(&[]entities.DetectionReview{
+		{DetectionID: detections[0].ID, Verified: entities.VerificationCorrect},
+		{DetectionID: detections[1].ID, Verified: entities.VerificationFalsePositive},
+	}).Error)
+	return db
+}
+
+func doJSON(t *testing.T, e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+// runExport starts a Darwin Core export and waits for it to finish.
+func runExport(t *testing.T, e *echo.Echo, body string) *Job {
+	t.Helper()
+	rec := doJSON(t, e, http.MethodPost, "/api/v2/exports/darwin-core", body)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var job Job
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+
+	require.Eventually(t, func() bool {
+		rec := doJSON(t, e, http.MethodGet, "/api/v2/exports/jobs/"+job.ID, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+		return job.Status == StatusCompleted || job.Status == StatusFailed
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, StatusCompleted, job.Status, job.Error)
+	return &job
+}
+
+// downloadOccurrences downloads a finished export and returns the rows of its
+// occurrence file keyed by column name.
+func downloadOccurrences(t *testing.T, e *echo.Echo, job *Job) []map[string]string {
+	t.Helper()
+	rec := doJSON(t, e, http.MethodGet, job.DownloadURL, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), ".zip")
+
+	data := rec.Body.Bytes()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	var occurrence string
+	var members []string
+	for _, f := range zr.File {
+		members = append(members, f.Name)
+		if f.Name != darwincore.OccurrenceFile {
+			continue
+		}
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		occurrence = string(content)
+	}
+	assert.ElementsMatch(t, []string{darwincore.OccurrenceFile, darwincore.MetaFile, darwincore.EMLFile}, members)
+
+	lines := strings.Split(strings.TrimSuffix(occurrence, "\n"), "\n")
+	header := strings.Split(lines[0], "\t")
+	rows := make([]map[string]string, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		values := strings.Split(line, "\t")
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			row[name] = values[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestExportRoutesReturn409WithoutV2(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, _ := setupExportsHandler(t, nil)
+	rec := doJSON(t, e, http.MethodPost, "/api/v2/exports/darwin-core", `{}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestExportRoutesAuthorizeBeforeV2Check(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e := echo.New()
+	core := apitest.NewCore(t, apitest.WithEcho(e))
+	core.RoleMiddleware = func(security.Role) echo.MiddlewareFunc {
+		return func(echo.HandlerFunc) echo.HandlerFunc {
+			return func(ctx echo.Context) error {
+				return ctx.NoContent(http.StatusUnauthorized)
+			}
+		}
+	}
+	h := New(core, nil)
+	h.jobs = newJobManager(t.TempDir())
+	h.RegisterRoutes(core.Group)
+	t.Cleanup(h.Shutdown)
+
+	rec := doJSON(t, e, http.MethodPost, "/api/v2/exports/darwin-core", `{}`)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestDarwinCoreExport(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, _ := setupExportsHandler(t, seedDetections(t))
+
+	job := runExport(t, e, `{"start_date":"2026-05-02","end_date":"2026-05-02"}`)
+	assert.Equal(t, formatDarwinCore, job.Format)
+	assert.Equal(t, 2, job.Records, "the false positive and the out-of-range detection are skipped")
+	assert.Equal(t, int64(2), job.Total, "the total only counts exported detections")
+	assert.Equal(t, 100, job.Progress)
+
+	rows := downloadOccurrences(t, e, job)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Turdus merula", rows[0]["scientificName"])
+	assert.Equal(t, "Eurasian Blackbird", rows[0]["vernacularName"])
+	assert.Equal(t, darwincore.StatusVerified, rows[0]["identificationVerificationStatus"])
+	assert.Equal(t, "BirdNET 2.4", rows[0]["identifiedBy"])
+	assert.Equal(t, "51.500000", rows[0]["decimalLatitude"], "detection coordinates take precedence")
+	assert.Contains(t, rows[0]["occurrenceID"], ":detection:")
+	assert.Equal(t, "Strix aluco", rows[1]["scientificName"])
+	assert.Equal(t, darwincore.StatusUnverified, rows[1]["identificationVerificationStatus"])
+
+	rec := doJSON(t, e, http.MethodGet, "/api/v2/exports/jobs", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), job.ID)
+
+	rec = doJSON(t, e, http.MethodDelete, "/api/v2/exports/jobs/"+job.ID, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = doJSON(t, e, http.MethodGet, job.DownloadURL, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDarwinCoreExport_Filters(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, _ := setupExportsHandler(t, seedDetections(t))
+
+	rows := downloadOccurrences(t, e, runExport(t, e, `{"species":["Turdus merula"],"include_false_positives":true}`))
+	require.Len(t, rows, 3)
+	statuses := make([]string, 0, len(rows))
+	for _, row := range rows {
+		assert.Equal(t, "Turdus merula", row["scientificName"])
+		statuses = append(statuses, row["identificationVerificationStatus"])
+	}
+	assert.Contains(t, statuses, darwincore.StatusFalsePositive)
+
+	rows = downloadOccurrences(t, e, runExport(t, e, `{"verified_only":true}`))
+	require.Len(t, rows, 1)
+	assert.Equal(t, darwincore.StatusVerified, rows[0]["identificationVerificationStatus"])
+}
+
+func TestDarwinCoreExport_Site(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	db := seedDetections(t)
+	site, habitat := "North Meadow", "wetland edge"
+	lat, lon := 60.5, 25.1
+	source := entities.AudioSource{
+		SourceURI:  "rtsp://meadow/stream",
+		NodeName:   "default",
+		SourceType: entities.SourceTypeRTSP,
+		Deployment: entities.SourceDeployment{Site: &site, Habitat: &habitat, Latitude: &lat, Longitude: &lon},
+	}
+	require.NoError(t, db.Create(&source).Error)
+	var owl entities.Label
+	require.NoError(t, db.Where("scientific_name = ?", "Strix aluco").First(&owl).Error)
+	require.NoError(t, db.Model(&entities.Detection{}).Where("label_id = ?", owl.ID).Update("source_id", source.ID).Error)
+
+	e, _ := setupExportsHandler(t, db)
+	rows := downloadOccurrences(t, e, runExport(t, e, `{"site":"north meadow"}`))
+	require.Len(t, rows, 1, "only detections recorded at the site are exported")
+	assert.Equal(t, "Strix aluco", rows[0]["scientificName"])
+	assert.Equal(t, "North Meadow", rows[0]["locality"])
+	assert.Equal(t, "wetland edge", rows[0]["habitat"])
+	assert.Equal(t, "60.500000", rows[0]["decimalLatitude"], "the deployment location precedes the station location")
+}
+
+func TestDarwinCoreExport_Validation(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, _ := setupExportsHandler(t, seedDetections(t))
+
+	for name, body := range map[string]string{
+		"bad date":        `{"start_date":"02.05.2026"}`,
+		"reversed range":  `{"start_date":"2026-05-03","end_date":"2026-05-01"}`,
+		"confidence":      `{"min_confidence":1.5}`,
+		"unknown species": `{"species":["Corvus corax"]}`,
+		"license":         `{"license":"all rights reserved"}`,
+		"unknown site":    `{"site":"Nowhere"}`,
+	} {
+		rec := doJSON(t, e, http.MethodPost, "/api/v2/exports/darwin-core", body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, name)
+	}
+}
+
+func TestDarwinCoreExport_PagesSharedTimestamps(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	db := seedDetections(t)
+	var owl entities.Label
+	require.NoError(t, db.Where("scientific_name = ?", "Strix aluco").First(&owl).Error)
+
+	// More than a page of detections in the same second, so only the ID
+	// orders them across pages.
+	const n = exportPageSize + 250
+	at := time.Date(2026, 6, 1, 2, 0, 0, 0, time.Local).Unix()
+	dets := make([]entities.Detection, n)
+	for i := range dets {
+		dets[i] = entities.Detection{ModelID: owl.ModelID, LabelID: owl.ID, DetectedAt: at, Confidence: 0.8}
+	}
+	require.NoError(t, db.CreateInBatches(&dets, 200).Error)
+
+	e, _ := setupExportsHandler(t, db)
+	job := runExport(t, e, `{"start_date":"2026-06-01","end_date":"2026-06-01"}`)
+	assert.Equal(t, n, job.Records)
+	assert.Equal(t, int64(n), job.Total)
+
+	rows := downloadOccurrences(t, e, job)
+	require.Len(t, rows, n)
+	seen := make(map[string]bool, n)
+	for _, row := range rows {
+		assert.False(t, seen[row["occurrenceID"]], "duplicate occurrence %s", row["occurrenceID"])
+		seen[row["occurrenceID"]] = true
+	}
+}
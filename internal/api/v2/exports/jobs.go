@@ -0,0 +1,213 @@
+package exports
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Export job status values.
+const (
+	StatusPending    = "pending"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+	StatusFailed     = "failed"
+)
+
+const (
+	// jobMaxAge is how long a job and its file are kept after it started.
+	jobMaxAge = 1 * time.Hour
+	// tempFilePrefix prefixes the export files written to the temp directory.
+	tempFilePrefix = "birdnet-export-"
+)
+
+// Job is an asynchronous export. Its file can be downloaded until the job
+// expires or is deleted.
+type Job struct {
+	ID          string     `json:"job_id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	Records     int        `json:"records"`
+	Total       int64      `json:"total"`
+	Progress    int        `json:"progress"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+
+	path     string
+	filename string
+	cancel   context.CancelFunc
+	mu       sync.RWMutex
+}
+
+// snapshot returns a copy of the job's public fields that is safe to encode
+// while the export is running.
+func (j *Job) snapshot() *Job {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return &Job{
+		ID:          j.ID,
+		Format:      j.Format,
+		Status:      j.Status,
+		Records:     j.Records,
+		Total:       j.Total,
+		Progress:    j.Progress,
+		StartedAt:   j.StartedAt,
+		CompletedAt: j.CompletedAt,
+		Error:       j.Error,
+		DownloadURL: j.DownloadURL,
+	}
+}
+
+func (j *Job) active() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Status == StatusPending || j.Status == StatusInProgress
+}
+
+func (j *Job) setTotal(total int64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Total = total
+}
+
+func (j *Job) setProgress(records, scanned int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Records = records
+	if j.Total > 0 {
+		j.Progress = min(int(int64(scanned)*100/j.Total), 100)
+	}
+}
+
+func (j *Job) setStatus(status, errMsg string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Status = status
+	j.Error = errMsg
+	switch status {
+	case StatusCompleted:
+		now := time.Now()
+		j.CompletedAt = &now
+		j.Progress = 100
+		j.DownloadURL = fmt.Sprintf("%s/exports/jobs/%s/download", apiV2Prefix, j.ID)
+	case StatusFailed:
+		now := time.Now()
+		j.CompletedAt = &now
+	}
+}
+
+// jobManager tracks export jobs. Only one export runs at a time; finished
+// jobs are dropped with their files once they expire.
+type jobManager struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+	dir  string
+}
+
+func newJobManager(dir string) *jobManager {
+	return &jobManager{jobs: make(map[string]*Job), dir: dir}
+}
+
+// errExportInProgress is returned by create while another export runs.
+var errExportInProgress = errors.NewStd("an export is already in progress")
+
+// create registers a new pending job whose file will be written to the
+// manager's directory.
+func (m *jobManager) create(format, extension string, cancel context.CancelFunc) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked(time.Now())
+
+	for _, job := range m.jobs {
+		if job.active() {
+			return nil, errExportInProgress
+		}
+	}
+
+	random := make([]byte, 4)
+	_, _ = rand.Read(random)
+	started := time.Now()
+	id := fmt.Sprintf("export-%s-%d-%s", format, started.UnixNano(), hex.EncodeToString(random))
+	stamp := started.Format("20060102-150405")
+	job := &Job{
+		ID:        id,
+		Format:    format,
+		Status:    StatusPending,
+		StartedAt: started,
+		path:      filepath.Join(m.dir, tempFilePrefix+id+extension),
+		filename:  fmt.Sprintf("birdnet-%s-%s%s", format, stamp, extension),
+		cancel:    cancel,
+	}
+	m.jobs[id] = job
+	return job, nil
+}
+
+func (m *jobManager) get(id string) (*Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked(time.Now())
+	job, ok := m.jobs[id]
+	return job, ok
+}
+
+// list returns all jobs, newest first.
+func (m *jobManager) list() []*Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked(time.Now())
+	jobs := make([]*Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, job)
+	}
+	slices.SortFunc(jobs, func(a, b *Job) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+	return jobs
+}
+
+// remove cancels a job and deletes its file.
+func (m *jobManager) remove(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return false
+	}
+	m.dropLocked(job)
+	return true
+}
+
+// removeAll cancels every job and deletes the files.
+func (m *jobManager) removeAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.jobs {
+		m.dropLocked(job)
+	}
+}
+
+func (m *jobManager) pruneLocked(now time.Time) {
+	for _, job := range m.jobs {
+		if now.Sub(job.StartedAt) > jobMaxAge {
+			m.dropLocked(job)
+		}
+	}
+}
+
+func (m *jobManager) dropLocked(job *Job) {
+	if job.cancel != nil {
+		job.cancel()
+	}
+	_ = os.Remove(job.path)
+	delete(m.jobs, job.ID)
+}
@@ -100,11 +100,15 @@ var hotReloadRegistry = map[string]hotReloadEntry{
 	"Realtime.Audio.Sources.*.Name":       {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_audio_sources"},
 	"Realtime.Audio.Sources.*.Device":     {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_audio_sources"},
 	"Realtime.Audio.Sources.*.SampleRate": {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_audio_sources"},
+	"Realtime.Audio.Sources.*.Channels":   {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_audio_sources"},
 	"Realtime.Audio.Sources.*.Gain":       {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_audio_sources"},
 	"Realtime.Audio.Sources.*.Model":      {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_audio_sources"},
 	"Realtime.Audio.Sources.*.Models":     {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_audio_sources"},
 	"Realtime.Audio.Sources.*.Equalizer":  {categories: []hotReloadCategory{hotReloadFresh}},
 	"Realtime.Audio.Sources.*.QuietHours": {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_quiet_hours"},
+	"Realtime.Audio.Sources.*.Deployment": {categories: []hotReloadCategory{hotReloadFresh}, action: "rebuild_range_filter"},
+	"Realtime.Audio.Sources.*.Soundscape": {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_audio_sources"},
+	"Realtime.Audio.Sources.*.Species":    {categories: []hotReloadCategory{hotReloadFresh}},
 	"Realtime.Audio.Source":               {categories: []hotReloadCategory{hotReloadRuntime}},
 	"Realtime.Audio.FfmpegPath":           {categories: []hotReloadCategory{hotReloadRuntime}},
 	"Realtime.Audio.FfmpegVersion":        {categories: []hotReloadCategory{hotReloadRuntime}},
@@ -116,6 +120,7 @@ var hotReloadRegistry = map[string]hotReloadEntry{
 	"Realtime.Audio.StreamTransport":      {categories: []hotReloadCategory{hotReloadFresh}},
 	"Realtime.Audio.Export":               {categories: []hotReloadCategory{hotReloadFresh}},
 	"Realtime.Audio.SoundLevel":           {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_sound_level"},
+	"Realtime.Audio.Soundscape":           {categories: []hotReloadCategory{hotReloadFresh}},
 	"Realtime.Audio.Equalizer":            {categories: []hotReloadCategory{hotReloadFresh}},
 	"Realtime.Audio.QuietHours":           {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_quiet_hours"},
 	"Realtime.Audio.Watchdog":             {categories: []hotReloadCategory{hotReloadRestart}},
@@ -171,6 +176,9 @@ var hotReloadRegistry = map[string]hotReloadEntry{
 	"Realtime.RTSP.Streams.*.Equalizer":   {categories: []hotReloadCategory{hotReloadFresh}},
 	"Realtime.RTSP.Streams.*.QuietHours":  {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_quiet_hours"},
 	"Realtime.RTSP.Streams.*.Models":      {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_rtsp_sources"},
+	"Realtime.RTSP.Streams.*.Deployment":  {categories: []hotReloadCategory{hotReloadFresh}, action: "rebuild_range_filter"},
+	"Realtime.RTSP.Streams.*.Soundscape":  {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_rtsp_sources"},
+	"Realtime.RTSP.Streams.*.Species":     {categories: []hotReloadCategory{hotReloadFresh}},
 	"Realtime.RTSP.URLs":                  {categories: []hotReloadCategory{hotReloadRuntime}},
 	"Realtime.RTSP.Transport":             {categories: []hotReloadCategory{hotReloadRuntime}},
 	"Realtime.RTSP.Health": {
@@ -189,6 +197,7 @@ var hotReloadRegistry = map[string]hotReloadEntry{
 	"Realtime.MQTT.Retain":        {categories: []hotReloadCategory{hotReloadFresh}},
 	"Realtime.MQTT.RetrySettings": {categories: []hotReloadCategory{hotReloadFresh}},
 	"Realtime.MQTT.TLS":           {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_mqtt"},
+	"Realtime.MQTT.Commands":      {categories: []hotReloadCategory{hotReloadFresh}, action: "reconfigure_mqtt"},
 	"Realtime.MQTT.HomeAssistant": {
 		categories: []hotReloadCategory{hotReloadFresh},
 		action:     "reconfigure_mqtt",
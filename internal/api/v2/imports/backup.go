@@ -367,7 +367,7 @@ func (c *Handler) RegisterBackupRoutes(g *echo.Group) {
 		if settings != nil && settings.Output.SQLite.Path != "" {
 			dbDirs = append(dbDirs, filepath.Dir(settings.Output.SQLite.Path))
 		}
-		if c.V2Manager != nil && !c.V2Manager.IsMySQL() {
+		if c.V2Manager != nil && !c.V2Manager.IsMySQL() && !c.V2Manager.IsPostgres() {
 			dbDirs = append(dbDirs, filepath.Dir(c.V2Manager.Path()))
 		}
 		backupJobManager.CleanupOrphanedTempFiles(dbDirs...)
@@ -656,7 +656,7 @@ func (c *Handler) getBackupDBInfo(dbType string) (string, *gorm.DB, error) {
 	if c.V2Manager == nil {
 		return "", nil, fmt.Errorf("v2 database not initialized")
 	}
-	if c.V2Manager.IsMySQL() {
+	if c.V2Manager.IsMySQL() || c.V2Manager.IsPostgres() {
 		return "", nil, fmt.Errorf("backup only available for SQLite databases")
 	}
 	return c.V2Manager.Path(), c.V2Manager.DB(), nil
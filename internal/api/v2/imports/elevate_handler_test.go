@@ -49,7 +49,7 @@ func TestElevateImport_FallbackWhenNotSudoer(t *testing.T) {
 	require.NoError(t, os.Chmod(src, 0o000)) // make unreadable so direct fails
 	t.Cleanup(func() { _ = os.Chmod(src, 0o600) })
 
-	h := New(testCore(t), nil)
+	h := New(testCore(t), nil, nil, nil)
 	h.isContainerEnv = func() bool { return false }
 	h.stagingBase = t.TempDir()
 	h.verifyTrustedBase = func(string) error { return nil }
@@ -79,7 +79,7 @@ func TestElevateImport_FallbackWhenNotSudoer(t *testing.T) {
 }
 
 func TestElevateImport_RejectsContainer(t *testing.T) {
-	h := New(testCore(t), nil)
+	h := New(testCore(t), nil, nil, nil)
 	h.isContainerEnv = func() bool { return true }
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v2/import/elevate", strings.NewReader(`{"source_path":"/x/birds.db","mode":"db-only"}`))
@@ -106,7 +106,7 @@ func TestElevateImport_InsufficientSpace(t *testing.T) {
 	require.NoError(t, os.Chmod(src, 0o000))
 	t.Cleanup(func() { _ = os.Chmod(src, 0o600) })
 
-	h := New(testCore(t), nil)
+	h := New(testCore(t), nil, nil, nil)
 	h.isContainerEnv = func() bool { return false }
 	h.stagingBase = t.TempDir()
 	h.verifyTrustedBase = func(string) error { return nil }
@@ -131,7 +131,7 @@ func TestElevateImport_PasswordRequired_WhenAllowElevationAndNoPassword(t *testi
 	src := filepath.Join(t.TempDir(), "birds.db")
 	writeMinimalBirdNetPiDB(t, src)
 
-	h := New(testCore(t), nil)
+	h := New(testCore(t), nil, nil, nil)
 	h.isContainerEnv = func() bool { return false }
 	h.stagingBase = t.TempDir()
 	h.verifyTrustedBase = func(string) error { return nil }
@@ -175,7 +175,7 @@ func TestElevateImport_FallbackWhenPasswordSuppliedButStillFails(t *testing.T) {
 	src := filepath.Join(t.TempDir(), "birds.db")
 	writeMinimalBirdNetPiDB(t, src)
 
-	h := New(testCore(t), nil)
+	h := New(testCore(t), nil, nil, nil)
 	h.isContainerEnv = func() bool { return false }
 	h.stagingBase = t.TempDir()
 	h.verifyTrustedBase = func(string) error { return nil }
@@ -216,7 +216,7 @@ func TestElevateImport_FallbackWhenAllowElevationDisabled(t *testing.T) {
 	src := filepath.Join(t.TempDir(), "birds.db")
 	writeMinimalBirdNetPiDB(t, src)
 
-	h := New(testCore(t), nil)
+	h := New(testCore(t), nil, nil, nil)
 	h.isContainerEnv = func() bool { return false }
 	h.stagingBase = t.TempDir()
 	h.verifyTrustedBase = func(string) error { return nil }
@@ -248,7 +248,7 @@ func TestElevateImport_FallbackWhenAllowElevationDisabled(t *testing.T) {
 // TestElevateImport_Returns503WhenDatastoreUnavailable verifies the datastore
 // guard fires before any elevation is attempted when DS is nil.
 func TestElevateImport_Returns503WhenDatastoreUnavailable(t *testing.T) {
-	h := New(testCore(t), nil)
+	h := New(testCore(t), nil, nil, nil)
 	h.isContainerEnv = func() bool { return false }
 	h.DS = nil // simulate missing datastore
 
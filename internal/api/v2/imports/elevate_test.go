@@ -31,7 +31,7 @@ func TestNewStagingDst_ReturnsNonExistentChildUnderBase(t *testing.T) {
 	base := filepath.Join(t.TempDir(), "stg")
 	require.NoError(t, os.MkdirAll(base, 0o700))
 
-	h := New(testCore(t), nil)
+	h := New(testCore(t), nil, nil, nil)
 	h.verifyTrustedBase = func(string) error { return nil } // bypass: tests cannot make a root dir
 	dst, err := h.newStagingDst(base)
 	require.NoError(t, err)
@@ -40,18 +40,18 @@ func TestNewStagingDst_ReturnsNonExistentChildUnderBase(t *testing.T) {
 }
 
 func TestNewStagingDst_RefusesUntrustedBase(t *testing.T) {
-	h := New(testCore(t), nil)
+	h := New(testCore(t), nil, nil, nil)
 	h.verifyTrustedBase = func(string) error { return ErrStagingBaseUnavailable }
 	_, err := h.newStagingDst(t.TempDir())
 	require.ErrorIs(t, err, ErrStagingBaseUnavailable)
 }
 
 func TestPreflightDiskSpace_Insufficient(t *testing.T) {
-	h := New(testCore(t), nil)
+	h := New(testCore(t), nil, nil, nil)
 	require.ErrorIs(t, h.preflightDiskSpace(100, nil, 1000), ErrInsufficientSpace)
 }
 
 func TestPreflightDiskSpace_Sufficient(t *testing.T) {
-	h := New(testCore(t), nil)
+	h := New(testCore(t), nil, nil, nil)
 	require.NoError(t, h.preflightDiskSpace(10_000, nil, 1000))
 }
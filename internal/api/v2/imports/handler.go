@@ -1,9 +1,10 @@
 // Package importsapi implements the v2 API import/migration domain: the
-// BirdNET-Pi import endpoints (/api/v2/import/*), the legacy->v2 database
+// BirdNET-Pi and result-file import endpoints (/api/v2/import/*), the legacy->v2 database
 // migration endpoints and the background migration-worker control surface
 // (/api/v2/system/database/migration/*), the migration prerequisite checks, the
-// async SQLite backup-job endpoints (/api/v2/system/database/backup/jobs/*), and
-// the legacy-database cleanup endpoints (/api/v2/system/database/legacy/*).
+// async SQLite backup-job endpoints (/api/v2/system/database/backup/jobs/*), the
+// stored-backup verify and restore endpoints (/api/v2/system/backups/*), and the
+// legacy-database cleanup endpoints (/api/v2/system/database/legacy/*).
 //
 // The package is named importsapi (not the bare imports) to avoid colliding with
 // the internal/imports package it depends on, mirroring the rangeapi/authapi
@@ -103,6 +104,16 @@ type Handler struct {
 	// isolated per-test instance so each test gets its own config and store without
 	// touching the global singleton.
 	notificationService *notification.Service
+
+	// loadCommonNameMap and loadCommonToScientificMap are the facade-owned cached
+	// name-map accessors. Result imports use them to resolve sources that record
+	// only a common name. Either may be nil (tests), leaving such rows unresolved.
+	loadCommonNameMap         func() map[string]string
+	loadCommonToScientificMap func() map[string]string
+
+	// backupRestorer lists and restores stored backups. Defaults to the backup
+	// manager held by the processor when nil; tests inject a fake.
+	backupRestorer backupRestorer
 }
 
 // envInfo is the runtime environment plus the BirdNET-Go process run-as identity.
@@ -132,17 +143,22 @@ func defaultEnvInfo() envInfo {
 	return info
 }
 
-// New constructs the import/migration domain handler around the shared core and
-// the facade-injected notification service. The import lifecycle manager is
+// New constructs the import/migration domain handler around the shared core, the
+// facade-injected notification service and the facade's cached name-map
+// accessors. The import lifecycle manager is
 // created here; the import source-path root/factory default lazily and the legacy
 // cleanup tracker is created in RegisterLegacyCleanupRoutes.
-func New(core *apicore.Core, notificationService *notification.Service) *Handler {
+func New(core *apicore.Core, notificationService *notification.Service,
+	loadCommonNameMap, loadCommonToScientificMap func() map[string]string,
+) *Handler {
 	return &Handler{
-		Core:                core,
-		notificationService: notificationService,
-		importMgr:           newImportManager(),
-		isContainerEnv:      sysinfo.IsContainer,
-		importEnvInfo:       defaultEnvInfo,
+		Core:                      core,
+		notificationService:       notificationService,
+		loadCommonNameMap:         loadCommonNameMap,
+		loadCommonToScientificMap: loadCommonToScientificMap,
+		importMgr:                 newImportManager(),
+		isContainerEnv:            sysinfo.IsContainer,
+		importEnvInfo:             defaultEnvInfo,
 		scanCandidates: func(ctx context.Context, p discovery.LocationProvider) []discovery.SourceCandidate {
 			return discovery.NewScanner(p).Scan(ctx)
 		},
@@ -277,6 +277,8 @@ func (c *Handler) RegisterImportRoutes(g *echo.Group) {
 	importGroup.POST("/validate", c.ValidateImportSource)
 	importGroup.POST("/elevate", c.ElevateImport, elevateLimiter)
 	importGroup.POST("/birdnet-pi", c.StartBirdNETPiImport)
+	importGroup.POST("/results", c.StartResultImport)
+	importGroup.POST("/preview", c.PreviewImport)
 	importGroup.GET("/jobs/:jobId/progress", c.StreamImportProgress)
 	importGroup.POST("/jobs/:jobId/cancel", c.CancelImport)
 	importGroup.GET("/status", c.GetImportStatus)
@@ -367,17 +369,6 @@ func (c *Handler) StartBirdNETPiImport(ctx echo.Context) error {
 // is already in use), it removes the staging directory itself so no elevated
 // copy is left on disk.
 func (c *Handler) launchImport(ctx echo.Context, resolvedPath, mode string, loc *time.Location, stagingCleanupDir string) (string, error) {
-	// Guard: clean up the staging dir if we return before the engine goroutine
-	// takes ownership. transferred flips to true right before c.Go() so that the
-	// deferred cleanup here is skipped once the goroutine is responsible.
-	staged := stagingCleanupDir != ""
-	transferred := false
-	defer func() {
-		if staged && !transferred {
-			c.cleanupStagingDir(stagingCleanupDir)
-		}
-	}()
-
 	// Build source using the injectable factory (defaults to birdnetpi.New).
 	factory := c.importSourceFactory
 	if factory == nil {
@@ -387,9 +378,40 @@ func (c *Handler) launchImport(ctx echo.Context, resolvedPath, mode string, loc
 	}
 	src, err := factory(resolvedPath)
 	if err != nil {
+		if stagingCleanupDir != "" {
+			c.cleanupStagingDir(stagingCleanupDir)
+		}
 		return "", c.HandleError(ctx, err, "failed to open source", http.StatusBadRequest)
 	}
 
+	opts := imports.ImportOptions{
+		SourceNode: imports.DefaultSourceNode,
+		Location:   loc,
+	}
+	if mode == importModeDBaudio {
+		opts.IncludeAudio = true
+		opts.AudioSourceDir = filepath.Dir(resolvedPath)
+	}
+	return c.startImportJob(ctx, src, &opts, stagingCleanupDir)
+}
+
+// startImportJob validates an opened source, reserves the single import slot,
+// and runs the engine in a tracked goroutine, returning the job id on success.
+// It owns src: the source is closed on every return path or by the goroutine.
+// When opts.IncludeAudio is set the clip export path is filled in from the
+// settings. stagingCleanupDir is as for launchImport.
+func (c *Handler) startImportJob(ctx echo.Context, src imports.Source, opts *imports.ImportOptions, stagingCleanupDir string) (string, error) {
+	// Guard: clean up the staging dir if we return before the engine goroutine
+	// takes ownership. transferred flips to true right before c.Go() so that the
+	// deferred cleanup here is skipped once the goroutine is responsible.
+	staged := stagingCleanupDir != ""
+	transferred := false
+	defer func() {
+		if staged && !transferred {
+			c.cleanupStagingDir(stagingCleanupDir)
+		}
+	}()
+
 	// Validate source synchronously for an immediate error response.
 	if err := src.Validate(ctx.Request().Context()); err != nil {
 		_ = src.Close()
@@ -398,12 +420,11 @@ func (c *Handler) launchImport(ctx echo.Context, resolvedPath, mode string, loc
 
 	// For db-audio mode, resolve the export path before reserving the import slot
 	// so an unconfigured path returns 400 without occupying the slot.
-	var clipExportPath string
-	if mode == importModeDBaudio {
+	if opts.IncludeAudio {
 		if settings := c.CurrentSettings(); settings != nil {
-			clipExportPath = settings.Realtime.Audio.Export.Path
+			opts.ClipExportPath = settings.Realtime.Audio.Export.Path
 		}
-		if clipExportPath == "" {
+		if opts.ClipExportPath == "" {
 			_ = src.Close()
 			return "", c.HandleError(ctx, nil, "audio export path is not configured; set the audio export path to import audio", http.StatusBadRequest)
 		}
@@ -424,15 +445,6 @@ func (c *Handler) launchImport(ctx echo.Context, resolvedPath, mode string, loc
 	}
 
 	// Run the engine in a goroutine tracked by the controller WaitGroup.
-	opts := imports.ImportOptions{
-		SourceNode: imports.DefaultSourceNode,
-		Location:   loc,
-	}
-	if mode == importModeDBaudio {
-		opts.IncludeAudio = true
-		opts.AudioSourceDir = filepath.Dir(resolvedPath)
-		opts.ClipExportPath = clipExportPath
-	}
 	eng := imports.NewEngine(c.Repo)
 
 	transferred = true // goroutine now owns the staging dir lifetime
@@ -453,7 +465,7 @@ func (c *Handler) launchImport(ctx echo.Context, resolvedPath, mode string, loc
 		if stagingCleanupDir != "" {
 			defer c.cleanupStagingDir(stagingCleanupDir)
 		}
-		stats, runErr = eng.Run(jobCtx, src, &opts, job)
+		stats, runErr = eng.Run(jobCtx, src, opts, job)
 	})
 
 	return id, nil
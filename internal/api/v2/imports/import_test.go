@@ -1133,6 +1133,8 @@ func TestImportRoutes_Registered(t *testing.T) {
 		"POST " + apiV2Prefix + "/import/validate",
 		"POST " + apiV2Prefix + "/import/elevate",
 		"POST " + apiV2Prefix + "/import/birdnet-pi",
+		"POST " + apiV2Prefix + "/import/results",
+		"POST " + apiV2Prefix + "/import/preview",
 		"GET " + apiV2Prefix + "/import/jobs/:jobId/progress",
 		"POST " + apiV2Prefix + "/import/jobs/:jobId/cancel",
 		"GET " + apiV2Prefix + "/import/status",
@@ -1742,7 +1744,7 @@ func TestStartBirdNETPiImport_ContainerDispatch_AcceptsRelativePath(t *testing.T
 // TestNewHandler_DefaultsImportSeams verifies that New() wires all injectable
 // seams used by the sources/validate/elevate endpoints.
 func TestNewHandler_DefaultsImportSeams(t *testing.T) {
-	h := New(apitest.NewCore(t), nil)
+	h := New(apitest.NewCore(t), nil, nil, nil)
 	require.NotNil(t, h.importEnvInfo)
 	require.NotNil(t, h.scanCandidates)
 	require.NotNil(t, h.newLadder)
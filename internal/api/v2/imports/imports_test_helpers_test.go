@@ -115,6 +115,7 @@ func (f *fakeV2Manager) CheckpointWAL() error { return nil }
 func (f *fakeV2Manager) Delete() error        { return nil }
 func (f *fakeV2Manager) Exists() bool         { return true }
 func (f *fakeV2Manager) IsMySQL() bool        { return false }
+func (f *fakeV2Manager) IsPostgres() bool     { return false }
 func (f *fakeV2Manager) TablePrefix() string  { return f.tablePrefix }
 
 // newFakeV2ManagerWithTable creates a fakeV2Manager backed by an in-memory SQLite
@@ -142,7 +143,7 @@ func newFakeV2ManagerWithTable(t *testing.T, tableName, prefix string, count int
 }
 
 // testCore returns a minimal *apicore.Core wired for unit tests in this package.
-// It wraps apitest.NewCore so any test that needs New(testCore(t), nil) gets a
+// It wraps apitest.NewCore so any test that needs New(testCore(t), nil, nil, nil) gets a
 // core with valid settings, a mock datastore, and an echo group. Defined here
 // (no build tag) so it is visible to all test files including linux-only ones.
 func testCore(t *testing.T) *apicore.Core {
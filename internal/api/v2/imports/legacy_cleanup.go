@@ -15,6 +15,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/notification"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // LegacyStatusResponse represents legacy database status for cleanup UI.
@@ -30,7 +31,7 @@ type LegacyStatusResponse struct {
 	Tables       []LegacyTableInfo `json:"tables"`
 }
 
-// LegacyTableInfo provides details about a legacy table (MySQL).
+// LegacyTableInfo provides details about a legacy table (MySQL and PostgreSQL).
 type LegacyTableInfo struct {
 	Name      string `json:"name"`
 	SizeBytes int64  `json:"size_bytes"`
@@ -67,19 +68,52 @@ var legacyTables = []string{
 	"notification_histories",
 }
 
-// tableExistsMySQL checks if a table exists in the MySQL database.
-func (c *Handler) tableExistsMySQL(db *gorm.DB, tableName string) (bool, error) {
+// tableExistsServer checks if a table exists in the MySQL or PostgreSQL database.
+func (c *Handler) tableExistsServer(db *gorm.DB, tableName string) (bool, error) {
 	var count int64
-	err := db.Raw(
-		"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = ? AND table_name = ?",
-		c.CurrentSettings().Output.MySQL.Database, tableName,
-	).Scan(&count).Error
+	var err error
+	if c.isUsingPostgres() {
+		err = db.Raw(
+			"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?",
+			tableName,
+		).Scan(&count).Error
+	} else {
+		err = db.Raw(
+			"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = ? AND table_name = ?",
+			c.CurrentSettings().Output.MySQL.Database, tableName,
+		).Scan(&count).Error
+	}
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
+// tableSizeServer returns the on-disk size of a table, including its indexes,
+// in the MySQL or PostgreSQL database.
+func (c *Handler) tableSizeServer(db *gorm.DB, tableName string) (int64, error) {
+	if c.isUsingPostgres() {
+		var size int64
+		err := db.Raw("SELECT pg_total_relation_size(to_regclass(?))", tableName).Row().Scan(&size)
+		return size, err
+	}
+	var dataLength, indexLength int64
+	err := db.Raw(`
+		SELECT COALESCE(data_length, 0), COALESCE(index_length, 0)
+		FROM information_schema.tables
+		WHERE table_schema = ? AND table_name = ?`,
+		c.CurrentSettings().Output.MySQL.Database, tableName).Row().Scan(&dataLength, &indexLength)
+	return dataLength + indexLength, err
+}
+
+// serverDatabaseName returns the configured MySQL or PostgreSQL database name.
+func (c *Handler) serverDatabaseName() string {
+	if c.isUsingPostgres() {
+		return c.CurrentSettings().Output.Postgres.Database
+	}
+	return c.CurrentSettings().Output.MySQL.Database
+}
+
 // CleanupStatus tracks the state of legacy database cleanup.
 // Thread-safe for concurrent access.
 type CleanupStatus struct {
@@ -170,9 +204,9 @@ func (c *Handler) GetLegacyStatus(ctx echo.Context) error {
 		Tables: []LegacyTableInfo{},
 	}
 
-	// Check if using MySQL
-	if c.isUsingMySQL() {
-		return c.getLegacyStatusMySQL(ctx, &response)
+	// MySQL and PostgreSQL keep the legacy tables in the shared database
+	if c.isUsingServerDB() {
+		return c.getLegacyStatusServer(ctx, &response)
 	}
 
 	return c.getLegacyStatusSQLite(ctx, &response)
@@ -248,19 +282,19 @@ func (c *Handler) getLegacyStatusSQLite(ctx echo.Context, response *LegacyStatus
 }
 
 // getLegacyStatusMySQL handles legacy status for MySQL deployments.
-func (c *Handler) getLegacyStatusMySQL(ctx echo.Context, response *LegacyStatusResponse) error {
-	response.Location = c.CurrentSettings().Output.MySQL.Database
+func (c *Handler) getLegacyStatusServer(ctx echo.Context, response *LegacyStatusResponse) error {
+	response.Location = c.serverDatabaseName()
 
 	// Check if we're in v2-only mode (required for cleanup)
 	if !isV2OnlyMode {
-		response.Exists = true // Assume exists for MySQL
+		response.Exists = true // Assume exists for MySQL and PostgreSQL
 		response.CanCleanup = false
 		response.Reason = "Application must be restarted after migration before cleanup is available"
 		response.ErrorKey = notification.MsgErrCleanupRestartRequired
 		return ctx.JSON(http.StatusOK, response)
 	}
 
-	// Get GORM DB for MySQL queries
+	// Get GORM DB for the legacy table queries
 	dbProvider, ok := c.Repo.(gormDBProvider)
 	if !ok {
 		response.Exists = false
@@ -284,7 +318,7 @@ func (c *Handler) getLegacyStatusMySQL(ctx echo.Context, response *LegacyStatusR
 		tableInfo.Name = tableName
 
 		// Check if table exists
-		exists, err := c.tableExistsMySQL(db, tableName)
+		exists, err := c.tableExistsServer(db, tableName)
 		if err != nil {
 			// Client disconnected: the request context is cancelled and every
 			// remaining table query would fail the same way, so stop quietly
@@ -303,7 +337,7 @@ func (c *Handler) getLegacyStatusMySQL(ctx echo.Context, response *LegacyStatusR
 		}
 
 		// Get row count
-		err = db.Raw(fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName)).Scan(&tableInfo.RowCount).Error
+		err = db.Raw("SELECT COUNT(*) FROM ?", clause.Table{Name: tableName}).Scan(&tableInfo.RowCount).Error
 		if err != nil {
 			c.LogWarnIfEnabled("Legacy cleanup: failed to query row count",
 				logger.String("table", tableName),
@@ -313,18 +347,13 @@ func (c *Handler) getLegacyStatusMySQL(ctx echo.Context, response *LegacyStatusR
 		totalRows += tableInfo.RowCount
 
 		// Get table size
-		var dataLength, indexLength int64
-		err = db.Raw(`
-			SELECT COALESCE(data_length, 0), COALESCE(index_length, 0)
-			FROM information_schema.tables
-			WHERE table_schema = ? AND table_name = ?`,
-			c.CurrentSettings().Output.MySQL.Database, tableName).Row().Scan(&dataLength, &indexLength)
+		size, err := c.tableSizeServer(db, tableName)
 		if err != nil {
 			c.LogWarnIfEnabled("Legacy cleanup: failed to query table size",
 				logger.String("table", tableName),
 				logger.Error(err))
 		} else {
-			tableInfo.SizeBytes = dataLength + indexLength
+			tableInfo.SizeBytes = size
 			totalSize += tableInfo.SizeBytes
 		}
 
@@ -374,8 +403,8 @@ func (c *Handler) StartLegacyCleanup(ctx echo.Context) error {
 
 	// Get size before deletion for reporting
 	var sizeBytes int64
-	if c.isUsingMySQL() {
-		sizeBytes = c.getMySQLLegacySize(ctx.Request().Context())
+	if c.isUsingServerDB() {
+		sizeBytes = c.getServerLegacySize(ctx.Request().Context())
 	} else {
 		sizeBytes = c.getSQLiteLegacySize()
 	}
@@ -391,8 +420,8 @@ func (c *Handler) StartLegacyCleanup(ctx echo.Context) error {
 		var err error
 		var remaining []string
 
-		if c.isUsingMySQL() {
-			remaining, err = c.cleanupMySQLLegacy(cleanupCtx)
+		if c.isUsingServerDB() {
+			remaining, err = c.cleanupServerLegacy(cleanupCtx)
 		} else {
 			err = c.cleanupSQLiteLegacy(cleanupCtx)
 		}
@@ -462,8 +491,8 @@ func (c *Handler) cleanupSQLiteLegacy(ctx context.Context) error {
 }
 
 // cleanupMySQLLegacy drops legacy tables from MySQL.
-func (c *Handler) cleanupMySQLLegacy(ctx context.Context) ([]string, error) {
-	c.LogInfoIfEnabled("Starting MySQL legacy tables cleanup",
+func (c *Handler) cleanupServerLegacy(ctx context.Context) ([]string, error) {
+	c.LogInfoIfEnabled("Starting legacy tables cleanup",
 		logger.Int("total_tables", len(legacyTables)))
 
 	dbProvider, ok := c.Repo.(gormDBProvider)
@@ -471,7 +500,7 @@ func (c *Handler) cleanupMySQLLegacy(ctx context.Context) ([]string, error) {
 		return legacyTables, fmt.Errorf("cannot access database connection")
 	}
 	// Bind the cleanup context to the GORM session so both the per-table
-	// existence check (tableExistsMySQL's Raw) and the DROP TABLE Exec below
+	// existence check (tableExistsServer's Raw) and the DROP TABLE Exec below
 	// observe cancellation. Without this, an in-flight DROP cannot abort on
 	// shutdown, stalling graceful shutdown and leaking the cleanup goroutine.
 	db := dbProvider.GetDB().WithContext(ctx)
@@ -483,7 +512,7 @@ func (c *Handler) cleanupMySQLLegacy(ctx context.Context) ([]string, error) {
 		// Check for cancellation before each table
 		select {
 		case <-ctx.Done():
-			c.LogWarnIfEnabled("MySQL cleanup cancelled",
+			c.LogWarnIfEnabled("Legacy tables cleanup cancelled",
 				logger.Int("tables_dropped", droppedCount),
 				logger.Int("tables_remaining", len(legacyTables)-i))
 			return legacyTables[i:], ctx.Err()
@@ -491,7 +520,7 @@ func (c *Handler) cleanupMySQLLegacy(ctx context.Context) ([]string, error) {
 		}
 
 		// Check if table exists first
-		exists, err := c.tableExistsMySQL(db, tableName)
+		exists, err := c.tableExistsServer(db, tableName)
 		if err != nil {
 			c.LogWarnIfEnabled("Legacy cleanup: failed to check table existence",
 				logger.String("table", tableName),
@@ -503,7 +532,7 @@ func (c *Handler) cleanupMySQLLegacy(ctx context.Context) ([]string, error) {
 		}
 
 		// Drop the table
-		err = db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName)).Error
+		err = db.Exec("DROP TABLE IF EXISTS ?", clause.Table{Name: tableName}).Error
 		if err != nil {
 			c.LogErrorIfEnabled("Failed to drop legacy table",
 				logger.String("table", tableName),
@@ -518,7 +547,7 @@ func (c *Handler) cleanupMySQLLegacy(ctx context.Context) ([]string, error) {
 			logger.String("table", tableName))
 	}
 
-	c.LogInfoIfEnabled("MySQL legacy cleanup completed",
+	c.LogInfoIfEnabled("Legacy tables cleanup completed",
 		logger.Int("tables_dropped", droppedCount))
 
 	return nil, nil
@@ -542,10 +571,10 @@ func (c *Handler) getSQLiteLegacySize() int64 {
 	return totalSize
 }
 
-// getMySQLLegacySize returns the total size of MySQL legacy tables. ctx (the
-// request context) bounds the information_schema queries so they abort if the
-// caller disconnects instead of running unbounded on the request thread.
-func (c *Handler) getMySQLLegacySize(ctx context.Context) int64 {
+// getServerLegacySize returns the total size of MySQL or PostgreSQL legacy
+// tables. ctx (the request context) bounds the catalog queries so they abort if
+// the caller disconnects instead of running unbounded on the request thread.
+func (c *Handler) getServerLegacySize(ctx context.Context) int64 {
 	dbProvider, ok := c.Repo.(gormDBProvider)
 	if !ok {
 		return 0
@@ -554,7 +583,7 @@ func (c *Handler) getMySQLLegacySize(ctx context.Context) int64 {
 
 	var totalSize int64
 	for _, tableName := range legacyTables {
-		exists, err := c.tableExistsMySQL(db, tableName)
+		exists, err := c.tableExistsServer(db, tableName)
 		if err != nil {
 			// Client disconnected: stop quietly instead of warning per table.
 			if ctx.Err() != nil {
@@ -570,18 +599,13 @@ func (c *Handler) getMySQLLegacySize(ctx context.Context) int64 {
 			continue
 		}
 
-		var dataLength, indexLength int64
-		err = db.Raw(`
-			SELECT COALESCE(data_length, 0), COALESCE(index_length, 0)
-			FROM information_schema.tables
-			WHERE table_schema = ? AND table_name = ?`,
-			c.CurrentSettings().Output.MySQL.Database, tableName).Row().Scan(&dataLength, &indexLength)
+		size, err := c.tableSizeServer(db, tableName)
 		if err != nil {
 			c.LogWarnIfEnabled("Legacy cleanup: failed to query table size",
 				logger.String("table", tableName),
 				logger.Error(err))
 		} else {
-			totalSize += dataLength + indexLength
+			totalSize += size
 		}
 	}
 
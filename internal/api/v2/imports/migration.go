@@ -715,12 +715,18 @@ func (c *Handler) runCriticalPrerequisiteChecks() []PrerequisiteCheck {
 	)
 
 	// Database-specific critical checks
-	if c.isUsingMySQL() {
+	switch {
+	case c.isUsingMySQL():
 		checks = append(checks,
 			c.checkMySQLTableHealth(),
 			c.checkMySQLPermissions(),
 		)
-	} else {
+	case c.isUsingPostgres():
+		checks = append(checks,
+			c.checkPostgresTableHealth(),
+			c.checkPostgresPermissions(),
+		)
+	default:
 		checks = append(checks,
 			c.checkSQLiteIntegrity(),
 			c.checkWritePermission(),
@@ -15,6 +15,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // v2OnlySkipMessage is the standard message for prerequisite checks that are
@@ -146,6 +147,21 @@ func (c *Handler) isUsingMySQL() bool {
 	return settings.Output.MySQL.Enabled
 }
 
+// isUsingPostgres returns true if the application is configured to use PostgreSQL.
+func (c *Handler) isUsingPostgres() bool {
+	settings := c.CurrentSettings()
+	if settings == nil {
+		return false
+	}
+	return settings.Output.Postgres.Enabled
+}
+
+// isUsingServerDB returns true for the server databases (MySQL, PostgreSQL),
+// which keep legacy and v2 tables in one database instead of separate files.
+func (c *Handler) isUsingServerDB() bool {
+	return c.isUsingMySQL() || c.isUsingPostgres()
+}
+
 // checkStateIdle verifies the migration is in IDLE state.
 func (c *Handler) checkStateIdle() PrerequisiteCheck {
 	check := PrerequisiteCheck{
@@ -204,7 +220,7 @@ func (c *Handler) checkDiskSpace() PrerequisiteCheck {
 	// For MySQL (remote database), disk space check is not applicable
 	if diskPath == "" {
 		check.Status = CheckStatusSkipped
-		check.Message = "Disk space check not applicable for MySQL (remote database)"
+		check.Message = "Disk space check not applicable for remote databases"
 		return check
 	}
 
@@ -252,15 +268,15 @@ func (c *Handler) checkDiskSpace() PrerequisiteCheck {
 
 // getDatabaseDirectoryResolved returns the database directory with symlinks resolved.
 // Returns an error if the directory cannot be determined or verified.
-// For MySQL, returns empty string with nil error (disk space check not applicable to remote DB).
+// For MySQL and PostgreSQL, returns empty string with nil error (disk space check not applicable to remote DB).
 func (c *Handler) getDatabaseDirectoryResolved() (string, error) {
 	settings := c.CurrentSettings()
 	if settings == nil {
 		return "", fmt.Errorf("settings not available")
 	}
 
-	if settings.Output.MySQL.Enabled {
-		// For MySQL, disk space check is not applicable (remote database)
+	if settings.Output.MySQL.Enabled || settings.Output.Postgres.Enabled {
+		// For remote databases the disk space check is not applicable
 		return "", nil
 	}
 
@@ -460,12 +476,60 @@ func (c *Handler) checkMySQLTableHealth() PrerequisiteCheck {
 	return check
 }
 
+// checkPostgresTableHealth verifies the legacy tables are readable.
+// PostgreSQL has no CHECK TABLE; reading a row surfaces missing relations,
+// permission problems and corrupted heap pages on the first block.
+func (c *Handler) checkPostgresTableHealth() PrerequisiteCheck {
+	check := PrerequisiteCheck{
+		ID:          "postgres_table_health",
+		Name:        "PostgreSQL Table Health",
+		Description: "Check legacy table health",
+		Severity:    CheckSeverityCritical,
+	}
+
+	db := c.getLegacyGormDB()
+	if db == nil {
+		if getIsV2OnlyMode() {
+			check.Status = CheckStatusSkipped
+			check.Message = v2OnlySkipMessage
+			return check
+		}
+		check.Status = CheckStatusError
+		check.Message = errMsgDBConnectionUnavailable
+		return check
+	}
+
+	for _, table := range []string{"notes", "results"} {
+		var rows []int
+		if err := db.Raw("SELECT 1 FROM ? LIMIT 1", clause.Table{Name: table}).Scan(&rows).Error; err != nil {
+			check.Status = CheckStatusFailed
+			check.Message = fmt.Sprintf("Table %s unhealthy: %v", table, err)
+			return check
+		}
+	}
+
+	check.Status = CheckStatusPassed
+	check.Message = "All tables healthy"
+	return check
+}
+
 // checkMySQLPermissions verifies CREATE, INSERT, UPDATE, DELETE, DROP permissions.
-// Uses a unique table name with timestamp to prevent concurrent request collisions.
 func (c *Handler) checkMySQLPermissions() PrerequisiteCheck {
+	return c.checkServerPermissions("mysql_permissions", "MySQL Permissions")
+}
+
+// checkPostgresPermissions verifies CREATE, INSERT, UPDATE, DELETE, DROP permissions.
+func (c *Handler) checkPostgresPermissions() PrerequisiteCheck {
+	return c.checkServerPermissions("postgres_permissions", "PostgreSQL Permissions")
+}
+
+// checkServerPermissions runs the permission probe shared by MySQL and
+// PostgreSQL; the statements it uses are valid in both dialects.
+// Uses a unique table name with timestamp to prevent concurrent request collisions.
+func (c *Handler) checkServerPermissions(id, name string) PrerequisiteCheck {
 	check := PrerequisiteCheck{
-		ID:          "mysql_permissions",
-		Name:        "MySQL Permissions",
+		ID:          id,
+		Name:        name,
 		Description: "Verify CREATE, INSERT, UPDATE, DELETE, DROP privileges",
 		Severity:    CheckSeverityCritical,
 	}
@@ -0,0 +1,190 @@
+// internal/api/v2/imports/restore.go
+// Listing, verifying and restoring backups made by the scheduled backup system.
+package importsapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/restart"
+)
+
+// reasonBackupRestoreRestart is the restart-reason i18n key recorded via
+// restart.MarkRestartRequired when a restored database is staged to be swapped
+// in on the next start. The frontend RestartBanner resolves it via t().
+const reasonBackupRestoreRestart = "restart.reasons.backupRestore"
+
+// backupRestorer lists and restores stored backups. The backup manager held by
+// the processor implements it.
+type backupRestorer interface {
+	ListBackups(ctx context.Context) ([]backup.BackupInfo, error)
+	Restore(ctx context.Context, opts *backup.RestoreOptions) (*backup.RestoreResult, error)
+}
+
+// StoredBackup is a backup held by one of the configured backup targets.
+type StoredBackup struct {
+	backup.Metadata
+	Target string `json:"target"`
+}
+
+// StoredBackupsResponse lists the backups held by the configured targets.
+type StoredBackupsResponse struct {
+	Backups []StoredBackup `json:"backups"`
+}
+
+// RestoreBackupRequest selects what to restore from a backup.
+type RestoreBackupRequest struct {
+	// RestoreConfig also restores the configuration file stored in the
+	// backup. Secrets are kept from the running configuration.
+	RestoreConfig bool `json:"restore_config"`
+}
+
+// RestoreBackupResponse reports a verified or staged restore.
+type RestoreBackupResponse struct {
+	*backup.RestoreResult
+	RestartRequired bool `json:"restart_required"`
+}
+
+// RegisterRestoreRoutes registers the stored-backup list, verify and restore
+// routes on the supplied v2 group.
+func (c *Handler) RegisterRestoreRoutes(g *echo.Group) {
+	restoreGroup := g.Group("/system/backups", c.AuthMiddleware)
+
+	restoreGroup.GET("", c.ListStoredBackups)
+	restoreGroup.POST("/:id/verify", c.VerifyStoredBackup)
+	restoreGroup.POST("/:id/restore", c.RestoreStoredBackup)
+}
+
+// getBackupRestorer returns the injected restorer when set (tests), otherwise
+// the backup manager held by the processor. It returns nil when the backup
+// system is not initialized.
+func (c *Handler) getBackupRestorer() backupRestorer {
+	if c.backupRestorer != nil {
+		return c.backupRestorer
+	}
+	if c.Processor == nil {
+		return nil
+	}
+	restorer, ok := c.Processor.GetBackupManager().(backupRestorer)
+	if !ok {
+		return nil
+	}
+	return restorer
+}
+
+// ListStoredBackups handles GET /api/v2/system/backups
+func (c *Handler) ListStoredBackups(ctx echo.Context) error {
+	restorer := c.getBackupRestorer()
+	if restorer == nil {
+		return c.HandleError(ctx, nil, "Backup system is not initialized", http.StatusServiceUnavailable)
+	}
+
+	backups, err := restorer.ListBackups(ctx.Request().Context())
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to list backups", http.StatusInternalServerError)
+	}
+
+	resp := StoredBackupsResponse{Backups: make([]StoredBackup, 0, len(backups))}
+	for i := range backups {
+		resp.Backups = append(resp.Backups, StoredBackup{Metadata: backups[i].Metadata, Target: backups[i].Target})
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// VerifyStoredBackup handles POST /api/v2/system/backups/:id/verify
+//
+// The backup is downloaded, decrypted and checked against its checksums and
+// the database integrity check without changing anything.
+func (c *Handler) VerifyStoredBackup(ctx echo.Context) error {
+	restorer := c.getBackupRestorer()
+	if restorer == nil {
+		return c.HandleError(ctx, nil, "Backup system is not initialized", http.StatusServiceUnavailable)
+	}
+
+	result, err := restorer.Restore(ctx.Request().Context(), &backup.RestoreOptions{
+		BackupID: ctx.Param("id"),
+		DryRun:   true,
+	})
+	if err != nil {
+		return c.HandleError(ctx, err, "Backup verification failed", restoreErrorStatus(err))
+	}
+	return ctx.JSON(http.StatusOK, RestoreBackupResponse{RestoreResult: result})
+}
+
+// RestoreStoredBackup handles POST /api/v2/system/backups/:id/restore
+//
+// The running application holds the database open, so the verified database
+// is staged next to it and swapped in on the next start. The configuration,
+// when requested, is written right away and also takes effect on restart.
+func (c *Handler) RestoreStoredBackup(ctx echo.Context) error {
+	var req RestoreBackupRequest
+	if ctx.Request().ContentLength != 0 {
+		if err := ctx.Bind(&req); err != nil {
+			return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+		}
+	}
+
+	restorer := c.getBackupRestorer()
+	if restorer == nil {
+		return c.HandleError(ctx, nil, "Backup system is not initialized", http.StatusServiceUnavailable)
+	}
+
+	settings := c.CurrentSettings()
+	if settings == nil || !settings.Output.SQLite.Enabled || settings.Output.SQLite.Path == "" {
+		return c.HandleError(ctx, nil, "Restore is only supported for the SQLite database", http.StatusBadRequest)
+	}
+
+	opts := &backup.RestoreOptions{
+		BackupID:     ctx.Param("id"),
+		DatabasePath: settings.Output.SQLite.Path,
+		Deferred:     true,
+	}
+	if req.RestoreConfig {
+		configPath, err := conf.FindConfigFile()
+		if err != nil {
+			return c.HandleError(ctx, err, "Failed to locate the configuration file", http.StatusInternalServerError)
+		}
+		opts.ConfigPath = configPath
+	}
+
+	start := time.Now()
+	result, err := restorer.Restore(ctx.Request().Context(), opts)
+	if result != nil && result.PendingDatabase != "" {
+		// The database is staged even if the configuration could not be
+		// written, so a restart is needed either way.
+		restart.MarkRestartRequired(reasonBackupRestoreRestart)
+	}
+	if err != nil {
+		return c.HandleError(ctx, err, "Backup restore failed", restoreErrorStatus(err))
+	}
+
+	c.LogInfoIfEnabled("Backup restore staged",
+		logger.String("backup_id", result.Metadata.ID),
+		logger.String("pending_database", result.PendingDatabase),
+		logger.Bool("config_restored", result.ConfigPath != ""),
+		logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+
+	return ctx.JSON(http.StatusOK, RestoreBackupResponse{RestoreResult: result, RestartRequired: true})
+}
+
+// restoreErrorStatus maps a restore error to an HTTP status code.
+func restoreErrorStatus(err error) int {
+	switch {
+	case backup.IsErrorCode(err, backup.ErrNotFound), errors.IsNotFound(err):
+		return http.StatusNotFound
+	case backup.IsErrorCode(err, backup.ErrValidation),
+		errors.IsCategory(err, errors.CategoryValidation),
+		errors.IsCategory(err, errors.CategoryConfiguration):
+		return http.StatusUnprocessableEntity
+	case errors.IsCategory(err, errors.CategoryConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,183 @@
+// restore_test.go: unit tests for the stored-backup list, verify and restore
+// handlers. The backup manager is replaced by a fake restorer.
+package importsapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/api/v2/apitest"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/restart"
+)
+
+// fakeRestorer records the restore options it receives and returns canned
+// results.
+type fakeRestorer struct {
+	backups []backup.BackupInfo
+	err     error
+	opts    *backup.RestoreOptions
+}
+
+func (f *fakeRestorer) ListBackups(context.Context) ([]backup.BackupInfo, error) {
+	return f.backups, f.err
+}
+
+func (f *fakeRestorer) Restore(_ context.Context, opts *backup.RestoreOptions) (*backup.RestoreResult, error) {
+	f.opts = opts
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := &backup.RestoreResult{
+		Metadata:        backup.Metadata{ID: opts.BackupID},
+		ArchiveVerified: true,
+		DataVerified:    true,
+		Integrity:       "ok",
+		DryRun:          opts.DryRun,
+	}
+	if !opts.DryRun && opts.Deferred {
+		result.PendingDatabase = opts.DatabasePath + backup.PendingRestoreSuffix
+	}
+	return result, nil
+}
+
+// newRestoreHandler builds a handler with the fake restorer and an enabled
+// SQLite database under a temp directory.
+func newRestoreHandler(t *testing.T, restorer *fakeRestorer) (h *Handler, dbPath string) {
+	t.Helper()
+	dbPath = filepath.Join(t.TempDir(), "birdnet.db")
+	core := apitest.NewCore(t, apitest.WithSettingsFunc(func(s *conf.Settings) {
+		s.Output.SQLite.Enabled = true
+		s.Output.SQLite.Path = dbPath
+	}))
+	h = New(core, nil, nil, nil)
+	h.backupRestorer = restorer
+	return h, dbPath
+}
+
+func newBackupRequest(method, target, body, id string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(req, rec)
+	if id != "" {
+		ctx.SetParamNames("id")
+		ctx.SetParamValues(id)
+	}
+	return ctx, rec
+}
+
+func TestListStoredBackups(t *testing.T) {
+	restorer := &fakeRestorer{backups: []backup.BackupInfo{
+		{Metadata: backup.Metadata{ID: "sqlite-20260101-000000", Type: "sqlite"}, Target: "local"},
+	}}
+	h, _ := newRestoreHandler(t, restorer)
+
+	ctx, rec := newBackupRequest(http.MethodGet, "/api/v2/system/backups", "", "")
+	require.NoError(t, h.ListStoredBackups(ctx))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp StoredBackupsResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp.Backups, 1)
+	assert.Equal(t, "sqlite-20260101-000000", resp.Backups[0].ID)
+	assert.Equal(t, "local", resp.Backups[0].Target)
+}
+
+func TestListStoredBackups_NotInitialized(t *testing.T) {
+	h := New(testCore(t), nil, nil, nil)
+
+	ctx, rec := newBackupRequest(http.MethodGet, "/api/v2/system/backups", "", "")
+	require.NoError(t, h.ListStoredBackups(ctx))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestVerifyStoredBackup_IsDryRun(t *testing.T) {
+	restorer := &fakeRestorer{}
+	h, _ := newRestoreHandler(t, restorer)
+
+	ctx, rec := newBackupRequest(http.MethodPost, "/api/v2/system/backups/b1/verify", "", "b1")
+	require.NoError(t, h.VerifyStoredBackup(ctx))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	require.NotNil(t, restorer.opts)
+	assert.Equal(t, "b1", restorer.opts.BackupID)
+	assert.True(t, restorer.opts.DryRun)
+	assert.Empty(t, restorer.opts.DatabasePath)
+}
+
+func TestVerifyStoredBackup_NotFound(t *testing.T) {
+	restorer := &fakeRestorer{err: backup.NewError(backup.ErrNotFound, "backup not found", nil)}
+	h, _ := newRestoreHandler(t, restorer)
+
+	ctx, rec := newBackupRequest(http.MethodPost, "/api/v2/system/backups/missing/verify", "", "missing")
+	require.NoError(t, h.VerifyStoredBackup(ctx))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRestoreStoredBackup_StagesAndRequiresRestart(t *testing.T) {
+	restart.Reset()
+	t.Cleanup(restart.Reset)
+
+	restorer := &fakeRestorer{}
+	h, dbPath := newRestoreHandler(t, restorer)
+
+	ctx, rec := newBackupRequest(http.MethodPost, "/api/v2/system/backups/b1/restore", "", "b1")
+	require.NoError(t, h.RestoreStoredBackup(ctx))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	require.NotNil(t, restorer.opts)
+	assert.True(t, restorer.opts.Deferred, "a running instance must stage the database")
+	assert.False(t, restorer.opts.DryRun)
+	assert.Equal(t, dbPath, restorer.opts.DatabasePath)
+	assert.Empty(t, restorer.opts.ConfigPath, "config is only restored on request")
+
+	var resp RestoreBackupResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.True(t, resp.RestartRequired)
+	assert.Equal(t, dbPath+backup.PendingRestoreSuffix, resp.PendingDatabase)
+	assert.Contains(t, restart.GetRestartReasons(), reasonBackupRestoreRestart)
+}
+
+func TestRestoreStoredBackup_VerificationFailure(t *testing.T) {
+	restart.Reset()
+	t.Cleanup(restart.Reset)
+
+	restorer := &fakeRestorer{err: errors.Newf("checksum mismatch").
+		Component("backup").
+		Category(errors.CategoryValidation).
+		Build()}
+	h, _ := newRestoreHandler(t, restorer)
+
+	ctx, rec := newBackupRequest(http.MethodPost, "/api/v2/system/backups/b1/restore", `{"restore_config":false}`, "b1")
+	require.NoError(t, h.RestoreStoredBackup(ctx))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.False(t, restart.IsRestartRequired(), "nothing was staged")
+}
+
+func TestRestoreStoredBackup_RequiresSQLite(t *testing.T) {
+	restorer := &fakeRestorer{}
+	core := apitest.NewCore(t, apitest.WithSettingsFunc(func(s *conf.Settings) {
+		s.Output.SQLite.Enabled = false
+	}))
+	h := New(core, nil, nil, nil)
+	h.backupRestorer = restorer
+
+	ctx, rec := newBackupRequest(http.MethodPost, "/api/v2/system/backups/b1/restore", "", "b1")
+	require.NoError(t, h.RestoreStoredBackup(ctx))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Nil(t, restorer.opts)
+}
@@ -0,0 +1,338 @@
+// internal/api/v2/imports/results.go
+package importsapi
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/imports"
+	"github.com/tphakala/birdnet-go/internal/imports/analyzer"
+	"github.com/tphakala/birdnet-go/internal/imports/birdnetgo"
+	"github.com/tphakala/birdnet-go/internal/imports/birdnetpi"
+	"github.com/tphakala/birdnet-go/internal/imports/raven"
+	"github.com/tphakala/birdnet-go/internal/imports/speciescsv"
+	"github.com/tphakala/birdnet-go/internal/sysinfo"
+)
+
+// Import format identifiers accepted by /import/results and /import/preview.
+const (
+	importFormatBirdNETPi       = "birdnet-pi"
+	importFormatBirdNETAnalyzer = "birdnet-analyzer"
+	importFormatRaven           = "raven"
+	importFormatBirdNETGo       = "birdnet-go"
+	importFormatHaikubox        = "haikubox"
+	importFormatMerlin          = "merlin"
+)
+
+// Preview limits: the sample size returned and how long a preview may read the
+// source before giving up.
+const (
+	previewSampleSize = 20
+	previewTimeout    = 2 * time.Minute
+)
+
+// resultFormat describes a result file format that can be imported.
+type resultFormat struct {
+	sourceNode string
+	modelName  string
+	open       func(path string, opts imports.FileSourceOptions) (imports.Source, error)
+}
+
+// resultFormats are the file-based import formats. BirdNET-Pi databases are
+// imported through /import/birdnet-pi and only previewed here.
+var resultFormats = map[string]resultFormat{
+	importFormatBirdNETAnalyzer: {analyzer.SourceNode, imports.DefaultModelName, openSource(analyzer.New)},
+	importFormatRaven:           {raven.SourceNode, imports.DefaultModelName, openSource(raven.New)},
+	importFormatBirdNETGo:       {birdnetgo.SourceNode, imports.DefaultModelName, openSource(birdnetgo.New)},
+	importFormatHaikubox:        speciesCSVFormat(speciescsv.Haikubox),
+	importFormatMerlin:          speciesCSVFormat(speciescsv.Merlin),
+}
+
+// openSource adapts an adapter constructor to the imports.Source interface,
+// keeping a failed open a nil interface.
+func openSource[S imports.Source](fn func(string, imports.FileSourceOptions) (S, error)) func(string, imports.FileSourceOptions) (imports.Source, error) {
+	return func(path string, opts imports.FileSourceOptions) (imports.Source, error) {
+		src, err := fn(path, opts)
+		if err != nil {
+			return nil, err
+		}
+		return src, nil
+	}
+}
+
+func speciesCSVFormat(profile speciescsv.Profile) resultFormat {
+	return resultFormat{
+		sourceNode: profile.SourceNode,
+		modelName:  profile.ModelName,
+		open: openSource(func(path string, opts imports.FileSourceOptions) (*imports.TableSource, error) {
+			return speciescsv.New(path, profile, opts)
+		}),
+	}
+}
+
+// resultImportRequest is the JSON body for POST /import/results and POST /import/preview.
+type resultImportRequest struct {
+	Format     string `json:"format"`      // one of the importFormat* values
+	Mode       string `json:"mode"`        // accepted values: "db-only", "db-audio"
+	SourcePath string `json:"source_path"` // result file or directory; resolved like the BirdNET-Pi source path
+	AudioPath  string `json:"audio_path"`  // optional directory holding the recordings; defaults to the source directory
+	Location   string `json:"location"`    // optional IANA timezone name e.g. "Europe/Helsinki"
+}
+
+// importPreviewResponse is the reply body for POST /import/preview.
+type importPreviewResponse struct {
+	Total      int                `json:"total"`
+	New        int                `json:"new"`
+	Duplicates int                `json:"duplicates"`
+	Errors     int                `json:"errors"`
+	First      *time.Time         `json:"first,omitempty"`
+	Last       *time.Time         `json:"last,omitempty"`
+	Sample     []previewDetection `json:"sample"`
+}
+
+// previewDetection is one detection of the preview sample.
+type previewDetection struct {
+	Timestamp      time.Time `json:"timestamp"`
+	ScientificName string    `json:"scientific_name"`
+	CommonName     string    `json:"common_name"`
+	Confidence     float64   `json:"confidence"`
+	HasAudio       bool      `json:"has_audio"`
+}
+
+// resultImport is a result source opened from a request, with the engine
+// options to import it.
+type resultImport struct {
+	src  imports.Source
+	opts imports.ImportOptions
+}
+
+// StartResultImport starts an import of BirdNET-Analyzer, Raven, BirdNET-Go,
+// Haikubox or Merlin results. Progress, cancel and status use the same job
+// endpoints as the BirdNET-Pi import. Returns 202 Accepted with a job_id on success.
+func (c *Handler) StartResultImport(ctx echo.Context) error {
+	if err := c.RequireDatastore(ctx); err != nil {
+		return err
+	}
+	if c.Repo == nil {
+		return c.HandleError(ctx, apicore.ErrDatastoreUnavailable, "datastore is not available", http.StatusServiceUnavailable)
+	}
+
+	var req resultImportRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "invalid request body", http.StatusBadRequest)
+	}
+	if _, ok := resultFormats[req.Format]; !ok {
+		return c.HandleError(ctx, nil, "unsupported import format", http.StatusBadRequest)
+	}
+	ri, ok := c.openResultImport(ctx, &req)
+	if !ok {
+		return nil
+	}
+
+	id, err := c.startImportJob(ctx, ri.src, &ri.opts, "")
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusAccepted, startImportResponse{
+		JobID:  id,
+		Status: importStatusStarted,
+	})
+}
+
+// PreviewImport reads a source without saving anything and reports how many
+// detections an import would add, how many are already present or unreadable,
+// the time span covered and a sample. It accepts every result format and
+// BirdNET-Pi databases.
+func (c *Handler) PreviewImport(ctx echo.Context) error {
+	if err := c.RequireDatastore(ctx); err != nil {
+		return err
+	}
+	if c.Repo == nil {
+		return c.HandleError(ctx, apicore.ErrDatastoreUnavailable, "datastore is not available", http.StatusServiceUnavailable)
+	}
+
+	var req resultImportRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "invalid request body", http.StatusBadRequest)
+	}
+	if _, ok := resultFormats[req.Format]; !ok && req.Format != importFormatBirdNETPi {
+		return c.HandleError(ctx, nil, "unsupported import format", http.StatusBadRequest)
+	}
+	ri, ok := c.openResultImport(ctx, &req)
+	if !ok {
+		return nil
+	}
+	defer func() { _ = ri.src.Close() }()
+
+	reqCtx, cancel := context.WithTimeout(ctx.Request().Context(), previewTimeout)
+	defer cancel()
+	preview, err := imports.NewEngine(c.Repo).Preview(reqCtx, ri.src, &ri.opts, previewSampleSize)
+	switch {
+	case err == nil:
+	case reqCtx.Err() != nil:
+		return c.HandleError(ctx, err, "preview timed out", http.StatusRequestTimeout)
+	case errors.IsCategory(err, errors.CategoryValidation):
+		return c.HandleError(ctx, err, "source validation failed", http.StatusBadRequest)
+	default:
+		return c.HandleError(ctx, err, "preview failed", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, toPreviewResponse(&preview))
+}
+
+// openResultImport validates the request, resolves its paths and opens the
+// source. On failure it writes the error response and returns false.
+func (c *Handler) openResultImport(ctx echo.Context, req *resultImportRequest) (*resultImport, bool) {
+	switch req.Mode {
+	case importModeDBOnly, importModeDBaudio:
+	default:
+		_ = c.HandleError(ctx, nil, "unsupported import mode", http.StatusBadRequest)
+		return nil, false
+	}
+
+	sourcePath, info, err := c.resolveResultPath(req.SourcePath)
+	if err != nil {
+		_ = c.HandleError(ctx, err, "invalid source path", http.StatusBadRequest)
+		return nil, false
+	}
+	if req.Format == importFormatBirdNETPi && !info.Mode().IsRegular() {
+		_ = c.HandleError(ctx, nil, "source file not found or not a regular file", http.StatusBadRequest)
+		return nil, false
+	}
+
+	audioDir := imports.ResultRoot(sourcePath)
+	if req.AudioPath != "" {
+		dir, dirInfo, err := c.resolveResultPath(req.AudioPath)
+		if err != nil || !dirInfo.IsDir() {
+			_ = c.HandleError(ctx, err, "invalid audio path", http.StatusBadRequest)
+			return nil, false
+		}
+		audioDir = dir
+	}
+
+	var loc *time.Location
+	if req.Location != "" {
+		if loc, err = time.LoadLocation(req.Location); err != nil {
+			_ = c.HandleError(ctx, err, "invalid location", http.StatusBadRequest)
+			return nil, false
+		}
+	}
+
+	ri := &resultImport{opts: imports.ImportOptions{
+		SourceNode:           imports.DefaultSourceNode,
+		Location:             loc,
+		LookupScientificName: c.scientificNameLookup(),
+	}}
+	if format, ok := resultFormats[req.Format]; ok {
+		ri.opts.SourceNode = format.sourceNode
+		ri.opts.ModelName = format.modelName
+		ri.src, err = format.open(sourcePath, imports.FileSourceOptions{AudioDir: audioDir, Location: loc})
+	} else {
+		factory := c.importSourceFactory
+		if factory == nil {
+			factory = func(p string) (imports.Source, error) {
+				return birdnetpi.New(p)
+			}
+		}
+		ri.src, err = factory(sourcePath)
+	}
+	if err != nil {
+		_ = c.HandleError(ctx, err, "failed to open source", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if req.Mode == importModeDBaudio {
+		ri.opts.IncludeAudio = true
+		ri.opts.AudioSourceDir = audioDir
+		if settings := c.CurrentSettings(); settings != nil {
+			ri.opts.FFmpegPath = settings.Realtime.Audio.FfmpegPath
+			ri.opts.ClipFormat = settings.Realtime.Audio.Export.Type
+		}
+	}
+	return ri, true
+}
+
+// resolveResultPath resolves a result source or audio directory the way the
+// BirdNET-Pi source path is resolved: under the external mount root in a
+// container, or as an absolute path on a native install. Unlike a birds.db a
+// result source may be a directory.
+func (c *Handler) resolveResultPath(userPath string) (string, os.FileInfo, error) {
+	var (
+		resolved string
+		err      error
+	)
+	if c.isContainerEnv == nil || c.isContainerEnv() {
+		root := c.importSourceRoot
+		if root == "" {
+			root = sysinfo.DefaultExternalMountPath
+		}
+		resolved, err = resolveImportSourcePath(root, userPath)
+	} else {
+		if userPath == "" || !filepath.IsAbs(userPath) {
+			return "", nil, errInvalidSourcePath
+		}
+		resolved, err = filepath.EvalSymlinks(filepath.Clean(userPath))
+	}
+	if err != nil {
+		return "", nil, errInvalidSourcePath
+	}
+	info, err := os.Stat(resolved)
+	if err != nil || (!info.Mode().IsRegular() && !info.IsDir()) {
+		return "", nil, errInvalidSourcePath
+	}
+	return resolved, info, nil
+}
+
+// scientificNameLookup returns the engine's name lookup over the classifier's
+// labels: it resolves a common name in the current locale, or a label that is
+// already a scientific name, and returns "" for unknown or ambiguous names.
+func (c *Handler) scientificNameLookup() func(string) string {
+	var commonToSci, sciToCommon map[string]string
+	if c.loadCommonToScientificMap != nil {
+		commonToSci = c.loadCommonToScientificMap()
+	}
+	if c.loadCommonNameMap != nil {
+		sciToCommon = c.loadCommonNameMap()
+	}
+	scientific := make(map[string]string, len(sciToCommon))
+	for sci := range sciToCommon {
+		scientific[apicore.NormalizeForLookup(sci)] = sci
+	}
+	return func(name string) string {
+		key := apicore.NormalizeForLookup(name)
+		if sci, ok := commonToSci[key]; ok {
+			return sci
+		}
+		return scientific[key]
+	}
+}
+
+// toPreviewResponse converts an engine preview to the API DTO.
+func toPreviewResponse(p *imports.ImportPreview) importPreviewResponse {
+	resp := importPreviewResponse{
+		Total:      p.Total,
+		New:        p.Inserted,
+		Duplicates: p.Skipped,
+		Errors:     p.Errors,
+		Sample:     make([]previewDetection, 0, len(p.Sample)),
+	}
+	if !p.First.IsZero() {
+		resp.First, resp.Last = &p.First, &p.Last
+	}
+	for i := range p.Sample {
+		d := &p.Sample[i]
+		resp.Sample = append(resp.Sample, previewDetection{
+			Timestamp:      d.Timestamp,
+			ScientificName: d.ScientificName,
+			CommonName:     d.CommonName,
+			Confidence:     d.Confidence,
+			HasAudio:       d.HasAudio,
+		})
+	}
+	return resp
+}
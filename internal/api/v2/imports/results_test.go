@@ -0,0 +1,169 @@
+package importsapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+	"github.com/tphakala/birdnet-go/internal/detection"
+)
+
+// analyzerResults is a BirdNET-Analyzer result file for a recording started at
+// 2024-05-01 05:30:00. The second row names its species only by common name.
+const analyzerResults = "Start (s),End (s),Scientific name,Common name,Confidence\n" +
+	"0.0,3.0,Turdus merula,Eurasian Blackbird,0.85\n" +
+	"63.0,66.0,,Great Tit,0.61\n"
+
+// newResultImportHandler returns an import handler over a mock repository that
+// records saved detections, with source paths resolved under a temp root that
+// holds one BirdNET-Analyzer result file.
+func newResultImportHandler(t *testing.T) (c *Handler, root string, saved func() []*detection.Result) {
+	t.Helper()
+	_, c = newImportHandler(t)
+	c.DS = mocks.NewMockInterface(t)
+	mockRepo := mocks.NewMockDetectionRepository(t)
+	mockRepo.EXPECT().Search(mock.Anything, mock.Anything).Return(nil, int64(0), nil).Maybe()
+
+	var mu sync.Mutex
+	var results []*detection.Result
+	mockRepo.EXPECT().Save(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, r *detection.Result, _ []detection.AdditionalResult) error {
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, r)
+			return nil
+		}).Maybe()
+	c.Repo = mockRepo
+	c.loadCommonToScientificMap = func() map[string]string { return map[string]string{"great tit": "Parus major"} }
+
+	root = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "site1"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "site1", "20240501_053000.BirdNET.results.csv"), []byte(analyzerResults), 0o600))
+	c.importSourceRoot = root
+
+	return c, root, func() []*detection.Result {
+		mu.Lock()
+		defer mu.Unlock()
+		return results
+	}
+}
+
+func postJSON(t *testing.T, handler echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	require.NoError(t, handler(e.NewContext(req, rec)))
+	return rec
+}
+
+func TestStartResultImport_AnalyzerDirectory_EndToEnd(t *testing.T) {
+	c, _, saved := newResultImportHandler(t)
+
+	rec := postJSON(t, c.StartResultImport, `{"format":"birdnet-analyzer","mode":"db-only","source_path":"site1","location":"UTC"}`)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+	deadline := time.Now().Add(10 * time.Second)
+	var status importStatusResponse
+	for time.Now().Before(deadline) {
+		statusRec := httptest.NewRecorder()
+		require.NoError(t, c.GetImportStatus(echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), statusRec)))
+		require.NoError(t, json.Unmarshal(statusRec.Body.Bytes(), &status))
+		if !status.Running && status.Status == importStatusDone {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	require.Equal(t, importStatusDone, status.Status)
+	require.NotNil(t, status.Progress)
+	assert.Equal(t, 2, status.Progress.Inserted)
+	assert.Equal(t, 0, status.Progress.Errors)
+
+	results := saved()
+	require.Len(t, results, 2)
+	assert.Equal(t, "birdnet-analyzer", results[0].SourceNode)
+	assert.Equal(t, time.Date(2024, 5, 1, 5, 30, 0, 0, time.UTC), results[0].Timestamp)
+	assert.Equal(t, "Parus major", results[1].Species.ScientificName, "common name resolved through the label map")
+	assert.Equal(t, time.Date(2024, 5, 1, 5, 31, 3, 0, time.UTC), results[1].Timestamp)
+}
+
+func TestStartResultImport_RejectsBadRequests(t *testing.T) {
+	c, _, _ := newResultImportHandler(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown format", `{"format":"ebird","mode":"db-only","source_path":"site1"}`},
+		{"birdnet-pi is imported through its own endpoint", `{"format":"birdnet-pi","mode":"db-only","source_path":"site1"}`},
+		{"unknown mode", `{"format":"raven","mode":"all","source_path":"site1"}`},
+		{"traversal", `{"format":"raven","mode":"db-only","source_path":"../../etc"}`},
+		{"audio path is a file", `{"format":"birdnet-analyzer","mode":"db-audio","source_path":"site1","audio_path":"site1/20240501_053000.BirdNET.results.csv"}`},
+		{"no raven tables in directory", `{"format":"raven","mode":"db-only","source_path":"site1"}`},
+	}
+	for _, tt := range tests {
+		rec := postJSON(t, c.StartResultImport, tt.body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, tt.name)
+	}
+	assert.Nil(t, c.importMgr.active(), "no import job is started")
+}
+
+func TestPreviewImport_Analyzer(t *testing.T) {
+	c, root, saved := newResultImportHandler(t)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "site1", "20240501_053000.wav"), []byte("audio"), 0o600))
+
+	rec := postJSON(t, c.PreviewImport, `{"format":"birdnet-analyzer","mode":"db-audio","source_path":"site1","location":"UTC"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp importPreviewResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Total)
+	assert.Equal(t, 2, resp.New)
+	assert.Equal(t, 0, resp.Duplicates)
+	require.NotNil(t, resp.First)
+	require.NotNil(t, resp.Last)
+	assert.Equal(t, time.Date(2024, 5, 1, 5, 30, 0, 0, time.UTC), resp.First.UTC())
+	assert.Equal(t, time.Date(2024, 5, 1, 5, 31, 3, 0, time.UTC), resp.Last.UTC())
+	require.Len(t, resp.Sample, 2)
+	assert.Equal(t, "Parus major", resp.Sample[1].ScientificName)
+	assert.True(t, resp.Sample[0].HasAudio)
+
+	assert.Empty(t, saved(), "preview must not save detections")
+	assert.Nil(t, c.importMgr.active(), "preview does not take the import slot")
+}
+
+func TestPreviewImport_ValidationFailure_Returns400(t *testing.T) {
+	c, _, _ := newResultImportHandler(t)
+
+	rec := postJSON(t, c.PreviewImport, `{"format":"merlin","mode":"db-only","source_path":"site1/20240501_053000.BirdNET.results.csv"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "an Analyzer file is not a Merlin export")
+}
+
+func TestScientificNameLookup(t *testing.T) {
+	t.Parallel()
+	c := &Handler{
+		loadCommonNameMap:         func() map[string]string { return map[string]string{"Strix aluco": "Tawny Owl"} },
+		loadCommonToScientificMap: func() map[string]string { return map[string]string{"tawny owl": "Strix aluco"} },
+	}
+	lookup := c.scientificNameLookup()
+
+	assert.Equal(t, "Strix aluco", lookup("Tawny Owl"))
+	assert.Equal(t, "Strix aluco", lookup("strix ALUCO"), "scientific name labels are recognized")
+	assert.Empty(t, lookup("Barn Owl"))
+
+	assert.Empty(t, (&Handler{}).scientificNameLookup()("Tawny Owl"), "no name maps resolve nothing")
+}
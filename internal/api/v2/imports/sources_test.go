@@ -30,7 +30,7 @@ func sourcesHandler(t *testing.T) (*echo.Echo, *Handler) {
 	core.Group = e.Group(apiV2Prefix)
 	core.AuthMiddleware = func(next echo.HandlerFunc) echo.HandlerFunc { return next }
 	core.SetTestContext(ctx, cancel)
-	h := New(core, nil)
+	h := New(core, nil, nil, nil)
 	t.Cleanup(func() {
 		cancel()
 		h.Wait()
@@ -0,0 +1,92 @@
+package media
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/tphakala/birdnet-go/internal/cliptier"
+	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// initArchivedClips wires the archived clip index used to recall clips the
+// tier retention policy moved off the local disk. It stays nil without the
+// enhanced (v2) database, where the tier policy cannot run.
+func (c *Handler) initArchivedClips() {
+	if c.archivedClips == nil && c.V2Manager != nil && datastoreV2.IsEnhancedDatabase() {
+		c.archivedClips = repository.NewArchivedClipRepository(c.V2Manager.DB(), nil)
+	}
+}
+
+// clipArchiver returns the archiver for the current tier settings. The
+// archiver is kept between requests so concurrent plays of one clip share a
+// single download, and rebuilt when the settings change.
+func (c *Handler) clipArchiver() (*cliptier.Archiver, error) {
+	settings := c.CurrentSettings()
+	tier := settings.Realtime.Audio.Export.Retention.Tier
+	clipDir := c.SFS.BaseDir()
+
+	c.archiverMu.Lock()
+	defer c.archiverMu.Unlock()
+
+	if c.archiver != nil && c.archiverSettings == tier && c.archiverDir == clipDir {
+		return c.archiver, nil
+	}
+	store, err := cliptier.NewStore(&tier)
+	if err != nil {
+		return nil, err
+	}
+	if c.archiver != nil {
+		_ = c.archiver.Store().Close()
+	}
+	c.archiver = cliptier.NewArchiver(store, c.archivedClips, clipDir)
+	c.archiverSettings = tier
+	c.archiverDir = clipDir
+	return c.archiver, nil
+}
+
+// recallArchivedClip copies an archived clip back into the clip directory
+// when its local file is missing. relClipPath is relative to the SecureFS
+// base directory. It reports whether the clip is now available locally; a
+// clip that was never archived, or any recall failure, returns false so the
+// caller falls back to its usual not-found handling.
+func (c *Handler) recallArchivedClip(ctx context.Context, relClipPath string) bool {
+	if c.archivedClips == nil {
+		return false
+	}
+	clipPath := filepath.ToSlash(relClipPath)
+
+	archiver, err := c.clipArchiver()
+	if err != nil {
+		c.LogWarnIfEnabled("Cannot recall archived clip: clip tier store unavailable",
+			logger.String("clip_path", clipPath),
+			logger.Error(err))
+		return false
+	}
+	if err := archiver.Recall(ctx, clipPath); err != nil {
+		if !errors.Is(err, cliptier.ErrNotArchived) {
+			c.LogErrorIfEnabled("Failed to recall archived clip",
+				logger.String("clip_path", clipPath),
+				logger.String("target", archiver.Store().Name()),
+				logger.Error(err))
+		}
+		return false
+	}
+	return true
+}
+
+// ensureClipLocal recalls an archived clip whose local file is missing, so
+// the caller's existence check and serving find it in the clip directory.
+// It is a no-op without the archived clip index or when the file exists.
+func (c *Handler) ensureClipLocal(ctx context.Context, relClipPath string) {
+	if c.archivedClips == nil {
+		return
+	}
+	if _, err := c.SFS.StatRel(relClipPath); err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	c.recallArchivedClip(ctx, relClipPath)
+}
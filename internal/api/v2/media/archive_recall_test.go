@@ -0,0 +1,80 @@
+package media
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/conf/conftest"
+	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+)
+
+// TestServeAudioByID_RecallsArchivedClip verifies that a clip the tier
+// retention policy moved to its secondary store is copied back into the clip
+// directory and served, while a missing clip that was never archived still
+// takes the not-found path.
+func TestServeAudioByID_RecallsArchivedClip(t *testing.T) {
+	withRestoredGlobalSettings(t)
+
+	const archivedClip = "2024/01/parus_major_80p_20240101T060000Z.wav"
+
+	e, controller, clipDir := setupMediaTestEnvironment(t)
+
+	archiveDir := t.TempDir()
+	archivedFile := filepath.Join(archiveDir, filepath.FromSlash(archivedClip))
+	require.NoError(t, os.MkdirAll(filepath.Dir(archivedFile), 0o750))
+	require.NoError(t, createTestAudioFile(t, archivedFile))
+
+	settings := controller.Settings.Load()
+	settings.Realtime.Audio.Export.Retention.Tier = conf.ClipTierSettings{
+		Target: conf.ClipTierTargetLocal,
+		Local:  conf.ClipTierLocalSettings{Path: archiveDir},
+	}
+	conftest.SetTestSettings(settings)
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&entities.ArchivedClip{}))
+
+	controller.archivedClips = repository.NewArchivedClipRepository(db, nil)
+	require.NoError(t, controller.archivedClips.Save(t.Context(), &entities.ArchivedClip{
+		ClipPath: archivedClip,
+		Target:   conf.ClipTierTargetLocal,
+		Location: archiveDir,
+	}))
+
+	mockDS := mocks.NewMockInterface(t)
+	mockDS.On("GetNoteClipPath", "7").Return(archivedClip, nil)
+	controller.DS = mockDS
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/audio/7", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	require.NoError(t, controller.ServeAudioByID(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.FileExists(t, filepath.Join(clipDir, filepath.FromSlash(archivedClip)),
+		"recalled clip should be restored to the clip directory")
+
+	t.Run("clip that was never archived is not recalled", func(t *testing.T) {
+		assert.False(t, controller.recallArchivedClip(t.Context(), "2024/01/missing.wav"))
+	})
+}
@@ -17,9 +17,13 @@ package media
 
 import (
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
+	"github.com/tphakala/birdnet-go/internal/cliptier"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
 	"github.com/tphakala/birdnet-go/internal/spectrogram"
 	"github.com/tphakala/birdnet-go/internal/sysinfo"
 )
@@ -62,6 +66,16 @@ type Handler struct {
 	// uses audioWaitTimeout. Tests set a short timeout to exercise the
 	// 503-after-timeout path without waiting the full default.
 	audioWaitTimeoutOverride time.Duration
+
+	// archivedClips indexes clips the tier retention policy moved to its
+	// secondary store; nil without the enhanced database. archiver is built
+	// on first recall and rebuilt when the tier settings change, guarded by
+	// archiverMu.
+	archivedClips    repository.ArchivedClipRepository
+	archiverMu       sync.Mutex
+	archiver         *cliptier.Archiver
+	archiverSettings conf.ClipTierSettings
+	archiverDir      string
 }
 
 // New constructs the media domain handler around the shared core. It builds the
@@ -70,6 +84,8 @@ type Handler struct {
 // wait group; the facade's Shutdown (Cancel + Wait) tears it down, so no
 // separate shutdown hook is needed (the audio-domain precedent). The
 // external-media probe seams default to nil (real sysinfo at request time).
+// The archived clip index used to recall tiered clips is wired here when the
+// enhanced database is in use.
 func New(core *apicore.Core) *Handler {
 	h := &Handler{Core: core}
 
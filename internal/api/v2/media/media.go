@@ -220,6 +220,10 @@ type ProcessAudioRequest struct {
 func (c *Handler) RegisterRoutes(g *echo.Group) {
 	c.LogInfoIfEnabled("Initializing media routes")
 
+	// The V2Manager is injected by a functional option after New, so the
+	// archived clip index is wired here rather than at construction.
+	c.initArchivedClips()
+
 	// Datastore-independent media routes serve from SecureFS / BirdImageCache and do
 	// not touch c.DS, so they register regardless of datastore availability.
 	// Original filename-based routes (keep for backward compatibility if needed, but ensure they use SFS)
@@ -778,6 +782,10 @@ func (c *Handler) ServeAudioByID(ctx echo.Context) error {
 		return c.HandleError(ctx, err, "Invalid clip path", http.StatusBadRequest)
 	}
 
+	// Clips moved off the local disk by the tier retention policy are
+	// recalled before serving.
+	c.ensureClipLocal(ctx.Request().Context(), normalizedClipPath)
+
 	// Extract the original filename and extension
 	originalFilename := filepath.Base(clipPath)
 	ext := strings.ToLower(filepath.Ext(originalFilename))
@@ -907,6 +915,7 @@ func (c *Handler) ExtractAudioClipByID(ctx echo.Context) error {
 	absolutePath := filepath.Join(c.SFS.BaseDir(), normalizedPath)
 
 	// Check if file exists using SecureFS (handle encoding-in-progress same as ServeAudioByID)
+	c.ensureClipLocal(ctx.Request().Context(), normalizedPath)
 	if _, statErr := c.SFS.StatRel(normalizedPath); statErr != nil {
 		if c.isAudioBeingEncoded(normalizedPath) {
 			return c.handleAudioNotReady(ctx)
@@ -1040,6 +1049,7 @@ func (c *Handler) ProcessAudioByID(ctx echo.Context) error {
 	}
 	absolutePath := filepath.Join(c.SFS.BaseDir(), normalizedPath)
 
+	c.ensureClipLocal(ctx.Request().Context(), normalizedPath)
 	if _, statErr := c.SFS.StatRel(normalizedPath); statErr != nil {
 		return c.HandleError(ctx, statErr, "Audio clip not found", http.StatusNotFound)
 	}
@@ -1165,6 +1175,7 @@ func (c *Handler) ProcessedSpectrogramByID(ctx echo.Context) error {
 	}
 	absolutePath := filepath.Join(c.SFS.BaseDir(), normalizedPath)
 
+	c.ensureClipLocal(ctx.Request().Context(), normalizedPath)
 	if _, statErr := c.SFS.StatRel(normalizedPath); statErr != nil {
 		return c.HandleError(ctx, statErr, "Audio clip not found", http.StatusNotFound)
 	}
@@ -1896,6 +1907,7 @@ func (c *Handler) runAsyncSpectrogramGeneration(noteID, clipPath, relAudioPath,
 	// missing file. The lookup and wait are skipped when the clip already exists.
 	genTimeout := spectrogramGenerationTimeout
 	var pendingDeadline time.Time
+	c.ensureClipLocal(c.Context(), relAudioPath)
 	if _, statErr := c.SFS.StatRel(relAudioPath); statErr != nil {
 		begin, end := c.noteCaptureTimes(noteID)
 		if win, ok := c.CurrentSettings().DetectionCaptureWindow(begin, end); ok {
@@ -2708,6 +2720,10 @@ func (c *Handler) waitForAudioFileCtx(ctx context.Context, relAudioPath string)
 	if _, err := c.SFS.StatRel(relAudioPath); err == nil {
 		return nil
 	}
+	// An archived clip is recalled instead of waited for.
+	if c.recallArchivedClip(ctx, relAudioPath) {
+		return nil
+	}
 
 	waitCtx, cancel := context.WithTimeout(ctx, audioWaitTimeout)
 	defer cancel()
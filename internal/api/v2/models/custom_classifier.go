@@ -0,0 +1,252 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/classifier"
+	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// msgNoCustomExamples is returned by the endpoints that need the labelled
+// clip store when the enhanced (v2) database is not in use.
+const msgNoCustomExamples = "The custom classifier requires the enhanced (v2) database"
+
+// CustomClassifierStatus is the response of GetCustomClassifier.
+type CustomClassifierStatus struct {
+	Trained        bool                              `json:"trained"`
+	Loaded         bool                              `json:"loaded"`
+	EmbeddingModel string                            `json:"embeddingModel,omitempty"`
+	Labels         []string                          `json:"labels,omitempty"`
+	TrainedAt      *time.Time                        `json:"trainedAt,omitempty"`
+	Stats          []classifier.CustomLabelStats     `json:"stats,omitempty"`
+	Skipped        []string                          `json:"skipped,omitempty"`
+	Examples       []repository.ClassifierLabelCount `json:"examples"`    // labelled clips per label
+	MinExamples    int                               `json:"minExamples"` // clips a label needs to be trained
+}
+
+// CustomExampleResponse is a labelled clip.
+type CustomExampleResponse struct {
+	DetectionID uint      `json:"detectionId"`
+	Label       string    `json:"label"`
+	LabelledBy  string    `json:"labelledBy,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// CustomExampleRequest labels a clip.
+type CustomExampleRequest struct {
+	Label string `json:"label"`
+}
+
+// initCustomClassifier wires the labelled clip store. It stays nil without
+// the enhanced (v2) database, which stores the labels and the embeddings
+// they are trained on.
+func (c *Handler) initCustomClassifier() {
+	if c.customExamples == nil && c.V2Manager != nil && datastoreV2.IsEnhancedDatabase() {
+		c.customExamples = repository.NewClassifierExampleRepository(c.V2Manager.DB(), nil)
+	}
+}
+
+// GetCustomClassifier returns the trained custom classifier, if any, and the
+// number of labelled clips per label.
+func (c *Handler) GetCustomClassifier(ctx echo.Context) error {
+	status, err := c.customClassifierStatus(ctx.Request().Context())
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to read the custom classifier", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, status)
+}
+
+// customClassifierStatus assembles the custom classifier status from the
+// installed head, the orchestrator and the labelled clip store.
+func (c *Handler) customClassifierStatus(ctx context.Context) (*CustomClassifierStatus, error) {
+	status := &CustomClassifierStatus{
+		Examples:    []repository.ClassifierLabelCount{},
+		MinExamples: classifier.MinCustomLabelExamples,
+	}
+
+	if c.customExamples != nil {
+		counts, err := c.customExamples.LabelCounts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		status.Examples = counts
+	}
+
+	if c.ModelManager != nil {
+		head, err := c.ModelManager.CustomClassifierHead()
+		if err != nil {
+			return nil, err
+		}
+		if head != nil {
+			status.Trained = true
+			status.EmbeddingModel = head.EmbeddingModel
+			status.Labels = head.Labels
+			status.TrainedAt = &head.TrainedAt
+			status.Stats = head.Stats
+			status.Skipped = head.Skipped
+		}
+	}
+	if orch, err := c.GetBirdNETInstance(); err == nil {
+		status.Loaded = orch.IsModelLoaded(classifier.RegistryIDCustomClassifier)
+	}
+
+	return status, nil
+}
+
+// ListCustomExamples returns the labelled clips, newest first.
+// Query parameters:
+// - label: only list clips carrying this label
+func (c *Handler) ListCustomExamples(ctx echo.Context) error {
+	if c.customExamples == nil {
+		return c.HandleError(ctx, nil, msgNoCustomExamples, http.StatusConflict)
+	}
+
+	examples, err := c.customExamples.List(ctx.Request().Context(), ctx.QueryParam("label"))
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to list labelled clips", http.StatusInternalServerError)
+	}
+
+	resp := make([]CustomExampleResponse, 0, len(examples))
+	for i := range examples {
+		resp = append(resp, CustomExampleResponse{
+			DetectionID: examples[i].DetectionID,
+			Label:       examples[i].Label,
+			LabelledBy:  examples[i].LabelledBy,
+			UpdatedAt:   examples[i].UpdatedAt,
+		})
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// SetCustomExample labels a detection's clip for the custom classifier,
+// replacing any earlier label.
+func (c *Handler) SetCustomExample(ctx echo.Context) error {
+	if c.customExamples == nil {
+		return c.HandleError(ctx, nil, msgNoCustomExamples, http.StatusConflict)
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("detectionId"), 10, 64)
+	if err != nil || id == 0 {
+		return c.HandleError(ctx, err, "Invalid detection ID", http.StatusBadRequest)
+	}
+
+	var req CustomExampleRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+	label, err := classifier.ValidateCustomLabel(req.Label)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	if _, err := c.DS.Get(strconv.FormatUint(id, 10)); err != nil {
+		return c.HandleError(ctx, err, "Detection not found", http.StatusNotFound)
+	}
+
+	username := auth.UsernameFromContext(ctx)
+	if err := c.customExamples.Set(ctx.Request().Context(), uint(id), label, username); err != nil {
+		return c.HandleError(ctx, err, "Failed to label clip", http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, CustomExampleResponse{
+		DetectionID: uint(id),
+		Label:       label,
+		LabelledBy:  username,
+		UpdatedAt:   time.Now(),
+	})
+}
+
+// DeleteCustomExample removes a detection's custom classifier label.
+func (c *Handler) DeleteCustomExample(ctx echo.Context) error {
+	if c.customExamples == nil {
+		return c.HandleError(ctx, nil, msgNoCustomExamples, http.StatusConflict)
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("detectionId"), 10, 64)
+	if err != nil || id == 0 {
+		return c.HandleError(ctx, err, "Invalid detection ID", http.StatusBadRequest)
+	}
+
+	err = c.customExamples.Delete(ctx.Request().Context(), uint(id))
+	if errors.Is(err, repository.ErrClassifierExampleNotFound) {
+		return c.HandleError(ctx, err, "Clip is not labelled", http.StatusNotFound)
+	}
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to remove label", http.StatusInternalServerError)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// TrainCustomClassifier trains the custom classifier on the labelled and
+// reviewed clips (see ClassifierExampleRepository.TrainingSet for how review
+// verdicts count), then installs and hot-loads it. Training
+// runs in the request; it takes seconds even for thousands of clips.
+func (c *Handler) TrainCustomClassifier(ctx echo.Context) error {
+	if c.customExamples == nil {
+		return c.HandleError(ctx, nil, msgNoCustomExamples, http.StatusConflict)
+	}
+	if c.ModelManager == nil {
+		return c.HandleError(ctx, nil, "model manager is not available", http.StatusServiceUnavailable)
+	}
+	orch, err := c.GetBirdNETInstance()
+	if err != nil {
+		return c.HandleError(ctx, err, "Classifier is not available", http.StatusServiceUnavailable)
+	}
+
+	embeddingPath := orch.EmbeddingModelPath(c.CurrentSettings())
+	if embeddingPath == "" {
+		return c.HandleError(ctx, nil,
+			"The custom classifier needs the BirdNET v2.4 embeddings model; install the bat model or set the clip embeddings model path",
+			http.StatusConflict)
+	}
+	embeddingModel := classifier.EmbeddingModelID(embeddingPath)
+
+	reqCtx := ctx.Request().Context()
+	examples, err := c.customExamples.TrainingSet(reqCtx, embeddingModel)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to load labelled clips", http.StatusInternalServerError)
+	}
+	samples := make([]classifier.CustomTrainingSample, len(examples))
+	for i := range examples {
+		samples[i] = classifier.CustomTrainingSample{Label: examples[i].Label, Vector: examples[i].Vector}
+	}
+
+	head, err := classifier.TrainCustomHead(reqCtx, embeddingModel, samples)
+	if errors.IsCategory(err, errors.CategoryValidation) {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to train custom classifier", http.StatusInternalServerError)
+	}
+
+	if err := c.ModelManager.InstallCustomClassifier(head); err != nil {
+		return c.HandleError(ctx, err, "Failed to install custom classifier", http.StatusInternalServerError)
+	}
+
+	status, err := c.customClassifierStatus(reqCtx)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to read the custom classifier", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, status)
+}
+
+// DeleteCustomClassifier unloads and removes the trained custom classifier.
+// Labelled clips are kept so it can be retrained.
+func (c *Handler) DeleteCustomClassifier(ctx echo.Context) error {
+	if c.ModelManager == nil {
+		return c.HandleError(ctx, nil, "model manager is not available", http.StatusServiceUnavailable)
+	}
+	if err := c.ModelManager.RemoveCustomClassifier(); err != nil {
+		return c.HandleError(ctx, err, "Failed to remove custom classifier", http.StatusInternalServerError)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
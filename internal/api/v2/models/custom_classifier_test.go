@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+
+	"github.com/tphakala/birdnet-go/internal/api/v2/apitest"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+)
+
+// setupCustomClassifierHandler registers the models routes on a fresh Echo,
+// with the labelled clip store backed by a temporary SQLite database when
+// withRepo is set.
+func setupCustomClassifierHandler(t *testing.T, ds *mocks.MockInterface, withRepo bool) *echo.Echo {
+	t.Helper()
+	e := echo.New()
+	core := apitest.NewCore(t, apitest.WithEcho(e), apitest.WithDatastore(ds))
+	core.AuthMiddleware = func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+
+	h := New(core)
+	if withRepo {
+		db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "custom.db")), &gorm.Config{
+			Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+		})
+		require.NoError(t, err)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+		require.NoError(t, db.AutoMigrate(&entities.ClassifierExample{}))
+		h.customExamples = repository.NewClassifierExampleRepository(db, nil)
+	}
+	h.RegisterRoutes(core.Group)
+	return e
+}
+
+func doCustomRequest(t *testing.T, e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCustomExampleRoutesReturn409WithoutV2(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e := setupCustomClassifierHandler(t, mocks.NewMockInterface(t), false)
+
+	rec := doCustomRequest(t, e, http.MethodPut, "/api/v2/models/custom/examples/5", `{"label":"Parrot"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = doCustomRequest(t, e, http.MethodPost, "/api/v2/models/custom/train", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// Status stays readable: no clips, nothing trained.
+	rec = doCustomRequest(t, e, http.MethodGet, "/api/v2/models/custom", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status CustomClassifierStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.False(t, status.Trained)
+	assert.Empty(t, status.Examples)
+}
+
+func TestCustomExampleLabelLifecycle(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	ds := mocks.NewMockInterface(t)
+	ds.EXPECT().Get("5").Return(datastore.Note{ID: 5}, nil)
+	ds.EXPECT().Get("6").Return(datastore.Note{}, assert.AnError)
+	e := setupCustomClassifierHandler(t, ds, true)
+
+	rec := doCustomRequest(t, e, http.MethodPut, "/api/v2/models/custom/examples/5", `{"label":"Under_score"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "invalid label")
+
+	rec = doCustomRequest(t, e, http.MethodPut, "/api/v2/models/custom/examples/6", `{"label":"Parrot"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "unknown detection")
+
+	rec = doCustomRequest(t, e, http.MethodPut, "/api/v2/models/custom/examples/5", `{"label":" Parrot "}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doCustomRequest(t, e, http.MethodGet, "/api/v2/models/custom/examples?label=Parrot", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var examples []CustomExampleResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &examples))
+	require.Len(t, examples, 1)
+	assert.Equal(t, uint(5), examples[0].DetectionID)
+	assert.Equal(t, "Parrot", examples[0].Label, "label is trimmed")
+
+	rec = doCustomRequest(t, e, http.MethodGet, "/api/v2/models/custom", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status CustomClassifierStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, []repository.ClassifierLabelCount{{Label: "Parrot", Count: 1}}, status.Examples)
+
+	rec = doCustomRequest(t, e, http.MethodDelete, "/api/v2/models/custom/examples/5", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = doCustomRequest(t, e, http.MethodDelete, "/api/v2/models/custom/examples/5", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
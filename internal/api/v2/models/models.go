@@ -1,7 +1,8 @@
 // Package models is the api/v2 models domain handler. It owns the
 // /api/v2/models/* endpoints: listing the enabled classifier models, browsing
-// the model gallery catalog, and installing, reinstalling, uninstalling, and
-// streaming download progress for gallery models. The Handler embeds
+// the model gallery catalog, installing, reinstalling, uninstalling, and
+// streaming download progress for gallery models, and training the custom
+// classifier on user-labelled clips. The Handler embeds
 // *apicore.Core by pointer so the shared dependencies and helpers (ModelManager,
 // CurrentSettings, HandleError, the Go/Context goroutine plumbing, and the
 // logging helpers) promote onto it; the facade constructs one Handler and calls
@@ -20,6 +21,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
 	"github.com/tphakala/birdnet-go/internal/classifier"
 	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
 	"github.com/tphakala/birdnet-go/internal/inference"
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
@@ -29,11 +31,17 @@ import (
 // carries atomic/lock-bearing fields and must never be copied by value.
 type Handler struct {
 	*apicore.Core
+
+	// customExamples stores the custom classifier's labelled clips; nil
+	// without the enhanced (v2) database. Wired in RegisterRoutes because
+	// V2Manager is set after the handler is built.
+	customExamples repository.ClassifierExampleRepository
 }
 
 // New builds a models Handler around the shared core. The models handlers need
-// only the shared *apicore.Core (ModelManager, settings, error/log helpers and
-// the goroutine plumbing), so there are no facade-owned dependencies to inject.
+// only the shared *apicore.Core (ModelManager, settings, error/log helpers, the
+// v2 database and the goroutine plumbing), so there are no facade-owned
+// dependencies to inject.
 func New(core *apicore.Core) *Handler {
 	return &Handler{Core: core}
 }
@@ -49,6 +57,15 @@ func (c *Handler) RegisterRoutes(g *echo.Group) {
 	g.POST("/models/reinstall/:id", c.ReinstallModel, c.AuthMiddleware)
 	g.DELETE("/models/installed/:id", c.UninstallModel, c.AuthMiddleware)
 	g.GET("/models/install/:id/progress", c.StreamInstallProgress)
+
+	// Custom classifier trained on user-labelled clips
+	c.initCustomClassifier()
+	g.GET("/models/custom", c.GetCustomClassifier)
+	g.POST("/models/custom/train", c.TrainCustomClassifier, c.AuthMiddleware)
+	g.DELETE("/models/custom", c.DeleteCustomClassifier, c.AuthMiddleware)
+	g.GET("/models/custom/examples", c.ListCustomExamples, c.AuthMiddleware)
+	g.PUT("/models/custom/examples/:detectionId", c.SetCustomExample, c.AuthMiddleware)
+	g.DELETE("/models/custom/examples/:detectionId", c.DeleteCustomExample, c.AuthMiddleware)
 }
 
 // ModelListItem represents a model in the API response.
@@ -25,6 +25,12 @@ func TestModelsRouteRegistration(t *testing.T) {
 		"POST /api/v2/models/reinstall/:id",
 		"DELETE /api/v2/models/installed/:id",
 		"GET /api/v2/models/install/:id/progress",
+		"GET /api/v2/models/custom",
+		"POST /api/v2/models/custom/train",
+		"DELETE /api/v2/models/custom",
+		"GET /api/v2/models/custom/examples",
+		"PUT /api/v2/models/custom/examples/:detectionId",
+		"DELETE /api/v2/models/custom/examples/:detectionId",
 	}
 	apitest.AssertRoutesRegistered(t, e, expectedRoutes)
 }
@@ -15,6 +15,7 @@ package api
 
 import (
 	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
+	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
 )
 
@@ -101,6 +102,23 @@ func (c *Controller) canonicalizeExcludeList(exclude []string) []string {
 	return apicore.CanonicalizeExcludeList(c.loadCommonToScientificMap(), exclude)
 }
 
+// normalizeSourceSpecies gives every per-source species override the same
+// treatment as the global species settings: lowercase config keys and an
+// exclude list canonicalized to scientific names.
+func (c *Controller) normalizeSourceSpecies(settings *conf.Settings) {
+	settings.NormalizeSourceSpeciesConfigKeys()
+	for _, stream := range settings.Realtime.RTSP.AllStreams() {
+		if stream.Species != nil {
+			stream.Species.Exclude = c.canonicalizeExcludeList(stream.Species.Exclude)
+		}
+	}
+	for i := range settings.Realtime.Audio.Sources {
+		if species := settings.Realtime.Audio.Sources[i].Species; species != nil {
+			species.Exclude = c.canonicalizeExcludeList(species.Exclude)
+		}
+	}
+}
+
 // UpdateCommonNameMap rebuilds both cached name maps from updated BirdNET labels.
 // Called after locale or model changes to keep insights and search endpoints current.
 func (c *Controller) UpdateCommonNameMap(labels []string) {
@@ -41,6 +41,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/notification"
 	"github.com/tphakala/birdnet-go/internal/observability/metrics"
 	"github.com/tphakala/birdnet-go/internal/privacy"
+	"github.com/tphakala/birdnet-go/internal/security"
 	"golang.org/x/time/rate"
 )
 
@@ -261,7 +262,7 @@ func (c *Handler) RegisterRoutes(g *echo.Group) {
 	// Auth-protected endpoints: per-item read, mutations, the test-notification
 	// trigger, and the NTFY connectivity probe (kept authed to avoid being
 	// used as an SSRF relay by unauthenticated callers).
-	notificationsGroup := g.Group("/notifications", c.AuthMiddleware)
+	notificationsGroup := g.Group("/notifications", c.RequireRole(security.RoleViewer))
 
 	notifServiceGroup := notificationsGroup.Group("", c.requireNotificationService)
 	notifServiceGroup.PUT("/read-all", c.MarkAllNotificationsRead)
@@ -0,0 +1,289 @@
+// Package outboxapi is the api/v2 delivery outbox domain handler. It owns the
+// /api/v2/outbox/* endpoints that let an admin inspect the queued BirdWeather,
+// MQTT and webhook deliveries, retry dead-lettered items and purge items that
+// should not be delivered any more. The Handler embeds *apicore.Core by
+// pointer so the shared dependencies and helpers (HandleError, the logging
+// helpers, the V2Manager, the Processor and the auth middleware) promote onto
+// it.
+//
+// Like the alerts domain it also owns the background machinery: when the
+// enhanced v2 database is active RegisterRoutes builds the outbox, points the
+// processor and the push webhooks at it and starts its delivery worker, and
+// the facade calls Shutdown to stop the worker. Without the v2 database every
+// route answers 409 Conflict and deliveries keep their in-memory retries.
+package outboxapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
+	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/notification"
+	"github.com/tphakala/birdnet-go/internal/outbox"
+)
+
+const (
+	// defaultListLimit and maxListLimit bound the item listing.
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// Handler serves the outbox endpoints. repo and box are nil until
+// RegisterRoutes builds them, which only happens when the enhanced v2
+// database is active.
+type Handler struct {
+	*apicore.Core
+
+	repo repository.OutboxRepository
+	box  *outbox.Outbox
+}
+
+// New builds an outbox Handler around the shared core.
+func New(core *apicore.Core) *Handler {
+	return &Handler{Core: core}
+}
+
+// RegisterRoutes registers the outbox endpoints and starts the delivery
+// worker. All routes require the admin role; authentication runs before the
+// v2 check so unauthenticated callers get 401 rather than learning about the
+// database state.
+func (c *Handler) RegisterRoutes(g *echo.Group) {
+	routes := g.Group("/outbox", c.AuthMiddleware, c.requireV2Middleware)
+	routes.GET("", c.ListOutboxItems)
+	routes.GET("/stats", c.GetOutboxStats)
+	routes.POST("/retry", c.RetryDeadOutboxItems)
+	routes.DELETE("", c.PurgeOutboxItems)
+	routes.POST("/:id/retry", c.RetryOutboxItem)
+	routes.DELETE("/:id", c.DeleteOutboxItem)
+
+	if c.V2Manager == nil || !datastoreV2.IsEnhancedDatabase() {
+		apicore.GetLogger().Info("delivery outbox skipped: v2 database schema not active")
+		return
+	}
+	c.repo = repository.NewOutboxRepository(c.V2Manager.DB(), nil)
+	c.box = outbox.New(c.repo)
+	c.wire()
+	c.box.Start(c.Context())
+}
+
+// wire registers the deliverers on the outbox and routes the processor's
+// BirdWeather and MQTT deliveries and the push webhooks through it.
+func (c *Handler) wire() {
+	c.box.Register(outbox.KindWebhook, deliverWebhook)
+	notification.SetWebhookOutbox(func(ctx context.Context, provider, summary string, payload []byte) error {
+		return c.box.Enqueue(ctx, outbox.Destination(outbox.KindWebhook, provider), summary, payload)
+	})
+	if c.Processor != nil {
+		c.Processor.SetOutbox(c.box)
+	}
+}
+
+// deliverWebhook sends a queued notification to its webhook provider. A
+// provider that was removed cannot receive it, so the item is dead-lettered.
+func deliverWebhook(ctx context.Context, provider string, payload []byte) error {
+	err := notification.DeliverQueuedWebhook(ctx, provider, payload)
+	if errors.Is(err, notification.ErrWebhookProviderNotFound) {
+		return outbox.Permanent(err)
+	}
+	return err
+}
+
+// Shutdown stops the delivery worker. Webhooks fall back to direct delivery;
+// queued items stay in the database and are delivered after the next start.
+func (c *Handler) Shutdown() {
+	if c.box == nil {
+		return
+	}
+	notification.SetWebhookOutbox(nil)
+	c.box.Stop()
+}
+
+// requireV2Middleware answers 409 Conflict when the outbox is unavailable.
+func (c *Handler) requireV2Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if c.repo == nil {
+			return c.HandleError(ctx, nil, "The delivery outbox requires the enhanced (v2) database", http.StatusConflict)
+		}
+		return next(ctx)
+	}
+}
+
+// ListOutboxItems handles GET /api/v2/outbox. The destination and status
+// query parameters filter the items; limit and offset page through them.
+func (c *Handler) ListOutboxItems(ctx echo.Context) error {
+	status, err := parseStatus(ctx.QueryParam("status"))
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+	limit, err := parseNonNegative(ctx.QueryParam("limit"), defaultListLimit)
+	if err != nil || limit == 0 {
+		return c.HandleError(ctx, err, "Invalid limit", http.StatusBadRequest)
+	}
+	offset, err := parseNonNegative(ctx.QueryParam("offset"), 0)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid offset", http.StatusBadRequest)
+	}
+
+	items, err := c.repo.List(ctx.Request().Context(), repository.OutboxFilter{
+		Destination: ctx.QueryParam("destination"),
+		Status:      status,
+		Limit:       min(limit, maxListLimit),
+		Offset:      offset,
+	})
+	if err != nil {
+		c.LogErrorIfEnabled("failed to list outbox items", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to list outbox items", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"items": items,
+		"count": len(items),
+	})
+}
+
+// GetOutboxStats handles GET /api/v2/outbox/stats.
+func (c *Handler) GetOutboxStats(ctx echo.Context) error {
+	stats, err := c.repo.Stats(ctx.Request().Context())
+	if err != nil {
+		c.LogErrorIfEnabled("failed to count outbox items", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to count outbox items", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{"stats": stats})
+}
+
+// RetryOutboxItem handles POST /api/v2/outbox/:id/retry. The item gets a
+// fresh attempt budget and is delivered right away.
+func (c *Handler) RetryOutboxItem(ctx echo.Context) error {
+	id, err := parseID(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid outbox item ID", http.StatusBadRequest)
+	}
+	if err := c.repo.Retry(ctx.Request().Context(), id, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrOutboxItemNotFound) {
+			return c.HandleError(ctx, err, "Outbox item not found", http.StatusNotFound)
+		}
+		c.LogErrorIfEnabled("failed to retry outbox item", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to retry outbox item", http.StatusInternalServerError)
+	}
+	c.wake()
+
+	c.LogInfoIfEnabled("outbox item retried",
+		logger.Uint64("item_id", uint64(id)),
+		logger.String("ip", ctx.RealIP()))
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Outbox item queued for retry"})
+}
+
+// RetryDeadOutboxItems handles POST /api/v2/outbox/retry. It retries every
+// dead item, or those of one destination given by the destination query
+// parameter.
+func (c *Handler) RetryDeadOutboxItems(ctx echo.Context) error {
+	destination := ctx.QueryParam("destination")
+	retried, err := c.repo.RetryDead(ctx.Request().Context(), destination, time.Now())
+	if err != nil {
+		c.LogErrorIfEnabled("failed to retry dead outbox items", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to retry outbox items", http.StatusInternalServerError)
+	}
+	c.wake()
+
+	c.LogInfoIfEnabled("dead outbox items retried",
+		logger.String("destination", destination),
+		logger.Int64("count", retried),
+		logger.String("ip", ctx.RealIP()))
+	return ctx.JSON(http.StatusOK, map[string]any{"retried": retried})
+}
+
+// DeleteOutboxItem handles DELETE /api/v2/outbox/:id.
+func (c *Handler) DeleteOutboxItem(ctx echo.Context) error {
+	id, err := parseID(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid outbox item ID", http.StatusBadRequest)
+	}
+	if err := c.repo.Delete(ctx.Request().Context(), id); err != nil {
+		if errors.Is(err, repository.ErrOutboxItemNotFound) {
+			return c.HandleError(ctx, err, "Outbox item not found", http.StatusNotFound)
+		}
+		c.LogErrorIfEnabled("failed to delete outbox item", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to delete outbox item", http.StatusInternalServerError)
+	}
+
+	c.LogInfoIfEnabled("outbox item deleted",
+		logger.Uint64("item_id", uint64(id)),
+		logger.String("ip", ctx.RealIP()))
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Outbox item deleted"})
+}
+
+// PurgeOutboxItems handles DELETE /api/v2/outbox. The destination and status
+// query parameters narrow the purge; without them every item is removed.
+func (c *Handler) PurgeOutboxItems(ctx echo.Context) error {
+	status, err := parseStatus(ctx.QueryParam("status"))
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+	destination := ctx.QueryParam("destination")
+	purged, err := c.repo.Purge(ctx.Request().Context(), destination, status)
+	if err != nil {
+		c.LogErrorIfEnabled("failed to purge outbox items", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to purge outbox items", http.StatusInternalServerError)
+	}
+
+	c.LogInfoIfEnabled("outbox items purged",
+		logger.String("destination", destination),
+		logger.String("status", string(status)),
+		logger.Int64("count", purged),
+		logger.String("ip", ctx.RealIP()))
+	return ctx.JSON(http.StatusOK, map[string]any{"purged": purged})
+}
+
+// wake makes the worker pick up retried items now.
+func (c *Handler) wake() {
+	if c.box != nil {
+		c.box.Wake()
+	}
+}
+
+// parseID reads the :id path parameter.
+func parseID(ctx echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// parseStatus validates an optional status filter.
+func parseStatus(value string) (entities.OutboxStatus, error) {
+	switch status := entities.OutboxStatus(value); status {
+	case "", entities.OutboxStatusPending, entities.OutboxStatusDead:
+		return status, nil
+	default:
+		return "", errors.Newf("invalid status %q, expected pending or dead", value).
+			Component("api").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+}
+
+// parseNonNegative parses an optional non-negative integer query parameter.
+func parseNonNegative(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, errors.Newf("value must not be negative").
+			Component("api").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+	return n, nil
+}
@@ -0,0 +1,142 @@
+package outboxapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+
+	"github.com/tphakala/birdnet-go/internal/api/v2/apitest"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+)
+
+// passthroughMiddleware stands in for the admin auth middleware.
+func passthroughMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
+
+// setupOutboxHandler registers the outbox routes on a fresh Echo. With
+// withRepo the repository is backed by a temporary SQLite database; no worker
+// is started.
+func setupOutboxHandler(t *testing.T, withRepo bool) (*echo.Echo, repository.OutboxRepository) {
+	t.Helper()
+	e := echo.New()
+	core := apitest.NewCore(t, apitest.WithEcho(e))
+	core.AuthMiddleware = passthroughMiddleware
+
+	h := New(core)
+	if withRepo {
+		db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "outbox.db")), &gorm.Config{
+			Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+		})
+		require.NoError(t, err)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+		require.NoError(t, db.AutoMigrate(&entities.OutboxItem{}))
+		h.repo = repository.NewOutboxRepository(db, nil)
+	}
+	h.RegisterRoutes(core.Group)
+	return e, h.repo
+}
+
+func do(t *testing.T, e *echo.Echo, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, http.NoBody)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestOutboxRoutesReturn409WithoutV2(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, _ := setupOutboxHandler(t, false)
+	rec := do(t, e, http.MethodGet, "/api/v2/outbox")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestOutboxItemLifecycle(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, repo := setupOutboxHandler(t, true)
+	ctx := t.Context()
+
+	now := time.Now()
+	items := []*entities.OutboxItem{
+		{Destination: "birdweather", Status: entities.OutboxStatusPending, Summary: "Great Tit (0.91)", Payload: []byte("{}"), NextAttemptAt: now},
+		{Destination: "mqtt", Status: entities.OutboxStatusDead, Summary: "Robin (0.88)", Payload: []byte("{}"), Attempts: 100, NextAttemptAt: now},
+		{Destination: "webhook:discord", Status: entities.OutboxStatusDead, Summary: "New species", Payload: []byte("{}"), Attempts: 1, NextAttemptAt: now},
+	}
+	for _, item := range items {
+		require.NoError(t, repo.Create(ctx, item))
+	}
+
+	rec := do(t, e, http.MethodGet, "/api/v2/outbox?status=dead")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var listed struct {
+		Items []entities.OutboxItem `json:"items"`
+		Count int                   `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	assert.Equal(t, 2, listed.Count)
+	assert.NotContains(t, rec.Body.String(), "payload", "payloads are not exposed")
+
+	rec = do(t, e, http.MethodGet, "/api/v2/outbox/stats")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"destination":"webhook:discord"`)
+
+	// Retry a single dead item.
+	rec = do(t, e, http.MethodPost, "/api/v2/outbox/"+strconv.FormatUint(uint64(items[1].ID), 10)+"/retry")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	retried, err := repo.GetByID(ctx, items[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.OutboxStatusPending, retried.Status)
+	assert.Zero(t, retried.Attempts)
+
+	// Retry the remaining dead items of one destination.
+	rec = do(t, e, http.MethodPost, "/api/v2/outbox/retry?destination=webhook:discord")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"retried":1}`, rec.Body.String())
+
+	rec = do(t, e, http.MethodDelete, "/api/v2/outbox/"+strconv.FormatUint(uint64(items[0].ID), 10))
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = do(t, e, http.MethodDelete, "/api/v2/outbox/"+strconv.FormatUint(uint64(items[0].ID), 10))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = do(t, e, http.MethodDelete, "/api/v2/outbox?destination=mqtt")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"purged":1}`, rec.Body.String())
+
+	remaining, err := repo.List(ctx, repository.OutboxFilter{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "webhook:discord", remaining[0].Destination)
+}
+
+func TestOutboxRoutesValidateQuery(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, _ := setupOutboxHandler(t, true)
+
+	for _, path := range []string{
+		"/api/v2/outbox?status=delivered",
+		"/api/v2/outbox?limit=0",
+		"/api/v2/outbox?offset=-1",
+	} {
+		rec := do(t, e, http.MethodGet, path)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+	}
+	rec := do(t, e, http.MethodDelete, "/api/v2/outbox?status=sent")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = do(t, e, http.MethodPost, "/api/v2/outbox/abc/retry")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
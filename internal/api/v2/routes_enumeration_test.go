@@ -27,14 +27,21 @@ import (
 var goldenRoutes = []string{
 	"DELETE /api/v2/alerts/history",
 	"DELETE /api/v2/alerts/rules/:id",
+	"DELETE /api/v2/auth/keys/:id",
 	"DELETE /api/v2/detections/:id",
 	"DELETE /api/v2/dynamic-thresholds",
 	"DELETE /api/v2/dynamic-thresholds/:species",
+	"DELETE /api/v2/exports/jobs/:id",
 	"DELETE /api/v2/integrations/mqtt/tls/certificate",
+	"DELETE /api/v2/models/custom",
+	"DELETE /api/v2/models/custom/examples/:detectionId",
 	"DELETE /api/v2/models/installed/:id",
 	"DELETE /api/v2/notifications/:id",
+	"DELETE /api/v2/outbox",
+	"DELETE /api/v2/outbox/:id",
 	"DELETE /api/v2/system/database/backup/jobs/:id",
 	"DELETE /api/v2/tls/certificate",
+	"DELETE /api/v2/users/:id",
 	"GET /api/v2/alerts/history",
 	"GET /api/v2/alerts/rules",
 	"GET /api/v2/alerts/rules/:id",
@@ -64,10 +71,14 @@ var goldenRoutes = []string{
 	"GET /api/v2/app/config",
 	"GET /api/v2/audio/:id",
 	"GET /api/v2/auth/callback",
+	"GET /api/v2/auth/keys",
+	"GET /api/v2/auth/keys/scopes",
 	"GET /api/v2/auth/status",
 	"GET /api/v2/control/actions",
 	"GET /api/v2/detections",
 	"GET /api/v2/detections/:id",
+	"GET /api/v2/detections/:id/clip-export",
+	"GET /api/v2/detections/:id/similar",
 	"GET /api/v2/detections/:id/time-of-day",
 	"GET /api/v2/detections/ignored",
 	"GET /api/v2/detections/recent",
@@ -76,6 +87,9 @@ var goldenRoutes = []string{
 	"GET /api/v2/dynamic-thresholds/:species",
 	"GET /api/v2/dynamic-thresholds/:species/events",
 	"GET /api/v2/dynamic-thresholds/stats",
+	"GET /api/v2/exports/jobs",
+	"GET /api/v2/exports/jobs/:id",
+	"GET /api/v2/exports/jobs/:id/download",
 	"GET /api/v2/filesystem/browse",
 	"GET /api/v2/health",
 	"GET /api/v2/health/audio",
@@ -94,6 +108,8 @@ var goldenRoutes = []string{
 	"GET /api/v2/media/spectrogram/:filename",
 	"GET /api/v2/models",
 	"GET /api/v2/models/catalog",
+	"GET /api/v2/models/custom",
+	"GET /api/v2/models/custom/examples",
 	"GET /api/v2/models/install/:id/progress",
 	"GET /api/v2/models/installed",
 	"GET /api/v2/notifications",
@@ -101,6 +117,8 @@ var goldenRoutes = []string{
 	"GET /api/v2/notifications/check-ntfy-server",
 	"GET /api/v2/notifications/stream",
 	"GET /api/v2/notifications/unread/count",
+	"GET /api/v2/outbox",
+	"GET /api/v2/outbox/stats",
 	"GET /api/v2/ping",
 	"GET /api/v2/range/heatmap",
 	"GET /api/v2/range/species/count",
@@ -115,6 +133,9 @@ var goldenRoutes = []string{
 	"GET /api/v2/settings/locales",
 	"GET /api/v2/settings/systemid",
 	"GET /api/v2/soundlevels/stream",
+	"GET /api/v2/soundscapes",
+	"GET /api/v2/soundscapes/:id/audio",
+	"GET /api/v2/soundscapes/sources",
 	"GET /api/v2/species",
 	"GET /api/v2/species/:code/thumbnail",
 	"GET /api/v2/species/all",
@@ -140,6 +161,7 @@ var goldenRoutes = []string{
 	"GET /api/v2/system/audio/devices/capabilities",
 	"GET /api/v2/system/audio/equalizer/config",
 	"GET /api/v2/system/audio/sources",
+	"GET /api/v2/system/backups",
 	"GET /api/v2/system/database/backup/jobs",
 	"GET /api/v2/system/database/backup/jobs/:id",
 	"GET /api/v2/system/database/backup/jobs/:id/download",
@@ -171,12 +193,16 @@ var goldenRoutes = []string{
 	"GET /api/v2/terminal/ws",
 	"GET /api/v2/tls/certificate",
 	"GET /api/v2/tls/certificate/download",
+	"GET /api/v2/users",
+	"GET /api/v2/users/:id",
+	"GET /api/v2/users/roles",
 	"GET /api/v2/weather/daily/:date",
 	"GET /api/v2/weather/detection/:id",
 	"GET /api/v2/weather/hourly/:date",
 	"GET /api/v2/weather/hourly/:date/:hour",
 	"GET /api/v2/weather/latest",
 	"GET /api/v2/weather/moon/:date",
+	"GET /api/v2/weather/station",
 	"GET /api/v2/weather/sun/:date",
 	"PATCH /api/v2/alerts/rules/:id/toggle",
 	"PATCH /api/v2/settings/:section",
@@ -187,6 +213,7 @@ var goldenRoutes = []string{
 	"POST /api/v2/app/wizard/dismiss",
 	"POST /api/v2/audio/:id/clip",
 	"POST /api/v2/audio/:id/process",
+	"POST /api/v2/auth/keys",
 	"POST /api/v2/auth/login",
 	"POST /api/v2/auth/logout",
 	"POST /api/v2/control/rebuild-filter",
@@ -202,9 +229,12 @@ var goldenRoutes = []string{
 	"POST /api/v2/detections/batch/resolve",
 	"POST /api/v2/detections/batch/review",
 	"POST /api/v2/detections/ignore",
+	"POST /api/v2/exports/darwin-core",
 	"POST /api/v2/import/birdnet-pi",
 	"POST /api/v2/import/elevate",
 	"POST /api/v2/import/jobs/:jobId/cancel",
+	"POST /api/v2/import/preview",
+	"POST /api/v2/import/results",
 	"POST /api/v2/import/validate",
 	"POST /api/v2/integrations/birdweather/test",
 	"POST /api/v2/integrations/ebird/test",
@@ -212,12 +242,16 @@ var goldenRoutes = []string{
 	"POST /api/v2/integrations/mqtt/test",
 	"POST /api/v2/integrations/mqtt/tls/certificate",
 	"POST /api/v2/integrations/weather/test",
+	"POST /api/v2/models/custom/train",
 	"POST /api/v2/models/install/:id",
 	"POST /api/v2/models/reinstall/:id",
 	"POST /api/v2/notifications/test/new-species",
+	"POST /api/v2/outbox/:id/retry",
+	"POST /api/v2/outbox/retry",
 	"POST /api/v2/range/rebuild",
 	"POST /api/v2/range/species/test",
 	"POST /api/v2/search",
+	"POST /api/v2/search/clip-export",
 	"POST /api/v2/spectrogram/:id/generate",
 	"POST /api/v2/spectrogram/:id/process",
 	"POST /api/v2/streams/analyze-channels",
@@ -226,6 +260,8 @@ var goldenRoutes = []string{
 	"POST /api/v2/streams/hls/heartbeat",
 	"POST /api/v2/streams/test",
 	"POST /api/v2/support/generate",
+	"POST /api/v2/system/backups/:id/restore",
+	"POST /api/v2/system/backups/:id/verify",
 	"POST /api/v2/system/database/backup",
 	"POST /api/v2/system/database/backup/jobs",
 	"POST /api/v2/system/database/legacy/cleanup",
@@ -238,11 +274,15 @@ var goldenRoutes = []string{
 	"POST /api/v2/system/diagnostics/run",
 	"POST /api/v2/tls/certificate",
 	"POST /api/v2/tls/certificate/generate",
+	"POST /api/v2/users",
+	"POST /api/v2/weather/station",
 	"PUT /api/v2/alerts/rules/:id",
+	"PUT /api/v2/models/custom/examples/:detectionId",
 	"PUT /api/v2/notifications/:id/acknowledge",
 	"PUT /api/v2/notifications/:id/read",
 	"PUT /api/v2/notifications/read-all",
 	"PUT /api/v2/settings",
+	"PUT /api/v2/users/:id",
 	"echo_route_not_found /api/v2",
 	"echo_route_not_found /api/v2/*",
 	"echo_route_not_found /api/v2/alerts",
@@ -257,12 +297,16 @@ var goldenRoutes = []string{
 	"echo_route_not_found /api/v2/analytics/time/*",
 	"echo_route_not_found /api/v2/auth",
 	"echo_route_not_found /api/v2/auth/*",
+	"echo_route_not_found /api/v2/auth/keys",
+	"echo_route_not_found /api/v2/auth/keys/*",
 	"echo_route_not_found /api/v2/control",
 	"echo_route_not_found /api/v2/control/*",
 	"echo_route_not_found /api/v2/detections",
 	"echo_route_not_found /api/v2/detections/*",
 	"echo_route_not_found /api/v2/detections/batch",
 	"echo_route_not_found /api/v2/detections/batch/*",
+	"echo_route_not_found /api/v2/exports",
+	"echo_route_not_found /api/v2/exports/*",
 	"echo_route_not_found /api/v2/filesystem",
 	"echo_route_not_found /api/v2/filesystem/*",
 	"echo_route_not_found /api/v2/import",
@@ -281,8 +325,12 @@ var goldenRoutes = []string{
 	"echo_route_not_found /api/v2/integrations/weather/*",
 	"echo_route_not_found /api/v2/notifications",
 	"echo_route_not_found /api/v2/notifications/*",
+	"echo_route_not_found /api/v2/outbox",
+	"echo_route_not_found /api/v2/outbox/*",
 	"echo_route_not_found /api/v2/settings",
 	"echo_route_not_found /api/v2/settings/*",
+	"echo_route_not_found /api/v2/soundscapes",
+	"echo_route_not_found /api/v2/soundscapes/*",
 	"echo_route_not_found /api/v2/streams/hls",
 	"echo_route_not_found /api/v2/streams/hls/*",
 	"echo_route_not_found /api/v2/streams/hls/t",
@@ -293,6 +341,8 @@ var goldenRoutes = []string{
 	"echo_route_not_found /api/v2/system/*",
 	"echo_route_not_found /api/v2/system/audio",
 	"echo_route_not_found /api/v2/system/audio/*",
+	"echo_route_not_found /api/v2/system/backups",
+	"echo_route_not_found /api/v2/system/backups/*",
 	"echo_route_not_found /api/v2/system/database",
 	"echo_route_not_found /api/v2/system/database/*",
 	"echo_route_not_found /api/v2/system/database/backup/jobs",
@@ -309,6 +359,8 @@ var goldenRoutes = []string{
 	"echo_route_not_found /api/v2/terminal/*",
 	"echo_route_not_found /api/v2/tls",
 	"echo_route_not_found /api/v2/tls/*",
+	"echo_route_not_found /api/v2/users",
+	"echo_route_not_found /api/v2/users/*",
 	"echo_route_not_found /api/v2/weather",
 	"echo_route_not_found /api/v2/weather/*",
 }
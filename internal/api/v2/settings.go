@@ -17,6 +17,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
 	"github.com/tphakala/birdnet-go/internal/audiocore/schedule"
 	"github.com/tphakala/birdnet-go/internal/conf"
@@ -25,6 +26,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/notification"
 	"github.com/tphakala/birdnet-go/internal/restart"
+	"github.com/tphakala/birdnet-go/internal/security"
 	"github.com/tphakala/birdnet-go/internal/support"
 	"github.com/tphakala/birdnet-go/internal/telemetry"
 	"gopkg.in/yaml.v3"
@@ -62,8 +64,9 @@ func (c *Controller) initSettingsRoutes() {
 	// static path before the auth-protected `/:section` parameter route.
 	c.Group.GET("/settings/dashboard", c.GetDashboardSettings)
 
-	// Create auth-protected settings API group for everything else.
-	settingsGroup := c.Group.Group("/settings", c.AuthMiddleware)
+	// Create auth-protected settings API group for everything else. API keys
+	// need the settings scope.
+	settingsGroup := c.Group.Group("/settings", c.RequireScope(security.ScopeSettings))
 
 	// Routes for settings
 	// GET /api/v2/settings - Retrieves all application settings
@@ -266,6 +269,7 @@ func (c *Controller) UpdateSettings(ctx echo.Context) error {
 	// the per-detection filter and the detection-card toggle match. Idempotent for
 	// an already-canonical list, so this does not spuriously trigger a rebuild.
 	updated.Realtime.Species.Exclude = c.canonicalizeExcludeList(updated.Realtime.Species.Exclude)
+	c.normalizeSourceSpecies(updated)
 
 	// Ensure LocationConfigured is set when birdnet coordinates are present.
 	// Backward compatibility with older frontends that don't send the flag.
@@ -276,6 +280,10 @@ func (c *Controller) UpdateSettings(ctx echo.Context) error {
 	// Migrate legacy single audio source if a cached frontend sent it.
 	updated.MigrateAudioSourceConfig()
 
+	if auth.IsAPIKeyRequest(ctx) && apiKeyProtectedSettingsChanged(current, updated) {
+		return c.HandleError(ctx, fmt.Errorf("settings change not permitted for API keys"), "API keys cannot change security settings or alert scripts", http.StatusForbidden)
+	}
+
 	// Validate the clone before publishing. No rollback needed on validation
 	// failure: we simply never publish.
 	if err := conf.ValidateSettings(updated); err != nil {
@@ -615,6 +623,15 @@ func (c *Controller) getSettingsOrFallback() *conf.Settings {
 	return conf.Setting()
 }
 
+// apiKeyProtectedSettingsChanged reports whether an update touches settings
+// that an API key must not change even with the settings scope: the Security
+// section, which would let a key widen its own access, and the alert scripts,
+// which run commands on the host.
+func apiKeyProtectedSettingsChanged(current, updated *conf.Settings) bool {
+	return !reflect.DeepEqual(current.Security, updated.Security) ||
+		!reflect.DeepEqual(current.Alerting.Scripts, updated.Alerting.Scripts)
+}
+
 // parseAndValidateJSON binds and validates the request body as JSON.
 func parseAndValidateJSON(ctx echo.Context) (json.RawMessage, error) {
 	var requestBody json.RawMessage
@@ -698,6 +715,14 @@ func (c *Controller) UpdateSectionSettings(ctx echo.Context) error {
 	if strings.EqualFold(section, SettingsSectionRealtime) || strings.EqualFold(section, SettingsSectionSpecies) {
 		updated.Realtime.Species.Exclude = c.canonicalizeExcludeList(updated.Realtime.Species.Exclude)
 	}
+	// Per-source species overrides live with the audio sources and streams.
+	if strings.EqualFold(section, SettingsSectionRealtime) || strings.EqualFold(section, SettingsSectionAudio) || strings.EqualFold(section, "rtsp") {
+		c.normalizeSourceSpecies(updated)
+	}
+
+	if auth.IsAPIKeyRequest(ctx) && apiKeyProtectedSettingsChanged(current, updated) {
+		return c.HandleError(ctx, fmt.Errorf("settings change not permitted for API keys"), "API keys cannot change security settings or alert scripts", http.StatusForbidden)
+	}
 
 	// Validate the clone before publishing. No rollback needed on validation
 	// failure: we simply never publish.
@@ -1597,6 +1622,18 @@ func validateAlertingSection(data json.RawMessage) error {
 		return err
 	}
 
+	// Scripts run commands on the host, so they are only configurable in
+	// config.yaml (see getBlockedFieldMap).
+	var updateMap map[string]json.RawMessage
+	if err := json.Unmarshal(data, &updateMap); err != nil {
+		return err
+	}
+	for key := range updateMap {
+		if strings.EqualFold(key, "scripts") {
+			return fmt.Errorf("alert scripts can only be configured in config.yaml")
+		}
+	}
+
 	if alertSettings.HistoryRetentionDays < 0 {
 		return fmt.Errorf("historyRetentionDays must be non-negative, got %d", alertSettings.HistoryRetentionDays)
 	}
@@ -1769,10 +1806,17 @@ func sanitizeSettingsForAPI(s *conf.Settings) *conf.Settings {
 
 	// --- Database ---
 	sanitized.Output.MySQL.Password = redact(s.Output.MySQL.Password)
+	sanitized.Output.Postgres.Password = redact(s.Output.Postgres.Password)
+
+	// --- Tiered clip storage ---
+	sanitized.Realtime.Audio.Export.Retention.Tier.S3.SecretAccessKey = redact(s.Realtime.Audio.Export.Retention.Tier.S3.SecretAccessKey)
+	sanitized.Realtime.Audio.Export.Retention.Tier.SFTP.Password = redact(s.Realtime.Audio.Export.Retention.Tier.SFTP.Password)
 
 	// --- Weather API keys ---
 	sanitized.Realtime.Weather.OpenWeather.APIKey = redact(s.Realtime.Weather.OpenWeather.APIKey)
 	sanitized.Realtime.Weather.Wunderground.APIKey = redact(s.Realtime.Weather.Wunderground.APIKey)
+	sanitized.Realtime.Weather.Station.PassKey = redact(s.Realtime.Weather.Station.PassKey)
+	sanitized.Realtime.Weather.Station.MQTT.Password = redact(s.Realtime.Weather.Station.MQTT.Password)
 
 	// --- eBird API key ---
 	sanitized.Realtime.EBird.APIKey = redact(s.Realtime.EBird.APIKey)
@@ -1881,9 +1925,18 @@ func restoreRedactedSecrets(current, incoming *conf.Settings) error {
 	// MySQL
 	restore(&current.Output.MySQL.Password, &incoming.Output.MySQL.Password)
 
+	// PostgreSQL
+	restore(&current.Output.Postgres.Password, &incoming.Output.Postgres.Password)
+
+	// Tiered clip storage
+	restore(&current.Realtime.Audio.Export.Retention.Tier.S3.SecretAccessKey, &incoming.Realtime.Audio.Export.Retention.Tier.S3.SecretAccessKey)
+	restore(&current.Realtime.Audio.Export.Retention.Tier.SFTP.Password, &incoming.Realtime.Audio.Export.Retention.Tier.SFTP.Password)
+
 	// Weather API keys
 	restore(&current.Realtime.Weather.OpenWeather.APIKey, &incoming.Realtime.Weather.OpenWeather.APIKey)
 	restore(&current.Realtime.Weather.Wunderground.APIKey, &incoming.Realtime.Weather.Wunderground.APIKey)
+	restore(&current.Realtime.Weather.Station.PassKey, &incoming.Realtime.Weather.Station.PassKey)
+	restore(&current.Realtime.Weather.Station.MQTT.Password, &incoming.Realtime.Weather.Station.MQTT.Password)
 
 	// eBird
 	restore(&current.Realtime.EBird.APIKey, &incoming.Realtime.EBird.APIKey)
@@ -1975,8 +2028,13 @@ func validateNoRedactedSentinels(s *conf.Settings) error {
 	check(s.Security.MicrosoftAuth.ClientSecret, "security.microsoftAuth.clientSecret")
 	check(s.Realtime.MQTT.Password, "realtime.mqtt.password")
 	check(s.Output.MySQL.Password, "output.mysql.password")
+	check(s.Output.Postgres.Password, "output.postgres.password")
+	check(s.Realtime.Audio.Export.Retention.Tier.S3.SecretAccessKey, "realtime.audio.export.retention.tier.s3.secretAccessKey")
+	check(s.Realtime.Audio.Export.Retention.Tier.SFTP.Password, "realtime.audio.export.retention.tier.sftp.password")
 	check(s.Realtime.Weather.OpenWeather.APIKey, "realtime.weather.openWeather.apiKey")
 	check(s.Realtime.Weather.Wunderground.APIKey, "realtime.weather.wunderground.apiKey")
+	check(s.Realtime.Weather.Station.PassKey, "realtime.weather.station.passKey")
+	check(s.Realtime.Weather.Station.MQTT.Password, "realtime.weather.station.mqtt.password")
 	check(s.Realtime.EBird.APIKey, "realtime.ebird.apiKey")
 	check(s.Backup.EncryptionKey, "backup.encryptionKey")
 
@@ -2042,8 +2100,13 @@ func clearRedactedSentinels(s *conf.Settings) {
 	clearField(&s.Security.MicrosoftAuth.ClientSecret)
 	clearField(&s.Realtime.MQTT.Password)
 	clearField(&s.Output.MySQL.Password)
+	clearField(&s.Output.Postgres.Password)
+	clearField(&s.Realtime.Audio.Export.Retention.Tier.S3.SecretAccessKey)
+	clearField(&s.Realtime.Audio.Export.Retention.Tier.SFTP.Password)
 	clearField(&s.Realtime.Weather.OpenWeather.APIKey)
 	clearField(&s.Realtime.Weather.Wunderground.APIKey)
+	clearField(&s.Realtime.Weather.Station.PassKey)
+	clearField(&s.Realtime.Weather.Station.MQTT.Password)
 	clearField(&s.Realtime.EBird.APIKey)
 	clearField(&s.Backup.EncryptionKey)
 
@@ -2129,6 +2192,12 @@ func getBlockedFieldMap() map[string]any {
 			"Audio": getAudioBlockedFields(),
 		},
 
+		// Alerting section - scripts run host commands and are configured in
+		// config.yaml only
+		"Alerting": map[string]any{
+			"Scripts": true,
+		},
+
 		// All other fields are allowed by default
 	}
 }
@@ -2369,6 +2438,11 @@ func rangeFilterSettingsChanged(oldSettings, currentSettings *conf.Settings) boo
 		return true
 	}
 
+	// Per-source deployment coordinates get their own species lists
+	if !maps.Equal(oldSettings.DeploymentLocations(), currentSettings.DeploymentLocations()) {
+		return true
+	}
+
 	return false
 }
 
@@ -2384,6 +2458,7 @@ func mqttSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		oldMQTT.Username != newMQTT.Username ||
 		oldMQTT.Password != newMQTT.Password ||
 		oldMQTT.Retain != newMQTT.Retain ||
+		oldMQTT.Commands != newMQTT.Commands ||
 		oldMQTT.TLS.InsecureSkipVerify != newMQTT.TLS.InsecureSkipVerify ||
 		oldMQTT.TLS.CACert != newMQTT.TLS.CACert ||
 		oldMQTT.TLS.ClientCert != newMQTT.TLS.ClientCert ||
@@ -2416,7 +2491,7 @@ func streamsSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 	}
 
 	// Check for changes in individual streams (name, URL, type, transport, channel
-	// mode, media mode, or models)
+	// mode, media mode, models, or soundscape recording)
 	for i := range oldRTSP.Streams {
 		if i >= len(newRTSP.Streams) {
 			return true
@@ -2430,7 +2505,8 @@ func streamsSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 			oldStream.Transport != newStream.Transport ||
 			oldStream.ChannelMode.Canonical() != newStream.ChannelMode.Canonical() ||
 			oldStream.MediaMode.Canonical() != newStream.MediaMode.Canonical() ||
-			!slices.Equal(oldStream.Models, newStream.Models) {
+			!slices.Equal(oldStream.Models, newStream.Models) ||
+			oldStream.Soundscape != newStream.Soundscape {
 			return true
 		}
 	}
@@ -2667,6 +2743,16 @@ func outputSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		return true
 	}
 
+	if oldOut.Postgres.Enabled != newOut.Postgres.Enabled ||
+		oldOut.Postgres.Username != newOut.Postgres.Username ||
+		oldOut.Postgres.Password != newOut.Postgres.Password ||
+		oldOut.Postgres.Database != newOut.Postgres.Database ||
+		oldOut.Postgres.Host != newOut.Postgres.Host ||
+		oldOut.Postgres.Port != newOut.Postgres.Port ||
+		oldOut.Postgres.SSLMode != newOut.Postgres.SSLMode {
+		return true
+	}
+
 	return false
 }
 
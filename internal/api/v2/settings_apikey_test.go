@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// apiKeyContext builds a request context that the auth middleware would have
+// marked as authenticated with an API key.
+func apiKeyContext(e *echo.Echo, method, path string, payload any) (echo.Context, *httptest.ResponseRecorder, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.Set(auth.CtxKeyIsAuthenticated, true)
+	ctx.Set(auth.CtxKeyAuthMethod, auth.AuthMethodAPIKey)
+	return ctx, rec, nil
+}
+
+// TestUpdateSectionSettings_APIKeyProtectedSettings verifies that an API key
+// with the settings scope cannot change the Security section or the alert
+// scripts, while other sections remain writable.
+func TestUpdateSectionSettings_APIKeyProtectedSettings(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	before := conf.CloneSettings(controller.Settings.Load())
+
+	patch := func(section string, payload any) int {
+		t.Helper()
+		ctx, rec, err := apiKeyContext(e, http.MethodPatch, "/api/v2/settings/"+section, payload)
+		require.NoError(t, err)
+		ctx.SetParamNames("section")
+		ctx.SetParamValues(section)
+		require.NoError(t, controller.UpdateSectionSettings(ctx))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, patch("security", map[string]any{"privateMode": !before.Security.PrivateMode}))
+	assert.NotEqual(t, http.StatusOK, patch("alerting", map[string]any{
+		"scripts": []map[string]any{{"name": "pwn", "command": "/bin/sh"}},
+	}))
+
+	after := controller.Settings.Load()
+	assert.Equal(t, before.Security, after.Security)
+	assert.Empty(t, after.Alerting.Scripts)
+
+	assert.Equal(t, http.StatusOK, patch("alerting", map[string]any{"historyRetentionDays": 45}))
+	assert.Equal(t, 45, controller.Settings.Load().Alerting.HistoryRetentionDays)
+}
+
+// TestUpdateSettings_APIKeyProtectedSettings verifies the full-settings PUT
+// path applies the same restriction.
+func TestUpdateSettings_APIKeyProtectedSettings(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	before := conf.CloneSettings(controller.Settings.Load())
+
+	s := conf.CloneSettings(before)
+	s.Security.PrivateMode = !before.Security.PrivateMode
+	ctx, rec, err := apiKeyContext(e, http.MethodPut, "/api/v2/settings", s)
+	require.NoError(t, err)
+	require.NoError(t, controller.UpdateSettings(ctx))
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, before.Security.PrivateMode, controller.Settings.Load().Security.PrivateMode)
+}
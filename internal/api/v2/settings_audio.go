@@ -19,8 +19,8 @@ type sourceNameUpdater interface {
 }
 
 // audioDeviceSettingChanged checks if audio device pipeline settings have changed.
-// Only compares device-affecting fields (Device, Gain, Model, Models,
-// SampleRate), not display-only fields (Name, Equalizer, QuietHours) which
+// Only compares device-affecting fields (Device, Channels, Gain, Model, Models,
+// SampleRate, Soundscape), not display-only fields (Name, Equalizer, QuietHours) which
 // are handled separately.
 //
 // Models is the per-source list of classifier IDs (e.g. ["birdnet",
@@ -35,10 +35,12 @@ func audioDeviceSettingChanged(oldSettings, currentSettings *conf.Settings) bool
 	}
 	for i := range oldSources {
 		if oldSources[i].Device != newSources[i].Device ||
+			!slices.Equal(oldSources[i].Channels, newSources[i].Channels) ||
 			oldSources[i].Gain != newSources[i].Gain ||
 			oldSources[i].Model != newSources[i].Model ||
 			!slices.Equal(oldSources[i].Models, newSources[i].Models) ||
-			oldSources[i].SampleRate != newSources[i].SampleRate {
+			oldSources[i].SampleRate != newSources[i].SampleRate ||
+			oldSources[i].Soundscape != newSources[i].Soundscape {
 			return true
 		}
 	}
@@ -46,15 +48,15 @@ func audioDeviceSettingChanged(oldSettings, currentSettings *conf.Settings) bool
 }
 
 // syncAudioSourceNames detects audio sources that were renamed while keeping
-// the same device, and updates their DisplayName in the registry. Returns true
-// if any name was changed. Uses a map keyed by Device so renames are detected
-// even if the source list was reordered.
+// the same device and channels, and updates their DisplayName in the registry.
+// Returns true if any name was changed. Uses a map keyed by connection string
+// so renames are detected even if the source list was reordered.
 func syncAudioSourceNames(oldSettings, currentSettings *conf.Settings, registry sourceNameUpdater) bool {
 	sources := oldSettings.Realtime.Audio.Sources
 	oldNames := make(map[string]string, len(sources))
 	for i := range sources {
 		if sources[i].Device != "" {
-			oldNames[sources[i].Device] = sources[i].Name
+			oldNames[sources[i].ConnectionString()] = sources[i].Name
 		}
 	}
 
@@ -64,10 +66,11 @@ func syncAudioSourceNames(oldSettings, currentSettings *conf.Settings, registry
 		if newSources[i].Device == "" {
 			continue
 		}
-		if oldName, ok := oldNames[newSources[i].Device]; ok && oldName != newSources[i].Name {
+		conn := newSources[i].ConnectionString()
+		if oldName, ok := oldNames[conn]; ok && oldName != newSources[i].Name {
 			changed = true
 			if registry != nil {
-				if src, ok := registry.GetByConnection(newSources[i].Device); ok {
+				if src, ok := registry.GetByConnection(conn); ok {
 					registry.UpdateDisplayName(src.ID, newSources[i].Name)
 				}
 			}
@@ -185,6 +185,22 @@ func TestSyncAudioSourceNames(t *testing.T) {
 			"should detect rename even when sources are reordered")
 	})
 
+	t.Run("reordered channels of a shared device", func(t *testing.T) {
+		t.Parallel()
+		old := &conf.Settings{}
+		old.Realtime.Audio.Sources = []conf.AudioSourceConfig{
+			{Name: "Pond", Device: "hw:1,0", Channels: []int{1}},
+			{Name: "Forest", Device: "hw:1,0", Channels: []int{2}},
+		}
+		cur := &conf.Settings{}
+		cur.Realtime.Audio.Sources = []conf.AudioSourceConfig{
+			{Name: "Forest", Device: "hw:1,0", Channels: []int{2}},
+			{Name: "Pond", Device: "hw:1,0", Channels: []int{1}},
+		}
+		assert.False(t, syncAudioSourceNames(old, cur, nil),
+			"sources on one device are told apart by their channels")
+	})
+
 	t.Run("both empty", func(t *testing.T) {
 		t.Parallel()
 		old := &conf.Settings{}
@@ -223,3 +223,27 @@ func TestUpdateSectionSettingsSkipsExcludeForUnrelatedSection(t *testing.T) {
 	assert.Equal(t, []string{testExcludeLocalizedName}, got,
 		"unrelated section save must leave the legacy exclude entry untouched")
 }
+
+// TestUpdateSectionSettingsNormalizesSourceSpecies verifies that per-stream
+// species overrides get the same treatment as the global species settings:
+// a canonical exclude list and lowercase config keys.
+func TestUpdateSectionSettingsNormalizesSourceSpecies(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	installExcludeTestResolver(t, controller)
+
+	patchSection(t, e, controller, "rtsp", map[string]any{
+		"streams": []map[string]any{{
+			"name": "Roadside", "url": "rtsp://roadside.local/stream", "type": "rtsp", "transport": "tcp",
+			"species": map[string]any{
+				"exclude": []string{testExcludeLocalizedName},
+				"config":  map[string]any{"Eurasian Siskin": map[string]any{"threshold": 0.3}},
+			},
+		}},
+	})
+
+	streams := controller.Settings.Load().Realtime.RTSP.Streams
+	require.Len(t, streams, 1)
+	require.NotNil(t, streams[0].Species)
+	assert.Equal(t, []string{testExcludeScientificName}, streams[0].Species.Exclude)
+	assert.Contains(t, streams[0].Species.Config, "eurasian siskin")
+}
@@ -46,9 +46,23 @@ func settingsWithSecrets(t *testing.T) *conf.Settings {
 	s.Output.MySQL.Password = "db-password"
 	s.Output.MySQL.Host = "db.local"
 
+	// PostgreSQL
+	s.Output.Postgres.Username = "pguser"
+	s.Output.Postgres.Password = "pg-password"
+	s.Output.Postgres.Host = "pg.local"
+
+	// Tiered clip storage
+	s.Realtime.Audio.Export.Retention.Tier.S3.AccessKeyID = "s3-access-key"
+	s.Realtime.Audio.Export.Retention.Tier.S3.SecretAccessKey = "s3-secret-key"
+	s.Realtime.Audio.Export.Retention.Tier.SFTP.Username = "sftp-user"
+	s.Realtime.Audio.Export.Retention.Tier.SFTP.Password = "sftp-password"
+
 	// Weather API keys
 	s.Realtime.Weather.OpenWeather.APIKey = "ow-api-key-123"
 	s.Realtime.Weather.Wunderground.APIKey = "wu-api-key-456"
+	s.Realtime.Weather.Station.PassKey = "station-passkey"
+	s.Realtime.Weather.Station.MQTT.Username = "weewx"
+	s.Realtime.Weather.Station.MQTT.Password = "station-mqtt-password"
 
 	// eBird
 	s.Realtime.EBird.APIKey = "ebird-api-key-789"
@@ -138,9 +152,23 @@ func TestSanitizeSettingsForAPI_RedactsAllSecrets(t *testing.T) {
 	assert.Equal(t, redactedValue, sanitized.Output.MySQL.Password, "mysql.password must be redacted")
 	assert.Equal(t, "dbuser", sanitized.Output.MySQL.Username, "mysql.username should be preserved")
 
+	// --- PostgreSQL ---
+	assert.Equal(t, redactedValue, sanitized.Output.Postgres.Password, "postgres.password must be redacted")
+	assert.Equal(t, "pguser", sanitized.Output.Postgres.Username, "postgres.username should be preserved")
+
+	// --- Tiered clip storage ---
+	tier := sanitized.Realtime.Audio.Export.Retention.Tier
+	assert.Equal(t, redactedValue, tier.S3.SecretAccessKey, "tier.s3.secretAccessKey must be redacted")
+	assert.Equal(t, "s3-access-key", tier.S3.AccessKeyID, "tier.s3.accessKeyId should be preserved")
+	assert.Equal(t, redactedValue, tier.SFTP.Password, "tier.sftp.password must be redacted")
+	assert.Equal(t, "sftp-user", tier.SFTP.Username, "tier.sftp.username should be preserved")
+
 	// --- Weather API keys ---
 	assert.Equal(t, redactedValue, sanitized.Realtime.Weather.OpenWeather.APIKey, "openWeather.apiKey must be redacted")
 	assert.Equal(t, redactedValue, sanitized.Realtime.Weather.Wunderground.APIKey, "wunderground.apiKey must be redacted")
+	assert.Equal(t, redactedValue, sanitized.Realtime.Weather.Station.PassKey, "station.passKey must be redacted")
+	assert.Equal(t, redactedValue, sanitized.Realtime.Weather.Station.MQTT.Password, "station.mqtt.password must be redacted")
+	assert.Equal(t, "weewx", sanitized.Realtime.Weather.Station.MQTT.Username, "station.mqtt.username should be preserved")
 
 	// --- eBird ---
 	assert.Equal(t, redactedValue, sanitized.Realtime.EBird.APIKey, "ebird.apiKey must be redacted")
@@ -272,6 +300,9 @@ func TestRestoreRedactedSecrets_PreservesRealValues(t *testing.T) {
 	incoming.Security.GoogleAuth.ClientSecret = redactedValue
 	incoming.Realtime.MQTT.Password = redactedValue
 	incoming.Output.MySQL.Password = redactedValue
+	incoming.Output.Postgres.Password = redactedValue
+	incoming.Realtime.Audio.Export.Retention.Tier.S3.SecretAccessKey = redactedValue
+	incoming.Realtime.Audio.Export.Retention.Tier.SFTP.Password = redactedValue
 	incoming.Realtime.Weather.OpenWeather.APIKey = redactedValue
 	incoming.Realtime.EBird.APIKey = redactedValue
 	incoming.Backup.EncryptionKey = redactedValue
@@ -287,6 +318,9 @@ func TestRestoreRedactedSecrets_PreservesRealValues(t *testing.T) {
 	assert.Equal(t, "google-secret", incoming.Security.GoogleAuth.ClientSecret)
 	assert.Equal(t, "mqtt-password", incoming.Realtime.MQTT.Password)
 	assert.Equal(t, "db-password", incoming.Output.MySQL.Password)
+	assert.Equal(t, "pg-password", incoming.Output.Postgres.Password)
+	assert.Equal(t, "s3-secret-key", incoming.Realtime.Audio.Export.Retention.Tier.S3.SecretAccessKey)
+	assert.Equal(t, "sftp-password", incoming.Realtime.Audio.Export.Retention.Tier.SFTP.Password)
 	assert.Equal(t, "ow-api-key-123", incoming.Realtime.Weather.OpenWeather.APIKey)
 	assert.Equal(t, "ebird-api-key-789", incoming.Realtime.EBird.APIKey)
 	assert.Equal(t, "base64-encryption-key", incoming.Backup.EncryptionKey)
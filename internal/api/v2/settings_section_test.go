@@ -195,6 +195,45 @@ func TestPatchAlertingValidation(t *testing.T) {
 	assert.Contains(t, response["error"], "historyRetentionDays must be non-negative")
 }
 
+// TestAlertScriptsNotWritableViaAPI verifies that the alert scripts, which
+// run commands on the host, cannot be changed through either settings update
+// path.
+func TestAlertScriptsNotWritableViaAPI(t *testing.T) {
+	e := echo.New()
+	controller := getTestController(t, e)
+	controller.Settings.Load().WebServer.Debug = true
+	configured := []conf.AlertScript{{Name: "notify", Command: "/usr/local/bin/notify", Timeout: 10}}
+	controller.Settings.Load().Alerting.Scripts = configured
+
+	body, err := json.Marshal(map[string]any{
+		"scripts": []map[string]any{{"name": "pwn", "command": "/bin/sh"}},
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPatch, "/api/v2/settings/alerting", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("section")
+	ctx.SetParamValues("alerting")
+
+	require.NoError(t, controller.UpdateSectionSettings(ctx))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "config.yaml")
+	assert.Equal(t, configured, controller.Settings.Load().Alerting.Scripts)
+
+	full := conf.CloneSettings(controller.Settings.Load())
+	full.Alerting.Scripts = []conf.AlertScript{{Name: "pwn", Command: "/bin/sh"}}
+	body, err = json.Marshal(full)
+	require.NoError(t, err)
+	req = httptest.NewRequest(http.MethodPut, "/api/v2/settings", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+
+	require.NoError(t, controller.UpdateSettings(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, configured, controller.Settings.Load().Alerting.Scripts, "PUT must skip the blocked scripts field")
+}
+
 // TestPatchAlertingZeroRetention verifies that zero is accepted as a valid
 // value for historyRetentionDays (meaning unlimited retention).
 func TestPatchAlertingZeroRetention(t *testing.T) {
@@ -0,0 +1,191 @@
+// Package soundscapes is the api/v2 continuous soundscape domain. It owns the
+// /api/v2/soundscapes/* endpoints that list the recorded soundscape segments
+// per source and time range and stream them for playback in the web UI.
+//
+// The Handler embeds *apicore.Core by pointer so the shared dependencies and
+// helpers (settings, V2Manager, the role middleware, HandleError and the
+// logging helpers) promote onto it. Segments are indexed in the v2 database,
+// so like the exports domain every route answers 409 Conflict while the
+// enhanced database is unavailable. Recording and retention are run by the
+// audio pipeline; this domain only reads.
+package soundscapes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
+	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/securefs"
+	"github.com/tphakala/birdnet-go/internal/security"
+)
+
+const (
+	// defaultListLimit and maxListLimit bound the segment listing. A day of
+	// five minute segments is 288 entries.
+	defaultListLimit = 300
+	maxListLimit     = 2000
+)
+
+// Handler serves the soundscape endpoints. repo is nil until RegisterRoutes
+// builds it, which only happens when the enhanced v2 database is active.
+type Handler struct {
+	*apicore.Core
+
+	repo repository.SoundscapeRepository
+}
+
+// New builds a soundscapes Handler around the shared core.
+func New(core *apicore.Core) *Handler {
+	return &Handler{Core: core}
+}
+
+// RegisterRoutes registers the soundscape endpoints. Listening to recorded
+// audio is a viewer action, like playing detection clips.
+func (c *Handler) RegisterRoutes(g *echo.Group) {
+	routes := g.Group("/soundscapes", c.RequireRole(security.RoleViewer), c.requireV2Middleware)
+	routes.GET("", c.ListSoundscapeSegments)
+	routes.GET("/sources", c.ListSoundscapeSources)
+	routes.GET("/:id/audio", c.ServeSoundscapeAudio)
+
+	if c.repo == nil && c.V2Manager != nil && datastoreV2.IsEnhancedDatabase() {
+		c.repo = repository.NewSoundscapeRepository(c.V2Manager.DB(), nil)
+	}
+}
+
+// requireV2Middleware answers 409 Conflict when the segment index is
+// unavailable.
+func (c *Handler) requireV2Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if c.repo == nil {
+			return c.HandleError(ctx, nil, "Soundscape recordings require the enhanced (v2) database", http.StatusConflict)
+		}
+		return next(ctx)
+	}
+}
+
+// ListSoundscapeSegments handles GET /api/v2/soundscapes. The source_id query
+// parameter selects one source; start and end bound the segment start times
+// and take RFC 3339 timestamps or YYYY-MM-DD dates, where an end date
+// includes that whole day. limit and offset page through the segments,
+// newest first.
+func (c *Handler) ListSoundscapeSegments(ctx echo.Context) error {
+	start, err := parseTime(ctx.QueryParam("start"), false)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid start time", http.StatusBadRequest)
+	}
+	end, err := parseTime(ctx.QueryParam("end"), true)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid end time", http.StatusBadRequest)
+	}
+	if !start.IsZero() && !end.IsZero() && !end.After(start) {
+		return c.HandleError(ctx, nil, "End time must be after start time", http.StatusBadRequest)
+	}
+	limit, err := parseNonNegative(ctx.QueryParam("limit"), defaultListLimit)
+	if err != nil || limit == 0 {
+		return c.HandleError(ctx, err, "Invalid limit", http.StatusBadRequest)
+	}
+	offset, err := parseNonNegative(ctx.QueryParam("offset"), 0)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid offset", http.StatusBadRequest)
+	}
+
+	segments, total, err := c.repo.List(ctx.Request().Context(), repository.SoundscapeFilter{
+		SourceID: ctx.QueryParam("source_id"),
+		Start:    start,
+		End:      end,
+		Limit:    min(limit, maxListLimit),
+		Offset:   offset,
+	})
+	if err != nil {
+		c.LogErrorIfEnabled("failed to list soundscape segments", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to list soundscape segments", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"segments": segments,
+		"count":    len(segments),
+		"total":    total,
+	})
+}
+
+// ListSoundscapeSources handles GET /api/v2/soundscapes/sources. It returns
+// the number, size and time span of the recorded segments per source.
+func (c *Handler) ListSoundscapeSources(ctx echo.Context) error {
+	sources, err := c.repo.Sources(ctx.Request().Context())
+	if err != nil {
+		c.LogErrorIfEnabled("failed to summarize soundscape sources", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to list soundscape sources", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{"sources": sources})
+}
+
+// ServeSoundscapeAudio handles GET /api/v2/soundscapes/:id/audio. The file is
+// served with range support so the player can seek within long segments, and
+// resolved inside the configured soundscape directory.
+func (c *Handler) ServeSoundscapeAudio(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid segment ID", http.StatusBadRequest)
+	}
+	segment, err := c.repo.GetByID(ctx.Request().Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, repository.ErrSoundscapeSegmentNotFound) {
+			return c.HandleError(ctx, err, "Soundscape segment not found", http.StatusNotFound)
+		}
+		c.LogErrorIfEnabled("failed to read soundscape segment", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to read soundscape segment", http.StatusInternalServerError)
+	}
+
+	sfs, err := securefs.New(c.CurrentSettings().Realtime.Audio.Soundscape.Path)
+	if err != nil {
+		return c.HandleError(ctx, err, "Soundscape directory is not accessible", http.StatusInternalServerError)
+	}
+	defer func() { _ = sfs.Close() }()
+	return sfs.ServeRelativeFile(ctx, segment.FilePath)
+}
+
+// parseTime parses an optional RFC 3339 timestamp or YYYY-MM-DD date in local
+// time. With endOfDay a date resolves to the start of the following day, so
+// an exclusive end bound includes the whole day.
+func parseTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, errors.Newf("invalid time %q, expected RFC 3339 or YYYY-MM-DD", value).
+			Component("api").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+	if endOfDay {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, nil
+}
+
+// parseNonNegative parses an optional non-negative integer query parameter.
+func parseNonNegative(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, errors.Newf("value must not be negative").
+			Component("api").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+	return n, nil
+}
@@ -0,0 +1,143 @@
+package soundscapes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+
+	"github.com/tphakala/birdnet-go/internal/api/v2/apitest"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/security"
+)
+
+// passthroughMiddleware stands in for the auth middleware.
+func passthroughMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
+
+// setupSoundscapeHandler registers the soundscape routes on a fresh Echo.
+// With withRepo the index is backed by a temporary SQLite database and the
+// soundscape path points at dir.
+func setupSoundscapeHandler(t *testing.T, withRepo bool, dir string) (*echo.Echo, repository.SoundscapeRepository) {
+	t.Helper()
+	e := echo.New()
+	core := apitest.NewCore(t, apitest.WithEcho(e), apitest.WithSettingsFunc(func(s *conf.Settings) {
+		s.Realtime.Audio.Soundscape.Path = dir
+	}))
+	core.AuthMiddleware = passthroughMiddleware
+	core.RoleMiddleware = func(security.Role) echo.MiddlewareFunc { return passthroughMiddleware }
+
+	h := New(core)
+	if withRepo {
+		db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "soundscapes.db")), &gorm.Config{
+			Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+		})
+		require.NoError(t, err)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+		require.NoError(t, db.AutoMigrate(&entities.SoundscapeSegment{}))
+		h.repo = repository.NewSoundscapeRepository(db, nil)
+	}
+	h.RegisterRoutes(core.Group)
+	return e, h.repo
+}
+
+func do(t *testing.T, e *echo.Echo, path string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSoundscapeRoutesReturn409WithoutV2(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, _ := setupSoundscapeHandler(t, false, t.TempDir())
+	rec := do(t, e, "/api/v2/soundscapes", nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestSoundscapeListAndPlayback(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	dir := t.TempDir()
+	e, repo := setupSoundscapeHandler(t, true, dir)
+	ctx := t.Context()
+
+	start := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	var segments []*entities.SoundscapeSegment
+	for i, source := range []string{"rtsp_a", "rtsp_a", "rtsp_b"} {
+		rel := source + "/2026/05/01/" + source + "_" + strconv.Itoa(i) + ".flac"
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, source, "2026", "05", "01"), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(rel)), []byte("0123456789"), 0o600))
+		seg := &entities.SoundscapeSegment{
+			SourceID:   source,
+			SourceName: source,
+			StartTime:  start.Add(time.Duration(i) * 5 * time.Minute),
+			Duration:   300,
+			FilePath:   rel,
+			Format:     conf.SoundscapeFormatFLAC,
+			SampleRate: 48000,
+			FileSize:   10,
+		}
+		require.NoError(t, repo.Create(ctx, seg))
+		segments = append(segments, seg)
+	}
+
+	rec := do(t, e, "/api/v2/soundscapes?source_id=rtsp_a&start=2026-05-01T06:00:00Z&end=2026-05-01T06:10:00Z&limit=1", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var listed struct {
+		Segments []entities.SoundscapeSegment `json:"segments"`
+		Count    int                          `json:"count"`
+		Total    int64                        `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	assert.Equal(t, 1, listed.Count)
+	assert.Equal(t, int64(2), listed.Total)
+	assert.Equal(t, segments[1].ID, listed.Segments[0].ID, "newest first")
+
+	rec = do(t, e, "/api/v2/soundscapes/sources", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"source_id":"rtsp_b"`)
+
+	audioPath := "/api/v2/soundscapes/" + strconv.FormatUint(uint64(segments[0].ID), 10) + "/audio"
+	rec = do(t, e, audioPath, http.Header{"Range": {"bytes=2-5"}})
+	require.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "2345", rec.Body.String())
+
+	rec = do(t, e, "/api/v2/soundscapes/9999/audio", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestSoundscapeRoutesValidateQuery(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e, _ := setupSoundscapeHandler(t, true, t.TempDir())
+
+	for _, path := range []string{
+		"/api/v2/soundscapes?start=yesterday",
+		"/api/v2/soundscapes?start=2026-05-02&end=2026-05-01",
+		"/api/v2/soundscapes?limit=0",
+		"/api/v2/soundscapes?offset=-1",
+		"/api/v2/soundscapes/abc/audio",
+	} {
+		rec := do(t, e, path, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+	}
+}
@@ -213,8 +213,11 @@ func (c *Handler) GenerateSupportDump(ctx echo.Context) error {
 	// Wire database info provider if V2Manager is available
 	if req.IncludeDatabaseInfo && c.V2Manager != nil && c.DS != nil {
 		dialect := datastore.DialectSQLite
-		if c.V2Manager.IsMySQL() {
+		switch {
+		case c.V2Manager.IsMySQL():
 			dialect = datastore.DialectMySQL
+		case c.V2Manager.IsPostgres():
+			dialect = datastore.DialectPostgres
 		}
 		dbCollector := supportpkg.NewGormDatabaseInfoCollector(
 			c.V2Manager.DB(),
@@ -174,8 +174,11 @@ func (c *Handler) registerHealthChecks() {
 				return false, "", errors.NewStd("datastore unavailable")
 			}
 			dbType := "sqlite"
-			if c.CurrentSettings().Output.MySQL.Enabled {
+			switch output := c.CurrentSettings().Output; {
+			case output.MySQL.Enabled:
 				dbType = "mysql"
+			case output.Postgres.Enabled:
+				dbType = "postgres"
 			}
 			return true, dbType + " (auto-migrated at startup)", nil
 		}),
@@ -1175,8 +1175,11 @@ func (c *Handler) getV2ManagerStats(ctx context.Context) (*datastore.DatabaseSta
 		Connected: true,
 	}
 
-	if mgr.IsMySQL() {
+	switch {
+	case mgr.IsMySQL():
 		stats.Type = datastore.DialectMySQL
+	case mgr.IsPostgres():
+		stats.Type = datastore.DialectPostgres
 	}
 
 	db := mgr.DB()
@@ -1185,11 +1188,24 @@ func (c *Handler) getV2ManagerStats(ctx context.Context) (*datastore.DatabaseSta
 		return stats, true
 	}
 
-	if !mgr.IsMySQL() {
+	switch {
+	case mgr.IsPostgres():
+		// Sum the relations of the current schema whose name carries the v2 prefix
+		// (all of them for a fresh install). LIKE needs the underscore escaped.
+		escaped := strings.ReplaceAll(mgr.TablePrefix(), "_", "\\_")
+		if err := db.WithContext(ctx).Raw(`
+			SELECT COALESCE(SUM(pg_total_relation_size(c.oid)), 0)
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE c.relkind = 'r' AND n.nspname = current_schema() AND c.relname LIKE ?
+		`, escaped+"%").Scan(&stats.SizeBytes).Error; err != nil {
+			c.LogWarnIfEnabled("Failed to get v2 PostgreSQL database size", logger.Error(err))
+		}
+	case !mgr.IsMySQL():
 		if err := db.WithContext(ctx).Raw("SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()").Scan(&stats.SizeBytes).Error; err != nil {
 			c.LogWarnIfEnabled("Failed to get v2 SQLite database size", logger.Error(err))
 		}
-	} else {
+	default:
 		prefix := mgr.TablePrefix()
 		if prefix != "" {
 			// Escape underscore for MySQL LIKE (underscore is single-char wildcard)
@@ -1270,7 +1286,7 @@ func (c *Handler) DownloadDatabaseBackup(ctx echo.Context) error {
 		settings := c.CurrentSettings()
 		if settings == nil || settings.Output.SQLite.Path == "" {
 			return c.HandleError(ctx, fmt.Errorf("not sqlite"),
-				"Backup download only available for SQLite databases. Use MySQL tools for MySQL backup.",
+				"Backup download only available for SQLite databases. Use the database server's tools for MySQL or PostgreSQL backup.",
 				http.StatusBadRequest)
 		}
 		dbPath = settings.Output.SQLite.Path
@@ -1294,9 +1310,9 @@ func (c *Handler) DownloadDatabaseBackup(ctx echo.Context) error {
 			return c.HandleError(ctx, fmt.Errorf("v2 not available"),
 				"V2 database not initialized", http.StatusNotFound)
 		}
-		if mgr.IsMySQL() {
+		if mgr.IsMySQL() || mgr.IsPostgres() {
 			return c.HandleError(ctx, fmt.Errorf("not sqlite"),
-				"Backup download only available for SQLite databases. Use MySQL tools for MySQL backup.",
+				"Backup download only available for SQLite databases. Use the database server's tools for MySQL or PostgreSQL backup.",
 				http.StatusBadRequest)
 		}
 		dbPath = mgr.Path()
@@ -21,6 +21,7 @@ const MetricsHistoryMaxPoints = system.MetricsHistoryMaxPoints
 //   - GET /system/external-media -> media domain (external_media.go)
 //   - /system/database/overview -> analytics domain (database_overview.go)
 //   - /system/database/{migration,backup,legacy} -> import domain
+//   - /system/backups (stored-backup verify/restore) -> import domain
 //
 // The /system/audio/* device routes have moved to the audio/streaming domain and
 // are registered by c.audio.RegisterAudioDeviceRoutes (its own ordered initRoutes
@@ -44,9 +45,10 @@ func (c *Controller) initSystemRoutes() {
 	// Database overview (analytics domain).
 	c.analytics.RegisterDatabaseOverviewRoutes(c.Group)
 
-	// Migration, async backup and legacy cleanup routes (import domain).
+	// Migration, async backup, backup restore and legacy cleanup routes (import domain).
 	c.imports.RegisterMigrationRoutes(c.Group)
 	c.imports.RegisterBackupRoutes(c.Group)
+	c.imports.RegisterRestoreRoutes(c.Group)
 	c.imports.RegisterLegacyCleanupRoutes(c.Group)
 }
 
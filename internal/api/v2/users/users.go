@@ -0,0 +1,293 @@
+// Package users is the api/v2 user accounts domain handler. It owns the
+// /api/v2/users/* endpoints that let an admin manage the station's user
+// accounts and their roles. The Handler embeds *apicore.Core by pointer so the
+// shared dependencies and helpers (HandleError, the logging helpers, the
+// V2Manager and auth middleware) promote onto it.
+//
+// Accounts live in the v2 user store, so like the alerts domain every route
+// answers 409 Conflict while the enhanced database is unavailable. The
+// configured BasicAuth credentials and OAuth allow-lists remain the admin
+// identity and are not managed here.
+package users
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/api/v2/apicore"
+	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/security"
+)
+
+// maxUsernameLength matches the size of the user_accounts.username column.
+const maxUsernameLength = 100
+
+// validUsername restricts usernames to characters that are safe in logs, URLs
+// and attribution fields. E-mail style names are allowed.
+var validUsername = regexp.MustCompile(`^[a-z0-9][a-z0-9._@-]*$`)
+
+// Handler serves the user accounts endpoints. repo is nil until RegisterRoutes
+// builds it, which only happens when the enhanced v2 database is active.
+type Handler struct {
+	*apicore.Core
+
+	repo repository.UserAccountRepository
+}
+
+// New builds a users Handler around the shared core.
+func New(core *apicore.Core) *Handler {
+	return &Handler{Core: core}
+}
+
+// UserRequest is the body of the create and update endpoints. On update every
+// field is optional and only the fields present are changed; username cannot
+// be changed because reviews and locks are attributed by it.
+type UserRequest struct {
+	Username    string  `json:"username"`
+	DisplayName *string `json:"display_name"`
+	Password    *string `json:"password"`
+	ExternalID  *string `json:"external_id"`
+	Role        *string `json:"role"`
+	Disabled    *bool   `json:"disabled"`
+}
+
+// RegisterRoutes registers the user account endpoints. All of them require
+// the admin role. Authentication runs before the v2 check so unauthenticated
+// callers get 401 rather than learning about the database state.
+func (c *Handler) RegisterRoutes(g *echo.Group) {
+	users := g.Group("/users", c.AuthMiddleware, c.requireV2Middleware)
+	users.GET("", c.ListUsers)
+	users.POST("", c.CreateUser)
+	users.GET("/roles", c.ListRoles)
+	users.GET("/:id", c.GetUser)
+	users.PUT("/:id", c.UpdateUser)
+	users.DELETE("/:id", c.DeleteUser)
+
+	if c.usersAvailable() {
+		c.repo = repository.NewUserAccountRepository(c.V2Manager.DB(), nil)
+	}
+}
+
+// usersAvailable reports whether the v2 user store is available.
+func (c *Handler) usersAvailable() bool {
+	return c.V2Manager != nil && datastoreV2.IsEnhancedDatabase()
+}
+
+// requireV2Middleware answers 409 Conflict when the user store is unavailable.
+func (c *Handler) requireV2Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if c.repo == nil {
+			return c.HandleError(ctx, nil, "User accounts require the enhanced (v2) database", http.StatusConflict)
+		}
+		return next(ctx)
+	}
+}
+
+// ListRoles returns the available roles from least to most privileged.
+func (c *Handler) ListRoles(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"roles": security.Roles,
+	})
+}
+
+// ListUsers returns all user accounts.
+func (c *Handler) ListUsers(ctx echo.Context) error {
+	accounts, err := c.repo.List(ctx.Request().Context())
+	if err != nil {
+		c.LogErrorIfEnabled("failed to list user accounts", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to list users", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"users": accounts,
+		"count": len(accounts),
+	})
+}
+
+// GetUser returns a single user account.
+func (c *Handler) GetUser(ctx echo.Context) error {
+	account, err := c.loadAccount(ctx)
+	if account == nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, account)
+}
+
+// CreateUser creates a user account. A role is required, and the account
+// needs a password, an external OAuth identity or both to be able to sign in.
+func (c *Handler) CreateUser(ctx echo.Context) error {
+	var req UserRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+
+	username := auth.NormalizeUsername(req.Username)
+	if err := validateUsername(username); err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+	if req.Role == nil {
+		return c.HandleError(ctx, nil, "Role is required", http.StatusBadRequest)
+	}
+	if (req.Password == nil || *req.Password == "") && (req.ExternalID == nil || strings.TrimSpace(*req.ExternalID) == "") {
+		return c.HandleError(ctx, nil, "A password or an external ID is required", http.StatusBadRequest)
+	}
+
+	account := &entities.UserAccount{Username: username}
+	if err := c.applyRequest(ctx, account, &req); err != nil {
+		return err
+	}
+
+	if err := c.repo.Create(ctx.Request().Context(), account); err != nil {
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return c.HandleError(ctx, err, "A user with this username already exists", http.StatusConflict)
+		}
+		c.LogErrorIfEnabled("failed to create user account", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to create user", http.StatusInternalServerError)
+	}
+
+	c.LogInfoIfEnabled("user account created",
+		logger.Username(account.Username),
+		logger.String("role", account.Role),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusCreated, account)
+}
+
+// UpdateUser changes the fields present in the request body.
+func (c *Handler) UpdateUser(ctx echo.Context) error {
+	account, err := c.loadAccount(ctx)
+	if account == nil {
+		return err
+	}
+
+	var req UserRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+	if req.Username != "" && auth.NormalizeUsername(req.Username) != account.Username {
+		return c.HandleError(ctx, nil, "Username cannot be changed", http.StatusBadRequest)
+	}
+	if err := c.applyRequest(ctx, account, &req); err != nil {
+		return err
+	}
+
+	if err := c.repo.Update(ctx.Request().Context(), account); err != nil {
+		c.LogErrorIfEnabled("failed to update user account", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to update user", http.StatusInternalServerError)
+	}
+
+	c.LogInfoIfEnabled("user account updated",
+		logger.Username(account.Username),
+		logger.String("role", account.Role),
+		logger.Bool("disabled", account.Disabled),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusOK, account)
+}
+
+// DeleteUser removes a user account. Reviews and locks keep the username they
+// were attributed to.
+func (c *Handler) DeleteUser(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid user ID", http.StatusBadRequest)
+	}
+
+	if err := c.repo.Delete(ctx.Request().Context(), uint(id)); err != nil {
+		if errors.Is(err, repository.ErrUserAccountNotFound) {
+			return c.HandleError(ctx, err, "User not found", http.StatusNotFound)
+		}
+		c.LogErrorIfEnabled("failed to delete user account", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to delete user", http.StatusInternalServerError)
+	}
+
+	c.LogInfoIfEnabled("user account deleted",
+		logger.Uint64("id", id),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// loadAccount resolves the :id route parameter. On failure it writes the
+// error response and returns a nil account with the written error.
+func (c *Handler) loadAccount(ctx echo.Context) (*entities.UserAccount, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, c.HandleError(ctx, err, "Invalid user ID", http.StatusBadRequest)
+	}
+
+	account, err := c.repo.GetByID(ctx.Request().Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, repository.ErrUserAccountNotFound) {
+			return nil, c.HandleError(ctx, err, "User not found", http.StatusNotFound)
+		}
+		c.LogErrorIfEnabled("failed to get user account", logger.Error(err))
+		return nil, c.HandleError(ctx, err, "Failed to get user", http.StatusInternalServerError)
+	}
+	return account, nil
+}
+
+// applyRequest validates the optional request fields and copies them onto
+// account. On failure it writes the error response and returns it.
+func (c *Handler) applyRequest(ctx echo.Context, account *entities.UserAccount, req *UserRequest) error {
+	if req.Role != nil {
+		role, err := security.ParseRole(*req.Role)
+		if err != nil {
+			return c.HandleError(ctx, err, "Role must be one of viewer, reviewer or admin", http.StatusBadRequest)
+		}
+		account.Role = string(role)
+	}
+	if req.DisplayName != nil {
+		account.DisplayName = strings.TrimSpace(*req.DisplayName)
+	}
+	if req.Password != nil {
+		if *req.Password == "" {
+			account.PasswordHash = "" // OAuth-only account
+		} else {
+			hash, err := auth.HashPassword(*req.Password)
+			if err != nil {
+				if errors.Is(err, auth.ErrPasswordTooShort) {
+					return c.HandleError(ctx, err, "Password must be at least "+strconv.Itoa(auth.MinPasswordLength)+" characters", http.StatusBadRequest)
+				}
+				return c.HandleError(ctx, err, "Failed to set password", http.StatusInternalServerError)
+			}
+			account.PasswordHash = hash
+		}
+	}
+	if req.ExternalID != nil {
+		externalID := strings.TrimSpace(*req.ExternalID)
+		if externalID != "" {
+			existing, err := c.repo.GetByExternalID(ctx.Request().Context(), externalID)
+			if err == nil && existing.ID != account.ID {
+				return c.HandleError(ctx, nil, "Another user is already linked to this external ID", http.StatusConflict)
+			}
+		}
+		account.ExternalID = externalID
+	}
+	if req.Disabled != nil {
+		account.Disabled = *req.Disabled
+	}
+	return nil
+}
+
+// validateUsername checks a normalized username.
+func validateUsername(username string) error {
+	switch {
+	case username == "":
+		return errors.NewStd("Username is required")
+	case len(username) > maxUsernameLength:
+		return errors.NewStd("Username is too long")
+	case username == security.SubnetUsername:
+		return errors.NewStd("Username is reserved")
+	case !validUsername.MatchString(username):
+		return errors.NewStd("Username may only contain letters, digits and . _ @ -")
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package users
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+
+	"github.com/tphakala/birdnet-go/internal/api/v2/apitest"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+)
+
+// passthroughMiddleware stands in for the admin auth middleware.
+func passthroughMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
+
+// setupUsersHandler registers the users routes on a fresh Echo with the
+// repository backed by a temporary SQLite database.
+func setupUsersHandler(t *testing.T, withRepo bool) *echo.Echo {
+	t.Helper()
+	e := echo.New()
+	core := apitest.NewCore(t, apitest.WithEcho(e))
+	core.AuthMiddleware = passthroughMiddleware
+
+	h := New(core)
+	if withRepo {
+		db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "users.db")), &gorm.Config{
+			Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+		})
+		require.NoError(t, err)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+		require.NoError(t, db.AutoMigrate(&entities.UserAccount{}))
+		h.repo = repository.NewUserAccountRepository(db, nil)
+	}
+	h.RegisterRoutes(core.Group)
+	return e
+}
+
+func doJSON(t *testing.T, e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestUserRoutesReturn409WithoutV2(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e := setupUsersHandler(t, false)
+	rec := doJSON(t, e, http.MethodGet, "/api/v2/users", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestUserRoutesAuthenticateBeforeV2Check(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e := echo.New()
+	core := apitest.NewCore(t, apitest.WithEcho(e))
+	core.AuthMiddleware = func(echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			return ctx.NoContent(http.StatusUnauthorized)
+		}
+	}
+	New(core).RegisterRoutes(core.Group)
+
+	rec := doJSON(t, e, http.MethodGet, "/api/v2/users", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestUserLifecycle(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e := setupUsersHandler(t, true)
+
+	rec := doJSON(t, e, http.MethodPost, "/api/v2/users",
+		`{"username":"Anna","password":"volunteer-pw","role":"reviewer","display_name":"Anna K"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.NotContains(t, rec.Body.String(), "volunteer-pw")
+	assert.NotContains(t, rec.Body.String(), "password_hash")
+
+	var created entities.UserAccount
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "anna", created.Username, "usernames are normalized")
+	assert.Equal(t, "reviewer", created.Role)
+
+	rec = doJSON(t, e, http.MethodPost, "/api/v2/users", `{"username":"anna","password":"another-pw","role":"viewer"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	id := "/api/v2/users/" + strconv.FormatUint(uint64(created.ID), 10)
+	rec = doJSON(t, e, http.MethodPut, id, `{"role":"admin","disabled":true}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var updated entities.UserAccount
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Equal(t, "admin", updated.Role)
+	assert.True(t, updated.Disabled)
+	assert.Equal(t, "Anna K", updated.DisplayName, "omitted fields are left unchanged")
+
+	rec = doJSON(t, e, http.MethodGet, "/api/v2/users", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"count":1`)
+
+	rec = doJSON(t, e, http.MethodDelete, id, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = doJSON(t, e, http.MethodGet, id, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestCreateUserValidation(t *testing.T) {
+	// NOT parallel: apitest.NewCore publishes to the process-global settings snapshot.
+	e := setupUsersHandler(t, true)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing username", `{"password":"volunteer-pw","role":"viewer"}`},
+		{"invalid username", `{"username":"a b","password":"volunteer-pw","role":"viewer"}`},
+		{"reserved username", `{"username":"subnet-bypass","password":"volunteer-pw","role":"viewer"}`},
+		{"missing role", `{"username":"bob","password":"volunteer-pw"}`},
+		{"unknown role", `{"username":"bob","password":"volunteer-pw","role":"owner"}`},
+		{"short password", `{"username":"bob","password":"short","role":"viewer"}`},
+		{"no way to sign in", `{"username":"bob","role":"viewer"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doJSON(t, e, http.MethodPost, "/api/v2/users", tt.body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package weather
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	errors_pkg "github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	weather_pkg "github.com/tphakala/birdnet-go/internal/weather"
+)
+
+// maxStationUploadSize bounds a WeeWX loop packet body; real packets are a
+// few kilobytes at most.
+const maxStationUploadSize = 64 * 1024
+
+// errStationPassKey is returned when an upload does not carry the configured PASSKEY.
+var errStationPassKey = errors_pkg.Newf("weather station upload has an invalid PASSKEY").
+	Component("api-weather").Category(errors_pkg.CategoryValidation).Build()
+
+// errStationPassKeyUnset is returned for uploads while no station PASSKEY is configured.
+var errStationPassKeyUnset = errors_pkg.Newf("weather station PASSKEY is not configured").
+	Component("api-weather").Category(errors_pkg.CategoryConfiguration).Build()
+
+// ReceiveStationUpload handles GET and POST /api/v2/weather/station
+// Accepts readings pushed by a local weather station: Ecowitt (POST form) and
+// Ambient Weather (GET query) "custom server" uploads, and WeeWX loop packets
+// POSTed as JSON. The endpoint is unauthenticated since stations cannot log
+// in, so uploads must carry the configured station PASSKEY in the PASSKEY
+// field; all uploads are refused while no PASSKEY is configured.
+func (c *Handler) ReceiveStationUpload(ctx echo.Context) error {
+	req := ctx.Request()
+	now := time.Now()
+
+	// Without a PASSKEY anyone who can reach the endpoint could inject readings
+	if c.CurrentSettings().Realtime.Weather.Station.PassKey == "" {
+		return c.HandleError(ctx, errStationPassKeyUnset, "Weather station PASSKEY is not configured", http.StatusServiceUnavailable)
+	}
+
+	var (
+		reading weather_pkg.StationReading
+		passKey string
+		err     error
+	)
+	if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		body, readErr := io.ReadAll(io.LimitReader(req.Body, maxStationUploadSize))
+		if readErr != nil {
+			return c.HandleError(ctx, readErr, "Failed to read weather station upload", http.StatusBadRequest)
+		}
+		passKey = ctx.QueryParam("PASSKEY")
+		if !c.stationPassKeyValid(passKey) {
+			return c.rejectStationUpload(ctx)
+		}
+		reading, err = weather_pkg.ParseWeeWXPacket(body, now)
+	} else {
+		if parseErr := req.ParseForm(); parseErr != nil {
+			return c.HandleError(ctx, parseErr, "Invalid weather station upload", http.StatusBadRequest)
+		}
+		passKey = req.Form.Get("PASSKEY")
+		if !c.stationPassKeyValid(passKey) {
+			return c.rejectStationUpload(ctx)
+		}
+		reading, err = weather_pkg.ParseStationUpload(req.Form, now)
+	}
+	if err != nil {
+		c.LogWarnIfEnabled("Invalid weather station upload",
+			logger.Error(err),
+			logger.String("ip", ctx.RealIP()),
+		)
+		return c.HandleError(ctx, err, "Invalid weather station upload", http.StatusBadRequest)
+	}
+
+	if err := weather_pkg.ReceiveStationReading(reading); err != nil {
+		if errors.Is(err, weather_pkg.ErrStationNotActive) {
+			return c.HandleError(ctx, err, "Local weather station provider is not enabled", http.StatusServiceUnavailable)
+		}
+		return c.HandleError(ctx, err, "Failed to receive weather station upload", http.StatusInternalServerError)
+	}
+
+	c.LogDebugIfEnabled("Received weather station upload",
+		logger.String("ip", ctx.RealIP()),
+	)
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// stationPassKeyValid reports whether passKey matches the configured station PASSKEY.
+func (c *Handler) stationPassKeyValid(passKey string) bool {
+	want := c.CurrentSettings().Realtime.Weather.Station.PassKey
+	return want != "" && subtle.ConstantTimeCompare([]byte(passKey), []byte(want)) == 1
+}
+
+// rejectStationUpload responds to an upload with a missing or wrong PASSKEY.
+func (c *Handler) rejectStationUpload(ctx echo.Context) error {
+	c.LogSecurityWarnIfEnabled("Rejected weather station upload with invalid PASSKEY",
+		logger.String("ip", ctx.RealIP()),
+	)
+	return c.HandleError(ctx, errStationPassKey, "Invalid weather station PASSKEY", http.StatusUnauthorized)
+}
@@ -0,0 +1,179 @@
+// station_test.go: tests for the local weather station upload endpoint.
+
+package weather
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/api/v2/apitest"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+	weather_pkg "github.com/tphakala/birdnet-go/internal/weather"
+)
+
+// setupStationTestEnvironment builds a weather Handler whose settings select
+// the station provider with the given PASSKEY. When active is true a station
+// weather service backed by the returned mock datastore is registered, so
+// uploads are accepted and a poll stores them.
+func setupStationTestEnvironment(t *testing.T, passKey string, active bool) (*echo.Echo, *Handler, *weather_pkg.Service, *mocks.MockInterface) {
+	t.Helper()
+
+	e := echo.New()
+	mockDS := mocks.NewMockInterface(t)
+	core := apitest.NewCore(t, apitest.WithEcho(e), apitest.WithSettingsFunc(func(s *conf.Settings) {
+		s.Realtime.Weather.Provider = string(conf.WeatherStation)
+		s.Realtime.Weather.Station.PassKey = passKey
+	}))
+
+	var svc *weather_pkg.Service
+	weather_pkg.UnregisterService()
+	if active {
+		var err error
+		svc, err = weather_pkg.NewService(core.CurrentSettings(), mockDS, nil)
+		require.NoError(t, err)
+		weather_pkg.RegisterService(svc)
+	}
+	t.Cleanup(weather_pkg.UnregisterService)
+
+	return e, New(core), svc, mockDS
+}
+
+func TestReceiveStationUpload(t *testing.T) {
+	tests := []struct {
+		name        string
+		passKey     string
+		active      bool
+		method      string
+		target      string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{
+			name:        "ecowitt form post",
+			passKey:     "ABC123",
+			active:      true,
+			method:      http.MethodPost,
+			target:      "/api/v2/weather/station",
+			contentType: echo.MIMEApplicationForm,
+			body:        "PASSKEY=ABC123&dateutc=now&tempf=68&humidity=50",
+			wantStatus:  http.StatusNoContent,
+		},
+		{
+			name:       "ambient query string",
+			passKey:    "00:11:22",
+			active:     true,
+			method:     http.MethodGet,
+			target:     "/api/v2/weather/station?PASSKEY=00:11:22&tempf=68&windspeedmph=5",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:        "weewx json packet",
+			passKey:     "ABC123",
+			active:      true,
+			method:      http.MethodPost,
+			target:      "/api/v2/weather/station?PASSKEY=ABC123",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"usUnits": 16, "outTemp_C": "20.0"}`,
+			wantStatus:  http.StatusNoContent,
+		},
+		{
+			name:        "wrong passkey",
+			passKey:     "ABC123",
+			active:      true,
+			method:      http.MethodPost,
+			target:      "/api/v2/weather/station",
+			contentType: echo.MIMEApplicationForm,
+			body:        "PASSKEY=nope&tempf=68",
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			name:        "missing passkey on json",
+			passKey:     "ABC123",
+			active:      true,
+			method:      http.MethodPost,
+			target:      "/api/v2/weather/station",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"outTemp_C": 20.0}`,
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			name:        "passkey not configured",
+			active:      true,
+			method:      http.MethodPost,
+			target:      "/api/v2/weather/station",
+			contentType: echo.MIMEApplicationForm,
+			body:        "PASSKEY=&tempf=68",
+			wantStatus:  http.StatusServiceUnavailable,
+		},
+		{
+			name:        "no readings",
+			passKey:     "ABC123",
+			active:      true,
+			method:      http.MethodPost,
+			target:      "/api/v2/weather/station",
+			contentType: echo.MIMEApplicationForm,
+			body:        "PASSKEY=ABC123&stationtype=GW1000",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "malformed json",
+			passKey:     "ABC123",
+			active:      true,
+			method:      http.MethodPost,
+			target:      "/api/v2/weather/station?PASSKEY=ABC123",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"outTemp_C":`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "station provider not running",
+			passKey:     "ABC123",
+			active:      false,
+			method:      http.MethodPost,
+			target:      "/api/v2/weather/station",
+			contentType: echo.MIMEApplicationForm,
+			body:        "PASSKEY=ABC123&tempf=68",
+			wantStatus:  http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Not parallel: publishes settings and registers the global weather service
+			e, controller, svc, mockDS := setupStationTestEnvironment(t, tt.passKey, tt.active)
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set(echo.HeaderContentType, tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v2/weather/station")
+
+			require.NoError(t, controller.ReceiveStationUpload(c))
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+
+			if svc == nil {
+				return
+			}
+			// Only an accepted upload leaves a reading for the next poll to store;
+			// the strict mock fails the test on any unexpected save
+			if tt.wantStatus == http.StatusNoContent {
+				mockDS.On("SaveDailyEvents", mock.Anything).Return(nil).Once()
+				mockDS.On("SaveHourlyWeather", mock.MatchedBy(func(hw *datastore.HourlyWeather) bool {
+					return hw.Temperature > 19.9 && hw.Temperature < 20.1
+				})).Return(nil).Once()
+			}
+			require.NoError(t, svc.Poll(t.Context()))
+		})
+	}
+}
@@ -28,6 +28,15 @@ const (
 	weatherSunWindowMinute = 30 // Minutes before/after sunrise/sunset for weather
 )
 
+// Route path fragments for the weather group and the local weather station
+// upload endpoint. Stations cannot log in, so the facade's PrivateMode
+// allow-list (isPrivateModeExempt) composes the exempt upload path from these
+// same constants, which keeps it from drifting from the registered route.
+const (
+	WeatherGroupPath  = "/weather"
+	StationUploadPath = "/station"
+)
+
 // Handler serves the weather domain endpoints. It embeds *apicore.Core BY
 // POINTER so the shared Core members promote onto it without re-wiring; Core
 // carries atomic/lock-bearing fields and must never be copied by value.
@@ -45,7 +54,7 @@ func New(core *apicore.Core) *Handler {
 // the weather domain was extracted.
 func (c *Handler) RegisterRoutes(g *echo.Group) {
 	// Create weather API group
-	weatherGroup := g.Group("/weather")
+	weatherGroup := g.Group(WeatherGroupPath)
 
 	// TODO: Consider adding authentication middleware to protect these endpoints
 	// Example: weatherGroup.Use(middlewares.RequireAuth())
@@ -71,6 +80,10 @@ func (c *Handler) RegisterRoutes(g *echo.Group) {
 
 	// Moon phase endpoint
 	weatherGroup.GET("/moon/:date", c.GetMoonPhase)
+
+	// Local weather station uploads (Ambient uses GET, Ecowitt and WeeWX POST)
+	weatherGroup.GET(StationUploadPath, c.ReceiveStationUpload)
+	weatherGroup.POST(StationUploadPath, c.ReceiveStationUpload)
 }
 
 // dailyWeatherResponse represents the API response for daily weather data
@@ -98,6 +111,7 @@ type hourlyWeatherResponse struct {
 	Clouds            int     `json:"clouds,omitempty"`
 	Precipitation     float64 `json:"precipitation,omitempty"`
 	PrecipitationType string  `json:"precipitation_type,omitempty"`
+	SolarRadiation    float64 `json:"solar_radiation,omitempty"`
 	WeatherMain       string  `json:"weather_main,omitempty"`
 	WeatherDesc       string  `json:"weather_desc,omitempty"`
 	WeatherIcon       string  `json:"weather_icon,omitempty"`
@@ -493,6 +507,7 @@ func (c *Handler) buildHourlyWeatherResponse(hw *datastore.HourlyWeather) hourly
 		Clouds:            hw.Clouds,
 		Precipitation:     hw.Precipitation,
 		PrecipitationType: hw.PrecipitationType,
+		SolarRadiation:    hw.SolarRadiation,
 		WeatherMain:       hw.WeatherMain,
 		WeatherDesc:       hw.WeatherDesc,
 		WeatherIcon:       hw.WeatherIcon,
@@ -0,0 +1,54 @@
+// Package audiofile decodes recorded WAV and FLAC files into fixed-length,
+// mono float32 analysis windows for offline classification.
+//
+// Decoding is streaming: the file is read, downmixed and resampled block by
+// block, so memory use is bounded by the analysis window rather than the
+// recording length. This matters for the multi-hour recordings produced by
+// unattended field recorders.
+package audiofile
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Supported file formats.
+const (
+	FormatWAV  = "wav"
+	FormatFLAC = "flac"
+)
+
+// Info describes the stored format of an audio file.
+type Info struct {
+	Format      string // FormatWAV or FormatFLAC
+	SampleRate  int    // Hz
+	Channels    int    // number of interleaved channels
+	BitDepth    int    // bits per sample
+	TotalFrames int64  // per-channel sample count; 0 when unknown
+}
+
+// Duration returns the recording length, or zero when the frame count is unknown.
+func (i Info) Duration() time.Duration {
+	if i.SampleRate <= 0 || i.TotalFrames <= 0 {
+		return 0
+	}
+	return time.Duration(i.TotalFrames) * time.Second / time.Duration(i.SampleRate)
+}
+
+// IsSupported reports whether path has a file extension this package can decode.
+func IsSupported(path string) bool {
+	return formatForPath(path) != ""
+}
+
+// formatForPath maps a file extension to a supported format, or "" if unsupported.
+func formatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".wav", ".wave":
+		return FormatWAV
+	case ".flac":
+		return FormatFLAC
+	default:
+		return ""
+	}
+}
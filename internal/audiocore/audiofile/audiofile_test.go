@@ -0,0 +1,237 @@
+package audiofile
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/audiocore/flac"
+)
+
+const testRate = 48000
+
+// writeWAV writes a minimal RIFF/WAVE file with the given raw sample payload.
+func writeWAV(t *testing.T, path string, format uint16, rate, channels, bitDepth int, payload []byte) {
+	t.Helper()
+	blockAlign := channels * bitDepth / 8
+	hdr := make([]byte, 0, 44)
+	hdr = append(hdr, "RIFF"...)
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(36+len(payload))) //nolint:gosec // test data is small
+	hdr = append(hdr, "WAVEfmt "...)
+	hdr = binary.LittleEndian.AppendUint32(hdr, 16)
+	hdr = binary.LittleEndian.AppendUint16(hdr, format)
+	hdr = binary.LittleEndian.AppendUint16(hdr, uint16(channels))        //nolint:gosec // test data
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(rate))            //nolint:gosec // test data
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(rate*blockAlign)) //nolint:gosec // test data
+	hdr = binary.LittleEndian.AppendUint16(hdr, uint16(blockAlign))      //nolint:gosec // test data
+	hdr = binary.LittleEndian.AppendUint16(hdr, uint16(bitDepth))        //nolint:gosec // test data
+	hdr = append(hdr, "data"...)
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(len(payload))) //nolint:gosec // test data is small
+	require.NoError(t, os.WriteFile(path, append(hdr, payload...), 0o600))
+}
+
+// pcm16Constant returns n frames of a constant 16-bit value on every channel.
+func pcm16Constant(n, channels int, v int16) []byte {
+	b := make([]byte, 0, n*channels*2)
+	for range n * channels {
+		b = binary.LittleEndian.AppendUint16(b, uint16(v)) //nolint:gosec // test data
+	}
+	return b
+}
+
+func chunkOpts() ChunkOptions {
+	return ChunkOptions{SampleRate: testRate, ClipLength: 3 * time.Second}
+}
+
+func collect(t *testing.T, path string, opts ChunkOptions) (Info, []Chunk) {
+	t.Helper()
+	var chunks []Chunk
+	info, err := ReadChunks(t.Context(), path, opts, func(c Chunk) error {
+		samples := make([]float32, len(c.Samples))
+		copy(samples, c.Samples)
+		c.Samples = samples
+		chunks = append(chunks, c)
+		return nil
+	})
+	require.NoError(t, err)
+	return info, chunks
+}
+
+func TestReadChunks_WAVWindowsAndTail(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "rec.wav")
+	// 7.5 s: two full windows plus a 1.5 s tail that must be padded.
+	writeWAV(t, path, wavFormatPCM, testRate, 1, 16, pcm16Constant(testRate*15/2, 1, 16384))
+
+	info, chunks := collect(t, path, chunkOpts())
+	assert.Equal(t, FormatWAV, info.Format)
+	assert.Equal(t, 7500*time.Millisecond, info.Duration())
+	require.Len(t, chunks, 3)
+
+	for i, c := range chunks {
+		assert.Len(t, c.Samples, 3*testRate, "window %d must be exactly one clip", i)
+		assert.Equal(t, time.Duration(i)*3*time.Second, c.Offset)
+	}
+	assert.Equal(t, 3*time.Second, chunks[0].Length)
+	assert.Equal(t, 1500*time.Millisecond, chunks[2].Length)
+	assert.InDelta(t, 0.5, chunks[2].Samples[0], 1e-4)
+	assert.InDelta(t, 0.0, chunks[2].Samples[len(chunks[2].Samples)-1], 1e-9, "tail must be zero padded")
+}
+
+func TestReadChunks_Overlap(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "rec.wav")
+	writeWAV(t, path, wavFormatPCM, testRate, 1, 16, pcm16Constant(testRate*6, 1, 1000))
+
+	opts := chunkOpts()
+	opts.Overlap = 1500 * time.Millisecond
+	_, chunks := collect(t, path, opts)
+
+	offsets := make([]time.Duration, 0, len(chunks))
+	for _, c := range chunks {
+		offsets = append(offsets, c.Offset)
+	}
+	assert.Equal(t, []time.Duration{0, 1500 * time.Millisecond, 3 * time.Second}, offsets,
+		"a 6 s file with 1.5 s overlap yields three full windows and no tail")
+}
+
+func TestReadChunks_ShortTailSkipped(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "rec.wav")
+	writeWAV(t, path, wavFormatPCM, testRate, 1, 16, pcm16Constant(testRate*3+testRate/2, 1, 1000))
+
+	_, chunks := collect(t, path, chunkOpts())
+	assert.Len(t, chunks, 1, "a 0.5 s tail is below the default minimum and is dropped")
+}
+
+func TestReadChunks_StereoDownmixAndResample(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "rec.wav")
+	const rate = 44100
+	payload := make([]byte, 0, rate*4*4)
+	for range rate * 4 {
+		payload = binary.LittleEndian.AppendUint16(payload, uint16(16384))  // left: 0.5
+		payload = binary.LittleEndian.AppendUint16(payload, uint16(0xC000)) // right: -0.5
+	}
+	writeWAV(t, path, wavFormatPCM, rate, 2, 16, payload)
+
+	info, chunks := collect(t, path, chunkOpts())
+	assert.Equal(t, rate, info.SampleRate)
+	assert.Equal(t, 2, info.Channels)
+	require.Len(t, chunks, 2)
+	assert.Len(t, chunks[0].Samples, 3*testRate)
+	mid := chunks[0].Samples[testRate]
+	assert.InDelta(t, 0.0, mid, 1e-3, "opposite channels must cancel when downmixed")
+}
+
+func TestReadChunks_Float32And24Bit(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	floatPath := filepath.Join(dir, "float.wav")
+	floatPayload := make([]byte, 0, testRate*4*4)
+	for range testRate * 4 {
+		floatPayload = binary.LittleEndian.AppendUint32(floatPayload, math.Float32bits(-0.25))
+	}
+	writeWAV(t, floatPath, wavFormatFloat, testRate, 1, 32, floatPayload)
+	_, chunks := collect(t, floatPath, chunkOpts())
+	require.NotEmpty(t, chunks)
+	assert.InDelta(t, -0.25, chunks[0].Samples[10], 1e-6)
+
+	path24 := filepath.Join(dir, "pcm24.wav")
+	payload24 := make([]byte, 0, testRate*4*3)
+	for range testRate * 4 {
+		v := uint32(0xC00000) // -0.5 in 24-bit two's complement
+		payload24 = append(payload24, byte(v), byte(v>>8), byte(v>>16))
+	}
+	writeWAV(t, path24, wavFormatPCM, testRate, 1, 24, payload24)
+	_, chunks = collect(t, path24, chunkOpts())
+	require.NotEmpty(t, chunks)
+	assert.InDelta(t, -0.5, chunks[0].Samples[10], 1e-6)
+}
+
+func TestReadChunks_FLAC(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "rec.flac")
+	require.NoError(t, flac.EncodePCM(t.Context(), &flac.Options{
+		PCMData:    pcm16Constant(testRate*3, 1, -8192),
+		OutputPath: path,
+		SampleRate: testRate,
+		Channels:   1,
+		BitDepth:   16,
+	}))
+
+	info, err := Probe(path)
+	require.NoError(t, err)
+	assert.Equal(t, FormatFLAC, info.Format)
+	assert.Equal(t, 3*time.Second, info.Duration())
+
+	_, chunks := collect(t, path, chunkOpts())
+	require.Len(t, chunks, 1)
+	assert.InDelta(t, -0.25, chunks[0].Samples[100], 1e-6)
+}
+
+func TestReadChunks_CallbackErrorStops(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "rec.wav")
+	writeWAV(t, path, wavFormatPCM, testRate, 1, 16, pcm16Constant(testRate*9, 1, 0))
+
+	stop := assert.AnError
+	calls := 0
+	_, err := ReadChunks(t.Context(), path, chunkOpts(), func(Chunk) error {
+		calls++
+		return stop
+	})
+	require.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestReadChunks_Validation(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	_, err := ReadChunks(t.Context(), filepath.Join(dir, "notes.txt"), chunkOpts(), func(Chunk) error { return nil })
+	require.Error(t, err, "unsupported extensions are rejected")
+
+	path := filepath.Join(dir, "rec.wav")
+	writeWAV(t, path, wavFormatPCM, testRate, 1, 16, pcm16Constant(testRate, 1, 0))
+	_, err = ReadChunks(t.Context(), path, ChunkOptions{SampleRate: testRate, ClipLength: time.Second, Overlap: time.Second},
+		func(Chunk) error { return nil })
+	require.Error(t, err, "overlap must be shorter than the clip")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.wav"), []byte("not a wav file"), 0o600))
+	_, err = Probe(filepath.Join(dir, "bad.wav"))
+	require.Error(t, err)
+}
+
+func TestIsSupported(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsSupported("/data/REC_0001.WAV"))
+	assert.True(t, IsSupported("a.flac"))
+	assert.False(t, IsSupported("a.mp3"))
+	assert.False(t, IsSupported("wav"))
+}
+
+func TestReadMono_DownmixesWholeFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "clip.wav")
+	// Stereo with the left channel at half scale and the right channel silent.
+	payload := make([]byte, 0, 10000*4)
+	for range 10000 {
+		payload = binary.LittleEndian.AppendUint16(payload, 16384)
+		payload = binary.LittleEndian.AppendUint16(payload, 0)
+	}
+	writeWAV(t, path, wavFormatPCM, 22050, 2, 16, payload)
+
+	samples, info, err := ReadMono(t.Context(), path)
+	require.NoError(t, err)
+	assert.Equal(t, 22050, info.SampleRate)
+	require.Len(t, samples, 10000)
+	assert.InDelta(t, 0.25, samples[0], 1e-6)
+	assert.InDelta(t, 0.25, samples[len(samples)-1], 1e-6)
+}
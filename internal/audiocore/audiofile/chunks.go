@@ -0,0 +1,188 @@
+package audiofile
+
+import (
+	"context"
+	"io"
+	"time"
+
+	audioresampler "github.com/tphakala/go-audio-resampler"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// defaultMinTail is the shortest trailing window that is still analysed when
+// ChunkOptions.MinTail is zero. Shorter tails are zero padded windows that
+// rarely contain enough signal for a reliable prediction.
+const defaultMinTail = time.Second
+
+// Chunk is one analysis window decoded from a file.
+type Chunk struct {
+	// Samples holds exactly one window of mono audio at the target sample rate.
+	// A trailing window shorter than the clip length is zero padded. The slice
+	// is reused between callbacks and must be copied if retained.
+	Samples []float32
+	// Offset is the start of the window relative to the beginning of the file.
+	Offset time.Duration
+	// Length is the duration of real (non-padded) audio in the window.
+	Length time.Duration
+}
+
+// ChunkOptions controls how a file is split into analysis windows.
+type ChunkOptions struct {
+	SampleRate int           // target sample rate in Hz expected by the model
+	ClipLength time.Duration // analysis window length
+	Overlap    time.Duration // overlap between consecutive windows; must be < ClipLength
+	MinTail    time.Duration // trailing windows with less new audio are skipped (default 1s)
+}
+
+// ReadChunks decodes the file at path and calls fn for each analysis window,
+// in order. Audio is downmixed to mono and resampled to opts.SampleRate.
+// Iteration stops at the first error returned by fn or when ctx is cancelled.
+func ReadChunks(ctx context.Context, path string, opts ChunkOptions, fn func(Chunk) error) (Info, error) {
+	if opts.SampleRate <= 0 || opts.ClipLength <= 0 || opts.Overlap < 0 || opts.Overlap >= opts.ClipLength {
+		return Info{}, errors.Newf("invalid chunk options: rate=%d clip=%s overlap=%s",
+			opts.SampleRate, opts.ClipLength, opts.Overlap).
+			Component(componentName).
+			Category(errors.CategoryValidation).
+			Context("operation", "read_chunks").
+			Build()
+	}
+	if opts.MinTail <= 0 {
+		opts.MinTail = defaultMinTail
+	}
+
+	s, err := openStream(path)
+	if err != nil {
+		return Info{}, err
+	}
+	defer func() { _ = s.Close() }()
+
+	var rs *audioresampler.SimpleResamplerFloat32
+	if s.info.SampleRate != opts.SampleRate {
+		rs, err = audioresampler.NewEngineFloat32(float64(s.info.SampleRate), float64(opts.SampleRate), audioresampler.QualityMedium)
+		if err != nil {
+			return s.info, errors.Newf("failed to create resampler from %d Hz to %d Hz: %w", s.info.SampleRate, opts.SampleRate, err).
+				Component(componentName).
+				Category(errors.CategoryAudio).
+				Context("operation", "read_chunks").
+				Build()
+		}
+	}
+
+	w := newWindower(opts)
+	block := make([]float32, readBlockFrames)
+	for {
+		if err := ctx.Err(); err != nil {
+			return s.info, err
+		}
+		n, readErr := s.readMono(block)
+		if n > 0 {
+			samples := block[:n]
+			if rs != nil {
+				if samples, err = rs.Process(samples); err != nil {
+					return s.info, errors.New(err).
+						Component(componentName).
+						Category(errors.CategoryAudio).
+						Context("operation", "resample_chunk").
+						Build()
+				}
+			}
+			if err := w.push(samples, fn); err != nil {
+				return s.info, err
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return s.info, readErr
+		}
+	}
+
+	if rs != nil {
+		tail, err := rs.Flush()
+		if err != nil {
+			return s.info, errors.New(err).
+				Component(componentName).
+				Category(errors.CategoryAudio).
+				Context("operation", "flush_resampler").
+				Build()
+		}
+		if err := w.push(tail, fn); err != nil {
+			return s.info, err
+		}
+	}
+	return s.info, w.finish(fn)
+}
+
+// windower slices a continuous sample stream into overlapping windows.
+type windower struct {
+	rate     int
+	clip     int // window length in samples
+	step     int // hop between window starts in samples
+	minTail  int // minimum unanalysed samples for a trailing window
+	buf      []float32
+	start    int64 // absolute sample index of buf[0]
+	analyzed int64 // absolute sample index up to which audio has been emitted
+	total    int64 // absolute number of samples pushed
+}
+
+func newWindower(opts ChunkOptions) *windower {
+	clip := durationToSamples(opts.ClipLength, opts.SampleRate)
+	step := clip - durationToSamples(opts.Overlap, opts.SampleRate)
+	return &windower{
+		rate:    opts.SampleRate,
+		clip:    clip,
+		step:    max(step, 1),
+		minTail: durationToSamples(opts.MinTail, opts.SampleRate),
+		buf:     make([]float32, 0, clip+readBlockFrames*2),
+	}
+}
+
+// push appends samples and emits every complete window.
+func (w *windower) push(samples []float32, fn func(Chunk) error) error {
+	w.buf = append(w.buf, samples...)
+	w.total += int64(len(samples))
+	for len(w.buf) >= w.clip {
+		if err := w.emit(w.buf[:w.clip], w.clip, fn); err != nil {
+			return err
+		}
+		w.buf = w.buf[:copy(w.buf, w.buf[w.step:])]
+		w.start += int64(w.step)
+	}
+	return nil
+}
+
+// finish emits the zero padded trailing window when it holds enough new audio.
+func (w *windower) finish(fn func(Chunk) error) error {
+	fresh := w.total - w.analyzed
+	if len(w.buf) == 0 || fresh <= 0 {
+		return nil
+	}
+	// A file shorter than one window is still analysed unless it is shorter
+	// than the minimum tail; otherwise only tails with enough fresh audio count.
+	if fresh < int64(w.minTail) {
+		return nil
+	}
+	valid := len(w.buf)
+	window := make([]float32, w.clip)
+	copy(window, w.buf)
+	return w.emit(window, valid, fn)
+}
+
+func (w *windower) emit(samples []float32, valid int, fn func(Chunk) error) error {
+	w.analyzed = w.start + int64(valid)
+	return fn(Chunk{
+		Samples: samples,
+		Offset:  samplesToDuration(w.start, w.rate),
+		Length:  samplesToDuration(int64(valid), w.rate),
+	})
+}
+
+func durationToSamples(d time.Duration, rate int) int {
+	return int(d * time.Duration(rate) / time.Second) //nolint:durationcheck // intentional: converts Hz rate to sample count via duration arithmetic
+}
+
+func samplesToDuration(n int64, rate int) time.Duration {
+	return time.Duration(n) * time.Second / time.Duration(rate)
+}
@@ -0,0 +1,282 @@
+package audiofile
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+
+	"github.com/go-audio/wav"
+	goflac "github.com/tphakala/go-flac/pcm"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+const (
+	componentName = "audiocore/audiofile"
+
+	// wavFormatPCM and wavFormatFloat are the WAVE fmt chunk audio format codes
+	// for integer PCM and IEEE float samples.
+	wavFormatPCM   = 1
+	wavFormatFloat = 3
+
+	// wavFormatExtensible marks WAVE_FORMAT_EXTENSIBLE headers. The sub-format is
+	// not exposed by the WAV parser; integer PCM is by far the most common payload
+	// and is assumed.
+	wavFormatExtensible = 0xFFFE
+
+	// readBlockFrames is the number of frames decoded per read. At 48 kHz this is
+	// ~85 ms of audio, large enough to amortise syscalls and small enough to keep
+	// memory flat for any channel count.
+	readBlockFrames = 4096
+)
+
+// pcmStream yields interleaved little-endian PCM from a decoded file and
+// converts it to mono float32 in [-1, 1].
+type pcmStream struct {
+	r        io.Reader
+	closer   io.Closer
+	info     Info
+	bytesPS  int  // bytes per sample in the interleaved stream
+	isFloat  bool // IEEE float samples (WAV format 3)
+	unsigned bool // 8-bit WAV samples are unsigned
+	scale    float32
+	raw      []byte
+}
+
+// openStream opens path and positions it at the start of the audio payload.
+func openStream(path string) (*pcmStream, error) {
+	format := formatForPath(path)
+	if format == "" {
+		return nil, errors.Newf("unsupported audio file format: %s", path).
+			Component(componentName).
+			Category(errors.CategoryValidation).
+			Context("operation", "open_audio_file").
+			Build()
+	}
+
+	f, err := os.Open(path) //nolint:gosec // G304: path is an operator-supplied input file
+	if err != nil {
+		return nil, errors.New(err).
+			Component(componentName).
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_audio_file").
+			Build()
+	}
+
+	var s *pcmStream
+	switch format {
+	case FormatWAV:
+		s, err = openWAV(f)
+	case FormatFLAC:
+		s, err = openFLAC(f)
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	s.closer = f
+	s.info.Format = format
+	return s, nil
+}
+
+// openWAV parses the RIFF headers and returns a stream limited to the data chunk.
+func openWAV(f *os.File) (*pcmStream, error) {
+	dec := wav.NewDecoder(f)
+	if err := dec.FwdToPCM(); err != nil || dec.Err() != nil || dec.PCMChunk == nil {
+		if err == nil {
+			err = dec.Err()
+		}
+		return nil, decodeError(err, "parse_wav_header")
+	}
+
+	channels := int(dec.NumChans)
+	bitDepth := int(dec.BitDepth)
+	isFloat := false
+	switch dec.WavAudioFormat {
+	case wavFormatPCM, wavFormatExtensible:
+	case wavFormatFloat:
+		isFloat = true
+	default:
+		return nil, errors.Newf("unsupported WAV sample encoding (format code %d)", dec.WavAudioFormat).
+			Component(componentName).
+			Category(errors.CategoryValidation).
+			Context("operation", "parse_wav_header").
+			Build()
+	}
+	if isFloat && bitDepth != 32 {
+		return nil, errors.Newf("unsupported WAV float bit depth: %d", bitDepth).
+			Component(componentName).
+			Category(errors.CategoryValidation).
+			Context("operation", "parse_wav_header").
+			Build()
+	}
+
+	s, err := newStream(Info{
+		SampleRate: int(dec.SampleRate),
+		Channels:   channels,
+		BitDepth:   bitDepth,
+	}, isFloat)
+	if err != nil {
+		return nil, err
+	}
+	s.unsigned = bitDepth == 8
+	if frameBytes := channels * s.bytesPS; frameBytes > 0 {
+		s.info.TotalFrames = int64(dec.PCMChunk.Size / frameBytes)
+	}
+	// The chunk reader is the underlying file, so bound it to the data chunk
+	// to keep trailing LIST/cue chunks out of the sample stream.
+	s.r = bufio.NewReader(io.LimitReader(dec.PCMChunk.R, int64(dec.PCMChunk.Size)))
+	return s, nil
+}
+
+// openFLAC reads the FLAC metadata and returns a stream over the decoded PCM.
+func openFLAC(f *os.File) (*pcmStream, error) {
+	dec, err := goflac.NewDecoder(bufio.NewReader(f))
+	if err != nil {
+		return nil, decodeError(err, "parse_flac_header")
+	}
+	si := dec.Info()
+	s, err := newStream(Info{
+		SampleRate:  si.SampleRate,
+		Channels:    si.Channels,
+		BitDepth:    si.BitDepth,
+		TotalFrames: int64(si.TotalSamples), //nolint:gosec // G115: FLAC sample counts are 36-bit
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	s.r = dec
+	return s, nil
+}
+
+// newStream validates the format and precomputes the sample conversion.
+func newStream(info Info, isFloat bool) (*pcmStream, error) {
+	if info.SampleRate <= 0 || info.Channels <= 0 || info.BitDepth < 8 || info.BitDepth > 32 {
+		return nil, errors.Newf("unsupported audio format: %d Hz, %d channels, %d bits",
+			info.SampleRate, info.Channels, info.BitDepth).
+			Component(componentName).
+			Category(errors.CategoryValidation).
+			Context("operation", "validate_audio_format").
+			Build()
+	}
+	bytesPS := (info.BitDepth + 7) / 8
+	return &pcmStream{
+		info:    info,
+		bytesPS: bytesPS,
+		isFloat: isFloat,
+		scale:   float32(math.Ldexp(1, info.BitDepth-1)),
+		raw:     make([]byte, readBlockFrames*info.Channels*bytesPS),
+	}, nil
+}
+
+// readMono decodes up to len(dst) frames into dst as mono float32, averaging
+// all channels. It returns io.EOF once the stream is exhausted.
+func (s *pcmStream) readMono(dst []float32) (int, error) {
+	frameBytes := s.info.Channels * s.bytesPS
+	want := min(len(dst), len(s.raw)/frameBytes) * frameBytes
+	n, err := io.ReadFull(s.r, s.raw[:want])
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, decodeError(err, "read_audio_samples")
+	}
+
+	frames := n / frameBytes
+	channels := float32(s.info.Channels)
+	for i := range frames {
+		var sum float32
+		base := i * frameBytes
+		for ch := range s.info.Channels {
+			sum += s.sample(s.raw[base+ch*s.bytesPS:])
+		}
+		dst[i] = sum / channels
+	}
+	if frames == 0 && err == nil {
+		err = io.EOF
+	}
+	return frames, err
+}
+
+// sample converts one little-endian sample to float32 in [-1, 1].
+func (s *pcmStream) sample(b []byte) float32 {
+	if s.isFloat {
+		return math.Float32frombits(binary.LittleEndian.Uint32(b))
+	}
+	var v int32
+	switch s.bytesPS {
+	case 1:
+		if s.unsigned {
+			v = int32(b[0]) - 128
+		} else {
+			v = int32(int8(b[0])) //nolint:gosec // G115: intentional byte→int8 reinterpretation for PCM audio
+		}
+	case 2:
+		v = int32(int16(binary.LittleEndian.Uint16(b))) //nolint:gosec // G115: intentional uint16→int16 reinterpretation for PCM audio
+	case 3:
+		v = int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8 //nolint:gosec // G115: sign-extend 24-bit PCM
+	default:
+		v = int32(binary.LittleEndian.Uint32(b)) //nolint:gosec // G115: intentional uint32→int32 reinterpretation for PCM audio
+	}
+	return float32(v) / s.scale
+}
+
+// Close releases the underlying file.
+func (s *pcmStream) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// decodeError wraps a decoder failure with the package's error context.
+func decodeError(err error, operation string) error {
+	if err == nil {
+		err = errors.NewStd("malformed audio file")
+	}
+	return errors.New(err).
+		Component(componentName).
+		Category(errors.CategoryFileParsing).
+		Context("operation", operation).
+		Build()
+}
+
+// Probe reads the header of the audio file at path and returns its format.
+func Probe(path string) (Info, error) {
+	s, err := openStream(path)
+	if err != nil {
+		return Info{}, err
+	}
+	defer func() { _ = s.Close() }()
+	return s.info, nil
+}
+
+// ReadMono decodes the whole audio file at path into mono float32 samples at
+// its stored sample rate. It is meant for short recordings such as detection
+// clips; use ReadChunks for long recordings.
+func ReadMono(ctx context.Context, path string) ([]float32, Info, error) {
+	s, err := openStream(path)
+	if err != nil {
+		return nil, Info{}, err
+	}
+	defer func() { _ = s.Close() }()
+
+	samples := make([]float32, 0, max(s.info.TotalFrames, 0))
+	block := make([]float32, readBlockFrames)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, s.info, err
+		}
+		n, readErr := s.readMono(block)
+		samples = append(samples, block[:n]...)
+		if errors.Is(readErr, io.EOF) {
+			return samples, s.info, nil
+		}
+		if readErr != nil {
+			return nil, s.info, readErr
+		}
+	}
+}
@@ -322,6 +322,14 @@ func startCapture(
 	deviceCfg.Alsa.NoMMap = 1
 	devIDPtr := selectedInfo.ID.Pointer()
 	deviceCfg.Capture.DeviceID = devIDPtr
+	if cfg.SplitChannels {
+		// A card split into per-channel sources is captured with all its
+		// channels; the splitting dispatcher mixes each source's channels.
+		if nativeCh := nativeCaptureChannels(selectedInfo); nativeCh > 0 {
+			deviceCfg.Capture.Channels = nativeCh
+			cfg.Channels = int(nativeCh)
+		}
+	}
 	if cfg.SampleRate > conf.SampleRate {
 		deviceCfg.Capture.Format = malgo.FormatS32
 		if runtime.GOOS == captureOSLinux {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/tphakala/birdnet-go/internal/audiocore/buffer"
+	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
@@ -58,6 +59,11 @@ type DeviceConfig struct {
 
 	// Channels count (e.g., 1 for mono).
 	Channels int
+
+	// SplitChannels opens the device at its native channel count and
+	// delivers the interleaved channels instead of a mono downmix. Channels
+	// is then only requested when the device reports no native format.
+	SplitChannels bool
 }
 
 // ActiveDevice holds the runtime state for a running capture session.
@@ -77,6 +83,11 @@ type ActiveDevice struct {
 	// done is closed by the capture goroutine when it exits.
 	// StopCapture and Close wait on this channel to ensure graceful shutdown.
 	done chan struct{}
+
+	// sharedDevice is set for a source reading some channels of a shared
+	// card. Such a session has no goroutine of its own: cancel and done are
+	// nil and the card is stopped with its last subscriber.
+	sharedDevice string
 }
 
 // ErrDeviceAlreadyActive is returned when StartCapture is called with a
@@ -104,13 +115,32 @@ type DeviceManager struct {
 	// active maps sourceID to the running capture session.
 	active map[string]*ActiveDevice
 
-	// mu guards the active map.
+	// shared maps a device identifier to the card captured for the sources
+	// that select channels of it.
+	shared map[string]*sharedCapture
+
+	// mu guards the active and shared maps.
 	mu sync.RWMutex
 
+	// capture opens a device and starts its capture goroutine. It is
+	// startCapture, replaced in tests that run without audio hardware.
+	capture captureFunc
+
 	// log is the structured logger for this manager.
 	log logger.Logger
 }
 
+// captureFunc is the signature of startCapture.
+type captureFunc func(
+	ctx context.Context,
+	sourceID string,
+	deviceID string,
+	cfg DeviceConfig,
+	dispatcher AudioDispatcher,
+	bufMgr *buffer.Manager,
+	log logger.Logger,
+) (DeviceInfo, chan struct{}, error)
+
 // NewDeviceManager creates a DeviceManager that dispatches captured frames
 // to the given AudioDispatcher. When bufMgr is non-nil, the malgo capture
 // callback borrows its S16 conversion output from bufMgr's size-specific
@@ -121,6 +151,8 @@ func NewDeviceManager(dispatcher AudioDispatcher, bufMgr *buffer.Manager, log lo
 		dispatcher: dispatcher,
 		bufMgr:     bufMgr,
 		active:     make(map[string]*ActiveDevice),
+		shared:     make(map[string]*sharedCapture),
+		capture:    startCapture,
 		log:        log.With(logger.String("component", "device_manager")),
 	}
 }
@@ -143,7 +175,10 @@ func ListCaptureDevices() ([]DeviceInfo, error) {
 // dispatching AudioFrames tagged with sourceID to the manager's dispatcher.
 //
 // deviceID must match either the decoded device ID or a substring of the
-// device name (same matching rule as the legacy myaudio package).
+// device name (same matching rule as the legacy myaudio package). A deviceID
+// carrying a channel selection (see conf.DeviceChannelsConnection) captures
+// only those channels, mixed to mono; the card is opened once and shared by
+// every source selecting channels of it.
 //
 // Returns ErrDeviceAlreadyActive when the same sourceID is already running.
 func (dm *DeviceManager) StartCapture(sourceID, deviceID string, cfg DeviceConfig) error {
@@ -154,6 +189,13 @@ func (dm *DeviceManager) StartCapture(sourceID, deviceID string, cfg DeviceConfi
 		return fmt.Errorf("start capture %s: %w", sourceID, ErrDeviceAlreadyActive)
 	}
 
+	if device, channels, ok := conf.ParseDeviceChannels(deviceID); ok {
+		if err := dm.startSplitCapture(sourceID, device, channels, cfg); err != nil {
+			return fmt.Errorf("start capture for source %s: %w", sourceID, err)
+		}
+		return nil
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	ad := &ActiveDevice{
@@ -162,7 +204,7 @@ func (dm *DeviceManager) StartCapture(sourceID, deviceID string, cfg DeviceConfi
 		cancel:   cancel,
 	}
 
-	info, done, err := startCapture(ctx, sourceID, deviceID, cfg, dm.dispatcher, dm.bufMgr, dm.log)
+	info, done, err := dm.capture(ctx, sourceID, deviceID, cfg, dm.dispatcher, dm.bufMgr, dm.log)
 	if err != nil {
 		cancel()
 		return fmt.Errorf("start capture for source %s: %w", sourceID, err)
@@ -191,6 +233,17 @@ func (dm *DeviceManager) StopCapture(sourceID string) error {
 		return fmt.Errorf("stop capture %s: %w", sourceID, ErrDeviceNotActive)
 	}
 	delete(dm.active, sourceID)
+	if ad.sharedDevice != "" {
+		sc := dm.stopSplitCapture(ad)
+		dm.mu.Unlock()
+		if sc != nil {
+			sc.stop(dm.log)
+		}
+		dm.log.Info("channel capture stopped",
+			logger.String("source_id", sourceID),
+			logger.String("device", ad.Info.Name))
+		return nil
+	}
 	dm.mu.Unlock()
 
 	ad.cancel()
@@ -240,9 +293,17 @@ func (dm *DeviceManager) Close() error {
 	dm.mu.Lock()
 	active := dm.active
 	dm.active = make(map[string]*ActiveDevice)
+	shared := dm.shared
+	dm.shared = make(map[string]*sharedCapture)
 	dm.mu.Unlock()
 
+	for _, sc := range shared {
+		sc.stop(dm.log)
+	}
 	for _, ad := range active {
+		if ad.sharedDevice != "" {
+			continue
+		}
 		ad.cancel()
 		waitForDone(ad.done, deviceShutdownTimeout, dm.log, ad.sourceID)
 		dm.log.Info("capture stopped on close",
@@ -0,0 +1,264 @@
+// Package audiocore provides the core audio infrastructure for BirdNET-Go.
+// device_split.go — shared capture of multichannel sound cards split into
+// per-channel logical sources.
+package audiocore
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	"github.com/tphakala/birdnet-go/internal/audiocore/buffer"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// sharedCapture is one physical sound card opened for several logical
+// sources. The card is captured at its native channel count and the splitter
+// delivers each subscriber a mono mix of its own channels.
+type sharedCapture struct {
+	// device is the device identifier the card was opened with.
+	device string
+
+	// info describes the opened card.
+	info DeviceInfo
+
+	// cfg holds the capture parameters the card was opened with.
+	cfg DeviceConfig
+
+	// splitter fans the interleaved frames out to the subscribers.
+	splitter *channelSplitter
+
+	// cancel stops the capture goroutine for the card.
+	cancel context.CancelFunc
+
+	// done is closed by the capture goroutine when it exits.
+	done chan struct{}
+}
+
+// splitSubscriber is a logical source reading a subset of a card's channels.
+type splitSubscriber struct {
+	// sourceID tags the frames delivered to this subscriber.
+	sourceID string
+
+	// channels are the 0-based channel indices mixed into the mono output.
+	channels []int
+
+	// warned limits the missing-channel warning to once per subscriber.
+	warned atomic.Bool
+}
+
+// channelSplitter is the AudioDispatcher of a shared capture. It turns each
+// interleaved S16 frame into one mono frame per subscriber. The subscriber
+// list is replaced copy-on-write so the capture callback never takes a lock.
+type channelSplitter struct {
+	dispatcher  AudioDispatcher
+	bufMgr      *buffer.Manager
+	subscribers atomic.Pointer[[]*splitSubscriber]
+	log         logger.Logger
+}
+
+// newChannelSplitter creates a splitter without subscribers that forwards the
+// mono frames to dispatcher.
+func newChannelSplitter(dispatcher AudioDispatcher, bufMgr *buffer.Manager, log logger.Logger) *channelSplitter {
+	s := &channelSplitter{dispatcher: dispatcher, bufMgr: bufMgr, log: log}
+	s.subscribers.Store(&[]*splitSubscriber{})
+	return s
+}
+
+// add subscribes sourceID to the given 1-based channels. The caller
+// serializes add and remove.
+func (s *channelSplitter) add(sourceID string, channels []int) {
+	indices := make([]int, len(channels))
+	for i, ch := range channels {
+		indices[i] = ch - 1
+	}
+	subs := slices.Clone(*s.subscribers.Load())
+	subs = append(subs, &splitSubscriber{sourceID: sourceID, channels: indices})
+	s.subscribers.Store(&subs)
+}
+
+// maxChannel returns the highest 1-based channel any subscriber reads, or 0
+// without subscribers.
+func (s *channelSplitter) maxChannel() int {
+	highest := 0
+	for _, sub := range *s.subscribers.Load() {
+		highest = max(highest, slices.Max(sub.channels)+1)
+	}
+	return highest
+}
+
+// remove unsubscribes sourceID and returns the number of subscribers left.
+// The caller serializes add and remove.
+func (s *channelSplitter) remove(sourceID string) int {
+	subs := slices.DeleteFunc(slices.Clone(*s.subscribers.Load()), func(sub *splitSubscriber) bool {
+		return sub.sourceID == sourceID
+	})
+	s.subscribers.Store(&subs)
+	return len(subs)
+}
+
+// Dispatch implements AudioDispatcher. The input frame is only read during
+// the call, so the capture callback may release it afterwards.
+func (s *channelSplitter) Dispatch(frame AudioFrame) { //nolint:gocritic // hugeParam: signature required by AudioDispatcher interface
+	if frame.BitDepth != 16 || frame.Channels < 1 {
+		return
+	}
+	samples := len(frame.Data) / (2 * frame.Channels)
+	if samples == 0 {
+		return
+	}
+
+	for _, sub := range *s.subscribers.Load() {
+		if slices.Max(sub.channels) >= frame.Channels {
+			if !sub.warned.Swap(true) {
+				s.log.Warn("capture device delivers fewer channels than selected, source receives no audio",
+					logger.String("source_id", sub.sourceID),
+					logger.Int("device_channels", frame.Channels),
+					logger.Int("selected_channel", slices.Max(sub.channels)+1))
+			}
+			continue
+		}
+
+		out, ref := s.outputBuffer(samples * 2)
+		mixChannels(out, frame.Data, frame.Channels, sub.channels)
+
+		mono := frame
+		mono.SourceID = sub.sourceID
+		mono.Data = out
+		mono.Channels = 1
+		mono.Ref = ref
+		s.dispatcher.Dispatch(mono)
+		ref.Release()
+	}
+}
+
+// outputBuffer returns a size byte slice for a mono frame, borrowed from the
+// buffer manager's pool when one is wired, together with its FrameRef.
+func (s *channelSplitter) outputBuffer(size int) ([]byte, *FrameRef) {
+	if s.bufMgr != nil {
+		if pool := s.bufMgr.BytePoolFor(size); pool != nil {
+			out := pool.Get()
+			return out, NewFrameRef(func() { pool.Put(out) })
+		}
+	}
+	return make([]byte, size), nil
+}
+
+// mixChannels writes the average of the selected 0-based channels of the
+// interleaved S16 data into dst, one mono sample per input frame. dst must
+// hold len(data)/channels bytes.
+func mixChannels(dst, data []byte, channels int, selected []int) {
+	frameBytes := 2 * channels
+	n := int32(len(selected)) //nolint:gosec // G115: a source selects at most a few dozen channels
+	for i := range len(dst) / 2 {
+		base := i * frameBytes
+		var sum int32
+		for _, ch := range selected {
+			off := base + 2*ch
+			sum += int32(int16(binary.LittleEndian.Uint16(data[off : off+2]))) //nolint:gosec // G115: 16-bit reinterpretation
+		}
+		binary.LittleEndian.PutUint16(dst[2*i:2*i+2], uint16(int16(sum/n))) //nolint:gosec // G115: average stays in 16-bit range
+	}
+}
+
+// startSplitCapture subscribes sourceID to the given channels of device,
+// opening the card when no other source captures from it. Must be called
+// with dm.mu held.
+//
+// Sources sharing a card are validated to share a sample rate. When a
+// subscriber still asks for another rate, which happens while the sources of
+// a card are reconfigured one after the other, the card is reopened at the
+// new rate for all of them.
+func (dm *DeviceManager) startSplitCapture(sourceID, device string, channels []int, cfg DeviceConfig) error {
+	sc, exists := dm.shared[device]
+	minChannels := slices.Max(channels)
+	if exists && sc.cfg.SampleRate != cfg.SampleRate {
+		dm.log.Warn("reopening shared capture device at new sample rate",
+			logger.String("source_id", sourceID),
+			logger.String("device", sc.info.Name),
+			logger.Int("old_sample_rate", sc.cfg.SampleRate),
+			logger.Int("new_sample_rate", cfg.SampleRate))
+		sc.cancel()
+		waitForDone(sc.done, deviceShutdownTimeout, dm.log, sourceID)
+		delete(dm.shared, device)
+		if err := dm.openSharedCapture(sourceID, sc, cfg, max(minChannels, sc.splitter.maxChannel())); err != nil {
+			return err
+		}
+	}
+	if !exists {
+		sc = &sharedCapture{
+			device:   device,
+			splitter: newChannelSplitter(dm.dispatcher, dm.bufMgr, dm.log),
+		}
+		if err := dm.openSharedCapture(sourceID, sc, cfg, minChannels); err != nil {
+			return err
+		}
+	}
+
+	sc.splitter.add(sourceID, channels)
+	dm.active[sourceID] = &ActiveDevice{
+		Info:         sc.info,
+		Config:       cfg,
+		sourceID:     sourceID,
+		sharedDevice: device,
+	}
+
+	dm.log.Info("channel capture started",
+		logger.String("source_id", sourceID),
+		logger.String("device", sc.info.Name),
+		logger.String("device_id", redactDeviceID(device)),
+		logger.String("channels", fmt.Sprint(channels)))
+	return nil
+}
+
+// openSharedCapture opens the card of sc at its native channel count with
+// the sample rate and bit depth of cfg, and registers sc in dm.shared.
+// minChannels is requested instead when the card reports no native format.
+// Must be called with dm.mu held.
+func (dm *DeviceManager) openSharedCapture(sourceID string, sc *sharedCapture, cfg DeviceConfig, minChannels int) error {
+	cfg.Channels = minChannels
+	cfg.SplitChannels = true
+	ctx, cancel := context.WithCancel(context.Background())
+	info, done, err := dm.capture(ctx, sourceID, sc.device, cfg, sc.splitter, dm.bufMgr, dm.log)
+	if err != nil {
+		cancel()
+		return errors.New(err).
+			Component("audiocore").
+			Category(errors.CategoryAudioSource).
+			Context("operation", "start_shared_capture").
+			Context("source_id", sourceID).
+			Build()
+	}
+	sc.info = info
+	sc.cfg = cfg
+	sc.cancel = cancel
+	sc.done = done
+	dm.shared[sc.device] = sc
+	return nil
+}
+
+// stopSplitCapture unsubscribes a logical source from its shared card. It
+// returns the shared capture to stop when no subscriber is left, or nil.
+// Must be called with dm.mu held.
+func (dm *DeviceManager) stopSplitCapture(ad *ActiveDevice) *sharedCapture {
+	sc, ok := dm.shared[ad.sharedDevice]
+	if !ok {
+		return nil
+	}
+	if sc.splitter.remove(ad.sourceID) > 0 {
+		return nil
+	}
+	delete(dm.shared, ad.sharedDevice)
+	return sc
+}
+
+// stop cancels the capture goroutine of the card and waits for it to exit.
+func (sc *sharedCapture) stop(log logger.Logger) {
+	sc.cancel()
+	waitForDone(sc.done, deviceShutdownTimeout, log, sc.device)
+	log.Info("shared capture stopped",
+		logger.String("device", sc.info.Name))
+}
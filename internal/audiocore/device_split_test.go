@@ -0,0 +1,154 @@
+package audiocore
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/audiocore/buffer"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// interleavedS16 builds an interleaved S16 frame from per-sample channel values.
+func interleavedS16(frames ...[]int16) []byte {
+	var data []byte
+	for _, frame := range frames {
+		for _, v := range frame {
+			data = binary.LittleEndian.AppendUint16(data, uint16(v)) //nolint:gosec // G115: test data
+		}
+	}
+	return data
+}
+
+// monoS16 decodes mono S16 samples.
+func monoS16(data []byte) []int16 {
+	out := make([]int16, len(data)/2)
+	for i := range out {
+		out[i] = int16(binary.LittleEndian.Uint16(data[2*i:])) //nolint:gosec // G115: test data
+	}
+	return out
+}
+
+func TestChannelSplitter_Dispatch(t *testing.T) {
+	t.Parallel()
+
+	disp := &mockDispatcher{}
+	s := newChannelSplitter(disp, nil, logger.Global().Module("test_device_split"))
+	s.add("pond", []int{1})
+	s.add("forest", []int{3, 4})
+	s.add("missing", []int{5})
+
+	s.Dispatch(AudioFrame{
+		SourceID:   "card",
+		SampleRate: 48000,
+		BitDepth:   16,
+		Channels:   4,
+		Data: interleavedS16(
+			[]int16{100, -1, 1000, 3000},
+			[]int16{-200, -1, -32768, -32768},
+		),
+	})
+
+	require.Len(t, disp.frames, 2, "a subscriber reading a channel the card lacks gets nothing")
+	assert.Equal(t, "pond", disp.frames[0].SourceID)
+	assert.Equal(t, 1, disp.frames[0].Channels)
+	assert.Equal(t, 48000, disp.frames[0].SampleRate)
+	assert.Equal(t, []int16{100, -200}, monoS16(disp.frames[0].Data))
+
+	assert.Equal(t, "forest", disp.frames[1].SourceID)
+	assert.Equal(t, []int16{2000, -32768}, monoS16(disp.frames[1].Data))
+
+	assert.Equal(t, 2, s.remove("missing"))
+	assert.Equal(t, 4, s.maxChannel())
+}
+
+func TestChannelSplitter_PooledFramesReleased(t *testing.T) {
+	t.Parallel()
+
+	bufMgr := buffer.NewManager(logger.Global().Module("test_device_split"))
+	disp := &mockDispatcher{}
+	s := newChannelSplitter(disp, bufMgr, logger.Global().Module("test_device_split"))
+	s.add("pond", []int{2})
+
+	s.Dispatch(AudioFrame{BitDepth: 16, Channels: 2, Data: interleavedS16([]int16{1, 2}, []int16{3, 4})})
+
+	require.Len(t, disp.frames, 1)
+	assert.Equal(t, []int16{2, 4}, monoS16(disp.frames[0].Data))
+	require.NotNil(t, disp.frames[0].Ref)
+	// The dispatcher did not retain the frame, so the splitter's own release
+	// returned the slice to the pool.
+	assert.Zero(t, disp.frames[0].Ref.remaining.Load())
+}
+
+// fakeCapture stands in for startCapture and records the opened devices.
+type fakeCapture struct {
+	mu      sync.Mutex
+	opened  []DeviceConfig
+	running int
+}
+
+func (f *fakeCapture) start(ctx context.Context, _, deviceID string, cfg DeviceConfig, _ AudioDispatcher, _ *buffer.Manager, _ logger.Logger) (DeviceInfo, chan struct{}, error) {
+	f.mu.Lock()
+	f.opened = append(f.opened, cfg)
+	f.running++
+	f.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		f.running--
+		f.mu.Unlock()
+		close(done)
+	}()
+	return DeviceInfo{Name: "USB interface", ID: deviceID}, done, nil
+}
+
+func (f *fakeCapture) state() (opened []DeviceConfig, running int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]DeviceConfig(nil), f.opened...), f.running
+}
+
+func TestDeviceManager_SharedChannelCapture(t *testing.T) {
+	t.Parallel()
+
+	dm, _ := newTestDeviceManager(t)
+	fake := &fakeCapture{}
+	dm.capture = fake.start
+	t.Cleanup(func() { _ = dm.Close() })
+
+	cfg := defaultTestConfig()
+	require.NoError(t, dm.StartCapture("pond", "hw:1,0#ch=1", cfg))
+	require.NoError(t, dm.StartCapture("forest", "hw:1,0#ch=3+4", cfg))
+
+	opened, running := fake.state()
+	require.Len(t, opened, 1, "the card is opened once for both sources")
+	assert.Equal(t, 1, running)
+	assert.True(t, opened[0].SplitChannels)
+	assert.Equal(t, 1, opened[0].Channels, "fallback channel request covers the first selection")
+	assert.Len(t, dm.ActiveDevices(), 2)
+
+	// Reconfiguring one source to another rate reopens the card for both.
+	require.NoError(t, dm.StopCapture("pond"))
+	cfg.SampleRate = 96000
+	require.NoError(t, dm.StartCapture("pond", "hw:1,0#ch=1", cfg))
+	opened, running = fake.state()
+	require.Len(t, opened, 2)
+	assert.Equal(t, 96000, opened[1].SampleRate)
+	assert.Equal(t, 4, opened[1].Channels, "reopen requests the channels of every subscriber")
+	assert.Equal(t, 1, running)
+
+	require.NoError(t, dm.StopCapture("pond"))
+	_, running = fake.state()
+	assert.Equal(t, 1, running, "the card keeps running for the remaining source")
+
+	require.NoError(t, dm.StopCapture("forest"))
+	_, running = fake.state()
+	assert.Zero(t, running, "the card stops with its last source")
+	assert.Empty(t, dm.ActiveDevices())
+}
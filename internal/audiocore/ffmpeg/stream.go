@@ -729,6 +729,7 @@ func (s *Stream) Run(parentCtx context.Context) {
 					Properties: map[string]any{
 						alerting.PropertyStreamName: s.config.SourceName,
 						alerting.PropertyStreamURL:  s.config.safeURL(),
+						alerting.PropertySourceID:   s.config.SourceID,
 						alerting.PropertyError:      err.Error(),
 					},
 				})
@@ -790,6 +791,7 @@ func (s *Stream) Run(parentCtx context.Context) {
 					Properties: map[string]any{
 						alerting.PropertyStreamName: s.config.SourceName,
 						alerting.PropertyStreamURL:  s.config.safeURL(),
+						alerting.PropertySourceID:   s.config.SourceID,
 						alerting.PropertyError:      sanitizedError,
 					},
 				})
@@ -111,6 +111,15 @@ type QuietHoursScheduler struct {
 	// Sound card suppression state.
 	soundCardSuppressed bool
 
+	// paused holds the connection strings (stream URL or sound card device) of
+	// sources paused at runtime. A paused source is stopped as if it were in
+	// quiet hours, whether or not quiet hours are configured for it.
+	paused map[string]bool
+
+	// quietHoursSuspended disables every quiet hours window at runtime without
+	// changing the configuration. Manually paused sources stay paused.
+	quietHoursSuspended atomic.Bool
+
 	// stopped is set to 1 when Stop() is called. Evaluate() checks this
 	// before sending on controlChan to avoid sending on a closed channel
 	// during shutdown.
@@ -135,6 +144,7 @@ func NewQuietHoursScheduler(cfg QuietHoursConfig) *QuietHoursScheduler {
 		log:            log,
 		suppressed:     make(map[string]bool),
 		suppressedURLs: make(map[string]string),
+		paused:         make(map[string]bool),
 	}
 }
 
@@ -244,20 +254,9 @@ func (s *QuietHoursScheduler) Evaluate() {
 		}
 		suppressedSourceID, isSuppressed := s.suppressedURLs[stream.URL]
 
-		if !stream.QuietHours.Enabled {
-			if isSuppressed {
-				actions = append(actions, streamAction{
-					sourceID:  suppressedSourceID,
-					url:       stream.URL,
-					name:      stream.Name,
-					transport: stream.Transport,
-					stop:      false,
-				})
-			}
-			continue
-		}
-
-		inQuietHours := s.isInQuietHours(&stream.QuietHours, now)
+		// Disabled quiet hours are never in their window, so a suppressed
+		// stream whose quiet hours were turned off is restarted below.
+		inQuietHours := s.paused[stream.URL] || s.isInQuietHours(&stream.QuietHours, now)
 
 		if inQuietHours && isActive && !s.suppressed[activeSourceID] {
 			actions = append(actions, streamAction{
@@ -377,11 +376,12 @@ func (s *QuietHoursScheduler) evaluateSoundCardQuietHours(settings *conf.Setting
 	anyInQuietHours := false
 	for i := range settings.Realtime.Audio.Sources {
 		src := &settings.Realtime.Audio.Sources[i]
-		if src.Device == "" || !src.QuietHours.Enabled {
+		paused := s.paused[src.ConnectionString()]
+		if src.Device == "" || (!src.QuietHours.Enabled && !paused) {
 			continue
 		}
 		anyPerSourceQuietHours = true
-		if s.isInQuietHours(&src.QuietHours, now) {
+		if paused || s.isInQuietHours(&src.QuietHours, now) {
 			anyInQuietHours = true
 			break
 		}
@@ -414,7 +414,7 @@ func (s *QuietHoursScheduler) evaluateSoundCardQuietHours(settings *conf.Setting
 
 // isInQuietHours determines whether the given time falls within the quiet hours window.
 func (s *QuietHoursScheduler) isInQuietHours(qh *conf.QuietHoursConfig, now time.Time) bool {
-	if !qh.Enabled {
+	if !qh.Enabled || s.quietHoursSuspended.Load() {
 		return false
 	}
 
@@ -501,6 +501,50 @@ func isTimeInWindow(now, start, end time.Time) bool {
 	return nowMinutes >= startMinutes || nowMinutes < endMinutes
 }
 
+// PauseSource stops the source with the given connection string (stream URL or
+// sound card device) until ResumeSource is called. Pausing a sound card device
+// stops sound card capture, which is shared by all sound card sources. The pause
+// is runtime state and does not survive a restart.
+func (s *QuietHoursScheduler) PauseSource(connection string) {
+	s.mu.Lock()
+	if s.paused == nil {
+		s.paused = make(map[string]bool)
+	}
+	s.paused[connection] = true
+	s.mu.Unlock()
+	s.Evaluate()
+}
+
+// ResumeSource lifts a pause set by PauseSource. The source restarts unless it
+// is in its quiet hours.
+func (s *QuietHoursScheduler) ResumeSource(connection string) {
+	s.mu.Lock()
+	delete(s.paused, connection)
+	s.mu.Unlock()
+	s.Evaluate()
+}
+
+// IsSourcePaused reports whether the source with the given connection string
+// was paused by PauseSource.
+func (s *QuietHoursScheduler) IsSourcePaused(connection string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused[connection]
+}
+
+// SetQuietHoursSuspended suspends or re-enables all quiet hours windows and
+// re-evaluates immediately. Suspended quiet hours restart the sources they
+// stopped; the configuration is left untouched.
+func (s *QuietHoursScheduler) SetQuietHoursSuspended(suspended bool) {
+	s.quietHoursSuspended.Store(suspended)
+	s.Evaluate()
+}
+
+// QuietHoursSuspended reports whether quiet hours are suspended.
+func (s *QuietHoursScheduler) QuietHoursSuspended() bool {
+	return s.quietHoursSuspended.Load()
+}
+
 // IsSoundCardSuppressed returns whether the sound card is currently suppressed by quiet hours.
 func (s *QuietHoursScheduler) IsSoundCardSuppressed() bool {
 	s.mu.Lock()
@@ -597,3 +597,84 @@ func TestGetSuppressedStreams(t *testing.T) {
 	assert.Equal(t, map[string]bool{"rtsp://cam1": true}, result,
 		"should only include suppressed streams")
 }
+
+// --- Runtime pause and suspend ---
+
+func TestPauseSource_Stream(t *testing.T) {
+	const sourceID = "rtsp_abc123"
+	mock := &mockManager{
+		activeStreams: []string{sourceID},
+		streamURLs:    map[string]string{sourceID: "rtsp://cam1"},
+	}
+
+	settings := conftest.GetTestSettings()
+	settings.Realtime.RTSP.Streams = []conf.StreamConfig{
+		{Name: "cam1", URL: "rtsp://cam1", Enabled: true, Transport: "tcp"},
+	}
+	setTestSettings(t, settings)
+
+	s := newTestScheduler(t, mock)
+	s.PauseSource("rtsp://cam1")
+
+	assert.True(t, s.IsSourcePaused("rtsp://cam1"))
+	assert.Equal(t, []string{sourceID}, mock.stopped, "a paused stream is stopped without quiet hours configured")
+
+	mock.activeStreams = nil
+	mock.streamURLs = nil
+	s.ResumeSource("rtsp://cam1")
+
+	assert.False(t, s.IsSourcePaused("rtsp://cam1"))
+	require.Len(t, mock.started, 1, "a resumed stream is restarted")
+	assert.Equal(t, sourceID, mock.started[0].sourceID)
+	assert.Equal(t, "tcp", mock.started[0].transport)
+}
+
+func TestPauseSource_SoundCard(t *testing.T) {
+	mock := &mockManager{activeStreams: []string{}}
+
+	settings := conftest.GetTestSettings()
+	settings.Realtime.Audio.Sources = []conf.AudioSourceConfig{{Name: "Test Sound Card", Device: "default"}}
+	setTestSettings(t, settings)
+
+	s := newTestScheduler(t, mock)
+	s.PauseSource("default")
+	require.Len(t, s.controlChan, 1)
+	assert.Equal(t, SignalQuietHoursStopSoundCard, <-s.controlChan)
+
+	s.ResumeSource("default")
+	require.Len(t, s.controlChan, 1)
+	assert.Equal(t, SignalQuietHoursStartSoundCard, <-s.controlChan)
+}
+
+func TestSetQuietHoursSuspended(t *testing.T) {
+	const sourceID = "rtsp_abc123"
+	mock := &mockManager{activeStreams: []string{}}
+
+	settings := conftest.GetTestSettings()
+	settings.Realtime.RTSP.Streams = []conf.StreamConfig{
+		{
+			Name: "cam1", URL: "rtsp://cam1", Enabled: true, Transport: "tcp",
+			QuietHours: conf.QuietHoursConfig{
+				Enabled:   true,
+				Mode:      "fixed",
+				StartTime: "00:00",
+				EndTime:   "23:59", // always in quiet hours
+			},
+		},
+	}
+	setTestSettings(t, settings)
+
+	s := newTestScheduler(t, mock)
+	s.suppressed[sourceID] = true
+	s.suppressedURLs["rtsp://cam1"] = sourceID
+
+	s.SetQuietHoursSuspended(true)
+	assert.True(t, s.QuietHoursSuspended())
+	require.Len(t, mock.started, 1, "suspending quiet hours restarts suppressed streams")
+	assert.False(t, s.isInQuietHours(&settings.Realtime.RTSP.Streams[0].QuietHours, time.Now()))
+
+	mock.activeStreams = []string{sourceID}
+	mock.streamURLs = map[string]string{sourceID: "rtsp://cam1"}
+	s.PauseSource("rtsp://cam1")
+	assert.Equal(t, []string{sourceID}, mock.stopped, "a manual pause still applies while quiet hours are suspended")
+}
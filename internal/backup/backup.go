@@ -44,6 +44,27 @@ type Source interface {
 	Validate() error
 }
 
+// TypedSource is implemented by sources that report the kind of data they
+// back up ("sqlite", "mysql", "config", "clips"). The type is recorded in the
+// backup metadata and selects the retention policy; sources without it are
+// typed by their name.
+type TypedSource interface {
+	Type() string
+}
+
+// IncrementalSource is implemented by sources that back up only the data
+// added since their last successful backup.
+type IncrementalSource interface {
+	Source
+	// BackupSince streams the data added after since. It also returns the
+	// cutoff the backup covers, recorded as the next since once every target
+	// has stored the backup. It returns ErrNoChanges when there is nothing new.
+	BackupSince(ctx context.Context, since time.Time) (io.ReadCloser, time.Time, error)
+}
+
+// ErrNoChanges is returned by an IncrementalSource with nothing to back up
+var ErrNoChanges = errors.NewStd("no changes since the last backup")
+
 // Target represents a destination where backups are stored
 type Target interface {
 	// Name returns the name of the target
@@ -64,16 +85,20 @@ type Metadata struct {
 	ID           string    `json:"id"`                      // Unique identifier for the backup
 	Timestamp    time.Time `json:"timestamp"`               // When the backup was created
 	Size         int64     `json:"size"`                    // Size of the backup in bytes
-	Type         string    `json:"type"`                    // Type of backup (e.g., "sqlite", "mysql")
+	Type         string    `json:"type"`                    // Type of backup (e.g., "sqlite", "mysql", "postgres")
 	Source       string    `json:"source"`                  // Source of the backup (e.g., database name)
 	IsDaily      bool      `json:"is_daily"`                // Whether this is a daily backup
 	IsWeekly     bool      `json:"is_weekly,omitempty"`     // Whether this is a weekly backup
 	ConfigHash   string    `json:"config_hash"`             // Hash of the configuration file (for verification)
 	AppVersion   string    `json:"app_version"`             // Version of the application that created the backup
-	Checksum     string    `json:"checksum,omitempty"`      // File checksum if available
+	Checksum     string    `json:"checksum,omitempty"`      // SHA-256 of the stored archive file (possibly encrypted)
+	DataChecksum string    `json:"data_checksum,omitempty"` // SHA-256 of the backup data inside the archive
 	Compressed   bool      `json:"compressed,omitempty"`    // Whether the backup is compressed
 	Encrypted    bool      `json:"encrypted,omitempty"`     // Whether the backup is encrypted
+	KeyID        string    `json:"key_id,omitempty"`        // ID of the encryption key, for restoring after key rotation
 	OriginalSize int64     `json:"original_size,omitempty"` // Original size before compression/encryption
+	Incremental  bool      `json:"incremental,omitempty"`   // Whether the backup holds only data added since the previous one
+	Since        time.Time `json:"since,omitzero"`          // Start of the period an incremental backup covers, zero for the first one
 }
 
 // BackupInfo represents information about a stored backup
@@ -131,8 +156,8 @@ type BackupStats struct {
 	LastBackupTime   time.Time // Time of the last backup operation
 }
 
-// sanitizeConfig creates a copy of the configuration with sensitive data removed
-func sanitizeConfig(config *conf.Settings) *conf.Settings {
+// SanitizeConfig creates a copy of the configuration with sensitive data removed
+func SanitizeConfig(config *conf.Settings) *conf.Settings {
 	// Create a deep copy of the config using JSON serialization
 	// This ensures all nested structures are properly duplicated
 	jsonData, err := json.Marshal(config)
@@ -158,12 +183,33 @@ func sanitizeConfig(config *conf.Settings) *conf.Settings {
 	sanitized.Security.GithubAuth.ClientSecret = ""
 	sanitized.Security.SessionSecret = ""
 	sanitized.Output.MySQL.Password = ""
+	sanitized.Output.Postgres.Password = ""
 	sanitized.Realtime.MQTT.Password = ""
 	sanitized.Realtime.Weather.OpenWeather.APIKey = ""
 
 	return &sanitized
 }
 
+// restoreSecrets copies the fields cleared by SanitizeConfig from the current
+// configuration into a restored one, so restoring a backup does not wipe
+// credentials. Secrets the restored configuration does carry are kept.
+func restoreSecrets(restored, current *conf.Settings) {
+	keep := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	keep(&restored.Security.BasicAuth.Password, current.Security.BasicAuth.Password)
+	keep(&restored.Security.BasicAuth.ClientSecret, current.Security.BasicAuth.ClientSecret)
+	keep(&restored.Security.GoogleAuth.ClientSecret, current.Security.GoogleAuth.ClientSecret)
+	keep(&restored.Security.GithubAuth.ClientSecret, current.Security.GithubAuth.ClientSecret)
+	keep(&restored.Security.SessionSecret, current.Security.SessionSecret)
+	keep(&restored.Output.MySQL.Password, current.Output.MySQL.Password)
+	keep(&restored.Output.Postgres.Password, current.Output.Postgres.Password)
+	keep(&restored.Realtime.MQTT.Password, current.Realtime.MQTT.Password)
+	keep(&restored.Realtime.Weather.OpenWeather.APIKey, current.Realtime.Weather.OpenWeather.APIKey)
+}
+
 // Manager handles the backup operations
 type Manager struct {
 	config       *conf.BackupConfig
@@ -174,6 +220,7 @@ type Manager struct {
 	logger       logger.Logger // Use centralized logger
 	stateManager *StateManager
 	appVersion   string // Store app version
+	keyPath      string // Encryption key file, empty for the default location
 }
 
 // NewManager creates a new backup manager
@@ -417,9 +464,28 @@ func (m *Manager) RunBackup(ctx context.Context) error {
 func (m *Manager) processBackupSource(ctx context.Context, sourceName string, source Source, timestamp time.Time, isDaily, isWeekly bool) ([]string, error) {
 	tempDirs := make([]string, 0, 1) // Track temp dirs created in this function
 
-	// 1. Perform the actual backup from the source
+	// 1. Perform the actual backup from the source. Incremental sources only
+	// stream what was added since their last successful backup.
 	m.logger.Debug("Starting source backup", logger.String("source_name", sourceName))
-	backupReader, err := source.Backup(ctx)
+	var (
+		backupReader io.ReadCloser
+		since        time.Time
+		cutoff       time.Time
+		err          error
+	)
+	incremental, isIncremental := source.(IncrementalSource)
+	if isIncremental {
+		if m.stateManager != nil {
+			since = m.stateManager.GetSourceState(sourceName).LastSuccessful
+		}
+		backupReader, cutoff, err = incremental.BackupSince(ctx, since)
+	} else {
+		backupReader, err = source.Backup(ctx)
+	}
+	if errors.Is(err, ErrNoChanges) {
+		m.logger.Info("Nothing to back up since the last backup", logger.String("source_name", sourceName), logger.Time("since", since))
+		return tempDirs, nil
+	}
 	if err != nil {
 		return tempDirs, errors.New(err).
 			Component("backup").
@@ -449,15 +515,17 @@ func (m *Manager) processBackupSource(ctx context.Context, sourceName string, so
 
 	// 3. Prepare metadata
 	metadata := &Metadata{
-		Version:    1, // Current metadata version
-		ID:         fmt.Sprintf("%s-%s", sourceName, timestamp.Format("20060102-150405")),
-		Timestamp:  timestamp,
-		Type:       sourceName, // Assuming source name is the type for now
-		Source:     sourceName,
-		IsDaily:    isDaily,
-		IsWeekly:   isWeekly, // Add weekly flag
-		AppVersion: m.appVersion,
-		Encrypted:  m.config.Encryption,
+		Version:     1, // Current metadata version
+		ID:          fmt.Sprintf("%s-%s", sourceName, timestamp.Format("20060102-150405")),
+		Timestamp:   timestamp,
+		Type:        sourceType(sourceName, source),
+		Source:      sourceName,
+		IsDaily:     isDaily,
+		IsWeekly:    isWeekly, // Add weekly flag
+		AppVersion:  m.appVersion,
+		Encrypted:   m.config.Encryption,
+		Incremental: isIncremental,
+		Since:       since,
 		// Size and checksum will be calculated later
 	}
 
@@ -469,17 +537,21 @@ func (m *Manager) processBackupSource(ctx context.Context, sourceName string, so
 		metadata.ConfigHash = configHash
 	}
 
-	// 4. Create the archive file path
+	// 4. Create the archive file path. Encrypted archives are encrypted as
+	// they are written and the source data is streamed straight into the
+	// archive, so no plaintext copy of the archive or the data is stored.
 	archiveFileName := metadata.ID + ".tar"
 	// Compression logic removed as it's not in BackupConfig
 	// if m.config.Compression {
 	//  archiveFileName += ".gz"
 	// }
-	// Note: Encryption happens *after* archiving/compression, file extension doesn't change yet.
+	if metadata.Encrypted {
+		archiveFileName += ".enc" // Convention for encrypted file
+	}
 	archivePath := filepath.Join(tempDir, archiveFileName)
 	m.logger.Debug("Prepared archive details", logger.String("source_name", sourceName), logger.String("archive_path", archivePath))
 
-	// 5. Create and populate the archive
+	// 5. Create and populate the archive, encrypting it if enabled
 	if err := m.createArchive(ctx, archivePath, backupReader, metadata); err != nil {
 		return tempDirs, errors.New(err).
 			Component("backup").
@@ -489,32 +561,8 @@ func (m *Manager) processBackupSource(ctx context.Context, sourceName string, so
 	}
 	m.logger.Debug("Archive created successfully", logger.String("source_name", sourceName), logger.String("archive_path", archivePath))
 
-	// 6. Optionally encrypt the archive
-	finalArchivePath := archivePath
-	if m.config.Encryption {
-		m.logger.Debug("Starting encryption", logger.String("source_name", sourceName), logger.String("archive_path", archivePath))
-		encryptedArchivePath := archivePath + ".enc" // Convention for encrypted file
-		err := m.encryptArchive(ctx, archivePath, encryptedArchivePath)
-		if err != nil {
-			return tempDirs, errors.New(err).
-				Component("backup").
-				Category(errors.CategorySystem).
-				Context("operation", "encrypt_archive").
-				Build()
-		}
-		// Clean up the unencrypted archive
-		if err := os.Remove(archivePath); err != nil {
-			m.logger.Warn("Failed to remove unencrypted archive after encryption", logger.String("path", archivePath), logger.Error(err))
-		} else {
-			m.logger.Debug("Removed unencrypted archive", logger.String("path", archivePath))
-		}
-		finalArchivePath = encryptedArchivePath
-		metadata.Encrypted = true // Ensure metadata reflects encryption status
-		m.logger.Debug("Encryption completed", logger.String("source_name", sourceName), logger.String("encrypted_path", finalArchivePath))
-	}
-
-	// 7. Update metadata with final size and checksum (of the final file, possibly encrypted)
-	fileInfo, err := os.Stat(finalArchivePath)
+	// 6. Update metadata with final size and checksum (of the final file, possibly encrypted)
+	fileInfo, err := os.Stat(archivePath)
 	if err != nil {
 		return tempDirs, errors.New(err).
 			Component("backup").
@@ -526,16 +574,19 @@ func (m *Manager) processBackupSource(ctx context.Context, sourceName string, so
 	metadata.Size = fileInfo.Size()
 	m.logger.Debug("Updated metadata with final size", logger.String("source_name", sourceName), logger.Int64("size", metadata.Size))
 
-	// Calculate checksum if needed (optional, can be time-consuming)
-	// checksum, err := calculateChecksum(finalArchivePath)
-	// if err == nil {
-	//     metadata.Checksum = checksum
-	// } else {
-	//     m.logger.Warn("Failed to calculate checksum", "path", finalArchivePath, "error", err)
-	// }
+	// Checksum the stored file so a restore can detect a damaged download
+	checksum, err := fileChecksum(archivePath)
+	if err != nil {
+		return tempDirs, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "checksum_archive_file").
+			Build()
+	}
+	metadata.Checksum = checksum
 
-	// 8. Store the final archive in all registered targets
-	if err := m.storeBackupInTargets(ctx, finalArchivePath, metadata); err != nil {
+	// 7. Store the final archive in all registered targets
+	if err := m.storeBackupInTargets(ctx, archivePath, metadata); err != nil {
 		return tempDirs, errors.New(err).
 			Component("backup").
 			Category(errors.CategorySystem).
@@ -543,13 +594,28 @@ func (m *Manager) processBackupSource(ctx context.Context, sourceName string, so
 			Build()
 	}
 
+	// 8. Remember how far an incremental backup got, so the next one starts there
+	if isIncremental && m.stateManager != nil {
+		if err := m.stateManager.UpdateSourceState(sourceName, metadata, cutoff); err != nil {
+			m.logger.Warn("Failed to record incremental backup state", logger.String("source_name", sourceName), logger.Error(err))
+		}
+	}
+
 	m.logger.Debug("Finished processing source", logger.String("source_name", sourceName))
 	return tempDirs, nil // Return tempDirs for cleanup by the caller
 }
 
+// sourceType returns the type recorded for backups of a source
+func sourceType(sourceName string, source Source) string {
+	if typed, ok := source.(TypedSource); ok {
+		return typed.Type()
+	}
+	return sourceName
+}
+
 // hashConfig calculates the SHA256 hash of the sanitized configuration
 func (m *Manager) hashConfig() (string, error) {
-	sanitizedConf := sanitizeConfig(m.fullConfig) // Sanitize the full config
+	sanitizedConf := SanitizeConfig(m.fullConfig) // Sanitize the full config
 
 	// Marshal the sanitized config to YAML (or JSON, ensure consistency)
 	yamlBytes, err := yaml.Marshal(sanitizedConf)
@@ -570,7 +636,7 @@ func (m *Manager) addConfigToArchive(tw *tar.Writer, metadata *Metadata) error {
 	m.logger.Debug("Adding sanitized config to archive", logger.String("backup_id", metadata.ID))
 	start := time.Now()
 
-	sanitizedConf := sanitizeConfig(m.fullConfig) // Sanitize the full config
+	sanitizedConf := SanitizeConfig(m.fullConfig) // Sanitize the full config
 
 	// Marshal the sanitized config to YAML
 	yamlBytes, err := yaml.Marshal(sanitizedConf)
@@ -584,7 +650,7 @@ func (m *Manager) addConfigToArchive(tw *tar.Writer, metadata *Metadata) error {
 
 	// Create TAR header
 	hdr := &tar.Header{
-		Name:    "config.yml", // Standard name within the archive
+		Name:    configEntryName, // Standard name within the archive
 		Size:    int64(len(yamlBytes)),
 		Mode:    int64(PermArchiveFile), // Read-only permissions
 		ModTime: metadata.Timestamp,
@@ -739,8 +805,27 @@ func (m *Manager) createArchive(ctx context.Context, archivePath string, reader
 		}
 	}()
 
-	// Determine writer: plain tar or gzipped tar
+	// Determine writer: plain tar or encrypted tar. The plaintext size is
+	// counted before encryption.
 	var fileWriter io.WriteCloser = archiveFile
+	if metadata.Encrypted {
+		key, err := m.getEncryptionKey()
+		if err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategorySystem).
+				Context("operation", "get_encryption_key").
+				Build()
+		}
+		encWriter, err := newEncryptWriter(archiveFile, key)
+		if err != nil {
+			return err
+		}
+		fileWriter = encWriter
+		metadata.KeyID = encryptionKeyID(key)
+		m.logger.Debug("Encrypting archive", logger.String("backup_id", metadata.ID), logger.String("key_id", metadata.KeyID))
+	}
+	counter := &countingWriter{w: fileWriter}
 	// Compression logic removed
 	// if m.config.Compression {
 	//  gzWriter := gzip.NewWriter(archiveFile)
@@ -750,7 +835,7 @@ func (m *Manager) createArchive(ctx context.Context, archivePath string, reader
 	//  m.logger.Debug("Using Gzip compression for archive", "backup_id", metadata.ID)
 	// }
 
-	tarWriter := tar.NewWriter(fileWriter)
+	tarWriter := tar.NewWriter(counter)
 	defer func() {
 		if !tarClosed {
 			if err := tarWriter.Close(); err != nil {
@@ -821,18 +906,24 @@ func (m *Manager) createArchive(ctx context.Context, archivePath string, reader
 	}
 	archiveClosed = true // Prevent double-close in defer
 
-	// Update metadata size *before* potential encryption
-	info, err := os.Stat(archivePath)
-	if err != nil {
-		m.logger.Warn("Failed to get archive size after creation", logger.String("backup_id", metadata.ID), logger.String("archive_path", archivePath), logger.Error(err))
-	} else {
-		metadata.OriginalSize = info.Size() // Store size before encryption
-	}
+	metadata.OriginalSize = counter.n // Store size before encryption
 
 	m.logger.Debug("Archive creation complete", logger.String("archive_path", archivePath), logger.String("backup_id", metadata.ID), logger.Int64("duration_ms", time.Since(start).Milliseconds()))
 	return nil
 }
 
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
 // addMetadataToArchive marshals metadata to JSON and adds it to the tar archive.
 func (m *Manager) addMetadataToArchive(ctx context.Context, tw *tar.Writer, metadata *Metadata) error {
 	start := time.Now()
@@ -848,7 +939,7 @@ func (m *Manager) addMetadataToArchive(ctx context.Context, tw *tar.Writer, meta
 
 	// Create TAR header for metadata.json
 	hdr := &tar.Header{
-		Name:    "metadata.json",
+		Name:    metadataEntryName,
 		Size:    int64(len(jsonData)),
 		Mode:    int64(PermArchiveFile), // Read-only
 		ModTime: metadata.Timestamp,     // Use backup timestamp
@@ -875,39 +966,21 @@ func (m *Manager) addMetadataToArchive(ctx context.Context, tw *tar.Writer, meta
 	return nil
 }
 
-// addBackupDataToArchive streams data from the source reader into the tar archive.
+// addBackupDataToArchive streams data from the source reader into the tar
+// archive. Tar headers carry the entry size, which a streaming source cannot
+// know up front, so the data is written as a sequence of entries of at most
+// dataPartSize bytes, each buffered in memory. Nothing is spooled to disk, so
+// with encryption enabled the data only ever reaches the disk encrypted. The
+// data checksum is recorded in the metadata so a restore can verify the
+// extracted data.
 func (m *Manager) addBackupDataToArchive(ctx context.Context, tw *tar.Writer, reader io.Reader, metadata *Metadata) error {
 	start := time.Now()
-	// Determine the filename within the archive based on source type or name
-	// Example: Use source name with a common extension
-	backupFilename := fmt.Sprintf("backup.%s", strings.ToLower(metadata.Source)) // e.g., backup.sqlite
-
-	// We don't know the size beforehand for streaming backup.
-	// Write the data directly. TAR format supports this.
-	// Create TAR header for the backup data
-	hdr := &tar.Header{
-		Name:    backupFilename,
-		Mode:    int64(PermArchiveFile), // Standard file permissions
-		ModTime: metadata.Timestamp,
-		// Size is unknown for streaming, tar writer handles this.
-	}
-
-	// Write header
-	if err := tw.WriteHeader(hdr); err != nil {
-		return errors.New(err).
-			Component("backup").
-			Category(errors.CategoryFileIO).
-			Context("operation", "write_backup_data_tar_header").
-			Build()
-	}
+	hash := sha256.New()
+	buf := make([]byte, dataPartSize)
 
-	// Copy data from source reader to tar writer
-	// Wrap the reader with a context checker if possible/needed,
-	// although source.Backup should handle context internally.
-	copiedBytes, err := io.Copy(tw, reader)
-	if err != nil {
-		// Check for context cancellation specifically if possible
-		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+	var copiedBytes int64
+	for part := 0; ; part++ {
+		if err := ctx.Err(); err != nil {
 			return errors.New(err).
 				Component("backup").
 				Category(errors.CategorySystem).
@@ -915,13 +988,61 @@ func (m *Manager) addBackupDataToArchive(ctx context.Context, tw *tar.Writer, re
 				Context("error_type", "cancelled").
 				Build()
 		}
-		return errors.New(err).
-			Component("backup").
-			Category(errors.CategoryFileIO).
-			Context("operation", "stream_backup_data_to_tar").
-			Context("bytes_copied", copiedBytes).
-			Build()
+
+		// source.Backup should handle context cancellation internally
+		n, err := io.ReadFull(reader, buf)
+		last := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !last {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return errors.New(err).
+					Component("backup").
+					Category(errors.CategorySystem).
+					Context("operation", "stream_backup_data").
+					Context("error_type", "cancelled").
+					Build()
+			}
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "read_backup_data").
+				Context("bytes_copied", copiedBytes).
+				Build()
+		}
+		// An empty source still gets its (empty) first entry; later parts are
+		// only written when they hold data
+		if n == 0 && part > 0 {
+			break
+		}
+
+		hdr := &tar.Header{
+			Name:    dataPartName(metadata.Source, part),
+			Size:    int64(n),
+			Mode:    int64(PermArchiveFile), // Standard file permissions
+			ModTime: metadata.Timestamp,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "write_backup_data_tar_header").
+				Build()
+		}
+		if _, err := tw.Write(buf[:n]); err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "stream_backup_data_to_tar").
+				Context("bytes_copied", copiedBytes).
+				Build()
+		}
+		hash.Write(buf[:n])
+		copiedBytes += int64(n)
+
+		if last {
+			break
+		}
 	}
+	metadata.DataChecksum = hex.EncodeToString(hash.Sum(nil))
 
 	m.logger.Debug("Finished adding backup data stream",
 		logger.String("backup_id", metadata.ID),
@@ -930,62 +1051,20 @@ func (m *Manager) addBackupDataToArchive(ctx context.Context, tw *tar.Writer, re
 	return nil
 }
 
-// encryptArchive encrypts the source file and writes it to the destination file.
-// Renamed from encryptAndWriteArchive for clarity.
-func (m *Manager) encryptArchive(ctx context.Context, sourcePath, destPath string) error {
-	start := time.Now()
-
-	// Read the entire source file (archive) into memory.
-	// Consider streaming encryption for very large files if memory becomes an issue.
-	// Read source file (internal temp archive path from backup manager)
-	plaintext, err := os.ReadFile(sourcePath) //nolint:gosec // G304 - sourcePath is an internal temp path from backup manager
-	if err != nil {
-		return errors.New(err).
-			Component("backup").
-			Category(errors.CategoryFileIO).
-			Context("operation", "read_archive_for_encryption").
-			Context("source_path", sourcePath).
-			Build()
-	}
-
-	m.logger.Debug("Encrypting archive", logger.String("source", sourcePath), logger.String("destination", destPath))
-
-	// Get encryption key
-	key, err := m.GetEncryptionKey() // Assumes GetEncryptionKey is implemented in encryption.go
-	if err != nil {
-		return errors.New(err).
-			Component("backup").
-			Category(errors.CategorySystem).
-			Context("operation", "get_encryption_key").
-			Build()
-	}
-
-	// Encrypt data
-	ciphertext, err := encryptData(plaintext, key) // Assumes encryptData is implemented in encryption.go
-	if err != nil {
-		return errors.New(err).
-			Component("backup").
-			Category(errors.CategorySystem).
-			Context("operation", "encrypt_data").
-			Build()
-	}
+// dataEntryName returns the name of the backup data entry inside an archive
+// for the given source.
+func dataEntryName(source string) string {
+	return dataEntryPrefix + strings.ToLower(source)
+}
 
-	// Write encrypted data to destination file
-	err = os.WriteFile(destPath, ciphertext, PermSecureFile) // Secure permissions
-	if err != nil {
-		return errors.New(err).
-			Component("backup").
-			Category(errors.CategoryFileIO).
-			Context("operation", "write_encrypted_archive").
-			Context("dest_path", destPath).
-			Build()
+// dataPartName returns the name of the given part of the backup data. The
+// first part keeps the plain data entry name, so archives whose data fits in
+// a single part look as they did before the data was split.
+func dataPartName(source string, part int) string {
+	if part == 0 {
+		return dataEntryName(source)
 	}
-
-	m.logger.Debug("Encryption successful",
-		logger.String("source", sourcePath),
-		logger.String("destination", destPath),
-		logger.Int64("duration_ms", time.Since(start).Milliseconds()))
-	return nil
+	return fmt.Sprintf("%s%s%d", dataEntryName(source), dataPartSuffix, part)
 }
 
 // parseRetentionAge parses a duration string (e.g., "30d", "4w", "1y") into time.Duration
@@ -1138,7 +1217,7 @@ func (m *Manager) deleteBackupWithTimeout(ctx context.Context, backup *BackupInf
 
 // enforceRetentionPolicy applies retention rules to a list of backups for a specific target and source type.
 // Backups list should be sorted newest first.
-func (m *Manager) enforceRetentionPolicy(ctx context.Context, target Target, backups []BackupInfo, retention conf.BackupRetention) error {
+func (m *Manager) enforceRetentionPolicy(ctx context.Context, target Target, backups []BackupInfo, retention conf.BackupRetentionPolicy) error {
 	if len(backups) == 0 {
 		return nil // Nothing to enforce
 	}
@@ -1282,11 +1361,10 @@ func (m *Manager) cleanupOldBackups(ctx context.Context) error {
 			continue
 		}
 
-		// Determine retention policy (should be per source type if config allows, otherwise global)
-		// Assuming global retention from m.config.Retention for now
-		retentionPolicy := m.config.Retention
-
 		for sourceType, backups := range sourceMap {
+			// Backups are sorted newest first, so the first one carries the
+			// current type of the source
+			retentionPolicy := m.config.Retention.ForSource(backups[0].Type)
 			wg.Go(func() {
 				if err := m.enforceRetentionPolicy(ctx, target, backups, retentionPolicy); err != nil {
 					m.logger.Error("Failed to enforce retention policy", logger.String("target_name", targetName), logger.String("source_type", sourceType), logger.Error(err))
@@ -1421,35 +1499,36 @@ func (m *Manager) DeleteBackup(ctx context.Context, id string) error {
 			Build()
 	}
 
-	var target Target
-	var backupToDelete BackupInfo
-	found := false
+	backupToDelete, target, err := m.findBackup(allBackups, id)
+	if err != nil {
+		return err
+	}
+
+	// Perform deletion with timeout
+	return m.deleteBackupWithTimeout(ctx, &backupToDelete, target)
+}
+
+// findBackup looks up a backup by ID in a listing and returns it together
+// with the registered target that holds it.
+func (m *Manager) findBackup(backups []BackupInfo, id string) (BackupInfo, Target, error) {
 	m.mu.RLock()
-	for i := range allBackups {
-		if allBackups[i].ID != id {
+	defer m.mu.RUnlock()
+
+	for i := range backups {
+		if backups[i].ID != id {
 			continue
 		}
 
-		t, ok := m.targets[allBackups[i].Target]
+		target, ok := m.targets[backups[i].Target]
 		if !ok {
-			m.mu.RUnlock()
-			m.logger.Error("Backup found, but its target is not registered", logger.String("backup_id", id), logger.String("target_name", allBackups[i].Target))
-			return NewError(ErrNotFound, fmt.Sprintf("target '%s' for backup '%s' not found", allBackups[i].Target, id), nil)
+			m.logger.Error("Backup found, but its target is not registered", logger.String("backup_id", id), logger.String("target_name", backups[i].Target))
+			return BackupInfo{}, nil, NewError(ErrNotFound, fmt.Sprintf("target '%s' for backup '%s' not found", backups[i].Target, id), nil)
 		}
-		target = t
-		backupToDelete = allBackups[i]
-		found = true
-		break
+		return backups[i], target, nil
 	}
-	m.mu.RUnlock()
 
-	if !found {
-		m.logger.Warn("Backup ID not found for deletion", logger.String("backup_id", id))
-		return NewError(ErrNotFound, fmt.Sprintf("backup with ID '%s' not found", id), nil)
-	}
-
-	// Perform deletion with timeout
-	return m.deleteBackupWithTimeout(ctx, &backupToDelete, target)
+	m.logger.Warn("Backup ID not found", logger.String("backup_id", id))
+	return BackupInfo{}, nil, NewError(ErrNotFound, fmt.Sprintf("backup with ID '%s' not found", id), nil)
 }
 
 // getBackupTimeout returns the configured timeout for the entire backup process.
@@ -1581,7 +1660,6 @@ func (m *Manager) ValidateBackupCounts(ctx context.Context) error {
 	}
 
 	groupedBackups := m.groupBackupsByTargetAndType(allBackups)
-	retention := m.config.Retention
 	var validationErrors []error
 
 	m.mu.RLock()
@@ -1595,7 +1673,7 @@ func (m *Manager) ValidateBackupCounts(ctx context.Context) error {
 		// Check counts for each source type found in the target
 		for sourceType, backups := range targetGroups {
 			backupCount := len(backups)
-			minRequired := retention.MinBackups
+			minRequired := m.config.Retention.ForSource(backups[0].Type).MinBackups
 
 			// Check minimum backups
 			if minRequired > 0 && backupCount < minRequired {
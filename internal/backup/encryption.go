@@ -1,10 +1,12 @@
 package backup
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/hex"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,6 +19,10 @@ import (
 
 // getEncryptionKeyPath returns the path to the encryption key file
 func (m *Manager) getEncryptionKeyPath() (string, error) {
+	if m.keyPath != "" {
+		return m.keyPath, nil
+	}
+
 	// Get the config directory
 	configPaths, err := conf.GetDefaultConfigPaths()
 	if err != nil {
@@ -76,92 +82,187 @@ func (m *Manager) getEncryptionKey() ([]byte, error) {
 				Context("operation", "generate_encryption_key").
 				Build()
 		}
+		if err := m.writeActiveKey(keyPath, key); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
 
-		// Encode the key as hex
-		keyHex := hex.EncodeToString(key)
+	return decodeEncryptionKey(keyBytes)
+}
 
-		// Create the config directory if it doesn't exist
-		if err := os.MkdirAll(filepath.Dir(keyPath), PermConfigDir); err != nil {
-			return nil, errors.New(err).
-				Component("backup").
-				Category(errors.CategoryFileIO).
-				Context("operation", "create_config_directory").
-				Context("dir_path", filepath.Dir(keyPath)).
-				Build()
+// retiredKeysDir returns the directory that keeps replaced encryption keys,
+// named by key ID, so backups made with them can still be restored
+func retiredKeysDir(keyPath string) string {
+	return filepath.Join(filepath.Dir(keyPath), "encryption-keys")
+}
+
+// loadKeyring reads the active and retired encryption keys without
+// generating one. Restores use it: a fresh key could never decrypt an
+// existing archive, and the keys may have been imported on a machine where
+// encryption is off.
+func (m *Manager) loadKeyring() (*encryptionKeyring, error) {
+	keyPath, err := m.getEncryptionKeyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	keyring := &encryptionKeyring{keys: make(map[string][]byte)}
+	keyBytes, err := os.ReadFile(keyPath) //nolint:gosec // G304 - keyPath is an internal config path from backup manager
+	switch {
+	case err == nil:
+		key, err := decodeEncryptionKey(keyBytes)
+		if err != nil {
+			return nil, err
 		}
+		keyring.activeID = encryptionKeyID(key)
+		keyring.keys[keyring.activeID] = key
+	case !os.IsNotExist(err):
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "load_encryption_key").
+			Context("key_path", keyPath).
+			Build()
+	}
 
-		// Write the key to the file with secure permissions
-		if err := os.WriteFile(keyPath, []byte(keyHex), PermSecureFile); err != nil {
-			return nil, errors.New(err).
-				Component("backup").
-				Category(errors.CategoryFileIO).
-				Context("operation", "write_encryption_key").
-				Context("key_path", keyPath).
-				Build()
+	retiredDir := retiredKeysDir(keyPath)
+	entries, err := os.ReadDir(retiredDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "list_retired_encryption_keys").
+			Context("dir_path", retiredDir).
+			Build()
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".key" {
+			continue
 		}
+		path := filepath.Join(retiredDir, entry.Name())
+		keyBytes, err := os.ReadFile(path) //nolint:gosec // G304 - path is inside the internal key directory
+		if err == nil {
+			var key []byte
+			if key, err = decodeEncryptionKey(keyBytes); err == nil {
+				keyring.keys[encryptionKeyID(key)] = key
+				continue
+			}
+		}
+		m.logger.Warn("Skipping unreadable retired encryption key", logger.String("path", path), logger.Error(err))
+	}
 
-		return key, nil
+	if len(keyring.keys) == 0 {
+		return nil, errors.Newf("backup is encrypted but no encryption key found at %s, import the key first", keyPath).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "load_encryption_key").
+			Build()
 	}
+	return keyring, nil
+}
 
-	// Decode existing key from hex
-	keyStr := strings.TrimSpace(string(keyBytes))
-	key, err := hex.DecodeString(keyStr)
-	if err != nil {
-		return nil, errors.New(err).
+// writeActiveKey makes key the key new backups are encrypted with. A
+// different key already in place is moved to the retired key directory
+// first, so no backup becomes unrecoverable.
+func (m *Manager) writeActiveKey(keyPath string, key []byte) error {
+	// Create the config directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(keyPath), PermConfigDir); err != nil {
+		return errors.New(err).
 			Component("backup").
-			Category(errors.CategorySystem).
-			Context("operation", "decode_encryption_key").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_config_directory").
+			Context("dir_path", filepath.Dir(keyPath)).
 			Build()
 	}
 
-	// Validate key length
-	if len(key) != AES256KeySize {
-		return nil, errors.Newf("invalid encryption key length: expected %d bytes, got %d", AES256KeySize, len(key)).
+	if keyBytes, err := os.ReadFile(keyPath); err == nil { //nolint:gosec // G304 - keyPath is an internal config path from backup manager
+		current, err := decodeEncryptionKey(keyBytes)
+		if err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryValidation).
+				Context("operation", "retire_encryption_key").
+				Context("hint", "the current key file is damaged, move it away before replacing it").
+				Build()
+		}
+		if bytes.Equal(current, key) {
+			return nil
+		}
+		if err := retireKey(keyPath, current); err != nil {
+			return err
+		}
+		m.logger.Info("Retired previous encryption key", logger.String("key_id", encryptionKeyID(current)))
+	} else if !os.IsNotExist(err) {
+		return errors.New(err).
 			Component("backup").
-			Category(errors.CategoryValidation).
-			Context("operation", "validate_encryption_key").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_encryption_key").
+			Context("key_path", keyPath).
 			Build()
 	}
 
-	return key, nil
+	// Write the key to the file with secure permissions
+	if err := os.WriteFile(keyPath, []byte(hex.EncodeToString(key)), PermSecureFile); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_encryption_key").
+			Context("key_path", keyPath).
+			Build()
+	}
+	return nil
 }
 
-// encryptData encrypts data using AES-256-GCM
-func encryptData(data, key []byte) ([]byte, error) {
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, errors.New(err).
+// retireKey stores key in the retired key directory under its key ID
+func retireKey(keyPath string, key []byte) error {
+	dir := retiredKeysDir(keyPath)
+	if err := os.MkdirAll(dir, PermConfigDir); err != nil {
+		return errors.New(err).
 			Component("backup").
-			Category(errors.CategorySystem).
-			Context("operation", "create_cipher_for_encryption").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_retired_key_directory").
+			Context("dir_path", dir).
+			Build()
+	}
+	path := filepath.Join(dir, encryptionKeyID(key)+".key")
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)), PermSecureFile); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "retire_encryption_key").
+			Context("key_path", path).
 			Build()
 	}
+	return nil
+}
 
-	gcm, err := cipher.NewGCM(block)
+// decodeEncryptionKey decodes a hex-encoded key file and validates its length
+func decodeEncryptionKey(keyBytes []byte) ([]byte, error) {
+	keyStr := strings.TrimSpace(string(keyBytes))
+	key, err := hex.DecodeString(keyStr)
 	if err != nil {
 		return nil, errors.New(err).
 			Component("backup").
 			Category(errors.CategorySystem).
-			Context("operation", "create_gcm_for_encryption").
+			Context("operation", "decode_encryption_key").
 			Build()
 	}
 
-	// Generate a random nonce
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := rand.Read(nonce); err != nil {
-		return nil, errors.New(err).
+	// Validate key length
+	if len(key) != AES256KeySize {
+		return nil, errors.Newf("invalid encryption key length: expected %d bytes, got %d", AES256KeySize, len(key)).
 			Component("backup").
-			Category(errors.CategorySystem).
-			Context("operation", "generate_nonce").
+			Category(errors.CategoryValidation).
+			Context("operation", "validate_encryption_key").
 			Build()
 	}
 
-	// Encrypt the data
-	ciphertext := gcm.Seal(nonce, nonce, data, nil)
-	return ciphertext, nil
+	return key, nil
 }
 
-// decryptData decrypts data using AES-256-GCM
+// decryptData decrypts an archive written before the streaming format:
+// a GCM nonce followed by the whole sealed archive
 func decryptData(encryptedData, key []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -204,7 +305,8 @@ func decryptData(encryptedData, key []byte) ([]byte, error) {
 	return plaintext, nil
 }
 
-// GenerateEncryptionKey generates a new encryption key and saves it to the default location
+// GenerateEncryptionKey generates a new encryption key and saves it to the
+// default location. The key it replaces is retired, not deleted.
 func (m *Manager) GenerateEncryptionKey() (string, error) {
 	m.logger.Info("Generating new encryption key...")
 	start := time.Now()
@@ -219,40 +321,38 @@ func (m *Manager) GenerateEncryptionKey() (string, error) {
 			Build()
 	}
 
-	// Convert key to hex string
-	keyHex := hex.EncodeToString(key)
-
 	// Get the key file path
 	keyPath, err := m.getEncryptionKeyPath()
 	if err != nil {
 		return "", err
 	}
 
-	// Create the config directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(keyPath), PermConfigDir); err != nil {
-		return "", errors.New(err).
-			Component("backup").
-			Category(errors.CategoryFileIO).
-			Context("operation", "create_config_directory_for_key").
-			Context("dir_path", filepath.Dir(keyPath)).
-			Build()
-	}
-
-	// Write the key to file with secure permissions
-	if err := os.WriteFile(keyPath, []byte(keyHex), PermSecureFile); err != nil {
-		return "", errors.New(err).
-			Component("backup").
-			Category(errors.CategoryFileIO).
-			Context("operation", "write_new_encryption_key").
-			Context("key_path", keyPath).
-			Build()
+	if err := m.writeActiveKey(keyPath, key); err != nil {
+		return "", err
 	}
 
 	m.logger.Info("Encryption key generated and saved successfully",
 		logger.String("path", keyPath),
+		logger.String("key_id", encryptionKeyID(key)),
 		logger.Int64("duration_ms", time.Since(start).Milliseconds()),
 	)
-	return keyHex, nil
+	return hex.EncodeToString(key), nil
+}
+
+// RotateEncryptionKey replaces the active encryption key with a new one and
+// returns the ID of the new key. New backups are encrypted with it, while
+// backups made with the previous keys stay restorable from their retired
+// copies.
+func (m *Manager) RotateEncryptionKey() (string, error) {
+	keyHex, err := m.GenerateEncryptionKey()
+	if err != nil {
+		return "", err
+	}
+	key, err := decodeEncryptionKey([]byte(keyHex))
+	if err != nil {
+		return "", err
+	}
+	return encryptionKeyID(key), nil
 }
 
 // ValidateEncryption checks if encryption is properly configured
@@ -295,23 +395,23 @@ func (m *Manager) GetEncryptionKey() ([]byte, error) {
 	return m.getEncryptionKey()
 }
 
-// DecryptData decrypts the provided data using the configured encryption key
+// DecryptData decrypts the provided data with the matching active or retired
+// encryption key. Both the streaming and the original format are accepted.
 func (m *Manager) DecryptData(encryptedData []byte) ([]byte, error) {
-	if !m.config.Encryption {
-		return nil, errors.Newf("encryption is not enabled").
-			Component("backup").
-			Category(errors.CategoryConfiguration).
-			Context("operation", "decrypt_data").
-			Build()
+	keyring, err := m.loadKeyring()
+	if err != nil {
+		return nil, err
 	}
 
-	key, err := m.getEncryptionKey()
+	r, err := newDecryptReader(bytes.NewReader(encryptedData), keyring)
 	if err != nil {
 		return nil, err
 	}
-
-	m.logger.Debug("Retrieved encryption key")
-	return decryptData(encryptedData, key)
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return plaintext, nil
 }
 
 // GetEncryptionKeyPath returns the path to the encryption key file
@@ -365,13 +465,14 @@ func (m *Manager) ImportEncryptionKey(content []byte) error {
 			Build()
 	}
 
-	// Validate key format (should be hex-encoded)
-	if _, err := hex.DecodeString(key); err != nil {
+	// Validate key format (should be a hex-encoded 256-bit key)
+	decoded, err := decodeEncryptionKey([]byte(key))
+	if err != nil {
 		return errors.New(err).
 			Component("backup").
 			Category(errors.CategoryValidation).
 			Context("operation", "validate_imported_key_format").
-			Context("error_detail", "key must be hex-encoded").
+			Context("error_detail", "key must be a hex-encoded 256-bit key").
 			Build()
 	}
 
@@ -384,30 +485,15 @@ func (m *Manager) ImportEncryptionKey(content []byte) error {
 	m.logger.Info("Attempting to import encryption key", logger.String("target_path", keyPath))
 	start = time.Now()
 
-	// Create the config directory if it doesn't exist
-	err = os.MkdirAll(filepath.Dir(keyPath), PermConfigDir)
-	if err != nil {
-		return errors.New(err).
-			Component("backup").
-			Category(errors.CategoryFileIO).
-			Context("operation", "create_config_directory_for_import").
-			Context("dir_path", filepath.Dir(keyPath)).
-			Build()
-	}
-
-	// Write the key to file with secure permissions
-	err = os.WriteFile(keyPath, []byte(key), PermSecureFile)
-	if err != nil {
-		return errors.New(err).
-			Component("backup").
-			Category(errors.CategoryFileIO).
-			Context("operation", "write_imported_encryption_key").
-			Context("key_path", keyPath).
-			Build()
+	// The imported key becomes the active one; a different key in place is
+	// retired so backups made with it stay restorable
+	if err := m.writeActiveKey(keyPath, decoded); err != nil {
+		return err
 	}
 
 	m.logger.Info("Encryption key imported successfully",
 		logger.String("path", keyPath),
+		logger.String("key_id", encryptionKeyID(decoded)),
 		logger.Int64("duration_ms", time.Since(start).Milliseconds()),
 	)
 	return nil
@@ -0,0 +1,409 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Streaming encryption format (version 2)
+//
+// The archive is split into chunks that are sealed one at a time with
+// AES-256-GCM, so neither encryption nor decryption holds more than one chunk
+// in memory. The stream starts with a header:
+//
+//	offset  size  field
+//	0       8     magic "BNGOENC\x00"
+//	8       1     format version (2)
+//	9       4     chunk size in bytes, big endian
+//	13      8     key ID, the first 8 bytes of SHA-256(key)
+//	21      7     random nonce prefix
+//
+// Every chunk holds chunk size bytes of plaintext plus the GCM tag, except the
+// last, which may be shorter or empty. The nonce of a chunk is the nonce
+// prefix, the chunk index as a big endian uint32 and a byte that is 1 for the
+// last chunk and 0 otherwise. The header is authenticated with every chunk.
+// Reordering or dropping chunks therefore breaks authentication, and a stream
+// cut at a chunk boundary is detected because its final chunk was not sealed
+// as the last one.
+//
+// Archives written before this format hold a single GCM nonce followed by
+// the sealed archive, with no header. They are still decrypted.
+const (
+	encStreamMagic         = "BNGOENC\x00"
+	encStreamVersion       = 2
+	encStreamKeyIDSize     = 8
+	encStreamPrefixSize    = 7
+	encStreamHeaderSize    = len(encStreamMagic) + 1 + 4 + encStreamKeyIDSize + encStreamPrefixSize
+	encStreamChunkSize     = 64 * KB
+	encStreamMaxChunkSize  = 16 * MB
+	encStreamMaxChunkCount = 1<<32 - 1
+)
+
+// encryptionKeyID returns the ID identifying a key in encrypted archives and
+// in the key directory
+func encryptionKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:encStreamKeyIDSize])
+}
+
+// newStreamGCM creates the AES-256-GCM cipher used for the stream chunks
+func newStreamGCM(key []byte, operation string) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategorySystem).
+			Context("operation", operation).
+			Build()
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategorySystem).
+			Context("operation", operation).
+			Build()
+	}
+	return gcm, nil
+}
+
+// chunkNonce builds the nonce of the chunk at index
+func chunkNonce(dst, prefix []byte, index uint32, last bool) []byte {
+	dst = append(dst[:0], prefix...)
+	dst = binary.BigEndian.AppendUint32(dst, index)
+	if last {
+		return append(dst, 1)
+	}
+	return append(dst, 0)
+}
+
+// encryptWriter encrypts everything written to it into the streaming format.
+// Close seals the final chunk; it does not close the underlying writer.
+type encryptWriter struct {
+	w      io.Writer
+	gcm    cipher.AEAD
+	header []byte
+	prefix []byte
+	index  uint32
+	buf    []byte
+	out    []byte
+	nonce  []byte
+	closed bool
+	err    error
+}
+
+// newEncryptWriter writes the stream header to w and returns a writer that
+// encrypts with key
+func newEncryptWriter(w io.Writer, key []byte) (*encryptWriter, error) {
+	gcm, err := newStreamGCM(key, "create_stream_cipher")
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, encStreamHeaderSize)
+	header = append(header, encStreamMagic...)
+	header = append(header, encStreamVersion)
+	header = binary.BigEndian.AppendUint32(header, encStreamChunkSize)
+	keyID, _ := hex.DecodeString(encryptionKeyID(key))
+	header = append(header, keyID...)
+	prefix := make([]byte, encStreamPrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategorySystem).
+			Context("operation", "generate_nonce_prefix").
+			Build()
+	}
+	header = append(header, prefix...)
+
+	if _, err := w.Write(header); err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_encryption_header").
+			Build()
+	}
+
+	return &encryptWriter{
+		w:      w,
+		gcm:    gcm,
+		header: header,
+		prefix: prefix,
+		buf:    make([]byte, 0, encStreamChunkSize),
+		out:    make([]byte, 0, encStreamChunkSize+gcm.Overhead()),
+		nonce:  make([]byte, 0, gcm.NonceSize()),
+	}, nil
+}
+
+// Write buffers p and seals every chunk that fills up. A full chunk is only
+// sealed once more data follows, as the last chunk is sealed differently.
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	if e.closed {
+		return 0, errors.NewStd("write to closed encryption stream")
+	}
+	written := 0
+	for len(p) > 0 {
+		if len(e.buf) == encStreamChunkSize {
+			if err := e.sealChunk(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(e.buf[len(e.buf):encStreamChunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close seals the buffered data as the last chunk
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return e.err
+	}
+	e.closed = true
+	if e.err != nil {
+		return e.err
+	}
+	return e.sealChunk(true)
+}
+
+func (e *encryptWriter) sealChunk(last bool) error {
+	if e.index == encStreamMaxChunkCount && !last {
+		e.err = errors.Newf("backup is too large for the encryption stream").
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "encrypt_stream_chunk").
+			Build()
+		return e.err
+	}
+	e.nonce = chunkNonce(e.nonce, e.prefix, e.index, last)
+	e.out = e.gcm.Seal(e.out[:0], e.nonce, e.buf, e.header)
+	if _, err := e.w.Write(e.out); err != nil {
+		e.err = errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_encrypted_chunk").
+			Build()
+		return e.err
+	}
+	e.buf = e.buf[:0]
+	e.index++
+	return nil
+}
+
+// decryptReader decrypts a stream written by encryptWriter
+type decryptReader struct {
+	r      *bufio.Reader
+	gcm    cipher.AEAD
+	header []byte
+	prefix []byte
+	index  uint32
+	chunk  []byte
+	plain  []byte
+	nonce  []byte
+	pos    int
+	done   bool
+	err    error
+}
+
+// Read returns decrypted data. It fails if a chunk does not authenticate or
+// the stream ends before its last chunk.
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for d.pos == len(d.plain) {
+		if d.err != nil {
+			return 0, d.err
+		}
+		if d.done {
+			return 0, io.EOF
+		}
+		d.err = d.openChunk()
+	}
+	n := copy(p, d.plain[d.pos:])
+	d.pos += n
+	return n, nil
+}
+
+// openChunk reads and authenticates the next chunk. A chunk is the last one
+// when the stream ends after it.
+func (d *decryptReader) openChunk() error {
+	n, err := io.ReadFull(d.r, d.chunk)
+	var last bool
+	switch {
+	case errors.Is(err, io.EOF):
+		return errTruncatedStream()
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_encrypted_chunk").
+			Build()
+	default:
+		if _, err := d.r.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "read_encrypted_chunk").
+				Build()
+		}
+	}
+	if n < d.gcm.Overhead() {
+		return errTruncatedStream()
+	}
+
+	d.nonce = chunkNonce(d.nonce, d.prefix, d.index, last)
+	plain, err := d.gcm.Open(d.plain[:0], d.nonce, d.chunk[:n], d.header)
+	if err != nil {
+		if last {
+			// A stream cut at a chunk boundary ends with a chunk that was
+			// not sealed as the last one
+			return errTruncatedStream()
+		}
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "decrypt_stream_chunk").
+			Context("chunk", d.index).
+			Context("hint", "damaged archive").
+			Build()
+	}
+	d.plain = plain
+	d.pos = 0
+	d.index++
+	d.done = last
+	return nil
+}
+
+func errTruncatedStream() error {
+	return errors.Newf("encrypted backup is truncated or damaged").
+		Component("backup").
+		Category(errors.CategoryValidation).
+		Context("operation", "decrypt_stream").
+		Build()
+}
+
+// encryptionKeyring holds the keys that can decrypt backups, by key ID. The
+// active key encrypts new backups; retired keys stay to decrypt old ones.
+type encryptionKeyring struct {
+	activeID string
+	keys     map[string][]byte
+}
+
+// ordered returns the keys with the active one first
+func (k *encryptionKeyring) ordered() [][]byte {
+	keys := make([][]byte, 0, len(k.keys))
+	if key, ok := k.keys[k.activeID]; ok {
+		keys = append(keys, key)
+	}
+	for id, key := range k.keys {
+		if id != k.activeID {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// newDecryptReader returns a reader that decrypts r with the matching key
+// from the keyring. Archives in the streaming format are decrypted chunk by
+// chunk; older archives are read into memory and decrypted with each key in
+// turn, as they carry no key ID.
+func newDecryptReader(r io.Reader, keyring *encryptionKeyring) (io.Reader, error) {
+	br := bufio.NewReaderSize(r, encStreamChunkSize)
+	magic, err := br.Peek(len(encStreamMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_encryption_header").
+			Build()
+	}
+	if !bytes.Equal(magic, []byte(encStreamMagic)) {
+		return decryptLegacy(br, keyring)
+	}
+
+	header := make([]byte, encStreamHeaderSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, errTruncatedStream()
+	}
+	if version := header[len(encStreamMagic)]; version != encStreamVersion {
+		return nil, errors.Newf("unsupported backup encryption format version %d", version).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "read_encryption_header").
+			Build()
+	}
+	offset := len(encStreamMagic) + 1
+	chunkSize := binary.BigEndian.Uint32(header[offset:])
+	offset += 4
+	if chunkSize == 0 || chunkSize > encStreamMaxChunkSize {
+		return nil, errors.Newf("invalid backup encryption chunk size %d", chunkSize).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "read_encryption_header").
+			Build()
+	}
+	keyID := hex.EncodeToString(header[offset : offset+encStreamKeyIDSize])
+	offset += encStreamKeyIDSize
+
+	key, ok := keyring.keys[keyID]
+	if !ok {
+		return nil, errors.Newf("backup was encrypted with key %s, which is not available, import that key first", keyID).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "find_decryption_key").
+			Context("key_id", keyID).
+			Build()
+	}
+	gcm, err := newStreamGCM(key, "create_stream_cipher")
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		r:      br,
+		gcm:    gcm,
+		header: header,
+		prefix: header[offset:],
+		chunk:  make([]byte, int(chunkSize)+gcm.Overhead()),
+		plain:  make([]byte, 0, chunkSize),
+		nonce:  make([]byte, 0, gcm.NonceSize()),
+	}, nil
+}
+
+// decryptLegacy decrypts an archive written before the streaming format
+func decryptLegacy(r io.Reader, keyring *encryptionKeyring) (io.Reader, error) {
+	ciphertext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_encrypted_archive").
+			Build()
+	}
+	for _, key := range keyring.ordered() {
+		if plaintext, err := decryptData(ciphertext, key); err == nil {
+			return bytes.NewReader(plaintext), nil
+		}
+	}
+	return nil, errors.Newf("failed to decrypt backup: wrong encryption key or damaged archive").
+		Component("backup").
+		Category(errors.CategoryValidation).
+		Context("operation", "decrypt_legacy_archive").
+		Build()
+}
@@ -0,0 +1,188 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func testKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, AES256KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func testKeyring(keys ...[]byte) *encryptionKeyring {
+	keyring := &encryptionKeyring{keys: make(map[string][]byte)}
+	for i, key := range keys {
+		id := encryptionKeyID(key)
+		if i == 0 {
+			keyring.activeID = id
+		}
+		keyring.keys[id] = key
+	}
+	return keyring
+}
+
+// encryptStream encrypts plaintext, writing it in uneven pieces
+func encryptStream(t *testing.T, key, plaintext []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := newEncryptWriter(&buf, key)
+	require.NoError(t, err)
+	for p := plaintext; len(p) > 0; {
+		n := min(len(p), 10_000)
+		_, err := w.Write(p[:n])
+		require.NoError(t, err)
+		p = p[n:]
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func decryptStream(keyring *encryptionKeyring, ciphertext []byte) ([]byte, error) {
+	r, err := newDecryptReader(bytes.NewReader(ciphertext), keyring)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestEncryptionStream_RoundTrip(t *testing.T) {
+	key := testKey(t)
+	for _, size := range []int{0, 1, encStreamChunkSize - 1, encStreamChunkSize, encStreamChunkSize + 1, 3*encStreamChunkSize + 17} {
+		plaintext := make([]byte, size)
+		_, err := rand.Read(plaintext)
+		require.NoError(t, err)
+
+		ciphertext := encryptStream(t, key, plaintext)
+		// A full last chunk is not followed by an empty one
+		chunks := max(1, (size+encStreamChunkSize-1)/encStreamChunkSize)
+		assert.Len(t, ciphertext, encStreamHeaderSize+size+chunks*16, "size %d", size)
+
+		decrypted, err := decryptStream(testKeyring(key), ciphertext)
+		require.NoError(t, err, "size %d", size)
+		assert.True(t, bytes.Equal(plaintext, decrypted), "size %d", size)
+	}
+}
+
+func TestEncryptionStream_DetectsTampering(t *testing.T) {
+	key := testKey(t)
+	plaintext := bytes.Repeat([]byte("birdnet"), encStreamChunkSize) // 7 chunks
+	ciphertext := encryptStream(t, key, plaintext)
+	chunkLen := encStreamChunkSize + 16
+
+	tests := []struct {
+		name   string
+		mangle func([]byte) []byte
+	}{
+		{"cut at chunk boundary", func(c []byte) []byte { return c[:encStreamHeaderSize+2*chunkLen] }},
+		{"cut inside chunk", func(c []byte) []byte { return c[:len(c)-100] }},
+		{"header only", func(c []byte) []byte { return c[:encStreamHeaderSize] }},
+		{"trailing data", func(c []byte) []byte { return append(c, 0) }},
+		{"flipped byte", func(c []byte) []byte { c[encStreamHeaderSize+chunkLen+5] ^= 1; return c }},
+		{"changed header", func(c []byte) []byte { c[encStreamHeaderSize-1] ^= 1; return c }},
+		{"swapped chunks", func(c []byte) []byte {
+			first := bytes.Clone(c[encStreamHeaderSize : encStreamHeaderSize+chunkLen])
+			copy(c[encStreamHeaderSize:], c[encStreamHeaderSize+chunkLen:encStreamHeaderSize+2*chunkLen])
+			copy(c[encStreamHeaderSize+chunkLen:], first)
+			return c
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decryptStream(testKeyring(key), tt.mangle(bytes.Clone(ciphertext)))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestEncryptionStream_SelectsKeyByID(t *testing.T) {
+	oldKey, newKey := testKey(t), testKey(t)
+	ciphertext := encryptStream(t, oldKey, []byte("made before rotation"))
+
+	decrypted, err := decryptStream(testKeyring(newKey, oldKey), ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "made before rotation", string(decrypted))
+
+	_, err = decryptStream(testKeyring(newKey), ciphertext)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), encryptionKeyID(oldKey))
+}
+
+func TestEncryptionStream_DecryptsLegacyFormat(t *testing.T) {
+	oldKey, newKey := testKey(t), testKey(t)
+
+	// The original format: a nonce followed by the whole sealed archive
+	block, err := aes.NewCipher(oldKey)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	require.NoError(t, err)
+	legacy := gcm.Seal(nonce, nonce, []byte("legacy archive"), nil)
+
+	decrypted, err := decryptStream(testKeyring(newKey, oldKey), legacy)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy archive", string(decrypted))
+
+	_, err = decryptStream(testKeyring(newKey), legacy)
+	require.Error(t, err)
+}
+
+func TestRestore_EncryptedAcrossKeyRotation(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "birdnet.db")
+	writeTestDatabase(t, dbPath, "original")
+
+	cfg := &conf.Settings{}
+	cfg.Backup.Enabled = true
+	cfg.Backup.Encryption = true
+
+	target := newMemTarget()
+	m := &Manager{
+		config:     &cfg.Backup,
+		fullConfig: cfg,
+		sources:    map[string]Source{"sqlite": fileSource{path: dbPath}},
+		targets:    map[string]Target{target.Name(): target},
+		logger:     GetLogger().Module("manager-test"),
+		keyPath:    filepath.Join(dir, "config", "encryption.key"),
+	}
+	require.NoError(t, m.RunBackup(t.Context()))
+	id := target.onlyID(t)
+
+	stored := target.metadata[id]
+	assert.True(t, stored.Encrypted)
+	assert.NotEmpty(t, stored.KeyID)
+	assert.Positive(t, stored.OriginalSize)
+	assert.True(t, bytes.HasPrefix(target.archives[id], []byte(encStreamMagic)), "archive should use the streaming format")
+
+	newKeyID, err := m.RotateEncryptionKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, stored.KeyID, newKeyID)
+	assert.FileExists(t, filepath.Join(dir, "config", "encryption-keys", stored.KeyID+".key"))
+
+	writeTestDatabase(t, dbPath, "changed")
+	result, err := m.Restore(t.Context(), &RestoreOptions{BackupID: id, DatabasePath: dbPath})
+	require.NoError(t, err)
+	assert.True(t, result.DataVerified)
+	assert.Equal(t, "original", readTestDatabase(t, dbPath))
+
+	// Without the retired key the backup can no longer be decrypted
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "config", "encryption-keys")))
+	_, err = m.Restore(t.Context(), &RestoreOptions{BackupID: id, DatabasePath: dbPath, DryRun: true})
+	require.Error(t, err)
+}
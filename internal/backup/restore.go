@@ -0,0 +1,866 @@
+// Package backup provides functionality for backing up application data
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite driver for restore integrity checks
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// Retriever is implemented by targets that can download a stored backup so
+// it can be restored. Backups on other targets can still be restored from a
+// manually downloaded archive with RestoreOptions.ArchivePath.
+type Retriever interface {
+	// Retrieve writes the stored archive of the backup with the given ID to w
+	Retrieve(ctx context.Context, id string, w io.Writer) error
+}
+
+// Archive entry names
+const (
+	metadataEntryName = "metadata.json"
+	configEntryName   = "config.yml"
+	dataEntryPrefix   = "backup."
+
+	// dataPartSuffix separates the data entry name from the part number of
+	// the second and later parts, e.g. backup.sqlite.part1
+	dataPartSuffix = ".part"
+
+	// dataPartSize caps the size of a single backup data entry, which is
+	// buffered in memory while the archive is written
+	dataPartSize = 4 * MB
+)
+
+// Restore file naming
+const (
+	// PendingRestoreSuffix is appended to the database path for a verified
+	// database waiting to be swapped in by ApplyPendingRestore
+	PendingRestoreSuffix = ".restore-pending"
+
+	// preRestoreSuffix is appended, with a timestamp, to the path of a
+	// database or config file replaced by a restore
+	preRestoreSuffix = ".pre-restore-"
+
+	// maxMetadataEntrySize caps the metadata and config entries read into memory
+	maxMetadataEntrySize = 16 * MB
+)
+
+// integrityNotApplicable is the integrity result of a verified backup that
+// does not hold a SQLite database
+const integrityNotApplicable = "not a SQLite database, skipped"
+
+// sqliteHeader is the magic string at the start of every SQLite database file
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// sqliteSidecarSuffixes are the files SQLite keeps next to a database in WAL mode
+var sqliteSidecarSuffixes = []string{"-wal", "-shm"}
+
+// RestoreOptions selects a backup and what to restore from it
+type RestoreOptions struct {
+	BackupID     string // ID of a backup listed by a registered target
+	ArchivePath  string // Archive file on disk to restore instead of BackupID
+	DatabasePath string // SQLite database to replace
+	ConfigPath   string // If set, the archived configuration is restored to this path
+	DryRun       bool   // Verify the backup without changing anything
+	Deferred     bool   // Stage the database for ApplyPendingRestore instead of replacing it now
+}
+
+// RestoreResult describes a verified, and unless dry-run, restored backup
+type RestoreResult struct {
+	Metadata         Metadata `json:"metadata"`
+	Target           string   `json:"target,omitempty"`
+	ArchiveVerified  bool     `json:"archive_verified"`            // Archive size and checksum matched the stored metadata
+	DataVerified     bool     `json:"data_verified"`               // Extracted data matched the stored data checksum
+	Integrity        string   `json:"integrity"`                   // Result of the SQLite integrity check
+	HasConfig        bool     `json:"has_config"`                  // Whether the archive holds a configuration file
+	DryRun           bool     `json:"dry_run"`                     // Nothing was changed
+	DatabasePath     string   `json:"database_path,omitempty"`     // Database that was replaced
+	PreviousDatabase string   `json:"previous_database,omitempty"` // Where the replaced database was kept
+	PendingDatabase  string   `json:"pending_database,omitempty"`  // Staged database applied on the next start
+	ConfigPath       string   `json:"config_path,omitempty"`       // Configuration file that was written
+	PreviousConfig   string   `json:"previous_config,omitempty"`   // Where the replaced configuration was kept
+}
+
+// archiveContents holds what was read from a backup archive
+type archiveContents struct {
+	metadata     *Metadata
+	config       []byte
+	dataPath     string
+	dataSize     int64
+	dataChecksum string
+}
+
+// Restore downloads or opens a backup archive, decrypts it, verifies it
+// against its metadata and checks the database integrity. Unless DryRun is
+// set it then replaces the SQLite database at DatabasePath, keeping the
+// previous file next to it, and optionally restores the configuration.
+//
+// The caller must make sure nothing holds the database open, or set Deferred
+// so the database is swapped in by ApplyPendingRestore on the next start.
+func (m *Manager) Restore(ctx context.Context, opts *RestoreOptions) (*RestoreResult, error) {
+	if opts.BackupID == "" && opts.ArchivePath == "" {
+		return nil, NewError(ErrValidation, "backup ID or archive path is required", nil)
+	}
+	if !opts.DryRun && opts.DatabasePath == "" {
+		return nil, NewError(ErrValidation, "database path is required to restore", nil)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.getBackupTimeout())
+	defer cancel()
+
+	start := time.Now()
+	workDir, err := os.MkdirTemp("", "birdnet-go-restore-*")
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_restore_directory").
+			Build()
+	}
+	defer m.cleanupTempDirectories([]string{workDir})
+
+	// 1. Fetch the archive and the metadata stored alongside it
+	archivePath, info, err := m.fetchArchive(ctx, opts, workDir)
+	if err != nil {
+		return nil, err
+	}
+	result := &RestoreResult{Metadata: info.Metadata, Target: info.Target, DryRun: opts.DryRun}
+
+	// 2. Verify the archive file against the stored metadata
+	verified, err := verifyArchiveFile(archivePath, &info.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	result.ArchiveVerified = verified
+
+	// 3. Decrypt and extract. The data is staged next to the database so the
+	// final swap is a rename on the same filesystem.
+	stageDir := workDir
+	if !opts.DryRun {
+		stageDir = filepath.Dir(opts.DatabasePath)
+	}
+	encrypted := info.Encrypted || strings.HasSuffix(archivePath, ".enc")
+	contents, err := m.extractArchive(ctx, archivePath, encrypted, stageDir)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.Remove(contents.dataPath); err != nil && !os.IsNotExist(err) {
+			m.logger.Warn("Failed to remove staged restore data", logger.String("path", contents.dataPath), logger.Error(err))
+		}
+	}()
+	result.HasConfig = contents.config != nil
+
+	// 4. Verify the extracted data
+	if err := verifyContents(contents, &info.Metadata); err != nil {
+		return nil, err
+	}
+	result.DataVerified = info.DataChecksum != ""
+	if info.ID == "" {
+		result.Metadata = *contents.metadata
+	}
+
+	// Only SQLite backups can be restored; the others (MySQL dumps,
+	// configuration and clip archives) are verified and then extracted by hand
+	isDatabase, err := isSQLiteFile(contents.dataPath)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case isDatabase:
+		result.Integrity, err = checkDatabaseIntegrity(ctx, contents.dataPath)
+		if err != nil {
+			return nil, err
+		}
+	case opts.DryRun:
+		result.Integrity = integrityNotApplicable
+	default:
+		return nil, NewError(ErrValidation,
+			fmt.Sprintf("backup %s holds %s data, only SQLite database backups can be restored", result.Metadata.ID, result.Metadata.Type), nil)
+	}
+
+	m.logger.Info("Backup verified",
+		logger.String("backup_id", result.Metadata.ID),
+		logger.Bool("archive_verified", result.ArchiveVerified),
+		logger.Bool("data_verified", result.DataVerified),
+		logger.Int64("data_size", contents.dataSize),
+		logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	// 5. Restore the database
+	if opts.Deferred {
+		pending := opts.DatabasePath + PendingRestoreSuffix
+		if err := os.Rename(contents.dataPath, pending); err != nil {
+			return nil, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "stage_pending_restore").
+				Context("path", pending).
+				Build()
+		}
+		syncDir(filepath.Dir(pending))
+		result.PendingDatabase = pending
+		m.logger.Info("Database restore staged, it is applied on the next start", logger.String("path", pending))
+	} else {
+		previous, err := replaceDatabase(contents.dataPath, opts.DatabasePath, start)
+		if err != nil {
+			return nil, err
+		}
+		result.DatabasePath = opts.DatabasePath
+		result.PreviousDatabase = previous
+		m.logger.Info("Database restored",
+			logger.String("path", opts.DatabasePath),
+			logger.String("previous", previous))
+	}
+
+	// 6. Optionally restore the configuration
+	if opts.ConfigPath != "" && contents.config != nil {
+		previous, err := m.restoreConfig(contents.config, opts.ConfigPath, start)
+		if err != nil {
+			return result, err
+		}
+		result.ConfigPath = opts.ConfigPath
+		result.PreviousConfig = previous
+		m.logger.Info("Configuration restored",
+			logger.String("path", opts.ConfigPath),
+			logger.String("previous", previous))
+	}
+
+	return result, nil
+}
+
+// fetchArchive returns the path of the archive to restore and the metadata
+// stored alongside it. Backups held by a target are downloaded into workDir.
+func (m *Manager) fetchArchive(ctx context.Context, opts *RestoreOptions, workDir string) (string, BackupInfo, error) {
+	if opts.ArchivePath != "" {
+		info := BackupInfo{}
+		if _, err := os.Stat(opts.ArchivePath); err != nil {
+			return "", info, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "open_restore_archive").
+				Context("path", opts.ArchivePath).
+				Build()
+		}
+		// Archives copied from a local target keep their metadata sidecar
+		if data, err := os.ReadFile(opts.ArchivePath + ".meta"); err == nil {
+			if err := json.Unmarshal(data, &info.Metadata); err != nil {
+				m.logger.Warn("Ignoring unreadable metadata sidecar", logger.String("path", opts.ArchivePath+".meta"), logger.Error(err))
+				info.Metadata = Metadata{}
+			}
+		}
+		return opts.ArchivePath, info, nil
+	}
+
+	allBackups, err := m.ListBackups(ctx)
+	if err != nil && len(allBackups) == 0 {
+		return "", BackupInfo{}, err
+	}
+	info, target, err := m.findBackup(allBackups, opts.BackupID)
+	if err != nil {
+		return "", BackupInfo{}, err
+	}
+
+	retriever, ok := target.(Retriever)
+	if !ok {
+		return "", info, NewError(ErrValidation,
+			fmt.Sprintf("target '%s' cannot download backups, download the archive manually and restore it from the file", target.Name()), nil)
+	}
+
+	archiveName := info.ID + ".tar"
+	if info.Encrypted {
+		archiveName += ".enc"
+	}
+	archivePath := filepath.Join(workDir, archiveName)
+	f, err := os.OpenFile(archivePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, PermSecureFile) //nolint:gosec // G304 - archivePath is inside the restore temp directory
+	if err != nil {
+		return "", info, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_download_file").
+			Build()
+	}
+
+	start := time.Now()
+	m.logger.Info("Downloading backup", logger.String("backup_id", info.ID), logger.String("target_name", target.Name()))
+	retrieveErr := retriever.Retrieve(ctx, info.ID, f)
+	closeErr := f.Close()
+	if retrieveErr != nil {
+		return "", info, errors.New(retrieveErr).
+			Component("backup").
+			Category(errors.CategoryNetwork).
+			Context("operation", "retrieve_backup").
+			Context("backup_id", info.ID).
+			Context("target_name", target.Name()).
+			Build()
+	}
+	if closeErr != nil {
+		return "", info, errors.New(closeErr).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "close_download_file").
+			Build()
+	}
+	m.logger.Info("Backup downloaded",
+		logger.String("backup_id", info.ID),
+		logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+
+	return archivePath, info, nil
+}
+
+// verifyArchiveFile checks the archive size and checksum against the stored
+// metadata. It reports whether a checksum was available to compare; backups
+// made before checksums were recorded are only checked for size.
+func verifyArchiveFile(path string, metadata *Metadata) (bool, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return false, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "stat_restore_archive").
+			Build()
+	}
+	if metadata.Size > 0 && fileInfo.Size() != metadata.Size {
+		return false, errors.Newf("backup archive size mismatch: expected %d bytes, got %d", metadata.Size, fileInfo.Size()).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "verify_archive_size").
+			Context("backup_id", metadata.ID).
+			Build()
+	}
+	if metadata.Checksum == "" {
+		return false, nil
+	}
+
+	checksum, err := fileChecksum(path)
+	if err != nil {
+		return false, err
+	}
+	if !strings.EqualFold(checksum, metadata.Checksum) {
+		return false, errors.Newf("backup archive checksum mismatch: the archive is damaged or was modified").
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "verify_archive_checksum").
+			Context("backup_id", metadata.ID).
+			Build()
+	}
+	return true, nil
+}
+
+// verifyContents checks the extracted archive against the stored metadata
+func verifyContents(contents *archiveContents, metadata *Metadata) error {
+	if metadata.ID != "" && contents.metadata.ID != metadata.ID {
+		return errors.Newf("archive holds backup %s, expected %s", contents.metadata.ID, metadata.ID).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "verify_archive_metadata").
+			Build()
+	}
+	if metadata.DataChecksum != "" && !strings.EqualFold(contents.dataChecksum, metadata.DataChecksum) {
+		return errors.Newf("backup data checksum mismatch: the archive is damaged or was modified").
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "verify_data_checksum").
+			Context("backup_id", metadata.ID).
+			Build()
+	}
+	return nil
+}
+
+// extractArchive decrypts the archive if needed and extracts its metadata,
+// configuration and backup data. The data is written to a file in stageDir.
+func (m *Manager) extractArchive(ctx context.Context, archivePath string, encrypted bool, stageDir string) (*archiveContents, error) {
+	f, err := os.Open(archivePath) //nolint:gosec // G304 - archivePath is a downloaded temp file or an archive chosen by the operator
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_archive").
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	// Encrypted archives are decrypted while they are read, so the archive
+	// never has to fit in memory
+	var archive io.Reader = f
+	if encrypted {
+		keyring, err := m.loadKeyring()
+		if err != nil {
+			return nil, err
+		}
+		if archive, err = newDecryptReader(f, keyring); err != nil {
+			return nil, err
+		}
+	}
+
+	contents := &archiveContents{}
+	stage := &dataStage{dir: stageDir}
+	success := false
+	defer func() {
+		if !success {
+			stage.discard()
+		}
+	}()
+
+	tr := tar.NewReader(archive)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			hint := "not a backup archive, or encrypted without a .enc extension"
+			if encrypted {
+				hint = "truncated or damaged archive"
+			}
+			return nil, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryValidation).
+				Context("operation", "read_archive").
+				Context("hint", hint).
+				Build()
+		}
+
+		switch {
+		case hdr.Name == metadataEntryName:
+			var metadata Metadata
+			if err := json.NewDecoder(io.LimitReader(tr, maxMetadataEntrySize)).Decode(&metadata); err != nil {
+				return nil, errors.New(err).
+					Component("backup").
+					Category(errors.CategoryValidation).
+					Context("operation", "decode_archive_metadata").
+					Build()
+			}
+			contents.metadata = &metadata
+		case hdr.Name == configEntryName:
+			config, err := io.ReadAll(io.LimitReader(tr, maxMetadataEntrySize))
+			if err != nil {
+				return nil, errors.New(err).
+					Component("backup").
+					Category(errors.CategoryFileIO).
+					Context("operation", "read_archive_config").
+					Build()
+			}
+			contents.config = config
+		case strings.HasPrefix(hdr.Name, dataEntryPrefix):
+			if err := stage.add(hdr.Name, tr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := stage.finish(contents); err != nil {
+		return nil, err
+	}
+	if contents.metadata == nil || contents.dataPath == "" {
+		return nil, errors.Newf("archive is missing its metadata or backup data").
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "read_archive").
+			Build()
+	}
+	success = true
+	return contents, nil
+}
+
+// dataStage collects the backup data entries of an archive into a single
+// file, hashing the data as it is written. The data is split into parts
+// named by dataPartName, which must appear in order.
+type dataStage struct {
+	dir   string
+	f     *os.File
+	hash  hash.Hash
+	size  int64
+	first string // name of the first data entry
+	parts int    // number of parts staged so far
+}
+
+// add appends the data entry name, read from r, to the staging file.
+func (s *dataStage) add(name string, r io.Reader) error {
+	if s.f == nil {
+		if strings.Contains(name, dataPartSuffix) {
+			return errors.Newf("archive data starts with a continuation part %q", name).
+				Component("backup").
+				Category(errors.CategoryValidation).
+				Context("operation", "read_archive").
+				Build()
+		}
+		f, err := os.CreateTemp(s.dir, ".birdnet-restore-*")
+		if err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "create_restore_staging_file").
+				Context("dir", s.dir).
+				Build()
+		}
+		s.f, s.hash, s.first = f, sha256.New(), name
+	} else if want := fmt.Sprintf("%s%s%d", s.first, dataPartSuffix, s.parts); name != want {
+		return errors.Newf("archive holds backup data entry %q where %q was expected", name, want).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "read_archive").
+			Build()
+	}
+
+	n, err := io.Copy(io.MultiWriter(s.f, s.hash), r)
+	s.size += n
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "extract_backup_data").
+			Build()
+	}
+	s.parts++
+	return nil
+}
+
+// finish syncs and closes the staging file and records it in contents. It
+// does nothing when the archive held no data entry.
+func (s *dataStage) finish(contents *archiveContents) error {
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Sync()
+	if closeErr := s.f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "extract_backup_data").
+			Build()
+	}
+	contents.dataPath = s.f.Name()
+	contents.dataSize = s.size
+	contents.dataChecksum = hex.EncodeToString(s.hash.Sum(nil))
+	return nil
+}
+
+// discard removes the staging file.
+func (s *dataStage) discard() {
+	if s.f != nil {
+		_ = s.f.Close()
+		_ = os.Remove(s.f.Name())
+	}
+}
+
+// isSQLiteFile reports whether the file at path starts with the SQLite header
+func isSQLiteFile(path string) (bool, error) {
+	header := make([]byte, len(sqliteHeader))
+	f, err := os.Open(path) //nolint:gosec // G304 - path is a staging file created by the restore
+	if err != nil {
+		return false, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_restored_data").
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false, nil
+	}
+	return bytes.Equal(header, sqliteHeader), nil
+}
+
+// checkDatabaseIntegrity runs SQLite's integrity check on a restored database
+// file and returns its result, which is "ok" for a healthy database.
+func checkDatabaseIntegrity(ctx context.Context, path string) (string, error) {
+	db, err := sql.Open("sqlite3", "file:"+filepath.ToSlash(path)+"?_busy_timeout=0")
+	if err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "open_restored_database").
+			Build()
+	}
+	defer func() {
+		_ = db.Close()
+		// A WAL-mode database leaves sidecars behind only if the close failed
+		for _, suffix := range sqliteSidecarSuffixes {
+			_ = os.Remove(path + suffix)
+		}
+	}()
+
+	var result string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "check_database_integrity").
+			Build()
+	}
+	if result != "ok" {
+		return result, errors.Newf("restored database failed the integrity check: %s", result).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "check_database_integrity").
+			Build()
+	}
+	return result, nil
+}
+
+// replaceDatabase atomically moves staged into place at dbPath. An existing
+// database is kept as dbPath.pre-restore-<timestamp> together with its WAL
+// sidecars, which must not be replayed into the restored database.
+func replaceDatabase(staged, dbPath string, now time.Time) (string, error) {
+	previous := ""
+	switch _, err := os.Stat(dbPath); {
+	case err == nil:
+		previous = dbPath + preRestoreSuffix + now.Format("20060102-150405")
+		if err := linkOrCopy(dbPath, previous); err != nil {
+			return "", errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "keep_previous_database").
+				Context("path", previous).
+				Build()
+		}
+		for _, suffix := range sqliteSidecarSuffixes {
+			if err := os.Rename(dbPath+suffix, previous+suffix); err != nil && !os.IsNotExist(err) {
+				return previous, errors.New(err).
+					Component("backup").
+					Category(errors.CategoryFileIO).
+					Context("operation", "move_database_sidecar").
+					Context("path", dbPath+suffix).
+					Build()
+			}
+		}
+	case !os.IsNotExist(err):
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "stat_database").
+			Context("path", dbPath).
+			Build()
+	}
+
+	if err := os.Rename(staged, dbPath); err != nil {
+		return previous, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "replace_database").
+			Context("path", dbPath).
+			Build()
+	}
+	syncDir(filepath.Dir(dbPath))
+	return previous, nil
+}
+
+// ApplyPendingRestore swaps in a database staged by a deferred restore. It
+// must run before the database is opened and reports whether a restore was
+// applied.
+func ApplyPendingRestore(dbPath string) (bool, error) {
+	pending := dbPath + PendingRestoreSuffix
+	if _, err := os.Stat(pending); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "stat_pending_restore").
+			Context("path", pending).
+			Build()
+	}
+
+	previous, err := replaceDatabase(pending, dbPath, time.Now())
+	if err != nil {
+		return false, err
+	}
+	GetLogger().Info("Applied pending database restore",
+		logger.String("path", dbPath),
+		logger.String("previous", previous))
+	return true, nil
+}
+
+// CheckDatabaseIdle returns an error if the SQLite database at dbPath looks
+// like it is in use. A WAL shared-memory file exists while any connection is
+// open (or after a crash), and an exclusive lock fails while another process
+// is writing.
+func CheckDatabaseIdle(ctx context.Context, dbPath string) error {
+	if _, err := os.Stat(dbPath + "-shm"); err == nil {
+		return errors.Newf("database %s appears to be in use, stop BirdNET-Go before restoring", dbPath).
+			Component("backup").
+			Category(errors.CategoryConflict).
+			Context("operation", "check_database_idle").
+			Build()
+	}
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.ToSlash(dbPath)+"?_busy_timeout=0")
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "check_database_idle").
+			Build()
+	}
+	defer func() { _ = db.Close() }()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "check_database_idle").
+			Build()
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN EXCLUSIVE"); err != nil {
+		return errors.Newf("database %s is locked by another process, stop BirdNET-Go before restoring", dbPath).
+			Component("backup").
+			Category(errors.CategoryConflict).
+			Context("operation", "check_database_idle").
+			Build()
+	}
+	_, _ = conn.ExecContext(ctx, "ROLLBACK")
+	return nil
+}
+
+// restoreConfig writes the archived configuration to path. Secrets removed
+// when the backup was made are carried over from the running configuration,
+// and an existing file is kept as path.pre-restore-<timestamp>.
+func (m *Manager) restoreConfig(data []byte, path string, now time.Time) (string, error) {
+	var restored conf.Settings
+	if err := yaml.Unmarshal(data, &restored); err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "parse_archived_config").
+			Build()
+	}
+	if m.fullConfig != nil {
+		restoreSecrets(&restored, m.fullConfig)
+	}
+	out, err := yaml.Marshal(&restored)
+	if err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "marshal_restored_config").
+			Build()
+	}
+
+	previous := ""
+	if _, err := os.Stat(path); err == nil {
+		previous = path + preRestoreSuffix + now.Format("20060102-150405")
+		if err := linkOrCopy(path, previous); err != nil {
+			return "", errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "keep_previous_config").
+				Context("path", previous).
+				Build()
+		}
+	}
+
+	tmp := path + ".restore-tmp"
+	if err := os.WriteFile(tmp, out, PermSecureFile); err != nil {
+		return previous, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_restored_config").
+			Context("path", tmp).
+			Build()
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return previous, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "replace_config").
+			Context("path", path).
+			Build()
+	}
+	return previous, nil
+}
+
+// fileChecksum returns the hex-encoded SHA-256 of a file
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // G304 - path is an archive handled by the backup manager
+	if err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_file_for_checksum").
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "calculate_checksum").
+			Build()
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// linkOrCopy makes dst a copy of src, using a hard link when possible
+func linkOrCopy(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src) //nolint:gosec // G304 - src is the database or config being replaced
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_EXCL, PermSecureFile) //nolint:gosec // G304 - dst is derived from src
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// syncDir flushes a directory entry so a rename survives a power loss. Errors
+// are ignored: not every platform supports syncing directories.
+func syncDir(dir string) {
+	d, err := os.Open(dir) //nolint:gosec // G304 - dir holds a file the restore just renamed
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
@@ -0,0 +1,430 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// fileSource backs up a file from disk, like the SQLite source does
+type fileSource struct{ path string }
+
+func (s fileSource) Name() string { return "sqlite" }
+func (s fileSource) Backup(_ context.Context) (io.ReadCloser, error) {
+	return os.Open(s.path)
+}
+func (s fileSource) Validate() error { return nil }
+
+// memTarget keeps stored archives in memory and can retrieve them
+type memTarget struct {
+	mu       sync.Mutex
+	archives map[string][]byte
+	metadata map[string]Metadata
+}
+
+func newMemTarget() *memTarget {
+	return &memTarget{archives: make(map[string][]byte), metadata: make(map[string]Metadata)}
+}
+
+func (t *memTarget) Name() string { return "memory" }
+func (t *memTarget) Store(_ context.Context, sourcePath string, metadata *Metadata) error {
+	data, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.archives[metadata.ID] = data
+	t.metadata[metadata.ID] = *metadata
+	return nil
+}
+func (t *memTarget) List(_ context.Context) ([]BackupInfo, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	backups := make([]BackupInfo, 0, len(t.metadata))
+	for _, md := range t.metadata {
+		backups = append(backups, BackupInfo{Metadata: md, Target: t.Name()})
+	}
+	return backups, nil
+}
+func (t *memTarget) Delete(_ context.Context, id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.archives, id)
+	delete(t.metadata, id)
+	return nil
+}
+func (t *memTarget) Validate() error { return nil }
+func (t *memTarget) Retrieve(_ context.Context, id string, w io.Writer) error {
+	t.mu.Lock()
+	data := t.archives[id]
+	t.mu.Unlock()
+	_, err := io.Copy(w, bytes.NewReader(data))
+	return err
+}
+
+// onlyID returns the ID of the single backup held by the target
+func (t *memTarget) onlyID(tb testing.TB) string {
+	tb.Helper()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	require.Len(tb, t.metadata, 1)
+	for id := range t.metadata {
+		return id
+	}
+	return ""
+}
+
+// writeTestDatabase creates a SQLite database holding a single value
+func writeTestDatabase(tb testing.TB, path, value string) {
+	tb.Helper()
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(tb, err)
+	defer func() { require.NoError(tb, db.Close()) }()
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS notes (value TEXT); DELETE FROM notes;`)
+	require.NoError(tb, err)
+	_, err = db.Exec(`INSERT INTO notes (value) VALUES (?)`, value)
+	require.NoError(tb, err)
+}
+
+// readTestDatabase returns the value stored by writeTestDatabase
+func readTestDatabase(tb testing.TB, path string) string {
+	tb.Helper()
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(tb, err)
+	defer func() { require.NoError(tb, db.Close()) }()
+	var value string
+	require.NoError(tb, db.QueryRow(`SELECT value FROM notes`).Scan(&value))
+	return value
+}
+
+// newRestoreTestManager backs up a database holding "original" to an
+// in-memory target and then changes the live database to "changed"
+func newRestoreTestManager(t *testing.T) (m *Manager, target *memTarget, dbPath string) {
+	t.Helper()
+	dbPath = filepath.Join(t.TempDir(), "birdnet.db")
+	writeTestDatabase(t, dbPath, "original")
+
+	cfg := &conf.Settings{}
+	cfg.Backup.Enabled = true
+	cfg.BirdNET.Latitude = 60.17
+	cfg.Security.SessionSecret = "session-secret"
+
+	target = newMemTarget()
+	m = &Manager{
+		config:     &cfg.Backup,
+		fullConfig: cfg,
+		sources:    map[string]Source{"sqlite": fileSource{path: dbPath}},
+		targets:    map[string]Target{target.Name(): target},
+		logger:     GetLogger().Module("manager-test"),
+	}
+	require.NoError(t, m.RunBackup(t.Context()))
+
+	writeTestDatabase(t, dbPath, "changed")
+	return m, target, dbPath
+}
+
+func TestRestore_RoundTrip(t *testing.T) {
+	m, target, dbPath := newRestoreTestManager(t)
+	id := target.onlyID(t)
+
+	stored := target.metadata[id]
+	assert.NotEmpty(t, stored.Checksum, "archive checksum should be recorded")
+	assert.NotEmpty(t, stored.DataChecksum, "data checksum should be recorded")
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("debug: true\n"), 0o600))
+
+	result, err := m.Restore(t.Context(), &RestoreOptions{
+		BackupID:     id,
+		DatabasePath: dbPath,
+		ConfigPath:   configPath,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, result.ArchiveVerified)
+	assert.True(t, result.DataVerified)
+	assert.Equal(t, "ok", result.Integrity)
+	assert.Equal(t, "original", readTestDatabase(t, dbPath))
+	require.NotEmpty(t, result.PreviousDatabase)
+	assert.Equal(t, "changed", readTestDatabase(t, result.PreviousDatabase))
+
+	// The restored configuration keeps the secret the backup left out
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	var restored conf.Settings
+	require.NoError(t, yaml.Unmarshal(data, &restored))
+	assert.InDelta(t, 60.17, restored.BirdNET.Latitude, 0.0001)
+	assert.Equal(t, "session-secret", restored.Security.SessionSecret)
+	assert.FileExists(t, result.PreviousConfig)
+}
+
+func TestRestore_DryRunLeavesDatabase(t *testing.T) {
+	m, target, dbPath := newRestoreTestManager(t)
+
+	result, err := m.Restore(t.Context(), &RestoreOptions{
+		BackupID:     target.onlyID(t),
+		DatabasePath: dbPath,
+		DryRun:       true,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, result.DryRun)
+	assert.Equal(t, "ok", result.Integrity)
+	assert.Empty(t, result.PreviousDatabase)
+	assert.Equal(t, "changed", readTestDatabase(t, dbPath))
+
+	entries, err := os.ReadDir(filepath.Dir(dbPath))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "a dry run must not leave files next to the database")
+}
+
+func TestRestore_ChecksumMismatch(t *testing.T) {
+	m, target, dbPath := newRestoreTestManager(t)
+	id := target.onlyID(t)
+
+	// Damage the stored archive without changing its size
+	target.archives[id][len(target.archives[id])/2] ^= 0xff
+
+	_, err := m.Restore(t.Context(), &RestoreOptions{BackupID: id, DatabasePath: dbPath})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum")
+	assert.Equal(t, "changed", readTestDatabase(t, dbPath))
+}
+
+func TestRestore_UnknownBackup(t *testing.T) {
+	m, _, dbPath := newRestoreTestManager(t)
+
+	_, err := m.Restore(t.Context(), &RestoreOptions{BackupID: "missing", DatabasePath: dbPath})
+	require.Error(t, err)
+	assert.True(t, IsErrorCode(err, ErrNotFound))
+}
+
+func TestRestore_DeferredThenApplyPendingRestore(t *testing.T) {
+	m, target, dbPath := newRestoreTestManager(t)
+
+	result, err := m.Restore(t.Context(), &RestoreOptions{
+		BackupID:     target.onlyID(t),
+		DatabasePath: dbPath,
+		Deferred:     true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, dbPath+PendingRestoreSuffix, result.PendingDatabase)
+	assert.Equal(t, "changed", readTestDatabase(t, dbPath), "a deferred restore must not touch the live database")
+
+	applied, err := ApplyPendingRestore(dbPath)
+	require.NoError(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, "original", readTestDatabase(t, dbPath))
+	assert.NoFileExists(t, result.PendingDatabase)
+
+	applied, err = ApplyPendingRestore(dbPath)
+	require.NoError(t, err)
+	assert.False(t, applied, "nothing is pending after the restore was applied")
+}
+
+func TestCheckDatabaseIdle(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "birdnet.db")
+	writeTestDatabase(t, dbPath, "value")
+	require.NoError(t, CheckDatabaseIdle(t.Context(), dbPath))
+
+	// Hold a write lock from another connection
+	db, err := sql.Open("sqlite3", dbPath)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+	conn, err := db.Conn(t.Context())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, conn.Close()) }()
+	_, err = conn.ExecContext(t.Context(), "BEGIN EXCLUSIVE")
+	require.NoError(t, err)
+	defer func() { _, _ = conn.ExecContext(context.Background(), "ROLLBACK") }()
+
+	assert.Error(t, CheckDatabaseIdle(t.Context(), dbPath))
+}
+
+func TestRestoreSecrets(t *testing.T) {
+	current := &conf.Settings{}
+	current.Security.SessionSecret = "current-session"
+	current.Output.MySQL.Password = "current-mysql"
+
+	restored := &conf.Settings{}
+	restored.Output.MySQL.Password = "restored-mysql"
+
+	restoreSecrets(restored, current)
+
+	assert.Equal(t, "current-session", restored.Security.SessionSecret, "cleared secrets come from the current config")
+	assert.Equal(t, "restored-mysql", restored.Output.MySQL.Password, "secrets carried by the backup are kept")
+}
+
+// incrementalSource records the since it is asked for and reports no
+// changes once it has been backed up up to cutoff
+type incrementalSource struct {
+	cutoff time.Time
+	asked  []time.Time
+}
+
+func (s *incrementalSource) Name() string { return "clips" }
+func (s *incrementalSource) Type() string { return "clips" }
+func (s *incrementalSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	r, _, err := s.BackupSince(ctx, time.Time{})
+	return r, err
+}
+func (s *incrementalSource) Validate() error { return nil }
+func (s *incrementalSource) BackupSince(_ context.Context, since time.Time) (io.ReadCloser, time.Time, error) {
+	s.asked = append(s.asked, since)
+	if !since.Before(s.cutoff) {
+		return nil, s.cutoff, ErrNoChanges
+	}
+	return io.NopCloser(bytes.NewReader([]byte("clip data"))), s.cutoff, nil
+}
+
+func TestRunBackup_IncrementalSource(t *testing.T) {
+	cfg := &conf.Settings{}
+	cfg.Backup.Enabled = true
+
+	source := &incrementalSource{cutoff: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)}
+	target := newMemTarget()
+	m := &Manager{
+		config:     &cfg.Backup,
+		fullConfig: cfg,
+		sources:    map[string]Source{source.Name(): source},
+		targets:    map[string]Target{target.Name(): target},
+		logger:     GetLogger().Module("manager-test"),
+		stateManager: &StateManager{
+			state: &BackupState{
+				Schedules: make(map[string]ScheduleState),
+				Targets:   make(map[string]TargetState),
+				Sources:   make(map[string]SourceState),
+				Stats:     make(map[string]BackupStats),
+			},
+			statePath: filepath.Join(t.TempDir(), "backup-state.json"),
+			logger:    GetLogger().Module("state-test"),
+		},
+	}
+
+	require.NoError(t, m.RunBackup(t.Context()))
+	id := target.onlyID(t)
+	stored := target.metadata[id]
+	assert.Equal(t, "clips", stored.Type)
+	assert.True(t, stored.Incremental)
+	assert.True(t, stored.Since.IsZero(), "the first incremental backup covers everything")
+	assert.Equal(t, source.cutoff, m.stateManager.GetSourceState("clips").LastSuccessful)
+
+	// The next run starts at the recorded cutoff and stores nothing new
+	require.NoError(t, m.RunBackup(t.Context()))
+	require.Len(t, source.asked, 2)
+	assert.Equal(t, source.cutoff, source.asked[1])
+	assert.Len(t, target.metadata, 1)
+}
+
+func TestBackupRetention_ForSource(t *testing.T) {
+	retention := conf.BackupRetention{
+		MaxAge:     "30d",
+		MaxBackups: 30,
+		MinBackups: 7,
+		Sources: map[string]conf.BackupRetentionPolicy{
+			"clips": {MaxAge: "1y", MaxBackups: 0, MinBackups: 0},
+		},
+	}
+
+	assert.Equal(t, conf.BackupRetentionPolicy{MaxAge: "1y"}, retention.ForSource("clips"))
+	assert.Equal(t, conf.BackupRetentionPolicy{MaxAge: "1y"}, retention.ForSource("Clips"))
+	assert.Equal(t, conf.BackupRetentionPolicy{MaxAge: "30d", MaxBackups: 30, MinBackups: 7}, retention.ForSource("sqlite"))
+}
+
+func TestArchive_DataSplitIntoParts(t *testing.T) {
+	cfg := &conf.Settings{}
+	m := &Manager{
+		config:     &cfg.Backup,
+		fullConfig: cfg,
+		logger:     GetLogger().Module("manager-test"),
+	}
+
+	data := make([]byte, 2*dataPartSize+123)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "backup.tar")
+	metadata := &Metadata{ID: "test", Source: "sqlite", Timestamp: time.Now()}
+	require.NoError(t, m.createArchive(t.Context(), archivePath, bytes.NewReader(data), metadata))
+
+	// The data is streamed into the archive without a spool file next to it
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	f, err := os.Open(archivePath)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, f.Close()) }()
+	var names []string
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+	}
+	assert.Equal(t, []string{metadataEntryName, configEntryName, "backup.sqlite", "backup.sqlite.part1", "backup.sqlite.part2"}, names)
+
+	contents, err := m.extractArchive(t.Context(), archivePath, false, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), contents.dataSize)
+	assert.Equal(t, metadata.DataChecksum, contents.dataChecksum)
+	restored, err := os.ReadFile(contents.dataPath)
+	require.NoError(t, err)
+	assert.Equal(t, data, restored)
+}
+
+func TestArchive_RejectsMisorderedParts(t *testing.T) {
+	m := &Manager{logger: GetLogger().Module("manager-test")}
+
+	tests := []struct {
+		name    string
+		entries []string
+	}{
+		{"starts with a continuation part", []string{"backup.sqlite.part1"}},
+		{"skips a part", []string{"backup.sqlite", "backup.sqlite.part2"}},
+		{"second data entry", []string{"backup.sqlite", "backup.clips"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tw := tar.NewWriter(&buf)
+			for _, name := range tt.entries {
+				require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Size: 1, Mode: 0o644}))
+				_, err := tw.Write([]byte{1})
+				require.NoError(t, err)
+			}
+			require.NoError(t, tw.Close())
+
+			dir := t.TempDir()
+			archivePath := filepath.Join(dir, "backup.tar")
+			require.NoError(t, os.WriteFile(archivePath, buf.Bytes(), 0o600))
+
+			stageDir := t.TempDir()
+			_, err := m.extractArchive(t.Context(), archivePath, false, stageDir)
+			require.Error(t, err)
+			staged, err := os.ReadDir(stageDir)
+			require.NoError(t, err)
+			assert.Empty(t, staged, "the partial staging file is removed")
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package sources
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// fileEntry is a file added to a directory backup
+type fileEntry struct {
+	name    string      // Slash-separated name inside the archive
+	path    string      // File on disk, unless data is set
+	data    []byte      // Contents to write instead of reading path
+	mode    os.FileMode // Permission bits
+	modTime time.Time
+}
+
+// streamFiles writes the entries as a tar stream to the returned reader. The
+// stream fails with the first error, including a cancelled context.
+func streamFiles(ctx context.Context, entries []fileEntry, log logger.Logger) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		err := writeTar(ctx, pw, entries)
+		if err != nil {
+			log.Error("Failed to write backup archive stream", logger.Error(err))
+		}
+		// CloseWithError(nil) closes the pipe normally
+		_ = pw.CloseWithError(err)
+	}()
+
+	return pr
+}
+
+// writeTar writes the entries to w as a tar archive
+func writeTar(ctx context.Context, w io.Writer, entries []fileEntry) error {
+	tw := tar.NewWriter(w)
+	for i := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := writeTarEntry(tw, &entries[i]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "close_tar_stream").
+			Build()
+	}
+	return nil
+}
+
+// writeTarEntry adds a single file to the archive. A file that disappeared
+// since it was listed is skipped.
+func writeTarEntry(tw *tar.Writer, entry *fileEntry) error {
+	var (
+		r    io.Reader
+		size int64
+	)
+	if entry.data != nil {
+		r = bytes.NewReader(entry.data)
+		size = int64(len(entry.data))
+	} else {
+		f, err := os.Open(entry.path) //nolint:gosec // G304 - path was listed from a configured backup directory
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "open_backup_file").
+				Context("path", entry.path).
+				Build()
+		}
+		defer func() { _ = f.Close() }()
+
+		info, err := f.Stat()
+		if err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "stat_backup_file").
+				Context("path", entry.path).
+				Build()
+		}
+		// Never write more than the header announces if the file grows
+		r = io.LimitReader(f, info.Size())
+		size = info.Size()
+	}
+
+	hdr := &tar.Header{
+		Name:    entry.name,
+		Size:    size,
+		Mode:    int64(entry.mode.Perm()),
+		ModTime: entry.modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_tar_header").
+			Context("name", entry.name).
+			Build()
+	}
+	if _, err := io.Copy(tw, r); err != nil {
+		if isMediaError(err) {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryDiskUsage).
+				Context("operation", "write_tar_entry").
+				Context("error_type", "media_error").
+				Build()
+		}
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_tar_entry").
+			Context("name", entry.name).
+			Build()
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package sources
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// clipSettleTime keeps clips modified this recently out of a backup. A clip
+// is written under a temporary name and renamed when complete, keeping the
+// modification time of its last write, so a clip still being written when
+// the directory is walked could otherwise end up before the next cutoff
+// without ever being listed.
+const clipSettleTime = 5 * time.Minute
+
+// clipExtensions are the audio formats the clip exporter writes
+var clipExtensions = []string{".wav", ".flac", ".mp3", ".aac", ".opus", ".m4a"}
+
+// ClipSource implements the backup.IncrementalSource interface for the audio
+// clip directory. Each backup is a tar archive of the clips modified since
+// the previous successful backup, keeping their paths relative to the clip
+// directory.
+type ClipSource struct {
+	config *conf.Settings
+	log    logger.Logger
+
+	// now returns the current time; tests replace it
+	now func() time.Time
+}
+
+// NewClipSource creates a new incremental audio clip backup source
+func NewClipSource(config *conf.Settings, log logger.Logger) *ClipSource {
+	if log == nil {
+		log = logger.Global().Module("backup")
+	}
+	return &ClipSource{
+		config: config,
+		log:    log.Module("clips"),
+		now:    time.Now,
+	}
+}
+
+// Name returns the name of this source
+func (s *ClipSource) Name() string {
+	return "clips"
+}
+
+// Type returns the kind of data this source backs up
+func (s *ClipSource) Type() string {
+	return "clips"
+}
+
+// Validate checks that a clip directory is configured
+func (s *ClipSource) Validate() error {
+	if s.config.Realtime.Audio.Export.Path == "" {
+		return errors.Newf("audio clip export path is not configured").
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "validate_clip_source").
+			Build()
+	}
+	return nil
+}
+
+// Backup streams every clip in the clip directory
+func (s *ClipSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	r, _, err := s.BackupSince(ctx, time.Time{})
+	return r, err
+}
+
+// BackupSince streams the clips modified after since. It returns
+// backup.ErrNoChanges when there are none.
+func (s *ClipSource) BackupSince(ctx context.Context, since time.Time) (io.ReadCloser, time.Time, error) {
+	start := time.Now()
+	cutoff := s.now().Add(-clipSettleTime)
+	root := s.config.Realtime.Audio.Export.Path
+
+	entries, totalSize, err := listClips(ctx, root, since, cutoff)
+	if err != nil {
+		return nil, cutoff, err
+	}
+	if len(entries) == 0 {
+		return nil, cutoff, backup.ErrNoChanges
+	}
+
+	s.log.Info("Backing up audio clips",
+		logger.String("clip_dir", root),
+		logger.Time("since", since),
+		logger.Time("cutoff", cutoff),
+		logger.Int("clip_count", len(entries)),
+		logger.Int64("total_size", totalSize),
+		logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+	return streamFiles(ctx, entries, s.log), cutoff, nil
+}
+
+// listClips returns the clips under root modified after since and no later
+// than cutoff, oldest first
+func listClips(ctx context.Context, root string, since, cutoff time.Time) ([]fileEntry, int64, error) {
+	var (
+		entries   []fileEntry
+		totalSize int64
+	)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || !slices.Contains(clipExtensions, strings.ToLower(filepath.Ext(path))) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil // Removed by retention while walking
+			}
+			return err
+		}
+		if !info.ModTime().After(since) || info.ModTime().After(cutoff) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, fileEntry{
+			name:    filepath.ToSlash(rel),
+			path:    path,
+			mode:    info.Mode(),
+			modTime: info.ModTime(),
+		})
+		totalSize += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, 0, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "list_clips").
+			Context("clip_dir", root).
+			Build()
+	}
+
+	slices.SortFunc(entries, func(a, b fileEntry) int {
+		return a.modTime.Compare(b.modTime)
+	})
+	return entries, totalSize, nil
+}
@@ -0,0 +1,170 @@
+package sources
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// configDirEntries are the files and directories next to config.yaml that a
+// configuration backup includes. Runtime state (sessions, HLS segments,
+// diagnostics, backup state) is left out, and so is the backup encryption
+// key: inside an encrypted archive it could never be used to open it, and in
+// a plain archive it would expose the key protecting the other backups.
+var configDirEntries = []string{
+	"model-catalog.json", // User-editable model catalog
+	"tls",                // Manually installed and self-signed certificates
+	"tls-acme",           // AutoTLS certificate cache
+}
+
+// ConfigSource implements the backup.Source interface for the configuration
+// directory. The data is a tar archive of config.yaml and the entries in
+// configDirEntries.
+type ConfigSource struct {
+	config *conf.Settings
+	log    logger.Logger
+
+	// findConfigFile locates config.yaml; tests replace it
+	findConfigFile func() (string, error)
+}
+
+// NewConfigSource creates a new configuration directory backup source
+func NewConfigSource(config *conf.Settings, log logger.Logger) *ConfigSource {
+	if log == nil {
+		log = logger.Global().Module("backup")
+	}
+	return &ConfigSource{
+		config:         config,
+		log:            log.Module("config"),
+		findConfigFile: conf.FindConfigFile,
+	}
+}
+
+// Name returns the name of this source
+func (s *ConfigSource) Name() string {
+	return "config"
+}
+
+// Type returns the kind of data this source backs up
+func (s *ConfigSource) Type() string {
+	return "config"
+}
+
+// Validate checks that the configuration file can be found
+func (s *ConfigSource) Validate() error {
+	if _, err := s.findConfigFile(); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "validate_config_source").
+			Build()
+	}
+	return nil
+}
+
+// Backup streams a tar archive of the configuration directory
+func (s *ConfigSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	start := time.Now()
+	configPath, err := s.findConfigFile()
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "find_config_file").
+			Build()
+	}
+
+	entries, err := s.listEntries(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Backing up configuration directory",
+		logger.String("config_dir", filepath.Dir(configPath)),
+		logger.Int("file_count", len(entries)),
+		logger.Bool("sanitized", s.config.Backup.SanitizeConfig),
+		logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+	return streamFiles(ctx, entries, s.log), nil
+}
+
+// listEntries returns the files to back up. config.yaml is replaced by the
+// sanitized running configuration when SanitizeConfig is set, so secrets do
+// not leave the station.
+func (s *ConfigSource) listEntries(configPath string) ([]fileEntry, error) {
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "stat_config_file").
+			Build()
+	}
+
+	configEntry := fileEntry{
+		name:    "config.yaml",
+		path:    configPath,
+		mode:    info.Mode(),
+		modTime: info.ModTime(),
+	}
+	if s.config.Backup.SanitizeConfig {
+		data, err := yaml.Marshal(backup.SanitizeConfig(s.config))
+		if err != nil {
+			return nil, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryConfiguration).
+				Context("operation", "marshal_sanitized_config").
+				Build()
+		}
+		configEntry.data = data
+	}
+	entries := []fileEntry{configEntry}
+
+	configDir := filepath.Dir(configPath)
+	for _, name := range configDirEntries {
+		root := filepath.Join(configDir, name)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && path == root {
+					return nil
+				}
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(configDir, path)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, fileEntry{
+				name:    filepath.ToSlash(rel),
+				path:    path,
+				mode:    info.Mode(),
+				modTime: info.ModTime(),
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "list_config_directory").
+				Context("entry", name).
+				Build()
+		}
+	}
+	return entries, nil
+}
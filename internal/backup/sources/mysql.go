@@ -0,0 +1,406 @@
+package sources
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// mysqlInsertBatchRows caps the rows written per INSERT statement
+	mysqlInsertBatchRows = 500
+
+	// mysqlInsertBatchBytes caps the size of an INSERT statement, well below
+	// the default max_allowed_packet of 64 MiB
+	mysqlInsertBatchBytes = 1 << 20
+
+	// mysqlConnectTimeout bounds connecting to the server
+	mysqlConnectTimeout = "30s"
+)
+
+// MySQLSource implements the backup.Source interface for MySQL and MariaDB
+// databases. It writes a logical SQL dump read inside a single consistent
+// snapshot transaction, so no mysqldump binary is needed. The dump restores
+// with `mysql <database> < dump.sql`.
+//
+// Tables and views are dumped; triggers, routines and events are not, as
+// BirdNET-Go does not create any.
+type MySQLSource struct {
+	config *conf.Settings
+	log    logger.Logger
+}
+
+// NewMySQLSource creates a new MySQL backup source
+func NewMySQLSource(config *conf.Settings, log logger.Logger) *MySQLSource {
+	if log == nil {
+		log = logger.Global().Module("backup")
+	}
+	return &MySQLSource{
+		config: config,
+		log:    log.Module("mysql"),
+	}
+}
+
+// Name returns the name of this source
+func (s *MySQLSource) Name() string {
+	return "mysql"
+}
+
+// Type returns the kind of data this source backs up
+func (s *MySQLSource) Type() string {
+	return "mysql"
+}
+
+// Validate checks that the MySQL connection settings are complete
+func (s *MySQLSource) Validate() error {
+	cfg := &s.config.Output.MySQL
+	if !cfg.Enabled {
+		return errors.Newf("MySQL database is not enabled").
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "validate_mysql_source").
+			Build()
+	}
+	if cfg.Host == "" || cfg.Database == "" || cfg.Username == "" {
+		return errors.Newf("MySQL host, database and username must be set").
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "validate_mysql_source").
+			Build()
+	}
+	return nil
+}
+
+// dsn builds the connection string from the configured MySQL settings.
+// Values are read as raw bytes, so parseTime stays off.
+func (s *MySQLSource) dsn() string {
+	cfg := &s.config.Output.MySQL
+	mc := mysql.Config{
+		User:                 cfg.Username,
+		Passwd:               cfg.Password,
+		Net:                  "tcp",
+		Addr:                 net.JoinHostPort(cfg.Host, cfg.Port),
+		DBName:               cfg.Database,
+		AllowNativePasswords: true,
+		CheckConnLiveness:    true,
+		Params: map[string]string{
+			"charset": "utf8mb4",
+			"timeout": mysqlConnectTimeout,
+		},
+	}
+	return mc.FormatDSN()
+}
+
+// Backup streams a logical SQL dump of the database
+func (s *MySQLSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	start := time.Now()
+	s.log.Info("Starting MySQL logical backup", logger.String("database", s.config.Output.MySQL.Database))
+
+	db, err := sql.Open("mysql", s.dsn())
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "open_mysql_database").
+			Build()
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "verify_mysql_connection").
+			Build()
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer func() {
+			if err := db.Close(); err != nil {
+				s.log.Debug("Failed to close MySQL connection", logger.Error(err))
+			}
+		}()
+
+		err := s.dump(ctx, db, pw)
+		if err != nil {
+			s.log.Error("MySQL backup failed", logger.Error(err), logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+		} else {
+			s.log.Info("MySQL backup completed successfully", logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
+// dump writes the SQL dump to w from a consistent snapshot
+func (s *MySQLSource) dump(ctx context.Context, db *sql.DB, w io.Writer) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return wrapMySQLError(err, "get_mysql_connection")
+	}
+	defer func() { _ = conn.Close() }()
+
+	// A repeatable-read snapshot gives every table the same point in time
+	// without locking out the writers
+	if _, err := conn.ExecContext(ctx, "SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ"); err != nil {
+		return wrapMySQLError(err, "set_isolation_level")
+	}
+	if _, err := conn.ExecContext(ctx, "START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY"); err != nil {
+		return wrapMySQLError(err, "start_snapshot")
+	}
+	defer func() { _, _ = conn.ExecContext(context.Background(), "ROLLBACK") }()
+
+	tables, views, err := listMySQLTables(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriterSize(w, 256*1024)
+	d := &mysqlDumper{conn: conn, w: bw}
+
+	d.printf("-- BirdNET-Go MySQL backup of database %s\n", quoteIdentifier(s.config.Output.MySQL.Database))
+	d.printf("-- Created %s\n\n", time.Now().UTC().Format(time.RFC3339))
+	d.printf("SET NAMES utf8mb4;\n")
+	d.printf("SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;\n")
+	d.printf("SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;\n")
+	d.printf("SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO';\n\n")
+
+	for _, table := range tables {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := d.dumpTable(ctx, table); err != nil {
+			return err
+		}
+		s.log.Debug("Dumped table", logger.String("table", table))
+	}
+	for _, view := range views {
+		if err := d.dumpView(ctx, view); err != nil {
+			return err
+		}
+	}
+
+	d.printf("SET SQL_MODE=@OLD_SQL_MODE;\n")
+	d.printf("SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;\n")
+	d.printf("SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;\n")
+	if d.err != nil {
+		return d.err
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_mysql_dump").
+			Build()
+	}
+	return nil
+}
+
+// listMySQLTables returns the base tables and views of the current database
+func listMySQLTables(ctx context.Context, conn *sql.Conn) (tables, views []string, err error) {
+	rows, err := conn.QueryContext(ctx,
+		"SELECT TABLE_NAME, TABLE_TYPE FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() ORDER BY TABLE_NAME")
+	if err != nil {
+		return nil, nil, wrapMySQLError(err, "list_tables")
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var name, tableType string
+		if err := rows.Scan(&name, &tableType); err != nil {
+			return nil, nil, wrapMySQLError(err, "scan_table_name")
+		}
+		switch tableType {
+		case "BASE TABLE":
+			tables = append(tables, name)
+		case "VIEW":
+			views = append(views, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, wrapMySQLError(err, "list_tables")
+	}
+	return tables, views, nil
+}
+
+// mysqlDumper writes SQL statements, keeping the first write error
+type mysqlDumper struct {
+	conn *sql.Conn
+	w    *bufio.Writer
+	err  error
+}
+
+func (d *mysqlDumper) printf(format string, args ...any) {
+	if d.err != nil {
+		return
+	}
+	if _, err := fmt.Fprintf(d.w, format, args...); err != nil {
+		d.err = errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_mysql_dump").
+			Build()
+	}
+}
+
+// dumpTable writes the table definition followed by its rows in batched
+// INSERT statements
+func (d *mysqlDumper) dumpTable(ctx context.Context, table string) error {
+	var name, createStmt string
+	if err := d.conn.QueryRowContext(ctx, "SHOW CREATE TABLE "+quoteIdentifier(table)).Scan(&name, &createStmt); err != nil {
+		return wrapMySQLError(err, "show_create_table")
+	}
+	d.printf("--\n-- Table %s\n--\n\n", quoteIdentifier(table))
+	d.printf("DROP TABLE IF EXISTS %s;\n%s;\n\n", quoteIdentifier(table), createStmt)
+
+	rows, err := d.conn.QueryContext(ctx, "SELECT * FROM "+quoteIdentifier(table))
+	if err != nil {
+		return wrapMySQLError(err, "select_table_rows")
+	}
+	defer func() { _ = rows.Close() }()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return wrapMySQLError(err, "get_column_types")
+	}
+	columns := make([]string, len(columnTypes))
+	for i, ct := range columnTypes {
+		columns[i] = quoteIdentifier(ct.Name())
+	}
+	insertPrefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES\n", quoteIdentifier(table), strings.Join(columns, ", "))
+
+	values := make([]sql.RawBytes, len(columnTypes))
+	scanArgs := make([]any, len(values))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	var (
+		batch     strings.Builder
+		batchRows int
+	)
+	flush := func() {
+		if batchRows == 0 {
+			return
+		}
+		d.printf("%s%s;\n", insertPrefix, batch.String())
+		batch.Reset()
+		batchRows = 0
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return wrapMySQLError(err, "scan_table_row")
+		}
+		if batchRows > 0 {
+			batch.WriteString(",\n")
+		}
+		batch.WriteByte('(')
+		for i, v := range values {
+			if i > 0 {
+				batch.WriteString(", ")
+			}
+			batch.WriteString(formatMySQLValue(v, columnTypes[i].DatabaseTypeName()))
+		}
+		batch.WriteByte(')')
+		batchRows++
+
+		if batchRows >= mysqlInsertBatchRows || batch.Len() >= mysqlInsertBatchBytes {
+			flush()
+		}
+		if d.err != nil {
+			return d.err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return wrapMySQLError(err, "select_table_rows")
+	}
+	flush()
+	d.printf("\n")
+	return d.err
+}
+
+// dumpView writes the definition of a view
+func (d *mysqlDumper) dumpView(ctx context.Context, view string) error {
+	var name, createStmt, charset, collation string
+	if err := d.conn.QueryRowContext(ctx, "SHOW CREATE VIEW "+quoteIdentifier(view)).Scan(&name, &createStmt, &charset, &collation); err != nil {
+		return wrapMySQLError(err, "show_create_view")
+	}
+	d.printf("DROP VIEW IF EXISTS %s;\n%s;\n\n", quoteIdentifier(view), createStmt)
+	return d.err
+}
+
+// formatMySQLValue formats a raw column value as an SQL literal. Numbers are
+// written as is, binary data as a hex literal and everything else as an
+// escaped string.
+func formatMySQLValue(v sql.RawBytes, typeName string) string {
+	if v == nil {
+		return "NULL"
+	}
+	switch strings.ToUpper(typeName) {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT",
+		"UNSIGNED TINYINT", "UNSIGNED SMALLINT", "UNSIGNED MEDIUMINT", "UNSIGNED INT", "UNSIGNED BIGINT",
+		"DECIMAL", "FLOAT", "DOUBLE", "YEAR":
+		return string(v)
+	case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "BIT", "GEOMETRY":
+		if len(v) == 0 {
+			return "''"
+		}
+		return "0x" + hex.EncodeToString(v)
+	default:
+		return quoteString(v)
+	}
+}
+
+// quoteString returns s as a single-quoted MySQL string literal
+func quoteString(s []byte) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('\'')
+	for _, c := range s {
+		switch c {
+		case 0:
+			b.WriteString(`\0`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case 0x1a:
+			b.WriteString(`\Z`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// quoteIdentifier returns name as a backtick-quoted MySQL identifier
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// wrapMySQLError wraps a database error from the dump
+func wrapMySQLError(err error, operation string) error {
+	return errors.New(err).
+		Component("backup").
+		Category(errors.CategoryDatabase).
+		Context("operation", operation).
+		Build()
+}
@@ -0,0 +1,40 @@
+package sources
+
+import (
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// RegisterConfigured registers a source for the enabled database and for
+// each optional source switched on under backup.sources. Every source is
+// attempted; the errors of those that fail are joined.
+func RegisterConfigured(m *backup.Manager, settings *conf.Settings, lg logger.Logger) error {
+	var candidates []backup.Source
+	if settings.Output.SQLite.Enabled {
+		candidates = append(candidates, NewSQLiteSource(settings, lg))
+	}
+	if settings.Output.MySQL.Enabled {
+		candidates = append(candidates, NewMySQLSource(settings, lg))
+	}
+	if settings.Backup.Sources.Config {
+		candidates = append(candidates, NewConfigSource(settings, lg))
+	}
+	if settings.Backup.Sources.Clips {
+		candidates = append(candidates, NewClipSource(settings, lg))
+	}
+
+	var errs []error
+	for _, source := range candidates {
+		if err := m.RegisterSource(source); err != nil {
+			errs = append(errs, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryConfiguration).
+				Context("operation", "register_source").
+				Context("source", source.Name()).
+				Build())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,157 @@
+package sources
+
+import (
+	"archive/tar"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// readTar returns the contents of a tar stream keyed by entry name
+func readTar(t *testing.T, r io.ReadCloser) map[string]string {
+	t.Helper()
+	defer func() { _ = r.Close() }()
+
+	files := make(map[string]string)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(data)
+	}
+}
+
+func writeFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	if !modTime.IsZero() {
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+}
+
+func TestConfigSource_Backup(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, configPath, "secret: on-disk\n", time.Time{})
+	writeFile(t, filepath.Join(dir, "model-catalog.json"), "{}", time.Time{})
+	writeFile(t, filepath.Join(dir, "tls", "cert.pem"), "cert", time.Time{})
+	writeFile(t, filepath.Join(dir, "encryption.key"), "key", time.Time{})
+	writeFile(t, filepath.Join(dir, "backup-state.json"), "{}", time.Time{})
+
+	settings := &conf.Settings{}
+	settings.Backup.SanitizeConfig = true
+	settings.Output.MySQL.Password = "hunter2"
+
+	source := NewConfigSource(settings, nil)
+	source.findConfigFile = func() (string, error) { return configPath, nil }
+	require.NoError(t, source.Validate())
+
+	r, err := source.Backup(t.Context())
+	require.NoError(t, err)
+	files := readTar(t, r)
+
+	assert.Contains(t, files, "config.yaml")
+	assert.Equal(t, "{}", files["model-catalog.json"])
+	assert.Equal(t, "cert", files["tls/cert.pem"])
+	assert.NotContains(t, files, "encryption.key", "the encryption key must never be backed up")
+	assert.NotContains(t, files, "backup-state.json")
+
+	// The sanitized running configuration replaces the file on disk
+	assert.NotContains(t, files["config.yaml"], "on-disk")
+	assert.NotContains(t, files["config.yaml"], "hunter2")
+}
+
+func TestClipSource_BackupSince(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	writeFile(t, filepath.Join(dir, "2026", "04", "old.wav"), "old", now.Add(-48*time.Hour))
+	writeFile(t, filepath.Join(dir, "2026", "05", "new.flac"), "new", now.Add(-time.Hour))
+	writeFile(t, filepath.Join(dir, "2026", "05", "writing.wav"), "partial", now.Add(-time.Minute))
+	writeFile(t, filepath.Join(dir, "2026", "05", "notes.txt"), "not a clip", now.Add(-time.Hour))
+
+	settings := &conf.Settings{}
+	settings.Realtime.Audio.Export.Path = dir
+	source := NewClipSource(settings, nil)
+	source.now = func() time.Time { return now }
+	require.NoError(t, source.Validate())
+
+	t.Run("full backup skips unsettled clips", func(t *testing.T) {
+		r, cutoff, err := source.BackupSince(t.Context(), time.Time{})
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(-clipSettleTime), cutoff)
+
+		files := readTar(t, r)
+		assert.Equal(t, map[string]string{
+			"2026/04/old.wav":  "old",
+			"2026/05/new.flac": "new",
+		}, files)
+	})
+
+	t.Run("incremental backup only includes newer clips", func(t *testing.T) {
+		r, _, err := source.BackupSince(t.Context(), now.Add(-24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"2026/05/new.flac": "new"}, readTar(t, r))
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		r, _, err := source.BackupSince(t.Context(), now.Add(-clipSettleTime))
+		require.ErrorIs(t, err, backup.ErrNoChanges)
+		assert.Nil(t, r)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		settings := &conf.Settings{}
+		settings.Realtime.Audio.Export.Path = filepath.Join(dir, "missing")
+		source := NewClipSource(settings, nil)
+		_, _, err := source.BackupSince(context.Background(), time.Time{})
+		require.ErrorIs(t, err, backup.ErrNoChanges)
+	})
+}
+
+func TestFormatMySQLValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    sql.RawBytes
+		typeName string
+		want     string
+	}{
+		{"null", nil, "VARCHAR", "NULL"},
+		{"integer", sql.RawBytes("42"), "INT", "42"},
+		{"unsigned", sql.RawBytes("7"), "UNSIGNED BIGINT", "7"},
+		{"decimal", sql.RawBytes("0.85"), "DECIMAL", "0.85"},
+		{"string", sql.RawBytes("Turdus merula"), "VARCHAR", "'Turdus merula'"},
+		{"quotes", sql.RawBytes(`O'Brien "x"`), "TEXT", `'O\'Brien "x"'`},
+		{"control characters", sql.RawBytes("a\\b\nc\rd\x00e\x1a"), "TEXT", `'a\\b\nc\rd\0e\Z'`},
+		{"datetime", sql.RawBytes("2026-05-01 12:00:00"), "DATETIME", "'2026-05-01 12:00:00'"},
+		{"blob", sql.RawBytes{0x00, 0xff}, "BLOB", "0x00ff"},
+		{"empty blob", sql.RawBytes{}, "LONGBLOB", "''"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatMySQLValue(tt.value, tt.typeName))
+		})
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	assert.Equal(t, "`notes`", quoteIdentifier("notes"))
+	assert.Equal(t, "`we``ird`", quoteIdentifier("we`ird"))
+}
@@ -50,6 +50,11 @@ func (s *SQLiteSource) Name() string {
 	return strings.TrimSuffix(baseName, filepath.Ext(baseName))
 }
 
+// Type returns the kind of data this source backs up
+func (s *SQLiteSource) Type() string {
+	return "sqlite"
+}
+
 // DatabaseConnection represents a managed database connection
 type DatabaseConnection struct {
 	db     *sql.DB
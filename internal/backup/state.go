@@ -18,6 +18,7 @@ type BackupState struct {
 	LastUpdate time.Time                `json:"last_update"`
 	Schedules  map[string]ScheduleState `json:"schedules"` // Key is "daily" or "weekly-{weekday}"
 	Targets    map[string]TargetState   `json:"targets"`   // Key is target name
+	Sources    map[string]SourceState   `json:"sources"`   // Key is source name
 	MissedRuns []MissedBackup           `json:"missed_runs"`
 	Stats      map[string]BackupStats   `json:"stats"` // Key is target name
 }
@@ -42,6 +43,12 @@ type TargetState struct {
 	ValidationStatus string    `json:"validation_status"`
 }
 
+// SourceState represents the state of an incremental backup source
+type SourceState struct {
+	LastSuccessful time.Time `json:"last_successful"` // Cutoff of the last backup stored in every target
+	LastBackupID   string    `json:"last_backup_id"`
+}
+
 // MissedBackup represents a missed backup event
 type MissedBackup struct {
 	ScheduledTime time.Time `json:"scheduled_time"`
@@ -84,6 +91,7 @@ func NewStateManager(_ logger.Logger) (*StateManager, error) {
 		state: &BackupState{
 			Schedules:  make(map[string]ScheduleState),
 			Targets:    make(map[string]TargetState),
+			Sources:    make(map[string]SourceState),
 			Stats:      make(map[string]BackupStats),
 			MissedRuns: make([]MissedBackup, 0),
 		},
@@ -118,14 +126,11 @@ func (sm *StateManager) loadState() error {
 	return json.Unmarshal(data, sm.state)
 }
 
-// saveState saves the current backup state to disk
+// saveState saves the current backup state to disk. The caller must hold sm.mu.
 func (sm *StateManager) saveState() error {
 	start := time.Now()
 
-	// Copy state under lock
-	sm.mu.Lock()
 	stateSnapshot := *sm.state
-	sm.mu.Unlock()
 
 	// Update last update time (on the snapshot)
 	stateSnapshot.LastUpdate = time.Now()
@@ -268,6 +273,31 @@ func (sm *StateManager) UpdateTargetState(targetName string, metadata *Metadata,
 	return nil
 }
 
+// UpdateSourceState records the cutoff of an incremental backup that every
+// target has stored. The next backup of the source starts there.
+func (sm *StateManager) UpdateSourceState(sourceName string, metadata *Metadata, cutoff time.Time) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.logger.Debug("Updating source state",
+		logger.String("source_name", sourceName),
+		logger.String("backup_id", metadata.ID),
+		logger.Time("cutoff", cutoff))
+
+	if sm.state.Sources == nil {
+		sm.state.Sources = make(map[string]SourceState)
+	}
+	sm.state.Sources[sourceName] = SourceState{
+		LastSuccessful: cutoff,
+		LastBackupID:   metadata.ID,
+	}
+
+	if err := sm.saveState(); err != nil {
+		sm.logger.Error("Failed to save state after updating source state", logger.String("source_name", sourceName), logger.Error(err))
+		return err
+	}
+	return nil
+}
+
 // UpdateStats updates the backup statistics
 func (sm *StateManager) UpdateStats(stats map[string]BackupStats) error {
 	sm.mu.Lock()
@@ -304,6 +334,14 @@ func (sm *StateManager) GetTargetState(targetName string) TargetState {
 	return sm.state.Targets[targetName]
 }
 
+// GetSourceState returns the state of a specific source
+func (sm *StateManager) GetSourceState(sourceName string) SourceState {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return sm.state.Sources[sourceName]
+}
+
 // GetMissedBackups returns all missed backups
 func (sm *StateManager) GetMissedBackups() []MissedBackup {
 	sm.mu.RLock()
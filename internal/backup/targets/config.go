@@ -0,0 +1,70 @@
+package targets
+
+import (
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// FromConfig creates the backup target described by a configured target entry
+func FromConfig(cfg *conf.BackupTarget, lg logger.Logger) (backup.Target, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "local":
+		path, _ := cfg.Settings["path"].(string)
+		debug, _ := cfg.Settings["debug"].(bool)
+		return nonNil(NewLocalTarget(LocalTargetConfig{Path: path, Debug: debug}, lg))
+	case "ftp":
+		return nonNil(NewFTPTargetFromMap(cfg.Settings))
+	case "sftp":
+		return nonNil(NewSFTPTarget(cfg.Settings, lg))
+	case "s3":
+		return nonNil(NewS3Target(cfg.Settings, lg))
+	case "rsync":
+		return nonNil(NewRsyncTarget(cfg.Settings, lg))
+	case "gdrive", "googledrive":
+		return nonNil(NewGDriveTargetFromMap(cfg.Settings))
+	default:
+		return nil, errors.Newf("unsupported backup target type: %s", cfg.Type).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "create_target").
+			Build()
+	}
+}
+
+// nonNil converts a constructor result to a backup.Target, keeping a nil
+// pointer on error from becoming a non-nil interface
+func nonNil[T backup.Target](target T, err error) (backup.Target, error) {
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// RegisterConfigured creates and registers every enabled target in the
+// backup configuration. Targets that fail to initialize are skipped and
+// their errors returned together.
+func RegisterConfigured(m *backup.Manager, cfg *conf.BackupConfig, lg logger.Logger) error {
+	var errs []error
+	for i := range cfg.Targets {
+		if !cfg.Targets[i].Enabled {
+			continue
+		}
+		target, err := FromConfig(&cfg.Targets[i], lg)
+		if err == nil {
+			err = m.RegisterTarget(target)
+		}
+		if err != nil {
+			errs = append(errs, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryConfiguration).
+				Context("operation", "register_target").
+				Context("target_type", cfg.Targets[i].Type).
+				Build())
+		}
+	}
+	return errors.Join(errs...)
+}
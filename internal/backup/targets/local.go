@@ -584,6 +584,72 @@ func (t *LocalTarget) List(ctx context.Context) ([]backup.BackupInfo, error) {
 	return backups, nil
 }
 
+// Retrieve implements the backup.Retriever interface. id may be the backup
+// ID or the archive name; the archive is the file named id or id plus an
+// archive extension.
+func (t *LocalTarget) Retrieve(ctx context.Context, id string, w io.Writer) error {
+	if !filepath.IsLocal(id) || strings.ContainsAny(id, `/\`) {
+		return errors.Newf("backup ID must be a plain file name").
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "retrieve_backup").
+			Build()
+	}
+
+	for _, name := range []string{id + ".tar.enc", id + ".tar", id} {
+		f, err := t.sfs.Open(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "open_backup_file").
+				Context("backup_id", id).
+				Build()
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				t.log.Debug("local: failed to close backup file", logger.String("name", name), logger.Error(err))
+			}
+		}()
+
+		if t.debug {
+			t.log.Info(fmt.Sprintf("🔄 Retrieving backup %s from local target", name))
+		}
+		if _, err := io.Copy(w, &contextReader{ctx: ctx, r: f}); err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "retrieve_backup").
+				Context("backup_id", id).
+				Build()
+		}
+		return nil
+	}
+
+	return errors.Newf("backup not found: %s", id).
+		Component("backup").
+		Category(errors.CategoryNotFound).
+		Context("operation", "retrieve_backup").
+		Context("backup_id", id).
+		Build()
+}
+
+// contextReader stops a copy when its context is cancelled
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
 // Delete removes a backup
 func (t *LocalTarget) Delete(ctx context.Context, backupID string) error {
 	if t.debug {
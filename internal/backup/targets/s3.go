@@ -0,0 +1,497 @@
+package targets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// S3-specific constants (shared constants imported from common.go)
+const (
+	s3DefaultEndpoint   = "s3.amazonaws.com"
+	s3DefaultPartSizeMB = 64 // multipart part size; S3 requires at least 5 MiB
+	s3MinPartSizeMB     = 5
+	s3UploadThreads     = 4
+	s3ValidateObject    = "write-test"
+
+	// User metadata keys; the S3 client adds the X-Amz-Meta- prefix.
+	s3MetaBackupID  = "Backup-Id"
+	s3MetaSource    = "Backup-Source"
+	s3MetaType      = "Backup-Type"
+	s3MetaTimestamp = "Backup-Timestamp"
+	s3MetaChecksum  = "Backup-Checksum"
+)
+
+// S3TargetConfig holds configuration for the S3 target
+type S3TargetConfig struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Prefix          string
+	UseSSL          bool
+	PathStyle       bool // force path-style bucket addressing (MinIO, Garage)
+	UseTags         bool // also store metadata as object tags (not supported by Garage)
+	PartSizeMB      int
+	Timeout         time.Duration
+	Debug           bool
+}
+
+// S3Target implements the backup.Target interface for Amazon S3 and
+// S3-compatible object stores such as MinIO and Garage.
+//
+// Each backup is stored as <prefix>/<archive name> with a JSON sidecar
+// <prefix>/<archive name>.meta holding the full backup.Metadata. Sidecars
+// work on every S3 implementation; key fields are additionally attached as
+// user metadata, and as object tags when enabled, so backups can be
+// identified in the provider's console.
+type S3Target struct {
+	config S3TargetConfig
+	client *minio.Client
+	log    logger.Logger
+}
+
+// NewS3Target creates a new S3 target with the given configuration. Keys
+// match conf.S3BackupSettings.
+func NewS3Target(settings map[string]any, lg logger.Logger) (*S3Target, error) {
+	p := NewSettingsParser(settings)
+
+	config := S3TargetConfig{
+		// Required settings
+		Bucket: p.RequireString("bucket", "s3"),
+		Region: p.RequireString("region", "s3"),
+
+		// Optional settings with defaults
+		Endpoint:        p.OptionalString("endpoint", s3DefaultEndpoint),
+		AccessKeyID:     p.OptionalString("accesskeyid", ""),
+		SecretAccessKey: p.OptionalString("secretaccesskey", ""),
+		Prefix:          strings.Trim(p.OptionalString("prefix", ""), "/"),
+		UseSSL:          p.OptionalBool("usessl", true),
+		PathStyle:       p.OptionalBool("pathstyle", false),
+		UseTags:         p.OptionalBool("usetags", false),
+		PartSizeMB:      p.OptionalInt("partsizemb", s3DefaultPartSizeMB),
+		Timeout:         p.OptionalDuration("timeout", DefaultTimeout, "s3"),
+		Debug:           p.OptionalBool("debug", false),
+	}
+
+	if err := p.Error(); err != nil {
+		return nil, err
+	}
+	if config.PartSizeMB < s3MinPartSizeMB {
+		return nil, backup.NewError(backup.ErrConfig, "s3: partsizemb must be at least 5", nil)
+	}
+
+	host, secure, err := parseS3Endpoint(config.Endpoint, config.UseSSL)
+	if err != nil {
+		return nil, err
+	}
+	config.UseSSL = secure
+
+	lookup := minio.BucketLookupAuto
+	if config.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	var creds *credentials.Credentials
+	if config.AccessKeyID != "" {
+		creds = credentials.NewStaticV4(config.AccessKeyID, config.SecretAccessKey, "")
+	} else {
+		// Fall back to the standard AWS environment and instance credentials.
+		creds = credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.IAM{},
+		})
+	}
+
+	client, err := minio.New(host, &minio.Options{
+		Creds:        creds,
+		Secure:       config.UseSSL,
+		Region:       config.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, backup.NewError(backup.ErrConfig, "s3: failed to create client", err)
+	}
+
+	if lg == nil {
+		lg = logger.Global().Module("backup")
+	}
+
+	return &S3Target{
+		config: config,
+		client: client,
+		log:    lg.Module("s3"),
+	}, nil
+}
+
+// parseS3Endpoint accepts either a bare host[:port] or a URL. An explicit
+// http:// or https:// scheme overrides the usessl setting.
+func parseS3Endpoint(endpoint string, useSSL bool) (host string, secure bool, err error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if !strings.Contains(endpoint, "://") {
+		return strings.TrimRight(endpoint, "/"), useSSL, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "", false, backup.NewError(backup.ErrConfig, "s3: invalid endpoint "+endpoint, err)
+	}
+	if u.Path != "" && u.Path != "/" {
+		return "", false, backup.NewError(backup.ErrConfig, "s3: endpoint must not contain a path; use prefix instead", nil)
+	}
+	switch u.Scheme {
+	case "https":
+		return u.Host, true, nil
+	case "http":
+		return u.Host, false, nil
+	default:
+		return "", false, backup.NewError(backup.ErrConfig, "s3: unsupported endpoint scheme "+u.Scheme, nil)
+	}
+}
+
+// Name returns the name of this target
+func (t *S3Target) Name() string {
+	return "s3"
+}
+
+// objectKey returns the full object key for a name below the prefix.
+func (t *S3Target) objectKey(name string) string {
+	if t.config.Prefix == "" {
+		return name
+	}
+	return t.config.Prefix + "/" + name
+}
+
+// listPrefix returns the prefix used to list this target's objects.
+func (t *S3Target) listPrefix() string {
+	if t.config.Prefix == "" {
+		return ""
+	}
+	return t.config.Prefix + "/"
+}
+
+// validateName ensures an archive name is a single safe key component.
+func (t *S3Target) validateName(name string) error {
+	_, err := ValidatePathWithOpts(name, PathValidationOpts{
+		AllowHidden:    false,
+		AllowAbsolute:  false,
+		ConvertToSlash: true,
+	})
+	if err != nil {
+		return err
+	}
+	if strings.Contains(filepath.ToSlash(name), "/") {
+		return backup.NewError(backup.ErrValidation, "s3: backup name must not contain path separators", nil)
+	}
+	return nil
+}
+
+// Store implements the backup.Target interface. Archives larger than the
+// configured part size are uploaded with S3 multipart upload; a failed
+// multipart upload is aborted so no orphaned parts are billed.
+func (t *S3Target) Store(ctx context.Context, sourcePath string, metadata *backup.Metadata) error {
+	name := filepath.Base(sourcePath)
+	if err := t.validateName(name); err != nil {
+		return err
+	}
+
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "marshal_metadata").
+			Build()
+	}
+
+	key := t.objectKey(name)
+	opts := minio.PutObjectOptions{
+		ContentType:  "application/octet-stream",
+		UserMetadata: s3UserMetadata(metadata),
+		PartSize:     uint64(t.config.PartSizeMB) * 1024 * 1024, //nolint:gosec // G115: validated >= 5 in NewS3Target
+		NumThreads:   s3UploadThreads,
+	}
+	if t.config.UseTags {
+		opts.UserTags = opts.UserMetadata
+	}
+
+	start := time.Now()
+	info, err := t.client.FPutObject(ctx, t.config.Bucket, key, sourcePath, opts)
+	if err != nil {
+		return t.wrapError(err, "upload_backup", key)
+	}
+
+	// The sidecar is written last: List only reports backups whose sidecar
+	// exists, so an interrupted upload never shows up as a usable backup.
+	if _, err := t.client.PutObject(ctx, t.config.Bucket, key+MetadataFileExt,
+		bytes.NewReader(metadataBytes), int64(len(metadataBytes)),
+		minio.PutObjectOptions{ContentType: "application/json"}); err != nil {
+		return t.wrapError(err, "store_metadata", key+MetadataFileExt)
+	}
+
+	if t.config.Debug {
+		t.log.Debug("S3: Stored backup",
+			logger.String("bucket", t.config.Bucket),
+			logger.String("key", key),
+			logger.Int64("size", info.Size),
+			logger.Duration("duration", time.Since(start)))
+	}
+	return nil
+}
+
+// s3UserMetadata returns the metadata fields attached to the archive object.
+func s3UserMetadata(m *backup.Metadata) map[string]string {
+	meta := map[string]string{
+		s3MetaBackupID:  m.ID,
+		s3MetaSource:    m.Source,
+		s3MetaType:      m.Type,
+		s3MetaTimestamp: m.Timestamp.UTC().Format(time.RFC3339),
+	}
+	if m.Checksum != "" {
+		meta[s3MetaChecksum] = m.Checksum
+	}
+	return meta
+}
+
+// List implements the backup.Target interface. Backups are returned newest first.
+func (t *S3Target) List(ctx context.Context) ([]backup.BackupInfo, error) {
+	objects := make(map[string]minio.ObjectInfo)
+	var sidecars []string
+	for obj := range t.client.ListObjects(ctx, t.config.Bucket, minio.ListObjectsOptions{
+		Prefix:    t.listPrefix(),
+		Recursive: false,
+	}) {
+		if obj.Err != nil {
+			return nil, t.wrapError(obj.Err, "list_backups", t.listPrefix())
+		}
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		if strings.HasSuffix(obj.Key, MetadataFileExt) {
+			sidecars = append(sidecars, obj.Key)
+			continue
+		}
+		objects[obj.Key] = obj
+	}
+
+	backups := make([]backup.BackupInfo, 0, len(sidecars))
+	for _, sidecar := range sidecars {
+		key := strings.TrimSuffix(sidecar, MetadataFileExt)
+		obj, ok := objects[key]
+		if !ok {
+			if t.config.Debug {
+				t.log.Debug("S3: Skipping orphaned metadata object", logger.String("key", sidecar))
+			}
+			continue
+		}
+		metadata, err := t.readMetadata(ctx, sidecar)
+		if err != nil {
+			t.log.Warn("S3: Skipping backup with unreadable metadata",
+				logger.String("key", sidecar),
+				logger.Error(err))
+			continue
+		}
+		if metadata.Size == 0 {
+			metadata.Size = obj.Size
+		}
+		backups = append(backups, backup.BackupInfo{
+			Metadata: *metadata,
+			Target:   t.Name(),
+		})
+	}
+
+	slices.SortFunc(backups, func(a, b backup.BackupInfo) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+	return backups, nil
+}
+
+// readMetadata downloads and decodes a metadata sidecar.
+func (t *S3Target) readMetadata(ctx context.Context, key string) (*backup.Metadata, error) {
+	obj, err := t.client.GetObject(ctx, t.config.Bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, t.wrapError(err, "read_metadata", key)
+	}
+	defer func() { _ = obj.Close() }()
+
+	var metadata backup.Metadata
+	if err := json.NewDecoder(obj).Decode(&metadata); err != nil {
+		return nil, t.wrapError(err, "decode_metadata", key)
+	}
+	return &metadata, nil
+}
+
+// Retrieve implements the backup.Retriever interface. id may be the backup
+// ID or the archive name, matched as in Delete.
+func (t *S3Target) Retrieve(ctx context.Context, id string, w io.Writer) error {
+	if err := t.validateName(id); err != nil {
+		return err
+	}
+
+	key := ""
+	for obj := range t.client.ListObjects(ctx, t.config.Bucket, minio.ListObjectsOptions{
+		Prefix:    t.objectKey(id),
+		Recursive: false,
+	}) {
+		if obj.Err != nil {
+			return t.wrapError(obj.Err, "list_backup_objects", id)
+		}
+		name := path.Base(obj.Key)
+		if strings.HasSuffix(name, MetadataFileExt) {
+			continue
+		}
+		if name == id || strings.HasPrefix(name, id+".") {
+			key = obj.Key
+			break
+		}
+	}
+	if key == "" {
+		return errors.Newf("backup not found: %s", id).
+			Component("backup").
+			Category(errors.CategoryNotFound).
+			Context("operation", "retrieve_backup").
+			Context("backup_id", id).
+			Build()
+	}
+
+	obj, err := t.client.GetObject(ctx, t.config.Bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return t.wrapError(err, "retrieve_backup", key)
+	}
+	defer func() { _ = obj.Close() }()
+
+	start := time.Now()
+	n, err := io.Copy(w, obj)
+	if err != nil {
+		return t.wrapError(err, "retrieve_backup", key)
+	}
+
+	if t.config.Debug {
+		t.log.Debug("S3: Retrieved backup",
+			logger.String("key", key),
+			logger.Int64("size", n),
+			logger.Duration("duration", time.Since(start)))
+	}
+	return nil
+}
+
+// Delete implements the backup.Target interface. id may be the backup ID or
+// the archive name; every archive whose name is id or starts with id plus an
+// extension is removed together with its sidecar.
+func (t *S3Target) Delete(ctx context.Context, id string) error {
+	if err := t.validateName(id); err != nil {
+		return err
+	}
+
+	var keys []string
+	for obj := range t.client.ListObjects(ctx, t.config.Bucket, minio.ListObjectsOptions{
+		Prefix:    t.objectKey(id),
+		Recursive: false,
+	}) {
+		if obj.Err != nil {
+			return t.wrapError(obj.Err, "list_backup_objects", id)
+		}
+		name := path.Base(obj.Key)
+		if name == id || strings.HasPrefix(name, id+".") {
+			keys = append(keys, obj.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return errors.Newf("backup not found: %s", id).
+			Component("backup").
+			Category(errors.CategoryNotFound).
+			Context("operation", "delete_backup").
+			Context("backup_id", id).
+			Build()
+	}
+
+	// Remove archives before sidecars so a partial failure leaves an orphaned
+	// sidecar (ignored by List) rather than an archive without metadata.
+	slices.SortStableFunc(keys, func(a, b string) int {
+		return boolToInt(strings.HasSuffix(a, MetadataFileExt)) - boolToInt(strings.HasSuffix(b, MetadataFileExt))
+	})
+
+	for _, key := range keys {
+		if err := t.client.RemoveObject(ctx, t.config.Bucket, key, minio.RemoveObjectOptions{}); err != nil {
+			return t.wrapError(err, "delete_backup", key)
+		}
+	}
+
+	if t.config.Debug {
+		t.log.Debug("S3: Deleted backup",
+			logger.String("backup_id", id),
+			logger.Int("objects", len(keys)))
+	}
+	return nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Validate checks that the bucket exists and is writable by storing and
+// removing a small test object under the prefix.
+func (t *S3Target) Validate() error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.config.Timeout)
+	defer cancel()
+
+	exists, err := t.client.BucketExists(ctx, t.config.Bucket)
+	if err != nil {
+		return t.wrapError(err, "validate_bucket", t.config.Bucket)
+	}
+	if !exists {
+		return errors.Newf("s3: bucket %s does not exist", t.config.Bucket).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "validate_bucket").
+			Build()
+	}
+
+	key := t.objectKey(s3ValidateObject)
+	data := []byte("test")
+	if _, err := t.client.PutObject(ctx, t.config.Bucket, key, bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{ContentType: "text/plain"}); err != nil {
+		return t.wrapError(err, "validate_write", key)
+	}
+	if err := t.client.RemoveObject(ctx, t.config.Bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		t.log.Warn("S3: Failed to delete validation object",
+			logger.String("key", key),
+			logger.Error(err))
+	}
+	return nil
+}
+
+// wrapError classifies an S3 client error.
+func (t *S3Target) wrapError(err error, operation, key string) error {
+	category := errors.CategoryNetwork
+	resp := minio.ToErrorResponse(err)
+	switch resp.StatusCode {
+	case HTTPUnauthorized, HTTPForbidden:
+		category = errors.CategoryConfiguration
+	case HTTPNotFound:
+		category = errors.CategoryNotFound
+	}
+	return errors.New(err).
+		Component("backup").
+		Category(category).
+		Context("operation", operation).
+		Context("bucket", t.config.Bucket).
+		Context("key", key).
+		Build()
+}
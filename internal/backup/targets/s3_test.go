@@ -0,0 +1,326 @@
+package targets
+
+import (
+	"bytes"
+	"crypto/md5" //nolint:gosec // ETag generation for the fake server only
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const fakeBucket = "birdnet"
+
+// fakeS3 is a minimal in-memory S3 server covering the calls S3Target makes:
+// bucket HEAD, object PUT/GET/DELETE, ListObjectsV2 and multipart upload.
+type fakeS3 struct {
+	mu        sync.Mutex
+	objects   map[string][]byte
+	headers   map[string]http.Header
+	uploads   map[string]map[int][]byte
+	multipart int // completed multipart uploads
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects: make(map[string][]byte),
+		headers: make(map[string]http.Header),
+		uploads: make(map[string]map[int][]byte),
+	}
+}
+
+func etag(b []byte) string {
+	sum := md5.Sum(b) //nolint:gosec // fake ETag
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != fakeBucket {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `<Error><Code>NoSuchBucket</Code></Error>`)
+		return
+	}
+	q := r.URL.Query()
+
+	switch {
+	case key == "" && r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case key == "" && r.Method == http.MethodGet:
+		f.list(w, q.Get("prefix"))
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		id := strconv.Itoa(len(f.uploads) + 1)
+		f.uploads[id] = make(map[int][]byte)
+		f.headers[key] = r.Header.Clone()
+		_, _ = fmt.Fprintf(w, `<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>`,
+			bucket, key, id)
+	case r.Method == http.MethodPut && q.Has("uploadId"):
+		body, _ := io.ReadAll(r.Body)
+		n, _ := strconv.Atoi(q.Get("partNumber"))
+		f.uploads[q.Get("uploadId")][n] = body
+		w.Header().Set("ETag", etag(body))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		parts := f.uploads[q.Get("uploadId")]
+		nums := make([]int, 0, len(parts))
+		for n := range parts {
+			nums = append(nums, n)
+		}
+		slices.Sort(nums)
+		var data []byte
+		for _, n := range nums {
+			data = append(data, parts[n]...)
+		}
+		f.objects[key] = data
+		f.multipart++
+		_, _ = fmt.Fprintf(w, `<CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><ETag>%s</ETag></CompleteMultipartUploadResult>`,
+			bucket, key, etag(data))
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+		f.headers[key] = r.Header.Clone()
+		w.Header().Set("ETag", etag(body))
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `<Error><Code>NoSuchKey</Code></Error>`)
+			return
+		}
+		w.Header().Set("ETag", etag(data))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix string) {
+	var sb strings.Builder
+	sb.WriteString(`<ListBucketResult><Name>` + fakeBucket + `</Name><IsTruncated>false</IsTruncated>`)
+	keys := make([]string, 0, len(f.objects))
+	for k := range f.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		var escaped bytes.Buffer
+		_ = xml.EscapeText(&escaped, []byte(k))
+		_, _ = fmt.Fprintf(&sb, `<Contents><Key>%s</Key><Size>%d</Size><LastModified>%s</LastModified><ETag>%s</ETag></Contents>`,
+			escaped.String(), len(f.objects[k]), time.Now().UTC().Format(time.RFC3339), etag(f.objects[k]))
+	}
+	sb.WriteString(`</ListBucketResult>`)
+	_, _ = io.WriteString(w, sb.String())
+}
+
+func (f *fakeS3) keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := make([]string, 0, len(f.objects))
+	for k := range f.objects {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// newTestS3Target returns a target talking to an in-memory S3 server over TLS.
+// TLS keeps the client from using aws-chunked streaming signatures, which the
+// fake server does not decode.
+func newTestS3Target(t *testing.T, settings map[string]any) (*S3Target, *fakeS3) {
+	t.Helper()
+	fake := newFakeS3()
+	srv := httptest.NewTLSServer(fake)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	base := map[string]any{
+		"endpoint":        srv.URL,
+		"region":          "garage",
+		"bucket":          fakeBucket,
+		"accesskeyid":     "GK0000",
+		"secretaccesskey": "secret",
+		"pathstyle":       true,
+	}
+	for k, v := range settings {
+		base[k] = v
+	}
+	target, err := NewS3Target(base, logger.Global().Module("backup"))
+	require.NoError(t, err)
+
+	target.client, err = minio.New(u.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4("GK0000", "secret", ""),
+		Secure:       true,
+		Region:       "garage",
+		BucketLookup: minio.BucketLookupPath,
+		Transport:    srv.Client().Transport,
+	})
+	require.NoError(t, err)
+	return target, fake
+}
+
+func writeArchive(t *testing.T, name string, size int) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	data := bytes.Repeat([]byte("birdnet!"), size/8+1)[:size]
+	require.NoError(t, os.WriteFile(p, data, 0o600))
+	return p
+}
+
+func TestS3Target_StoreListDelete(t *testing.T) {
+	t.Parallel()
+	target, fake := newTestS3Target(t, map[string]any{"prefix": "/nodes/garden/", "usetags": true})
+	ctx := t.Context()
+
+	older := &backup.Metadata{ID: "sqlite-20240101-030000", Timestamp: time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC), Type: "sqlite", Source: "birdnet.db"}
+	newer := &backup.Metadata{ID: "sqlite-20240102-030000", Timestamp: time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC), Type: "sqlite", Source: "birdnet.db", Checksum: "abc"}
+	require.NoError(t, target.Store(ctx, writeArchive(t, older.ID+".tar.gz", 1024), older))
+	require.NoError(t, target.Store(ctx, writeArchive(t, newer.ID+".tar.gz", 2048), newer))
+
+	assert.Equal(t, []string{
+		"nodes/garden/sqlite-20240101-030000.tar.gz",
+		"nodes/garden/sqlite-20240101-030000.tar.gz.meta",
+		"nodes/garden/sqlite-20240102-030000.tar.gz",
+		"nodes/garden/sqlite-20240102-030000.tar.gz.meta",
+	}, fake.keys())
+
+	hdr := fake.headers["nodes/garden/sqlite-20240102-030000.tar.gz"]
+	assert.Equal(t, newer.ID, hdr.Get("X-Amz-Meta-Backup-Id"))
+	assert.Equal(t, "abc", hdr.Get("X-Amz-Meta-Backup-Checksum"))
+	assert.Contains(t, hdr.Get("X-Amz-Tagging"), "Backup-Id="+newer.ID)
+
+	backups, err := target.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, newer.ID, backups[0].ID, "newest first")
+	assert.Equal(t, int64(2048), backups[0].Size, "size falls back to the object size")
+	assert.Equal(t, "s3", backups[0].Target)
+
+	require.NoError(t, target.Delete(ctx, older.ID))
+	assert.Equal(t, []string{
+		"nodes/garden/sqlite-20240102-030000.tar.gz",
+		"nodes/garden/sqlite-20240102-030000.tar.gz.meta",
+	}, fake.keys())
+
+	require.Error(t, target.Delete(ctx, older.ID), "deleting a missing backup reports not found")
+	require.Error(t, target.Delete(ctx, "../escape"))
+}
+
+func TestS3Target_MultipartUpload(t *testing.T) {
+	t.Parallel()
+	target, fake := newTestS3Target(t, map[string]any{"partsizemb": 5})
+
+	size := 11 * 1024 * 1024
+	archive := writeArchive(t, "mysql-20240101-030000.tar", size)
+	require.NoError(t, target.Store(t.Context(), archive, &backup.Metadata{ID: "mysql-20240101-030000", Timestamp: time.Now()}))
+
+	want, err := os.ReadFile(archive)
+	require.NoError(t, err)
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Equal(t, 1, fake.multipart)
+	assert.Equal(t, want, fake.objects["mysql-20240101-030000.tar"])
+	assert.Equal(t, "mysql-20240101-030000", fake.headers["mysql-20240101-030000.tar"].Get("X-Amz-Meta-Backup-Id"))
+}
+
+func TestS3Target_Retrieve(t *testing.T) {
+	t.Parallel()
+	target, _ := newTestS3Target(t, map[string]any{"prefix": "garden"})
+	ctx := t.Context()
+
+	archive := writeArchive(t, "sqlite-20240101-030000.tar.enc", 4096)
+	// A backup whose ID extends the first one must not be picked up instead
+	require.NoError(t, target.Store(ctx, writeArchive(t, "sqlite-20240101-030000-2.tar", 64), &backup.Metadata{ID: "sqlite-20240101-030000-2", Timestamp: time.Now()}))
+	require.NoError(t, target.Store(ctx, archive, &backup.Metadata{ID: "sqlite-20240101-030000", Timestamp: time.Now()}))
+
+	var buf bytes.Buffer
+	require.NoError(t, target.Retrieve(ctx, "sqlite-20240101-030000", &buf))
+	want, err := os.ReadFile(archive)
+	require.NoError(t, err)
+	assert.Equal(t, want, buf.Bytes())
+
+	require.Error(t, target.Retrieve(ctx, "sqlite-20990101-030000", io.Discard), "retrieving a missing backup reports not found")
+}
+
+func TestS3Target_ListSkipsIncompleteBackups(t *testing.T) {
+	t.Parallel()
+	target, fake := newTestS3Target(t, nil)
+	fake.objects["no-sidecar.tar"] = []byte("data")
+	fake.objects["orphan.tar.meta"] = []byte(`{"id":"orphan"}`)
+	fake.objects["corrupt.tar"] = []byte("data")
+	fake.objects["corrupt.tar.meta"] = []byte("{not json")
+
+	backups, err := target.List(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+}
+
+func TestS3Target_Validate(t *testing.T) {
+	t.Parallel()
+	target, fake := newTestS3Target(t, map[string]any{"prefix": "backups"})
+	require.NoError(t, target.Validate())
+	assert.Empty(t, fake.keys(), "validation object is removed")
+
+	target.config.Bucket = "missing"
+	require.Error(t, target.Validate())
+}
+
+func TestNewS3Target_Config(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewS3Target(map[string]any{"region": "us-east-1"}, nil)
+	require.Error(t, err, "bucket is required")
+
+	_, err = NewS3Target(map[string]any{"bucket": "b", "region": "r", "partsizemb": 1}, nil)
+	require.Error(t, err, "part size below the S3 minimum is rejected")
+
+	_, err = NewS3Target(map[string]any{"bucket": "b", "region": "r", "endpoint": "ftp://host"}, nil)
+	require.Error(t, err)
+
+	tests := []struct {
+		endpoint string
+		useSSL   bool
+		host     string
+		secure   bool
+	}{
+		{"minio.local:9000", false, "minio.local:9000", false},
+		{"http://garage.lan:3900", true, "garage.lan:3900", false},
+		{"https://s3.eu-central-1.amazonaws.com/", false, "s3.eu-central-1.amazonaws.com", true},
+	}
+	for _, tt := range tests {
+		host, secure, err := parseS3Endpoint(tt.endpoint, tt.useSSL)
+		require.NoError(t, err, tt.endpoint)
+		assert.Equal(t, tt.host, host)
+		assert.Equal(t, tt.secure, secure)
+	}
+}
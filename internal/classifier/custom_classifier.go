@@ -0,0 +1,147 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// errTypeCustomClassification is the error_type recorded when the trained
+// head fails to score an embedding.
+const errTypeCustomClassification = "custom_classification"
+
+// CustomClassifier runs a user-trained CustomHead on BirdNET v2.4 clip
+// embeddings. Its labels are the user's own classes (local call types, a
+// neighbour's parrot, a recurring noise source), reported as species whose
+// scientific and common names are both the label.
+// Implements ModelInstance. Goroutine-safe via internal mutex.
+type CustomClassifier struct {
+	embedder *Embedder
+	head     *CustomHead
+	labels   []string // head labels in "Label_Label" species form
+	info     ModelInfo
+	mu       sync.Mutex
+}
+
+// NewCustomClassifier pairs a loaded embedder with a trained head. The
+// classifier takes ownership of the embedder and closes it on Close.
+func NewCustomClassifier(embedder *Embedder, head *CustomHead) (*CustomClassifier, error) {
+	if embedder.ModelID() != head.EmbeddingModel {
+		return nil, errors.Newf("custom classifier was trained on %s embeddings but %s is loaded; retrain it", head.EmbeddingModel, embedder.ModelID()).
+			Component("classifier.custom").
+			Category(errors.CategoryModelInit).
+			Context("embedding_model", embedder.ModelID()).
+			Build()
+	}
+	labels := make([]string, len(head.Labels))
+	for i, l := range head.Labels {
+		labels[i] = l + "_" + l
+	}
+	info := ModelRegistry[RegistryIDCustomClassifier]
+	info.Description = fmt.Sprintf("Custom classifier with %d labels", len(labels))
+	info.NumSpecies = len(labels)
+
+	GetLogger().Info("Custom classifier initialized",
+		logger.String("embedding_model", head.EmbeddingModel),
+		logger.Int("labels", len(labels)),
+		logger.Time("trained_at", head.TrainedAt))
+
+	return &CustomClassifier{embedder: embedder, head: head, labels: labels, info: info}, nil
+}
+
+// Predict embeds the first clip and scores it with the trained head.
+func (c *CustomClassifier) Predict(ctx context.Context, samples [][]float32) ([]datastore.Results, error) {
+	span, _ := startPredictSpan(ctx, RegistryIDCustomClassifier, samples)
+	defer span.Finish()
+
+	start := time.Now()
+
+	if len(samples) == 0 || len(samples[0]) == 0 {
+		span.markErrored(errTypeEmptySample)
+		return nil, errors.Newf("empty audio sample").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.embedder == nil {
+		span.markErrored(errTypeClassifierNil)
+		return nil, errors.Newf("custom classifier is not initialized").
+			Category(errors.CategoryModelInit).
+			Build()
+	}
+
+	embedding, err := c.embedder.Embed(samples[0])
+	if err != nil {
+		err = errors.New(err).
+			Category(errors.CategoryAudio).
+			Context("model", RegistryIDCustomClassifier).
+			Context("stage", "embedding_extraction").
+			Build()
+		recordPredictionFailure(span, RegistryIDCustomClassifier, errTypeEmbeddingExtraction, start, err)
+		return nil, err
+	}
+
+	scores, err := c.head.Scores(embedding)
+	if err != nil {
+		err = errors.New(err).
+			Category(errors.CategoryAudio).
+			Context("model", RegistryIDCustomClassifier).
+			Context("stage", "custom_classification").
+			Build()
+		recordPredictionFailure(span, RegistryIDCustomClassifier, errTypeCustomClassification, start, err)
+		return nil, err
+	}
+
+	results, err := pairLabelsAndConfidence(c.labels, scores)
+	if err != nil {
+		recordPredictionFailure(span, RegistryIDCustomClassifier, errTypeLabelMismatch, start, err)
+		return nil, err
+	}
+	topResults := getTopKResults(results, defaultTopKResults)
+
+	recordPredictionSuccess(span, len(topResults), start)
+	return topResults, nil
+}
+
+// Spec returns the audio requirements of the embeddings model.
+func (c *CustomClassifier) Spec() ModelSpec { return c.info.Spec }
+
+// ModelID returns the unique model identifier.
+func (c *CustomClassifier) ModelID() string { return c.info.ID }
+
+// ModelName returns the human-readable model name.
+func (c *CustomClassifier) ModelName() string { return c.info.Name }
+
+// ModelVersion returns the model version string.
+func (c *CustomClassifier) ModelVersion() string { return c.info.DetectionVersion }
+
+// NumSpecies returns the number of trained labels.
+func (c *CustomClassifier) NumSpecies() int { return len(c.labels) }
+
+// Labels returns the trained labels in species form.
+func (c *CustomClassifier) Labels() []string { return c.labels }
+
+// RuntimeInfo reports the ONNX Runtime CPU EP the embedder runs on; the head
+// itself is plain Go. Implements ModelInstance.
+func (c *CustomClassifier) RuntimeInfo() (device, backend, precision string) {
+	return deviceCPU, BackendONNX, ""
+}
+
+// Close releases the embedder.
+func (c *CustomClassifier) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.embedder != nil {
+		c.embedder.Close()
+		c.embedder = nil
+	}
+	return nil
+}
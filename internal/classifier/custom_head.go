@@ -0,0 +1,425 @@
+package classifier
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// customHeadVersion is the on-disk format version of a trained custom
+// classifier head.
+const customHeadVersion = 1
+
+// Custom classifier training limits and hyperparameters. The head is a
+// one-vs-rest logistic regression over L2-normalized embeddings, trained by
+// full-batch gradient descent. Normalized inputs keep the loss curvature
+// bounded, so a fixed learning rate converges without tuning.
+const (
+	// MinCustomLabelExamples is the number of labelled clips a label needs
+	// before it is trained; labels with fewer clips are skipped.
+	MinCustomLabelExamples = 3
+	// MaxCustomLabelLength is the longest accepted label, in characters.
+	MaxCustomLabelLength = 64
+
+	customHeadEpochs       = 400
+	customHeadLearningRate = 2.0
+	customHeadL2           = 1e-4
+)
+
+// customHeadFileMode is the permission of a saved head file.
+const customHeadFileMode = 0o644
+
+// CustomLabelStats summarizes how one label's classifier fits its training
+// clips at a 0.5 score cut-off.
+type CustomLabelStats struct {
+	Label     string  `json:"label"`
+	Positives int     `json:"positives"` // clips carrying the label
+	Negatives int     `json:"negatives"` // every other training clip
+	Recall    float64 `json:"recall"`    // share of positives scored above 0.5
+	Precision float64 `json:"precision"` // share of clips scored above 0.5 that are positives
+}
+
+// CustomHead is a trained custom classifier: one logistic regression per
+// label on top of clip embeddings from EmbeddingModel. It is persisted as
+// JSON next to the installed models.
+type CustomHead struct {
+	Version        int                `json:"version"`
+	EmbeddingModel string             `json:"embeddingModel"` // model ID of the embeddings it was trained on
+	Labels         []string           `json:"labels"`
+	Weights        [][]float32        `json:"weights"` // one row per label
+	Bias           []float32          `json:"bias"`
+	TrainedAt      time.Time          `json:"trainedAt"`
+	Stats          []CustomLabelStats `json:"stats"`
+	Skipped        []string           `json:"skipped,omitempty"` // labels with too few clips
+}
+
+// CustomTrainingSample is one embedded clip of a training set. An empty
+// Label marks a negative clip for every label.
+type CustomTrainingSample struct {
+	Label  string
+	Vector []float32
+}
+
+// ValidateCustomLabel trims label and checks it can name a custom class.
+// Labels become the species name of their detections, so the underscore
+// that separates scientific and common names in model labels is rejected.
+func ValidateCustomLabel(label string) (string, error) {
+	label = strings.TrimSpace(label)
+	switch {
+	case label == "":
+		return "", errors.Newf("label cannot be empty").
+			Component("classifier.custom").
+			Category(errors.CategoryValidation).
+			Build()
+	case utf8.RuneCountInString(label) > MaxCustomLabelLength:
+		return "", errors.Newf("label cannot be longer than %d characters", MaxCustomLabelLength).
+			Component("classifier.custom").
+			Category(errors.CategoryValidation).
+			Build()
+	case strings.Contains(label, "_"):
+		return "", errors.Newf("label cannot contain an underscore").
+			Component("classifier.custom").
+			Category(errors.CategoryValidation).
+			Build()
+	case strings.ContainsFunc(label, unicode.IsControl):
+		return "", errors.Newf("label cannot contain control characters").
+			Component("classifier.custom").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+	return label, nil
+}
+
+// TrainCustomHead fits a custom classifier head to samples embedded by
+// embeddingModel. Each label with at least MinCustomLabelExamples clips gets
+// its own classifier, trained against every other clip as negatives. That
+// includes clips of other labels and unlabelled clips, such as detections
+// reviewed as false positives or confirmed as a species that is no custom
+// label. Runs on the CPU; ctx cancels training between epochs.
+func TrainCustomHead(ctx context.Context, embeddingModel string, samples []CustomTrainingSample) (*CustomHead, error) {
+	if len(samples) == 0 {
+		return nil, errors.Newf("no training clips: label clips or review detections first").
+			Component("classifier.custom").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+	dim := len(samples[0].Vector)
+	inputs := make([][]float64, len(samples))
+	counts := make(map[string]int)
+	for i := range samples {
+		if len(samples[i].Vector) != dim || dim == 0 {
+			return nil, errors.Newf("training clips have mismatched embedding sizes").
+				Component("classifier.custom").
+				Category(errors.CategoryValidation).
+				Context("embedding_model", embeddingModel).
+				Build()
+		}
+		inputs[i] = normalizeEmbedding(samples[i].Vector)
+		if samples[i].Label != "" {
+			counts[samples[i].Label]++
+		}
+	}
+
+	head := &CustomHead{
+		Version:        customHeadVersion,
+		EmbeddingModel: embeddingModel,
+		TrainedAt:      time.Now(),
+	}
+	for label, n := range counts {
+		if n < MinCustomLabelExamples {
+			head.Skipped = append(head.Skipped, label)
+			continue
+		}
+		head.Labels = append(head.Labels, label)
+	}
+	slices.Sort(head.Labels)
+	slices.Sort(head.Skipped)
+	if len(head.Labels) == 0 {
+		return nil, errors.Newf("no label has the %d clips needed for training", MinCustomLabelExamples).
+			Component("classifier.custom").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+
+	for _, label := range head.Labels {
+		positives := counts[label]
+		if positives == len(samples) {
+			return nil, errors.Newf("label %q has no negative clips: label other sounds or review detections first", label).
+				Component("classifier.custom").
+				Category(errors.CategoryValidation).
+				Build()
+		}
+		targets := make([]bool, len(samples))
+		for i := range samples {
+			targets[i] = samples[i].Label == label
+		}
+		w, b, err := trainLogistic(ctx, inputs, targets, positives)
+		if err != nil {
+			return nil, err
+		}
+		head.Weights = append(head.Weights, w)
+		head.Bias = append(head.Bias, b)
+		head.Stats = append(head.Stats, fitStats(label, inputs, targets, w, b))
+	}
+	return head, nil
+}
+
+// trainLogistic fits one class-balanced logistic regression. Positives and
+// negatives each carry half of the total loss weight, so a rare label is not
+// drowned out by the negative clips.
+func trainLogistic(ctx context.Context, inputs [][]float64, targets []bool, positives int) (weights []float32, bias float32, err error) {
+	n := len(inputs)
+	dim := len(inputs[0])
+	posWeight := float64(n) / (2 * float64(positives))
+	negWeight := float64(n) / (2 * float64(n-positives))
+
+	w := make([]float64, dim)
+	grad := make([]float64, dim)
+	var b float64
+	for range customHeadEpochs {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, errors.New(err).
+				Component("classifier.custom").
+				Category(errors.CategoryCancellation).
+				Build()
+		}
+		clear(grad)
+		var gradB float64
+		for i, x := range inputs {
+			p := sigmoid(dot64(w, x) + b)
+			var diff float64
+			if targets[i] {
+				diff = (p - 1) * posWeight
+			} else {
+				diff = p * negWeight
+			}
+			for j, v := range x {
+				grad[j] += diff * v
+			}
+			gradB += diff
+		}
+		for j := range w {
+			w[j] -= customHeadLearningRate * (grad[j]/float64(n) + customHeadL2*w[j])
+		}
+		b -= customHeadLearningRate * gradB / float64(n)
+	}
+
+	weights = make([]float32, dim)
+	for j, v := range w {
+		weights[j] = float32(v)
+	}
+	return weights, float32(b), nil
+}
+
+// fitStats scores the training clips with a trained classifier.
+func fitStats(label string, inputs [][]float64, targets []bool, weights []float32, bias float32) CustomLabelStats {
+	w := make([]float64, len(weights))
+	for j, v := range weights {
+		w[j] = float64(v)
+	}
+	stats := CustomLabelStats{Label: label}
+	var truePos, predicted int
+	for i, x := range inputs {
+		hit := sigmoid(dot64(w, x)+float64(bias)) > 0.5
+		if targets[i] {
+			stats.Positives++
+		} else {
+			stats.Negatives++
+		}
+		if hit {
+			predicted++
+			if targets[i] {
+				truePos++
+			}
+		}
+	}
+	if stats.Positives > 0 {
+		stats.Recall = float64(truePos) / float64(stats.Positives)
+	}
+	if predicted > 0 {
+		stats.Precision = float64(truePos) / float64(predicted)
+	}
+	return stats
+}
+
+// Dim returns the embedding size the head expects.
+func (h *CustomHead) Dim() int {
+	if len(h.Weights) == 0 {
+		return 0
+	}
+	return len(h.Weights[0])
+}
+
+// Scores returns the per-label probabilities for one embedding, in Labels
+// order. The embedding is normalized first, matching training.
+func (h *CustomHead) Scores(embedding []float32) ([]float32, error) {
+	if len(embedding) != h.Dim() {
+		return nil, errors.Newf("embedding has %d dimensions, custom classifier expects %d", len(embedding), h.Dim()).
+			Component("classifier.custom").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+	x := normalizeEmbedding(embedding)
+	scores := make([]float32, len(h.Weights))
+	for k, row := range h.Weights {
+		z := float64(h.Bias[k])
+		for j, v := range row {
+			z += float64(v) * x[j]
+		}
+		scores[k] = float32(sigmoid(z))
+	}
+	return scores, nil
+}
+
+// validate checks a loaded head is internally consistent.
+func (h *CustomHead) validate() error {
+	if h.Version != customHeadVersion {
+		return errors.Newf("unsupported custom classifier version %d", h.Version).
+			Component("classifier.custom").
+			Category(errors.CategoryModelInit).
+			Build()
+	}
+	if len(h.Labels) == 0 || len(h.Weights) != len(h.Labels) || len(h.Bias) != len(h.Labels) {
+		return errors.Newf("custom classifier labels, weights and bias do not match").
+			Component("classifier.custom").
+			Category(errors.CategoryModelInit).
+			Build()
+	}
+	for _, row := range h.Weights {
+		if len(row) == 0 || len(row) != len(h.Weights[0]) {
+			return errors.Newf("custom classifier weight rows have mismatched sizes").
+				Component("classifier.custom").
+				Category(errors.CategoryModelInit).
+				Build()
+		}
+	}
+	return nil
+}
+
+// LoadCustomHead reads a trained head from path.
+func LoadCustomHead(path string) (*CustomHead, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is derived from the models directory
+	if err != nil {
+		return nil, errors.New(err).
+			Component("classifier.custom").
+			Category(errors.CategoryFileIO).
+			Context("path", path).
+			Build()
+	}
+	var head CustomHead
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, errors.New(err).
+			Component("classifier.custom").
+			Category(errors.CategoryModelInit).
+			Context("path", path).
+			Build()
+	}
+	if err := head.validate(); err != nil {
+		return nil, err
+	}
+	return &head, nil
+}
+
+// Save writes the head to path, replacing any earlier one atomically so a
+// concurrent load never sees a partial file.
+func (h *CustomHead) Save(path string) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return errors.New(err).
+			Component("classifier.custom").
+			Category(errors.CategoryModelInit).
+			Build()
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // G301: models directory is shared with other model files
+		return errors.New(err).
+			Component("classifier.custom").
+			Category(errors.CategoryFileIO).
+			Context("path", dir).
+			Build()
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.New(err).
+			Component("classifier.custom").
+			Category(errors.CategoryFileIO).
+			Context("path", dir).
+			Build()
+	}
+	tmpName := tmp.Name()
+	defer func() { _ = os.Remove(tmpName) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return errors.New(err).
+			Component("classifier.custom").
+			Category(errors.CategoryFileIO).
+			Context("path", tmpName).
+			Build()
+	}
+	if err := tmp.Chmod(customHeadFileMode); err != nil {
+		_ = tmp.Close()
+		return errors.New(err).
+			Component("classifier.custom").
+			Category(errors.CategoryFileIO).
+			Context("path", tmpName).
+			Build()
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.New(err).
+			Component("classifier.custom").
+			Category(errors.CategoryFileIO).
+			Context("path", tmpName).
+			Build()
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return errors.New(err).
+			Component("classifier.custom").
+			Category(errors.CategoryFileIO).
+			Context("path", path).
+			Build()
+	}
+	return nil
+}
+
+// normalizeEmbedding returns v scaled to unit length, in float64. A zero
+// vector is returned unchanged.
+func normalizeEmbedding(v []float32) []float64 {
+	out := make([]float64, len(v))
+	var norm float64
+	for i, x := range v {
+		out[i] = float64(x)
+		norm += out[i] * out[i]
+	}
+	if norm == 0 {
+		return out
+	}
+	norm = math.Sqrt(norm)
+	for i := range out {
+		out[i] /= norm
+	}
+	return out
+}
+
+// sigmoid is the logistic function.
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
+
+// dot64 returns the dot product of two equal-length vectors.
+func dot64(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
@@ -0,0 +1,122 @@
+package classifier
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clusterSample returns a training sample near the given axis of a small
+// embedding space, so each label forms its own separable cluster.
+func clusterSample(label string, axis int, jitter float32) CustomTrainingSample {
+	v := make([]float32, 8)
+	for i := range v {
+		v[i] = 0.05
+	}
+	v[axis] = 1
+	v[(axis+1)%len(v)] += jitter
+	return CustomTrainingSample{Label: label, Vector: v}
+}
+
+func TestTrainCustomHead_SeparatesLabels(t *testing.T) {
+	t.Parallel()
+
+	var samples []CustomTrainingSample
+	for i := range 4 {
+		jitter := float32(i) * 0.05
+		samples = append(samples,
+			clusterSample("Parrot", 0, jitter),
+			clusterSample("Pump", 2, jitter),
+			clusterSample("", 4, jitter), // reviewed negatives
+		)
+	}
+	samples = append(samples, clusterSample("Rare", 6, 0)) // too few clips
+
+	head, err := TrainCustomHead(t.Context(), "birdnet-v24", samples)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Parrot", "Pump"}, head.Labels)
+	assert.Equal(t, []string{"Rare"}, head.Skipped)
+	assert.Equal(t, 8, head.Dim())
+	require.Len(t, head.Stats, 2)
+	assert.Equal(t, 4, head.Stats[0].Positives)
+	assert.Equal(t, 9, head.Stats[0].Negatives)
+	assert.InDelta(t, 1.0, head.Stats[0].Recall, 0.001)
+	assert.InDelta(t, 1.0, head.Stats[0].Precision, 0.001)
+
+	parrot, err := head.Scores(clusterSample("", 0, 0.02).Vector)
+	require.NoError(t, err)
+	assert.Greater(t, parrot[0], float32(0.8), "parrot clip should score high as Parrot")
+	assert.Less(t, parrot[1], float32(0.2), "parrot clip should score low as Pump")
+
+	noise, err := head.Scores(clusterSample("", 4, 0.02).Vector)
+	require.NoError(t, err)
+	assert.Less(t, noise[0], float32(0.2))
+	assert.Less(t, noise[1], float32(0.2))
+
+	_, err = head.Scores(make([]float32, 3))
+	require.Error(t, err, "wrong embedding size")
+}
+
+func TestTrainCustomHead_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := TrainCustomHead(t.Context(), "m", nil)
+	require.Error(t, err, "no samples")
+
+	few := []CustomTrainingSample{clusterSample("Parrot", 0, 0), clusterSample("", 4, 0)}
+	_, err = TrainCustomHead(t.Context(), "m", few)
+	require.Error(t, err, "no label reaches the minimum")
+
+	onlyPositives := []CustomTrainingSample{
+		clusterSample("Parrot", 0, 0), clusterSample("Parrot", 0, 0.1), clusterSample("Parrot", 0, 0.2),
+	}
+	_, err = TrainCustomHead(t.Context(), "m", onlyPositives)
+	require.Error(t, err, "no negatives")
+
+	mismatched := append([]CustomTrainingSample{{Label: "Parrot", Vector: []float32{1}}}, onlyPositives...)
+	_, err = TrainCustomHead(t.Context(), "m", mismatched)
+	require.Error(t, err, "mismatched embedding sizes")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err = TrainCustomHead(ctx, "m", append(onlyPositives, clusterSample("", 4, 0)))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCustomHead_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	samples := []CustomTrainingSample{
+		clusterSample("Parrot", 0, 0), clusterSample("Parrot", 0, 0.1), clusterSample("Parrot", 0, 0.2),
+		clusterSample("", 4, 0), clusterSample("", 4, 0.1),
+	}
+	head, err := TrainCustomHead(t.Context(), "birdnet-v24", samples)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "custom", customClassifierFile)
+	require.NoError(t, head.Save(path))
+	loaded, err := LoadCustomHead(path)
+	require.NoError(t, err)
+	assert.Equal(t, head.Labels, loaded.Labels)
+	assert.Equal(t, head.Weights, loaded.Weights)
+	assert.Equal(t, "birdnet-v24", loaded.EmbeddingModel)
+
+	_, err = LoadCustomHead(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestValidateCustomLabel(t *testing.T) {
+	t.Parallel()
+
+	label, err := ValidateCustomLabel("  Neighbour's parrot ")
+	require.NoError(t, err)
+	assert.Equal(t, "Neighbour's parrot", label)
+
+	for _, bad := range []string{"", "   ", "Parrot_call", "line\nbreak", string(make([]rune, MaxCustomLabelLength+1))} {
+		_, err := ValidateCustomLabel(bad)
+		assert.Error(t, err, "label %q should be rejected", bad)
+	}
+}
@@ -0,0 +1,155 @@
+package classifier
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/inference"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// EmbedderSampleRate and EmbedderWindowSamples describe the input of the
+// BirdNET v2.4 embedding model: a three second window of 48 kHz mono audio.
+const (
+	EmbedderSampleRate    = 48000
+	EmbedderWindowSamples = 3 * EmbedderSampleRate
+)
+
+// Embedder computes BirdNET v2.4 embedding vectors for audio clips. It loads
+// the same embeddings model the bat pipeline chains into its classifier, but
+// as a standalone instance so clip indexing never contends with live
+// inference. Goroutine-safe via internal mutex.
+type Embedder struct {
+	extractor inference.EmbeddingExtractor
+	modelID   string
+	mu        sync.Mutex
+}
+
+// EmbedderConfig holds configuration for creating an Embedder.
+type EmbedderConfig struct {
+	ModelPath       string
+	Labels          []string
+	ONNXRuntimePath string
+	Threads         int
+}
+
+// NewEmbedder loads the embeddings model on ONNX Runtime.
+func NewEmbedder(cfg *EmbedderConfig) (*Embedder, error) {
+	if err := checkORTOrFail(cfg.ONNXRuntimePath, "Clip embeddings", "embeddings", "classifier.embedder"); err != nil {
+		return nil, err
+	}
+	if err := inference.InitONNXRuntime(cfg.ONNXRuntimePath); err != nil {
+		return nil, errors.New(err).
+			Component("classifier.embedder").
+			Category(errors.CategoryModelInit).
+			Context("onnx_runtime_path", cfg.ONNXRuntimePath).
+			Build()
+	}
+
+	cls, err := inference.NewONNXClassifier(cfg.ModelPath, inference.ONNXClassifierOptions{
+		Labels:              cfg.Labels,
+		Threads:             cfg.Threads,
+		SkipLabelValidation: true,
+	})
+	if err != nil {
+		return nil, errors.New(err).
+			Component("classifier.embedder").
+			Category(errors.CategoryModelInit).
+			Context("embedding_model", cfg.ModelPath).
+			Build()
+	}
+	ext, ok := cls.(inference.EmbeddingExtractor)
+	if !ok {
+		cls.Close()
+		return nil, errors.Newf("embedding model does not support embedding extraction; ensure it has 2 outputs").
+			Component("classifier.embedder").
+			Category(errors.CategoryModelInit).
+			Context("embedding_model", cfg.ModelPath).
+			Build()
+	}
+
+	modelID := EmbeddingModelID(cfg.ModelPath)
+	GetLogger().Info("Clip embedding model initialized",
+		logger.String("embedding_model", cfg.ModelPath),
+		logger.String("model_id", modelID))
+
+	return &Embedder{extractor: ext, modelID: modelID}, nil
+}
+
+// EmbeddingModelID returns the ID an Embedder loaded from path reports: the
+// model file name without its extension.
+func EmbeddingModelID(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// ModelID identifies the embeddings model. Vectors are only comparable
+// between clips embedded by the same model.
+func (e *Embedder) ModelID() string {
+	return e.modelID
+}
+
+// Embed returns the embedding vector for one window of 48 kHz samples.
+// Shorter input is zero padded and longer input truncated to
+// EmbedderWindowSamples.
+func (e *Embedder) Embed(samples []float32) ([]float32, error) {
+	if len(samples) == 0 {
+		return nil, errors.Newf("empty audio sample").
+			Component("classifier.embedder").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+	window := make([]float32, EmbedderWindowSamples)
+	copy(window, samples)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.extractor == nil {
+		return nil, errors.Newf("embedder is closed").
+			Component("classifier.embedder").
+			Category(errors.CategoryModelInit).
+			Build()
+	}
+	_, embeddings, err := e.extractor.PredictWithEmbeddings(window)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("classifier.embedder").
+			Category(errors.CategoryAudio).
+			Context("stage", "embedding_extraction").
+			Build()
+	}
+	if embeddings == nil {
+		return nil, errors.Newf("embedding model did not produce embeddings").
+			Component("classifier.embedder").
+			Category(errors.CategoryModelInit).
+			Context("embedding_model", e.modelID).
+			Build()
+	}
+	return embeddings, nil
+}
+
+// Close releases the model.
+func (e *Embedder) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.extractor != nil {
+		e.extractor.Close()
+		e.extractor = nil
+	}
+}
+
+// EmbeddingModelPath returns the embeddings model used for clip embeddings:
+// the configured path, else the bat pipeline's embedding model, else the
+// installed bat model's shared embeddings file. Empty when none is available.
+func (o *Orchestrator) EmbeddingModelPath(settings *conf.Settings) string {
+	if p := settings.Realtime.Audio.Export.Embeddings.ModelPath; p != "" {
+		return p
+	}
+	if p := settings.Bat.EmbeddingModel; p != "" {
+		return p
+	}
+	_, _, p := o.resolveInstalledPaths(RegistryIDBat)
+	return p
+}
@@ -232,6 +232,9 @@ func (mm *ModelManager) ScanInstalled() {
 		if updated.BSG.ModelPath != "" {
 			addIfMissing(conf.ModelIDBSG)
 		}
+		if _, err := os.Stat(customClassifierPathIn(mm.modelsDir)); err == nil {
+			addIfMissing(conf.ModelIDCustomClassifier)
+		}
 
 		if changed {
 			conf.StoreSettings(updated)
@@ -0,0 +1,117 @@
+package classifier
+
+import (
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// CustomClassifierHead returns the installed custom classifier head, or nil
+// when none has been trained yet.
+func (mm *ModelManager) CustomClassifierHead() (*CustomHead, error) {
+	path := customClassifierPathIn(mm.modelsDir)
+	if path == "" {
+		return nil, nil
+	}
+	head, err := LoadCustomHead(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return head, err
+}
+
+// InstallCustomClassifier saves a trained head, enables the custom
+// classifier in models.enabled so sources can select it, and hot-loads it,
+// replacing a running earlier head. A hot-load failure is logged and leaves
+// the head installed for the next start, mirroring a gallery install.
+func (mm *ModelManager) InstallCustomClassifier(head *CustomHead) error {
+	log := GetLogger()
+	path := customClassifierPathIn(mm.modelsDir)
+	if path == "" {
+		return errors.Newf("models directory is not set").
+			Component("classifier.model_manager").
+			Category(errors.CategoryConfiguration).
+			Build()
+	}
+	if err := head.Save(path); err != nil {
+		return err
+	}
+	mm.setCustomClassifierEnabled(true)
+
+	if mm.orchestrator == nil {
+		return nil
+	}
+	wasLoaded := mm.orchestrator.IsModelLoaded(RegistryIDCustomClassifier)
+	if err := mm.orchestrator.ReloadCustomClassifier(); err != nil {
+		log.Warn("Failed to hot-load custom classifier (will be available after restart)",
+			logger.Error(err))
+		return nil
+	}
+	if !wasLoaded && mm.orchestrator.IsModelLoaded(RegistryIDCustomClassifier) {
+		mm.notifyTopologyChanged()
+	}
+	log.Info("Custom classifier installed",
+		logger.Int("labels", len(head.Labels)),
+		logger.String("path", path))
+	return nil
+}
+
+// RemoveCustomClassifier unloads the custom classifier, disables it and
+// deletes its trained head. Labelled clips are kept for retraining.
+func (mm *ModelManager) RemoveCustomClassifier() error {
+	if mm.orchestrator != nil && mm.orchestrator.IsModelLoaded(RegistryIDCustomClassifier) {
+		if err := mm.orchestrator.UnloadModel(RegistryIDCustomClassifier); err != nil {
+			return err
+		}
+		mm.notifyTopologyChanged()
+	}
+	mm.setCustomClassifierEnabled(false)
+
+	path := customClassifierPathIn(mm.modelsDir)
+	if path == "" {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errors.New(err).
+			Component("classifier.model_manager").
+			Category(errors.CategoryFileIO).
+			Context("path", path).
+			Build()
+	}
+	return nil
+}
+
+// setCustomClassifierEnabled adds the custom classifier to, or removes it
+// from, models.enabled and persists the change. Uses clone-mutate-publish
+// like applyConfigForInstall.
+func (mm *ModelManager) setCustomClassifierEnabled(enabled bool) {
+	if mm.settings == nil {
+		return
+	}
+
+	mm.settingsMu.Lock()
+	defer mm.settingsMu.Unlock()
+
+	updated := conf.CloneSettings(conf.GetSettings())
+	isAlias := func(id string) bool { return strings.EqualFold(id, conf.ModelIDCustomClassifier) }
+	present := slices.ContainsFunc(updated.Models.Enabled, isAlias)
+	switch {
+	case enabled && !present:
+		updated.Models.Enabled = append(updated.Models.Enabled, conf.ModelIDCustomClassifier)
+	case !enabled && present:
+		updated.Models.Enabled = slices.DeleteFunc(updated.Models.Enabled, isAlias)
+	default:
+		return
+	}
+
+	conf.StoreSettings(updated)
+	if err := conf.SaveSettings(); err != nil {
+		GetLogger().Warn("Failed to persist settings after custom classifier change",
+			logger.Error(err))
+	}
+}
@@ -1513,3 +1513,31 @@ func TestModelManager_TopologyChangedCallback(t *testing.T) {
 	assert.NotPanics(t, mm.notifyTopologyChanged)
 	assert.Equal(t, int64(2), fired.Load(), "cleared callback must not fire")
 }
+
+func TestModelManager_CustomClassifierLifecycle(t *testing.T) {
+	t.Parallel()
+
+	mm := NewModelManager(t.TempDir(), nil, nil)
+
+	head, err := mm.CustomClassifierHead()
+	require.NoError(t, err)
+	assert.Nil(t, head, "no head before training")
+
+	trained, err := TrainCustomHead(t.Context(), "birdnet-v24", []CustomTrainingSample{
+		clusterSample("Parrot", 0, 0), clusterSample("Parrot", 0, 0.1), clusterSample("Parrot", 0, 0.2),
+		clusterSample("", 4, 0),
+	})
+	require.NoError(t, err)
+	require.NoError(t, mm.InstallCustomClassifier(trained))
+
+	head, err = mm.CustomClassifierHead()
+	require.NoError(t, err)
+	require.NotNil(t, head)
+	assert.Equal(t, []string{"Parrot"}, head.Labels)
+
+	require.NoError(t, mm.RemoveCustomClassifier())
+	head, err = mm.CustomClassifierHead()
+	require.NoError(t, err)
+	assert.Nil(t, head)
+	require.NoError(t, mm.RemoveCustomClassifier(), "removing twice is a no-op")
+}
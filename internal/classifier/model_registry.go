@@ -60,6 +60,8 @@ const (
 	RegistryIDBSG       = "BSG"
 	RegistryIDBat       = "Bat"
 	RegistryIDPerchV2   = "Perch_V2"
+
+	RegistryIDCustomClassifier = "Custom_Classifier"
 )
 
 // defaultBirdNETClassifierARM64Arch is the GOARCH for which container images
@@ -196,6 +198,16 @@ var ModelRegistry = map[string]ModelInfo{
 		Spec:             ModelSpec{SampleRate: 48000, ClipLength: 3 * time.Second},
 		ConfigAliases:    []string{conf.ModelIDBSG},
 	},
+	RegistryIDCustomClassifier: {
+		ID:               RegistryIDCustomClassifier,
+		Name:             "Custom Classifier",
+		Backend:          BackendONNX,
+		DetectionName:    "CustomClassifier",
+		DetectionVersion: "1.0",
+		Description:      "User-trained classifier on BirdNET v2.4 embeddings", // NumSpecies omitted: determined by the trained labels
+		Spec:             ModelSpec{SampleRate: 48000, ClipLength: 3 * time.Second},
+		ConfigAliases:    []string{conf.ModelIDCustomClassifier},
+	},
 }
 
 // isBirdNETV24Family reports whether id is the BirdNET v2.4 classifier.
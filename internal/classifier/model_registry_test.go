@@ -316,6 +316,8 @@ func TestRegistryIDConstants_MatchRegistryKeys(t *testing.T) {
 		"RegistryIDBSG":       RegistryIDBSG,
 		"RegistryIDBat":       RegistryIDBat,
 		"RegistryIDPerchV2":   RegistryIDPerchV2,
+
+		"RegistryIDCustomClassifier": RegistryIDCustomClassifier,
 	}
 	for name, id := range constants {
 		_, exists := ModelRegistry[id]
@@ -332,6 +334,7 @@ func TestKnownConfigIDs_AllModels(t *testing.T) {
 	assert.True(t, ids["perch_v2"], "perch_v2 config ID should be known")
 	assert.True(t, ids["bat"], "bat config ID should be known")
 	assert.True(t, ids["bsg"], "bsg config ID should be known")
+	assert.True(t, ids["custom_classifier"], "custom_classifier config ID should be known")
 	assert.False(t, ids["unknown"], "unknown config ID should not be known")
 }
 
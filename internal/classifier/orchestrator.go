@@ -1518,8 +1518,9 @@ func (o *Orchestrator) IsModelLoaded(registryID string) bool {
 // this map are recognized but not yet implemented; callers log a warning
 // and skip. Adding a new loader only requires one entry here.
 var modelLoaders = map[string]func(o *Orchestrator, threads int) error{
-	RegistryIDPerchV2: (*Orchestrator).loadPerch,
-	RegistryIDBat:     (*Orchestrator).loadBat,
+	RegistryIDPerchV2:          (*Orchestrator).loadPerch,
+	RegistryIDBat:              (*Orchestrator).loadBat,
+	RegistryIDCustomClassifier: (*Orchestrator).loadCustomClassifier,
 }
 
 // secondaryModelBuilder constructs (but does not register) a secondary model
@@ -0,0 +1,160 @@
+package classifier
+
+import (
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// customClassifierFile is the trained head's file name under the custom
+// subdirectory of the models directory.
+const customClassifierFile = "custom_classifier.json"
+
+// customClassifierPathIn returns where the trained head is stored under
+// modelsDir, or "" when modelsDir is not set.
+func customClassifierPathIn(modelsDir string) string {
+	if modelsDir == "" {
+		return ""
+	}
+	return filepath.Join(modelsDir, "custom", customClassifierFile)
+}
+
+// customClassifierPath returns where the trained head is stored. o.modelsDir
+// is set once at startup by SetModelsDir, so the read needs no lock (see
+// resolveInstalledPaths).
+func (o *Orchestrator) customClassifierPath() string {
+	return customClassifierPathIn(o.modelsDir)
+}
+
+// buildCustomClassifier constructs a CustomClassifier from the trained head
+// and the clip embeddings model WITHOUT registering it in o.models.
+func (o *Orchestrator) buildCustomClassifier(settings *conf.Settings, threads int) (*CustomClassifier, error) {
+	headPath := o.customClassifierPath()
+	if headPath == "" {
+		return nil, errors.Newf("models directory is not set").
+			Component("classifier.orchestrator").
+			Category(errors.CategoryModelInit).
+			Context("model", RegistryIDCustomClassifier).
+			Build()
+	}
+	head, err := LoadCustomHead(headPath)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("classifier.orchestrator").
+			Category(errors.CategoryModelInit).
+			Context("model", RegistryIDCustomClassifier).
+			Context("path", headPath).
+			Build()
+	}
+
+	embeddingModel := o.EmbeddingModelPath(settings)
+	if embeddingModel == "" {
+		return nil, errors.Newf("custom classifier needs the BirdNET v2.4 embeddings model; install the bat model or set the clip embeddings model path").
+			Component("classifier.orchestrator").
+			Category(errors.CategoryModelInit).
+			Context("model", RegistryIDCustomClassifier).
+			Build()
+	}
+	if id := EmbeddingModelID(embeddingModel); id != head.EmbeddingModel {
+		return nil, errors.Newf("custom classifier was trained on %s embeddings but %s is configured; retrain it", head.EmbeddingModel, id).
+			Component("classifier.orchestrator").
+			Category(errors.CategoryModelInit).
+			Context("model", RegistryIDCustomClassifier).
+			Build()
+	}
+
+	embedder, err := NewEmbedder(&EmbedderConfig{
+		ModelPath:       embeddingModel,
+		Labels:          settings.BirdNET.Labels,
+		ONNXRuntimePath: settings.BirdNET.ONNXRuntimePath,
+		Threads:         threads,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cc, err := NewCustomClassifier(embedder, head)
+	if err != nil {
+		embedder.Close()
+		return nil, err
+	}
+	return cc, nil
+}
+
+// loadCustomClassifier creates and registers the custom classifier.
+// o.mu.Lock() is held by the caller.
+func (o *Orchestrator) loadCustomClassifier(threads int) error {
+	before := o.captureRSSBefore()
+
+	cc, err := o.buildCustomClassifier(o.currentSettings(), threads)
+	if err != nil {
+		return err
+	}
+
+	o.models[cc.ModelID()] = &modelEntry{instance: cc}
+	o.deferWarmup(cc.ModelID(), before)
+
+	GetLogger().Info("Custom classifier loaded into Orchestrator",
+		logger.String("model_id", cc.ModelID()),
+		logger.Int("labels", cc.NumSpecies()))
+
+	return nil
+}
+
+// ReloadCustomClassifier picks up a newly trained head. A loaded classifier
+// is rebuilt and swapped into its existing entry so inference continues on
+// the old head until the new one is ready; an unloaded one is loaded when it
+// is listed in models.enabled. Does nothing otherwise.
+func (o *Orchestrator) ReloadCustomClassifier() error {
+	o.mu.RLock()
+	entry, loaded := o.models[RegistryIDCustomClassifier]
+	o.mu.RUnlock()
+
+	settings := o.currentSettings()
+	if !loaded {
+		if !slices.ContainsFunc(settings.Models.Enabled, func(id string) bool {
+			return strings.EqualFold(id, conf.ModelIDCustomClassifier)
+		}) {
+			return nil
+		}
+		return o.LoadModel(RegistryIDCustomClassifier)
+	}
+
+	threads := settings.BirdNET.Threads
+	if threads <= 0 {
+		threads = runtime.NumCPU()
+	}
+	before := o.captureRSSBefore()
+	cc, err := o.buildCustomClassifier(settings, threads)
+	if err != nil {
+		return err
+	}
+	func() {
+		o.inferenceMu.Lock()
+		defer o.inferenceMu.Unlock()
+		o.warmupAndRecordRSS(RegistryIDCustomClassifier, before, cc)
+	}()
+
+	entry.mu.Lock()
+	old := entry.instance
+	if old == nil {
+		// Unloaded while building; do not resurrect a detached entry.
+		entry.mu.Unlock()
+		_ = cc.Close()
+		return nil
+	}
+	entry.instance = cc
+	entry.mu.Unlock()
+
+	if err := old.Close(); err != nil {
+		GetLogger().Warn("failed to close old custom classifier after reload",
+			logger.Error(err))
+	}
+	GetLogger().Info("Custom classifier reloaded",
+		logger.Int("labels", cc.NumSpecies()))
+	return nil
+}
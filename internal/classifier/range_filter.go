@@ -106,6 +106,11 @@ func BuildRangeFilter(o *Orchestrator) error {
 	settings := o.CurrentSettings()
 
 	var includedSpecies []string
+	// locationSpecies holds the lists of deployment locations away from the
+	// station. Only the universal geomodel path can predict arbitrary
+	// locations; the legacy path leaves it nil, so every source uses the
+	// station list.
+	var locationSpecies map[string][]string
 
 	// Snapshot primary under read lock to avoid racing with Delete(), which sets
 	// o.primary = nil under o.mu.Lock(). All callers reach BuildRangeFilter
@@ -148,6 +153,9 @@ func BuildRangeFilter(o *Orchestrator) error {
 				Build()
 		}
 
+		// Deployment locations are predicted under the same lock as the station.
+		locationScores := predictLocationScores(up, settings, getWeekForFilter(today), threshold)
+
 		// Sync unmappedScore with the current PassUnmappedSpecies setting so
 		// that the legacy predictFilter path (which calls Predict()) sees the
 		// correct value without requiring a full ReloadRangeFilter.
@@ -171,48 +179,19 @@ func BuildRangeFilter(o *Orchestrator) error {
 		// matches() below stays off the dataset scan.
 		excluder := newExcludeMatcher(settings.Realtime.Species.Exclude, settings.BirdNET.Locale)
 
-		includedSpecies = make([]string, 0, len(scores))
-		for _, ss := range scores {
-			if !excluder.matches(ss.Label) {
-				includedSpecies = append(includedSpecies, ss.Label)
-			}
-		}
-
-		addUserOverrideSpecies(&includedSpecies, settings, allGeoLabels)
-
-		// When PassUnmappedSpecies is enabled, add classifier species that
-		// have no corresponding entry in the geomodel so they are not
-		// silently blocked by the species inclusion check in the processor.
 		var unmappedCount int
-		if settings.BirdNET.RangeFilter.PassUnmappedSpecies {
-			seen := make(map[string]bool, len(includedSpecies))
-			for _, s := range includedSpecies {
-				seen[s] = true
-			}
-			mapping := cachedMapping
-			if mapping == nil {
-				mapping = buildSpeciesMapping(settings.BirdNET.Labels, allGeoLabels)
-			}
-			// cachedMapping (mappedRangeFilter.classifierToGeo) is sized from the
-			// model's labels at load time and can be longer than the live settings
-			// snapshot during a concurrent model/settings reload, so bounds-check the
-			// snapshot index before reading it.
-			for i, geoIdx := range mapping {
-				if geoIdx == -1 && i < len(settings.BirdNET.Labels) {
-					label := settings.BirdNET.Labels[i]
-					if !seen[label] && !excluder.matches(label) {
-						includedSpecies = append(includedSpecies, label)
-						seen[label] = true
-						unmappedCount++
-					}
-				}
-			}
+		includedSpecies, unmappedCount = filterIncludedSpecies(scores, settings, allGeoLabels, cachedMapping, excluder)
+
+		locationSpecies = make(map[string][]string, len(locationScores))
+		for key, locScores := range locationScores {
+			locationSpecies[key], _ = filterIncludedSpecies(locScores, settings, allGeoLabels, cachedMapping, excluder)
 		}
 
 		GetLogger().Info("Range filter updated via universal geomodel path",
 			logger.Int("geomodel_species", len(scores)),
 			logger.Int("included_species", len(includedSpecies)),
 			logger.Int("unmapped_species_added", unmappedCount),
+			logger.Int("deployment_locations", len(locationSpecies)),
 			logger.Float64("threshold", float64(threshold)),
 			logger.String("duration", time.Since(start).String()))
 	} else {
@@ -242,6 +221,7 @@ func BuildRangeFilter(o *Orchestrator) error {
 	}
 
 	conf.UpdateIncludedSpecies(includedSpecies)
+	conf.UpdateLocationIncludedSpecies(locationSpecies)
 	if err := o.RebuildNameResolver(includedSpecies); err != nil {
 		// Non-fatal: the resolver keeps its previous snapshot and on-demand Lookup
 		// still resolves names. Log and continue so a name-index hiccup never blocks
@@ -253,6 +233,76 @@ func BuildRangeFilter(o *Orchestrator) error {
 	return nil
 }
 
+// filterIncludedSpecies turns geomodel scores into an inclusion list: it drops
+// excluded species, adds the user's force-include overrides and, when
+// PassUnmappedSpecies is enabled, the classifier species the geomodel does not
+// know. It returns the list and the number of unmapped species added.
+func filterIncludedSpecies(scores []SpeciesScore, settings *conf.Settings, allGeoLabels []string, cachedMapping []int, excluder excludeMatcher) (includedSpecies []string, unmappedCount int) {
+	includedSpecies = make([]string, 0, len(scores))
+	for _, ss := range scores {
+		if !excluder.matches(ss.Label) {
+			includedSpecies = append(includedSpecies, ss.Label)
+		}
+	}
+
+	addUserOverrideSpecies(&includedSpecies, settings, allGeoLabels)
+
+	// When PassUnmappedSpecies is enabled, add classifier species that
+	// have no corresponding entry in the geomodel so they are not
+	// silently blocked by the species inclusion check in the processor.
+	if settings.BirdNET.RangeFilter.PassUnmappedSpecies {
+		seen := make(map[string]bool, len(includedSpecies))
+		for _, s := range includedSpecies {
+			seen[s] = true
+		}
+		mapping := cachedMapping
+		if mapping == nil {
+			mapping = buildSpeciesMapping(settings.BirdNET.Labels, allGeoLabels)
+		}
+		// cachedMapping (mappedRangeFilter.classifierToGeo) is sized from the
+		// model's labels at load time and can be longer than the live settings
+		// snapshot during a concurrent model/settings reload, so bounds-check the
+		// snapshot index before reading it.
+		for i, geoIdx := range mapping {
+			if geoIdx == -1 && i < len(settings.BirdNET.Labels) {
+				label := settings.BirdNET.Labels[i]
+				if !seen[label] && !excluder.matches(label) {
+					includedSpecies = append(includedSpecies, label)
+					seen[label] = true
+					unmappedCount++
+				}
+			}
+		}
+	}
+	return includedSpecies, unmappedCount
+}
+
+// predictLocationScores predicts the geomodel scores of every source
+// deployment location away from the station, keyed by conf.LocationKey. A
+// location that fails to predict is logged and left out, so its sources fall
+// back to the station list. The caller must hold the primary model's lock.
+func predictLocationScores(up UniversalSpeciesPredictor, settings *conf.Settings, week, threshold float32) map[string][]SpeciesScore {
+	locations := settings.DeploymentLocations()
+	delete(locations, conf.LocationKey(settings.BirdNET.Latitude, settings.BirdNET.Longitude))
+	if len(locations) == 0 {
+		return nil
+	}
+
+	scores := make(map[string][]SpeciesScore, len(locations))
+	for key, loc := range locations {
+		locScores, err := up.PredictSpeciesScores(float32(loc[0]), float32(loc[1]), week, threshold)
+		if err != nil {
+			GetLogger().Warn("Range filter prediction failed for deployment location, using station list",
+				logger.Error(err),
+				logger.Float64("latitude", loc[0]),
+				logger.Float64("longitude", loc[1]))
+			continue
+		}
+		scores[key] = locScores
+	}
+	return scores
+}
+
 // matchingLabels returns every label that matches speciesName by its common or
 // scientific name, in canonical "Scientific_Common" form so callers can append
 // the label instead of the user's bare entry.
@@ -389,3 +389,50 @@ func TestGetWeekForFilter_MonthEndClamp(t *testing.T) {
 		assert.LessOrEqual(t, week, float32(48), "week above 48 for %s", date.Format(time.DateOnly))
 	}
 }
+
+// locationRangeFilter is a fakeUniversalRangeFilter that predicts different
+// species north of latitude 61.
+type locationRangeFilter struct {
+	fakeUniversalRangeFilter
+	northScores []SpeciesScore
+}
+
+func (f *locationRangeFilter) PredictSpeciesScores(lat, lon, week, threshold float32) ([]SpeciesScore, error) {
+	if lat > 61 {
+		return f.northScores, nil
+	}
+	return f.fakeUniversalRangeFilter.PredictSpeciesScores(lat, lon, week, threshold)
+}
+
+func TestBuildRangeFilter_DeploymentLocations(t *testing.T) {
+	settings := conftest.GetTestSettings()
+	settings.BirdNET.Latitude = 60.0
+	settings.BirdNET.Longitude = 25.0
+	settings.BirdNET.LocationConfigured = true
+	settings.BirdNET.RangeFilter.Threshold = 0.01
+	settings.Realtime.RTSP.Streams = []conf.StreamConfig{
+		{Name: "Lake", URL: "rtsp://lake/stream", Deployment: conf.DeploymentConfig{Latitude: 62.0, Longitude: 26.0}},
+		{Name: "Yard", URL: "rtsp://yard/stream", Deployment: conf.DeploymentConfig{Latitude: 60.0, Longitude: 25.0}},
+	}
+	conftest.SetTestSettings(settings)
+	t.Cleanup(func() { conftest.SetTestSettings(nil) })
+
+	rf := &locationRangeFilter{
+		fakeUniversalRangeFilter: fakeUniversalRangeFilter{
+			geoLabels: []string{"Turdus merula_Common Blackbird", "Gavia arctica_Arctic Loon"},
+			scores:    []SpeciesScore{{Score: 0.9, Label: "Turdus merula_Common Blackbird"}},
+		},
+		northScores: []SpeciesScore{{Score: 0.7, Label: "Gavia arctica_Arctic Loon"}},
+	}
+	require.NoError(t, BuildRangeFilter(buildTestOrchestrator(t, settings, rf)))
+
+	s := conf.GetSettings()
+	assert.Len(t, s.BirdNET.RangeFilter.LocationScientificNames, 1,
+		"a source at the station location uses the station list")
+
+	lake := &settings.Realtime.RTSP.Streams[0].Deployment
+	assert.True(t, s.IsSpeciesIncludedAt(lake, "Gavia arctica_Arctic Loon"))
+	assert.False(t, s.IsSpeciesIncludedAt(lake, "Turdus merula_Common Blackbird"))
+	assert.True(t, s.IsSpeciesIncludedAt(nil, "Turdus merula_Common Blackbird"))
+	assert.False(t, s.IsSpeciesIncludedAt(nil, "Gavia arctica_Arctic Loon"))
+}
@@ -0,0 +1,255 @@
+package cliptier
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotArchived is returned by Archiver.Recall for a clip that has not been
+// moved to the secondary store.
+var ErrNotArchived = errors.NewStd("clip is not archived")
+
+// recallTimeout bounds a recall. Recalls are shared between concurrent
+// requests for the same clip, so one client disconnecting must not cancel
+// the download for the others.
+const recallTimeout = 2 * time.Minute
+
+// Index records which clips live in the secondary store. It is implemented by
+// repository.ArchivedClipRepository.
+type Index interface {
+	Save(ctx context.Context, clip *entities.ArchivedClip) error
+	GetByClipPath(ctx context.Context, clipPath string) (*entities.ArchivedClip, error)
+	FilterArchived(ctx context.Context, clipPaths []string) (map[string]bool, error)
+}
+
+// Archiver moves clips between the clip export directory and a Store and
+// keeps the Index in step. Clip paths are relative to the export directory,
+// which is opened with os.Root for every operation.
+type Archiver struct {
+	store   Store
+	index   Index
+	clipDir string
+	recalls singleflight.Group
+	log     logger.Logger
+}
+
+// NewArchiver creates an archiver for clips below clipDir.
+func NewArchiver(store Store, index Index, clipDir string) *Archiver {
+	return &Archiver{
+		store:   store,
+		index:   index,
+		clipDir: clipDir,
+		log:     GetLogger(),
+	}
+}
+
+// Store returns the secondary store.
+func (a *Archiver) Store() Store { return a.store }
+
+// FilterArchived returns the subset of clipPaths that have been archived.
+func (a *Archiver) FilterArchived(ctx context.Context, clipPaths []string) (map[string]bool, error) {
+	return a.index.FilterArchived(ctx, clipPaths)
+}
+
+// Archive uploads the clip, and its spectrograms unless keepSpectrograms is
+// set, records it in the index and then removes the local copies. The local
+// files are only removed after the index records the move, so a failure at
+// any step leaves the clip playable from the local disk. Returns the number
+// of bytes freed.
+func (a *Archiver) Archive(ctx context.Context, clipPath string, keepSpectrograms bool) (int64, error) {
+	if err := validateKey(clipPath); err != nil {
+		return 0, err
+	}
+	root, err := os.OpenRoot(a.clipDir)
+	if err != nil {
+		return 0, fmt.Errorf("cliptier: open clip directory: %w", err)
+	}
+	defer func() { _ = root.Close() }()
+
+	size, err := a.upload(ctx, root, clipPath)
+	if err != nil {
+		return 0, err
+	}
+	sizes := map[string]int64{clipPath: size}
+
+	var spectrograms []string
+	if !keepSpectrograms {
+		for _, name := range findSpectrograms(root, clipPath) {
+			n, err := a.upload(ctx, root, name)
+			if err != nil {
+				return 0, err
+			}
+			spectrograms = append(spectrograms, name)
+			sizes[name] = n
+		}
+	}
+
+	if err := a.index.Save(ctx, &entities.ArchivedClip{
+		ClipPath:     clipPath,
+		Target:       a.store.Name(),
+		Location:     a.store.Location(),
+		FileSize:     size,
+		Spectrograms: spectrograms,
+	}); err != nil {
+		return 0, err
+	}
+
+	var freed int64
+	for name, n := range sizes {
+		if err := root.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			a.log.Warn("failed to remove archived file from clip directory",
+				logger.String("path", name),
+				logger.Error(err),
+				logger.String("operation", "tier_archive"))
+			continue
+		}
+		freed += n
+	}
+	return freed, nil
+}
+
+// upload copies one file below root to the store under the same key.
+func (a *Archiver) upload(ctx context.Context, root *os.Root, name string) (int64, error) {
+	f, err := root.Open(name)
+	if err != nil {
+		return 0, fmt.Errorf("cliptier: open %s: %w", name, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("cliptier: stat %s: %w", name, err)
+	}
+	if err := a.store.Put(ctx, name, f, info.Size()); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// Recall copies an archived clip back into the clip directory so it can be
+// served like any other clip. Concurrent recalls of the same clip share one
+// download. A recalled copy is removed again by the next tier cleanup run
+// once it is no longer being played; the archive keeps the clip.
+//
+// Returns ErrNotArchived if the clip is not in the index and ErrNotFound if
+// the store no longer holds it.
+func (a *Archiver) Recall(ctx context.Context, clipPath string) error {
+	if err := validateKey(clipPath); err != nil {
+		return err
+	}
+	_, err, _ := a.recalls.Do(clipPath, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recallTimeout)
+		defer cancel()
+		return nil, a.recall(ctx, clipPath)
+	})
+	return err
+}
+
+func (a *Archiver) recall(ctx context.Context, clipPath string) error {
+	if _, err := a.index.GetByClipPath(ctx, clipPath); err != nil {
+		if errors.Is(err, repository.ErrArchivedClipNotFound) {
+			return ErrNotArchived
+		}
+		return err
+	}
+
+	root, err := os.OpenRoot(a.clipDir)
+	if err != nil {
+		return fmt.Errorf("cliptier: open clip directory: %w", err)
+	}
+	defer func() { _ = root.Close() }()
+
+	if _, err := root.Stat(clipPath); err == nil {
+		return nil // already recalled
+	}
+	if err := root.MkdirAll(path.Dir(clipPath), permDir); err != nil {
+		return fmt.Errorf("cliptier: create directory for %s: %w", clipPath, err)
+	}
+
+	// The temporary name has no audio extension, so the retention scan and
+	// the clip reconciler ignore a partial download.
+	tmp := fmt.Sprintf("%s.recall-%d", clipPath, time.Now().UnixNano())
+	f, err := root.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, permFile)
+	if err != nil {
+		return fmt.Errorf("cliptier: create %s: %w", tmp, err)
+	}
+	start := time.Now()
+	err = a.store.Get(ctx, clipPath, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = root.Remove(tmp)
+		return err
+	}
+	if err := root.Rename(tmp, clipPath); err != nil {
+		_ = root.Remove(tmp)
+		return fmt.Errorf("cliptier: rename %s: %w", clipPath, err)
+	}
+
+	a.log.Info("recalled archived clip",
+		logger.String("clip_path", clipPath),
+		logger.String("target", a.store.Name()),
+		logger.Int64("duration_ms", time.Since(start).Milliseconds()),
+		logger.String("operation", "tier_recall"))
+	return nil
+}
+
+// findSpectrograms returns the spectrogram images rendered for a clip: the
+// legacy <base>.png and the sized <base>_<width>px*.png variants the media
+// API writes next to the clip.
+func findSpectrograms(root *os.Root, clipPath string) []string {
+	dir := path.Dir(clipPath)
+	base := strings.TrimSuffix(path.Base(clipPath), path.Ext(clipPath))
+
+	d, err := root.Open(dir)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = d.Close() }()
+	entries, err := d.ReadDir(-1)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.EqualFold(path.Ext(name), ".png") {
+			continue
+		}
+		if isSpectrogramOf(name, base) {
+			names = append(names, path.Join(dir, name))
+		}
+	}
+	return names
+}
+
+// isSpectrogramOf reports whether name is a spectrogram of the clip with the
+// given base name.
+func isSpectrogramOf(name, base string) bool {
+	stem := name[:len(name)-len(".png")]
+	if stem == base {
+		return true
+	}
+	rest, ok := strings.CutPrefix(stem, base+"_")
+	if !ok {
+		return false
+	}
+	digits := 0
+	for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+		digits++
+	}
+	return digits > 0 && strings.HasPrefix(rest[digits:], "px")
+}
@@ -0,0 +1,147 @@
+package cliptier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+)
+
+// memoryIndex is an in-memory Index.
+type memoryIndex struct {
+	mu    sync.Mutex
+	clips map[string]entities.ArchivedClip
+}
+
+func newMemoryIndex() *memoryIndex {
+	return &memoryIndex{clips: make(map[string]entities.ArchivedClip)}
+}
+
+func (m *memoryIndex) Save(_ context.Context, clip *entities.ArchivedClip) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clips[clip.ClipPath] = *clip
+	return nil
+}
+
+func (m *memoryIndex) GetByClipPath(_ context.Context, clipPath string) (*entities.ArchivedClip, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clip, ok := m.clips[clipPath]
+	if !ok {
+		return nil, repository.ErrArchivedClipNotFound
+	}
+	return &clip, nil
+}
+
+func (m *memoryIndex) FilterArchived(_ context.Context, clipPaths []string) (map[string]bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[string]bool)
+	for _, p := range clipPaths {
+		if _, ok := m.clips[p]; ok {
+			result[p] = true
+		}
+	}
+	return result, nil
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o644))
+}
+
+func TestArchiver_ArchiveAndRecall(t *testing.T) {
+	t.Parallel()
+	clipDir := t.TempDir()
+	archiveDir := t.TempDir()
+
+	const clip = "2026/05/parus_major_80p_20260501T060000Z.wav"
+	writeFile(t, filepath.Join(clipDir, clip), "audio")
+	writeFile(t, filepath.Join(clipDir, "2026/05/parus_major_80p_20260501T060000Z.png"), "legacy")
+	writeFile(t, filepath.Join(clipDir, "2026/05/parus_major_80p_20260501T060000Z_400px-legend.png"), "sized")
+	writeFile(t, filepath.Join(clipDir, "2026/05/parus_major_80p_20260501T061500Z.png"), "other clip")
+
+	store, err := NewLocalStore(archiveDir)
+	require.NoError(t, err)
+	index := newMemoryIndex()
+	archiver := NewArchiver(store, index, clipDir)
+
+	freed, err := archiver.Archive(t.Context(), clip, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("audio")+len("legacy")+len("sized")), freed)
+
+	// The clip and its spectrograms moved; the other clip's image stayed.
+	assert.NoFileExists(t, filepath.Join(clipDir, clip))
+	assert.NoFileExists(t, filepath.Join(clipDir, "2026/05/parus_major_80p_20260501T060000Z.png"))
+	assert.FileExists(t, filepath.Join(clipDir, "2026/05/parus_major_80p_20260501T061500Z.png"))
+	assert.FileExists(t, filepath.Join(archiveDir, clip))
+	assert.FileExists(t, filepath.Join(archiveDir, "2026/05/parus_major_80p_20260501T060000Z_400px-legend.png"))
+
+	record, err := index.GetByClipPath(t.Context(), clip)
+	require.NoError(t, err)
+	assert.Equal(t, "local", record.Target)
+	assert.Equal(t, archiveDir, record.Location)
+	assert.Equal(t, int64(len("audio")), record.FileSize)
+	assert.Len(t, record.Spectrograms, 2)
+
+	require.NoError(t, archiver.Recall(t.Context(), clip))
+	data, err := os.ReadFile(filepath.Join(clipDir, clip))
+	require.NoError(t, err)
+	assert.Equal(t, "audio", string(data))
+
+	// Recalling a clip that is already local is a no-op.
+	require.NoError(t, archiver.Recall(t.Context(), clip))
+}
+
+func TestArchiver_KeepSpectrograms(t *testing.T) {
+	t.Parallel()
+	clipDir := t.TempDir()
+	const clip = "2026/05/turdus_merula_90p_20260501T060000Z.wav"
+	writeFile(t, filepath.Join(clipDir, clip), "audio")
+	writeFile(t, filepath.Join(clipDir, "2026/05/turdus_merula_90p_20260501T060000Z.png"), "legacy")
+
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	archiver := NewArchiver(store, newMemoryIndex(), clipDir)
+
+	_, err = archiver.Archive(t.Context(), clip, true)
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(clipDir, clip))
+	assert.FileExists(t, filepath.Join(clipDir, "2026/05/turdus_merula_90p_20260501T060000Z.png"))
+}
+
+func TestArchiver_RecallErrors(t *testing.T) {
+	t.Parallel()
+	clipDir := t.TempDir()
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	index := newMemoryIndex()
+	archiver := NewArchiver(store, index, clipDir)
+
+	require.ErrorIs(t, archiver.Recall(t.Context(), "2026/05/missing.wav"), ErrNotArchived)
+
+	// Indexed but gone from the store.
+	require.NoError(t, index.Save(t.Context(), &entities.ArchivedClip{ClipPath: "2026/05/lost.wav", Target: "local"}))
+	require.ErrorIs(t, archiver.Recall(t.Context(), "2026/05/lost.wav"), ErrNotFound)
+	entries, err := os.ReadDir(filepath.Join(clipDir, "2026/05"))
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a failed recall must not leave a partial file")
+}
+
+func TestValidateKey(t *testing.T) {
+	t.Parallel()
+	for _, key := range []string{"a.wav", "2026/05/a.wav"} {
+		assert.NoError(t, validateKey(key), key)
+	}
+	for _, key := range []string{"", "/etc/passwd", "../a.wav", "2026/../../a.wav", "2026//a.wav", "./a.wav", `2026\a.wav`, ".."} {
+		assert.Error(t, validateKey(key), key)
+	}
+}
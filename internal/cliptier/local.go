@@ -0,0 +1,164 @@
+package cliptier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Permissions for archived clips. They match the clip export directory, which
+// the web server and other users on the host may read.
+const (
+	permDir  = 0o755
+	permFile = 0o644
+)
+
+// LocalStore keeps clips in a directory, typically a mounted NAS share. The
+// directory is opened with os.Root for every operation, so keys cannot
+// escape it and a share that is remounted between operations is picked up.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a store in dir. The directory must exist; it is not
+// created so that an unmounted share is reported instead of being filled on
+// the SD card underneath the mount point.
+func NewLocalStore(dir string) (*LocalStore, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return nil, errors.Newf("clip tier local path must not be empty").
+			Component("cliptier").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "create_local_store").
+			Build()
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Name returns "local".
+func (s *LocalStore) Name() string { return "local" }
+
+// Location returns the archive directory.
+func (s *LocalStore) Location() string { return s.dir }
+
+// Close is a no-op; the directory is opened per operation.
+func (s *LocalStore) Close() error { return nil }
+
+// open opens the archive directory as a root.
+func (s *LocalStore) open(operation string) (*os.Root, error) {
+	root, err := os.OpenRoot(s.dir)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("cliptier").
+			Category(errors.CategoryFileIO).
+			Context("operation", operation).
+			Context("path", s.dir).
+			Build()
+	}
+	return root, nil
+}
+
+// Put writes the clip to a temporary file next to its final name and renames
+// it into place, so a failed upload never leaves a truncated clip behind.
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	root, err := s.open("local_put")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = root.Close() }()
+
+	if err := root.MkdirAll(path.Dir(key), permDir); err != nil {
+		return fmt.Errorf("cliptier: create directory for %s: %w", key, err)
+	}
+
+	tmp := fmt.Sprintf("%s.tmp-%d", key, time.Now().UnixNano())
+	f, err := root.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, permFile)
+	if err != nil {
+		return fmt.Errorf("cliptier: create %s: %w", tmp, err)
+	}
+	written, err := io.Copy(f, contextReader{ctx: ctx, r: r})
+	if err == nil && written != size {
+		err = fmt.Errorf("wrote %d of %d bytes", written, size)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = root.Remove(tmp)
+		return fmt.Errorf("cliptier: write %s: %w", key, err)
+	}
+	if err := root.Rename(tmp, key); err != nil {
+		_ = root.Remove(tmp)
+		return fmt.Errorf("cliptier: rename %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get copies the stored clip to w.
+func (s *LocalStore) Get(ctx context.Context, key string, w io.Writer) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	root, err := s.open("local_get")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = root.Close() }()
+
+	f, err := root.Open(key)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("cliptier: open %s: %w", key, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := io.Copy(w, contextReader{ctx: ctx, r: f}); err != nil {
+		return fmt.Errorf("cliptier: read %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes the stored clip.
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	root, err := s.open("local_delete")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = root.Close() }()
+
+	if err := root.Remove(key); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("cliptier: delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// contextReader stops a copy when its context is cancelled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,216 @@
+package cliptier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// s3DefaultEndpoint is used when no endpoint is configured.
+const s3DefaultEndpoint = "s3.amazonaws.com"
+
+// S3Store keeps clips in an S3-compatible bucket as <prefix>/<key>.
+type S3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Store creates a store for the configured bucket. Without an access
+// key the standard AWS environment and instance credentials are used.
+func NewS3Store(settings *conf.ClipTierS3Settings) (*S3Store, error) {
+	if settings.Bucket == "" {
+		return nil, errors.Newf("clip tier S3 bucket must not be empty").
+			Component("cliptier").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "create_s3_store").
+			Build()
+	}
+
+	host, secure, err := parseS3Endpoint(settings.Endpoint, settings.UseSSL)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := minio.BucketLookupAuto
+	if settings.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	var creds *credentials.Credentials
+	if settings.AccessKeyID != "" {
+		creds = credentials.NewStaticV4(settings.AccessKeyID, settings.SecretAccessKey, "")
+	} else {
+		creds = credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.IAM{},
+		})
+	}
+
+	client, err := minio.New(host, &minio.Options{
+		Creds:        creds,
+		Secure:       secure,
+		Region:       settings.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, errors.New(err).
+			Component("cliptier").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "create_s3_store").
+			Build()
+	}
+
+	return &S3Store{
+		client: client,
+		bucket: settings.Bucket,
+		prefix: strings.Trim(settings.Prefix, "/"),
+	}, nil
+}
+
+// parseS3Endpoint accepts either a bare host[:port] or a URL. An explicit
+// http:// or https:// scheme overrides the usessl setting.
+func parseS3Endpoint(endpoint string, useSSL bool) (host string, secure bool, err error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return s3DefaultEndpoint, useSSL, nil
+	}
+	if !strings.Contains(endpoint, "://") {
+		return strings.TrimRight(endpoint, "/"), useSSL, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || (u.Path != "" && u.Path != "/") {
+		return "", false, errors.Newf("invalid clip tier S3 endpoint %q", endpoint).
+			Component("cliptier").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "parse_s3_endpoint").
+			Build()
+	}
+	switch u.Scheme {
+	case "https":
+		return u.Host, true, nil
+	case "http":
+		return u.Host, false, nil
+	default:
+		return "", false, errors.Newf("unsupported clip tier S3 endpoint scheme %q", u.Scheme).
+			Component("cliptier").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "parse_s3_endpoint").
+			Build()
+	}
+}
+
+// Name returns "s3".
+func (s *S3Store) Name() string { return "s3" }
+
+// Location returns the bucket URL and prefix.
+func (s *S3Store) Location() string {
+	location := "s3://" + s.bucket
+	if s.prefix != "" {
+		location += "/" + s.prefix
+	}
+	return location
+}
+
+// Close is a no-op; the client holds no persistent connections of its own.
+func (s *S3Store) Close() error { return nil }
+
+// objectKey returns the object key for a clip key below the prefix.
+func (s *S3Store) objectKey(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
+
+// Put uploads the clip.
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if _, err := s.client.PutObject(ctx, s.bucket, s.objectKey(key), r, size, minio.PutObjectOptions{
+		ContentType: contentType(key),
+	}); err != nil {
+		return s.wrapError(err, "s3_put", key)
+	}
+	return nil
+}
+
+// Get downloads the clip to w.
+func (s *S3Store) Get(ctx context.Context, key string, w io.Writer) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, s.objectKey(key), minio.GetObjectOptions{})
+	if err != nil {
+		return s.wrapError(err, "s3_get", key)
+	}
+	defer func() { _ = obj.Close() }()
+
+	// GetObject is lazy; a missing key surfaces on the first read.
+	if _, err := io.Copy(w, obj); err != nil {
+		if minio.ToErrorResponse(err).StatusCode == http.StatusNotFound {
+			return ErrNotFound
+		}
+		return s.wrapError(err, "s3_get", key)
+	}
+	return nil
+}
+
+// Delete removes the clip. S3 reports success for missing keys.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if err := s.client.RemoveObject(ctx, s.bucket, s.objectKey(key), minio.RemoveObjectOptions{}); err != nil {
+		return s.wrapError(err, "s3_delete", key)
+	}
+	return nil
+}
+
+// wrapError classifies an S3 client error.
+func (s *S3Store) wrapError(err error, operation, key string) error {
+	category := errors.CategoryNetwork
+	switch minio.ToErrorResponse(err).StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		category = errors.CategoryConfiguration
+	case http.StatusNotFound:
+		category = errors.CategoryNotFound
+	}
+	return errors.New(fmt.Errorf("cliptier: %s %s: %w", operation, key, err)).
+		Component("cliptier").
+		Category(category).
+		Context("operation", operation).
+		Context("bucket", s.bucket).
+		Build()
+}
+
+// contentType returns the MIME type stored with an uploaded object, so the
+// bucket console and presigned links serve clips with the right type.
+func contentType(key string) string {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".wav":
+		return "audio/wav"
+	case ".flac":
+		return "audio/flac"
+	case ".mp3":
+		return "audio/mpeg"
+	case ".m4a", ".aac":
+		return "audio/aac"
+	case ".opus":
+		return "audio/opus"
+	case ".png":
+		return "image/png"
+	default:
+		return "application/octet-stream"
+	}
+}
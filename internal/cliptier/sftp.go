@@ -0,0 +1,283 @@
+package cliptier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	sftpDefaultPort    = 22
+	sftpConnectTimeout = 30 * time.Second
+)
+
+// SFTPStore keeps clips in a directory on an SFTP server. It holds one
+// connection, opened on first use and reopened after a failed operation.
+// Host keys are verified against a known_hosts file.
+type SFTPStore struct {
+	settings conf.ClipTierSFTPSettings
+	basePath string
+
+	mu     sync.Mutex
+	ssh    *ssh.Client
+	client *sftp.Client
+}
+
+// NewSFTPStore creates a store for the configured server. No connection is
+// made until the first operation.
+func NewSFTPStore(settings *conf.ClipTierSFTPSettings) (*SFTPStore, error) {
+	if settings.Host == "" || settings.Username == "" {
+		return nil, errors.Newf("clip tier SFTP host and username must not be empty").
+			Component("cliptier").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "create_sftp_store").
+			Build()
+	}
+	s := *settings
+	if s.Port == 0 {
+		s.Port = sftpDefaultPort
+	}
+	if s.KnownHostsFile == "" {
+		if home, err := conf.GetUserHomeDir(); err == nil {
+			s.KnownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+		}
+	}
+	// Relative paths, and an empty one, are resolved against the login
+	// directory.
+	return &SFTPStore{settings: s, basePath: path.Clean(s.Path)}, nil
+}
+
+// Name returns "sftp".
+func (s *SFTPStore) Name() string { return "sftp" }
+
+// Location returns the server and remote directory.
+func (s *SFTPStore) Location() string {
+	return fmt.Sprintf("sftp://%s@%s/%s",
+		s.settings.Username,
+		net.JoinHostPort(s.settings.Host, strconv.Itoa(s.settings.Port)),
+		strings.TrimPrefix(s.basePath, "/"))
+}
+
+// Close closes the connection, if one is open.
+func (s *SFTPStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.disconnectLocked()
+}
+
+func (s *SFTPStore) disconnectLocked() error {
+	var err error
+	if s.client != nil {
+		err = s.client.Close()
+		s.client = nil
+	}
+	if s.ssh != nil {
+		if closeErr := s.ssh.Close(); err == nil {
+			err = closeErr
+		}
+		s.ssh = nil
+	}
+	return err
+}
+
+// clientConfig builds the SSH client configuration.
+func (s *SFTPStore) clientConfig() (*ssh.ClientConfig, error) {
+	if s.settings.KnownHostsFile == "" {
+		return nil, errors.Newf("clip tier SFTP needs a known_hosts file to verify the server").
+			Component("cliptier").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "sftp_known_hosts").
+			Build()
+	}
+	callback, err := knownhosts.New(s.settings.KnownHostsFile)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("cliptier").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "sftp_known_hosts").
+			Context("hint", "run 'ssh-keyscan <hostname> >> "+s.settings.KnownHostsFile+"' to add the host key").
+			Build()
+	}
+
+	config := &ssh.ClientConfig{
+		User:            s.settings.Username,
+		HostKeyCallback: callback,
+		Timeout:         sftpConnectTimeout,
+	}
+	switch {
+	case s.settings.PrivateKeyPath != "":
+		key, err := os.ReadFile(s.settings.PrivateKeyPath)
+		if err != nil {
+			return nil, errors.New(err).
+				Component("cliptier").
+				Category(errors.CategoryFileIO).
+				Context("operation", "sftp_read_private_key").
+				Build()
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, errors.New(err).
+				Component("cliptier").
+				Category(errors.CategoryConfiguration).
+				Context("operation", "sftp_parse_private_key").
+				Build()
+		}
+		config.Auth = []ssh.AuthMethod{ssh.PublicKeys(signer)}
+	case s.settings.Password != "":
+		config.Auth = []ssh.AuthMethod{ssh.Password(s.settings.Password)}
+	default:
+		return nil, errors.Newf("clip tier SFTP needs a password or a private key").
+			Component("cliptier").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "sftp_auth").
+			Build()
+	}
+	return config, nil
+}
+
+// withClient runs fn with a connected client. A failed operation drops the
+// connection so the next one reconnects, which covers servers that close idle
+// sessions between cleanup runs.
+func (s *SFTPStore) withClient(ctx context.Context, fn func(*sftp.Client) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == nil {
+		config, err := s.clientConfig()
+		if err != nil {
+			return err
+		}
+		addr := net.JoinHostPort(s.settings.Host, strconv.Itoa(s.settings.Port))
+		conn, err := (&net.Dialer{Timeout: sftpConnectTimeout}).DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return errors.New(err).
+				Component("cliptier").
+				Category(errors.CategoryNetwork).
+				Context("operation", "sftp_connect").
+				Context("host", s.settings.Host).
+				Build()
+		}
+		sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+		if err != nil {
+			_ = conn.Close()
+			return errors.New(err).
+				Component("cliptier").
+				Category(errors.CategoryNetwork).
+				Context("operation", "sftp_connect").
+				Context("host", s.settings.Host).
+				Build()
+		}
+		s.ssh = ssh.NewClient(sshConn, chans, reqs)
+		client, err := sftp.NewClient(s.ssh)
+		if err != nil {
+			_ = s.disconnectLocked()
+			return errors.New(err).
+				Component("cliptier").
+				Category(errors.CategoryNetwork).
+				Context("operation", "sftp_session").
+				Build()
+		}
+		s.client = client
+	}
+
+	if err := fn(s.client); err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			_ = s.disconnectLocked()
+		}
+		return err
+	}
+	return nil
+}
+
+// remotePath returns the remote path for a key.
+func (s *SFTPStore) remotePath(key string) string {
+	return path.Join(s.basePath, key)
+}
+
+// Put uploads the clip to a temporary name and renames it into place.
+func (s *SFTPStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	target := s.remotePath(key)
+	tmp := fmt.Sprintf("%s.tmp-%d", target, time.Now().UnixNano())
+	return s.withClient(ctx, func(client *sftp.Client) error {
+		if err := client.MkdirAll(path.Dir(target)); err != nil {
+			return fmt.Errorf("cliptier: sftp mkdir %s: %w", path.Dir(target), err)
+		}
+		f, err := client.Create(tmp)
+		if err != nil {
+			return fmt.Errorf("cliptier: sftp create %s: %w", tmp, err)
+		}
+		written, err := f.ReadFrom(contextReader{ctx: ctx, r: r})
+		if err == nil && written != size {
+			err = fmt.Errorf("wrote %d of %d bytes", written, size)
+		}
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = client.Remove(tmp)
+			return fmt.Errorf("cliptier: sftp write %s: %w", key, err)
+		}
+		// Plain SFTP rename fails when the target exists; use the POSIX
+		// rename extension where the server offers it.
+		if err := client.PosixRename(tmp, target); err != nil {
+			_ = client.Remove(target)
+			if err := client.Rename(tmp, target); err != nil {
+				_ = client.Remove(tmp)
+				return fmt.Errorf("cliptier: sftp rename %s: %w", key, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Get downloads the clip to w.
+func (s *SFTPStore) Get(ctx context.Context, key string, w io.Writer) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	return s.withClient(ctx, func(client *sftp.Client) error {
+		f, err := client.Open(s.remotePath(key))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("cliptier: sftp open %s: %w", key, err)
+		}
+		defer func() { _ = f.Close() }()
+		if _, err := f.WriteTo(w); err != nil {
+			return fmt.Errorf("cliptier: sftp read %s: %w", key, err)
+		}
+		return nil
+	})
+}
+
+// Delete removes the clip.
+func (s *SFTPStore) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	return s.withClient(ctx, func(client *sftp.Client) error {
+		if err := client.Remove(s.remotePath(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("cliptier: sftp delete %s: %w", key, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,82 @@
+// Package cliptier moves audio clips to a secondary store and fetches them
+// back. It backs the "tier" clip retention policy: instead of deleting older
+// clips, diskmanager.TierCleanup hands them to an Archiver, which uploads the
+// clip (and optionally its spectrograms) to a directory, an S3-compatible
+// bucket or an SFTP server, records the move in the datastore and removes
+// the local copy. The media API recalls an archived clip into the clip
+// directory when it is played.
+//
+// Store keys are clip paths relative to the clip export directory, with
+// forward slashes, the same form the detections record. A clip therefore
+// keeps its year/month layout in the secondary store.
+package cliptier
+
+import (
+	"context"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// ErrNotFound is returned by Store.Get for a key the store does not hold.
+var ErrNotFound = errors.NewStd("clip not found in tier store")
+
+// Store is a secondary clip store. Implementations are safe for concurrent
+// use.
+type Store interface {
+	// Name returns the target type: "local", "s3" or "sftp".
+	Name() string
+	// Location describes where the store keeps clips, without credentials.
+	Location() string
+	// Put writes size bytes from r under key, replacing an existing object.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get writes the object stored under key to w. Returns ErrNotFound if
+	// the store does not hold key.
+	Get(ctx context.Context, key string, w io.Writer) error
+	// Delete removes the object stored under key. Removing a missing key is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+	// Close releases connections held by the store.
+	Close() error
+}
+
+// GetLogger returns the cliptier package logger.
+func GetLogger() logger.Logger {
+	return logger.Global().Module("cliptier")
+}
+
+// NewStore creates the store for the configured tier target.
+func NewStore(settings *conf.ClipTierSettings) (Store, error) {
+	switch settings.Target {
+	case conf.ClipTierTargetLocal:
+		return NewLocalStore(settings.Local.Path)
+	case conf.ClipTierTargetS3:
+		return NewS3Store(&settings.S3)
+	case conf.ClipTierTargetSFTP:
+		return NewSFTPStore(&settings.SFTP)
+	default:
+		return nil, errors.Newf("unsupported clip tier target %q", settings.Target).
+			Component("cliptier").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "create_store").
+			Build()
+	}
+}
+
+// validateKey rejects keys that are not clean relative slash paths, so a
+// tampered clip path cannot address objects outside the store's root.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") ||
+		path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return errors.Newf("invalid clip tier key %q", key).
+			Component("cliptier").
+			Category(errors.CategoryValidation).
+			Context("operation", "validate_key").
+			Build()
+	}
+	return nil
+}
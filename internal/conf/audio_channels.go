@@ -0,0 +1,104 @@
+// audio_channels.go - channel selection for multichannel sound cards
+package conf
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MaxAudioSourceChannel is the highest device channel an audio source may
+// select. It covers the largest USB and PCIe recording interfaces.
+const MaxAudioSourceChannel = 32
+
+// deviceChannelsSeparator joins a device and its channel selection in a
+// source connection string, e.g. "hw:1,0#ch=3+4".
+const deviceChannelsSeparator = "#ch="
+
+// ConnectionString returns the connection string the audio engine registers
+// for this source. Without a channel selection it is the device itself; with
+// one the selected channels are appended, so every logical source split from
+// the same sound card has its own registry entry and source ID.
+func (a *AudioSourceConfig) ConnectionString() string {
+	return DeviceChannelsConnection(strings.TrimSpace(a.Device), a.Channels)
+}
+
+// DeviceChannelsConnection returns the connection string for the given
+// 1-based channels of device. It returns device unchanged when channels is
+// empty.
+func DeviceChannelsConnection(device string, channels []int) string {
+	if len(channels) == 0 {
+		return device
+	}
+	parts := make([]string, len(channels))
+	for i, ch := range channels {
+		parts[i] = strconv.Itoa(ch)
+	}
+	return device + deviceChannelsSeparator + strings.Join(parts, "+")
+}
+
+// ParseDeviceChannels splits a connection string built by
+// DeviceChannelsConnection into the device and its 1-based channels. ok is
+// false when the connection string carries no channel selection.
+func ParseDeviceChannels(conn string) (device string, channels []int, ok bool) {
+	idx := strings.LastIndex(conn, deviceChannelsSeparator)
+	if idx < 0 {
+		return conn, nil, false
+	}
+	for part := range strings.SplitSeq(conn[idx+len(deviceChannelsSeparator):], "+") {
+		ch, err := strconv.Atoi(part)
+		if err != nil || ch < 1 || ch > MaxAudioSourceChannel {
+			return conn, nil, false
+		}
+		channels = append(channels, ch)
+	}
+	return conn[:idx], channels, true
+}
+
+// validateChannels checks the channel selection of an audio source. Channels
+// are 1-based, at most MaxAudioSourceChannel and listed once each.
+func (a *AudioSourceConfig) validateChannels() error {
+	for i, ch := range a.Channels {
+		if ch < 1 || ch > MaxAudioSourceChannel {
+			return fmt.Errorf("audio source '%s': channel %d out of range [1, %d]", a.Name, ch, MaxAudioSourceChannel)
+		}
+		if slices.Contains(a.Channels[:i], ch) {
+			return fmt.Errorf("audio source '%s': channel %d is listed more than once", a.Name, ch)
+		}
+	}
+	return nil
+}
+
+// validateSharedDevices checks the sources that capture from the same sound
+// card. A device may only be listed more than once when every source on it
+// selects its channels, and all of them must use the same sample rate since
+// the card is opened once for all of them.
+func (a *AudioSettings) validateSharedDevices() error {
+	type deviceUse struct {
+		name       string
+		sampleRate int
+		split      bool
+	}
+	seen := make(map[string]deviceUse, len(a.Sources))
+	for i := range a.Sources {
+		src := &a.Sources[i]
+		use := deviceUse{name: src.Name, sampleRate: src.SampleRate, split: len(src.Channels) > 0}
+		if use.sampleRate == 0 {
+			use.sampleRate = SampleRate
+		}
+		first, ok := seen[src.Device]
+		if !ok {
+			seen[src.Device] = use
+			continue
+		}
+		if !first.split || !use.split {
+			return fmt.Errorf("audio source '%s' has a duplicate device: '%s' (set channels on every source sharing a device)", src.Name, src.Device)
+		}
+		if first.sampleRate != use.sampleRate {
+			return fmt.Errorf("audio sources '%s' and '%s' share device '%s' but use different sample rates (%d and %d Hz)",
+				first.name, src.Name, src.Device, first.sampleRate, use.sampleRate)
+		}
+	}
+	return nil
+}
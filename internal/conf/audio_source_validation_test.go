@@ -270,6 +270,112 @@ func TestAudioSettings_ValidateSources_DuplicateDevices(t *testing.T) {
 	assert.Contains(t, err.Error(), "duplicate device")
 }
 
+func TestAudioSourceConfig_Validate_Channels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		channels []int
+		wantErr  string
+	}{
+		{"all channels", nil, ""},
+		{"single channel", []int{3}, ""},
+		{"channel pair", []int{1, 2}, ""},
+		{"zero", []int{0}, "out of range"},
+		{"too high", []int{MaxAudioSourceChannel + 1}, "out of range"},
+		{"repeated", []int{2, 2}, "more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			src := &AudioSourceConfig{Name: "Mic", Device: "hw:1,0", Channels: tt.channels}
+			err := src.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAudioSettings_ValidateSources_SharedDevice(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sources []AudioSourceConfig
+		wantErr string
+	}{
+		{
+			name: "one source per channel",
+			sources: []AudioSourceConfig{
+				{Name: "Pond", Device: "hw:1,0", Channels: []int{1}},
+				{Name: "Forest", Device: "hw:1,0", Channels: []int{2}},
+				{Name: "Meadow", Device: "hw:1,0", Channels: []int{3, 4}},
+			},
+		},
+		{
+			name: "same channels twice",
+			sources: []AudioSourceConfig{
+				{Name: "Pond", Device: "hw:1,0", Channels: []int{1}},
+				{Name: "Forest", Device: "hw:1,0", Channels: []int{1}},
+			},
+			wantErr: "duplicate device",
+		},
+		{
+			name: "whole device next to a channel",
+			sources: []AudioSourceConfig{
+				{Name: "Pond", Device: "hw:1,0"},
+				{Name: "Forest", Device: "hw:1,0", Channels: []int{2}},
+			},
+			wantErr: "set channels on every source",
+		},
+		{
+			name: "different sample rates",
+			sources: []AudioSourceConfig{
+				{Name: "Pond", Device: "hw:1,0", Channels: []int{1}},
+				{Name: "Bats", Device: "hw:1,0", Channels: []int{2}, SampleRate: 96000},
+			},
+			wantErr: "different sample rates",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			audio := &AudioSettings{Sources: tt.sources}
+			err := audio.ValidateSources()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestDeviceChannelsConnection_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	src := AudioSourceConfig{Device: " hw:1,0 ", Channels: []int{3, 4}}
+	conn := src.ConnectionString()
+	assert.Equal(t, "hw:1,0#ch=3+4", conn)
+
+	device, channels, ok := ParseDeviceChannels(conn)
+	require.True(t, ok)
+	assert.Equal(t, "hw:1,0", device)
+	assert.Equal(t, []int{3, 4}, channels)
+
+	src.Channels = nil
+	assert.Equal(t, "hw:1,0", src.ConnectionString())
+	for _, conn := range []string{"hw:1,0", "hw:1,0#ch=", "hw:1,0#ch=0", "hw:1,0#ch=a+2"} {
+		_, _, ok := ParseDeviceChannels(conn)
+		assert.False(t, ok, conn)
+	}
+}
+
 func TestAudioSettings_ValidateSources_EmptyArray(t *testing.T) {
 	t.Parallel()
 
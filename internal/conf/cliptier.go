@@ -0,0 +1,113 @@
+package conf
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Clip tier targets: where the tier retention policy moves clips to.
+const (
+	ClipTierTargetLocal = "local" // a directory, typically a mounted NAS share
+	ClipTierTargetS3    = "s3"    // an S3-compatible bucket
+	ClipTierTargetSFTP  = "sftp"  // a directory on an SFTP server
+)
+
+// ClipTierSettings configures the secondary store the tier retention policy
+// moves older clips and spectrograms to. Archived clips stay listed with their
+// detections and are fetched back when played.
+type ClipTierSettings struct {
+	Target string                `yaml:"target" json:"target"` // secondary store: "local", "s3" or "sftp"
+	Local  ClipTierLocalSettings `yaml:"local" json:"local"`   // settings for the local target
+	S3     ClipTierS3Settings    `yaml:"s3" json:"s3"`         // settings for the s3 target
+	SFTP   ClipTierSFTPSettings  `yaml:"sftp" json:"sftp"`     // settings for the sftp target
+}
+
+// ClipTierLocalSettings configures a directory target such as a NAS mount.
+type ClipTierLocalSettings struct {
+	Path string `yaml:"path" json:"path"` // archive directory, must not be inside the clip export directory
+}
+
+// ClipTierS3Settings configures an S3-compatible bucket target.
+type ClipTierS3Settings struct {
+	Endpoint        string `yaml:"endpoint" json:"endpoint"`               // S3 endpoint host, e.g. s3.amazonaws.com or minio.lan:9000
+	Region          string `yaml:"region" json:"region"`                   // bucket region
+	Bucket          string `yaml:"bucket" json:"bucket"`                   // bucket name
+	Prefix          string `yaml:"prefix" json:"prefix"`                   // object key prefix
+	AccessKeyID     string `yaml:"accesskeyid" json:"accessKeyId"`         // access key ID
+	SecretAccessKey string `yaml:"secretaccesskey" json:"secretAccessKey"` // secret access key
+	UseSSL          bool   `yaml:"usessl" json:"useSSL"`                   // use TLS
+	PathStyle       bool   `yaml:"pathstyle" json:"pathStyle"`             // path-style bucket addressing, needed by most MinIO/Garage setups
+}
+
+// ClipTierSFTPSettings configures a directory on an SFTP server.
+type ClipTierSFTPSettings struct {
+	Host           string `yaml:"host" json:"host"`                     // SFTP server hostname or IP address
+	Port           int    `yaml:"port" json:"port"`                     // SFTP server port (default: 22)
+	Username       string `yaml:"username" json:"username"`             // SFTP username
+	Password       string `yaml:"password" json:"password"`             // SFTP password (optional if using key)
+	PrivateKeyPath string `yaml:"privatekeypath" json:"privateKeyPath"` // path to private key file (optional)
+	KnownHostsFile string `yaml:"knownhostsfile" json:"knownHostsFile"` // known_hosts file (default: ~/.ssh/known_hosts)
+	Path           string `yaml:"path" json:"path"`                     // remote archive directory
+}
+
+// validateClipTierSettings checks the tier target settings. It runs only when
+// the tier retention policy is selected so unused targets are not rejected.
+func validateClipTierSettings(s *ClipTierSettings) error {
+	switch s.Target {
+	case ClipTierTargetLocal:
+		if strings.TrimSpace(s.Local.Path) == "" {
+			return fmt.Errorf("clip tier local path must not be empty")
+		}
+	case ClipTierTargetS3:
+		if s.S3.Bucket == "" {
+			return fmt.Errorf("clip tier S3 bucket must not be empty")
+		}
+	case ClipTierTargetSFTP:
+		if s.SFTP.Host == "" {
+			return fmt.Errorf("clip tier SFTP host must not be empty")
+		}
+		if s.SFTP.Username == "" {
+			return fmt.Errorf("clip tier SFTP username must not be empty")
+		}
+		if s.SFTP.Password == "" && s.SFTP.PrivateKeyPath == "" {
+			return fmt.Errorf("clip tier SFTP needs a password or a private key")
+		}
+		if s.SFTP.Port < 0 || s.SFTP.Port > 65535 {
+			return fmt.Errorf("clip tier SFTP port must be between 0 and 65535, got %d", s.SFTP.Port)
+		}
+	default:
+		return fmt.Errorf("clip tier target must be %q, %q or %q, got %q",
+			ClipTierTargetLocal, ClipTierTargetS3, ClipTierTargetSFTP, s.Target)
+	}
+	return nil
+}
+
+// validateClipTierLocalPath rejects a local tier directory inside the clip
+// export directory, where archived clips would be picked up again by the
+// retention scan and the clip reconciler.
+func validateClipTierLocalPath(retention *RetentionSettings, exportPath string) error {
+	if retention.Policy != RetentionPolicyTier || retention.Tier.Target != ClipTierTargetLocal {
+		return nil
+	}
+	tierPath := strings.TrimSpace(retention.Tier.Local.Path)
+	if tierPath == "" || strings.TrimSpace(exportPath) == "" {
+		return nil
+	}
+	tierAbs, err := filepath.Abs(tierPath)
+	if err != nil {
+		return nil
+	}
+	exportAbs, err := filepath.Abs(exportPath)
+	if err != nil {
+		return nil
+	}
+	rel, err := filepath.Rel(exportAbs, tierAbs)
+	if err != nil {
+		return nil
+	}
+	if rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))) {
+		return fmt.Errorf("clip tier local path %q must not be inside the clip export directory %q", tierPath, exportPath)
+	}
+	return nil
+}
@@ -51,6 +51,9 @@ func CloneSettings(src *Settings) *Settings {
 	dst.BirdNET.Labels = slices.Clone(src.BirdNET.Labels)
 	dst.BirdNET.RangeFilter.Species = slices.Clone(src.BirdNET.RangeFilter.Species)
 	dst.BirdNET.RangeFilter.IncludedScientificNames = maps.Clone(src.BirdNET.RangeFilter.IncludedScientificNames)
+	// The per-location sets are never mutated after publishing, so they are
+	// shared; only the outer map is copied.
+	dst.BirdNET.RangeFilter.LocationScientificNames = maps.Clone(src.BirdNET.RangeFilter.LocationScientificNames)
 
 	// Models.
 	dst.Models.Enabled = slices.Clone(src.Models.Enabled)
@@ -101,6 +104,7 @@ func CloneSettings(src *Settings) *Settings {
 	// Backup.
 	dst.Backup.Targets = cloneBackupTargets(src.Backup.Targets)
 	dst.Backup.Schedules = slices.Clone(src.Backup.Schedules)
+	dst.Backup.Retention.Sources = maps.Clone(src.Backup.Retention.Sources)
 
 	// Notification.
 	dst.Notification.Push.Providers = clonePushProviders(src.Notification.Push.Providers)
@@ -109,8 +113,9 @@ func CloneSettings(src *Settings) *Settings {
 }
 
 // cloneAudioSources deep-copies a slice of AudioSourceConfig so that the
-// returned slice, its Models string slices, and any per-source Equalizer
-// (with its Filters slice) share no backing storage with the input.
+// returned slice, its Models and Channels slices, and any per-source Equalizer
+// (with its Filters slice) or species overrides share no backing storage with
+// the input.
 func cloneAudioSources(in []AudioSourceConfig) []AudioSourceConfig {
 	if in == nil {
 		return nil
@@ -119,18 +124,20 @@ func cloneAudioSources(in []AudioSourceConfig) []AudioSourceConfig {
 	for i := range in {
 		s := in[i]
 		s.Models = slices.Clone(s.Models)
+		s.Channels = slices.Clone(s.Channels)
 		if s.Equalizer != nil {
 			eq := *s.Equalizer
 			eq.Filters = slices.Clone(eq.Filters)
 			s.Equalizer = &eq
 		}
+		s.Species = cloneSpeciesSettings(s.Species)
 		out[i] = s
 	}
 	return out
 }
 
 // cloneStreamConfigs deep-copies a slice of StreamConfig, ensuring each
-// StreamConfig's Models slice is independent.
+// StreamConfig's Models slice, Equalizer and species overrides are independent.
 func cloneStreamConfigs(in []StreamConfig) []StreamConfig {
 	if in == nil {
 		return nil
@@ -144,6 +151,7 @@ func cloneStreamConfigs(in []StreamConfig) []StreamConfig {
 			eq.Filters = slices.Clone(eq.Filters)
 			s.Equalizer = &eq
 		}
+		s.Species = cloneSpeciesSettings(s.Species)
 		out[i] = s
 	}
 	return out
@@ -193,6 +201,18 @@ func cloneDashboardElements(in []DashboardElement) []DashboardElement {
 	return out
 }
 
+// cloneSpeciesSettings deep-copies optional per-source species overrides.
+func cloneSpeciesSettings(in *SpeciesSettings) *SpeciesSettings {
+	if in == nil {
+		return nil
+	}
+	return &SpeciesSettings{
+		Include: slices.Clone(in.Include),
+		Exclude: slices.Clone(in.Exclude),
+		Config:  cloneSpeciesConfigMap(in.Config),
+	}
+}
+
 // cloneSpeciesConfigMap clones the per-species config map, deep-copying each
 // SpeciesConfig so that nested Actions slices (and their Parameters slices)
 // are independent.
@@ -73,8 +73,9 @@ func newPopulatedSettings() *Settings {
 
 	s.Realtime.Audio.Sources = []AudioSourceConfig{
 		{
-			Name:   "front",
-			Models: []string{"birdnet"},
+			Name:     "front",
+			Models:   []string{"birdnet"},
+			Channels: []int{1, 2},
 			Equalizer: &EqualizerSettings{
 				Enabled: true,
 				Filters: []EqualizerFilter{{Type: "LowPass", Frequency: 100}},
@@ -155,6 +156,7 @@ func newPopulatedSettings() *Settings {
 		},
 	}
 	s.Backup.Schedules = []BackupScheduleConfig{{Enabled: true, Hour: 3}}
+	s.Backup.Retention.Sources = map[string]BackupRetentionPolicy{"clips": {MaxAge: "1y"}}
 
 	s.Notification.Push.Providers = []PushProviderConfig{
 		{
@@ -212,6 +214,7 @@ func mutateCloneEverywhere(dst *Settings) {
 
 	dst.Realtime.Audio.Sources[0].Name = mutated
 	dst.Realtime.Audio.Sources[0].Models[0] = mutated
+	dst.Realtime.Audio.Sources[0].Channels[0] = 3
 	dst.Realtime.Audio.Sources[0].Equalizer.Enabled = false
 	dst.Realtime.Audio.Sources[0].Equalizer.Filters[0].Type = mutated
 	dst.Realtime.Audio.SoxAudioTypes[0] = mutated
@@ -268,6 +271,7 @@ func mutateCloneEverywhere(dst *Settings) {
 		}
 	}
 	dst.Backup.Schedules[0].Hour = 99
+	dst.Backup.Retention.Sources["clips"] = BackupRetentionPolicy{MaxAge: mutated}
 
 	pp := dst.Notification.Push.Providers[0]
 	pp.URLs[0] = mutated
@@ -335,6 +339,7 @@ func assertSourceUnchanged(t *testing.T, src *Settings) {
 	require.Len(t, src.Realtime.Audio.Sources, 1)
 	assert.Equal(t, "front", src.Realtime.Audio.Sources[0].Name)
 	assert.Equal(t, []string{"birdnet"}, src.Realtime.Audio.Sources[0].Models)
+	assert.Equal(t, []int{1, 2}, src.Realtime.Audio.Sources[0].Channels)
 	require.NotNil(t, src.Realtime.Audio.Sources[0].Equalizer)
 	assert.True(t, src.Realtime.Audio.Sources[0].Equalizer.Enabled)
 	require.Len(t, src.Realtime.Audio.Sources[0].Equalizer.Filters, 1)
@@ -404,6 +409,7 @@ func assertSourceUnchanged(t *testing.T, src *Settings) {
 		"deeply nested []any inside nested map must be independently cloned")
 	require.Len(t, src.Backup.Schedules, 1)
 	assert.Equal(t, 3, src.Backup.Schedules[0].Hour)
+	assert.Equal(t, "1y", src.Backup.Retention.Sources["clips"].MaxAge)
 
 	require.Len(t, src.Notification.Push.Providers, 1)
 	pp := src.Notification.Push.Providers[0]
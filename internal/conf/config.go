@@ -52,6 +52,16 @@ type ExportSettings struct {
 	PreCapture    int                   `yaml:"precapture" json:"preCapture" mapstructure:"preCapture"`          // pre-capture in seconds
 	Gain          float64               `yaml:"gain" json:"gain" mapstructure:"gain"`                            // gain in dB for audio capture
 	Normalization NormalizationSettings `yaml:"normalization" json:"normalization" mapstructure:"normalization"` // audio normalization settings (EBU R128)
+	Embeddings    EmbeddingSettings     `yaml:"embeddings" json:"embeddings" mapstructure:"embeddings"`          // per-clip embedding vectors for similar recording search
+}
+
+// EmbeddingSettings controls computing a BirdNET v2.4 embedding vector for
+// every saved clip. The vectors are stored in the enhanced (v2) database and
+// back the similar recordings search; clips saved before the option was
+// enabled have no vector and are not searchable.
+type EmbeddingSettings struct {
+	Enabled   bool   `yaml:"enabled" json:"enabled" mapstructure:"enabled"`       // true to compute and store an embedding for each saved clip
+	ModelPath string `yaml:"modelpath" json:"modelPath" mapstructure:"modelpath"` // BirdNET v2.4 embeddings ONNX model; empty uses the bat embedding model
 }
 
 // NormalizationSettings contains audio normalization configuration based on EBU R128 standard
@@ -64,12 +74,14 @@ type NormalizationSettings struct {
 
 type RetentionSettings struct {
 	Debug            bool   `yaml:"debug" json:"debug"`                       // true to enable retention debug
-	Policy           string `yaml:"policy" json:"policy"`                     // retention policy, "none", "age" or "usage"
+	Policy           string `yaml:"policy" json:"policy"`                     // retention policy, "none", "age", "usage" or "tier"
 	MaxAge           string `yaml:"maxage" json:"maxAge"`                     // maximum age of audio clips to keep
 	MaxUsage         string `yaml:"maxusage" json:"maxUsage"`                 // maximum disk usage percentage before cleanup
 	MinClips         int    `yaml:"minclips" json:"minClips"`                 // minimum number of clips per species to keep
 	KeepSpectrograms bool   `yaml:"keepspectrograms" json:"keepSpectrograms"` // true to keep spectrograms
 	CheckInterval    int    `yaml:"checkinterval" json:"checkInterval"`       // cleanup check interval in minutes (default: 15)
+
+	Tier ClipTierSettings `yaml:"tier" json:"tier"` // secondary store for the tier policy
 }
 
 // AudioSettings contains settings for audio processing and export.
@@ -86,11 +98,15 @@ type AudioSourceConfig struct {
 	Name       string             `yaml:"name" json:"name" mapstructure:"name"`                                       // Required: descriptive name like "Front Yard Mic"
 	Device     string             `yaml:"device" json:"device" mapstructure:"device"`                                 // Required: ALSA device ID (e.g., "sysdefault", "hw:0,0", "Loopback")
 	SampleRate int                `yaml:"samplerate,omitempty" json:"sampleRate,omitempty" mapstructure:"samplerate"` // Capture rate in Hz (0 = default 48000; set 256000 for bat detectors)
+	Channels   []int              `yaml:"channels,omitempty" json:"channels,omitempty" mapstructure:"channels"`       // 1-based device channels mixed into this source (empty = all, downmixed)
 	Gain       float64            `yaml:"gain" json:"gain" mapstructure:"gain"`                                       // Input gain in dB (0 = no adjustment)
 	Model      string             `yaml:"model,omitempty" json:"model,omitempty" mapstructure:"model"`                // AI model: "" or "birdnet" (default), "perch_v2", "bat" (future)
 	Models     []string           `yaml:"models,omitempty" json:"models,omitempty" mapstructure:"models"`             // Model IDs for this source (e.g., ["birdnet", "perch_v2"])
 	Equalizer  *EqualizerSettings `yaml:"equalizer,omitempty" json:"equalizer,omitempty" mapstructure:"equalizer"`    // Per-source EQ (nil = use global)
 	QuietHours QuietHoursConfig   `yaml:"quietHours" json:"quietHours" mapstructure:"quietHours"`                     // Per-source quiet hours
+	Deployment DeploymentConfig   `yaml:"deployment,omitempty" json:"deployment" mapstructure:"deployment"`           // Where the microphone is installed (empty = station location)
+	Soundscape bool               `yaml:"soundscape,omitempty" json:"soundscape" mapstructure:"soundscape"`           // Record a continuous soundscape (see audio.soundscape)
+	Species    *SpeciesSettings   `yaml:"species,omitempty" json:"species,omitempty" mapstructure:"species"`          // Per-source species lists, thresholds and actions (nil = use global)
 }
 
 type AudioSettings struct {
@@ -106,6 +122,7 @@ type AudioSettings struct {
 	StreamTransport string              `yaml:"streamtransport" json:"streamTransport"`                         // preferred transport for audio streaming: "auto", "sse", or "ws"
 	Export          ExportSettings      `yaml:"export" json:"export"`                                           // export settings
 	SoundLevel      SoundLevelSettings  `yaml:"soundlevel" json:"soundLevel"`                                   // sound level monitoring settings
+	Soundscape      SoundscapeSettings  `yaml:"soundscape" json:"soundscape" mapstructure:"soundscape"`         // continuous soundscape recording settings
 
 	Equalizer  EqualizerSettings `yaml:"equalizer" json:"equalizer"`                             // equalizer settings (global default)
 	QuietHours QuietHoursConfig  `yaml:"quietHours" json:"quietHours" mapstructure:"quietHours"` // quiet hours (global default, legacy)
@@ -257,6 +274,20 @@ const (
 	SpectrogramDynamicRangeExtended     = "120" // Extended - more detail, Sox default
 )
 
+// Spectrogram renderer constants.
+const (
+	SpectrogramRendererNative = "native" // In-process STFT renderer (default)
+	SpectrogramRendererSox    = "sox"    // External Sox, with FFmpeg fallback
+)
+
+// Spectrogram frequency axis constants. Only the native renderer supports
+// scales other than linear.
+const (
+	SpectrogramScaleLinear = "linear"
+	SpectrogramScaleLog    = "log"
+	SpectrogramScaleMel    = "mel"
+)
+
 // SpectrogramPreRender contains settings for spectrogram generation modes.
 // Three modes control when and how spectrograms are generated:
 //   - "auto": Generate on-demand when API is called (default, suitable for most systems)
@@ -269,6 +300,15 @@ type SpectrogramPreRender struct {
 	Raw          bool   `yaml:"raw" json:"raw"                       mapstructure:"raw"`          // Generate raw spectrogram without axes/legend (default: true)
 	Style        string `yaml:"style" json:"style"                   mapstructure:"style"`        // Visual style preset: "default", "scientific_dark", "high_contrast_dark", "scientific"
 	DynamicRange string `yaml:"dynamicrange" json:"dynamicRange"     mapstructure:"dynamicRange"` // Dynamic range in dB: "80" (high contrast), "100" (standard), "120" (extended)
+	Renderer     string `yaml:"renderer" json:"renderer"             mapstructure:"renderer"`     // Renderer: "native" (in-process, default) or "sox" (external Sox/FFmpeg)
+	Scale        string `yaml:"scale" json:"scale"                   mapstructure:"scale"`        // Frequency axis of the native renderer: "linear" (default), "log" or "mel"
+}
+
+// UsesNativeRenderer returns true if spectrograms are rendered in-process
+// rather than by the external Sox/FFmpeg tools. An unset renderer keeps the
+// external tools, so settings built in code behave as before.
+func (s *SpectrogramPreRender) UsesNativeRenderer() bool {
+	return s.Renderer == SpectrogramRendererNative
 }
 
 // GetMode returns the effective spectrogram generation mode, handling backward compatibility.
@@ -352,11 +392,12 @@ type EBirdSettings struct {
 
 // WeatherSettings contains all weather-related settings
 type WeatherSettings struct {
-	Provider     string               `yaml:"provider" json:"provider"`         // "none", "yrno", "openweather", or "wunderground"
+	Provider     string               `yaml:"provider" json:"provider"`         // "none", "yrno", "openweather", "wunderground", or "station"
 	PollInterval int                  `yaml:"pollinterval" json:"pollInterval"` // weather data polling interval in minutes
 	Debug        bool                 `yaml:"debug" json:"debug"`               // true to enable debug mode
 	OpenWeather  OpenWeatherSettings  `yaml:"openweather" json:"openWeather"`   // OpenWeather integration settings
 	Wunderground WundergroundSettings `yaml:"wunderground" json:"wunderground"` // WeatherUnderground integration settings
+	Station      StationSettings      `yaml:"station" json:"station"`           // local weather station settings
 }
 
 // ---------------- Notification push configuration -----------------
@@ -485,6 +526,23 @@ type WundergroundSettings struct {
 	Units     string `yaml:"units" json:"units"`         // units of measurement: "e" (imperial), "m" (metric), "h" (UK hybrid)
 }
 
+// StationSettings contains settings for a local weather station that pushes
+// its readings to BirdNET-Go instead of being polled.
+type StationSettings struct {
+	Interval int                 `yaml:"interval" json:"interval"` // minutes of readings averaged into one stored record (default: 5)
+	PassKey  string              `yaml:"passkey" json:"passKey"`   // required; uploads must carry this PASSKEY value
+	MQTT     StationMQTTSettings `yaml:"mqtt" json:"mqtt"`         // WeeWX loop packets over MQTT
+}
+
+// StationMQTTSettings contains settings for receiving WeeWX loop packets over MQTT.
+type StationMQTTSettings struct {
+	Enabled  bool   `yaml:"enabled" json:"enabled"`   // true to subscribe to WeeWX loop packets
+	Broker   string `yaml:"broker" json:"broker"`     // MQTT broker URL, empty to use the MQTT integration broker and credentials
+	Topic    string `yaml:"topic" json:"topic"`       // topic of the aggregated WeeWX loop packets
+	Username string `yaml:"username" json:"username"` // MQTT username
+	Password string `yaml:"password" json:"password"` // MQTT password
+}
+
 // OpenWeatherSettings contains settings for OpenWeather integration.
 type OpenWeatherSettings struct {
 	Enabled  bool   `yaml:"enabled" json:"enabled"`   // true to enable OpenWeather integration, for legacy support
@@ -499,6 +557,14 @@ type PrivacyFilterSettings struct {
 	Debug      bool    `yaml:"debug" json:"debug"`           // true to enable debug mode
 	Enabled    bool    `yaml:"enabled" json:"enabled"`       // true to enable privacy filter
 	Confidence float32 `yaml:"confidence" json:"confidence"` // confidence threshold for human detection
+	Mode       string  `yaml:"mode" json:"mode"`             // "discard" drops detections near human voice, "redact" keeps them and blanks the voice in clips, uploads and the live stream
+	RedactFill string  `yaml:"redactfill" json:"redactFill"` // what replaces redacted voice: "silence" or "noise"
+}
+
+// Redacting reports whether the privacy filter keeps detections near human
+// voice and blanks the voice in their clips instead of dropping them.
+func (s *PrivacyFilterSettings) Redacting() bool {
+	return s.Enabled && s.Mode == PrivacyModeRedact
 }
 
 // DogBarkFilterSettings contains settings for the dog bark filter.
@@ -640,6 +706,9 @@ type StreamConfig struct {
 	Equalizer   *EqualizerSettings `yaml:"equalizer,omitempty" json:"equalizer,omitempty" mapstructure:"equalizer"` // Per-stream EQ (nil = use global)
 	QuietHours  QuietHoursConfig   `yaml:"quietHours" json:"quietHours" mapstructure:"quietHours"`                  // Quiet hours configuration
 	Models      []string           `yaml:"models,omitempty" json:"models,omitempty" mapstructure:"models"`          // Model IDs for this stream (e.g., ["birdnet", "perch_v2"])
+	Deployment  DeploymentConfig   `yaml:"deployment,omitempty" json:"deployment" mapstructure:"deployment"`        // Where the microphone is installed (empty = station location)
+	Soundscape  bool               `yaml:"soundscape,omitempty" json:"soundscape" mapstructure:"soundscape"`        // Record a continuous soundscape (see audio.soundscape)
+	Species     *SpeciesSettings   `yaml:"species,omitempty" json:"species,omitempty" mapstructure:"species"`       // Per-stream species lists, thresholds and actions (nil = use global)
 }
 
 // IsEnabled returns the effective enabled state for a stream.
@@ -716,6 +785,7 @@ type MQTTSettings struct {
 	RetrySettings RetrySettings         `yaml:"retrysettings" json:"retrySettings"`                              // settings for retry mechanism
 	TLS           MQTTTLSSettings       `yaml:"tls" json:"tls"`                                                  // TLS/SSL configuration
 	HomeAssistant HomeAssistantSettings `yaml:"homeassistant" mapstructure:"homeassistant" json:"homeAssistant"` // Home Assistant auto-discovery settings
+	Commands      bool                  `yaml:"commands" json:"commands"`                                        // true to accept control commands on <topic>/cmd/#
 }
 
 // MQTTTLSSettings contains TLS/SSL configuration for secure MQTT connections
@@ -1286,15 +1356,16 @@ const (
 
 // RangeFilterSettings contains settings for the range filter
 type RangeFilterSettings struct {
-	Debug                   bool                `yaml:"debug" json:"debug"`                               // true to enable debug mode
-	Model                   string              `yaml:"model" json:"model"`                               // range filter model version: "legacy" for v1, "v3" for geomodel v3.0, or empty/default for v2
-	ModelPath               string              `yaml:"modelpath" json:"modelPath"`                       // path to external meta model file (empty for embedded)
-	LabelsPath              string              `yaml:"labelspath,omitempty" json:"labelsPath,omitempty"` // path to geomodel labels file (required when geomodel differs from classifier labels)
-	Threshold               float32             `yaml:"threshold" json:"threshold"`                       // rangefilter species occurrence threshold
-	PassUnmappedSpecies     bool                `yaml:"passunmappedspecies" json:"passUnmappedSpecies"`   // true to pass through species absent from geomodel (score 1.0); false to filter them out (score 0.0)
-	Species                 []string            `yaml:"-" json:"species,omitempty"`                       // list of included species, runtime value
-	IncludedScientificNames map[string]struct{} `yaml:"-" json:"-"`                                       // O(1) lookup set of included scientific names (lowercase), runtime value
-	LastUpdated             time.Time           `yaml:"-" json:"lastUpdated"`                             // last time the species list was updated, runtime value
+	Debug                   bool                           `yaml:"debug" json:"debug"`                               // true to enable debug mode
+	Model                   string                         `yaml:"model" json:"model"`                               // range filter model version: "legacy" for v1, "v3" for geomodel v3.0, or empty/default for v2
+	ModelPath               string                         `yaml:"modelpath" json:"modelPath"`                       // path to external meta model file (empty for embedded)
+	LabelsPath              string                         `yaml:"labelspath,omitempty" json:"labelsPath,omitempty"` // path to geomodel labels file (required when geomodel differs from classifier labels)
+	Threshold               float32                        `yaml:"threshold" json:"threshold"`                       // rangefilter species occurrence threshold
+	PassUnmappedSpecies     bool                           `yaml:"passunmappedspecies" json:"passUnmappedSpecies"`   // true to pass through species absent from geomodel (score 1.0); false to filter them out (score 0.0)
+	Species                 []string                       `yaml:"-" json:"species,omitempty"`                       // list of included species, runtime value
+	IncludedScientificNames map[string]struct{}            `yaml:"-" json:"-"`                                       // O(1) lookup set of included scientific names (lowercase), runtime value
+	LocationScientificNames map[string]map[string]struct{} `yaml:"-" json:"-"`                                       // included scientific names per deployment location (LocationKey), runtime value
+	LastUpdated             time.Time                      `yaml:"-" json:"lastUpdated"`                             // last time the species list was updated, runtime value
 }
 
 // PerchConfig holds configuration for the Google Perch v2 model.
@@ -1540,6 +1611,24 @@ type BackupRetention struct {
 	MaxAge     string `yaml:"maxage" json:"maxAge"`         // Duration string for the maximum age of backups to keep (e.g., "30d" for 30 days, "6m" for 6 months, "1y" for 1 year). Backups older than this may be deleted.
 	MaxBackups int    `yaml:"maxbackups" json:"maxBackups"` // Maximum total number of backups to keep for a given source. If 0, no limit by count (only by age or MinBackups).
 	MinBackups int    `yaml:"minbackups" json:"minBackups"` // Minimum number of recent backups to keep for a given source, regardless of their age. This ensures a baseline number of backups are always available.
+
+	Sources map[string]BackupRetentionPolicy `yaml:"sources,omitempty" json:"sources,omitempty"` // Per-source overrides keyed by source type ("sqlite", "mysql", "config" or "clips"). A source type listed here uses its own policy instead of the one above.
+}
+
+// BackupRetentionPolicy is the retention policy for backups of one source type
+type BackupRetentionPolicy struct {
+	MaxAge     string `yaml:"maxage" json:"maxAge"`         // Duration string for the maximum age of backups to keep (e.g., "30d", "6m", "1y").
+	MaxBackups int    `yaml:"maxbackups" json:"maxBackups"` // Maximum number of backups to keep. If 0, no limit by count.
+	MinBackups int    `yaml:"minbackups" json:"minBackups"` // Minimum number of recent backups to keep regardless of their age.
+}
+
+// ForSource returns the retention policy for backups of the given source
+// type, which is the override in Sources when one is set.
+func (r *BackupRetention) ForSource(sourceType string) BackupRetentionPolicy {
+	if policy, ok := r.Sources[strings.ToLower(sourceType)]; ok {
+		return policy
+	}
+	return BackupRetentionPolicy{MaxAge: r.MaxAge, MaxBackups: r.MaxBackups, MinBackups: r.MinBackups}
 }
 
 // BackupTargetSettings is an interface for type-safe backup target configuration
@@ -1614,6 +1703,9 @@ type S3BackupSettings struct {
 	SecretAccessKey string `yaml:"secretaccesskey"` // AWS secret access key
 	Prefix          string `yaml:"prefix"`          // Object key prefix
 	UseSSL          bool   `yaml:"usessl"`          // Use SSL/TLS (default: true)
+	PathStyle       bool   `yaml:"pathstyle"`       // Path-style bucket addressing, needed by most MinIO/Garage setups
+	UseTags         bool   `yaml:"usetags"`         // Tag objects with backup metadata for lifecycle rules
+	PartSizeMB      int    `yaml:"partsizemb"`      // Multipart upload part size in MB (default: 64, minimum: 5)
 }
 
 // Validate validates S3 backup settings
@@ -1624,6 +1716,9 @@ func (s *S3BackupSettings) Validate() error {
 	if s.Region == "" {
 		return fmt.Errorf("S3 region cannot be empty")
 	}
+	if s.PartSizeMB != 0 && s.PartSizeMB < 5 {
+		return fmt.Errorf("S3 multipart part size must be at least 5 MB")
+	}
 	return nil
 }
 
@@ -1686,6 +1781,13 @@ type ImportConfig struct {
 	AllowInAppElevation bool `yaml:"allowinappelevation" json:"allowInAppElevation" mapstructure:"allowinappelevation"`
 }
 
+// BackupSources selects the data backed up besides the SQLite or MySQL
+// database, which is always backed up when backups are enabled
+type BackupSources struct {
+	Config bool `yaml:"config" json:"config"` // If true, the configuration directory (config.yaml, model catalog and TLS certificates) is backed up. The backup encryption key is never included.
+	Clips  bool `yaml:"clips" json:"clips"`   // If true, audio clips are backed up incrementally: each backup holds only the clips created since the last successful clip backup.
+}
+
 // BackupConfig contains backup-related configuration
 type BackupConfig struct {
 	Enabled        bool                   `yaml:"enabled" json:"enabled"`                // Global flag to enable or disable the entire backup system. If false, no backups (manual or scheduled) will occur.
@@ -1694,6 +1796,7 @@ type BackupConfig struct {
 	EncryptionKey  string                 `yaml:"encryption_key" json:"encryptionKey"`   // Base64-encoded encryption key used for AES-256-GCM encryption of backup archives. Must be kept secret and safe.
 	SanitizeConfig bool                   `yaml:"sanitize_config" json:"sanitizeConfig"` // If true, sensitive information (like passwords, API keys) will be removed from the configuration file copy that is included in the backup archive.
 	Retention      BackupRetention        `yaml:"retention" json:"retention"`            // Defines policies for how long and how many backups are kept.
+	Sources        BackupSources          `yaml:"sources" json:"sources"`                // Selects what is backed up besides the database.
 	Targets        []BackupTarget         `yaml:"targets" json:"targets"`                // A list of configured backup targets (destinations) where backup archives will be stored.
 	Schedules      []BackupScheduleConfig `yaml:"schedules" json:"schedules"`            // A list of schedules (e.g., daily, weekly) that define when automatic backups should run.
 
@@ -1761,6 +1864,16 @@ type Settings struct {
 			Host     string `yaml:"host" json:"host"`         // host for mysql database
 			Port     string `yaml:"port" json:"port"`         // port for mysql database
 		} `yaml:"mysql" json:"mysql"`
+
+		Postgres struct {
+			Enabled  bool   `yaml:"enabled" json:"enabled"`   // true to enable postgresql output
+			Username string `yaml:"username" json:"username"` // username for postgresql database
+			Password string `yaml:"password" json:"password"` // password for postgresql database
+			Database string `yaml:"database" json:"database"` // database name for postgresql database
+			Host     string `yaml:"host" json:"host"`         // host for postgresql database
+			Port     string `yaml:"port" json:"port"`         // port for postgresql database
+			SSLMode  string `yaml:"sslmode" json:"sslMode"`   // libpq sslmode: disable, allow, prefer, require, verify-ca or verify-full
+		} `yaml:"postgres" json:"postgres"`
 	} `yaml:"output" json:"output"`
 
 	Backup BackupConfig `yaml:"backup" json:"backup"` // Backup configuration
@@ -1791,7 +1904,17 @@ func (s *Settings) ResolveEQOverride(displayName string) *EqualizerSettings {
 
 // AlertSettings configures the alerting rules engine.
 type AlertSettings struct {
-	HistoryRetentionDays int `json:"historyRetentionDays" yaml:"history_retention_days" mapstructure:"history_retention_days"` // Days to retain alert history (0 = unlimited)
+	HistoryRetentionDays int           `json:"historyRetentionDays" yaml:"history_retention_days" mapstructure:"history_retention_days"` // Days to retain alert history (0 = unlimited)
+	Scripts              []AlertScript `json:"scripts" yaml:"scripts" mapstructure:"scripts"`                                            // Scripts that alert rule actions may run
+}
+
+// AlertScript is a script that alert rule actions may run. Rules refer to a
+// script by name, so rules created through the API can only run commands
+// listed in the configuration file.
+type AlertScript struct {
+	Name    string `json:"name" yaml:"name" mapstructure:"name"`          // Name referenced by alert rule actions
+	Command string `json:"command" yaml:"command" mapstructure:"command"` // Absolute path to the executable
+	Timeout int    `json:"timeout" yaml:"timeout" mapstructure:"timeout"` // Seconds before the script is killed (0 = 30)
 }
 
 // GetOAuthProvider returns the OAuth provider configuration for the given provider ID.
@@ -1843,6 +1966,7 @@ const (
 	WeatherYrNo         WeatherProvider = "yrno"
 	WeatherOpenWeather  WeatherProvider = "openweather"
 	WeatherWunderground WeatherProvider = "wunderground"
+	WeatherStation      WeatherProvider = "station"
 )
 
 // Prefer explicit settings return to avoid confusion at call sites.
@@ -1853,6 +1977,8 @@ func (s *Settings) GetWeatherProvider() (provider WeatherProvider, settings any)
 		return WeatherOpenWeather, s.Realtime.Weather.OpenWeather
 	case string(WeatherWunderground):
 		return WeatherWunderground, s.Realtime.Weather.Wunderground
+	case string(WeatherStation):
+		return WeatherStation, s.Realtime.Weather.Station
 	case string(WeatherYrNo), string(WeatherNone):
 		return WeatherProvider(p), nil
 	default:
@@ -1864,6 +1990,25 @@ func (s *Settings) GetWeatherProvider() (provider WeatherProvider, settings any)
 	}
 }
 
+// ValidateStation validates local weather station settings when the provider is "station"
+func (s *StationSettings) ValidateStation() error {
+	// Normalize like the poll interval: nested viper defaults can be lost
+	if s.Interval == 0 {
+		s.Interval = DefaultWeatherStationInterval
+	}
+	if s.Interval < 1 || s.Interval > MaxWeatherStationInterval {
+		return fmt.Errorf("station.interval must be between 1 and %d minutes, got %d", MaxWeatherStationInterval, s.Interval)
+	}
+	// The upload endpoint cannot require a login, so the PASSKEY is its only credential
+	if strings.TrimSpace(s.PassKey) == "" {
+		return fmt.Errorf("station.passKey is required when provider is station")
+	}
+	if s.MQTT.Enabled && strings.TrimSpace(s.MQTT.Topic) == "" {
+		return fmt.Errorf("station.mqtt.topic is required when station MQTT is enabled")
+	}
+	return nil
+}
+
 // ValidateWunderground validates Wunderground settings when the provider is "wunderground"
 func (w *WundergroundSettings) ValidateWunderground() error {
 	// Validate required fields when provider is "wunderground"
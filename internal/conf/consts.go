@@ -10,6 +10,9 @@ const (
 	ModelIDBat     = "bat"
 	ModelIDBSG     = "bsg"
 
+	// ModelIDCustomClassifier is the user-trained classifier on BirdNET embeddings.
+	ModelIDCustomClassifier = "custom_classifier"
+
 	SampleRate     = 48000 // Sample rate of the audio fed to BirdNET Analyzer
 	BitDepth       = 16    // Bit depth of the audio fed to BirdNET Analyzer
 	NumChannels    = 1     // Number of channels of the audio fed to BirdNET Analyzer
@@ -48,6 +51,12 @@ const (
 
 	// DefaultWeatherPollInterval is the default weather poll interval in minutes.
 	DefaultWeatherPollInterval = 60
+	// DefaultWeatherStationInterval is the default local weather station
+	// record interval in minutes.
+	DefaultWeatherStationInterval = 5
+	// MaxWeatherStationInterval is the longest local weather station record
+	// interval in minutes.
+	MaxWeatherStationInterval = 60
 )
 
 // DefaultSessionDuration is the default session duration (7 days).
@@ -121,6 +121,14 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.audio.soundlevel.enabled", false)
 	viper.SetDefault("realtime.audio.soundlevel.interval", 10)
 
+	// Continuous soundscape recording configuration
+	viper.SetDefault("realtime.audio.soundscape.path", "soundscapes/")
+	viper.SetDefault("realtime.audio.soundscape.format", SoundscapeFormatFLAC)
+	viper.SetDefault("realtime.audio.soundscape.bitrate", 64)
+	viper.SetDefault("realtime.audio.soundscape.segmentlength", DefaultSoundscapeSegmentLength)
+	viper.SetDefault("realtime.audio.soundscape.retention.maxage", "14d")
+	viper.SetDefault("realtime.audio.soundscape.retention.maxsizegb", 0)
+
 	// Audio capture configuration
 	viper.SetDefault("realtime.audio.export.debug", false)
 	viper.SetDefault("realtime.audio.export.enabled", true)
@@ -137,6 +145,10 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.audio.export.normalization.loudnessRange", 7.0) // typical range for broadcast
 	viper.SetDefault("realtime.audio.export.normalization.truePeak", -2.0)     // headroom to prevent clipping
 
+	// Clip embeddings for similar recording search
+	viper.SetDefault("realtime.audio.export.embeddings.enabled", false)
+	viper.SetDefault("realtime.audio.export.embeddings.modelpath", "")
+
 	// Quiet hours configuration (sound card)
 	viper.SetDefault("realtime.audio.quiethours.enabled", false)
 	viper.SetDefault("realtime.audio.quiethours.mode", QuietHoursModeFixed)
@@ -183,6 +195,8 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.dashboard.spectrogram.raw", true)                                     // Raw spectrogram (no axes/legend)
 	viper.SetDefault("realtime.dashboard.spectrogram.style", "default")                              // Visual style preset
 	viper.SetDefault("realtime.dashboard.spectrogram.dynamicrange", SpectrogramDynamicRangeStandard) // Dynamic range in dB (100 = standard)
+	viper.SetDefault("realtime.dashboard.spectrogram.renderer", SpectrogramRendererNative)           // In-process renderer, no Sox needed
+	viper.SetDefault("realtime.dashboard.spectrogram.scale", SpectrogramScaleLinear)                 // Linear frequency axis
 
 	// Retention policy configuration
 	viper.SetDefault("realtime.audio.export.retention.enabled", true)
@@ -193,6 +207,16 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.audio.export.retention.minclips", 10)
 	viper.SetDefault("realtime.audio.export.retention.keepspectrograms", true)
 	viper.SetDefault("realtime.audio.export.retention.checkinterval", DefaultCleanupCheckInterval)
+	viper.SetDefault("realtime.audio.export.retention.tier.target", ClipTierTargetLocal)
+	viper.SetDefault("realtime.audio.export.retention.tier.local.path", "")
+	viper.SetDefault("realtime.audio.export.retention.tier.s3.endpoint", "s3.amazonaws.com")
+	viper.SetDefault("realtime.audio.export.retention.tier.s3.region", "us-east-1")
+	viper.SetDefault("realtime.audio.export.retention.tier.s3.bucket", "")
+	viper.SetDefault("realtime.audio.export.retention.tier.s3.prefix", "birdnet-go/clips")
+	viper.SetDefault("realtime.audio.export.retention.tier.s3.usessl", true)
+	viper.SetDefault("realtime.audio.export.retention.tier.s3.pathstyle", false)
+	viper.SetDefault("realtime.audio.export.retention.tier.sftp.port", 22)
+	viper.SetDefault("realtime.audio.export.retention.tier.sftp.path", "")
 
 	// Dynamic threshold configuration
 	viper.SetDefault("realtime.dynamicthreshold.enabled", true)
@@ -261,6 +285,13 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.weather.wunderground.endpoint", "https://api.weather.com/v2/pws/observations/current")
 	viper.SetDefault("realtime.weather.wunderground.units", "m") // m=metric, e=imperial, h=UK hybrid
 
+	// Local weather station configuration
+	viper.SetDefault("realtime.weather.station.interval", DefaultWeatherStationInterval)
+	viper.SetDefault("realtime.weather.station.passkey", "")
+	viper.SetDefault("realtime.weather.station.mqtt.enabled", false)
+	viper.SetDefault("realtime.weather.station.mqtt.broker", "")
+	viper.SetDefault("realtime.weather.station.mqtt.topic", "weather/loop")
+
 	// RTSP configuration
 	viper.SetDefault("realtime.rtsp.urls", []string{})
 	viper.SetDefault("realtime.rtsp.transport", DefaultTransport)
@@ -276,6 +307,7 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.mqtt.username", "")
 	viper.SetDefault("realtime.mqtt.password", "")
 	viper.SetDefault("realtime.mqtt.retain", false)
+	viper.SetDefault("realtime.mqtt.commands", false)
 	viper.SetDefault("realtime.mqtt.retrysettings.enabled", true)
 	viper.SetDefault("realtime.mqtt.retrysettings.maxretries", 5)
 	viper.SetDefault("realtime.mqtt.retrysettings.initialdelay", 30)
@@ -291,6 +323,8 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.privacyfilter.enabled", true)
 	viper.SetDefault("realtime.privacyfilter.debug", false)
 	viper.SetDefault("realtime.privacyfilter.confidence", 0.05)
+	viper.SetDefault("realtime.privacyfilter.mode", PrivacyModeDiscard)
+	viper.SetDefault("realtime.privacyfilter.redactfill", RedactFillSilence)
 
 	// Dog bark filter configuration
 	viper.SetDefault("realtime.dogbarkfilter.enabled", false)
@@ -372,6 +406,15 @@ func setDefaultConfig() {
 	viper.SetDefault("output.mysql.host", "localhost")
 	viper.SetDefault("output.mysql.port", 3306)
 
+	// PostgreSQL output configuration
+	viper.SetDefault("output.postgres.enabled", false)
+	viper.SetDefault("output.postgres.username", "birdnet")
+	viper.SetDefault("output.postgres.password", "secret")
+	viper.SetDefault("output.postgres.database", "birdnet")
+	viper.SetDefault("output.postgres.host", "localhost")
+	viper.SetDefault("output.postgres.port", 5432)
+	viper.SetDefault("output.postgres.sslmode", "prefer")
+
 	// Security configuration
 	viper.SetDefault("security.debug", false)
 	viper.SetDefault("security.baseurl", "")
@@ -445,6 +488,7 @@ func setDefaultConfig() {
 
 	// Alerting rules engine
 	viper.SetDefault("alerting.history_retention_days", 30)
+	viper.SetDefault("alerting.scripts", []map[string]any{})
 }
 
 // setModuleLogDefaults sets default values for a module log configuration
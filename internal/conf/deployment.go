@@ -0,0 +1,116 @@
+package conf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// MaxDeploymentTextLength bounds the free-text deployment fields. They are
+// stored in varchar(100) columns of the v2 audio_sources table.
+const MaxDeploymentTextLength = 100
+
+// DeploymentConfig describes where an audio source's microphone is installed.
+// Every field is optional. A deployment without coordinates is at the station
+// location (BirdNET.Latitude/Longitude), so single-site installs need no
+// per-source configuration.
+type DeploymentConfig struct {
+	Site             string  `yaml:"site,omitempty" json:"site,omitempty" mapstructure:"site"`                                     // site name grouping sources, e.g. "North Meadow"
+	Latitude         float64 `yaml:"latitude,omitempty" json:"latitude,omitempty" mapstructure:"latitude"`                         // decimal degrees, 0 with Longitude 0 = station location
+	Longitude        float64 `yaml:"longitude,omitempty" json:"longitude,omitempty" mapstructure:"longitude"`                      // decimal degrees
+	Habitat          string  `yaml:"habitat,omitempty" json:"habitat,omitempty" mapstructure:"habitat"`                            // habitat description, e.g. "wetland edge"
+	MicrophoneModel  string  `yaml:"microphoneModel,omitempty" json:"microphoneModel,omitempty" mapstructure:"microphoneModel"`    // microphone make and model
+	MicrophoneHeight float64 `yaml:"microphoneHeight,omitempty" json:"microphoneHeight,omitempty" mapstructure:"microphoneHeight"` // meters above ground
+}
+
+// HasLocation reports whether the deployment has its own coordinates.
+func (d *DeploymentConfig) HasLocation() bool {
+	return d.Latitude != 0 || d.Longitude != 0
+}
+
+// IsZero reports whether no deployment metadata is set.
+func (d *DeploymentConfig) IsZero() bool {
+	return *d == DeploymentConfig{}
+}
+
+// Validate checks the coordinates and lengths. It trims the text fields
+// in-place so downstream code sees normalized values.
+func (d *DeploymentConfig) Validate(context string) error {
+	d.Site = strings.TrimSpace(d.Site)
+	d.Habitat = strings.TrimSpace(d.Habitat)
+	d.MicrophoneModel = strings.TrimSpace(d.MicrophoneModel)
+
+	for _, field := range []struct{ name, value string }{
+		{"site", d.Site},
+		{"habitat", d.Habitat},
+		{"microphone model", d.MicrophoneModel},
+	} {
+		if len(field.value) > MaxDeploymentTextLength {
+			return fmt.Errorf("%s: deployment %s exceeds maximum length of %d characters", context, field.name, MaxDeploymentTextLength)
+		}
+	}
+	if math.IsNaN(d.Latitude) || d.Latitude < -90 || d.Latitude > 90 {
+		return fmt.Errorf("%s: deployment latitude %v out of range [-90, 90]", context, d.Latitude)
+	}
+	if math.IsNaN(d.Longitude) || d.Longitude < -180 || d.Longitude > 180 {
+		return fmt.Errorf("%s: deployment longitude %v out of range [-180, 180]", context, d.Longitude)
+	}
+	if math.IsNaN(d.MicrophoneHeight) || math.IsInf(d.MicrophoneHeight, 0) || d.MicrophoneHeight < 0 {
+		return fmt.Errorf("%s: deployment microphone height must not be negative", context)
+	}
+	return nil
+}
+
+// SourceDeployment returns the deployment of the stream whose URL, or the
+// audio source whose connection string, is connection. It returns nil when no
+// source matches or the matching source has no deployment metadata.
+func (s *Settings) SourceDeployment(connection string) *DeploymentConfig {
+	connection = strings.TrimSpace(connection)
+	if connection == "" {
+		return nil
+	}
+	for _, stream := range s.Realtime.RTSP.AllStreams() {
+		if strings.TrimSpace(stream.URL) == connection {
+			return nonZeroDeployment(&stream.Deployment)
+		}
+	}
+	for i := range s.Realtime.Audio.Sources {
+		src := &s.Realtime.Audio.Sources[i]
+		if src.ConnectionString() == connection {
+			return nonZeroDeployment(&src.Deployment)
+		}
+	}
+	return nil
+}
+
+// DeploymentLocations returns the distinct coordinates of every configured
+// source with its own location, keyed by LocationKey.
+func (s *Settings) DeploymentLocations() map[string][2]float64 {
+	locations := make(map[string][2]float64)
+	add := func(d *DeploymentConfig) {
+		if d.HasLocation() {
+			locations[LocationKey(d.Latitude, d.Longitude)] = [2]float64{d.Latitude, d.Longitude}
+		}
+	}
+	for _, stream := range s.Realtime.RTSP.AllStreams() {
+		add(&stream.Deployment)
+	}
+	for i := range s.Realtime.Audio.Sources {
+		add(&s.Realtime.Audio.Sources[i].Deployment)
+	}
+	return locations
+}
+
+// LocationKey identifies a location for per-location lookups. Coordinates are
+// rounded to two decimals (about 1 km), finer than the geomodel resolves, so
+// nearby sources share one species list.
+func LocationKey(lat, lon float64) string {
+	return fmt.Sprintf("%.2f,%.2f", lat, lon)
+}
+
+func nonZeroDeployment(d *DeploymentConfig) *DeploymentConfig {
+	if d.IsZero() {
+		return nil
+	}
+	return d
+}
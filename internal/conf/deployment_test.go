@@ -0,0 +1,56 @@
+package conf
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	d := DeploymentConfig{Site: "  North Meadow ", Habitat: " wetland edge", Latitude: 60.2, Longitude: 24.9, MicrophoneHeight: 1.5}
+	require.NoError(t, d.Validate("stream 'meadow'"))
+	assert.Equal(t, "North Meadow", d.Site, "text fields are trimmed")
+	assert.Equal(t, "wetland edge", d.Habitat)
+
+	for name, bad := range map[string]DeploymentConfig{
+		"latitude":         {Latitude: 91},
+		"longitude":        {Longitude: -181},
+		"NaN latitude":     {Latitude: math.NaN()},
+		"negative height":  {MicrophoneHeight: -1},
+		"site too long":    {Site: strings.Repeat("x", MaxDeploymentTextLength+1)},
+		"microphone model": {MicrophoneModel: strings.Repeat("x", MaxDeploymentTextLength+1)},
+	} {
+		err := bad.Validate("stream 'meadow'")
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "stream 'meadow'", name)
+	}
+}
+
+func TestSourceDeployment(t *testing.T) {
+	t.Parallel()
+
+	s := &Settings{}
+	s.Realtime.RTSP.Streams = []StreamConfig{
+		{Name: "Meadow", URL: "rtsp://meadow/stream", Deployment: DeploymentConfig{Site: "North Meadow", Latitude: 60.5, Longitude: 25.1}},
+		{Name: "Yard", URL: "rtsp://yard/stream"},
+	}
+	s.Realtime.Audio.Sources = []AudioSourceConfig{
+		{Name: "Pond", Device: "hw:1,0", Deployment: DeploymentConfig{Habitat: "pond", Latitude: 60.5, Longitude: 25.1}},
+	}
+
+	d := s.SourceDeployment("rtsp://meadow/stream")
+	require.NotNil(t, d)
+	assert.Equal(t, "North Meadow", d.Site)
+	require.NotNil(t, s.SourceDeployment("hw:1,0"))
+	assert.Nil(t, s.SourceDeployment("rtsp://yard/stream"), "a source without deployment metadata has none")
+	assert.Nil(t, s.SourceDeployment("rtsp://unknown/stream"))
+	assert.Nil(t, s.SourceDeployment(""))
+
+	assert.Equal(t, map[string][2]float64{"60.50,25.10": {60.5, 25.1}}, s.DeploymentLocations(),
+		"sources at the same location share one entry")
+}
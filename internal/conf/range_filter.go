@@ -68,6 +68,32 @@ func UpdateIncludedSpecies(species []string) {
 	StoreSettings(updated)
 }
 
+// UpdateLocationIncludedSpecies publishes the included species of each
+// deployment location, keyed by LocationKey. It replaces every previous
+// location list; a location missing from species uses the station list.
+func UpdateLocationIncludedSpecies(species map[string][]string) {
+	speciesListMutex.Lock()
+	defer speciesListMutex.Unlock()
+
+	current := GetSettings()
+	if current == nil {
+		return
+	}
+
+	locations := make(map[string]map[string]struct{}, len(species))
+	for key, labels := range species {
+		sciNames := make(map[string]struct{}, len(labels))
+		for _, label := range labels {
+			sciNames[canonicalSci(label)] = struct{}{}
+		}
+		locations[key] = sciNames
+	}
+
+	updated := CloneSettings(current)
+	updated.BirdNET.RangeFilter.LocationScientificNames = locations
+	StoreSettings(updated)
+}
+
 // GetIncludedSpecies returns a copy of the included species list from this
 // snapshot. The snapshot is immutable, so no mutex is needed.
 func (s *Settings) GetIncludedSpecies() []string {
@@ -95,6 +121,19 @@ func (s *Settings) IsSpeciesIncluded(result string) bool {
 	return false
 }
 
+// IsSpeciesIncludedAt is IsSpeciesIncluded for a source deployed at its own
+// location. A nil deployment, one without coordinates, or a location whose
+// list has not been built yet uses the station list.
+func (s *Settings) IsSpeciesIncludedAt(d *DeploymentConfig, result string) bool {
+	if d != nil && d.HasLocation() {
+		if names, ok := s.BirdNET.RangeFilter.LocationScientificNames[LocationKey(d.Latitude, d.Longitude)]; ok {
+			_, found := names[canonicalSci(result)]
+			return found
+		}
+	}
+	return s.IsSpeciesIncluded(result)
+}
+
 // LocalNoon returns 12:00:00 on the calendar day of t, evaluated in t's own time
 // zone. Range-filter date logic uses it to anchor "today" on the local calendar
 // day: time.Time.Truncate operates on absolute (UTC) time, so truncating to a 24h
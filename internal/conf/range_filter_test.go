@@ -498,3 +498,35 @@ func TestResetAndCheckInterleaved(t *testing.T) {
 
 	wg.Wait()
 }
+
+// TestIsSpeciesIncludedAt verifies that sources deployed away from the station
+// are checked against their location's list and everything else falls back to
+// the station list.
+func TestIsSpeciesIncludedAt(t *testing.T) {
+	settings := &Settings{}
+	setupGlobalSettings(t, settings)
+
+	UpdateIncludedSpecies([]string{"Turdus merula_Eurasian Blackbird"})
+	UpdateLocationIncludedSpecies(map[string][]string{
+		LocationKey(61.5, 23.8): {"Gavia arctica_Arctic Loon"},
+	})
+	s := GetSettings()
+
+	away := &DeploymentConfig{Latitude: 61.5, Longitude: 23.8}
+	assert.True(t, s.IsSpeciesIncludedAt(away, "Gavia arctica_Arctic Loon"))
+	assert.False(t, s.IsSpeciesIncludedAt(away, "Turdus merula_Eurasian Blackbird"),
+		"the location list replaces the station list")
+
+	for name, d := range map[string]*DeploymentConfig{
+		"nil deployment":   nil,
+		"no coordinates":   {Site: "Garden"},
+		"location unbuilt": {Latitude: 10, Longitude: 10},
+	} {
+		assert.True(t, s.IsSpeciesIncludedAt(d, "Turdus merula_Eurasian Blackbird"), name)
+		assert.False(t, s.IsSpeciesIncludedAt(d, "Gavia arctica_Arctic Loon"), name)
+	}
+
+	UpdateLocationIncludedSpecies(nil)
+	assert.True(t, GetSettings().IsSpeciesIncludedAt(away, "Turdus merula_Eurasian Blackbird"),
+		"clearing the location lists falls back to the station list")
+}
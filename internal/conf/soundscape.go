@@ -0,0 +1,122 @@
+package conf
+
+import (
+	"fmt"
+	"time"
+)
+
+// Soundscape segment formats.
+const (
+	SoundscapeFormatFLAC = "flac"
+	SoundscapeFormatOpus = "opus"
+)
+
+// Soundscape segment length bounds in seconds. Segments are encoded from an
+// in-memory buffer, so the upper bound also caps the recorder memory: 15
+// minutes of 48 kHz 16-bit mono PCM is about 86 MB.
+const (
+	MinSoundscapeSegmentLength     = 60
+	MaxSoundscapeSegmentLength     = 900
+	DefaultSoundscapeSegmentLength = 300
+)
+
+// SoundscapeSettings configures continuous soundscape recording. Sources opt
+// in with their soundscape flag; these settings apply to every recorder and
+// are read at the start of each segment, so changes need no restart.
+type SoundscapeSettings struct {
+	Path          string                      `yaml:"path" json:"path" mapstructure:"path"`                            // directory for soundscape segments, separate from detection clips
+	Format        string                      `yaml:"format" json:"format" mapstructure:"format"`                      // segment format: "flac" (lossless) or "opus"
+	Bitrate       int                         `yaml:"bitrate" json:"bitrate" mapstructure:"bitrate"`                   // Opus bitrate in kbit/s
+	SegmentLength int                         `yaml:"segmentlength" json:"segmentLength" mapstructure:"segmentlength"` // segment length in seconds (60-900)
+	Retention     SoundscapeRetentionSettings `yaml:"retention" json:"retention" mapstructure:"retention"`             // segment retention, independent of clip retention
+}
+
+// SoundscapeRetentionSettings bounds the disk space taken by soundscape
+// segments. The oldest segments are deleted first; both limits apply when set.
+type SoundscapeRetentionSettings struct {
+	MaxAge    string `yaml:"maxage" json:"maxAge" mapstructure:"maxage"`          // delete segments older than this, e.g. "14d" (empty = no age limit)
+	MaxSizeGB int    `yaml:"maxsizegb" json:"maxSizeGB" mapstructure:"maxsizegb"` // delete the oldest segments above this total size in GB (0 = no size limit)
+}
+
+// MaxAgeDuration returns the parsed age limit, or zero when none is set.
+func (r *SoundscapeRetentionSettings) MaxAgeDuration() (time.Duration, error) {
+	if r.MaxAge == "" {
+		return 0, nil
+	}
+	hours, err := ParseRetentionPeriod(r.MaxAge)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(hours) * time.Hour, nil
+}
+
+// MaxSizeBytes returns the size limit in bytes, or zero when none is set.
+func (r *SoundscapeRetentionSettings) MaxSizeBytes() int64 {
+	return int64(r.MaxSizeGB) << 30
+}
+
+// SoundscapeEnabled reports whether any audio source or enabled stream
+// records a continuous soundscape.
+func (s *Settings) SoundscapeEnabled() bool {
+	for i := range s.Realtime.Audio.Sources {
+		if s.Realtime.Audio.Sources[i].Soundscape {
+			return true
+		}
+	}
+	for _, stream := range s.Realtime.RTSP.EnabledStreams() {
+		if stream.Soundscape {
+			return true
+		}
+	}
+	return false
+}
+
+// SoundscapeEnabledFor reports whether the audio source or stream with the
+// given name records a continuous soundscape. Names match the registry
+// DisplayName, like the per-source equalizer lookup.
+func (s *Settings) SoundscapeEnabledFor(name string) bool {
+	for i := range s.Realtime.Audio.Sources {
+		if s.Realtime.Audio.Sources[i].Name == name {
+			return s.Realtime.Audio.Sources[i].Soundscape
+		}
+	}
+	for i := range s.Realtime.RTSP.Streams {
+		if s.Realtime.RTSP.Streams[i].Name == name {
+			return s.Realtime.RTSP.Streams[i].Soundscape
+		}
+	}
+	return false
+}
+
+// validateSoundscapeSettings checks the soundscape settings. It runs only
+// when a source records a soundscape so configs that never use the feature
+// are not rejected for values they do not use.
+func validateSoundscapeSettings(s *SoundscapeSettings) error {
+	if err := validateExportPath(s.Path); err != nil {
+		return err
+	}
+	if s.Path == "" {
+		return fmt.Errorf("soundscape path must not be empty")
+	}
+	switch s.Format {
+	case SoundscapeFormatFLAC:
+	case SoundscapeFormatOpus:
+		if s.Bitrate < minAudioExportBitrateKbps || s.Bitrate > maxAudioExportBitrateKbps {
+			return fmt.Errorf("soundscape Opus bitrate must be between %d and %d kbit/s, got %d",
+				minAudioExportBitrateKbps, maxAudioExportBitrateKbps, s.Bitrate)
+		}
+	default:
+		return fmt.Errorf("soundscape format must be %q or %q, got %q", SoundscapeFormatFLAC, SoundscapeFormatOpus, s.Format)
+	}
+	if s.SegmentLength < MinSoundscapeSegmentLength || s.SegmentLength > MaxSoundscapeSegmentLength {
+		return fmt.Errorf("soundscape segment length must be between %d and %d seconds, got %d",
+			MinSoundscapeSegmentLength, MaxSoundscapeSegmentLength, s.SegmentLength)
+	}
+	if _, err := s.Retention.MaxAgeDuration(); err != nil {
+		return fmt.Errorf("invalid soundscape retention max age: %w", err)
+	}
+	if s.Retention.MaxSizeGB < 0 {
+		return fmt.Errorf("soundscape retention max size must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package conf
+
+import "strings"
+
+// Per-source species overrides refine the global Realtime.Species settings
+// for one audio source or stream:
+//
+//   - Exclude adds to the global exclude list; an excluded species is never
+//     reported from the source.
+//   - Include lists species that are always reported from the source, even
+//     when the range filter would drop them.
+//   - A Config entry replaces the global entry for the same species on that
+//     source, including its threshold, interval and actions.
+
+// SourceSpecies returns the species overrides of the stream whose URL, or the
+// audio source whose connection string, is connection. It returns nil when no
+// source matches or the matching source has no overrides.
+func (s *Settings) SourceSpecies(connection string) *SpeciesSettings {
+	connection = strings.TrimSpace(connection)
+	if connection == "" {
+		return nil
+	}
+	for _, stream := range s.Realtime.RTSP.AllStreams() {
+		if strings.TrimSpace(stream.URL) == connection {
+			return stream.Species
+		}
+	}
+	for i := range s.Realtime.Audio.Sources {
+		src := &s.Realtime.Audio.Sources[i]
+		if src.ConnectionString() == connection {
+			return src.Species
+		}
+	}
+	return nil
+}
+
+// NormalizeSourceSpeciesConfigKeys lowercases the Config keys of every
+// per-source species override, see NormalizeSpeciesConfigKeys.
+func (s *Settings) NormalizeSourceSpeciesConfigKeys() {
+	normalize := func(species *SpeciesSettings) {
+		if species != nil && species.Config != nil {
+			species.Config = NormalizeSpeciesConfigKeys(species.Config)
+		}
+	}
+	for _, stream := range s.Realtime.RTSP.AllStreams() {
+		normalize(stream.Species)
+	}
+	for i := range s.Realtime.Audio.Sources {
+		normalize(s.Realtime.Audio.Sources[i].Species)
+	}
+}
@@ -0,0 +1,80 @@
+package conf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceSpecies(t *testing.T) {
+	t.Parallel()
+
+	s := &Settings{}
+	s.Realtime.RTSP.Streams = []StreamConfig{
+		{Name: "Feeder", URL: "rtsp://feeder/stream", Species: &SpeciesSettings{
+			Config: map[string]SpeciesConfig{"Eurasian Siskin": {Threshold: 0.3}},
+		}},
+		{Name: "Yard", URL: "rtsp://yard/stream"},
+	}
+	s.Realtime.Audio.Sources = []AudioSourceConfig{
+		{Name: "Roadside", Device: "hw:1,0", Species: &SpeciesSettings{Exclude: []string{"Eurasian Magpie"}}},
+	}
+
+	s.NormalizeSourceSpeciesConfigKeys()
+	feeder := s.SourceSpecies("rtsp://feeder/stream")
+	require.NotNil(t, feeder)
+	assert.Contains(t, feeder.Config, "eurasian siskin", "config keys are normalized")
+
+	roadside := s.SourceSpecies("hw:1,0")
+	require.NotNil(t, roadside)
+	assert.Equal(t, []string{"Eurasian Magpie"}, roadside.Exclude)
+
+	assert.Nil(t, s.SourceSpecies("rtsp://yard/stream"), "a source without overrides uses the global settings")
+	assert.Nil(t, s.SourceSpecies("rtsp://unknown/stream"))
+	assert.Nil(t, s.SourceSpecies(""))
+}
+
+func TestSourceSpeciesValidation(t *testing.T) {
+	t.Parallel()
+
+	stream := StreamConfig{
+		Name: "Feeder", URL: "rtsp://feeder/stream", Type: StreamTypeRTSP, Transport: "tcp",
+		Species: &SpeciesSettings{Config: map[string]SpeciesConfig{"eurasian siskin": {Threshold: 1.5}}},
+	}
+	err := stream.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stream 'Feeder'")
+	assert.Contains(t, err.Error(), "threshold")
+
+	stream.Species.Config["eurasian siskin"] = SpeciesConfig{Threshold: 0.3}
+	require.NoError(t, stream.Validate())
+
+	src := AudioSourceConfig{
+		Name: "Roadside", Device: "hw:1,0",
+		Species: &SpeciesSettings{Config: map[string]SpeciesConfig{"eurasian magpie": {Interval: -1}}},
+	}
+	err = src.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "audio source 'Roadside'")
+}
+
+func TestCloneSourceSpecies(t *testing.T) {
+	t.Parallel()
+
+	s := &Settings{}
+	s.Realtime.RTSP.Streams = []StreamConfig{{Name: "Feeder", URL: "rtsp://feeder/stream", Species: &SpeciesSettings{
+		Include: []string{"Eurasian Siskin"},
+		Config:  map[string]SpeciesConfig{"eurasian siskin": {Threshold: 0.3}},
+	}}}
+	s.Realtime.Audio.Sources = []AudioSourceConfig{{Name: "Roadside", Device: "hw:1,0", Species: &SpeciesSettings{Exclude: []string{"Eurasian Magpie"}}}}
+
+	c := CloneSettings(s)
+	c.Realtime.RTSP.Streams[0].Species.Include[0] = "changed"
+	c.Realtime.RTSP.Streams[0].Species.Config["eurasian siskin"] = SpeciesConfig{Threshold: 0.9}
+	c.Realtime.Audio.Sources[0].Species.Exclude[0] = "changed"
+
+	assert.Equal(t, "Eurasian Siskin", s.Realtime.RTSP.Streams[0].Species.Include[0])
+	assert.InDelta(t, 0.3, s.Realtime.RTSP.Streams[0].Species.Config["eurasian siskin"].Threshold, 0)
+	assert.Equal(t, "Eurasian Magpie", s.Realtime.Audio.Sources[0].Species.Exclude[0])
+}
@@ -86,6 +86,11 @@ func Load() (*Settings, error) {
 	if settings.Realtime.Species.Config != nil {
 		settings.Realtime.Species.Config = NormalizeSpeciesConfigKeys(settings.Realtime.Species.Config)
 	}
+	settings.NormalizeSourceSpeciesConfigKeys()
+
+	// Normalize the PostgreSQL sslmode so the DSN always carries a libpq
+	// keyword; an empty value falls back to the driver default "prefer".
+	settings.Output.Postgres.SSLMode = NormalizePostgresSSLMode(settings.Output.Postgres.SSLMode)
 
 	// Migrate legacy AutoTLS boolean to new TLSMode field
 	if settings.MigrateTLSConfig() {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/tphakala/birdnet-go/internal/errors"
@@ -23,6 +24,7 @@ const (
 	RetentionPolicyNone  = "none"  // No retention cleanup
 	RetentionPolicyAge   = "age"   // Age-based retention cleanup
 	RetentionPolicyUsage = "usage" // Disk usage-based retention cleanup
+	RetentionPolicyTier  = "tier"  // Move older clips to a secondary store
 )
 
 // validRetentionPolicies contains all valid retention policy values
@@ -30,8 +32,24 @@ var validRetentionPolicies = []string{
 	RetentionPolicyNone,
 	RetentionPolicyAge,
 	RetentionPolicyUsage,
+	RetentionPolicyTier,
 }
 
+// Valid privacy filter modes and redaction fills
+const (
+	PrivacyModeDiscard = "discard" // Drop detections near human voice
+	PrivacyModeRedact  = "redact"  // Keep detections, blank human voice in the clip
+
+	RedactFillSilence = "silence" // Replace redacted voice with silence
+	RedactFillNoise   = "noise"   // Replace redacted voice with low-level noise
+)
+
+// validPrivacyModes and validRedactFills contain the accepted values
+var (
+	validPrivacyModes = []string{PrivacyModeDiscard, PrivacyModeRedact}
+	validRedactFills  = []string{RedactFillSilence, RedactFillNoise}
+)
+
 // htmlTagPattern matches HTML tags for sanitization.
 var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
 
@@ -72,6 +90,8 @@ var ValidAudioModels = map[string]bool{
 	ModelIDPerchV2: true,
 	ModelIDBat:     true,
 	ModelIDBSG:     true,
+
+	ModelIDCustomClassifier: true,
 }
 
 // ValidationError is the set of fatal validation findings produced by
@@ -236,10 +256,20 @@ func ValidateSettings(settings *Settings) error {
 		ve.Errors = append(ve.Errors, err.Error())
 	}
 
+	// Validate soundscape recording settings when a source records one
+	if settings.SoundscapeEnabled() {
+		if err := validateSoundscapeSettings(&settings.Realtime.Audio.Soundscape); err != nil {
+			ve.Errors = append(ve.Errors, err.Error())
+		}
+	}
+
 	// Validate Retention settings (policy, maxAge, maxUsage)
 	if err := validateRetentionSettings(&settings.Realtime.Audio.Export.Retention); err != nil {
 		ve.Errors = append(ve.Errors, err.Error())
 	}
+	if err := validateClipTierLocalPath(&settings.Realtime.Audio.Export.Retention, settings.Realtime.Audio.Export.Path); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
 
 	// Validate Dashboard settings
 	if err := validateDashboardSettings(&settings.Realtime.Dashboard); err != nil {
@@ -261,9 +291,50 @@ func ValidateSettings(settings *Settings) error {
 		ve.Errors = append(ve.Errors, err.Error())
 	}
 
+	// Validate PostgreSQL connection settings
+	if err := validatePostgresSettings(settings); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
 	// If there are any errors, return the ValidationError
 	if len(ve.Errors) > 0 {
 		return ve
 	}
 	return nil
 }
+
+// validPostgresSSLModes lists the libpq sslmode values accepted by the
+// PostgreSQL driver.
+var validPostgresSSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// NormalizePostgresSSLMode lowercases and trims an sslmode value. An empty
+// value becomes "prefer", the libpq default.
+func NormalizePostgresSSLMode(mode string) string {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		return "prefer"
+	}
+	return mode
+}
+
+// validatePostgresSettings checks the PostgreSQL output settings. An empty
+// sslmode is accepted and left to the libpq default.
+func validatePostgresSettings(settings *Settings) error {
+	pg := &settings.Output.Postgres
+	if !pg.Enabled {
+		return nil
+	}
+	if pg.SSLMode != "" && !slices.Contains(validPostgresSSLModes, NormalizePostgresSSLMode(pg.SSLMode)) {
+		return errors.Newf("postgres sslmode must be one of %v, got %q", validPostgresSSLModes, pg.SSLMode).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "postgres-sslmode").
+			Build()
+	}
+	if pg.Host == "" || pg.Database == "" {
+		return errors.Newf("postgres host and database must be set when postgres output is enabled").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "postgres-connection").
+			Build()
+	}
+	return nil
+}
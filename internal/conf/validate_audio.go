@@ -175,7 +175,14 @@ func (s *StreamConfig) Validate() error {
 		return err
 	}
 
-	return nil
+	// Validate per-stream species overrides if set
+	if s.Species != nil {
+		if err := validateSpeciesConfigSettings(s.Species); err != nil {
+			return fmt.Errorf("stream '%s': %w", s.Name, err)
+		}
+	}
+
+	return s.Deployment.Validate(fmt.Sprintf("stream '%s'", s.Name))
 }
 
 // ValidateQuietHours validates a quiet hours configuration
@@ -342,6 +349,10 @@ func (a *AudioSourceConfig) Validate() error {
 		}
 	}
 
+	if err := a.validateChannels(); err != nil {
+		return err
+	}
+
 	// Validate model identifier
 	if !ValidAudioModels[a.Model] {
 		return fmt.Errorf("audio source '%s': unknown model '%s'", a.Name, a.Model)
@@ -359,14 +370,22 @@ func (a *AudioSourceConfig) Validate() error {
 		return err
 	}
 
-	return nil
+	// Validate per-source species overrides if set
+	if a.Species != nil {
+		if err := validateSpeciesConfigSettings(a.Species); err != nil {
+			return fmt.Errorf("audio source '%s': %w", a.Name, err)
+		}
+	}
+
+	return a.Deployment.Validate(fmt.Sprintf("audio source '%s'", a.Name))
 }
 
 // ValidateSources validates all audio source configurations, including
-// duplicate name and device detection.
+// duplicate name and device detection. A device may repeat when its sources
+// select different channels, see validateSharedDevices.
 func (a *AudioSettings) ValidateSources() error {
 	names := make(map[string]bool)
-	devices := make(map[string]bool)
+	connections := make(map[string]bool)
 
 	for i := range a.Sources {
 		src := &a.Sources[i]
@@ -381,14 +400,16 @@ func (a *AudioSettings) ValidateSources() error {
 		}
 		names[nameLower] = true
 
-		// Check for duplicate devices
-		if devices[src.Device] {
-			return fmt.Errorf("audio source '%s' has a duplicate device: '%s'", src.Name, src.Device)
+		// Check for duplicate devices, or duplicate channel selections on a
+		// shared device
+		conn := src.ConnectionString()
+		if connections[conn] {
+			return fmt.Errorf("audio source '%s' has a duplicate device: '%s'", src.Name, conn)
 		}
-		devices[src.Device] = true
+		connections[conn] = true
 	}
 
-	return nil
+	return a.validateSharedDevices()
 }
 
 // clearFfmpegMetadata resets all FFmpeg-related fields when path validation fails.
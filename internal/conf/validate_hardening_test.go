@@ -414,6 +414,52 @@ func TestValidateSettings_EmptyBirdNETLocaleDefaulted(t *testing.T) {
 		"defaulted locale should be an English variant")
 }
 
+func TestValidatePostgresSettings(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		enabled bool
+		sslMode string
+		host    string
+		wantErr string
+	}{
+		{"disabled ignores invalid sslmode", false, "bogus", "", ""},
+		{"empty sslmode accepted", true, "", "db", ""},
+		{"require accepted", true, "require", "db", ""},
+		{"mixed case accepted", true, " Verify-Full ", "db", ""},
+		{"unknown sslmode rejected", true, "always", "db", "postgres sslmode"},
+		{"missing host rejected", true, "disable", "", "host and database"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			settings := &Settings{}
+			settings.Output.Postgres.Enabled = tt.enabled
+			settings.Output.Postgres.SSLMode = tt.sslMode
+			settings.Output.Postgres.Host = tt.host
+			settings.Output.Postgres.Database = "birdnet"
+
+			err := validatePostgresSettings(settings)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.sslMode, settings.Output.Postgres.SSLMode, "validation must not modify settings")
+		})
+	}
+}
+
+func TestNormalizePostgresSSLMode(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "prefer", NormalizePostgresSSLMode(""))
+	assert.Equal(t, "verify-full", NormalizePostgresSSLMode(" Verify-Full "))
+	assert.Equal(t, "disable", NormalizePostgresSSLMode("disable"))
+}
+
 func TestValidateWeatherSettings_InvalidProvider(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -426,6 +472,7 @@ func TestValidateWeatherSettings_InvalidProvider(t *testing.T) {
 		{"yrno provider allowed", "yrno", false},
 		{"openweather provider allowed", "openweather", false},
 		{"wunderground provider allowed", "wunderground", false},
+		{"station provider allowed", "station", false},
 		{"unknown provider rejected", "invalid_provider", true},
 		{"whitespace-only rejected", "  ", true},
 	}
@@ -440,6 +487,7 @@ func TestValidateWeatherSettings_InvalidProvider(t *testing.T) {
 					APIKey:    "testkey",
 					StationID: "KTEST1",
 				},
+				Station: StationSettings{PassKey: "ABC123"},
 			}
 
 			err := validateWeatherSettings(settings)
@@ -454,6 +502,34 @@ func TestValidateWeatherSettings_InvalidProvider(t *testing.T) {
 	}
 }
 
+func TestValidateStation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		settings StationSettings
+		errMsg   string
+	}{
+		{"valid", StationSettings{Interval: 5, PassKey: "ABC123"}, ""},
+		{"passkey required", StationSettings{Interval: 5}, "passKey is required"},
+		{"blank passkey rejected", StationSettings{Interval: 5, PassKey: "  "}, "passKey is required"},
+		{"interval out of range", StationSettings{Interval: 61, PassKey: "ABC123"}, "interval"},
+		{"mqtt topic required", StationSettings{Interval: 5, PassKey: "ABC123", MQTT: StationMQTTSettings{Enabled: true}}, "topic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.settings.ValidateStation()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
 // -----------------------------------------------------------------------
 // Issue #504: Retention maxAge/minClips
 // -----------------------------------------------------------------------
@@ -543,6 +619,74 @@ func TestValidateRetentionSettings_MinClipsValidatedRegardlessOfPolicy(t *testin
 	assert.Equal(t, errors.CategoryValidation, enhanced.Category)
 }
 
+func TestValidateRetentionSettings_TierTarget(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		tier     ClipTierSettings
+		maxUsage string
+		wantErr  string
+	}{
+		{"local target", ClipTierSettings{Target: ClipTierTargetLocal, Local: ClipTierLocalSettings{Path: "/mnt/nas/clips"}}, "", ""},
+		{"s3 target with usage trigger", ClipTierSettings{Target: ClipTierTargetS3, S3: ClipTierS3Settings{Bucket: "clips"}}, "80%", ""},
+		{"sftp target with key", ClipTierSettings{Target: ClipTierTargetSFTP, SFTP: ClipTierSFTPSettings{Host: "nas", Username: "birdnet", PrivateKeyPath: "/home/birdnet/.ssh/id_ed25519"}}, "", ""},
+		{"missing target", ClipTierSettings{}, "", "retention-tier"},
+		{"local without path", ClipTierSettings{Target: ClipTierTargetLocal}, "", "retention-tier"},
+		{"s3 without bucket", ClipTierSettings{Target: ClipTierTargetS3}, "", "retention-tier"},
+		{"sftp without auth", ClipTierSettings{Target: ClipTierTargetSFTP, SFTP: ClipTierSFTPSettings{Host: "nas", Username: "birdnet"}}, "", "retention-tier"},
+		{"invalid usage trigger", ClipTierSettings{Target: ClipTierTargetLocal, Local: ClipTierLocalSettings{Path: "/mnt/nas"}}, "lots", "retention-max-usage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			settings := &RetentionSettings{
+				Policy:   RetentionPolicyTier,
+				MaxAge:   "30d",
+				MaxUsage: tt.maxUsage,
+				Tier:     tt.tier,
+			}
+
+			err := validateRetentionSettings(settings)
+
+			if tt.wantErr != "" {
+				assertValidationError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateClipTierLocalPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"separate directory", "/mnt/nas/clips", false},
+		{"sibling with shared prefix", "/data/clips-archive", false},
+		{"export directory itself", "/data/clips", true},
+		{"inside export directory", "/data/clips/archive", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			retention := &RetentionSettings{Policy: RetentionPolicyTier}
+			retention.Tier.Target = ClipTierTargetLocal
+			retention.Tier.Local.Path = tt.path
+			err := validateClipTierLocalPath(retention, "/data/clips")
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 // -----------------------------------------------------------------------
 // Helper: createMinimalValidSettings creates a Settings struct that passes
 // all validation, so tests can modify one field at a time.
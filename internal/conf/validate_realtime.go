@@ -82,6 +82,32 @@ func validateRealtimeSettings(settings *RealtimeSettings) error {
 		return err
 	}
 
+	// Validate privacy filter settings
+	if err := validatePrivacyFilterSettings(&settings.PrivacyFilter); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validatePrivacyFilterSettings validates the privacy filter mode and
+// redaction fill. Empty values mean discard and silence, as before redaction
+// existed.
+func validatePrivacyFilterSettings(settings *PrivacyFilterSettings) error {
+	if settings.Mode != "" && !slices.Contains(validPrivacyModes, settings.Mode) {
+		return errors.Newf("privacy filter mode must be one of %v, got %q", validPrivacyModes, settings.Mode).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "privacy-filter-mode").
+			Context("mode", settings.Mode).
+			Build()
+	}
+	if settings.RedactFill != "" && !slices.Contains(validRedactFills, settings.RedactFill) {
+		return errors.Newf("privacy filter redactFill must be one of %v, got %q", validRedactFills, settings.RedactFill).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "privacy-filter-redact-fill").
+			Context("redact_fill", settings.RedactFill).
+			Build()
+	}
 	return nil
 }
 
@@ -130,8 +156,9 @@ func validateRetentionSettings(settings *RetentionSettings) error {
 			Build()
 	}
 
-	// Validate MaxAge when age-based policy is active
-	if settings.Policy == RetentionPolicyAge {
+	// Validate MaxAge when age-based or tier policy is active; the tier
+	// policy moves clips older than MaxAge to the secondary store.
+	if settings.Policy == RetentionPolicyAge || settings.Policy == RetentionPolicyTier {
 		hours, err := ParseRetentionPeriod(settings.MaxAge)
 		if err != nil {
 			return errors.Newf("retention maxAge %q is invalid: %v", settings.MaxAge, err).
@@ -150,8 +177,9 @@ func validateRetentionSettings(settings *RetentionSettings) error {
 		}
 	}
 
-	// Validate MaxUsage when usage-based policy is active
-	if settings.Policy == RetentionPolicyUsage {
+	// Validate MaxUsage when usage-based policy is active, or when the tier
+	// policy also moves clips to relieve disk usage.
+	if settings.Policy == RetentionPolicyUsage || (settings.Policy == RetentionPolicyTier && settings.MaxUsage != "") {
 		if _, err := ParsePercentage(settings.MaxUsage, "retention.maxUsage"); err != nil {
 			return errors.Newf("retention maxUsage %q is invalid: %v", settings.MaxUsage, err).
 				Category(errors.CategoryValidation).
@@ -161,6 +189,17 @@ func validateRetentionSettings(settings *RetentionSettings) error {
 		}
 	}
 
+	// Validate the secondary store when the tier policy is active
+	if settings.Policy == RetentionPolicyTier {
+		if err := validateClipTierSettings(&settings.Tier); err != nil {
+			return errors.New(err).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "retention-tier").
+				Context("target", settings.Tier.Target).
+				Build()
+		}
+	}
+
 	return nil
 }
 
@@ -272,6 +311,28 @@ func validateDashboardSettings(settings *Dashboard) error {
 		}
 	}
 
+	// Validate spectrogram renderer
+	if settings.Spectrogram.Renderer != "" {
+		validRenderers := []string{SpectrogramRendererNative, SpectrogramRendererSox}
+		if !slices.Contains(validRenderers, settings.Spectrogram.Renderer) {
+			GetLogger().Warn("Invalid spectrogram renderer, using native",
+				logger.String("invalid_renderer", settings.Spectrogram.Renderer),
+				logger.String("valid_renderers", strings.Join(validRenderers, ", ")))
+			settings.Spectrogram.Renderer = SpectrogramRendererNative
+		}
+	}
+
+	// Validate spectrogram frequency scale
+	if settings.Spectrogram.Scale != "" {
+		validScales := []string{SpectrogramScaleLinear, SpectrogramScaleLog, SpectrogramScaleMel}
+		if !slices.Contains(validScales, settings.Spectrogram.Scale) {
+			GetLogger().Warn("Invalid spectrogram scale, using linear",
+				logger.String("invalid_scale", settings.Spectrogram.Scale),
+				logger.String("valid_scales", strings.Join(validScales, ", ")))
+			settings.Spectrogram.Scale = SpectrogramScaleLinear
+		}
+	}
+
 	// Log the effective spectrogram mode at startup for troubleshooting
 	effectiveMode := settings.Spectrogram.GetMode()
 	GetLogger().Debug("Spectrogram configuration",
@@ -280,13 +341,15 @@ func validateDashboardSettings(settings *Dashboard) error {
 		logger.String("effective_mode", effectiveMode),
 		logger.String("size", settings.Spectrogram.Size),
 		logger.Bool("raw", settings.Spectrogram.Raw),
-		logger.String("style", settings.Spectrogram.Style))
+		logger.String("style", settings.Spectrogram.Style),
+		logger.String("renderer", settings.Spectrogram.Renderer),
+		logger.String("scale", settings.Spectrogram.Scale))
 
 	return nil
 }
 
 // validWeatherProviders contains all recognized weather provider values.
-var validWeatherProviders = []string{"none", "yrno", "openweather", "wunderground"} //nolint:goconst // weather-provider value, not the RetentionPolicyNone constant
+var validWeatherProviders = []string{"none", "yrno", "openweather", "wunderground", "station"} //nolint:goconst // weather-provider value, not the RetentionPolicyNone constant
 
 // validateWeatherSettings validates weather-specific settings
 func validateWeatherSettings(settings *WeatherSettings) error {
@@ -326,6 +389,16 @@ func validateWeatherSettings(settings *WeatherSettings) error {
 		}
 	}
 
+	// Validate local station settings if it's the selected provider
+	if settings.Provider == string(WeatherStation) {
+		if err := settings.Station.ValidateStation(); err != nil {
+			return errors.New(err).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "station-settings").
+				Build()
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,242 @@
+// Package darwincore writes detections as a Darwin Core Archive, the dataset
+// format GBIF and other biodiversity portals ingest.
+//
+// An archive is a ZIP file holding a tab-separated occurrence table
+// (occurrence.txt), a descriptor mapping its columns to Darwin Core terms
+// (meta.xml) and dataset metadata in the Ecological Metadata Language
+// (eml.xml). Occurrences are streamed into the archive as they are written;
+// the descriptor and metadata, which summarise the rows, are added on Close.
+package darwincore
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const componentName = "darwincore"
+
+// Archive member names.
+const (
+	OccurrenceFile = "occurrence.txt"
+	MetaFile       = "meta.xml"
+	EMLFile        = "eml.xml"
+)
+
+// Values of the identificationVerificationStatus term.
+const (
+	StatusVerified      = "verified"
+	StatusUnverified    = "unverified"
+	StatusFalsePositive = "false positive"
+)
+
+// Fixed values shared by every occurrence recorded by a station.
+const (
+	basisOfRecord    = "MachineObservation"
+	occurrenceStatus = "present"
+	geodeticDatum    = "EPSG:4326"
+	samplingProtocol = "passive acoustic monitoring"
+)
+
+// Licences GBIF accepts for published datasets.
+const (
+	LicenseCC0    = "CC0 1.0"
+	LicenseCCBY   = "CC-BY 4.0"
+	LicenseCCBYNC = "CC-BY-NC 4.0"
+)
+
+// Licenses lists the accepted licences.
+var Licenses = []string{LicenseCC0, LicenseCCBY, LicenseCCBYNC}
+
+// DefaultLicense is the licence applied when a Dataset does not name one.
+const DefaultLicense = LicenseCCBY
+
+// Dataset describes the archive as a whole.
+type Dataset struct {
+	ID          string // packageId of the metadata; keep it stable across exports
+	Title       string
+	Description string
+	Creator     string    // person or organisation credited for the dataset
+	License     string    // defaults to DefaultLicense
+	Created     time.Time // defaults to the time the archive is closed
+	Latitude    float64   // station location, used for the geographic coverage
+	Longitude   float64
+	HasLocation bool
+}
+
+// Occurrence is a single detection in the occurrence table.
+type Occurrence struct {
+	ID                 string // globally unique, stable identifier
+	EventDate          time.Time
+	ScientificName     string
+	VernacularName     string
+	Latitude           float64
+	Longitude          float64
+	HasLocation        bool
+	Locality           string  // deployment site of the recording source
+	Habitat            string  // habitat at the deployment site
+	Confidence         float64 // 0..1
+	IdentifiedBy       string  // classifier that made the identification
+	VerificationStatus string  // one of the Status constants
+	Remarks            string  // free text, such as the recording source
+}
+
+// term is an occurrence column and the Darwin Core term it maps to.
+type term struct {
+	name  string
+	value func(*Occurrence) string
+}
+
+// columns lists the occurrence table columns. The first column is the
+// archive's record identifier.
+var columns = []term{
+	{"occurrenceID", func(o *Occurrence) string { return o.ID }},
+	{"basisOfRecord", func(*Occurrence) string { return basisOfRecord }},
+	{"occurrenceStatus", func(*Occurrence) string { return occurrenceStatus }},
+	{"eventDate", func(o *Occurrence) string { return o.EventDate.Format(time.RFC3339) }},
+	{"scientificName", func(o *Occurrence) string { return o.ScientificName }},
+	{"vernacularName", func(o *Occurrence) string { return o.VernacularName }},
+	{"decimalLatitude", func(o *Occurrence) string { return formatCoordinate(o.HasLocation, o.Latitude) }},
+	{"decimalLongitude", func(o *Occurrence) string { return formatCoordinate(o.HasLocation, o.Longitude) }},
+	{"geodeticDatum", func(o *Occurrence) string {
+		if !o.HasLocation {
+			return ""
+		}
+		return geodeticDatum
+	}},
+	{"locality", func(o *Occurrence) string { return o.Locality }},
+	{"habitat", func(o *Occurrence) string { return o.Habitat }},
+	{"samplingProtocol", func(*Occurrence) string { return samplingProtocol }},
+	{"identifiedBy", func(o *Occurrence) string { return o.IdentifiedBy }},
+	{"identificationVerificationStatus", func(o *Occurrence) string { return o.VerificationStatus }},
+	{"occurrenceRemarks", func(o *Occurrence) string { return o.Remarks }},
+	{"dynamicProperties", func(o *Occurrence) string {
+		return `{"confidence":` + strconv.FormatFloat(o.Confidence, 'f', 4, 64) + `}`
+	}},
+}
+
+func formatCoordinate(ok bool, v float64) string {
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', 6, 64)
+}
+
+// fieldReplacer removes the characters that would break the tab-separated
+// table. The archive declares no quote character, so they cannot be escaped.
+var fieldReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+// Writer streams occurrences into a Darwin Core Archive.
+type Writer struct {
+	zw      *zip.Writer
+	table   io.Writer
+	dataset Dataset
+
+	count    int
+	first    time.Time
+	last     time.Time
+	species  map[string]struct{}
+	taxa     []string
+	closed   bool
+	rowCells []string
+}
+
+// NewWriter starts an archive on w and writes the occurrence table header.
+// The caller must call Close to complete the archive; w itself is not closed.
+func NewWriter(w io.Writer, dataset Dataset) (*Writer, error) {
+	zw := zip.NewWriter(w)
+	table, err := zw.Create(OccurrenceFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: create %s: %w", componentName, OccurrenceFile, err)
+	}
+	dw := &Writer{
+		zw:       zw,
+		table:    table,
+		dataset:  dataset,
+		species:  make(map[string]struct{}),
+		rowCells: make([]string, len(columns)),
+	}
+	for i, col := range columns {
+		dw.rowCells[i] = col.name
+	}
+	if err := dw.writeRow(); err != nil {
+		return nil, err
+	}
+	return dw, nil
+}
+
+// Write appends an occurrence to the table.
+func (w *Writer) Write(o *Occurrence) error {
+	if w.closed {
+		return fmt.Errorf("%s: write to closed archive", componentName)
+	}
+	for i, col := range columns {
+		w.rowCells[i] = fieldReplacer.Replace(col.value(o))
+	}
+	if err := w.writeRow(); err != nil {
+		return err
+	}
+
+	w.count++
+	if w.first.IsZero() || o.EventDate.Before(w.first) {
+		w.first = o.EventDate
+	}
+	if o.EventDate.After(w.last) {
+		w.last = o.EventDate
+	}
+	if _, seen := w.species[o.ScientificName]; !seen && o.ScientificName != "" {
+		w.species[o.ScientificName] = struct{}{}
+		w.taxa = append(w.taxa, o.ScientificName)
+	}
+	return nil
+}
+
+func (w *Writer) writeRow() error {
+	if _, err := io.WriteString(w.table, strings.Join(w.rowCells, "\t")+"\n"); err != nil {
+		return fmt.Errorf("%s: write %s: %w", componentName, OccurrenceFile, err)
+	}
+	return nil
+}
+
+// Count returns the number of occurrences written so far.
+func (w *Writer) Count() int {
+	return w.count
+}
+
+// Close adds the archive descriptor and dataset metadata and finishes the ZIP
+// file.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	meta, err := w.zw.Create(MetaFile)
+	if err != nil {
+		return fmt.Errorf("%s: create %s: %w", componentName, MetaFile, err)
+	}
+	if err := writeMeta(meta); err != nil {
+		return err
+	}
+
+	eml, err := w.zw.Create(EMLFile)
+	if err != nil {
+		return fmt.Errorf("%s: create %s: %w", componentName, EMLFile, err)
+	}
+	if err := writeEML(eml, w.dataset, w.coverage()); err != nil {
+		return err
+	}
+
+	if err := w.zw.Close(); err != nil {
+		return fmt.Errorf("%s: finish archive: %w", componentName, err)
+	}
+	return nil
+}
+
+// coverage summarises the rows written for the dataset metadata.
+func (w *Writer) coverage() coverage {
+	return coverage{first: w.first, last: w.last, taxa: w.taxa}
+}
@@ -0,0 +1,157 @@
+package darwincore
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readArchive returns the members of a ZIP archive by name.
+func readArchive(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := make(map[string]string, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestWriter_Archive(t *testing.T) {
+	t.Parallel()
+
+	zone := time.FixedZone("EET", 2*60*60)
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Dataset{
+		ID:          "station-1",
+		Title:       "Backyard detections",
+		Description: "Acoustic detections",
+		Creator:     "Backyard",
+		Created:     time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		Latitude:    60.1699,
+		Longitude:   24.9384,
+		HasLocation: true,
+	})
+	require.NoError(t, err)
+
+	occurrences := []Occurrence{
+		{
+			ID:                 "urn:test:1",
+			EventDate:          time.Date(2026, 5, 2, 4, 30, 0, 0, zone),
+			ScientificName:     "Turdus merula",
+			VernacularName:     "Eurasian Blackbird",
+			Latitude:           60.1699,
+			Longitude:          24.9384,
+			HasLocation:        true,
+			Locality:           "North Meadow",
+			Habitat:            "wet\nmeadow",
+			Confidence:         0.91,
+			IdentifiedBy:       "BirdNET 2.4",
+			VerificationStatus: StatusVerified,
+			Remarks:            "garden\tmic",
+		},
+		{
+			ID:                 "urn:test:2",
+			EventDate:          time.Date(2026, 5, 1, 22, 0, 0, 0, zone),
+			ScientificName:     "Strix aluco",
+			Confidence:         0.75,
+			VerificationStatus: StatusUnverified,
+		},
+		{
+			ID:                 "urn:test:3",
+			EventDate:          time.Date(2026, 5, 3, 5, 0, 0, 0, zone),
+			ScientificName:     "Turdus merula",
+			Confidence:         0.8,
+			VerificationStatus: StatusUnverified,
+		},
+	}
+	for i := range occurrences {
+		require.NoError(t, w.Write(&occurrences[i]))
+	}
+	assert.Equal(t, 3, w.Count())
+	require.NoError(t, w.Close())
+	require.Error(t, w.Write(&occurrences[0]), "writing after Close should fail")
+
+	files := readArchive(t, buf.Bytes())
+	require.Contains(t, files, OccurrenceFile)
+	require.Contains(t, files, MetaFile)
+	require.Contains(t, files, EMLFile)
+
+	lines := strings.Split(strings.TrimSuffix(files[OccurrenceFile], "\n"), "\n")
+	require.Len(t, lines, 4, "header plus one row per occurrence")
+	header := strings.Split(lines[0], "\t")
+	assert.Equal(t, "occurrenceID", header[0])
+
+	row := strings.Split(lines[1], "\t")
+	require.Len(t, row, len(header), "tabs inside values must not add columns")
+	field := func(name string) string {
+		for i, h := range header {
+			if h == name {
+				return row[i]
+			}
+		}
+		t.Fatalf("column %s missing", name)
+		return ""
+	}
+	assert.Equal(t, "urn:test:1", field("occurrenceID"))
+	assert.Equal(t, "MachineObservation", field("basisOfRecord"))
+	assert.Equal(t, "2026-05-02T04:30:00+02:00", field("eventDate"))
+	assert.Equal(t, "Turdus merula", field("scientificName"))
+	assert.Equal(t, "60.169900", field("decimalLatitude"))
+	assert.Equal(t, "North Meadow", field("locality"))
+	assert.Equal(t, "wet meadow", field("habitat"))
+	assert.Equal(t, "verified", field("identificationVerificationStatus"))
+	assert.Equal(t, "garden mic", field("occurrenceRemarks"))
+	assert.Equal(t, `{"confidence":0.9100}`, field("dynamicProperties"))
+
+	unlocated := strings.Split(lines[2], "\t")
+	assert.Empty(t, unlocated[6], "occurrence without a location has no latitude")
+
+	var meta metaArchive
+	require.NoError(t, xml.Unmarshal([]byte(files[MetaFile]), &meta))
+	assert.Equal(t, EMLFile, meta.Metadata)
+	assert.Equal(t, OccurrenceFile, meta.Core.Location)
+	assert.Equal(t, `\t`, meta.Core.FieldsTerminatedBy)
+	require.Len(t, meta.Core.Fields, len(header))
+	for i, f := range meta.Core.Fields {
+		assert.Equal(t, i, f.Index)
+		assert.Equal(t, "http://rs.tdwg.org/dwc/terms/"+header[i], f.Term)
+	}
+
+	eml := files[EMLFile]
+	assert.Contains(t, eml, `packageId="station-1"`)
+	assert.Contains(t, eml, "<title>Backyard detections</title>")
+	assert.Contains(t, eml, "<pubDate>2026-06-01</pubDate>")
+	assert.Contains(t, eml, "<calendarDate>2026-05-01</calendarDate>", "temporal coverage starts at the earliest occurrence")
+	assert.Contains(t, eml, "<calendarDate>2026-05-03</calendarDate>")
+	assert.Equal(t, 1, strings.Count(eml, "<taxonRankValue>Turdus merula</taxonRankValue>"), "taxa are listed once")
+	assert.Contains(t, eml, "<northBoundingCoordinate>60.169900</northBoundingCoordinate>")
+	assert.Contains(t, eml, DefaultLicense)
+}
+
+func TestWriter_EmptyArchive(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Dataset{Title: "Empty"})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close(), "Close is idempotent")
+
+	files := readArchive(t, buf.Bytes())
+	assert.Equal(t, 1, strings.Count(files[OccurrenceFile], "\n"), "only the header row")
+	assert.NotContains(t, files[EMLFile], "<coverage>")
+}
@@ -0,0 +1,211 @@
+package darwincore
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	dwcNamespace     = "http://rs.tdwg.org/dwc/terms/"
+	dwcTextNamespace = "http://rs.tdwg.org/dwc/text/"
+	emlNamespace     = "eml://ecoinformatics.org/eml-2.1.1"
+	emlSchema        = "eml://ecoinformatics.org/eml-2.1.1 http://rs.gbif.org/schema/eml-gbif-profile/1.1/eml.xsd"
+	xsiNamespace     = "http://www.w3.org/2001/XMLSchema-instance"
+	emlDateLayout    = "2006-01-02"
+)
+
+// metaArchive is the meta.xml archive descriptor.
+type metaArchive struct {
+	XMLName  xml.Name `xml:"archive"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Metadata string   `xml:"metadata,attr"`
+	Core     metaCore `xml:"core"`
+}
+
+type metaCore struct {
+	Encoding           string      `xml:"encoding,attr"`
+	FieldsTerminatedBy string      `xml:"fieldsTerminatedBy,attr"`
+	LinesTerminatedBy  string      `xml:"linesTerminatedBy,attr"`
+	FieldsEnclosedBy   string      `xml:"fieldsEnclosedBy,attr"`
+	IgnoreHeaderLines  int         `xml:"ignoreHeaderLines,attr"`
+	RowType            string      `xml:"rowType,attr"`
+	Location           string      `xml:"files>location"`
+	ID                 metaIndex   `xml:"id"`
+	Fields             []metaField `xml:"field"`
+}
+
+type metaIndex struct {
+	Index int `xml:"index,attr"`
+}
+
+type metaField struct {
+	Index int    `xml:"index,attr"`
+	Term  string `xml:"term,attr"`
+}
+
+// writeMeta writes the descriptor of the occurrence table. The separators
+// are written as the escape sequences the Darwin Core text guide uses.
+func writeMeta(w io.Writer) error {
+	archive := metaArchive{
+		XMLNS:    dwcTextNamespace,
+		Metadata: EMLFile,
+		Core: metaCore{
+			Encoding:           "UTF-8",
+			FieldsTerminatedBy: `\t`,
+			LinesTerminatedBy:  `\n`,
+			IgnoreHeaderLines:  1,
+			RowType:            dwcNamespace + "Occurrence",
+			Location:           OccurrenceFile,
+		},
+	}
+	for i, col := range columns {
+		archive.Core.Fields = append(archive.Core.Fields, metaField{Index: i, Term: dwcNamespace + col.name})
+	}
+	return writeXML(w, MetaFile, archive)
+}
+
+// emlDocument is the eml.xml dataset metadata, following the GBIF metadata
+// profile.
+type emlDocument struct {
+	XMLName        xml.Name   `xml:"eml:eml"`
+	XMLNSEml       string     `xml:"xmlns:eml,attr"`
+	XMLNSXsi       string     `xml:"xmlns:xsi,attr"`
+	SchemaLocation string     `xml:"xsi:schemaLocation,attr"`
+	PackageID      string     `xml:"packageId,attr"`
+	System         string     `xml:"system,attr"`
+	Scope          string     `xml:"scope,attr"`
+	Lang           string     `xml:"xml:lang,attr"`
+	Dataset        emlDataset `xml:"dataset"`
+}
+
+type emlDataset struct {
+	Title              string       `xml:"title"`
+	Creator            emlParty     `xml:"creator"`
+	MetadataProvider   emlParty     `xml:"metadataProvider"`
+	PubDate            string       `xml:"pubDate"`
+	Language           string       `xml:"language"`
+	Abstract           emlPara      `xml:"abstract"`
+	IntellectualRights emlPara      `xml:"intellectualRights"`
+	Coverage           *emlCoverage `xml:"coverage,omitempty"`
+	Contact            emlParty     `xml:"contact"`
+}
+
+type emlParty struct {
+	OrganizationName string `xml:"organizationName"`
+}
+
+type emlPara struct {
+	Para string `xml:"para"`
+}
+
+type emlCoverage struct {
+	Geographic *emlGeographic `xml:"geographicCoverage,omitempty"`
+	Temporal   *emlTemporal   `xml:"temporalCoverage,omitempty"`
+	Taxonomic  *emlTaxonomic  `xml:"taxonomicCoverage,omitempty"`
+}
+
+type emlGeographic struct {
+	Description string `xml:"geographicDescription"`
+	West        string `xml:"boundingCoordinates>westBoundingCoordinate"`
+	East        string `xml:"boundingCoordinates>eastBoundingCoordinate"`
+	North       string `xml:"boundingCoordinates>northBoundingCoordinate"`
+	South       string `xml:"boundingCoordinates>southBoundingCoordinate"`
+}
+
+type emlTemporal struct {
+	Begin string `xml:"rangeOfDates>beginDate>calendarDate"`
+	End   string `xml:"rangeOfDates>endDate>calendarDate"`
+}
+
+type emlTaxonomic struct {
+	Taxa []emlTaxon `xml:"taxonomicClassification"`
+}
+
+type emlTaxon struct {
+	Value string `xml:"taxonRankValue"`
+}
+
+// coverage is what the written occurrences span.
+type coverage struct {
+	first, last time.Time
+	taxa        []string
+}
+
+// writeEML writes the dataset metadata.
+func writeEML(w io.Writer, dataset Dataset, cov coverage) error {
+	created := dataset.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	license := dataset.License
+	if license == "" {
+		license = DefaultLicense
+	}
+	packageID := dataset.ID
+	if packageID == "" {
+		packageID = "birdnet-go-" + strconv.FormatInt(created.Unix(), 10)
+	}
+	party := emlParty{OrganizationName: dataset.Creator}
+
+	doc := emlDocument{
+		XMLNSEml:       emlNamespace,
+		XMLNSXsi:       xsiNamespace,
+		SchemaLocation: emlSchema,
+		PackageID:      packageID,
+		System:         "http://gbif.org",
+		Scope:          "system",
+		Lang:           "en",
+		Dataset: emlDataset{
+			Title:              dataset.Title,
+			Creator:            party,
+			MetadataProvider:   party,
+			PubDate:            created.Format(emlDateLayout),
+			Language:           "en",
+			Abstract:           emlPara{Para: dataset.Description},
+			IntellectualRights: emlPara{Para: "This work is licensed under " + license + "."},
+			Contact:            party,
+		},
+	}
+
+	var c emlCoverage
+	if dataset.HasLocation {
+		lat := formatCoordinate(true, dataset.Latitude)
+		lon := formatCoordinate(true, dataset.Longitude)
+		c.Geographic = &emlGeographic{
+			Description: "Recording station",
+			West:        lon, East: lon, North: lat, South: lat,
+		}
+	}
+	if !cov.first.IsZero() {
+		c.Temporal = &emlTemporal{Begin: cov.first.Format(emlDateLayout), End: cov.last.Format(emlDateLayout)}
+	}
+	if len(cov.taxa) > 0 {
+		c.Taxonomic = &emlTaxonomic{Taxa: make([]emlTaxon, len(cov.taxa))}
+		for i, name := range cov.taxa {
+			c.Taxonomic.Taxa[i] = emlTaxon{Value: name}
+		}
+	}
+	if c.Geographic != nil || c.Temporal != nil || c.Taxonomic != nil {
+		doc.Dataset.Coverage = &c
+	}
+	return writeXML(w, EMLFile, doc)
+}
+
+func writeXML(w io.Writer, name string, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("%s: write %s: %w", componentName, name, err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("%s: write %s: %w", componentName, name, err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("%s: write %s: %w", componentName, name, err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
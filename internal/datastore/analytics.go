@@ -136,6 +136,8 @@ type AudioSourceSummary struct {
 	NodeName string
 	// SourceType is the source kind (e.g. "rtsp", "alsa", "file"), used for anonymized labelling.
 	SourceType string
+	// Site is the source's deployment site, empty when unset.
+	Site string
 	// Count is the number of (false-positive-excluded) detections from this source in the range.
 	Count int
 }
@@ -658,6 +660,27 @@ func (ds *DataStore) GetDetectionTrends(ctx context.Context, period string, limi
 				Context("limit", fmt.Sprintf("%d", limit)).
 				Build()
 		}
+	case DialectPostgres:
+		// notes.date is text, so compare against a formatted date string
+		startDate = fmt.Sprintf("to_char(CURRENT_DATE - INTERVAL '%s', 'YYYY-MM-DD')", interval)
+		query := fmt.Sprintf(`
+			SELECT date, COUNT(*) as count
+			FROM notes
+			WHERE date >= %s
+			GROUP BY date
+			ORDER BY date DESC
+			LIMIT ?
+		`, startDate)
+
+		if err := ds.DB.WithContext(ctx).Raw(query, limit).Scan(&trends).Error; err != nil {
+			return nil, errors.New(err).
+				Component("datastore").
+				Category(errors.CategoryDatabase).
+				Context("operation", "get_detection_trends_postgres").
+				Context("period", period).
+				Context("limit", fmt.Sprintf("%d", limit)).
+				Build()
+		}
 	default:
 		// Safely get database type for error context
 		dialectName := DialectUnknown
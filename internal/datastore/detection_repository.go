@@ -189,9 +189,9 @@ func (r *detectionRepository) GetHourly(ctx context.Context, date, hour string,
 	return results, total, nil
 }
 
-// Lock sets a lock on a detection.
-func (r *detectionRepository) Lock(ctx context.Context, id string) error {
-	if err := r.store.LockNote(id); err != nil {
+// Lock sets a lock on a detection on behalf of lockedBy.
+func (r *detectionRepository) Lock(ctx context.Context, id, lockedBy string) error {
+	if err := r.store.LockNote(id, lockedBy); err != nil {
 		return fmt.Errorf("failed to lock detection %s: %w", id, err)
 	}
 	return nil
@@ -357,6 +357,7 @@ func NoteFromResult(result *detection.Result) Note {
 			ID:          result.AudioSource.ID,
 			SafeString:  result.AudioSource.SafeString,
 			DisplayName: result.AudioSource.DisplayName,
+			Deployment:  result.AudioSource.Deployment,
 		},
 		Unlikely:   result.Unlikely,
 		Occurrence: result.Occurrence,
@@ -409,6 +410,7 @@ func (r *detectionRepository) noteToResult(note *Note) (*detection.Result, error
 			DisplayName: note.Source.DisplayName,
 			SafeString:  note.Source.SafeString,
 			Type:        detection.DetermineSourceType(note.Source.SafeString),
+			Deployment:  note.Source.Deployment,
 		},
 		BeginTime: note.BeginTime,
 		EndTime:   note.EndTime,
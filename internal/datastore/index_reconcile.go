@@ -49,7 +49,7 @@ import (
 
 // dbUniqueIndex describes a unique index discovered on the live database.
 // Columns are ordered by the index's column sequence (SEQ_IN_INDEX in MySQL,
-// seqno in SQLite PRAGMA index_info).
+// seqno in SQLite PRAGMA index_info, the indkey position in PostgreSQL).
 type dbUniqueIndex struct {
 	Name    string
 	Table   string
@@ -165,11 +165,12 @@ func indexIsLegacyPrecursor(live dbUniqueIndex, declared []entityUniqueIndex) bo
 // non-fatal pattern as cleanupLegacySchemaContamination).
 //
 // dbType is case-insensitive ("MySQL", "SQLite", ...). dbName is required
-// for MySQL (used as TABLE_SCHEMA); pass "" for SQLite.
+// for MySQL (used as TABLE_SCHEMA); pass "" for SQLite. PostgreSQL reads the
+// current schema and ignores dbName.
 func reconcileLegacyUniqueIndexes(db *gorm.DB, dbType, dbName string, entities []any) error {
 	dialect := strings.ToLower(dbType)
 	switch dialect {
-	case DialectSQLite:
+	case DialectSQLite, DialectPostgres:
 		// ok, no dbName needed
 	case DialectMySQL:
 		if dbName == "" {
@@ -205,6 +206,8 @@ func reconcileLegacyUniqueIndexes(db *gorm.DB, dbType, dbName string, entities [
 			live, err = liveUniqueIndexesSQLite(db, tableName)
 		case DialectMySQL:
 			live, err = liveUniqueIndexesMySQL(db, dbName, tableName)
+		case DialectPostgres:
+			live, err = liveUniqueIndexesPostgres(db, tableName)
 		}
 		if err != nil {
 			// Live-index introspection failure for one table should not
@@ -339,9 +342,48 @@ func liveUniqueIndexesMySQL(db *gorm.DB, dbName, tableName string) ([]dbUniqueIn
 	return result, nil
 }
 
+// postgresUniqueIndexesQuery lists the unique, non-primary indexes of a table
+// in the current schema, one row per column in index order. Indexes backing
+// a table constraint are skipped: they can only be removed with ALTER TABLE
+// and GORM never creates them for uniqueIndex tags.
+const postgresUniqueIndexesQuery = `SELECT ci.relname AS index_name, a.attname AS column_name
+	FROM pg_index i
+	JOIN pg_class ct ON ct.oid = i.indrelid
+	JOIN pg_class ci ON ci.oid = i.indexrelid
+	JOIN pg_namespace n ON n.oid = ct.relnamespace
+	JOIN pg_attribute a ON a.attrelid = ct.oid AND a.attnum = ANY(i.indkey)
+	LEFT JOIN pg_constraint con ON con.conindid = i.indexrelid
+	WHERE n.nspname = current_schema() AND ct.relname = ?
+	  AND i.indisunique AND NOT i.indisprimary AND con.oid IS NULL
+	ORDER BY ci.relname, array_position(i.indkey::int2[], a.attnum)`
+
+// liveUniqueIndexesPostgres returns the unique indexes currently present on
+// the given table in PostgreSQL, excluding the primary key.
+func liveUniqueIndexesPostgres(db *gorm.DB, tableName string) ([]dbUniqueIndex, error) {
+	type row struct {
+		IndexName  string `gorm:"column:index_name"`
+		ColumnName string `gorm:"column:column_name"`
+	}
+	var rows []row
+	if err := db.Raw(postgresUniqueIndexesQuery, tableName).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("pg_index for %s: %w", tableName, err)
+	}
+
+	// Rows arrive grouped by index name, so a slice keeps the order stable.
+	var result []dbUniqueIndex
+	for _, r := range rows {
+		if n := len(result); n == 0 || result[n-1].Name != r.IndexName {
+			result = append(result, dbUniqueIndex{Name: r.IndexName, Table: tableName})
+		}
+		last := &result[len(result)-1]
+		last.Columns = append(last.Columns, r.ColumnName)
+	}
+	return result, nil
+}
+
 // dropUniqueIndex issues a dialect-appropriate DROP INDEX for the given live
 // index. Identifier quoting uses backticks (MySQL) and double quotes
-// (SQLite); GORM's quoted identifier helpers are not used here because we
+// (SQLite, PostgreSQL); GORM's quoted identifier helpers are not used here because we
 // already hold resolved identifier strings from the introspection queries.
 func dropUniqueIndex(db *gorm.DB, dialect string, idx dbUniqueIndex) error {
 	switch dialect {
@@ -357,11 +399,11 @@ func dropUniqueIndex(db *gorm.DB, dialect string, idx dbUniqueIndex) error {
 			return nil
 		}
 		return err
-	case DialectSQLite:
+	case DialectSQLite, DialectPostgres:
 		if !isSafeIdentifier(idx.Name) {
 			return fmt.Errorf("unsafe identifier refusing drop: index=%q", idx.Name)
 		}
-		// SQLite's IF EXISTS already handles the concurrent-drop race.
+		// IF EXISTS already handles the concurrent-drop race.
 		return db.Exec(fmt.Sprintf("DROP INDEX IF EXISTS %q", idx.Name)).Error
 	default:
 		return fmt.Errorf("unsupported dialect: %s", dialect)
@@ -42,9 +42,10 @@ const sunriseSetWindowMinutes = 30
 // Database dialect constants.
 // NOTE: These must be lowercase to match GORM's dialector.Name() output.
 const (
-	DialectUnknown = "unknown"
-	DialectSQLite  = "sqlite"
-	DialectMySQL   = "mysql"
+	DialectUnknown  = "unknown"
+	DialectSQLite   = "sqlite"
+	DialectMySQL    = "mysql"
+	DialectPostgres = "postgres"
 )
 
 // Time-of-day filter constants for detection queries.
@@ -171,7 +172,7 @@ type Interface interface {
 	CountSpeciesDetections(species, date, hour string, duration int) (int64, error)
 	Transaction(fc func(tx *gorm.DB) error) error
 	// Lock management methods
-	LockNote(noteID string) error
+	LockNote(noteID, lockedBy string) error
 	UnlockNote(noteID string) error
 	GetNoteLock(noteID string) (*NoteLock, error)
 	IsNoteLocked(noteID string) (bool, error)
@@ -297,11 +298,11 @@ type AppEvent struct {
 
 // DatabaseStats contains basic runtime statistics about the database
 type DatabaseStats struct {
-	Type            string `json:"type"`             // "sqlite" or "mysql"
+	Type            string `json:"type"`             // "sqlite", "mysql" or "postgres"
 	SizeBytes       int64  `json:"size_bytes"`       // Database size in bytes
 	TotalDetections int64  `json:"total_detections"` // Total number of detection records
 	Connected       bool   `json:"connected"`        // Whether database is connected
-	Location        string `json:"location"`         // File path for SQLite, host:port/database for MySQL
+	Location        string `json:"location"`         // File path for SQLite, host:port/database for MySQL and PostgreSQL
 }
 
 // DataStore implements StoreInterface using a GORM database.
@@ -365,6 +366,13 @@ func New(settings *conf.Settings) Interface {
 				SunCalc: sunCalc,
 			},
 		}
+	case settings.Output.Postgres.Enabled:
+		return &PostgresStore{
+			Settings: settings,
+			DataStore: DataStore{
+				SunCalc: sunCalc,
+			},
+		}
 	default:
 		// No database explicitly enabled — default to SQLite
 		return &SQLiteStore{
@@ -775,12 +783,14 @@ func (ds *DataStore) GetHourFormat() string {
 		return ""
 	}
 
-	// Handling for supported databases: SQLite and MySQL
+	// Handling for supported databases: SQLite, MySQL and PostgreSQL
 	switch strings.ToLower(dialector.Name()) {
 	case DialectSQLite:
 		return "strftime('%H', time)"
 	case DialectMySQL:
 		return "TIME_FORMAT(time, '%H')"
+	case DialectPostgres:
+		return "substr(time, 1, 2)"
 	default:
 		// Log or handle unsupported database types
 		return ""
@@ -794,6 +804,7 @@ func (ds *DataStore) GetHourFormat() string {
 // Supported formats:
 //   - SQLite: datetime(dateCol || ' ' || timeCol)
 //   - MySQL: STR_TO_DATE(CONCAT(dateCol, ' ', timeCol), '%Y-%m-%d %H:%i:%s')
+//   - PostgreSQL: CAST(dateCol || ' ' || timeCol AS timestamp)
 //
 // The returned fragment can be used directly in SQL SELECT statements for MIN/MAX datetime operations.
 func (ds *DataStore) GetDateTimeExpr(dateCol, timeCol string) string {
@@ -802,12 +813,14 @@ func (ds *DataStore) GetDateTimeExpr(dateCol, timeCol string) string {
 		return ""
 	}
 
-	// Handling for supported databases: SQLite and MySQL
+	// Handling for supported databases: SQLite, MySQL and PostgreSQL
 	switch strings.ToLower(dialector.Name()) {
 	case DialectSQLite:
 		return fmt.Sprintf("datetime(%s || ' ' || %s)", dateCol, timeCol)
 	case DialectMySQL:
 		return fmt.Sprintf("STR_TO_DATE(CONCAT(%s, ' ', %s), '%%Y-%%m-%%d %%H:%%i:%%s')", dateCol, timeCol)
+	case DialectPostgres:
+		return fmt.Sprintf("CAST(%s || ' ' || %s AS timestamp)", dateCol, timeCol)
 	default:
 		// Log or handle unsupported database types
 		return ""
@@ -821,6 +834,7 @@ func (ds *DataStore) GetDateTimeExpr(dateCol, timeCol string) string {
 // Supported formats:
 //   - SQLite: datetime(date || ' ' || time)
 //   - MySQL: STR_TO_DATE(CONCAT(date, ' ', time), '%Y-%m-%d %H:%i:%s')
+//   - PostgreSQL: CAST(date || ' ' || time AS timestamp)
 //
 // The returned fragment can be used directly in SQL SELECT statements for MIN/MAX datetime operations.
 func (ds *DataStore) GetDateTimeFormat() string {
@@ -833,6 +847,7 @@ func (ds *DataStore) GetDateTimeFormat() string {
 // Supported formats:
 //   - SQLite: date(column_name)
 //   - MySQL: DATE(column_name)
+//   - PostgreSQL: CAST(column_name AS date)
 //
 // The returned fragment can be used directly in SQL WHERE clauses for date comparisons.
 func (ds *DataStore) GetDateFormat(columnName string) string {
@@ -841,12 +856,14 @@ func (ds *DataStore) GetDateFormat(columnName string) string {
 		return ""
 	}
 
-	// Handling for supported databases: SQLite and MySQL
+	// Handling for supported databases: SQLite, MySQL and PostgreSQL
 	switch strings.ToLower(dialector.Name()) {
 	case DialectSQLite:
 		return fmt.Sprintf("date(%s)", columnName)
 	case DialectMySQL:
 		return fmt.Sprintf("DATE(%s)", columnName)
+	case DialectPostgres:
+		return fmt.Sprintf("CAST(%s AS date)", columnName)
 	default:
 		// Log or handle unsupported database types
 		return ""
@@ -1134,6 +1151,9 @@ func (ds *DataStore) SaveHourlyWeather(hourlyWeather *HourlyWeather) error {
 		switch strings.ToLower(ds.Dialector().Name()) {
 		case DialectMySQL:
 			whereClause = "UNIX_TIMESTAMP(time) = UNIX_TIMESTAMP(?)"
+		case DialectPostgres:
+			// timestamptz compares instants regardless of the stored offset
+			whereClause = "time = ?"
 		default: // SQLite
 			whereClause = "strftime('%s', time) = strftime('%s', ?)"
 		}
@@ -1207,6 +1227,8 @@ func (ds *DataStore) GetHourlyWeatherInLocation(date string, loc *time.Location)
 	switch strings.ToLower(ds.Dialector().Name()) {
 	case DialectMySQL:
 		whereClause = "UNIX_TIMESTAMP(time) >= UNIX_TIMESTAMP(?) AND UNIX_TIMESTAMP(time) < UNIX_TIMESTAMP(?)"
+	case DialectPostgres:
+		whereClause = "time >= ? AND time < ?"
 	default: // SQLite
 		whereClause = "strftime('%s', time) >= strftime('%s', ?) AND strftime('%s', time) < strftime('%s', ?)"
 	}
@@ -1780,8 +1802,9 @@ func (ds *DataStore) IsNoteLocked(noteID string) (bool, error) {
 	return count > 0, nil
 }
 
-// LockNote creates or updates a lock for a note
-func (ds *DataStore) LockNote(noteID string) error {
+// LockNote creates or updates a lock for a note. lockedBy records the user
+// that placed the lock and may be empty for system locks.
+func (ds *DataStore) LockNote(noteID, lockedBy string) error {
 	id, err := parseEntityID(noteID, "note")
 	if err != nil {
 		return err
@@ -1791,6 +1814,7 @@ func (ds *DataStore) LockNote(noteID string) error {
 		lock := &NoteLock{
 			NoteID:   id,
 			LockedAt: time.Now(),
+			LockedBy: lockedBy,
 		}
 
 		result := ds.DB.Where("note_id = ?", id).
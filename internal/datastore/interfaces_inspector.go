@@ -6,13 +6,14 @@ import "github.com/tphakala/birdnet-go/internal/datastore/dbstats"
 // and detection rate histograms for the database dashboard. This interface is
 // separate from StoreInterface to keep concerns clean.
 //
-// Implemented by SQLiteStore and MySQLStore. The API handler type-asserts
+// Implemented by SQLiteStore, MySQLStore and PostgresStore. The API handler type-asserts
 // the store to DatabaseInspector; if the store doesn't implement it, the
 // dashboard sections return null.
 type DatabaseInspector interface {
 	// GetEngineDetails returns engine-specific metadata.
 	// For SQLite stores, EngineDetails.SQLite is populated.
 	// For MySQL stores, EngineDetails.MySQL is populated.
+	// For PostgreSQL stores, EngineDetails.Postgres is populated.
 	GetEngineDetails() (EngineDetails, error)
 
 	// GetTableStats returns per-table row counts, sizes, and usage percentages.
@@ -26,11 +27,12 @@ type DatabaseInspector interface {
 	GetDetectionRateDaily(days int) ([]DailyCount, error)
 }
 
-// EngineDetails holds engine-specific metadata. Exactly one of SQLite or MySQL
-// is populated depending on the active database engine.
+// EngineDetails holds engine-specific metadata. Exactly one of SQLite, MySQL
+// or Postgres is populated depending on the active database engine.
 type EngineDetails struct {
-	SQLite *SQLiteDetails `json:"sqlite,omitempty"`
-	MySQL  *MySQLDetails  `json:"mysql,omitempty"`
+	SQLite   *SQLiteDetails   `json:"sqlite,omitempty"`
+	MySQL    *MySQLDetails    `json:"mysql,omitempty"`
+	Postgres *PostgresDetails `json:"postgres,omitempty"`
 }
 
 // SQLiteDetails contains SQLite-specific engine metadata collected from
@@ -73,13 +75,33 @@ type MySQLDetails struct {
 	TableLocksImmediate int64   `json:"table_locks_immediate"`
 }
 
+// PostgresDetails contains PostgreSQL-specific engine metadata collected from
+// sql.DB.Stats(), pg_stat_database and server settings.
+type PostgresDetails struct {
+	EngineVersion string `json:"engine_version"` // SHOW server_version
+
+	// Connection Pool (from sql.DB.Stats() + pg_stat_database)
+	ActiveConnections int `json:"active_connections"`
+	IdleConnections   int `json:"idle_connections"`
+	MaxConnections    int `json:"max_connections"`
+	Backends          int `json:"backends"`         // pg_stat_database.numbackends
+	ServerMaxConns    int `json:"server_max_conns"` // SHOW max_connections
+
+	// Transactions & Cache (from pg_stat_database)
+	CacheHitRate float64 `json:"cache_hit_rate"` // blks_hit / (blks_hit + blks_read), percentage
+	Commits      int64   `json:"commits"`        // xact_commit
+	Rollbacks    int64   `json:"rollbacks"`      // xact_rollback
+	Deadlocks    int64   `json:"deadlocks"`      // deadlocks
+	TempBytes    int64   `json:"temp_bytes"`     // temp_bytes
+}
+
 // TableStats describes a single database table's size and row count.
 type TableStats struct {
 	Name      string  `json:"name"`
 	RowCount  int64   `json:"row_count"`
 	SizeBytes int64   `json:"size_bytes"`
 	UsagePct  float64 `json:"usage_pct"` // percentage of total DB size
-	Engine    string  `json:"engine"`    // MySQL only; empty for SQLite
+	Engine    string  `json:"engine"`    // MySQL only; empty for SQLite and PostgreSQL
 }
 
 // PerformanceStats contains current database performance metrics derived from
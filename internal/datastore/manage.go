@@ -284,6 +284,40 @@ func hasV2ImageCacheContaminationMySQL(db *gorm.DB, dbName string) bool {
 	return count > 0
 }
 
+// hasCorrectImageCacheIndexPostgres checks if the PostgreSQL database has the
+// correct composite unique index on the image_caches table.
+func hasCorrectImageCacheIndexPostgres(db *gorm.DB, debug bool) (bool, error) {
+	targetIndexName := "idx_imagecache_provider_species"
+
+	if !db.Migrator().HasTable(&ImageCache{}) {
+		return false, nil // Table doesn't exist, AutoMigrate will create it
+	}
+
+	indexes, err := liveUniqueIndexesPostgres(db, "image_caches")
+	if err != nil {
+		return false, err
+	}
+
+	foundCorrectIndex := false
+	foundIncorrectIndex := false
+	for _, idx := range indexes {
+		switch {
+		case idx.Name == targetIndexName:
+			foundCorrectIndex = slices.Equal(idx.Columns, []string{"provider_name", "scientific_name"})
+		case len(idx.Columns) == 1 && idx.Columns[0] == "scientific_name":
+			foundIncorrectIndex = true
+		}
+	}
+
+	if debug {
+		GetLogger().Debug("PostgreSQL schema check result",
+			logger.Bool("correct_index_found", foundCorrectIndex),
+			logger.Bool("incorrect_index_found", foundIncorrectIndex))
+	}
+
+	return foundCorrectIndex && !foundIncorrectIndex, nil
+}
+
 // hasV2ImageCacheContaminationSQLite checks if the legacy image_caches table contains
 // a label_id column from the v2 schema. See hasV2ImageCacheContaminationMySQL for context.
 func hasV2ImageCacheContaminationSQLite(db *gorm.DB) bool {
@@ -355,7 +389,7 @@ func performAutoMigration(db *gorm.DB, debug bool, dbType, dbName string) error
 
 // validateAndFixSchema checks and fixes the database schema if needed.
 // For MySQL, dbName is the database name used for information_schema queries.
-// For SQLite, dbName is unused.
+// For SQLite and PostgreSQL, dbName is unused.
 func validateAndFixSchema(db *gorm.DB, dbType, dbName string, debug bool, log logger.Logger) error {
 	migrator := db.Migrator()
 	var schemaCorrect bool
@@ -401,6 +435,21 @@ func validateAndFixSchema(db *gorm.DB, dbType, dbName string, debug bool, log lo
 				GetLogger().Warn("Detected v2 column contamination (label_id) in legacy image_caches table, will recreate")
 			}
 		}
+	case DialectPostgres:
+		schemaCorrect, err = hasCorrectImageCacheIndexPostgres(db, debug)
+		if err != nil {
+			enhancedErr := criticalError(err, "schema_validation", "schema_integrity_check_failed",
+				"db_type", dbType,
+				"table", "image_caches",
+				"action", "database_schema_validation")
+
+			GetLogger().Error("Schema validation failed", logger.Error(enhancedErr))
+			return enhancedErr
+		}
+		if schemaCorrect && db.Migrator().HasColumn(&ImageCache{}, "label_id") {
+			schemaCorrect = false
+			GetLogger().Warn("Detected v2 column contamination (label_id) in legacy image_caches table, will recreate")
+		}
 	default:
 		GetLogger().Warn("Unsupported database type for image_caches schema check. Assuming schema is correct.",
 			logger.String("db_type", dbType))
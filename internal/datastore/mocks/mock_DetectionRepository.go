@@ -835,17 +835,17 @@ func (_c *MockDetectionRepository_IsLocked_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
-// Lock provides a mock function with given fields: ctx, id
-func (_m *MockDetectionRepository) Lock(ctx context.Context, id string) error {
-	ret := _m.Called(ctx, id)
+// Lock provides a mock function with given fields: ctx, id, lockedBy
+func (_m *MockDetectionRepository) Lock(ctx context.Context, id string, lockedBy string) error {
+	ret := _m.Called(ctx, id, lockedBy)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Lock")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, lockedBy)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -861,13 +861,14 @@ type MockDetectionRepository_Lock_Call struct {
 // Lock is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id string
-func (_e *MockDetectionRepository_Expecter) Lock(ctx interface{}, id interface{}) *MockDetectionRepository_Lock_Call {
-	return &MockDetectionRepository_Lock_Call{Call: _e.mock.On("Lock", ctx, id)}
+//   - lockedBy string
+func (_e *MockDetectionRepository_Expecter) Lock(ctx interface{}, id interface{}, lockedBy interface{}) *MockDetectionRepository_Lock_Call {
+	return &MockDetectionRepository_Lock_Call{Call: _e.mock.On("Lock", ctx, id, lockedBy)}
 }
 
-func (_c *MockDetectionRepository_Lock_Call) Run(run func(ctx context.Context, id string)) *MockDetectionRepository_Lock_Call {
+func (_c *MockDetectionRepository_Lock_Call) Run(run func(ctx context.Context, id string, lockedBy string)) *MockDetectionRepository_Lock_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
@@ -877,7 +878,7 @@ func (_c *MockDetectionRepository_Lock_Call) Return(_a0 error) *MockDetectionRep
 	return _c
 }
 
-func (_c *MockDetectionRepository_Lock_Call) RunAndReturn(run func(context.Context, string) error) *MockDetectionRepository_Lock_Call {
+func (_c *MockDetectionRepository_Lock_Call) RunAndReturn(run func(context.Context, string, string) error) *MockDetectionRepository_Lock_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -4412,17 +4412,17 @@ func (_c *MockInterface_LatestHourlyWeather_Call) RunAndReturn(run func() (*data
 	return _c
 }
 
-// LockNote provides a mock function with given fields: noteID
-func (_m *MockInterface) LockNote(noteID string) error {
-	ret := _m.Called(noteID)
+// LockNote provides a mock function with given fields: noteID, lockedBy
+func (_m *MockInterface) LockNote(noteID string, lockedBy string) error {
+	ret := _m.Called(noteID, lockedBy)
 
 	if len(ret) == 0 {
 		panic("no return value specified for LockNote")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(noteID)
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(noteID, lockedBy)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -4437,13 +4437,14 @@ type MockInterface_LockNote_Call struct {
 
 // LockNote is a helper method to define mock.On call
 //   - noteID string
-func (_e *MockInterface_Expecter) LockNote(noteID interface{}) *MockInterface_LockNote_Call {
-	return &MockInterface_LockNote_Call{Call: _e.mock.On("LockNote", noteID)}
+//   - lockedBy string
+func (_e *MockInterface_Expecter) LockNote(noteID interface{}, lockedBy interface{}) *MockInterface_LockNote_Call {
+	return &MockInterface_LockNote_Call{Call: _e.mock.On("LockNote", noteID, lockedBy)}
 }
 
-func (_c *MockInterface_LockNote_Call) Run(run func(noteID string)) *MockInterface_LockNote_Call {
+func (_c *MockInterface_LockNote_Call) Run(run func(noteID string, lockedBy string)) *MockInterface_LockNote_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(string), args[1].(string))
 	})
 	return _c
 }
@@ -4453,7 +4454,7 @@ func (_c *MockInterface_LockNote_Call) Return(_a0 error) *MockInterface_LockNote
 	return _c
 }
 
-func (_c *MockInterface_LockNote_Call) RunAndReturn(run func(string) error) *MockInterface_LockNote_Call {
+func (_c *MockInterface_LockNote_Call) RunAndReturn(run func(string, string) error) *MockInterface_LockNote_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -4,6 +4,7 @@ package datastore
 import (
 	"time"
 
+	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/detection"
 )
 
@@ -13,6 +14,10 @@ type AudioSource struct {
 	ID          string `json:"id"`          // Source ID for buffer operations (e.g., "rtsp_87b89761")
 	SafeString  string `json:"safeString"`  // Sanitized connection string for logging (credentials removed)
 	DisplayName string `json:"displayName"` // User-friendly name for UI display
+
+	// Deployment is the source's deployment metadata, persisted by the v2
+	// datastore on the AudioSource entity. Nil when the source has none.
+	Deployment *conf.DeploymentConfig `json:"deployment,omitempty"`
 }
 
 // Note represents a single observation data point
@@ -93,11 +98,12 @@ func (r Results) Copy() Results {
 // NoteReview represents the review status of a Note
 // GORM will automatically create table name as 'note_reviews'
 type NoteReview struct {
-	ID        uint      `gorm:"primaryKey"`
-	NoteID    uint      `gorm:"uniqueIndex;not null;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;foreignKey:NoteID;references:ID"` // Foreign key to associate with Note
-	Verified  string    `gorm:"type:varchar(20)"`                                                                                  // Values: "correct", "false_positive"
-	CreatedAt time.Time `gorm:"index"`                                                                                             // When the review was created
-	UpdatedAt time.Time // When the review was last updated
+	ID         uint      `gorm:"primaryKey"`
+	NoteID     uint      `gorm:"uniqueIndex;not null;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;foreignKey:NoteID;references:ID"` // Foreign key to associate with Note
+	Verified   string    `gorm:"type:varchar(20)"`                                                                                  // Values: "correct", "false_positive"
+	ReviewedBy string    `gorm:"type:varchar(100)"`                                                                                 // Username of the reviewer, empty for legacy reviews
+	CreatedAt  time.Time `gorm:"index"`                                                                                             // When the review was created
+	UpdatedAt  time.Time // When the review was last updated
 }
 
 // NoteComment represents user comments on a detection
@@ -116,6 +122,7 @@ type NoteLock struct {
 	ID       uint      `gorm:"primaryKey"`
 	NoteID   uint      `gorm:"uniqueIndex;not null;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;foreignKey:NoteID;references:ID"` // Foreign key to associate with Note, with unique constraint
 	LockedAt time.Time `gorm:"index;not null"`                                                                                    // When the note was locked
+	LockedBy string    `gorm:"type:varchar(100)"`                                                                                 // Username that locked the note, empty for legacy locks
 }
 
 // DailyEvents represents the daily weather data that doesn't change throughout the day
@@ -150,13 +157,14 @@ type HourlyWeather struct {
 	WeatherDesc   string
 	WeatherIcon   string
 
-	// Precipitation and PrecipitationType are transport-only fields carried
-	// across the datastore.Interface boundary into the v2only datastore, which
-	// persists them on the v2 hourly_weathers schema. They are tagged gorm:"-"
-	// so the deprecated legacy datastore schema stays untouched: the legacy
-	// GORM model neither migrates nor reads/writes these columns.
+	// Precipitation, PrecipitationType and SolarRadiation are transport-only
+	// fields carried across the datastore.Interface boundary into the v2only
+	// datastore, which persists them on the v2 hourly_weathers schema. They are
+	// tagged gorm:"-" so the deprecated legacy datastore schema stays untouched:
+	// the legacy GORM model neither migrates nor reads/writes these columns.
 	Precipitation     float64 `gorm:"-"` // Precipitation amount in mm
 	PrecipitationType string  `gorm:"-"` // "rain", "snow", "sleet", or ""
+	SolarRadiation    float64 `gorm:"-"` // Solar radiation in W/m², 0 when not measured
 }
 
 // ImageCache represents cached image metadata for species
@@ -149,6 +149,14 @@ func (ds *DataStore) getDatabaseSize(ctx context.Context) (int64, error) {
 		return size, nil
 	}
 
+	// PostgreSQL-specific query
+	if strings.ToLower(ds.DB.Name()) == DialectPostgres {
+		if err := ds.DB.WithContext(ctx).Raw("SELECT pg_database_size(current_database())").Scan(&size).Error; err != nil {
+			return 0, fmt.Errorf("failed to get PostgreSQL database size: %w", err)
+		}
+		return size, nil
+	}
+
 	return 0, fmt.Errorf("unsupported database type: %s", ds.DB.Name())
 }
 
@@ -0,0 +1,265 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/dbstats"
+	"github.com/tphakala/birdnet-go/internal/detection"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// PostgreSQL connection pool constants and the DSN builder are defined in
+// postgres_pool.go and shared with the v2 store.
+
+// PostgresStore implements DataStore for PostgreSQL
+type PostgresStore struct {
+	DataStore
+	Settings *conf.Settings
+}
+
+// Open sets up the PostgreSQL database connection
+func (store *PostgresStore) Open() (retErr error) {
+	pg := store.Settings.Output.Postgres
+	GetLogger().Info("Opening PostgreSQL database connection",
+		logger.String("location", PostgresLocation(store.Settings)),
+		logger.String("username", pg.Username),
+		logger.String("sslmode", pg.SSLMode))
+
+	// Configure GORM logger with metrics if available
+	// Use 1 second threshold to accommodate migration batch queries.
+	var gormLogger gormlogger.Interface
+	if store.Settings.Debug {
+		gormLogger = NewGormLogger(1*time.Second, gormlogger.Info, store.metrics)
+	} else {
+		gormLogger = NewGormLogger(1*time.Second, gormlogger.Warn, store.metrics)
+	}
+
+	db, err := gorm.Open(postgres.Open(PostgresDSN(store.Settings, "")), &gorm.Config{Logger: gormLogger})
+	if err != nil {
+		GetLogger().Error("Failed to open PostgreSQL database", logger.Error(err))
+		return errors.New(err).
+			Component("datastore").
+			Category(errors.CategoryDatabase).
+			Context("operation", "postgres_open").
+			Build()
+	}
+
+	// Configure the connection pool. Like MySQL, PostgreSQL handles
+	// concurrent connections natively; the limits keep the store within the
+	// server's max_connections budget.
+	sqlDB, err := db.DB()
+	if err != nil {
+		return errors.New(err).
+			Component("datastore").
+			Category(errors.CategoryDatabase).
+			Context("operation", "get_underlying_sqldb").
+			Build()
+	}
+	sqlDB.SetMaxOpenConns(PostgresMaxOpenConns)
+	sqlDB.SetMaxIdleConns(PostgresMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(PostgresConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(PostgresConnMaxIdleTime)
+
+	// Clean up connection pool if any subsequent initialization step fails.
+	defer func() {
+		if retErr != nil {
+			if closeErr := sqlDB.Close(); closeErr != nil {
+				GetLogger().Warn("Failed to close DB pool after init error", logger.Error(closeErr))
+			}
+			store.DB = nil
+		}
+	}()
+
+	store.DB = db
+
+	// Initialize atomic counters for query latency tracking and register GORM callbacks
+	store.dbCounters = &dbstats.Counters{}
+	dbstats.RegisterCallbacks(db, store.dbCounters)
+
+	GetLogger().Info("PostgreSQL database opened successfully",
+		logger.String("location", PostgresLocation(store.Settings)))
+
+	if err := performAutoMigration(db, store.Settings.Debug, "Postgres", pg.Database); err != nil {
+		return err
+	}
+
+	// Start monitoring if metrics are available, with the same intervals
+	// as the MySQL store.
+	if store.metrics != nil {
+		store.StartMonitoring(30*time.Second, 5*time.Minute)
+	}
+
+	return nil
+}
+
+// Close PostgreSQL database connections
+func (store *PostgresStore) Close() error {
+	if store.DB == nil {
+		return nil
+	}
+
+	// Stop monitoring before closing database
+	store.StopMonitoring()
+
+	location := PostgresLocation(store.Settings)
+	GetLogger().Info("Closing PostgreSQL database connection",
+		logger.String("location", location))
+
+	sqlDB, err := store.DB.DB()
+	if err != nil {
+		GetLogger().Error("Failed to retrieve generic DB object",
+			logger.Error(err))
+		return err
+	}
+
+	err = sqlDB.Close()
+	store.DB = nil
+
+	if err != nil {
+		GetLogger().Error("Failed to close PostgreSQL database",
+			logger.String("location", location),
+			logger.Error(err))
+		return err
+	}
+
+	GetLogger().Info("PostgreSQL database closed successfully",
+		logger.String("location", location))
+	return nil
+}
+
+// Optimize vacuums and analyzes every table of the current schema.
+func (store *PostgresStore) Optimize(ctx context.Context) error {
+	if store.DB == nil {
+		return errors.Newf("database connection is not initialized").
+			Component("datastore").
+			Category(errors.CategoryValidation).
+			Context("operation", "optimize").
+			Build()
+	}
+
+	optimizeStart := time.Now()
+	optimizeLogger := GetLogger().With(logger.String("operation", "optimize"), logger.String("db_type", "PostgreSQL"))
+
+	optimizeLogger.Info("Starting database optimization")
+
+	tables, err := store.DB.WithContext(ctx).Migrator().GetTables()
+	if err != nil {
+		enhancedErr := errors.New(err).
+			Component("datastore").
+			Category(errors.CategoryDatabase).
+			Context("operation", "list_tables").
+			Build()
+		optimizeLogger.Error("Failed to get table list", logger.Error(enhancedErr))
+		return enhancedErr
+	}
+
+	optimizeLogger.Info("Found tables to optimize", logger.Int("table_count", len(tables)))
+
+	// VACUUM cannot run inside a transaction block; GORM's Exec runs each
+	// statement on its own, so every table is vacuumed separately.
+	optimizedCount := 0
+	for _, table := range tables {
+		tableStart := time.Now()
+		if err := store.DB.WithContext(ctx).Exec("VACUUM (ANALYZE) ?", clause.Table{Name: table}).Error; err != nil {
+			optimizeLogger.Warn("Failed to vacuum table",
+				logger.String("table", table),
+				logger.Error(err))
+			continue
+		}
+		optimizedCount++
+		optimizeLogger.Info("Table optimization completed",
+			logger.String("table", table),
+			logger.Duration("duration", time.Since(tableStart)))
+	}
+
+	optimizeLogger.Info("Database optimization completed",
+		logger.Duration("total_duration", time.Since(optimizeStart)),
+		logger.Int("tables_processed", len(tables)),
+		logger.Int("tables_optimized", optimizedCount))
+
+	return nil
+}
+
+// EnsureModelRegistered is a no-op for the legacy PostgreSQL datastore.
+// Model registration is only supported in the v2 schema.
+func (store *PostgresStore) EnsureModelRegistered(_ detection.ModelInfo) error {
+	return nil
+}
+
+// UpdateNote updates specific fields of a note in PostgreSQL
+func (store *PostgresStore) UpdateNote(id string, updates map[string]any) error {
+	return store.DB.Model(&Note{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// SchemaVersion returns the datastore schema version.
+func (store *PostgresStore) SchemaVersion() string {
+	return SchemaVersionLegacy
+}
+
+// SaveAppEvent is a no-op for the legacy PostgreSQL datastore.
+func (store *PostgresStore) SaveAppEvent(_ context.Context, _, _, _ string, _ map[string]any) error {
+	return nil
+}
+
+// GetRecentAppEvents is a no-op for the legacy PostgreSQL datastore.
+func (store *PostgresStore) GetRecentAppEvents(_ context.Context, _ int) ([]AppEvent, error) {
+	return nil, nil
+}
+
+// GetAppEventsSince is a no-op for the legacy PostgreSQL datastore.
+func (store *PostgresStore) GetAppEventsSince(_ context.Context, _ time.Time, _ int) ([]AppEvent, error) {
+	return nil, nil
+}
+
+// PruneAppEvents is a no-op for the legacy PostgreSQL datastore.
+func (store *PostgresStore) PruneAppEvents(_ context.Context, _ int) (int64, error) {
+	return 0, nil
+}
+
+// GetDatabaseStats returns basic runtime statistics about the PostgreSQL database.
+// Returns partial stats with ErrDBNotConnected if the database is unreachable.
+// The Connected field in the returned stats indicates if the DB is reachable.
+func (store *PostgresStore) GetDatabaseStats(ctx context.Context) (*DatabaseStats, error) {
+	location := ""
+	if store.Settings != nil {
+		location = PostgresLocation(store.Settings)
+	}
+
+	stats := &DatabaseStats{
+		Type:      DialectPostgres,
+		Connected: false,
+		Location:  location,
+	}
+
+	if store.DB == nil {
+		return stats, ErrDBNotConnected
+	}
+
+	sqlDB, err := store.DB.DB()
+	if err != nil {
+		return stats, ErrDBNotConnected
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return stats, ErrDBNotConnected
+	}
+	stats.Connected = true
+
+	if size, sizeErr := store.getDatabaseSize(ctx); sizeErr == nil {
+		stats.SizeBytes = size
+	}
+
+	if count, countErr := store.getTableRowCount(ctx, "notes"); countErr == nil {
+		stats.TotalDetections = count
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,154 @@
+package datastore
+
+import (
+	"fmt"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// Compile-time check that PostgresStore implements DatabaseInspector.
+var _ DatabaseInspector = (*PostgresStore)(nil)
+
+// GetEngineDetails returns PostgreSQL-specific engine metadata collected from
+// sql.DB.Stats() and pg_stat_database.
+func (s *PostgresStore) GetEngineDetails() (EngineDetails, error) {
+	return PostgresEngineDetails(s.DB)
+}
+
+// PostgresEngineDetails collects the engine metadata of a PostgreSQL
+// connection. It is shared by the legacy store and the v2-only datastore.
+func PostgresEngineDetails(db *gorm.DB) (EngineDetails, error) {
+	details := &PostgresDetails{}
+
+	// Go connection pool stats from database/sql
+	sqlDB, err := db.DB()
+	if err == nil {
+		stats := sqlDB.Stats()
+		details.ActiveConnections = stats.InUse
+		details.IdleConnections = stats.Idle
+		details.MaxConnections = stats.MaxOpenConnections
+	}
+
+	var version string
+	if err := db.Raw("SHOW server_version").Scan(&version).Error; err == nil {
+		details.EngineVersion = version
+	}
+	var maxConns string
+	if err := db.Raw("SHOW max_connections").Scan(&maxConns).Error; err == nil {
+		details.ServerMaxConns, _ = strconv.Atoi(maxConns)
+	}
+
+	row, err := queryDatabaseStat(db)
+	if err != nil {
+		return EngineDetails{Postgres: details}, fmt.Errorf("postgres: query pg_stat_database: %w", err)
+	}
+
+	details.Backends = row.NumBackends
+	details.Commits = row.XactCommit
+	details.Rollbacks = row.XactRollback
+	details.Deadlocks = row.Deadlocks
+	details.TempBytes = row.TempBytes
+	if total := row.BlksHit + row.BlksRead; total > 0 {
+		details.CacheHitRate = float64(row.BlksHit) / float64(total) * 100
+	}
+
+	return EngineDetails{Postgres: details}, nil
+}
+
+// GetTableStats returns per-table row estimates and total relation sizes for
+// the tables of the current schema.
+func (s *PostgresStore) GetTableStats() ([]TableStats, error) {
+	return PostgresTableStats(s.DB)
+}
+
+// PostgresTableStats returns the table statistics of a PostgreSQL
+// connection. Row counts are the planner's live-tuple estimates, which avoid
+// a full scan of every table on each dashboard refresh.
+func PostgresTableStats(db *gorm.DB) ([]TableStats, error) {
+	var results []TableStats
+	err := db.Raw(`
+		SELECT
+			relname AS name,
+			n_live_tup AS row_count,
+			pg_total_relation_size(relid) AS size_bytes
+		FROM pg_stat_user_tables
+		WHERE schemaname = current_schema()
+		ORDER BY size_bytes DESC
+	`).Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("postgres: get table stats: %w", err)
+	}
+
+	// Compute usage percentage
+	var totalSize int64
+	for i := range results {
+		totalSize += results[i].SizeBytes
+	}
+	if totalSize > 0 {
+		for i := range results {
+			results[i].UsagePct = float64(results[i].SizeBytes) / float64(totalSize) * 100
+		}
+	}
+	return results, nil
+}
+
+// GetDetectionRate24h returns hourly detection counts for the last 24 hours.
+// The legacy notes.date column holds "YYYY-MM-DD" and time "HH:MM:SS" text.
+func (s *PostgresStore) GetDetectionRate24h() ([]HourlyCount, error) {
+	var results []HourlyCount
+	err := s.DB.Raw(`
+		SELECT to_char(date_trunc('hour', CAST(date || ' ' || time AS timestamp)), 'YYYY-MM-DD"T"HH24:00:00"Z"') AS hour, COUNT(*) AS count
+		FROM notes
+		WHERE CAST(date || ' ' || time AS timestamp) > (now() AT TIME ZONE 'UTC') - INTERVAL '24 hours'
+		GROUP BY hour
+		ORDER BY hour
+	`).Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("postgres: get 24h detection rate: %w", err)
+	}
+	return results, nil
+}
+
+// GetDetectionRateDaily returns daily detection counts for the specified number of days.
+func (s *PostgresStore) GetDetectionRateDaily(days int) ([]DailyCount, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("postgres: days must be positive, got %d", days)
+	}
+	var results []DailyCount
+	err := s.DB.Raw(`
+		SELECT date, COUNT(*) AS count
+		FROM notes
+		WHERE date > to_char(CURRENT_DATE - make_interval(days => ?), 'YYYY-MM-DD')
+		GROUP BY date
+		ORDER BY date
+	`, days).Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("postgres: get daily detection rate: %w", err)
+	}
+	return results, nil
+}
+
+// --- PostgreSQL statistics helpers ---
+
+// databaseStatRow holds the pg_stat_database counters of the current database.
+type databaseStatRow struct {
+	NumBackends  int   `gorm:"column:numbackends"`
+	XactCommit   int64 `gorm:"column:xact_commit"`
+	XactRollback int64 `gorm:"column:xact_rollback"`
+	BlksRead     int64 `gorm:"column:blks_read"`
+	BlksHit      int64 `gorm:"column:blks_hit"`
+	Deadlocks    int64 `gorm:"column:deadlocks"`
+	TempBytes    int64 `gorm:"column:temp_bytes"`
+}
+
+// queryDatabaseStat reads the pg_stat_database row of the current database.
+func queryDatabaseStat(db *gorm.DB) (databaseStatRow, error) {
+	var row databaseStatRow
+	err := db.Raw(`
+		SELECT numbackends, xact_commit, xact_rollback, blks_read, blks_hit, deadlocks, temp_bytes
+		FROM pg_stat_database
+		WHERE datname = current_database()
+	`).Scan(&row).Error
+	return row, err
+}
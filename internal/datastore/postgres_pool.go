@@ -0,0 +1,87 @@
+package datastore
+
+import (
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// PostgreSQL connection pool defaults shared by both v1 and v2 stores.
+// They match the MySQL budget: PostgreSQL's default max_connections is 100,
+// so the combined maximum of 2 * PostgresMaxOpenConns = 50 during migration
+// leaves room for superuser-reserved slots and other clients.
+const (
+	PostgresMaxOpenConns    = 25
+	PostgresMaxIdleConns    = 10
+	PostgresConnMaxLifetime = 5 * time.Minute
+	PostgresConnMaxIdleTime = 3 * time.Minute
+)
+
+// PostgresConnection holds the parameters needed to reach a PostgreSQL
+// server. Both the v1 store and the v2 manager build their DSN from it.
+type PostgresConnection struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	Database string
+	SSLMode  string
+}
+
+// PostgresConnectionFromSettings returns the connection parameters of the
+// configured PostgreSQL output.
+func PostgresConnectionFromSettings(settings *conf.Settings) PostgresConnection {
+	pg := settings.Output.Postgres
+	return PostgresConnection{
+		Host:     pg.Host,
+		Port:     pg.Port,
+		Username: pg.Username,
+		Password: pg.Password,
+		Database: pg.Database,
+		SSLMode:  pg.SSLMode,
+	}
+}
+
+// DSN builds a connection URL for the server. Credentials are URL-escaped.
+// connectTimeout, when non-empty, is passed as the libpq connect_timeout in
+// seconds.
+func (c PostgresConnection) DSN(connectTimeout string) string {
+	query := url.Values{}
+	if c.SSLMode != "" {
+		query.Set("sslmode", c.SSLMode)
+	}
+	if connectTimeout != "" {
+		query.Set("connect_timeout", connectTimeout)
+	}
+	host := c.Host
+	if c.Port != "" {
+		host = net.JoinHostPort(c.Host, c.Port)
+	}
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.Username, c.Password),
+		Host:     host,
+		Path:     "/" + c.Database,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// Location returns the host:port/database display form of the server,
+// without credentials.
+func (c PostgresConnection) Location() string {
+	return net.JoinHostPort(c.Host, c.Port) + "/" + c.Database
+}
+
+// PostgresDSN builds the connection URL of the configured PostgreSQL output.
+func PostgresDSN(settings *conf.Settings, connectTimeout string) string {
+	return PostgresConnectionFromSettings(settings).DSN(connectTimeout)
+}
+
+// PostgresLocation returns the host:port/database display form of the
+// configured PostgreSQL output.
+func PostgresLocation(settings *conf.Settings) string {
+	return PostgresConnectionFromSettings(settings).Location()
+}
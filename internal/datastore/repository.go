@@ -44,7 +44,7 @@ type DetectionRepository interface {
 	GetHourly(ctx context.Context, date, hour string, duration, limit, offset int) ([]*detection.Result, int64, error)
 
 	// Lock/Unlock operations
-	Lock(ctx context.Context, id string) error
+	Lock(ctx context.Context, id, lockedBy string) error
 	Unlock(ctx context.Context, id string) error
 	IsLocked(ctx context.Context, id string) (bool, error)
 
@@ -259,7 +259,7 @@ func TestDatabaseContract_LockRelationship(t *testing.T) {
 
 	// Lock the note
 	noteIDStr := fmt.Sprintf("%d", note.ID)
-	err = ds.LockNote(noteIDStr)
+	err = ds.LockNote(noteIDStr, "")
 	require.NoError(t, err, "Failed to lock note")
 
 	// Load note (should include Lock via preload)
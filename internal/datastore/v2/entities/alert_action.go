@@ -1,12 +1,21 @@
 package entities
 
-// AlertAction defines a notification target for an alert rule.
-// Target is "bell" for web UI or a push provider name.
+// AlertAction defines what happens when an alert rule fires.
+// Target is "bell" for web UI, "push" for push providers, or one of the
+// automation targets "mqtt", "webhook", "script" and "control". The fields
+// after TemplateMessage only apply to the targets noted beside them.
 type AlertAction struct {
 	ID              uint   `gorm:"primaryKey" json:"id"`
 	RuleID          uint   `gorm:"not null;index" json:"rule_id"`
 	Target          string `gorm:"size:100;not null" json:"target"`
 	TemplateTitle   string `gorm:"size:500;default:''" json:"template_title"`
 	TemplateMessage string `gorm:"size:2000;default:''" json:"template_message"`
+	TemplateBody    string `gorm:"type:text" json:"template_body,omitempty"`         // mqtt, webhook, script: payload (empty = JSON event)
+	Topic           string `gorm:"size:255;default:''" json:"topic,omitempty"`       // mqtt
+	URL             string `gorm:"size:2000;default:''" json:"url,omitempty"`        // webhook
+	Method          string `gorm:"size:10;default:''" json:"method,omitempty"`       // webhook (empty = POST)
+	Script          string `gorm:"size:100;default:''" json:"script,omitempty"`      // script: name from alerting.scripts
+	Control         string `gorm:"size:50;default:''" json:"control,omitempty"`      // control: action name
+	ControlArg      string `gorm:"size:255;default:''" json:"control_arg,omitempty"` // control: e.g. source ID
 	SortOrder       int    `gorm:"default:0" json:"sort_order"`
 }
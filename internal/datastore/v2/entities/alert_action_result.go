@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// AlertActionResult records the outcome of one action dispatched for an
+// alert history entry. Status is "success", "failed" or "skipped"; Detail
+// holds a short target-specific summary such as the HTTP status of a webhook.
+type AlertActionResult struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	HistoryID  uint      `gorm:"not null;index" json:"history_id"`
+	ActionID   uint      `gorm:"not null;default:0" json:"action_id"`
+	Target     string    `gorm:"size:100;not null" json:"target"`
+	Status     string    `gorm:"size:20;not null" json:"status"`
+	Detail     string    `gorm:"size:500;default:''" json:"detail,omitempty"`
+	Error      string    `gorm:"size:1000;default:''" json:"error,omitempty"`
+	Test       bool      `gorm:"not null;default:false" json:"test"`
+	DurationMs int64     `gorm:"not null;default:0" json:"duration_ms"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -1,13 +1,24 @@
 package entities
 
 // AlertCondition defines a single condition within an alert rule.
-// All conditions in a rule use AND logic.
+// Conditions belong to the rule's root group unless GroupKey names one of
+// the rule's ConditionGroups; each group ANDs or ORs its members.
 type AlertCondition struct {
 	ID          uint   `gorm:"primaryKey" json:"id"`
 	RuleID      uint   `gorm:"not null;index" json:"rule_id"`
 	Property    string `gorm:"size:100;not null" json:"property"`
 	Operator    string `gorm:"size:20;not null" json:"operator"`
 	Value       string `gorm:"size:500;not null" json:"value"`
-	DurationSec int    `gorm:"default:0" json:"duration_sec"`
+	DurationSec int    `gorm:"default:0" json:"duration_sec"`                 // Metric: sustained for; event_count: counting window
+	GroupKey    int    `gorm:"not null;default:0" json:"group_key,omitempty"` // 0 = root group
 	SortOrder   int    `gorm:"default:0" json:"sort_order"`
 }
+
+// AlertConditionGroup is a nested AND/OR group of conditions. Groups are
+// stored with the rule and refer to each other by Key, which the client
+// chooses; Parent 0 is the rule's root group.
+type AlertConditionGroup struct {
+	Key    int    `json:"key"`
+	Parent int    `json:"parent"`
+	Match  string `json:"match"` // "all" (AND) or "any" (OR)
+}
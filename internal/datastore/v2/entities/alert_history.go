@@ -11,4 +11,8 @@ type AlertHistory struct {
 	Actions   string    `gorm:"type:text" json:"actions"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	Rule      AlertRule `gorm:"foreignKey:RuleID;constraint:OnDelete:CASCADE" json:"rule,omitzero"`
+
+	// Results holds one entry per dispatched action, filled in as the
+	// actions complete.
+	Results []AlertActionResult `gorm:"foreignKey:HistoryID;constraint:OnDelete:CASCADE" json:"results,omitempty"`
 }
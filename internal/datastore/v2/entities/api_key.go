@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// APIKey is a named, scoped credential for dashboards and scripts that poll
+// the API. Only a SHA-256 hash of the key is stored; the key itself is shown
+// once when it is created. Prefix keeps the first characters of the key so
+// users can tell their keys apart.
+//
+// Scopes holds a comma-separated list of security.Scope names. A revoked or
+// expired key is kept for the audit trail but no longer authenticates.
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:16;not null" json:"prefix"`
+	KeyHash    string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scopes     string     `gorm:"size:255;not null" json:"scopes"`
+	CreatedBy  string     `gorm:"size:100;default:''" json:"created_by,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
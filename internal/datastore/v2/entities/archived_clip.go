@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// ArchivedClip records an audio clip that the tiering retention policy moved
+// from the local clip directory to a secondary store. Detections keep their
+// clip path; this index tells the media handlers where the file now lives so
+// playback can fetch it back on demand.
+//
+// ClipPath is relative to the clip export directory, the same form the
+// detections store. Spectrograms lists the rendered images that were moved
+// alongside the clip, by the same relative form.
+type ArchivedClip struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ClipPath     string    `gorm:"size:500;not null;uniqueIndex" json:"clip_path"`
+	Target       string    `gorm:"size:20;not null" json:"target"`               // "local", "s3" or "sftp"
+	Location     string    `gorm:"size:500;not null;default:''" json:"location"` // display form, no credentials
+	FileSize     int64     `gorm:"not null" json:"file_size"`
+	Spectrograms []string  `gorm:"serializer:json;default:null" json:"spectrograms,omitempty"`
+	ArchivedAt   time.Time `gorm:"autoCreateTime;index" json:"archived_at"`
+}
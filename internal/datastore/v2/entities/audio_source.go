@@ -25,5 +25,19 @@ type AudioSource struct {
 	SourceType  SourceType `gorm:"type:varchar(20);not null"`
 	DisplayName *string    `gorm:"type:varchar(200)"`
 	ConfigJSON  *string    `gorm:"type:text"`
-	CreatedAt   time.Time  `gorm:"autoCreateTime"`
+	// Deployment is where the source's microphone is installed. Sources
+	// without deployment metadata leave every column NULL.
+	Deployment SourceDeployment `gorm:"embedded;embeddedPrefix:deployment_"`
+	CreatedAt  time.Time        `gorm:"autoCreateTime"`
+}
+
+// SourceDeployment is the deployment metadata of an audio source, copied from
+// the source's configuration when it records a detection.
+type SourceDeployment struct {
+	Site             *string  `gorm:"type:varchar(100);index"`
+	Latitude         *float64 // decimal degrees
+	Longitude        *float64 // decimal degrees
+	Habitat          *string  `gorm:"type:varchar(100)"`
+	MicrophoneModel  *string  `gorm:"type:varchar(100)"`
+	MicrophoneHeight *float64 // meters above ground
 }
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// ClassifierExample assigns a user-defined label to a detection's clip. The
+// custom classifier is trained on the embeddings of labelled clips, so a
+// label can name anything the stock models do not know: a local call type,
+// a neighbour's parrot or a recurring noise source. A clip carries at most
+// one label.
+type ClassifierExample struct {
+	ID          uint      `gorm:"primaryKey"`
+	DetectionID uint      `gorm:"not null;uniqueIndex"`
+	Label       string    `gorm:"size:64;not null;index"`
+	LabelledBy  string    `gorm:"size:100;default:''"` // Username of the labeller; empty without user accounts
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+
+	// Relationship
+	Detection *Detection `gorm:"foreignKey:DetectionID;constraint:OnDelete:CASCADE,OnUpdate:CASCADE"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// ClipRedaction records the stretches of a detection's saved clip that the
+// privacy filter blanked because they held human voice. Segments are offsets
+// from the clip start; Fill names what replaced the voice ("silence" or
+// "noise").
+type ClipRedaction struct {
+	DetectionID uint              `gorm:"primaryKey;autoIncrement:false" json:"detection_id"`
+	Fill        string            `gorm:"size:16;not null" json:"fill"`
+	Segments    []RedactedSegment `gorm:"serializer:json;not null" json:"segments"`
+	CreatedAt   time.Time         `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationship
+	Detection *Detection `gorm:"foreignKey:DetectionID;constraint:OnDelete:CASCADE,OnUpdate:CASCADE" json:"-"`
+}
+
+// RedactedSegment is one blanked stretch of a clip, in seconds from the
+// clip start.
+type RedactedSegment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
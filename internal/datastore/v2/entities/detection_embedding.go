@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// DetectionEmbedding stores the embedding vector computed from a detection's
+// saved audio clip. The similar recordings search compares these vectors to
+// find clips that sound alike.
+//
+// Vector holds the L2-normalized embedding quantized to one signed byte per
+// dimension; multiplying each byte by Scale restores the normalized value.
+// Model names the embedding model, since vectors from different models are
+// not comparable.
+type DetectionEmbedding struct {
+	DetectionID uint      `gorm:"primaryKey;autoIncrement:false"`
+	Model       string    `gorm:"size:100;not null;index"`
+	Dim         int       `gorm:"not null"`
+	Scale       float32   `gorm:"not null"`
+	Vector      []byte    `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+
+	// Relationship
+	Detection *Detection `gorm:"foreignKey:DetectionID;constraint:OnDelete:CASCADE,OnUpdate:CASCADE"`
+}
@@ -8,6 +8,7 @@ type DetectionLock struct {
 	ID          uint      `gorm:"primaryKey"`
 	DetectionID uint      `gorm:"not null;uniqueIndex"`
 	LockedAt    time.Time `gorm:"autoCreateTime;index"`
+	LockedBy    string    `gorm:"size:100;default:''"` // Username of the user who locked it; empty for older locks
 
 	// Relationship
 	Detection *Detection `gorm:"foreignKey:DetectionID;constraint:OnDelete:CASCADE,OnUpdate:CASCADE"`
@@ -16,6 +16,7 @@ type DetectionReview struct {
 	ID          uint               `gorm:"primaryKey"`
 	DetectionID uint               `gorm:"not null;uniqueIndex"`
 	Verified    VerificationStatus `gorm:"type:varchar(20);not null"`
+	ReviewedBy  string             `gorm:"size:100;default:''"` // Username of the reviewer; empty for reviews made before user accounts
 	CreatedAt   time.Time          `gorm:"autoCreateTime;index"`
 	UpdatedAt   time.Time          `gorm:"autoUpdateTime"`
 
@@ -18,6 +18,23 @@
 //   - DetectionReview: Verification status
 //   - DetectionComment: User comments
 //   - DetectionLock: Lock status
+//   - DetectionEmbedding: Clip embedding vector for similar recording search
+//   - ClassifierExample: User label on a clip for training the custom classifier
+//   - ClipRedaction: Stretches of a saved clip blanked by the privacy filter
+//
+// # Accounts
+//
+//   - UserAccount: Station users with roles; reviews and locks record the username
+//   - APIKey: Named, scoped and revocable keys for API clients
+//
+// # Deliveries
+//
+//   - OutboxItem: Queued BirdWeather, MQTT and webhook deliveries that survive restarts
+//
+// # Recordings
+//
+//   - SoundscapeSegment: Files of continuous per-source soundscape recordings
+//   - ArchivedClip: Clips the tiering retention policy moved to a secondary store
 //
 // # Migration
 //
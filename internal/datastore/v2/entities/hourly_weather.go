@@ -4,8 +4,8 @@ import "time"
 
 // HourlyWeather stores hourly weather data.
 // It mirrors the legacy hourly_weathers table structure plus the v2-only
-// Precipitation/PrecipitationType columns, which the deprecated legacy schema
-// intentionally does not carry.
+// Precipitation/PrecipitationType/SolarRadiation columns, which the deprecated
+// legacy schema intentionally does not carry.
 type HourlyWeather struct {
 	ID                uint      `gorm:"primaryKey"`
 	DailyEventsID     uint      `gorm:"index"` // Foreign key to DailyEvents
@@ -23,6 +23,7 @@ type HourlyWeather struct {
 	Clouds            int
 	Precipitation     float64   // Precipitation amount in mm for the observation window
 	PrecipitationType string    `gorm:"size:20"` // "rain", "snow", "sleet", or "" when none
+	SolarRadiation    float64   // Solar radiation in W/m², 0 when the provider does not measure it
 	WeatherMain       string    `gorm:"size:50"`
 	WeatherDesc       string    `gorm:"size:200"`
 	WeatherIcon       string    `gorm:"size:20"`
@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// OutboxStatus is the delivery state of an outbox item.
+type OutboxStatus string
+
+const (
+	// OutboxStatusPending items wait for delivery or for their next retry.
+	OutboxStatusPending OutboxStatus = "pending"
+	// OutboxStatusDead items ran out of attempts or failed permanently. They
+	// stay in the table until retried or purged.
+	OutboxStatusDead OutboxStatus = "dead"
+)
+
+// OutboxItem is a queued delivery to an external service such as
+// BirdWeather, the MQTT broker or a push webhook. Items survive restarts and
+// are deleted once delivered.
+//
+// Destination names the receiving service, e.g. "birdweather" or
+// "webhook:discord". Items of one destination are delivered in ID order; a
+// failing item holds back the items queued after it until it is delivered or
+// dead-lettered.
+type OutboxItem struct {
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	Destination   string       `gorm:"size:100;not null;index:idx_outbox_items_destination_status,priority:1" json:"destination"`
+	Status        OutboxStatus `gorm:"type:varchar(20);not null;index:idx_outbox_items_destination_status,priority:2" json:"status"`
+	Summary       string       `gorm:"size:255;default:''" json:"summary"`
+	Payload       []byte       `gorm:"not null" json:"-"`
+	Attempts      int          `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time    `gorm:"not null" json:"next_attempt_at"`
+	LastError     string       `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// SoundscapeSegment indexes one file of a continuous soundscape recording.
+// Recorders write consecutive segments per audio source; the index lets the
+// web UI list them by source and time and lets the retention policy delete
+// the oldest first without walking the directory tree.
+//
+// FilePath is relative to the configured soundscape directory.
+type SoundscapeSegment struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	SourceID   string    `gorm:"size:100;not null;index:idx_soundscape_segments_source_start,priority:1" json:"source_id"`
+	SourceName string    `gorm:"size:255;default:''" json:"source_name"`
+	StartTime  time.Time `gorm:"not null;index:idx_soundscape_segments_source_start,priority:2;index:idx_soundscape_segments_start" json:"start_time"`
+	Duration   float64   `gorm:"not null" json:"duration"` // seconds
+	FilePath   string    `gorm:"size:500;not null;uniqueIndex" json:"file_path"`
+	Format     string    `gorm:"size:10;not null" json:"format"`
+	SampleRate int       `gorm:"not null" json:"sample_rate"`
+	FileSize   int64     `gorm:"not null" json:"file_size"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// EndTime returns the time the segment's last sample was captured.
+func (s *SoundscapeSegment) EndTime() time.Time {
+	return s.StartTime.Add(time.Duration(s.Duration * float64(time.Second)))
+}
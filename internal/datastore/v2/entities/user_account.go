@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// UserAccount is a station user with a role. Accounts let several people
+// share one station without sharing the admin credentials. A user signs in
+// either with a password (PasswordHash) or through one of the configured OAuth
+// providers, matched on ExternalID (the provider e-mail address or subject).
+//
+// Role holds one of the security.Role names ("viewer", "reviewer", "admin").
+type UserAccount struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Username     string     `gorm:"size:100;not null;uniqueIndex" json:"username"`
+	DisplayName  string     `gorm:"size:255;default:''" json:"display_name"`
+	PasswordHash string     `gorm:"size:255;default:''" json:"-"`
+	ExternalID   string     `gorm:"size:255;default:'';index" json:"external_id,omitempty"`
+	Role         string     `gorm:"size:20;not null" json:"role"`
+	Disabled     bool       `gorm:"not null;default:false" json:"disabled"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
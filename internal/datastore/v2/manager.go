@@ -49,26 +49,28 @@ type Manager interface {
 	Initialize() error
 	// DB returns the underlying GORM database.
 	DB() *gorm.DB
-	// Path returns the database location (file path for SQLite, connection string info for MySQL).
+	// Path returns the database location (file path for SQLite, connection string info for MySQL and PostgreSQL).
 	Path() string
 	// Close closes the database connection.
 	Close() error
 	// CheckpointWAL forces a WAL checkpoint to ensure all changes are written to the main database file.
 	// This should be called before Close() for graceful shutdown.
 	CheckpointWAL() error
-	// Delete removes the v2 database (file for SQLite, tables for MySQL).
+	// Delete removes the v2 database (file for SQLite, tables for MySQL and PostgreSQL).
 	Delete() error
 	// Exists checks if the v2 database exists.
 	Exists() bool
 	// IsMySQL returns true if this is a MySQL manager.
 	IsMySQL() bool
+	// IsPostgres returns true if this is a PostgreSQL manager.
+	IsPostgres() bool
 	// TablePrefix returns the prefix applied to every v2 table name. It is
-	// non-empty (currently "v2_") only on MySQL deployments that are still
-	// in the v1→v2 migration window — there, v2 tables coexist alongside
-	// the legacy v1 schema and need the prefix to avoid name collisions.
-	// On SQLite, on fresh-install MySQL, and on any future cutover where
-	// the prefix is dropped, this returns "". Code that builds raw-SQL
-	// table references MUST consult this to stay correct under all three
+	// non-empty (currently "v2_") only on MySQL and PostgreSQL deployments
+	// that are still in the v1→v2 migration window — there, v2 tables
+	// coexist alongside the legacy v1 schema and need the prefix to avoid
+	// name collisions. On SQLite, on fresh installs, and on any future
+	// cutover where the prefix is dropped, this returns "". Code that builds
+	// raw-SQL table references MUST consult this to stay correct under all
 	// deployment modes.
 	TablePrefix() string
 }
@@ -312,10 +314,26 @@ func v2Entities() []any {
 		&entities.AlertCondition{},
 		&entities.AlertAction{},
 		&entities.AlertHistory{},
+		&entities.AlertActionResult{},
 		// Application metadata
 		&entities.AppMetadata{},
 		// Application event log
 		&entities.AppEvent{},
+		// Multi-user accounts and API keys
+		&entities.UserAccount{},
+		&entities.APIKey{},
+		// Durable delivery outbox
+		&entities.OutboxItem{},
+		// Continuous soundscape recording index
+		&entities.SoundscapeSegment{},
+		// Tiered clip storage index
+		&entities.ArchivedClip{},
+		// Clip embeddings for similar recording search
+		&entities.DetectionEmbedding{},
+		// Labelled clips for the custom classifier
+		&entities.ClassifierExample{},
+		// Voice redactions applied to saved clips
+		&entities.ClipRedaction{},
 	}
 }
 
@@ -526,6 +544,19 @@ func mysqlColumnLister(db *gorm.DB, tableName string) ([]string, error) {
 	return names, nil
 }
 
+// postgresColumnLister queries information_schema for the column names of a
+// table in the current schema.
+func postgresColumnLister(db *gorm.DB, tableName string) ([]string, error) {
+	var names []string
+	if err := db.Raw(
+		"SELECT column_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ?",
+		tableName,
+	).Scan(&names).Error; err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
 // missingColumnsEntry records the missing columns for a single table during validation.
 type missingColumnsEntry struct {
 	table   string
@@ -891,6 +922,11 @@ func (m *SQLiteManager) IsMySQL() bool {
 	return false
 }
 
+// IsPostgres returns false for SQLite manager.
+func (m *SQLiteManager) IsPostgres() bool {
+	return false
+}
+
 // TablePrefix returns the table prefix for v2 tables.
 // For SQLite, this is empty since we use a separate database file.
 // For MySQL, this would return "v2_".
@@ -14,7 +14,7 @@ import (
 // All methods are nil-safe — if the receiver is nil, calls are no-ops.
 // Consent is handled by the telemetry package (checks settings.Sentry.Enabled).
 type MigrationTelemetry struct {
-	dbType string // "sqlite" or "mysql"
+	dbType string // "sqlite", "mysql" or "postgres"
 }
 
 // NewMigrationTelemetry creates a new migration telemetry reporter.
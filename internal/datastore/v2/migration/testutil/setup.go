@@ -578,8 +578,8 @@ func (r *testDetectionRepo) GetHourly(_ context.Context, _, _ string, _, _, _ in
 	return nil, 0, nil
 }
 
-func (r *testDetectionRepo) Lock(_ context.Context, _ string) error   { return nil }
-func (r *testDetectionRepo) Unlock(_ context.Context, _ string) error { return nil }
+func (r *testDetectionRepo) Lock(_ context.Context, _, _ string) error { return nil }
+func (r *testDetectionRepo) Unlock(_ context.Context, _ string) error  { return nil }
 func (r *testDetectionRepo) IsLocked(_ context.Context, _ string) (bool, error) {
 	return false, nil
 }
@@ -718,7 +718,7 @@ func (s *testLegacyInterface) CountSpeciesDetections(_, _, _ string, _ int) (int
 	return 0, nil
 }
 func (s *testLegacyInterface) Transaction(_ func(tx *gorm.DB) error) error       { return nil }
-func (s *testLegacyInterface) LockNote(_ string) error                           { return nil }
+func (s *testLegacyInterface) LockNote(_, _ string) error                        { return nil }
 func (s *testLegacyInterface) UnlockNote(_ string) error                         { return nil }
 func (s *testLegacyInterface) GetNoteLock(_ string) (*datastore.NoteLock, error) { return nil, nil } //nolint:nilnil // stub
 func (s *testLegacyInterface) IsNoteLocked(_ string) (bool, error)               { return false, nil }
@@ -255,6 +255,7 @@ func (m *RelatedDataMigrator) migrateReviews(ctx context.Context) (migrated, bat
 			v2Reviews = append(v2Reviews, &entities.DetectionReview{
 				DetectionID: r.NoteID,
 				Verified:    entities.VerificationStatus(r.Verified),
+				ReviewedBy:  r.ReviewedBy,
 				CreatedAt:   r.CreatedAt,
 				UpdatedAt:   r.UpdatedAt,
 			})
@@ -424,6 +425,7 @@ func (m *RelatedDataMigrator) migrateLocks(ctx context.Context) (migrated, batch
 			v2Locks = append(v2Locks, &entities.DetectionLock{
 				DetectionID: l.NoteID,
 				LockedAt:    l.LockedAt,
+				LockedBy:    l.LockedBy,
 			})
 		}
 
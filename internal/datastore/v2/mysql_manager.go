@@ -237,11 +237,25 @@ func (m *MySQLManager) Close() error {
 // Delete removes all v2 tables from the MySQL database.
 // This should only be called during rollback or cleanup.
 func (m *MySQLManager) Delete() error {
-	// Drop tables in reverse dependency order
 	// Use the stored prefix (could be "" for fresh install or "v2_" for migration)
-	prefix := m.tablePrefix
-	tables := []string{
+	for _, table := range v2TablesInDropOrder(m.tablePrefix) {
+		// Use raw SQL to drop tables to avoid GORM's safety checks
+		if err := m.db.Exec("DROP TABLE IF EXISTS " + table).Error; err != nil {
+			return fmt.Errorf("failed to drop table %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// v2TablesInDropOrder returns every v2 table name with the given prefix, in
+// reverse dependency order so children are dropped before their parents.
+func v2TablesInDropOrder(prefix string) []string {
+	return []string{
 		// Core detection tables (drop children first)
+		prefix + "clip_redactions",
+		prefix + "classifier_examples",
+		prefix + "detection_embeddings",
 		prefix + "detection_locks",
 		prefix + "detection_comments",
 		prefix + "detection_reviews",
@@ -250,6 +264,7 @@ func (m *MySQLManager) Delete() error {
 		prefix + "detections",
 		prefix + "audio_sources",
 		// Alert tables (drop children first)
+		prefix + "alert_action_results",
 		prefix + "alert_histories",
 		prefix + "alert_actions",
 		prefix + "alert_conditions",
@@ -266,22 +281,20 @@ func (m *MySQLManager) Delete() error {
 		prefix + "ai_models",
 		prefix + "taxonomic_classes",
 		prefix + "label_types",
-		// Application metadata and event log (no dependencies)
+		// Application metadata, event log, user accounts, API keys, the
+		// delivery outbox, the soundscape index and the archived clip index
+		// (no dependencies)
+		prefix + "archived_clips",
+		prefix + "soundscape_segments",
+		prefix + "outbox_items",
+		prefix + "api_keys",
+		prefix + "user_accounts",
 		prefix + "app_events",
 		prefix + "app_metadata",
 		// Migration tracking
 		prefix + "migration_states",
 		prefix + "migration_dirty_ids",
 	}
-
-	for _, table := range tables {
-		// Use raw SQL to drop tables to avoid GORM's safety checks
-		if err := m.db.Exec("DROP TABLE IF EXISTS " + table).Error; err != nil {
-			return fmt.Errorf("failed to drop table %s: %w", table, err)
-		}
-	}
-
-	return nil
 }
 
 // Exists checks if v2 tables exist in the MySQL database.
@@ -297,6 +310,11 @@ func (m *MySQLManager) IsMySQL() bool {
 	return true
 }
 
+// IsPostgres returns false for MySQL manager.
+func (m *MySQLManager) IsPostgres() bool {
+	return false
+}
+
 // CheckpointWAL is a no-op for MySQL as it doesn't use Write-Ahead Logging.
 // This method exists to satisfy the Manager interface.
 func (m *MySQLManager) CheckpointWAL() error {
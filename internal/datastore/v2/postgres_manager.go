@@ -0,0 +1,172 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gorm_logger "gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// PostgresConfig holds PostgreSQL-specific configuration for the v2 manager.
+type PostgresConfig struct {
+	datastore.PostgresConnection
+	Debug bool
+	// UseV2Prefix controls whether tables use the "v2_" prefix, with the
+	// same meaning as MySQLConfig.UseV2Prefix.
+	UseV2Prefix bool
+	Logger      logger.Logger
+}
+
+// PostgresManager handles the v2 normalized database for PostgreSQL. Like
+// MySQL it shares the database with the legacy tables during migration and
+// separates the v2 schema with the "v2_" table prefix.
+type PostgresManager struct {
+	db          *gorm.DB
+	config      PostgresConfig
+	tablePrefix string // "" for fresh installs, "v2_" for migration
+	log         logger.Logger
+}
+
+// NewPostgresManager creates a new v2 PostgreSQL database manager.
+// If UseV2Prefix is true, tables will be created with the "v2_" prefix (migration mode).
+// If UseV2Prefix is false, tables use clean names (fresh install mode).
+func NewPostgresManager(cfg *PostgresConfig) (*PostgresManager, error) {
+	var gormLog gorm_logger.Interface
+	if cfg.Logger != nil {
+		gormLog = logger.NewGormLoggerAdapter(cfg.Logger, defaultGormSlowThreshold)
+	} else {
+		logLevel := gorm_logger.Silent
+		if cfg.Debug {
+			logLevel = gorm_logger.Info
+		}
+		gormLog = gorm_logger.Default.LogMode(logLevel)
+	}
+
+	tablePrefix := ""
+	if cfg.UseV2Prefix {
+		tablePrefix = v2TablePrefix
+	}
+
+	db, err := gorm.Open(postgres.Open(cfg.DSN("")), &gorm.Config{
+		Logger: gormLog,
+		NamingStrategy: schema.NamingStrategy{
+			TablePrefix: tablePrefix, // "" for fresh install, "v2_" for migration
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PostgreSQL database for v2: %w", err)
+	}
+
+	// Configure connection pool using shared constants (see datastore.Postgres*).
+	// Matches the v1 store so both stay within max_connections during migration.
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying database: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(datastore.PostgresMaxOpenConns)
+	sqlDB.SetMaxIdleConns(datastore.PostgresMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(datastore.PostgresConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(datastore.PostgresConnMaxIdleTime)
+
+	return &PostgresManager{
+		db:          db,
+		config:      *cfg,
+		tablePrefix: tablePrefix,
+		log:         cfg.Logger,
+	}, nil
+}
+
+// Initialize creates the v2 schema tables and seeds the lookup data.
+// PostgreSQL support postdates the PR #2165 table renames, so unlike the
+// MySQL manager there are no legacy table names to repair first.
+func (m *PostgresManager) Initialize() error {
+	if err := m.db.AutoMigrate(v2Entities()...); err != nil {
+		reportInitFailure("postgres", "AutoMigrate", err, m.config.Host, m.config.Database, m.config.Username)
+		return fmt.Errorf("failed to migrate v2 schema: %w", err)
+	}
+
+	// Validate schema integrity AFTER AutoMigrate, see MySQLManager.Initialize.
+	if err := validateSchemaIntegrity(m.db, m.log, "postgres", postgresColumnLister); err != nil {
+		return fmt.Errorf("v2 schema integrity check failed: %w", err)
+	}
+
+	state := entities.MigrationState{ID: 1, State: entities.MigrationStatusIdle}
+	if err := m.db.FirstOrCreate(&state, entities.MigrationState{ID: 1}).Error; err != nil {
+		reportInitFailure("postgres", "initMigrationState", err, m.config.Host, m.config.Database, m.config.Username)
+		return fmt.Errorf("failed to initialize migration state: %w", err)
+	}
+
+	if err := seedLookupTablesDB(m.db); err != nil {
+		reportInitFailure("postgres", "seedLookupTables", err, m.config.Host, m.config.Database, m.config.Username)
+		return fmt.Errorf("failed to seed lookup tables: %w", err)
+	}
+
+	if err := seedDefaultModelDB(m.db); err != nil {
+		reportInitFailure("postgres", "seedDefaultModel", err, m.config.Host, m.config.Database, m.config.Username)
+		return err
+	}
+	return nil
+}
+
+// DB returns the underlying GORM database.
+func (m *PostgresManager) DB() *gorm.DB {
+	return m.db
+}
+
+// Path returns the database location (host:port/database).
+func (m *PostgresManager) Path() string {
+	return m.config.Location()
+}
+
+// Close closes the database connection.
+func (m *PostgresManager) Close() error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying database: %w", err)
+	}
+	return sqlDB.Close()
+}
+
+// Delete removes all v2 tables from the PostgreSQL database.
+// This should only be called during rollback or cleanup.
+func (m *PostgresManager) Delete() error {
+	for _, table := range v2TablesInDropOrder(m.tablePrefix) {
+		if err := m.db.Exec("DROP TABLE IF EXISTS ? CASCADE", clause.Table{Name: table}).Error; err != nil {
+			return fmt.Errorf("failed to drop table %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// Exists checks if v2 tables exist in the PostgreSQL database.
+func (m *PostgresManager) Exists() bool {
+	return m.db.Migrator().HasTable(m.tablePrefix + "migration_states")
+}
+
+// IsMySQL returns false for PostgreSQL manager.
+func (m *PostgresManager) IsMySQL() bool {
+	return false
+}
+
+// IsPostgres returns true for PostgreSQL manager.
+func (m *PostgresManager) IsPostgres() bool {
+	return true
+}
+
+// CheckpointWAL is a no-op for PostgreSQL, which manages its own
+// write-ahead log. This method exists to satisfy the Manager interface.
+func (m *PostgresManager) CheckpointWAL() error {
+	return nil
+}
+
+// TablePrefix returns the table prefix for v2 tables.
+// Returns "" for fresh installs, "v2_" for migration mode.
+func (m *PostgresManager) TablePrefix() string {
+	return m.tablePrefix
+}
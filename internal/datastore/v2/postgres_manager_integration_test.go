@@ -0,0 +1,126 @@
+//go:build integration
+
+package v2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/conf/conftest"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/testutil/containers"
+)
+
+// startPostgres starts a PostgreSQL container for a single test and returns
+// settings pointing at it. The container is terminated on test cleanup.
+//
+// The tests in this file start their own container instead of sharing one
+// through TestMain, which the MySQL coexistence tests already own.
+func startPostgres(t *testing.T) *conf.Settings {
+	t.Helper()
+	ctx := t.Context()
+
+	cfg := containers.DefaultPostgresConfig()
+	container, err := containers.NewPostgresContainer(ctx, &cfg)
+	require.NoError(t, err, "failed to create PostgreSQL container")
+	t.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil { //nolint:gocritic // cleanup context must outlive test
+			t.Logf("error terminating test container: %v", err)
+		}
+	})
+
+	host, err := container.GetHost(ctx)
+	require.NoError(t, err)
+	port, err := container.GetPort(ctx)
+	require.NoError(t, err)
+
+	settings := conftest.GetTestSettings()
+	settings.Output.Postgres.Enabled = true
+	settings.Output.Postgres.Host = host
+	settings.Output.Postgres.Port = strconv.Itoa(port)
+	settings.Output.Postgres.Database = cfg.Database
+	settings.Output.Postgres.Username = cfg.Username
+	settings.Output.Postgres.Password = cfg.Password
+	settings.Output.Postgres.SSLMode = "disable"
+	return settings
+}
+
+// newTestPostgresManager creates and initializes a v2 manager for the settings.
+func newTestPostgresManager(t *testing.T, settings *conf.Settings, useV2Prefix bool) *PostgresManager {
+	t.Helper()
+	mgr, err := NewPostgresManager(&PostgresConfig{
+		PostgresConnection: datastore.PostgresConnectionFromSettings(settings),
+		UseV2Prefix:        useV2Prefix,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mgr.Close() })
+	require.NoError(t, mgr.Initialize())
+	return mgr
+}
+
+func TestPostgresManager_Integration(t *testing.T) {
+	settings := startPostgres(t)
+
+	t.Run("fresh install layout", func(t *testing.T) {
+		assert.Equal(t, DecisionFreshInstall, checkPostgresMigrationState(settings).Decision)
+
+		mgr := newTestPostgresManager(t, settings, false)
+		assert.True(t, mgr.IsPostgres())
+		assert.False(t, mgr.IsMySQL())
+		assert.Empty(t, mgr.TablePrefix())
+		assert.True(t, mgr.Exists())
+		assert.True(t, mgr.DB().Migrator().HasTable("detections"))
+		assert.True(t, CheckPostgresHasFreshV2Schema(settings))
+
+		// Initialize is idempotent on an existing schema.
+		require.NoError(t, mgr.Initialize())
+
+		state := checkPostgresMigrationState(settings)
+		assert.Equal(t, DecisionV2Restart, state.Decision)
+		assert.False(t, state.LegacyRequired)
+
+		require.NoError(t, mgr.Delete())
+		assert.False(t, mgr.Exists())
+		assert.False(t, mgr.DB().Migrator().HasTable("detections"))
+	})
+
+	t.Run("migration layout", func(t *testing.T) {
+		mgr := newTestPostgresManager(t, settings, true)
+		t.Cleanup(func() { _ = mgr.Delete() })
+
+		assert.Equal(t, v2TablePrefix, mgr.TablePrefix())
+		assert.True(t, mgr.Exists())
+		assert.True(t, mgr.DB().Migrator().HasTable(v2TablePrefix+"detections"))
+		assert.False(t, CheckPostgresHasFreshV2Schema(settings))
+
+		state := checkPostgresMigrationState(settings)
+		assert.Equal(t, fmt.Sprintf("%s%s", DecisionMigrationPrefix, state.MigrationStatus), state.Decision)
+		assert.True(t, state.V2Available)
+	})
+
+	t.Run("legacy store", func(t *testing.T) {
+		store := &datastore.PostgresStore{Settings: settings}
+		require.NoError(t, store.Open())
+		t.Cleanup(func() {
+			_ = store.DB.Exec("DROP TABLE IF EXISTS notes CASCADE").Error
+			assert.NoError(t, store.Close())
+		})
+
+		stats, err := store.GetDatabaseStats(t.Context())
+		require.NoError(t, err)
+		assert.True(t, stats.Connected)
+		assert.Equal(t, datastore.DialectPostgres, stats.Type)
+
+		details, err := store.GetEngineDetails()
+		require.NoError(t, err)
+		require.NotNil(t, details.Postgres)
+		assert.NotEmpty(t, details.Postgres.EngineVersion)
+
+		assert.Equal(t, DecisionLegacyMode, checkPostgresMigrationState(settings).Decision)
+	})
+}
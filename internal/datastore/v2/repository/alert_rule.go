@@ -23,6 +23,7 @@ type AlertRuleRepository interface {
 
 	// History
 	SaveHistory(ctx context.Context, history *entities.AlertHistory) error
+	SaveActionResult(ctx context.Context, result *entities.AlertActionResult) error
 	ListHistory(ctx context.Context, filter AlertHistoryFilter) ([]entities.AlertHistory, int64, error)
 	DeleteHistory(ctx context.Context) (int64, error)
 	DeleteHistoryBefore(ctx context.Context, before time.Time) (int64, error)
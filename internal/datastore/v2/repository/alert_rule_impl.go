@@ -166,6 +166,17 @@ func (r *alertRuleRepository) SaveHistory(ctx context.Context, history *entities
 	}, r.metrics)
 }
 
+// SaveActionResult saves the outcome of one action dispatched for a history entry.
+func (r *alertRuleRepository) SaveActionResult(ctx context.Context, result *entities.AlertActionResult) error {
+	return datastore.RetryOnLock(ctx, "v2_save_alert_action_result", func() error {
+		result.ID = 0 // Reset ID for retry safety
+		if err := r.db.WithContext(ctx).Create(result).Error; err != nil {
+			return fmt.Errorf("failed to save alert action result: %w", err)
+		}
+		return nil
+	}, r.metrics)
+}
+
 // ListHistory returns alert history entries matching the filter with pagination.
 func (r *alertRuleRepository) ListHistory(ctx context.Context, filter AlertHistoryFilter) ([]entities.AlertHistory, int64, error) {
 	var items []entities.AlertHistory
@@ -179,7 +190,9 @@ func (r *alertRuleRepository) ListHistory(ctx context.Context, filter AlertHisto
 		return nil, 0, fmt.Errorf("failed to count alert history: %w", err)
 	}
 
-	query := r.db.WithContext(ctx).Preload("Rule").Order("fired_at DESC")
+	query := r.db.WithContext(ctx).Preload("Rule").
+		Preload("Results", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Order("fired_at DESC")
 	if filter.RuleID > 0 {
 		query = query.Where("rule_id = ?", filter.RuleID)
 	}
@@ -195,11 +208,16 @@ func (r *alertRuleRepository) ListHistory(ctx context.Context, filter AlertHisto
 	return items, total, nil
 }
 
-// DeleteHistory deletes all alert history entries.
+// DeleteHistory deletes all alert history entries and their action results.
+// Results are deleted explicitly rather than relying on the cascade, which
+// SQLite only enforces when foreign keys are enabled on the connection.
 func (r *alertRuleRepository) DeleteHistory(ctx context.Context) (int64, error) {
 	var rowsAffected int64
-	err := datastore.RetryOnLock(ctx, "v2_delete_alert_history", func() error {
-		result := r.db.WithContext(ctx).Where("1 = 1").Delete(&entities.AlertHistory{})
+	err := datastore.RetryTransactionOnLock(ctx, r.db, "v2_delete_alert_history", func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&entities.AlertActionResult{}).Error; err != nil {
+			return fmt.Errorf("failed to delete alert action results: %w", err)
+		}
+		result := tx.Where("1 = 1").Delete(&entities.AlertHistory{})
 		if result.Error != nil {
 			return fmt.Errorf("failed to delete alert history: %w", result.Error)
 		}
@@ -209,11 +227,16 @@ func (r *alertRuleRepository) DeleteHistory(ctx context.Context) (int64, error)
 	return rowsAffected, err
 }
 
-// DeleteHistoryBefore deletes alert history entries older than the given time.
+// DeleteHistoryBefore deletes alert history entries older than the given time,
+// together with their action results.
 func (r *alertRuleRepository) DeleteHistoryBefore(ctx context.Context, before time.Time) (int64, error) {
 	var rowsAffected int64
-	err := datastore.RetryOnLock(ctx, "v2_delete_alert_history_before", func() error {
-		result := r.db.WithContext(ctx).Where("fired_at < ?", before).Delete(&entities.AlertHistory{})
+	err := datastore.RetryTransactionOnLock(ctx, r.db, "v2_delete_alert_history_before", func(tx *gorm.DB) error {
+		expired := tx.Model(&entities.AlertHistory{}).Select("id").Where("fired_at < ?", before)
+		if err := tx.Where("history_id IN (?)", expired).Delete(&entities.AlertActionResult{}).Error; err != nil {
+			return fmt.Errorf("failed to delete alert action results before %v: %w", before, err)
+		}
+		result := tx.Where("fired_at < ?", before).Delete(&entities.AlertHistory{})
 		if result.Error != nil {
 			return fmt.Errorf("failed to delete alert history before %v: %w", before, result.Error)
 		}
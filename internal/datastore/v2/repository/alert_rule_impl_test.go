@@ -32,6 +32,7 @@ func setupAlertTestDB(t *testing.T) *gorm.DB {
 		&entities.AlertCondition{},
 		&entities.AlertAction{},
 		&entities.AlertHistory{},
+		&entities.AlertActionResult{},
 	)
 	require.NoError(t, err, "failed to migrate alert tables")
 	return db
@@ -323,6 +324,54 @@ func TestAlertRuleRepository_History(t *testing.T) {
 	})
 }
 
+func TestAlertRuleRepository_ActionResults(t *testing.T) {
+	db := setupAlertTestDB(t)
+	repo := NewAlertRuleRepository(db, nil)
+	ctx := t.Context()
+
+	rule := createTestRule(t, repo, "ResultRule", "stream", "event", "stream.disconnected")
+
+	now := time.Now()
+	old := &entities.AlertHistory{RuleID: rule.ID, FiredAt: now.Add(-48 * time.Hour)}
+	recent := &entities.AlertHistory{RuleID: rule.ID, FiredAt: now}
+	require.NoError(t, repo.SaveHistory(ctx, old))
+	require.NoError(t, repo.SaveHistory(ctx, recent))
+
+	require.NoError(t, repo.SaveActionResult(ctx, &entities.AlertActionResult{HistoryID: old.ID, Target: "webhook", Status: "failed", Error: "HTTP 500"}))
+	require.NoError(t, repo.SaveActionResult(ctx, &entities.AlertActionResult{HistoryID: recent.ID, Target: "control", Status: "success", Detail: "restart_audio_source rtsp_1"}))
+	require.NoError(t, repo.SaveActionResult(ctx, &entities.AlertActionResult{HistoryID: recent.ID, Target: "mqtt", Status: "success"}))
+
+	t.Run("history includes results", func(t *testing.T) {
+		items, _, err := repo.ListHistory(ctx, AlertHistoryFilter{})
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		require.Len(t, items[0].Results, 2)
+		assert.Equal(t, "control", items[0].Results[0].Target)
+		assert.Equal(t, "mqtt", items[0].Results[1].Target)
+		require.Len(t, items[1].Results, 1)
+		assert.Equal(t, "HTTP 500", items[1].Results[0].Error)
+	})
+
+	t.Run("expired history removes its results", func(t *testing.T) {
+		deleted, err := repo.DeleteHistoryBefore(ctx, now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		var count int64
+		require.NoError(t, db.Model(&entities.AlertActionResult{}).Count(&count).Error)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("delete all history removes all results", func(t *testing.T) {
+		_, err := repo.DeleteHistory(ctx)
+		require.NoError(t, err)
+
+		var count int64
+		require.NoError(t, db.Model(&entities.AlertActionResult{}).Count(&count).Error)
+		assert.Equal(t, int64(0), count)
+	})
+}
+
 func TestAlertRuleRepository_GetEnabledRules(t *testing.T) {
 	db := setupAlertTestDB(t)
 	repo := NewAlertRuleRepository(db, nil)
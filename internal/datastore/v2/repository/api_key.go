@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// APIKeyRepository handles API keys.
+type APIKeyRepository interface {
+	// List returns all keys, including revoked ones, newest first.
+	List(ctx context.Context) ([]entities.APIKey, error)
+	// GetByID returns ErrAPIKeyNotFound if the key does not exist.
+	GetByID(ctx context.Context, id uint) (*entities.APIKey, error)
+	// GetByHash returns ErrAPIKeyNotFound if no key has the given hash.
+	GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error)
+	// Create stores a new key.
+	Create(ctx context.Context, key *entities.APIKey) error
+	// Revoke marks a key as revoked. Revoking an already revoked key keeps
+	// the original revocation time. Returns ErrAPIKeyNotFound if the key does
+	// not exist.
+	Revoke(ctx context.Context, id uint, at time.Time) error
+	// TouchLastUsed records that a key authenticated a request.
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
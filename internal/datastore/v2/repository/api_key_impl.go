@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+)
+
+// apiKeyRepository implements APIKeyRepository.
+type apiKeyRepository struct {
+	db      *gorm.DB
+	metrics *datastore.Metrics
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository.
+// metrics is optional (nil-safe) and enables retry observability.
+func NewAPIKeyRepository(db *gorm.DB, metrics *datastore.Metrics) APIKeyRepository {
+	return &apiKeyRepository{db: db, metrics: metrics}
+}
+
+// List returns all keys, newest first.
+func (r *apiKeyRepository) List(ctx context.Context) ([]entities.APIKey, error) {
+	var keys []entities.APIKey
+	if err := r.db.WithContext(ctx).Order("id DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// GetByID returns a single key by ID.
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uint) (*entities.APIKey, error) {
+	return r.first(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// GetByHash returns the key with the given hash.
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	return r.first(r.db.WithContext(ctx).Where("key_hash = ?", keyHash))
+}
+
+func (r *apiKeyRepository) first(query *gorm.DB) (*entities.APIKey, error) {
+	var key entities.APIKey
+	if err := query.First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return &key, nil
+}
+
+// Create stores a new key.
+func (r *apiKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	return datastore.RetryOnLock(ctx, "v2_create_api_key", func() error {
+		key.ID = 0 // Reset ID for retry safety
+		if err := r.db.WithContext(ctx).Create(key).Error; err != nil {
+			return fmt.Errorf("failed to create API key: %w", err)
+		}
+		return nil
+	}, r.metrics)
+}
+
+// Revoke marks a key as revoked.
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return datastore.RetryOnLock(ctx, "v2_revoke_api_key", func() error {
+		if err := r.db.WithContext(ctx).Model(&entities.APIKey{}).
+			Where("id = ? AND revoked_at IS NULL", id).
+			UpdateColumn("revoked_at", at).Error; err != nil {
+			return fmt.Errorf("failed to revoke API key %d: %w", id, err)
+		}
+		return nil
+	}, r.metrics)
+}
+
+// TouchLastUsed records that a key authenticated a request.
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return datastore.RetryOnLock(ctx, "v2_touch_api_key", func() error {
+		if err := r.db.WithContext(ctx).Model(&entities.APIKey{}).
+			Where("id = ?", id).UpdateColumn("last_used_at", at).Error; err != nil {
+			return fmt.Errorf("failed to record use of API key %d: %w", id, err)
+		}
+		return nil
+	}, r.metrics)
+}
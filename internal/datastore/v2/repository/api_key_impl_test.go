@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+func setupAPIKeyTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+
+	require.NoError(t, db.AutoMigrate(&entities.APIKey{}))
+	return db
+}
+
+func TestAPIKeyRepository_CreateAndGet(t *testing.T) {
+	t.Parallel()
+	repo := NewAPIKeyRepository(setupAPIKeyTestDB(t), nil)
+	ctx := t.Context()
+
+	first := &entities.APIKey{Name: "grafana", Prefix: "bnk_abcd", KeyHash: "hash-1", Scopes: "detections:read"}
+	second := &entities.APIKey{Name: "script", Prefix: "bnk_efgh", KeyHash: "hash-2", Scopes: "control"}
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+
+	got, err := repo.GetByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, got.ID)
+	assert.Equal(t, "detections:read", got.Scopes)
+
+	_, err = repo.GetByHash(ctx, "missing")
+	require.ErrorIs(t, err, ErrAPIKeyNotFound)
+
+	keys, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "script", keys[0].Name, "newest key first")
+
+	// The hash is unique.
+	require.Error(t, repo.Create(ctx, &entities.APIKey{Name: "dup", Prefix: "bnk_abcd", KeyHash: "hash-1", Scopes: "control"}))
+}
+
+func TestAPIKeyRepository_RevokeAndTouch(t *testing.T) {
+	t.Parallel()
+	repo := NewAPIKeyRepository(setupAPIKeyTestDB(t), nil)
+	ctx := t.Context()
+
+	key := &entities.APIKey{Name: "grafana", Prefix: "bnk_abcd", KeyHash: "hash-1", Scopes: "detections:read"}
+	require.NoError(t, repo.Create(ctx, key))
+
+	used := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	require.NoError(t, repo.TouchLastUsed(ctx, key.ID, used))
+
+	revoked := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, repo.Revoke(ctx, key.ID, revoked))
+	// A second revocation keeps the original time.
+	require.NoError(t, repo.Revoke(ctx, key.ID, revoked.Add(time.Hour)))
+
+	got, err := repo.GetByID(ctx, key.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.LastUsedAt)
+	assert.True(t, got.LastUsedAt.Equal(used))
+	require.NotNil(t, got.RevokedAt)
+	assert.True(t, got.RevokedAt.Equal(revoked))
+
+	require.ErrorIs(t, repo.Revoke(ctx, 999, revoked), ErrAPIKeyNotFound)
+}
@@ -47,7 +47,7 @@ func (r *appMetadataRepository) tableName() string {
 func (r *appMetadataRepository) Get(ctx context.Context, key string) (string, error) {
 	var meta entities.AppMetadata
 	err := r.db.WithContext(ctx).Table(r.tableName()).
-		Where("`key` = ?", key).
+		Where(clause.Eq{Column: clause.Column{Name: "key"}, Value: key}).
 		First(&meta).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return "", nil
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// ArchivedClipRepository handles the index of clips moved to the secondary
+// clip store by the tiering retention policy.
+type ArchivedClipRepository interface {
+	// Save records an archived clip, replacing any earlier record for the
+	// same clip path.
+	Save(ctx context.Context, clip *entities.ArchivedClip) error
+	// GetByClipPath returns ErrArchivedClipNotFound if the clip has not been
+	// archived.
+	GetByClipPath(ctx context.Context, clipPath string) (*entities.ArchivedClip, error)
+	// FilterArchived returns the subset of clipPaths that have been archived.
+	FilterArchived(ctx context.Context, clipPaths []string) (map[string]bool, error)
+	// Count returns the number of archived clips and their combined size in
+	// bytes.
+	Count(ctx context.Context) (count, totalBytes int64, err error)
+	// Delete removes the record for a clip path. Returns
+	// ErrArchivedClipNotFound if the clip has not been archived.
+	Delete(ctx context.Context, clipPath string) error
+}
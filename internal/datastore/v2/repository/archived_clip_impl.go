@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// archivedClipRepository implements ArchivedClipRepository.
+type archivedClipRepository struct {
+	db      *gorm.DB
+	metrics *datastore.Metrics
+}
+
+// NewArchivedClipRepository creates a new ArchivedClipRepository.
+// metrics is optional (nil-safe) and enables retry observability.
+func NewArchivedClipRepository(db *gorm.DB, metrics *datastore.Metrics) ArchivedClipRepository {
+	return &archivedClipRepository{db: db, metrics: metrics}
+}
+
+// Save records an archived clip, replacing any earlier record for the same
+// clip path.
+func (r *archivedClipRepository) Save(ctx context.Context, clip *entities.ArchivedClip) error {
+	if clip == nil {
+		return fmt.Errorf("archived clip cannot be nil")
+	}
+	if clip.ClipPath == "" {
+		return fmt.Errorf("archived clip path cannot be empty")
+	}
+	return datastore.RetryOnLock(ctx, "v2_save_archived_clip", func() error {
+		clip.ID = 0 // Reset ID for retry safety
+		if err := r.db.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "clip_path"}},
+				DoUpdates: clause.AssignmentColumns([]string{"target", "location", "file_size", "spectrograms", "archived_at"}),
+			}).
+			Create(clip).Error; err != nil {
+			return fmt.Errorf("failed to save archived clip: %w", err)
+		}
+		return nil
+	}, r.metrics)
+}
+
+// GetByClipPath returns the archive record for a clip path.
+func (r *archivedClipRepository) GetByClipPath(ctx context.Context, clipPath string) (*entities.ArchivedClip, error) {
+	var clip entities.ArchivedClip
+	if err := r.db.WithContext(ctx).Where("clip_path = ?", clipPath).First(&clip).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrArchivedClipNotFound
+		}
+		return nil, fmt.Errorf("failed to get archived clip: %w", err)
+	}
+	return &clip, nil
+}
+
+// FilterArchived returns the subset of clipPaths that have been archived.
+// Handles large path sets by chunking to avoid SQL parameter limits.
+func (r *archivedClipRepository) FilterArchived(ctx context.Context, clipPaths []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	for i := 0; i < len(clipPaths); i += batchQuerySize {
+		end := min(i+batchQuerySize, len(clipPaths))
+
+		var found []string
+		if err := r.db.WithContext(ctx).Model(&entities.ArchivedClip{}).
+			Where("clip_path IN ?", clipPaths[i:end]).
+			Pluck("clip_path", &found).Error; err != nil {
+			return nil, fmt.Errorf("failed to filter archived clips: %w", err)
+		}
+		for _, p := range found {
+			result[p] = true
+		}
+	}
+	return result, nil
+}
+
+// Count returns the number of archived clips and their combined size in bytes.
+func (r *archivedClipRepository) Count(ctx context.Context) (count, totalBytes int64, err error) {
+	var row struct {
+		Count      int64
+		TotalBytes int64
+	}
+	if err := r.db.WithContext(ctx).Model(&entities.ArchivedClip{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(file_size), 0) AS total_bytes").
+		Scan(&row).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to count archived clips: %w", err)
+	}
+	return row.Count, row.TotalBytes, nil
+}
+
+// Delete removes the record for a clip path.
+func (r *archivedClipRepository) Delete(ctx context.Context, clipPath string) error {
+	var deleted int64
+	err := datastore.RetryOnLock(ctx, "v2_delete_archived_clip", func() error {
+		result := r.db.WithContext(ctx).Where("clip_path = ?", clipPath).Delete(&entities.ArchivedClip{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete archived clip %q: %w", clipPath, result.Error)
+		}
+		deleted = result.RowsAffected
+		return nil
+	}, r.metrics)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrArchivedClipNotFound
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+func setupArchivedClipTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+
+	require.NoError(t, db.AutoMigrate(&entities.ArchivedClip{}))
+	return db
+}
+
+func TestArchivedClipRepository_SaveAndGet(t *testing.T) {
+	t.Parallel()
+	repo := NewArchivedClipRepository(setupArchivedClipTestDB(t), nil)
+	ctx := t.Context()
+
+	clip := &entities.ArchivedClip{
+		ClipPath:     "2026/05/parus_major_80p_20260501T060000Z.wav",
+		Target:       "local",
+		Location:     "/mnt/nas/clips",
+		FileSize:     1000,
+		Spectrograms: []string{"2026/05/parus_major_80p_20260501T060000Z.png"},
+	}
+	require.NoError(t, repo.Save(ctx, clip))
+
+	got, err := repo.GetByClipPath(ctx, clip.ClipPath)
+	require.NoError(t, err)
+	assert.Equal(t, "local", got.Target)
+	assert.Equal(t, int64(1000), got.FileSize)
+	assert.Equal(t, clip.Spectrograms, got.Spectrograms)
+	assert.False(t, got.ArchivedAt.IsZero())
+
+	// Saving the same path again replaces the record instead of failing on
+	// the unique index.
+	require.NoError(t, repo.Save(ctx, &entities.ArchivedClip{
+		ClipPath: clip.ClipPath,
+		Target:   "s3",
+		Location: "s3://bucket/clips",
+		FileSize: 2000,
+	}))
+	got, err = repo.GetByClipPath(ctx, clip.ClipPath)
+	require.NoError(t, err)
+	assert.Equal(t, "s3", got.Target)
+	assert.Equal(t, int64(2000), got.FileSize)
+	assert.Empty(t, got.Spectrograms)
+
+	count, total, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, int64(2000), total)
+
+	_, err = repo.GetByClipPath(ctx, "missing.wav")
+	require.ErrorIs(t, err, ErrArchivedClipNotFound)
+}
+
+func TestArchivedClipRepository_FilterAndDelete(t *testing.T) {
+	t.Parallel()
+	repo := NewArchivedClipRepository(setupArchivedClipTestDB(t), nil)
+	ctx := t.Context()
+
+	for _, p := range []string{"a.wav", "b.wav"} {
+		require.NoError(t, repo.Save(ctx, &entities.ArchivedClip{ClipPath: p, Target: "local", FileSize: 10}))
+	}
+
+	archived, err := repo.FilterArchived(ctx, []string{"a.wav", "b.wav", "c.wav"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"a.wav": true, "b.wav": true}, archived)
+
+	empty, err := repo.FilterArchived(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	require.NoError(t, repo.Delete(ctx, "a.wav"))
+	require.ErrorIs(t, repo.Delete(ctx, "a.wav"), ErrArchivedClipNotFound)
+
+	count, total, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, int64(10), total)
+}
@@ -37,6 +37,10 @@ type AudioSourceRepository interface {
 	// GetByNodeName retrieves all audio sources for a specific node.
 	GetByNodeName(ctx context.Context, nodeName string) ([]*entities.AudioSource, error)
 
+	// GetBySite retrieves all audio sources deployed at a site. The site name
+	// is matched case-insensitively.
+	GetBySite(ctx context.Context, site string) ([]*entities.AudioSource, error)
+
 	// Count returns the total number of audio sources.
 	Count(ctx context.Context) (int64, error)
 
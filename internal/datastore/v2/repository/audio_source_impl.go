@@ -192,6 +192,16 @@ func (r *audioSourceRepository) GetByNodeName(ctx context.Context, nodeName stri
 	return sources, err
 }
 
+// GetBySite retrieves all audio sources deployed at a site.
+func (r *audioSourceRepository) GetBySite(ctx context.Context, site string) ([]*entities.AudioSource, error) {
+	var sources []*entities.AudioSource
+	err := r.db.WithContext(ctx).Table(r.tableName()).
+		Where("LOWER(deployment_site) = LOWER(?)", site).
+		Order("node_name ASC, source_uri ASC").
+		Find(&sources).Error
+	return sources, err
+}
+
 // Count returns the total number of audio sources.
 func (r *audioSourceRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
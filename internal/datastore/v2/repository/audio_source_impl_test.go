@@ -182,3 +182,27 @@ func TestGetOrCreate_AutoDetectsSourceType(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, entities.SourceTypeRTSP, source.SourceType)
 }
+
+func TestGetBySite(t *testing.T) {
+	t.Parallel()
+	db := setupAudioSourceTestDB(t)
+	repo := NewAudioSourceRepository(db, nil, false, false)
+	ctx := t.Context()
+
+	meadow, err := repo.GetOrCreate(ctx, "rtsp://meadow/stream", "node1", nil, "")
+	require.NoError(t, err)
+	_, err = repo.GetOrCreate(ctx, "hw:0,0", "node1", nil, entities.SourceTypeALSA)
+	require.NoError(t, err)
+	require.NoError(t, repo.Update(ctx, meadow.ID, map[string]any{"deployment_site": "North Meadow"}))
+
+	sources, err := repo.GetBySite(ctx, "north meadow")
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	assert.Equal(t, meadow.ID, sources[0].ID)
+	require.NotNil(t, sources[0].Deployment.Site)
+	assert.Equal(t, "North Meadow", *sources[0].Deployment.Site)
+
+	sources, err = repo.GetBySite(ctx, "Nowhere")
+	require.NoError(t, err)
+	assert.Empty(t, sources)
+}
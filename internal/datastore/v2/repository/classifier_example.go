@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// ClassifierLabelCount is the number of clips carrying one custom label.
+type ClassifierLabelCount struct {
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+// TrainingExample is one clip of the custom classifier training set.
+// Label is empty for a negative clip: a detection reviewed as a false
+// positive, or one reviewed correct whose species is not a custom label.
+type TrainingExample struct {
+	DetectionID uint
+	Label       string
+	Vector      []float32 // normalized embedding
+}
+
+// ClassifierExampleRepository stores the user labels the custom classifier
+// is trained on and assembles its training set.
+type ClassifierExampleRepository interface {
+	// Set labels a detection's clip, replacing any earlier label.
+	Set(ctx context.Context, detectionID uint, label, labelledBy string) error
+	// Delete removes a detection's label. Returns
+	// ErrClassifierExampleNotFound if the detection has none.
+	Delete(ctx context.Context, detectionID uint) error
+	// List returns the labelled clips, newest first. An empty label lists
+	// every clip.
+	List(ctx context.Context, label string) ([]entities.ClassifierExample, error)
+	// LabelCounts returns the number of clips per label, sorted by label.
+	LabelCounts(ctx context.Context) ([]ClassifierLabelCount, error)
+	// TrainingSet returns every labelled or reviewed clip that has an
+	// embedding from model. Clips without an embedding are skipped. A
+	// detection reviewed correct is a positive for its species if that
+	// species is a custom label; other reviewed clips are negatives.
+	TrainingSet(ctx context.Context, model string) ([]TrainingExample, error)
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// trainingSetBatch bounds the number of detection IDs per IN clause while
+// loading training set embeddings.
+const trainingSetBatch = 500
+
+// classifierExampleRepository implements ClassifierExampleRepository.
+type classifierExampleRepository struct {
+	db      *gorm.DB
+	metrics *datastore.Metrics
+}
+
+// NewClassifierExampleRepository creates a new ClassifierExampleRepository.
+// metrics is optional (nil-safe) and enables retry observability.
+func NewClassifierExampleRepository(db *gorm.DB, metrics *datastore.Metrics) ClassifierExampleRepository {
+	return &classifierExampleRepository{db: db, metrics: metrics}
+}
+
+// Set labels a detection's clip, replacing any earlier label.
+func (r *classifierExampleRepository) Set(ctx context.Context, detectionID uint, label, labelledBy string) error {
+	if detectionID == 0 {
+		return fmt.Errorf("detection ID cannot be zero")
+	}
+	if label == "" {
+		return fmt.Errorf("label cannot be empty")
+	}
+	row := &entities.ClassifierExample{
+		DetectionID: detectionID,
+		Label:       label,
+		LabelledBy:  labelledBy,
+	}
+	return datastore.RetryOnLock(ctx, "v2_set_classifier_example", func() error {
+		if err := r.db.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "detection_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"label", "labelled_by", "updated_at"}),
+			}).
+			Create(row).Error; err != nil {
+			return fmt.Errorf("failed to save classifier example: %w", err)
+		}
+		return nil
+	}, r.metrics)
+}
+
+// Delete removes a detection's label.
+func (r *classifierExampleRepository) Delete(ctx context.Context, detectionID uint) error {
+	var affected int64
+	err := datastore.RetryOnLock(ctx, "v2_delete_classifier_example", func() error {
+		result := r.db.WithContext(ctx).Where("detection_id = ?", detectionID).Delete(&entities.ClassifierExample{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete classifier example: %w", result.Error)
+		}
+		affected = result.RowsAffected
+		return nil
+	}, r.metrics)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrClassifierExampleNotFound
+	}
+	return nil
+}
+
+// List returns the labelled clips, newest first.
+func (r *classifierExampleRepository) List(ctx context.Context, label string) ([]entities.ClassifierExample, error) {
+	query := r.db.WithContext(ctx).Order("created_at DESC, id DESC")
+	if label != "" {
+		query = query.Where("label = ?", label)
+	}
+	var rows []entities.ClassifierExample
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list classifier examples: %w", err)
+	}
+	return rows, nil
+}
+
+// LabelCounts returns the number of clips per label.
+func (r *classifierExampleRepository) LabelCounts(ctx context.Context) ([]ClassifierLabelCount, error) {
+	var counts []ClassifierLabelCount
+	if err := r.db.WithContext(ctx).Model(&entities.ClassifierExample{}).
+		Select("label, COUNT(*) AS count").
+		Group("label").
+		Order("label").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count classifier labels: %w", err)
+	}
+	return counts, nil
+}
+
+// TrainingSet joins the labelled and reviewed clips with their embeddings.
+// A label takes precedence over a review of the same clip. A detection
+// reviewed correct is a positive for its species when that species is a
+// custom label, so confirmed custom classifier detections feed back into
+// training. Every other reviewed clip is a negative for all labels: a false
+// positive, or a confirmed detection of a sound that is no custom label.
+func (r *classifierExampleRepository) TrainingSet(ctx context.Context, model string) ([]TrainingExample, error) {
+	var examples []entities.ClassifierExample
+	if err := r.db.WithContext(ctx).Select("detection_id", "label").Find(&examples).Error; err != nil {
+		return nil, fmt.Errorf("failed to load classifier examples: %w", err)
+	}
+	var reviews []entities.DetectionReview
+	if err := r.db.WithContext(ctx).Select("detection_id", "verified").Find(&reviews).Error; err != nil {
+		return nil, fmt.Errorf("failed to load detection reviews: %w", err)
+	}
+
+	labels := make(map[uint]string, len(examples)+len(reviews))
+	var confirmed []uint
+	for i := range reviews {
+		switch reviews[i].Verified {
+		case entities.VerificationCorrect:
+			labels[reviews[i].DetectionID] = ""
+			confirmed = append(confirmed, reviews[i].DetectionID)
+		case entities.VerificationFalsePositive:
+			labels[reviews[i].DetectionID] = ""
+		}
+	}
+	positives, err := r.confirmedCustomLabels(ctx, confirmed, examples)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(labels, positives)
+	for i := range examples {
+		labels[examples[i].DetectionID] = examples[i].Label
+	}
+	ids := make([]uint, 0, len(labels))
+	for id := range labels {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	set := make([]TrainingExample, 0, len(ids))
+	for chunk := range slices.Chunk(ids, trainingSetBatch) {
+		var rows []entities.DetectionEmbedding
+		if err := r.db.WithContext(ctx).
+			Select("detection_id", "scale", "vector").
+			Where("model = ? AND detection_id IN ?", model, chunk).
+			Order("detection_id").
+			Find(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to load training embeddings: %w", err)
+		}
+		for i := range rows {
+			set = append(set, TrainingExample{
+				DetectionID: rows[i].DetectionID,
+				Label:       labels[rows[i].DetectionID],
+				Vector:      dequantizeEmbedding(rows[i].Scale, rows[i].Vector),
+			})
+		}
+	}
+	return set, nil
+}
+
+// confirmedCustomLabels maps the detections among ids whose species is one of
+// the custom labels in examples to that label. Custom classifier detections
+// are stored under a label whose scientific name is the custom label.
+func (r *classifierExampleRepository) confirmedCustomLabels(ctx context.Context, ids []uint, examples []entities.ClassifierExample) (map[uint]string, error) {
+	names := make([]string, 0, len(examples))
+	for i := range examples {
+		names = append(names, examples[i].Label)
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+	if len(ids) == 0 || len(names) == 0 {
+		return nil, nil
+	}
+
+	var labelRows []entities.Label
+	if err := r.db.WithContext(ctx).
+		Select("id", "scientific_name").
+		Where("scientific_name IN ?", names).
+		Find(&labelRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load custom classifier labels: %w", err)
+	}
+	if len(labelRows) == 0 {
+		return nil, nil
+	}
+	labelNames := make(map[uint]string, len(labelRows))
+	labelIDs := make([]uint, 0, len(labelRows))
+	for i := range labelRows {
+		labelNames[labelRows[i].ID] = labelRows[i].ScientificName
+		labelIDs = append(labelIDs, labelRows[i].ID)
+	}
+
+	positives := make(map[uint]string)
+	for chunk := range slices.Chunk(ids, trainingSetBatch) {
+		var detections []entities.Detection
+		if err := r.db.WithContext(ctx).
+			Select("id", "label_id").
+			Where("id IN ? AND label_id IN ?", chunk, labelIDs).
+			Find(&detections).Error; err != nil {
+			return nil, fmt.Errorf("failed to load confirmed detections: %w", err)
+		}
+		for i := range detections {
+			positives[detections[i].ID] = labelNames[detections[i].LabelID]
+		}
+	}
+	return positives, nil
+}
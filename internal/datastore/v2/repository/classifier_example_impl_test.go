@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+func setupClassifierExampleTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+
+	require.NoError(t, db.AutoMigrate(&entities.ClassifierExample{}, &entities.DetectionEmbedding{}, &entities.DetectionReview{},
+		&entities.Label{}, &entities.Detection{}))
+	return db
+}
+
+func TestClassifierExampleRepository_SetListDelete(t *testing.T) {
+	t.Parallel()
+	repo := NewClassifierExampleRepository(setupClassifierExampleTestDB(t), nil)
+	ctx := t.Context()
+
+	require.NoError(t, repo.Set(ctx, 1, "Parrot", "alice"))
+	require.NoError(t, repo.Set(ctx, 2, "Pump", ""))
+	require.NoError(t, repo.Set(ctx, 3, "Parrot", ""))
+	// Relabelling replaces the label instead of failing on the key.
+	require.NoError(t, repo.Set(ctx, 2, "Parrot", "bob"))
+
+	rows, err := repo.List(ctx, "Parrot")
+	require.NoError(t, err)
+	assert.Len(t, rows, 3)
+
+	counts, err := repo.LabelCounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []ClassifierLabelCount{{Label: "Parrot", Count: 3}}, counts)
+
+	require.NoError(t, repo.Delete(ctx, 2))
+	require.ErrorIs(t, repo.Delete(ctx, 2), ErrClassifierExampleNotFound)
+
+	rows, err = repo.List(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, rows, 2)
+
+	require.Error(t, repo.Set(ctx, 4, "", ""), "empty label")
+	require.Error(t, repo.Set(ctx, 0, "Parrot", ""), "zero detection ID")
+}
+
+func TestClassifierExampleRepository_TrainingSet(t *testing.T) {
+	t.Parallel()
+	db := setupClassifierExampleTestDB(t)
+	repo := NewClassifierExampleRepository(db, nil)
+	embeddings := NewEmbeddingRepository(db, nil)
+	ctx := t.Context()
+
+	for id := uint(1); id <= 5; id++ {
+		require.NoError(t, embeddings.Save(ctx, id, "birdnet-v24", []float32{float32(id), 1, 0}))
+	}
+	require.NoError(t, embeddings.Save(ctx, 6, "other-model", []float32{1, 0, 0}))
+
+	// 1: labelled, 2: labelled and reviewed, 3: reviewed only,
+	// 4: untouched, 5: labelled but no embedding from the model, 6: other model.
+	require.NoError(t, repo.Set(ctx, 1, "Parrot", ""))
+	require.NoError(t, repo.Set(ctx, 2, "Pump", ""))
+	require.NoError(t, repo.Set(ctx, 6, "Parrot", ""))
+	require.NoError(t, db.Create(&entities.DetectionReview{DetectionID: 2, Verified: entities.VerificationCorrect}).Error)
+	require.NoError(t, db.Create(&entities.DetectionReview{DetectionID: 3, Verified: entities.VerificationFalsePositive}).Error)
+	require.NoError(t, db.Where("detection_id = ?", 5).Delete(&entities.DetectionEmbedding{}).Error)
+	require.NoError(t, repo.Set(ctx, 5, "Parrot", ""))
+
+	set, err := repo.TrainingSet(ctx, "birdnet-v24")
+	require.NoError(t, err)
+	require.Len(t, set, 3)
+	assert.Equal(t, uint(1), set[0].DetectionID)
+	assert.Equal(t, "Parrot", set[0].Label)
+	assert.Equal(t, uint(2), set[1].DetectionID)
+	assert.Equal(t, "Pump", set[1].Label, "a label takes precedence over a review")
+	assert.Equal(t, uint(3), set[2].DetectionID)
+	assert.Empty(t, set[2].Label, "reviewed clips without a label are negatives")
+	assert.Len(t, set[0].Vector, 3)
+}
+
+func TestClassifierExampleRepository_TrainingSet_ReviewVerdicts(t *testing.T) {
+	t.Parallel()
+	db := setupClassifierExampleTestDB(t)
+	repo := NewClassifierExampleRepository(db, nil)
+	embeddings := NewEmbeddingRepository(db, nil)
+	ctx := t.Context()
+
+	parrot := &entities.Label{ScientificName: "Parrot", ModelID: 2, LabelTypeID: 1}
+	robin := &entities.Label{ScientificName: "Erithacus rubecula", ModelID: 1, LabelTypeID: 1}
+	require.NoError(t, db.Create(parrot).Error)
+	require.NoError(t, db.Create(robin).Error)
+
+	// 1: labelled Parrot, 2: Parrot detection confirmed, 3: Parrot detection
+	// rejected, 4: robin confirmed, 5: robin rejected.
+	labelOf := map[uint]uint{1: robin.ID, 2: parrot.ID, 3: parrot.ID, 4: robin.ID, 5: robin.ID}
+	for id := uint(1); id <= 5; id++ {
+		require.NoError(t, db.Create(&entities.Detection{ID: id, ModelID: 1, LabelID: labelOf[id], Confidence: 0.9, DetectedAt: int64(id)}).Error)
+		require.NoError(t, embeddings.Save(ctx, id, "birdnet-v24", []float32{float32(id), 1, 0}))
+	}
+	require.NoError(t, repo.Set(ctx, 1, "Parrot", ""))
+	reviews := map[uint]entities.VerificationStatus{
+		2: entities.VerificationCorrect,
+		3: entities.VerificationFalsePositive,
+		4: entities.VerificationCorrect,
+		5: entities.VerificationFalsePositive,
+	}
+	for id, verdict := range reviews {
+		require.NoError(t, db.Create(&entities.DetectionReview{DetectionID: id, Verified: verdict}).Error)
+	}
+
+	set, err := repo.TrainingSet(ctx, "birdnet-v24")
+	require.NoError(t, err)
+	got := make(map[uint]string, len(set))
+	for i := range set {
+		got[set[i].DetectionID] = set[i].Label
+	}
+	assert.Equal(t, map[uint]string{
+		1: "Parrot",
+		2: "Parrot", // confirmed custom label detection is a positive
+		3: "",       // false positive of the custom label is a negative
+		4: "",       // confirmed detection of another species is a negative
+		5: "",       // false positive of another species is a negative
+	}, got)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// ClipRedactionRepository records which stretches of saved clips the
+// privacy filter blanked.
+type ClipRedactionRepository interface {
+	// Save records the redacted segments of a detection's clip, replacing
+	// any earlier record.
+	Save(ctx context.Context, detectionID uint, fill string, segments []entities.RedactedSegment) error
+	// Get returns the redaction record of a detection's clip. Returns
+	// ErrClipRedactionNotFound if nothing was redacted.
+	Get(ctx context.Context, detectionID uint) (*entities.ClipRedaction, error)
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// clipRedactionRepository implements ClipRedactionRepository.
+type clipRedactionRepository struct {
+	db      *gorm.DB
+	metrics *datastore.Metrics
+}
+
+// NewClipRedactionRepository creates a new ClipRedactionRepository.
+// metrics is optional (nil-safe) and enables retry observability.
+func NewClipRedactionRepository(db *gorm.DB, metrics *datastore.Metrics) ClipRedactionRepository {
+	return &clipRedactionRepository{db: db, metrics: metrics}
+}
+
+// Save records the redacted segments of a detection's clip.
+func (r *clipRedactionRepository) Save(ctx context.Context, detectionID uint, fill string, segments []entities.RedactedSegment) error {
+	if detectionID == 0 {
+		return fmt.Errorf("detection ID cannot be zero")
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("redacted segments cannot be empty")
+	}
+	row := &entities.ClipRedaction{
+		DetectionID: detectionID,
+		Fill:        fill,
+		Segments:    segments,
+	}
+	return datastore.RetryOnLock(ctx, "v2_save_clip_redaction", func() error {
+		if err := r.db.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "detection_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"fill", "segments", "created_at"}),
+			}).
+			Create(row).Error; err != nil {
+			return fmt.Errorf("failed to save clip redaction: %w", err)
+		}
+		return nil
+	}, r.metrics)
+}
+
+// Get returns the redaction record of a detection's clip.
+func (r *clipRedactionRepository) Get(ctx context.Context, detectionID uint) (*entities.ClipRedaction, error) {
+	var row entities.ClipRedaction
+	if err := r.db.WithContext(ctx).Where("detection_id = ?", detectionID).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrClipRedactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get clip redaction: %w", err)
+	}
+	return &row, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+func setupClipRedactionTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+
+	require.NoError(t, db.AutoMigrate(&entities.ClipRedaction{}))
+	return db
+}
+
+func TestClipRedactionRepository_SaveAndGet(t *testing.T) {
+	t.Parallel()
+	repo := NewClipRedactionRepository(setupClipRedactionTestDB(t), nil)
+	ctx := t.Context()
+
+	_, err := repo.Get(ctx, 1)
+	require.ErrorIs(t, err, ErrClipRedactionNotFound)
+
+	segments := []entities.RedactedSegment{{Start: 0, End: 1.5}, {Start: 6, End: 9}}
+	require.NoError(t, repo.Save(ctx, 1, "silence", segments))
+
+	got, err := repo.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "silence", got.Fill)
+	assert.Equal(t, segments, got.Segments)
+
+	// Saving again replaces the record instead of failing on the key.
+	require.NoError(t, repo.Save(ctx, 1, "noise", segments[:1]))
+	got, err = repo.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "noise", got.Fill)
+	assert.Equal(t, segments[:1], got.Segments)
+
+	require.Error(t, repo.Save(ctx, 2, "silence", nil), "nothing redacted")
+	require.Error(t, repo.Save(ctx, 0, "silence", segments), "zero detection ID")
+}
@@ -209,9 +209,10 @@ type DetectionRepository interface {
 
 	// === Locks ===
 
-	// Lock prevents modification/deletion of a detection.
+	// Lock prevents modification/deletion of a detection. lockedBy records
+	// the username that placed the lock and may be empty for system locks.
 	// Returns ErrDetectionNotFound if detection doesn't exist.
-	Lock(ctx context.Context, detectionID uint) error
+	Lock(ctx context.Context, detectionID uint, lockedBy string) error
 
 	// Unlock removes the lock from a detection.
 	// This operation is idempotent — unlocking an already-unlocked detection succeeds silently.
@@ -36,6 +36,7 @@ type detectionRepository struct {
 	metrics     *datastore.Metrics
 	useV2Prefix bool
 	isMySQL     bool
+	isPostgres  bool
 }
 
 // NewDetectionRepository creates a new DetectionRepository.
@@ -44,15 +45,23 @@ type detectionRepository struct {
 //   - metrics: optional DatastoreMetrics for retry observability (nil-safe)
 //   - useV2Prefix: true to use v2_ table prefix (MySQL migration mode)
 //   - isMySQL: true for MySQL dialect (affects date/time SQL expressions)
+//
+// The PostgreSQL dialect is detected from the connection itself.
 func NewDetectionRepository(db *gorm.DB, metrics *datastore.Metrics, useV2Prefix, isMySQL bool) DetectionRepository {
 	return &detectionRepository{
 		db:          db,
 		metrics:     metrics,
 		useV2Prefix: useV2Prefix,
 		isMySQL:     isMySQL,
+		isPostgres:  isPostgresDB(db),
 	}
 }
 
+// isPostgresDB reports whether db is a PostgreSQL connection.
+func isPostgresDB(db *gorm.DB) bool {
+	return db != nil && db.Dialector != nil && db.Dialector.Name() == datastore.DialectPostgres
+}
+
 // Table name helpers
 func (r *detectionRepository) tableName() string {
 	if r.useV2Prefix {
@@ -129,13 +138,25 @@ func (r *detectionRepository) sourcesTable() string {
 // would apply that zone on top of the offset and double-count. Integer DIV avoids floating-point
 // rounding at exact day boundaries.
 //
-// SQLite: date(column + offset, 'unixepoch')
-// MySQL:  DATE_ADD('1970-01-01', INTERVAL (column + offset) DIV 86400 DAY)
+// SQLite:     date(column + offset, 'unixepoch')
+// MySQL:      DATE_ADD('1970-01-01', INTERVAL (column + offset) DIV 86400 DAY)
+// PostgreSQL: to_char(DATE '1970-01-01' + CAST((column + offset) / 86400 AS INTEGER), 'YYYY-MM-DD')
 func (r *detectionRepository) dateFromUnixExpr(column string, offsetSeconds int) string {
-	if r.isMySQL {
+	return dateFromUnixExpr(column, offsetSeconds, r.isMySQL, r.isPostgres)
+}
+
+// dateFromUnixExpr builds the dialect-specific date expression shared by the
+// detection and insights repositories. The PostgreSQL form returns text like
+// SQLite so callers scan the same "YYYY-MM-DD" strings.
+func dateFromUnixExpr(column string, offsetSeconds int, isMySQL, isPostgres bool) string {
+	switch {
+	case isMySQL:
 		return fmt.Sprintf("DATE_ADD('1970-01-01', INTERVAL (%s + %d) DIV 86400 DAY)", column, offsetSeconds)
+	case isPostgres:
+		return fmt.Sprintf("to_char(DATE '1970-01-01' + CAST((%s + %d) / 86400 AS INTEGER), 'YYYY-MM-DD')", column, offsetSeconds)
+	default:
+		return fmt.Sprintf("date(%s + %d, 'unixepoch')", column, offsetSeconds)
 	}
-	return fmt.Sprintf("date(%s + %d, 'unixepoch')", column, offsetSeconds)
 }
 
 // hourFromUnixExpr returns a SQL expression that extracts the wall-clock HOUR (0-23) of a
@@ -144,8 +165,8 @@ func (r *detectionRepository) dateFromUnixExpr(column string, offsetSeconds int)
 // timezone (unlike the older 'localtime'/FROM_UNIXTIME form). This mirrors the IncludedHours
 // search-filter expression. detected_at is always positive, so the integer division and
 // modulo stay non-negative even for negative (west-of-UTC) offsets.
-// SQLite: CAST((column + offset) / 3600 AS INTEGER) % 24
-// MySQL:  FLOOR((column + offset) / 3600) % 24
+// SQLite, PostgreSQL: CAST((column + offset) / 3600 AS INTEGER) % 24
+// MySQL:              FLOOR((column + offset) / 3600) % 24
 func (r *detectionRepository) hourFromUnixExpr(column string, offsetSeconds int) string {
 	if r.isMySQL {
 		return fmt.Sprintf("FLOOR((%s + %d) / 3600) %% 24", column, offsetSeconds)
@@ -659,6 +680,16 @@ func (r *detectionRepository) buildSearchFilters(query *gorm.DB, filters *Search
 	return query
 }
 
+// likeOperator returns the case-insensitive pattern match operator. LIKE is
+// case-insensitive for ASCII on SQLite and MySQL's default collation, but
+// case-sensitive on PostgreSQL.
+func (r *detectionRepository) likeOperator() string {
+	if r.isPostgres {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
+
 // buildSearchJoins applies JOIN clauses for filters requiring related tables.
 func (r *detectionRepository) buildSearchJoins(query *gorm.DB, filters *SearchFilters) *gorm.DB {
 	// Text search: scientific name LIKE (unbounded, requires labels join) OR-ed with
@@ -674,12 +705,12 @@ func (r *detectionRepository) buildSearchJoins(query *gorm.DB, filters *SearchFi
 		// labels first and index-join into detections.
 		query = query.Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.label_id",
 			r.labelsTable(), r.labelsTable(), r.tableName())).
-			Where(fmt.Sprintf("(%s.scientific_name LIKE ? OR %s.id IN ?)",
-				r.labelsTable(), r.labelsTable()), "%"+filters.Query+"%", filters.CommonLabelIDs)
+			Where(fmt.Sprintf("(%s.scientific_name %s ? OR %s.id IN ?)",
+				r.labelsTable(), r.likeOperator(), r.labelsTable()), "%"+filters.Query+"%", filters.CommonLabelIDs)
 	case hasQuery:
 		query = query.Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.label_id",
 			r.labelsTable(), r.labelsTable(), r.tableName())).
-			Where(fmt.Sprintf("%s.scientific_name LIKE ?", r.labelsTable()), "%"+filters.Query+"%")
+			Where(fmt.Sprintf("%s.scientific_name %s ?", r.labelsTable(), r.likeOperator()), "%"+filters.Query+"%")
 	case hasCommon:
 		query = query.Where(fmt.Sprintf("%s.label_id IN ?", r.tableName()), filters.CommonLabelIDs)
 	}
@@ -704,6 +735,14 @@ func (r *detectionRepository) buildSearchJoins(query *gorm.DB, filters *SearchFi
 		}
 	}
 
+	// False-positive exclusion (detections without a review are kept)
+	if filters.ExcludeFalsePositives {
+		query = query.Where(fmt.Sprintf(
+			"NOT EXISTS (SELECT 1 FROM %s WHERE %s.detection_id = %s.id AND %s.verified = ?)",
+			r.reviewsTable(), r.reviewsTable(), r.tableName(), r.reviewsTable()),
+			string(entities.VerificationFalsePositive))
+	}
+
 	// Locked filter (requires locks join)
 	if filters.IsLocked != nil {
 		if *filters.IsLocked {
@@ -751,6 +790,9 @@ func (r *detectionRepository) applySearchOrdering(query *gorm.DB, filters *Searc
 	default: // SortFieldDetectedAt or empty
 		query = query.Order("detected_at" + dir)
 	}
+	// Tiebreak on ID so rows sharing a sort value keep a stable order across
+	// pages; otherwise offset pagination may repeat or skip them.
+	query = query.Order(r.tableName() + ".id" + dir)
 
 	// Pagination
 	if filters.Limit > 0 {
@@ -1260,7 +1302,7 @@ func (r *detectionRepository) DeletePredictions(ctx context.Context, detectionID
 
 // SaveReview creates or updates a review for a detection.
 // Uses GORM's clause.OnConflict for dialect-aware upsert (SQLite + MySQL).
-// Idempotent: re-saving updates the verified status and timestamp.
+// Idempotent: re-saving updates the verified status, reviewer and timestamp.
 func (r *detectionRepository) SaveReview(ctx context.Context, review *entities.DetectionReview) error {
 	return datastore.RetryOnLock(ctx, "v2_save_review", func() error {
 		now := time.Now()
@@ -1269,8 +1311,9 @@ func (r *detectionRepository) SaveReview(ctx context.Context, review *entities.D
 			Clauses(clause.OnConflict{
 				Columns: []clause.Column{{Name: "detection_id"}},
 				DoUpdates: clause.Assignments(map[string]any{
-					"verified":   string(review.Verified),
-					"updated_at": now,
+					"verified":    string(review.Verified),
+					"reviewed_by": review.ReviewedBy,
+					"updated_at":  now,
 				}),
 			}).
 			Create(review).Error
@@ -1489,12 +1532,12 @@ func (r *detectionRepository) GetCommentsByDetectionIDs(ctx context.Context, det
 // Uses an atomic INSERT...SELECT...WHERE NOT EXISTS to avoid TOCTOU races.
 // Idempotent: locking an already-locked detection succeeds silently.
 // Returns ErrDetectionNotFound only if the detection does not exist.
-func (r *detectionRepository) Lock(ctx context.Context, detectionID uint) error {
+func (r *detectionRepository) Lock(ctx context.Context, detectionID uint, lockedBy string) error {
 	return datastore.RetryOnLock(ctx, "v2_lock_detection", func() error {
 		result := r.db.WithContext(ctx).Exec(
-			fmt.Sprintf("INSERT INTO %s (detection_id, locked_at) SELECT ?, CURRENT_TIMESTAMP FROM %s WHERE %s.id = ? AND NOT EXISTS (SELECT 1 FROM %s WHERE %s.detection_id = ?)",
+			fmt.Sprintf("INSERT INTO %s (detection_id, locked_at, locked_by) SELECT ?, CURRENT_TIMESTAMP, ? FROM %s WHERE %s.id = ? AND NOT EXISTS (SELECT 1 FROM %s WHERE %s.detection_id = ?)",
 				r.locksTable(), r.tableName(), r.tableName(), r.locksTable(), r.locksTable()),
-			detectionID, detectionID, detectionID)
+			detectionID, lockedBy, detectionID, detectionID)
 		if result.Error != nil {
 			return result.Error
 		}
@@ -1837,8 +1880,8 @@ func (r *detectionRepository) GetSourceActivitySummaries(ctx context.Context, st
 		Joins(fmt.Sprintf("JOIN %s s ON s.id = d.source_id", r.sourcesTable())).
 		// COUNT(DISTINCT d.id): the reviews LEFT JOIN is 1:1 so plain COUNT is already fan-out-immune
 		// today, but DISTINCT keeps the count correct if a future join introduces row multiplication.
-		Select("s.id as source_id, s.display_name as display_name, s.node_name as node_name, s.source_type as source_type, COUNT(DISTINCT d.id) as count").
-		Group("s.id, s.display_name, s.node_name, s.source_type").
+		Select("s.id as source_id, s.display_name as display_name, s.node_name as node_name, s.source_type as source_type, s.deployment_site as site, COUNT(DISTINCT d.id) as count").
+		Group("s.id, s.display_name, s.node_name, s.source_type, s.deployment_site").
 		Order("count DESC, s.id ASC").
 		Scan(&results).Error
 
@@ -427,7 +427,7 @@ func TestLock_ExistingUnlocked(t *testing.T) {
 	repo := &detectionRepository{db: db}
 	det := createTestDetection(t, db, 1000)
 
-	err := repo.Lock(ctx, det.ID)
+	err := repo.Lock(ctx, det.ID, "")
 	require.NoError(t, err)
 
 	locked, err := repo.IsLocked(ctx, det.ID)
@@ -442,10 +442,10 @@ func TestLock_AlreadyLockedIsIdempotent(t *testing.T) {
 	repo := &detectionRepository{db: db}
 	det := createTestDetection(t, db, 1000)
 
-	require.NoError(t, repo.Lock(ctx, det.ID))
+	require.NoError(t, repo.Lock(ctx, det.ID, ""))
 
 	// Second lock should succeed silently
-	err := repo.Lock(ctx, det.ID)
+	err := repo.Lock(ctx, det.ID, "")
 	require.NoError(t, err)
 
 	// Should still be exactly one lock row
@@ -460,7 +460,7 @@ func TestLock_NonexistentDetection(t *testing.T) {
 
 	repo := &detectionRepository{db: db}
 
-	err := repo.Lock(ctx, 99999)
+	err := repo.Lock(ctx, 99999, "")
 	require.ErrorIs(t, err, ErrDetectionNotFound)
 }
 
@@ -477,7 +477,7 @@ func TestLock_ConcurrentCallsNoDuplicates(t *testing.T) {
 
 	for range goroutines {
 		wg.Go(func() {
-			errs <- repo.Lock(ctx, det.ID)
+			errs <- repo.Lock(ctx, det.ID, "")
 		})
 	}
 	wg.Wait()
@@ -898,3 +898,49 @@ func TestGetTopSpecies_NoLimitWhenNonPositive(t *testing.T) {
 	assert.Equal(t, labelA.ID, all[0].LabelID)
 	assert.Equal(t, labelC.ID, all[2].LabelID)
 }
+
+func TestSearch_ExcludeFalsePositives(t *testing.T) {
+	db := setupDetectionTestDB(t)
+	ctx := t.Context()
+	repo := &detectionRepository{db: db}
+
+	correct := createTestDetection(t, db, 1000)
+	falsePositive := createTestDetection(t, db, 1001)
+	unreviewed := createTestDetection(t, db, 1002)
+	require.NoError(t, db.Table(tableDetectionReviews).Create(&[]entities.DetectionReview{
+		{DetectionID: correct.ID, Verified: entities.VerificationCorrect},
+		{DetectionID: falsePositive.ID, Verified: entities.VerificationFalsePositive},
+	}).Error)
+
+	results, total, err := repo.Search(ctx, &SearchFilters{ExcludeFalsePositives: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, results, 2)
+	assert.Equal(t, correct.ID, results[0].ID)
+	assert.Equal(t, unreviewed.ID, results[1].ID)
+}
+
+// TestSearch_PagesTiesByID verifies detections sharing a sort value are paged
+// in ID order, so offset pagination neither repeats nor skips them.
+func TestSearch_PagesTiesByID(t *testing.T) {
+	db := setupDetectionTestDB(t)
+	ctx := t.Context()
+	repo := &detectionRepository{db: db}
+
+	ids := make([]uint, 5)
+	for i := range ids {
+		ids[i] = createTestDetection(t, db, 1000).ID
+	}
+
+	var got []uint
+	for offset := 0; offset < len(ids); offset += 2 {
+		results, _, err := repo.Search(ctx, &SearchFilters{
+			SortBy: SortFieldDetectedAt, SortDesc: true, Limit: 2, Offset: offset,
+		})
+		require.NoError(t, err)
+		for _, d := range results {
+			got = append(got, d.ID)
+		}
+	}
+	assert.Equal(t, []uint{ids[4], ids[3], ids[2], ids[1], ids[0]}, got)
+}
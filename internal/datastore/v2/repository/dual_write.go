@@ -694,9 +694,10 @@ func (dw *DualWriteRepository) GetHourly(ctx context.Context, date, hour string,
 	return dw.legacy.GetHourly(ctx, date, hour, duration, limit, offset)
 }
 
-// Lock prevents modification of a detection.
-func (dw *DualWriteRepository) Lock(ctx context.Context, id string) error {
-	if err := dw.legacy.Lock(ctx, id); err != nil {
+// Lock prevents modification of a detection. lockedBy is recorded in both
+// databases.
+func (dw *DualWriteRepository) Lock(ctx context.Context, id, lockedBy string) error {
+	if err := dw.legacy.Lock(ctx, id, lockedBy); err != nil {
 		return err
 	}
 
@@ -708,7 +709,7 @@ func (dw *DualWriteRepository) Lock(ctx context.Context, id string) error {
 	if dualWrite {
 		uid, err := parseDetectionID(id)
 		if err == nil {
-			if err := dw.v2.Lock(ctx, uid); err != nil {
+			if err := dw.v2.Lock(ctx, uid, lockedBy); err != nil {
 				dw.logger.Warn("v2 lock failed", logger.String("id", id), logger.Error(err))
 			}
 		}
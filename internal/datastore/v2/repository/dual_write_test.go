@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"io"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -9,6 +11,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+	v2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
 
@@ -237,3 +242,43 @@ func TestReconciliation_ShutdownWithoutStart(t *testing.T) {
 		dw.Shutdown()
 	})
 }
+
+func TestDualWrite_LockRecordsLockedBy(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := v2.NewSQLiteManager(v2.Config{DataDir: t.TempDir()})
+	require.NoError(t, err)
+	require.NoError(t, mgr.Initialize())
+	t.Cleanup(func() { _ = mgr.Close() })
+
+	stateManager := v2.NewStateManager(mgr.DB())
+	require.NoError(t, stateManager.StartMigration(1))
+	require.NoError(t, stateManager.TransitionToDualWrite())
+
+	db := mgr.DB()
+	var model entities.AIModel
+	require.NoError(t, db.First(&model).Error)
+	var labelType entities.LabelType
+	require.NoError(t, db.First(&labelType).Error)
+	label := entities.Label{ScientificName: "Turdus merula", ModelID: model.ID, LabelTypeID: labelType.ID}
+	require.NoError(t, db.Create(&label).Error)
+	det := &entities.Detection{LabelID: label.ID, ModelID: model.ID, Confidence: 0.9, DetectedAt: 1000}
+	require.NoError(t, db.Table(tableDetections).Create(det).Error)
+	id := strconv.FormatUint(uint64(det.ID), 10)
+
+	ctx := context.Background()
+	legacy := mocks.NewMockDetectionRepository(t)
+	legacy.EXPECT().Lock(ctx, id, "alice").Return(nil)
+
+	dw := NewDualWriteRepository(&DualWriteConfig{
+		Legacy:       legacy,
+		V2:           NewDetectionRepository(db, nil, false, false),
+		StateManager: stateManager,
+		Logger:       testLogger(),
+	})
+	require.NoError(t, dw.Lock(ctx, id, "alice"))
+
+	var lock entities.DetectionLock
+	require.NoError(t, db.Where("detection_id = ?", det.ID).First(&lock).Error)
+	assert.Equal(t, "alice", lock.LockedBy)
+}
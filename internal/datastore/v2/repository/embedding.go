@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+)
+
+// EmbeddingMatch is one result of a nearest neighbour search.
+type EmbeddingMatch struct {
+	DetectionID uint
+	Similarity  float64 // cosine similarity, 1 for identical clips
+}
+
+// EmbeddingRepository stores clip embedding vectors and answers nearest
+// neighbour queries over them. The index is a brute-force scan of every
+// vector for the model, which stays fast enough for a single station's
+// detection history and needs no index maintenance.
+type EmbeddingRepository interface {
+	// Save stores the embedding for a detection, replacing any earlier one.
+	// The vector is normalized and quantized before storage.
+	Save(ctx context.Context, detectionID uint, model string, vector []float32) error
+	// Get returns the stored (normalized) vector and its model. Returns
+	// ErrEmbeddingNotFound if the detection has no embedding.
+	Get(ctx context.Context, detectionID uint) (vector []float32, model string, err error)
+	// Nearest returns up to limit detections whose embeddings from the same
+	// model are most similar to vector, best match first. excludeID is left
+	// out of the results so a query by detection does not return itself.
+	Nearest(ctx context.Context, model string, vector []float32, limit int, excludeID uint) ([]EmbeddingMatch, error)
+	// Count returns the number of stored embeddings.
+	Count(ctx context.Context) (int64, error)
+}
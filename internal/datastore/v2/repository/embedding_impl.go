@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// embeddingScanBatch is the number of vectors loaded per query while
+// scanning for nearest neighbours, bounding memory use on large histories.
+const embeddingScanBatch = 2000
+
+// embeddingRepository implements EmbeddingRepository.
+type embeddingRepository struct {
+	db      *gorm.DB
+	metrics *datastore.Metrics
+}
+
+// NewEmbeddingRepository creates a new EmbeddingRepository.
+// metrics is optional (nil-safe) and enables retry observability.
+func NewEmbeddingRepository(db *gorm.DB, metrics *datastore.Metrics) EmbeddingRepository {
+	return &embeddingRepository{db: db, metrics: metrics}
+}
+
+// Save stores the embedding for a detection, replacing any earlier one.
+func (r *embeddingRepository) Save(ctx context.Context, detectionID uint, model string, vector []float32) error {
+	if detectionID == 0 {
+		return fmt.Errorf("detection ID cannot be zero")
+	}
+	if model == "" {
+		return fmt.Errorf("embedding model cannot be empty")
+	}
+	scale, quantized, ok := quantizeEmbedding(vector)
+	if !ok {
+		return fmt.Errorf("embedding vector is empty or zero")
+	}
+	row := &entities.DetectionEmbedding{
+		DetectionID: detectionID,
+		Model:       model,
+		Dim:         len(vector),
+		Scale:       scale,
+		Vector:      quantized,
+	}
+	return datastore.RetryOnLock(ctx, "v2_save_embedding", func() error {
+		if err := r.db.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "detection_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"model", "dim", "scale", "vector", "created_at"}),
+			}).
+			Create(row).Error; err != nil {
+			return fmt.Errorf("failed to save embedding: %w", err)
+		}
+		return nil
+	}, r.metrics)
+}
+
+// Get returns the stored vector for a detection.
+func (r *embeddingRepository) Get(ctx context.Context, detectionID uint) (vector []float32, model string, err error) {
+	var row entities.DetectionEmbedding
+	if err := r.db.WithContext(ctx).Where("detection_id = ?", detectionID).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrEmbeddingNotFound
+		}
+		return nil, "", fmt.Errorf("failed to get embedding: %w", err)
+	}
+	return dequantizeEmbedding(row.Scale, row.Vector), row.Model, nil
+}
+
+// Nearest scans every vector of the model in detection ID order and keeps
+// the limit best matches.
+func (r *embeddingRepository) Nearest(ctx context.Context, model string, vector []float32, limit int, excludeID uint) ([]EmbeddingMatch, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	scale, query, ok := quantizeEmbedding(vector)
+	if !ok {
+		return nil, fmt.Errorf("embedding vector is empty or zero")
+	}
+
+	var best []EmbeddingMatch
+	var lastID uint
+	for {
+		var rows []entities.DetectionEmbedding
+		if err := r.db.WithContext(ctx).
+			Select("detection_id", "scale", "vector").
+			Where("model = ? AND dim = ? AND detection_id > ?", model, len(query), lastID).
+			Order("detection_id").
+			Limit(embeddingScanBatch).
+			Find(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to scan embeddings: %w", err)
+		}
+		for i := range rows {
+			if rows[i].DetectionID == excludeID || len(rows[i].Vector) != len(query) {
+				continue
+			}
+			sim := float64(scale) * float64(rows[i].Scale) * float64(dotInt8(query, rows[i].Vector))
+			best = insertMatch(best, EmbeddingMatch{DetectionID: rows[i].DetectionID, Similarity: sim}, limit)
+		}
+		if len(rows) < embeddingScanBatch {
+			return best, nil
+		}
+		lastID = rows[len(rows)-1].DetectionID
+	}
+}
+
+// Count returns the number of stored embeddings.
+func (r *embeddingRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.DetectionEmbedding{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count embeddings: %w", err)
+	}
+	return count, nil
+}
+
+// quantizeEmbedding L2-normalizes vector and maps it onto signed bytes. The
+// returned scale converts a byte back to its normalized value. ok is false
+// for an empty or all-zero vector, which has no direction to compare.
+func quantizeEmbedding(vector []float32) (scale float32, quantized []byte, ok bool) {
+	var norm, maxAbs float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+		maxAbs = math.Max(maxAbs, math.Abs(float64(v)))
+	}
+	if norm == 0 || math.IsNaN(norm) || math.IsInf(norm, 0) {
+		return 0, nil, false
+	}
+	norm = math.Sqrt(norm)
+	step := maxAbs / norm / math.MaxInt8
+	quantized = make([]byte, len(vector))
+	for i, v := range vector {
+		q := math.Round(float64(v) / norm / step)
+		quantized[i] = byte(int8(max(math.MinInt8+1, min(math.MaxInt8, q))))
+	}
+	return float32(step), quantized, true
+}
+
+// dequantizeEmbedding reverses quantizeEmbedding.
+func dequantizeEmbedding(scale float32, quantized []byte) []float32 {
+	vector := make([]float32, len(quantized))
+	for i, q := range quantized {
+		vector[i] = float32(int8(q)) * scale
+	}
+	return vector
+}
+
+// dotInt8 returns the dot product of two quantized vectors of equal length.
+func dotInt8(a, b []byte) int32 {
+	var sum int32
+	for i := range a {
+		sum += int32(int8(a[i])) * int32(int8(b[i]))
+	}
+	return sum
+}
+
+// insertMatch adds m to the descending list best, keeping at most limit
+// entries.
+func insertMatch(best []EmbeddingMatch, m EmbeddingMatch, limit int) []EmbeddingMatch {
+	if len(best) == limit && m.Similarity <= best[len(best)-1].Similarity {
+		return best
+	}
+	i := sort.Search(len(best), func(i int) bool { return best[i].Similarity < m.Similarity })
+	if len(best) < limit {
+		best = append(best, EmbeddingMatch{})
+	}
+	copy(best[i+1:], best[i:])
+	best[i] = m
+	return best
+}
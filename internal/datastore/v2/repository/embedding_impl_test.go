@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+func setupEmbeddingTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+
+	require.NoError(t, db.AutoMigrate(&entities.DetectionEmbedding{}))
+	return db
+}
+
+func TestEmbeddingRepository_SaveAndGet(t *testing.T) {
+	t.Parallel()
+	repo := NewEmbeddingRepository(setupEmbeddingTestDB(t), nil)
+	ctx := t.Context()
+
+	require.NoError(t, repo.Save(ctx, 1, "birdnet-v24", []float32{3, 4, 0}))
+
+	vec, model, err := repo.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "birdnet-v24", model)
+	require.Len(t, vec, 3)
+	// Stored vectors are normalized; quantization keeps them within a step.
+	assert.InDelta(t, 0.6, vec[0], 0.01)
+	assert.InDelta(t, 0.8, vec[1], 0.01)
+	assert.InDelta(t, 0.0, vec[2], 0.01)
+
+	// Saving again replaces the vector instead of failing on the key.
+	require.NoError(t, repo.Save(ctx, 1, "birdnet-v24", []float32{0, 0, 2}))
+	vec, _, err = repo.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, vec[2], 0.01)
+
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	_, _, err = repo.Get(ctx, 2)
+	require.ErrorIs(t, err, ErrEmbeddingNotFound)
+
+	require.Error(t, repo.Save(ctx, 3, "birdnet-v24", []float32{0, 0, 0}), "zero vector has no direction")
+	require.Error(t, repo.Save(ctx, 0, "birdnet-v24", []float32{1}), "detection ID is required")
+}
+
+func TestEmbeddingRepository_Nearest(t *testing.T) {
+	t.Parallel()
+	repo := NewEmbeddingRepository(setupEmbeddingTestDB(t), nil)
+	ctx := t.Context()
+
+	vectors := map[uint][]float32{
+		1: {1, 0, 0, 0},
+		2: {0.9, 0.1, 0, 0},
+		3: {0.5, 0.5, 0, 0},
+		4: {0, 1, 0, 0},
+		5: {-1, 0, 0, 0},
+	}
+	for id, v := range vectors {
+		require.NoError(t, repo.Save(ctx, id, "birdnet-v24", v))
+	}
+	// Vectors from another model or dimension are never compared.
+	require.NoError(t, repo.Save(ctx, 6, "other-model", []float32{1, 0, 0, 0}))
+	require.NoError(t, repo.Save(ctx, 7, "birdnet-v24", []float32{1, 0, 0}))
+
+	matches, err := repo.Nearest(ctx, "birdnet-v24", []float32{1, 0, 0, 0}, 3, 1)
+	require.NoError(t, err)
+	require.Len(t, matches, 3)
+	assert.Equal(t, uint(2), matches[0].DetectionID)
+	assert.Equal(t, uint(3), matches[1].DetectionID)
+	assert.Equal(t, uint(4), matches[2].DetectionID)
+	assert.InDelta(t, 0.994, matches[0].Similarity, 0.01)
+	assert.InDelta(t, 0.707, matches[1].Similarity, 0.01)
+	assert.InDelta(t, 0.0, matches[2].Similarity, 0.01)
+
+	matches, err = repo.Nearest(ctx, "birdnet-v24", []float32{1, 0, 0, 0}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, matches, 5)
+	assert.Equal(t, uint(1), matches[0].DetectionID)
+	assert.Equal(t, uint(5), matches[4].DetectionID)
+	assert.InDelta(t, -1.0, matches[4].Similarity, 0.01)
+}
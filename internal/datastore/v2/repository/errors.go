@@ -71,6 +71,35 @@ var (
 	// ErrAlertRuleNotFound indicates the requested alert rule does not exist.
 	ErrAlertRuleNotFound = errors.NewStd("alert rule not found")
 
+	// ErrUserAccountNotFound indicates the requested user account does not exist.
+	ErrUserAccountNotFound = errors.NewStd("user account not found")
+
+	// ErrAPIKeyNotFound indicates the requested API key does not exist.
+	ErrAPIKeyNotFound = errors.NewStd("API key not found")
+
+	// ErrOutboxItemNotFound indicates the requested outbox item does not exist.
+	ErrOutboxItemNotFound = errors.NewStd("outbox item not found")
+
+	// ErrSoundscapeSegmentNotFound indicates the requested soundscape segment
+	// does not exist.
+	ErrSoundscapeSegmentNotFound = errors.NewStd("soundscape segment not found")
+
+	// ErrArchivedClipNotFound indicates the clip has not been moved to the
+	// secondary store.
+	ErrArchivedClipNotFound = errors.NewStd("archived clip not found")
+
+	// ErrEmbeddingNotFound indicates no embedding has been stored for the
+	// detection.
+	ErrEmbeddingNotFound = errors.NewStd("detection embedding not found")
+
+	// ErrClassifierExampleNotFound indicates the detection has no custom
+	// classifier label.
+	ErrClassifierExampleNotFound = errors.NewStd("classifier example not found")
+
+	// ErrClipRedactionNotFound indicates no voice was redacted from the
+	// detection's clip.
+	ErrClipRedactionNotFound = errors.NewStd("clip redaction not found")
+
 	// ErrCommonNameSearchUnsupported indicates a free-text query reached the
 	// dual-write read path, which has no name-map source to resolve common names
 	// to label IDs. Honoring the query would silently degrade to scientific-name-only
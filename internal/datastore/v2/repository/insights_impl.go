@@ -12,6 +12,7 @@ type insightsRepository struct {
 	db          *gorm.DB
 	useV2Prefix bool
 	isMySQL     bool
+	isPostgres  bool
 }
 
 // NewInsightsRepository creates a new insights repository.
@@ -20,6 +21,7 @@ func NewInsightsRepository(db *gorm.DB, useV2Prefix, isMySQL bool) InsightsRepos
 		db:          db,
 		useV2Prefix: useV2Prefix,
 		isMySQL:     isMySQL,
+		isPostgres:  isPostgresDB(db),
 	}
 }
 
@@ -53,18 +55,16 @@ func (r *insightsRepository) reviewsTable() string {
 // arithmetic stays non-negative even for west-of-UTC (negative) offsets.
 
 // dateFromUnixExpr returns a SQL expression for the wall-clock calendar date (YYYY-MM-DD).
-// SQLite: date(column + offset, 'unixepoch')
-// MySQL:  DATE_ADD('1970-01-01', INTERVAL (column + offset) DIV 86400 DAY)
+// SQLite:     date(column + offset, 'unixepoch')
+// MySQL:      DATE_ADD('1970-01-01', INTERVAL (column + offset) DIV 86400 DAY)
+// PostgreSQL: to_char(DATE '1970-01-01' + ..., 'YYYY-MM-DD'), see dateFromUnixExpr
 //
 // The MySQL form computes the civil date arithmetically (DATE_ADD on a literal date with an
 // integer day count) so it does not depend on the session time_zone; DATE(FROM_UNIXTIME(...))
 // would apply the session zone on top of the offset and double-count. Integer DIV avoids any
 // floating-point rounding at exact day boundaries.
 func (r *insightsRepository) dateFromUnixExpr(column string, offsetSeconds int) string {
-	if r.isMySQL {
-		return fmt.Sprintf("DATE_ADD('1970-01-01', INTERVAL (%s + %d) DIV 86400 DAY)", column, offsetSeconds)
-	}
-	return fmt.Sprintf("date(%s + %d, 'unixepoch')", column, offsetSeconds)
+	return dateFromUnixExpr(column, offsetSeconds, r.isMySQL, r.isPostgres)
 }
 
 func (r *insightsRepository) hourFromUnixExpr(column string, offsetSeconds int) string {
@@ -78,6 +78,9 @@ func (r *insightsRepository) yearFromUnixExpr(column string, offsetSeconds int)
 	if r.isMySQL {
 		return fmt.Sprintf("YEAR(DATE_ADD('1970-01-01', INTERVAL (%s + %d) DIV 86400 DAY))", column, offsetSeconds)
 	}
+	if r.isPostgres {
+		return fmt.Sprintf("CAST(EXTRACT(YEAR FROM DATE '1970-01-01' + CAST((%s + %d) / 86400 AS INTEGER)) AS INTEGER)", column, offsetSeconds)
+	}
 	return fmt.Sprintf("CAST(strftime('%%Y', %s + %d, 'unixepoch') AS INTEGER)", column, offsetSeconds)
 }
 
@@ -588,6 +588,53 @@ func (_c *MockAlertRuleRepository_ListRules_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// SaveActionResult provides a mock function with given fields: ctx, result
+func (_m *MockAlertRuleRepository) SaveActionResult(ctx context.Context, result *entities.AlertActionResult) error {
+	ret := _m.Called(ctx, result)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveActionResult")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.AlertActionResult) error); ok {
+		r0 = rf(ctx, result)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAlertRuleRepository_SaveActionResult_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveActionResult'
+type MockAlertRuleRepository_SaveActionResult_Call struct {
+	*mock.Call
+}
+
+// SaveActionResult is a helper method to define mock.On call
+//   - ctx context.Context
+//   - result *entities.AlertActionResult
+func (_e *MockAlertRuleRepository_Expecter) SaveActionResult(ctx interface{}, result interface{}) *MockAlertRuleRepository_SaveActionResult_Call {
+	return &MockAlertRuleRepository_SaveActionResult_Call{Call: _e.mock.On("SaveActionResult", ctx, result)}
+}
+
+func (_c *MockAlertRuleRepository_SaveActionResult_Call) Run(run func(ctx context.Context, result *entities.AlertActionResult)) *MockAlertRuleRepository_SaveActionResult_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entities.AlertActionResult))
+	})
+	return _c
+}
+
+func (_c *MockAlertRuleRepository_SaveActionResult_Call) Return(_a0 error) *MockAlertRuleRepository_SaveActionResult_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAlertRuleRepository_SaveActionResult_Call) RunAndReturn(run func(context.Context, *entities.AlertActionResult) error) *MockAlertRuleRepository_SaveActionResult_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveHistory provides a mock function with given fields: ctx, history
 func (_m *MockAlertRuleRepository) SaveHistory(ctx context.Context, history *entities.AlertHistory) error {
 	ret := _m.Called(ctx, history)
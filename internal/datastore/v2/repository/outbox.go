@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// OutboxFilter selects outbox items for listing. Empty fields match all items.
+type OutboxFilter struct {
+	Destination string
+	Status      entities.OutboxStatus
+	Limit       int
+	Offset      int
+}
+
+// OutboxStat counts the outbox items of one destination in one status.
+type OutboxStat struct {
+	Destination string                `json:"destination"`
+	Status      entities.OutboxStatus `json:"status"`
+	Count       int64                 `json:"count"`
+}
+
+// OutboxRepository handles the durable delivery outbox.
+type OutboxRepository interface {
+	// Create queues a new item.
+	Create(ctx context.Context, item *entities.OutboxItem) error
+	// GetByID returns ErrOutboxItemNotFound if the item does not exist.
+	GetByID(ctx context.Context, id uint) (*entities.OutboxItem, error)
+	// List returns items matching the filter, oldest first, without payloads.
+	List(ctx context.Context, filter OutboxFilter) ([]entities.OutboxItem, error)
+	// Stats counts the items per destination and status.
+	Stats(ctx context.Context) ([]OutboxStat, error)
+	// PendingHeads returns the oldest pending item of every destination.
+	PendingHeads(ctx context.Context) ([]entities.OutboxItem, error)
+	// RecordFailure stores a failed attempt and schedules the next one.
+	RecordFailure(ctx context.Context, id uint, attempts int, next time.Time, lastErr string) error
+	// MarkDead moves an item to the dead-letter state.
+	MarkDead(ctx context.Context, id uint, attempts int, lastErr string) error
+	// Retry puts an item back in the pending state with a fresh attempt
+	// budget, due at the given time. Returns ErrOutboxItemNotFound if the
+	// item does not exist.
+	Retry(ctx context.Context, id uint, at time.Time) error
+	// RetryDead retries every dead item of a destination, or of all
+	// destinations when destination is empty. Returns the number of items.
+	RetryDead(ctx context.Context, destination string, at time.Time) (int64, error)
+	// Delete removes an item. Returns ErrOutboxItemNotFound if the item does
+	// not exist.
+	Delete(ctx context.Context, id uint) error
+	// Purge removes the items of a destination in a status. Empty arguments
+	// match all destinations or statuses. Returns the number of items removed.
+	Purge(ctx context.Context, destination string, status entities.OutboxStatus) (int64, error)
+}
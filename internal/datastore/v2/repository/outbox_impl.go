@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+)
+
+// outboxRepository implements OutboxRepository.
+type outboxRepository struct {
+	db      *gorm.DB
+	metrics *datastore.Metrics
+}
+
+// NewOutboxRepository creates a new OutboxRepository.
+// metrics is optional (nil-safe) and enables retry observability.
+func NewOutboxRepository(db *gorm.DB, metrics *datastore.Metrics) OutboxRepository {
+	return &outboxRepository{db: db, metrics: metrics}
+}
+
+// Create queues a new item.
+func (r *outboxRepository) Create(ctx context.Context, item *entities.OutboxItem) error {
+	if item == nil {
+		return fmt.Errorf("outbox item cannot be nil")
+	}
+	return datastore.RetryOnLock(ctx, "v2_create_outbox_item", func() error {
+		item.ID = 0 // Reset ID for retry safety
+		if err := r.db.WithContext(ctx).Create(item).Error; err != nil {
+			return fmt.Errorf("failed to create outbox item: %w", err)
+		}
+		return nil
+	}, r.metrics)
+}
+
+// GetByID returns a single item, including its payload.
+func (r *outboxRepository) GetByID(ctx context.Context, id uint) (*entities.OutboxItem, error) {
+	var item entities.OutboxItem
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOutboxItemNotFound
+		}
+		return nil, fmt.Errorf("failed to get outbox item: %w", err)
+	}
+	return &item, nil
+}
+
+// List returns items matching the filter, oldest first, without payloads.
+func (r *outboxRepository) List(ctx context.Context, filter OutboxFilter) ([]entities.OutboxItem, error) {
+	query := r.filtered(r.db.WithContext(ctx).Omit("payload"), filter.Destination, filter.Status).Order("id ASC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+	var items []entities.OutboxItem
+	if err := query.Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to list outbox items: %w", err)
+	}
+	return items, nil
+}
+
+// Stats counts the items per destination and status.
+func (r *outboxRepository) Stats(ctx context.Context) ([]OutboxStat, error) {
+	var stats []OutboxStat
+	if err := r.db.WithContext(ctx).Model(&entities.OutboxItem{}).
+		Select("destination, status, COUNT(*) AS count").
+		Group("destination, status").
+		Order("destination, status").
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to count outbox items: %w", err)
+	}
+	return stats, nil
+}
+
+// PendingHeads returns the oldest pending item of every destination.
+func (r *outboxRepository) PendingHeads(ctx context.Context) ([]entities.OutboxItem, error) {
+	heads := r.db.WithContext(ctx).Model(&entities.OutboxItem{}).
+		Select("MIN(id)").
+		Where("status = ?", entities.OutboxStatusPending).
+		Group("destination")
+	var items []entities.OutboxItem
+	if err := r.db.WithContext(ctx).Where("id IN (?)", heads).Order("id ASC").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to get pending outbox items: %w", err)
+	}
+	return items, nil
+}
+
+// RecordFailure stores a failed attempt and schedules the next one.
+func (r *outboxRepository) RecordFailure(ctx context.Context, id uint, attempts int, next time.Time, lastErr string) error {
+	return r.update(ctx, "v2_outbox_record_failure", id, map[string]any{
+		"attempts":        attempts,
+		"next_attempt_at": next,
+		"last_error":      lastErr,
+	})
+}
+
+// MarkDead moves an item to the dead-letter state.
+func (r *outboxRepository) MarkDead(ctx context.Context, id uint, attempts int, lastErr string) error {
+	return r.update(ctx, "v2_outbox_mark_dead", id, map[string]any{
+		"status":     entities.OutboxStatusDead,
+		"attempts":   attempts,
+		"last_error": lastErr,
+	})
+}
+
+// Retry puts an item back in the pending state.
+func (r *outboxRepository) Retry(ctx context.Context, id uint, at time.Time) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return r.update(ctx, "v2_outbox_retry", id, map[string]any{
+		"status":          entities.OutboxStatusPending,
+		"attempts":        0,
+		"next_attempt_at": at,
+	})
+}
+
+// RetryDead retries every dead item of a destination.
+func (r *outboxRepository) RetryDead(ctx context.Context, destination string, at time.Time) (int64, error) {
+	var updated int64
+	err := datastore.RetryOnLock(ctx, "v2_outbox_retry_dead", func() error {
+		result := r.filtered(r.db.WithContext(ctx).Model(&entities.OutboxItem{}), destination, entities.OutboxStatusDead).
+			Updates(map[string]any{
+				"status":          entities.OutboxStatusPending,
+				"attempts":        0,
+				"next_attempt_at": at,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to retry dead outbox items: %w", result.Error)
+		}
+		updated = result.RowsAffected
+		return nil
+	}, r.metrics)
+	return updated, err
+}
+
+// Delete removes an item.
+func (r *outboxRepository) Delete(ctx context.Context, id uint) error {
+	var deleted int64
+	err := datastore.RetryOnLock(ctx, "v2_delete_outbox_item", func() error {
+		result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&entities.OutboxItem{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete outbox item %d: %w", id, result.Error)
+		}
+		deleted = result.RowsAffected
+		return nil
+	}, r.metrics)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrOutboxItemNotFound
+	}
+	return nil
+}
+
+// Purge removes the items of a destination in a status.
+func (r *outboxRepository) Purge(ctx context.Context, destination string, status entities.OutboxStatus) (int64, error) {
+	var deleted int64
+	err := datastore.RetryOnLock(ctx, "v2_purge_outbox_items", func() error {
+		// The always-true condition lets GORM run a delete without filters.
+		query := r.filtered(r.db.WithContext(ctx).Where("1 = 1"), destination, status)
+		result := query.Delete(&entities.OutboxItem{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge outbox items: %w", result.Error)
+		}
+		deleted = result.RowsAffected
+		return nil
+	}, r.metrics)
+	return deleted, err
+}
+
+// filtered narrows query to a destination and status; empty values match all.
+func (r *outboxRepository) filtered(query *gorm.DB, destination string, status entities.OutboxStatus) *gorm.DB {
+	if destination != "" {
+		query = query.Where("destination = ?", destination)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return query
+}
+
+// update applies column updates to a single item.
+func (r *outboxRepository) update(ctx context.Context, operation string, id uint, columns map[string]any) error {
+	return datastore.RetryOnLock(ctx, operation, func() error {
+		if err := r.db.WithContext(ctx).Model(&entities.OutboxItem{}).
+			Where("id = ?", id).Updates(columns).Error; err != nil {
+			return fmt.Errorf("failed to update outbox item %d: %w", id, err)
+		}
+		return nil
+	}, r.metrics)
+}
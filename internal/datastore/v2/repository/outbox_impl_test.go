@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+func setupOutboxTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+
+	require.NoError(t, db.AutoMigrate(&entities.OutboxItem{}))
+	return db
+}
+
+func newOutboxItem(destination string) *entities.OutboxItem {
+	return &entities.OutboxItem{
+		Destination:   destination,
+		Status:        entities.OutboxStatusPending,
+		Payload:       []byte(`{"n":1}`),
+		NextAttemptAt: time.Now().UTC(),
+	}
+}
+
+func TestOutboxRepository_PendingHeads(t *testing.T) {
+	t.Parallel()
+	repo := NewOutboxRepository(setupOutboxTestDB(t), nil)
+	ctx := t.Context()
+
+	bw1 := newOutboxItem("birdweather")
+	mqtt1 := newOutboxItem("mqtt")
+	bw2 := newOutboxItem("birdweather")
+	for _, item := range []*entities.OutboxItem{bw1, mqtt1, bw2} {
+		require.NoError(t, repo.Create(ctx, item))
+	}
+
+	heads, err := repo.PendingHeads(ctx)
+	require.NoError(t, err)
+	require.Len(t, heads, 2)
+	assert.Equal(t, bw1.ID, heads[0].ID)
+	assert.Equal(t, mqtt1.ID, heads[1].ID)
+	assert.Equal(t, []byte(`{"n":1}`), heads[0].Payload)
+
+	// A dead head no longer blocks the destination.
+	require.NoError(t, repo.MarkDead(ctx, bw1.ID, 3, "gone"))
+	heads, err = repo.PendingHeads(ctx)
+	require.NoError(t, err)
+	require.Len(t, heads, 2)
+	assert.Equal(t, mqtt1.ID, heads[0].ID)
+	assert.Equal(t, bw2.ID, heads[1].ID)
+
+	// Retrying the dead item puts it back at the front.
+	require.NoError(t, repo.Retry(ctx, bw1.ID, time.Now().UTC()))
+	heads, err = repo.PendingHeads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, bw1.ID, heads[0].ID)
+	assert.Zero(t, heads[0].Attempts)
+
+	require.ErrorIs(t, repo.Retry(ctx, 999, time.Now()), ErrOutboxItemNotFound)
+}
+
+func TestOutboxRepository_ListStatsAndPurge(t *testing.T) {
+	t.Parallel()
+	repo := NewOutboxRepository(setupOutboxTestDB(t), nil)
+	ctx := t.Context()
+
+	items := []*entities.OutboxItem{newOutboxItem("birdweather"), newOutboxItem("birdweather"), newOutboxItem("webhook:discord")}
+	for _, item := range items {
+		require.NoError(t, repo.Create(ctx, item))
+	}
+	next := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	require.NoError(t, repo.RecordFailure(ctx, items[0].ID, 1, next, "timeout"))
+	require.NoError(t, repo.MarkDead(ctx, items[2].ID, 5, "404"))
+
+	got, err := repo.GetByID(ctx, items[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Equal(t, "timeout", got.LastError)
+	assert.True(t, got.NextAttemptAt.Equal(next))
+
+	listed, err := repo.List(ctx, OutboxFilter{Destination: "birdweather"})
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	assert.Nil(t, listed[0].Payload, "listing omits payloads")
+
+	listed, err = repo.List(ctx, OutboxFilter{Status: entities.OutboxStatusDead})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, items[2].ID, listed[0].ID)
+
+	stats, err := repo.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []OutboxStat{
+		{Destination: "birdweather", Status: entities.OutboxStatusPending, Count: 2},
+		{Destination: "webhook:discord", Status: entities.OutboxStatusDead, Count: 1},
+	}, stats)
+
+	retried, err := repo.RetryDead(ctx, "", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), retried)
+
+	require.NoError(t, repo.Delete(ctx, items[0].ID))
+	require.ErrorIs(t, repo.Delete(ctx, items[0].ID), ErrOutboxItemNotFound)
+
+	purged, err := repo.Purge(ctx, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+}
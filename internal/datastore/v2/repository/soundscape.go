@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// SoundscapeFilter selects soundscape segments for listing. Zero fields match
+// all segments; Start (inclusive) and End (exclusive) bound the segment start
+// times.
+type SoundscapeFilter struct {
+	SourceID string
+	Start    time.Time
+	End      time.Time
+	Limit    int
+	Offset   int
+}
+
+// SoundscapeSource summarizes the recorded segments of one audio source.
+type SoundscapeSource struct {
+	SourceID   string    `json:"source_id"`
+	SourceName string    `json:"source_name"`
+	Segments   int64     `json:"segments"`
+	TotalBytes int64     `json:"total_bytes"`
+	First      time.Time `json:"first"`
+	Last       time.Time `json:"last"`
+}
+
+// SoundscapeRepository handles the index of continuous soundscape recordings.
+type SoundscapeRepository interface {
+	// Create indexes a written segment.
+	Create(ctx context.Context, segment *entities.SoundscapeSegment) error
+	// GetByID returns ErrSoundscapeSegmentNotFound if the segment does not exist.
+	GetByID(ctx context.Context, id uint) (*entities.SoundscapeSegment, error)
+	// List returns segments matching the filter, newest first, and the total
+	// number of matching segments.
+	List(ctx context.Context, filter SoundscapeFilter) ([]entities.SoundscapeSegment, int64, error)
+	// Sources summarizes the segments per audio source.
+	Sources(ctx context.Context) ([]SoundscapeSource, error)
+	// Oldest returns up to limit segments, oldest first.
+	Oldest(ctx context.Context, limit int) ([]entities.SoundscapeSegment, error)
+	// TotalSize returns the combined size of all segments in bytes.
+	TotalSize(ctx context.Context) (int64, error)
+	// Delete removes a segment from the index. Returns
+	// ErrSoundscapeSegmentNotFound if the segment does not exist.
+	Delete(ctx context.Context, id uint) error
+}
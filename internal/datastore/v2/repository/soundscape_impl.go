@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+)
+
+// soundscapeRepository implements SoundscapeRepository.
+type soundscapeRepository struct {
+	db      *gorm.DB
+	metrics *datastore.Metrics
+}
+
+// NewSoundscapeRepository creates a new SoundscapeRepository.
+// metrics is optional (nil-safe) and enables retry observability.
+func NewSoundscapeRepository(db *gorm.DB, metrics *datastore.Metrics) SoundscapeRepository {
+	return &soundscapeRepository{db: db, metrics: metrics}
+}
+
+// Create indexes a written segment.
+func (r *soundscapeRepository) Create(ctx context.Context, segment *entities.SoundscapeSegment) error {
+	if segment == nil {
+		return fmt.Errorf("soundscape segment cannot be nil")
+	}
+	return datastore.RetryOnLock(ctx, "v2_create_soundscape_segment", func() error {
+		segment.ID = 0 // Reset ID for retry safety
+		if err := r.db.WithContext(ctx).Create(segment).Error; err != nil {
+			return fmt.Errorf("failed to create soundscape segment: %w", err)
+		}
+		return nil
+	}, r.metrics)
+}
+
+// GetByID returns a single segment.
+func (r *soundscapeRepository) GetByID(ctx context.Context, id uint) (*entities.SoundscapeSegment, error) {
+	var segment entities.SoundscapeSegment
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&segment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSoundscapeSegmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get soundscape segment: %w", err)
+	}
+	return &segment, nil
+}
+
+// List returns segments matching the filter, newest first, and the total
+// number of matching segments.
+func (r *soundscapeRepository) List(ctx context.Context, filter SoundscapeFilter) ([]entities.SoundscapeSegment, int64, error) {
+	query := r.db.WithContext(ctx).Model(&entities.SoundscapeSegment{})
+	if filter.SourceID != "" {
+		query = query.Where("source_id = ?", filter.SourceID)
+	}
+	if !filter.Start.IsZero() {
+		query = query.Where("start_time >= ?", filter.Start)
+	}
+	if !filter.End.IsZero() {
+		query = query.Where("start_time < ?", filter.End)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count soundscape segments: %w", err)
+	}
+
+	query = query.Order("start_time DESC, id DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+	var segments []entities.SoundscapeSegment
+	if err := query.Find(&segments).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list soundscape segments: %w", err)
+	}
+	return segments, total, nil
+}
+
+// Sources summarizes the segments per audio source. The source name is the
+// most recent one recorded for the source.
+func (r *soundscapeRepository) Sources(ctx context.Context) ([]SoundscapeSource, error) {
+	var sources []SoundscapeSource
+	if err := r.db.WithContext(ctx).Model(&entities.SoundscapeSegment{}).
+		Select("source_id, COUNT(*) AS segments, COALESCE(SUM(file_size), 0) AS total_bytes").
+		Group("source_id").
+		Order("source_id").
+		Scan(&sources).Error; err != nil {
+		return nil, fmt.Errorf("failed to summarize soundscape sources: %w", err)
+	}
+	// First and last are read from the rows rather than with MIN/MAX, which
+	// SQLite returns as text that does not scan into time.Time.
+	for i := range sources {
+		var first, last entities.SoundscapeSegment
+		if err := r.db.WithContext(ctx).Where("source_id = ?", sources[i].SourceID).
+			Order("start_time ASC").First(&first).Error; err != nil {
+			return nil, fmt.Errorf("failed to get first soundscape segment: %w", err)
+		}
+		if err := r.db.WithContext(ctx).Where("source_id = ?", sources[i].SourceID).
+			Order("start_time DESC").First(&last).Error; err != nil {
+			return nil, fmt.Errorf("failed to get last soundscape segment: %w", err)
+		}
+		sources[i].SourceName = last.SourceName
+		sources[i].First = first.StartTime
+		sources[i].Last = last.StartTime
+	}
+	return sources, nil
+}
+
+// Oldest returns up to limit segments, oldest first.
+func (r *soundscapeRepository) Oldest(ctx context.Context, limit int) ([]entities.SoundscapeSegment, error) {
+	var segments []entities.SoundscapeSegment
+	if err := r.db.WithContext(ctx).Order("start_time ASC, id ASC").Limit(limit).Find(&segments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get oldest soundscape segments: %w", err)
+	}
+	return segments, nil
+}
+
+// TotalSize returns the combined size of all segments in bytes.
+func (r *soundscapeRepository) TotalSize(ctx context.Context) (int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&entities.SoundscapeSegment{}).
+		Select("COALESCE(SUM(file_size), 0)").
+		Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to sum soundscape segment sizes: %w", err)
+	}
+	return total, nil
+}
+
+// Delete removes a segment from the index.
+func (r *soundscapeRepository) Delete(ctx context.Context, id uint) error {
+	var deleted int64
+	err := datastore.RetryOnLock(ctx, "v2_delete_soundscape_segment", func() error {
+		result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&entities.SoundscapeSegment{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete soundscape segment %d: %w", id, result.Error)
+		}
+		deleted = result.RowsAffected
+		return nil
+	}, r.metrics)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrSoundscapeSegmentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+func setupSoundscapeTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+
+	require.NoError(t, db.AutoMigrate(&entities.SoundscapeSegment{}))
+	return db
+}
+
+func newSoundscapeSegment(sourceID string, start time.Time, size int64) *entities.SoundscapeSegment {
+	return &entities.SoundscapeSegment{
+		SourceID:   sourceID,
+		SourceName: "Mic " + sourceID,
+		StartTime:  start,
+		Duration:   300,
+		FilePath:   fmt.Sprintf("%s/%s.flac", sourceID, start.Format("20060102T150405Z")),
+		Format:     "flac",
+		SampleRate: 48000,
+		FileSize:   size,
+	}
+}
+
+func TestSoundscapeRepository_ListAndSources(t *testing.T) {
+	t.Parallel()
+	repo := NewSoundscapeRepository(setupSoundscapeTestDB(t), nil)
+	ctx := t.Context()
+
+	base := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	for i := range 4 {
+		require.NoError(t, repo.Create(ctx, newSoundscapeSegment("a", base.Add(time.Duration(i)*5*time.Minute), 100)))
+	}
+	require.NoError(t, repo.Create(ctx, newSoundscapeSegment("b", base, 50)))
+
+	segments, total, err := repo.List(ctx, SoundscapeFilter{
+		SourceID: "a",
+		Start:    base.Add(5 * time.Minute),
+		End:      base.Add(20 * time.Minute),
+		Limit:    2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, segments, 2)
+	assert.Equal(t, base.Add(15*time.Minute), segments[0].StartTime.UTC(), "newest first")
+
+	sources, err := repo.Sources(ctx)
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	assert.Equal(t, "a", sources[0].SourceID)
+	assert.Equal(t, "Mic a", sources[0].SourceName)
+	assert.Equal(t, int64(4), sources[0].Segments)
+	assert.Equal(t, int64(400), sources[0].TotalBytes)
+	assert.Equal(t, base, sources[0].First.UTC())
+	assert.Equal(t, base.Add(15*time.Minute), sources[0].Last.UTC())
+
+	size, err := repo.TotalSize(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(450), size)
+}
+
+func TestSoundscapeRepository_OldestAndDelete(t *testing.T) {
+	t.Parallel()
+	repo := NewSoundscapeRepository(setupSoundscapeTestDB(t), nil)
+	ctx := t.Context()
+
+	base := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	late := newSoundscapeSegment("a", base.Add(time.Hour), 10)
+	early := newSoundscapeSegment("b", base, 10)
+	require.NoError(t, repo.Create(ctx, late))
+	require.NoError(t, repo.Create(ctx, early))
+
+	oldest, err := repo.Oldest(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, oldest, 1)
+	assert.Equal(t, early.ID, oldest[0].ID)
+
+	require.NoError(t, repo.Delete(ctx, early.ID))
+	require.ErrorIs(t, repo.Delete(ctx, early.ID), ErrSoundscapeSegmentNotFound)
+	_, err = repo.GetByID(ctx, early.ID)
+	require.ErrorIs(t, err, ErrSoundscapeSegmentNotFound)
+}
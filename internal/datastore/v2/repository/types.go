@@ -54,6 +54,9 @@ type SearchFilters struct {
 	// true = has review with verdict, false = no review or no verdict.
 	IsReviewed *bool
 
+	// ExcludeFalsePositives drops detections reviewed as false positives.
+	ExcludeFalsePositives bool
+
 	// IsLocked filters by lock status (optional).
 	IsLocked *bool
 
@@ -191,6 +194,10 @@ type SourceActivitySummary struct {
 	// SourceType is the source kind (e.g. "rtsp", "alsa", "file"), used for anonymized labelling.
 	SourceType string
 
+	// Site is the source's deployment site (nil when unset). Like DisplayName it is user-configured
+	// and anonymized by the API layer.
+	Site *string
+
 	// Count is the number of (false-positive-excluded) detections from this source in the period.
 	Count int
 }
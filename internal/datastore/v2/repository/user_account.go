@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// UserAccountRepository handles station user accounts.
+type UserAccountRepository interface {
+	// List returns all accounts ordered by username.
+	List(ctx context.Context) ([]entities.UserAccount, error)
+	// Count returns the number of accounts.
+	Count(ctx context.Context) (int64, error)
+	// GetByID returns ErrUserAccountNotFound if the account does not exist.
+	GetByID(ctx context.Context, id uint) (*entities.UserAccount, error)
+	// GetByUsername returns ErrUserAccountNotFound if the account does not exist.
+	GetByUsername(ctx context.Context, username string) (*entities.UserAccount, error)
+	// GetByExternalID returns the first account linked to any of the given
+	// OAuth identities, or ErrUserAccountNotFound.
+	GetByExternalID(ctx context.Context, externalIDs ...string) (*entities.UserAccount, error)
+	// Create returns ErrDuplicateKey if the username is taken.
+	Create(ctx context.Context, account *entities.UserAccount) error
+	// Update saves all fields of an existing account.
+	Update(ctx context.Context, account *entities.UserAccount) error
+	// Delete returns ErrUserAccountNotFound if the account does not exist.
+	Delete(ctx context.Context, id uint) error
+	// TouchLastLogin records a successful sign-in.
+	TouchLastLogin(ctx context.Context, id uint, at time.Time) error
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+)
+
+// userAccountRepository implements UserAccountRepository.
+type userAccountRepository struct {
+	db      *gorm.DB
+	metrics *datastore.Metrics
+}
+
+// NewUserAccountRepository creates a new UserAccountRepository.
+// metrics is optional (nil-safe) and enables retry observability.
+func NewUserAccountRepository(db *gorm.DB, metrics *datastore.Metrics) UserAccountRepository {
+	return &userAccountRepository{db: db, metrics: metrics}
+}
+
+// List returns all accounts ordered by username.
+func (r *userAccountRepository) List(ctx context.Context) ([]entities.UserAccount, error) {
+	var accounts []entities.UserAccount
+	if err := r.db.WithContext(ctx).Order("username ASC").Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list user accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// Count returns the number of accounts.
+func (r *userAccountRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.UserAccount{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count user accounts: %w", err)
+	}
+	return count, nil
+}
+
+// GetByID returns a single account by ID.
+func (r *userAccountRepository) GetByID(ctx context.Context, id uint) (*entities.UserAccount, error) {
+	return r.first(ctx, r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// GetByUsername returns a single account by username.
+func (r *userAccountRepository) GetByUsername(ctx context.Context, username string) (*entities.UserAccount, error) {
+	return r.first(ctx, r.db.WithContext(ctx).Where("username = ?", username))
+}
+
+// GetByExternalID returns the first account linked to any of the given
+// OAuth identities. Empty identities are ignored so an unlinked account
+// (empty ExternalID) can never match.
+func (r *userAccountRepository) GetByExternalID(ctx context.Context, externalIDs ...string) (*entities.UserAccount, error) {
+	ids := make([]string, 0, len(externalIDs))
+	for _, id := range externalIDs {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, ErrUserAccountNotFound
+	}
+	return r.first(ctx, r.db.WithContext(ctx).Where("external_id IN ?", ids).Order("id ASC"))
+}
+
+func (r *userAccountRepository) first(_ context.Context, query *gorm.DB) (*entities.UserAccount, error) {
+	var account entities.UserAccount
+	if err := query.First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get user account: %w", err)
+	}
+	return &account, nil
+}
+
+// Create creates a new account.
+func (r *userAccountRepository) Create(ctx context.Context, account *entities.UserAccount) error {
+	if _, err := r.GetByUsername(ctx, account.Username); err == nil {
+		return ErrDuplicateKey
+	} else if !errors.Is(err, ErrUserAccountNotFound) {
+		return err
+	}
+	return datastore.RetryOnLock(ctx, "v2_create_user_account", func() error {
+		account.ID = 0 // Reset ID for retry safety
+		if err := r.db.WithContext(ctx).Create(account).Error; err != nil {
+			return fmt.Errorf("failed to create user account: %w", err)
+		}
+		return nil
+	}, r.metrics)
+}
+
+// Update saves all fields of an existing account.
+func (r *userAccountRepository) Update(ctx context.Context, account *entities.UserAccount) error {
+	if account.ID == 0 {
+		return fmt.Errorf("failed to update user account: missing account ID")
+	}
+	return datastore.RetryOnLock(ctx, "v2_update_user_account", func() error {
+		if err := r.db.WithContext(ctx).Save(account).Error; err != nil {
+			return fmt.Errorf("failed to update user account %d: %w", account.ID, err)
+		}
+		return nil
+	}, r.metrics)
+}
+
+// Delete removes an account.
+func (r *userAccountRepository) Delete(ctx context.Context, id uint) error {
+	var rowsAffected int64
+	err := datastore.RetryOnLock(ctx, "v2_delete_user_account", func() error {
+		result := r.db.WithContext(ctx).Delete(&entities.UserAccount{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete user account %d: %w", id, result.Error)
+		}
+		rowsAffected = result.RowsAffected
+		return nil
+	}, r.metrics)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrUserAccountNotFound
+	}
+	return nil
+}
+
+// TouchLastLogin records a successful sign-in.
+func (r *userAccountRepository) TouchLastLogin(ctx context.Context, id uint, at time.Time) error {
+	return datastore.RetryOnLock(ctx, "v2_touch_user_account_login", func() error {
+		// UpdateColumn leaves updated_at alone: signing in does not edit the account.
+		if err := r.db.WithContext(ctx).Model(&entities.UserAccount{}).
+			Where("id = ?", id).UpdateColumn("last_login_at", at).Error; err != nil {
+			return fmt.Errorf("failed to record login for user account %d: %w", id, err)
+		}
+		return nil
+	}, r.metrics)
+}
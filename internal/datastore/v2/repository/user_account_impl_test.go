@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+func setupUserAccountTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+
+	require.NoError(t, db.AutoMigrate(&entities.UserAccount{}))
+	return db
+}
+
+func TestUserAccountRepository_CreateAndGet(t *testing.T) {
+	t.Parallel()
+	repo := NewUserAccountRepository(setupUserAccountTestDB(t), nil)
+	ctx := t.Context()
+
+	account := &entities.UserAccount{Username: "anna", Role: "reviewer", ExternalID: "anna@example.com"}
+	require.NoError(t, repo.Create(ctx, account))
+	assert.NotZero(t, account.ID)
+
+	got, err := repo.GetByUsername(ctx, "anna")
+	require.NoError(t, err)
+	assert.Equal(t, account.ID, got.ID)
+	assert.Equal(t, "reviewer", got.Role)
+
+	got, err = repo.GetByID(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "anna", got.Username)
+
+	got, err = repo.GetByExternalID(ctx, "", "anna@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, account.ID, got.ID)
+
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestUserAccountRepository_DuplicateUsername(t *testing.T) {
+	t.Parallel()
+	repo := NewUserAccountRepository(setupUserAccountTestDB(t), nil)
+	ctx := t.Context()
+
+	require.NoError(t, repo.Create(ctx, &entities.UserAccount{Username: "anna", Role: "viewer"}))
+	err := repo.Create(ctx, &entities.UserAccount{Username: "anna", Role: "admin"})
+	require.ErrorIs(t, err, ErrDuplicateKey)
+}
+
+func TestUserAccountRepository_NotFound(t *testing.T) {
+	t.Parallel()
+	repo := NewUserAccountRepository(setupUserAccountTestDB(t), nil)
+	ctx := t.Context()
+
+	// Create an account without an external ID; an empty lookup must not match it.
+	require.NoError(t, repo.Create(ctx, &entities.UserAccount{Username: "anna", Role: "viewer"}))
+
+	_, err := repo.GetByUsername(ctx, "bob")
+	require.ErrorIs(t, err, ErrUserAccountNotFound)
+	_, err = repo.GetByID(ctx, 999)
+	require.ErrorIs(t, err, ErrUserAccountNotFound)
+	_, err = repo.GetByExternalID(ctx, "")
+	require.ErrorIs(t, err, ErrUserAccountNotFound)
+	require.ErrorIs(t, repo.Delete(ctx, 999), ErrUserAccountNotFound)
+}
+
+func TestUserAccountRepository_UpdateDeleteAndLogin(t *testing.T) {
+	t.Parallel()
+	repo := NewUserAccountRepository(setupUserAccountTestDB(t), nil)
+	ctx := t.Context()
+
+	account := &entities.UserAccount{Username: "bob", Role: "viewer"}
+	require.NoError(t, repo.Create(ctx, account))
+
+	account.Role = "admin"
+	account.Disabled = true
+	require.NoError(t, repo.Update(ctx, account))
+
+	at := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, repo.TouchLastLogin(ctx, account.ID, at))
+
+	got, err := repo.GetByID(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", got.Role)
+	assert.True(t, got.Disabled)
+	require.NotNil(t, got.LastLoginAt)
+	assert.True(t, at.Equal(*got.LastLoginAt))
+
+	list, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	require.NoError(t, repo.Delete(ctx, account.ID))
+	_, err = repo.GetByID(ctx, account.ID)
+	require.ErrorIs(t, err, ErrUserAccountNotFound)
+}
@@ -17,6 +17,7 @@ type weatherRepository struct {
 	metrics     *datastore.Metrics
 	useV2Prefix bool
 	isMySQL     bool // Dialect flag: true for MySQL (UNIX_TIMESTAMP), false for SQLite (strftime)
+	isPostgres  bool // Dialect flag: PostgreSQL compares timestamptz values directly
 }
 
 // NewWeatherRepository creates a new WeatherRepository.
@@ -31,6 +32,7 @@ func NewWeatherRepository(db *gorm.DB, metrics *datastore.Metrics, useV2Prefix,
 		metrics:     metrics,
 		useV2Prefix: useV2Prefix,
 		isMySQL:     isMySQL,
+		isPostgres:  isPostgresDB(db),
 	}
 }
 
@@ -117,9 +119,12 @@ func (r *weatherRepository) GetHourlyWeatherInLocation(ctx context.Context, date
 	// Convert timestamps to Unix epoch seconds for comparison.
 	// This handles legacy records stored with local timezone offsets alongside new UTC records.
 	var whereClause string
-	if r.isMySQL {
+	switch {
+	case r.isMySQL:
 		whereClause = "UNIX_TIMESTAMP(time) >= UNIX_TIMESTAMP(?) AND UNIX_TIMESTAMP(time) < UNIX_TIMESTAMP(?)"
-	} else {
+	case r.isPostgres:
+		whereClause = "time >= ? AND time < ?"
+	default:
 		whereClause = "strftime('%s', time) >= strftime('%s', ?) AND strftime('%s', time) < strftime('%s', ?)"
 	}
 
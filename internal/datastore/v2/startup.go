@@ -18,6 +18,7 @@ import (
 	gormmysql "gorm.io/driver/mysql"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	gormlogger "gorm.io/gorm/logger"
 )
 
@@ -123,6 +124,9 @@ func CheckMigrationStateBeforeStartup(settings *conf.Settings) StartupState {
 	if settings.Output.MySQL.Enabled {
 		return checkMySQLMigrationState(settings)
 	}
+	if settings.Output.Postgres.Enabled {
+		return checkPostgresMigrationState(settings)
+	}
 	return checkSQLiteMigrationState(settings)
 }
 
@@ -587,6 +591,9 @@ func HasUnmigratedLegacyRecords(settings *conf.Settings, log logger.Logger) bool
 	if settings.Output.MySQL.Enabled {
 		return hasUnmigratedLegacyMySQL(settings, log)
 	}
+	if settings.Output.Postgres.Enabled {
+		return hasUnmigratedLegacyPostgres(settings, log)
+	}
 	if settings.Output.SQLite.Enabled {
 		return hasUnmigratedLegacySQLite(settings, log)
 	}
@@ -937,20 +944,66 @@ func legacyDataPresent(db *gorm.DB) (bool, error) {
 		// hold hundreds of thousands of unmigrated rows (GitHub #3924), and counting all of them on
 		// every startup would scan the whole table and could approach the DB startup timeout. EXISTS
 		// short-circuits at the first row and yields an explicit 0/1, so it does not depend on GORM
-		// populating RowsAffected after Scan (which can vary by version/driver). The table name comes
-		// from the fixed legacyDataTables allowlist, so the interpolation is safe. Backtick quoting
-		// works on both SQLite and MySQL (mirrors cleanupLegacySchemaContamination in this package).
-		var hasRow int
-		if err := db.Raw("SELECT EXISTS(SELECT 1 FROM `" + name + "`)").Scan(&hasRow).Error; err != nil {
+		// populating RowsAffected after Scan (which can vary by version/driver). clause.Table lets
+		// the dialector quote the name, so the same probe works on SQLite, MySQL and PostgreSQL.
+		var hasRow bool
+		if err := db.Raw("SELECT EXISTS(SELECT 1 FROM ?)", clause.Table{Name: name}).Scan(&hasRow).Error; err != nil {
 			return false, err
 		}
-		if hasRow != 0 {
+		if hasRow {
 			return true, nil
 		}
 	}
 	return false, nil
 }
 
+// orphanedBareV2Tables lists the bare v2-only tables that do NOT collide with legacy tables.
+// Drop in reverse dependency order (children before parents).
+// Both naming forms are listed: the OLD singular forms (migration_state,
+// alert_history) left by pre-PR #2165 code with TableName() overrides, and the
+// current GORM-pluralized forms (migration_states, alert_histories) that a more
+// recent broken nightly would leave behind. An entry that does not exist is a
+// harmless no-op (DROP TABLE IF EXISTS).
+var orphanedBareV2Tables = []string{
+	// Alert children first, then parent
+	"alert_action_results",
+	"alert_actions",
+	"alert_conditions",
+	"alert_history",
+	"alert_histories",
+	"alert_rules",
+	// Detection children first, then parent
+	"clip_redactions",
+	"classifier_examples",
+	"detection_embeddings",
+	"detection_locks",
+	"detection_comments",
+	"detection_reviews",
+	"detection_model_contributions",
+	"detection_predictions",
+	"detections",
+	// Labels before its reference tables (labels has FKs to ai_models, label_types, taxonomic_classes)
+	"labels",
+	"audio_sources",
+	"ai_models",
+	"taxonomic_classes",
+	"label_types",
+	// Application metadata, event log, user accounts, API keys, the
+	// delivery outbox, the soundscape index and the archived clip index (no FK
+	// dependencies)
+	"archived_clips",
+	"soundscape_segments",
+	"outbox_items",
+	"api_keys",
+	"user_accounts",
+	"app_events",
+	"app_metadata",
+	// Migration tracking
+	"migration_dirty_ids",
+	"migration_state",
+	"migration_states",
+}
+
 // cleanupOrphanedBareV2Tables removes bare v2 tables that were created by a broken nightly
 // alongside existing legacy tables. These orphaned tables prevent the migration infrastructure
 // from starting fresh. Only v2-specific tables are dropped; legacy tables with real user data
@@ -971,42 +1024,6 @@ func cleanupOrphanedBareV2Tables(db *gorm.DB, database string) {
 		)
 	})
 
-	// Bare v2-only tables that do NOT collide with legacy tables.
-	// Drop in reverse dependency order (children before parents).
-	// Both naming forms are listed: the OLD singular forms (migration_state,
-	// alert_history) left by pre-PR #2165 code with TableName() overrides, and the
-	// current GORM-pluralized forms (migration_states, alert_histories) that a more
-	// recent broken nightly would leave behind. FK checks are disabled below, so an
-	// entry that does not exist is a harmless no-op (DROP TABLE IF EXISTS).
-	orphanedTables := []string{
-		// Alert children first, then parent
-		"alert_actions",
-		"alert_conditions",
-		"alert_history",
-		"alert_histories",
-		"alert_rules",
-		// Detection children first, then parent
-		"detection_locks",
-		"detection_comments",
-		"detection_reviews",
-		"detection_model_contributions",
-		"detection_predictions",
-		"detections",
-		// Labels before its reference tables (labels has FKs to ai_models, label_types, taxonomic_classes)
-		"labels",
-		"audio_sources",
-		"ai_models",
-		"taxonomic_classes",
-		"label_types",
-		// Application metadata and event log (no FK dependencies)
-		"app_events",
-		"app_metadata",
-		// Migration tracking
-		"migration_dirty_ids",
-		"migration_state",
-		"migration_states",
-	}
-
 	// Use db.Connection to pin all operations to a single pooled connection,
 	// ensuring SET FOREIGN_KEY_CHECKS applies to the same connection as the DROPs.
 	// FK checks must be disabled because preserved legacy tables (dynamic_thresholds,
@@ -1028,7 +1045,7 @@ func cleanupOrphanedBareV2Tables(db *gorm.DB, database string) {
 				}
 			}
 		}()
-		for _, table := range orphanedTables {
+		for _, table := range orphanedBareV2Tables {
 			if err := tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS `%s`", table)).Error; err != nil {
 				reportStartupError("mysql", "cleanupOrphanedTable_"+table, err, database)
 			}
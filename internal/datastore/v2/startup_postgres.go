@@ -0,0 +1,237 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/telemetry"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// postgresStartupConnectTimeout is the libpq connect_timeout (in seconds) for
+// startup-time checks, matching dbStartupTimeout.
+const postgresStartupConnectTimeout = "5"
+
+// openPostgresStartupDB opens a short-lived PostgreSQL connection for the
+// startup checks. The caller must close the returned connection.
+func openPostgresStartupDB(settings *conf.Settings) (*gorm.DB, func(), error) {
+	dsn := datastore.PostgresDSN(settings, postgresStartupConnectTimeout)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get underlying DB: %w", err)
+	}
+	return db, func() { _ = sqlDB.Close() }, nil
+}
+
+// resolvePostgresTable returns the first of the given table names that exists
+// in the current schema, or "" when none does.
+func resolvePostgresTable(db *gorm.DB, names ...string) string {
+	for _, name := range names {
+		if db.Migrator().HasTable(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// checkPostgresMigrationState checks migration state for PostgreSQL deployments.
+// The decision table is the same as checkMySQLMigrationState: legacy and v2
+// tables share one database and are told apart by the "v2_" prefix.
+func checkPostgresMigrationState(settings *conf.Settings) StartupState {
+	pgHost := settings.Output.Postgres.Host
+	pgDB := settings.Output.Postgres.Database
+	pgUser := settings.Output.Postgres.Username
+
+	db, closeDB, err := openPostgresStartupDB(settings)
+	if err != nil {
+		reportStartupError("postgres", "openDatabase", err, pgHost, pgDB, pgUser)
+		return StartupState{
+			MigrationStatus: entities.MigrationStatusIdle,
+			V2Available:     false,
+			LegacyRequired:  true,
+			Decision:        DecisionV2Corrupted,
+			Error:           fmt.Errorf("%w: %w", ErrV2DatabaseCorrupted, err),
+		}
+	}
+	defer closeDB()
+
+	// Probe connectivity explicitly: HasTable reports false on query errors,
+	// which would otherwise be misread as a fresh install.
+	var one int
+	if err := db.Raw("SELECT 1").Scan(&one).Error; err != nil {
+		reportStartupError("postgres", "ping", err, pgHost, pgDB, pgUser)
+		return StartupState{
+			MigrationStatus: entities.MigrationStatusIdle,
+			V2Available:     false,
+			LegacyRequired:  true,
+			Decision:        DecisionV2Corrupted,
+			Error:           fmt.Errorf("%w: %w", ErrV2DatabaseCorrupted, err),
+		}
+	}
+
+	legacyExists := db.Migrator().HasTable("notes")
+	v2MigrationTableName := resolvePostgresTable(db, v2TablePrefix+"migration_states", v2TablePrefix+"migration_state")
+	v2MigrationExists := v2MigrationTableName != ""
+	freshV2Exists := db.Migrator().HasTable("detections")
+
+	// Fresh install: no tables exist at all
+	if !legacyExists && !v2MigrationExists && !freshV2Exists {
+		return StartupState{
+			MigrationStatus: entities.MigrationStatusIdle,
+			LegacyRequired:  false,
+			FreshInstall:    true,
+			Decision:        DecisionFreshInstall,
+		}
+	}
+
+	// Fresh v2 exists WITHOUT legacy = genuine fresh install restart
+	if freshV2Exists && !v2MigrationExists && !legacyExists {
+		return StartupState{
+			MigrationStatus: entities.MigrationStatusCompleted,
+			V2Available:     true,
+			LegacyRequired:  false,
+			Decision:        DecisionV2Restart,
+		}
+	}
+
+	// Fresh v2 exists WITH legacy = orphaned bare v2 tables from a failed setup
+	if freshV2Exists && !v2MigrationExists && legacyExists {
+		cleanupOrphanedBareV2TablesPostgres(db, pgDB)
+		return StartupState{
+			MigrationStatus: entities.MigrationStatusIdle,
+			LegacyRequired:  true,
+			Decision:        DecisionLegacyMode,
+		}
+	}
+
+	// No v2 tables at all but legacy exists = legacy mode
+	if !v2MigrationExists && !freshV2Exists {
+		return StartupState{
+			MigrationStatus: entities.MigrationStatusIdle,
+			LegacyRequired:  true,
+			Decision:        DecisionLegacyMode,
+		}
+	}
+
+	if !v2MigrationExists {
+		reportStartupError("postgres", "resolveV2TableName", fmt.Errorf("could not resolve migration state table"), pgHost, pgDB, pgUser)
+		return StartupState{
+			MigrationStatus: entities.MigrationStatusIdle,
+			V2Available:     true,
+			LegacyRequired:  true,
+			Decision:        DecisionV2Corrupted,
+			Error:           fmt.Errorf("%w: migration state table not found", ErrV2DatabaseCorrupted),
+		}
+	}
+
+	var state entities.MigrationState
+	if err := db.Table(v2MigrationTableName).First(&state).Error; err != nil {
+		reportStartupError("postgres", "readMigrationState", err, pgHost, pgDB, pgUser)
+		return StartupState{
+			MigrationStatus: entities.MigrationStatusIdle,
+			V2Available:     true,
+			LegacyRequired:  true,
+			Decision:        DecisionV2Corrupted,
+			Error:           fmt.Errorf("%w: %w", ErrV2DatabaseCorrupted, err),
+		}
+	}
+
+	return StartupState{
+		MigrationStatus: state.State,
+		V2Available:     true,
+		LegacyRequired:  state.State != entities.MigrationStatusCompleted,
+		Decision:        DecisionMigrationPrefix + string(state.State),
+	}
+}
+
+// hasUnmigratedLegacyPostgres checks for unmigrated records in a PostgreSQL legacy database.
+func hasUnmigratedLegacyPostgres(settings *conf.Settings, log logger.Logger) bool {
+	db, closeDB, err := openPostgresStartupDB(settings)
+	if err != nil {
+		log.Warn("reconciliation: failed to open PostgreSQL database", logger.Error(err))
+		return false
+	}
+	defer closeDB()
+
+	v2MigrationTableName := resolvePostgresTable(db, v2TablePrefix+"migration_states", v2TablePrefix+"migration_state")
+	if v2MigrationTableName == "" {
+		return false
+	}
+
+	var state entities.MigrationState
+	if err := db.Table(v2MigrationTableName).First(&state).Error; err != nil {
+		log.Warn("reconciliation: failed to read PostgreSQL migration state", logger.Error(err))
+		return false
+	}
+
+	if state.State != entities.MigrationStatusCompleted || !db.Migrator().HasTable("notes") {
+		return false
+	}
+
+	// Count legacy notes beyond the last migrated ID
+	var count int64
+	if err := db.Raw("SELECT COUNT(*) FROM notes WHERE id > ?", state.LastMigratedID).Scan(&count).Error; err != nil {
+		log.Warn("reconciliation: failed to count unmigrated PostgreSQL legacy records", logger.Error(err))
+		return false
+	}
+
+	if count > 0 {
+		log.Warn("found unmigrated legacy records after potential crash recovery (PostgreSQL)",
+			logger.Int64("count", count),
+			logger.Uint64("last_migrated_id", uint64(state.LastMigratedID)))
+		return true
+	}
+
+	return false
+}
+
+// CheckPostgresHasFreshV2Schema is the PostgreSQL counterpart of
+// CheckMySQLHasFreshV2Schema and drives the same prefix decision.
+func CheckPostgresHasFreshV2Schema(settings *conf.Settings) bool {
+	db, closeDB, err := openPostgresStartupDB(settings)
+	if err != nil {
+		return false
+	}
+	defer closeDB()
+
+	return hasCompleteFreshV2Schema(db)
+}
+
+// cleanupOrphanedBareV2TablesPostgres removes bare v2 tables left alongside
+// legacy tables by a failed setup, like cleanupOrphanedBareV2Tables does for
+// MySQL. PostgreSQL has no session switch for foreign key checks, so each
+// table is dropped with CASCADE, which removes only the referencing
+// constraints and never the preserved legacy tables themselves.
+func cleanupOrphanedBareV2TablesPostgres(db *gorm.DB, database string) {
+	sentry.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("component", "datastore-startup")
+		scope.SetTag("db_type", "postgres")
+		scope.SetTag("operation", "cleanupOrphanedBareV2Tables")
+		scope.SetFingerprint([]string{"datastore-startup", "postgres", "cleanupOrphanedBareV2Tables"})
+
+		telemetry.CaptureMessage(
+			"Orphaned bare v2 tables found alongside legacy data, cleaning up",
+			sentry.LevelWarning,
+			"datastore-startup",
+		)
+	})
+
+	for _, table := range orphanedBareV2Tables {
+		if err := db.Exec("DROP TABLE IF EXISTS ? CASCADE", clause.Table{Name: table}).Error; err != nil {
+			reportStartupError("postgres", "cleanupOrphanedTable_"+table, err, database)
+		}
+	}
+}
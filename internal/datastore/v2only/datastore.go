@@ -451,7 +451,7 @@ func (ds *Datastore) Close() error {
 		if sqliteMgr, ok := ds.manager.(*v2.SQLiteManager); ok {
 			sqliteMgr.StopPeriodicCheckpoint()
 		}
-		if !ds.manager.IsMySQL() {
+		if !ds.manager.IsMySQL() && !ds.manager.IsPostgres() {
 			log.Info("performing SQLite WAL checkpoint",
 				logger.String("operation", "wal_checkpoint_before_shutdown"),
 				logger.String("mode", "v2only"))
@@ -489,14 +489,22 @@ func (ds *Datastore) SetSunCalcMetrics(suncalcMetrics any) {
 
 // Optimize performs database optimization.
 func (ds *Datastore) Optimize(ctx context.Context) error {
-	if !ds.manager.IsMySQL() {
+	switch {
+	case ds.manager.IsMySQL():
+		return nil
+	case ds.manager.IsPostgres():
+		// A plain VACUUM does not lock tables against reads or writes.
+		if err := ds.manager.DB().WithContext(ctx).Exec("VACUUM (ANALYZE)").Error; err != nil {
+			return fmt.Errorf("VACUUM failed: %w", err)
+		}
+		return nil
+	default:
 		db := ds.manager.DB()
 		if err := db.WithContext(ctx).Exec("VACUUM").Error; err != nil {
 			return fmt.Errorf("VACUUM failed: %w", err)
 		}
 		return db.WithContext(ctx).Exec("ANALYZE").Error
 	}
-	return nil
 }
 
 // Transaction runs a function within a database transaction.
@@ -543,9 +551,12 @@ func (ds *Datastore) GetDatabaseStats(ctx context.Context) (*datastore.DatabaseS
 		return nil, err
 	}
 
-	dbType := "sqlite"
-	if ds.manager.IsMySQL() {
-		dbType = "mysql"
+	dbType := datastore.DialectSQLite
+	switch {
+	case ds.manager.IsMySQL():
+		dbType = datastore.DialectMySQL
+	case ds.manager.IsPostgres():
+		dbType = datastore.DialectPostgres
 	}
 
 	stats := &datastore.DatabaseStats{
@@ -557,14 +568,17 @@ func (ds *Datastore) GetDatabaseStats(ctx context.Context) (*datastore.DatabaseS
 
 	// Get database size (best-effort); guard against nil DB after concurrent Close()
 	if db := ds.manager.DB(); db != nil {
-		if !ds.manager.IsMySQL() {
-			_ = db.WithContext(ctx).Raw("SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()").Scan(&stats.SizeBytes).Error
-		} else {
+		switch {
+		case ds.manager.IsMySQL():
 			_ = db.WithContext(ctx).Raw(`
 				SELECT SUM(DATA_LENGTH + INDEX_LENGTH)
 				FROM information_schema.TABLES
 				WHERE TABLE_SCHEMA = DATABASE()
 			`).Scan(&stats.SizeBytes).Error
+		case ds.manager.IsPostgres():
+			_ = db.WithContext(ctx).Raw("SELECT pg_database_size(current_database())").Scan(&stats.SizeBytes).Error
+		default:
+			_ = db.WithContext(ctx).Raw("SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()").Scan(&stats.SizeBytes).Error
 		}
 	}
 
@@ -789,6 +803,7 @@ func (ds *Datastore) Save(note *datastore.Note, results []datastore.Results) err
 			// Continue without source - not fatal
 		} else {
 			det.SourceID = &source.ID
+			ds.syncSourceDeployment(ctx, source, note.Source.Deployment)
 		}
 	} else if note.Source.SafeString != "" && ds.source == nil {
 		if ds.log != nil {
@@ -960,6 +975,7 @@ func (ds *Datastore) detectionToNote(det *entities.Detection) datastore.Note {
 			ID:          det.Source.SourceURI,
 			SafeString:  det.Source.SourceURI,
 			DisplayName: displayName,
+			Deployment:  deploymentConfig(&det.Source.Deployment),
 		}
 	}
 
@@ -1637,11 +1653,12 @@ func (ds *Datastore) GetNoteReview(noteID string) (*datastore.NoteReview, error)
 	}
 
 	return &datastore.NoteReview{
-		ID:        review.ID,
-		NoteID:    id,
-		Verified:  string(review.Verified),
-		CreatedAt: review.CreatedAt,
-		UpdatedAt: review.UpdatedAt,
+		ID:         review.ID,
+		NoteID:     id,
+		Verified:   string(review.Verified),
+		ReviewedBy: review.ReviewedBy,
+		CreatedAt:  review.CreatedAt,
+		UpdatedAt:  review.UpdatedAt,
 	}, nil
 }
 
@@ -1652,6 +1669,7 @@ func (ds *Datastore) SaveNoteReview(review *datastore.NoteReview) error {
 	v2Review := &entities.DetectionReview{
 		DetectionID: review.NoteID,
 		Verified:    entities.VerificationStatus(review.Verified),
+		ReviewedBy:  review.ReviewedBy,
 	}
 
 	return ds.detection.SaveReview(ctx, v2Review)
@@ -1892,6 +1910,7 @@ func (ds *Datastore) SaveHourlyWeather(hourlyWeather *datastore.HourlyWeather) e
 		Clouds:            hourlyWeather.Clouds,
 		Precipitation:     hourlyWeather.Precipitation,
 		PrecipitationType: hourlyWeather.PrecipitationType,
+		SolarRadiation:    hourlyWeather.SolarRadiation,
 		WeatherMain:       hourlyWeather.WeatherMain,
 		WeatherDesc:       hourlyWeather.WeatherDesc,
 		WeatherIcon:       hourlyWeather.WeatherIcon,
@@ -1929,6 +1948,7 @@ func (ds *Datastore) GetHourlyWeather(date string) ([]datastore.HourlyWeather, e
 			Clouds:            w.Clouds,
 			Precipitation:     w.Precipitation,
 			PrecipitationType: w.PrecipitationType,
+			SolarRadiation:    w.SolarRadiation,
 			WeatherMain:       w.WeatherMain,
 			WeatherDesc:       w.WeatherDesc,
 			WeatherIcon:       w.WeatherIcon,
@@ -1964,6 +1984,7 @@ func (ds *Datastore) LatestHourlyWeather() (*datastore.HourlyWeather, error) {
 		Clouds:            w.Clouds,
 		Precipitation:     w.Precipitation,
 		PrecipitationType: w.PrecipitationType,
+		SolarRadiation:    w.SolarRadiation,
 		WeatherMain:       w.WeatherMain,
 		WeatherDesc:       w.WeatherDesc,
 		WeatherIcon:       w.WeatherIcon,
@@ -2234,14 +2255,14 @@ func (ds *Datastore) loadDetectionRelations(ctx context.Context, dets []*entitie
 // Lock Methods
 // ============================================================
 
-// LockNote locks a note.
-func (ds *Datastore) LockNote(noteID string) error {
+// LockNote locks a note on behalf of lockedBy.
+func (ds *Datastore) LockNote(noteID, lockedBy string) error {
 	ctx := context.Background()
 	id, err := parseID(noteID)
 	if err != nil {
 		return err
 	}
-	return ds.detection.Lock(ctx, id)
+	return ds.detection.Lock(ctx, id, lockedBy)
 }
 
 // UnlockNote unlocks a note.
@@ -2278,6 +2299,7 @@ func (ds *Datastore) GetNoteLock(noteID string) (*datastore.NoteLock, error) {
 		ID:       lock.ID,
 		NoteID:   id,
 		LockedAt: lock.LockedAt,
+		LockedBy: lock.LockedBy,
 	}, nil
 }
 
@@ -2600,13 +2622,18 @@ func dateRangeOffsetAnchor(start, end int64) int64 {
 // the session time_zone; DATE(FROM_UNIXTIME(...)) would apply that zone on top of the offset and
 // double-count. Integer DIV avoids floating-point rounding at exact day boundaries.
 //
-// SQLite: date(d.detected_at + offset, 'unixepoch')
-// MySQL:  DATE_ADD('1970-01-01', INTERVAL (d.detected_at + offset) DIV 86400 DAY)
+// SQLite:     date(d.detected_at + offset, 'unixepoch')
+// MySQL:      DATE_ADD('1970-01-01', INTERVAL (d.detected_at + offset) DIV 86400 DAY)
+// PostgreSQL: to_char(DATE '1970-01-01' + CAST((d.detected_at + offset) / 86400 AS INTEGER), 'YYYY-MM-DD')
 func (ds *Datastore) detectionDateExpr(offsetSeconds int) string {
-	if ds.manager.IsMySQL() {
+	switch {
+	case ds.manager.IsMySQL():
 		return fmt.Sprintf("DATE_ADD('1970-01-01', INTERVAL (d.detected_at + %d) DIV 86400 DAY)", offsetSeconds)
+	case ds.manager.IsPostgres():
+		return fmt.Sprintf("to_char(DATE '1970-01-01' + CAST((d.detected_at + %d) / 86400 AS INTEGER), 'YYYY-MM-DD')", offsetSeconds)
+	default:
+		return fmt.Sprintf("date(d.detected_at + %d, 'unixepoch')", offsetSeconds)
 	}
-	return fmt.Sprintf("date(d.detected_at + %d, 'unixepoch')", offsetSeconds)
 }
 
 // GetSpeciesSummaryData retrieves species summary data.
@@ -3349,11 +3376,16 @@ func (ds *Datastore) GetAudioSources(ctx context.Context, startDate, endDate str
 		if rows[i].DisplayName != nil {
 			displayName = *rows[i].DisplayName
 		}
+		site := ""
+		if rows[i].Site != nil {
+			site = *rows[i].Site
+		}
 		summaries = append(summaries, datastore.AudioSourceSummary{
 			ID:          rows[i].SourceID,
 			DisplayName: displayName,
 			NodeName:    rows[i].NodeName,
 			SourceType:  rows[i].SourceType,
+			Site:        site,
 			Count:       rows[i].Count,
 		})
 	}
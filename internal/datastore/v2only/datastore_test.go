@@ -1231,7 +1231,7 @@ func TestV2OnlyDatastore_LockUnlock(t *testing.T) {
 	require.NoError(t, err)
 
 	// Lock the note
-	err = ds.LockNote("1")
+	err = ds.LockNote("1", "")
 	require.NoError(t, err)
 
 	// Check if locked
@@ -1360,7 +1360,7 @@ func TestV2OnlyDatastore_SpeciesDetections_LoadsRelations(t *testing.T) {
 	err := ds.SaveNoteReview(review)
 	require.NoError(t, err)
 
-	err = ds.LockNote("1")
+	err = ds.LockNote("1", "")
 	require.NoError(t, err)
 
 	// Query via SpeciesDetections
@@ -1391,7 +1391,7 @@ func TestV2OnlyDatastore_GetHourlyDetections_LoadsRelations(t *testing.T) {
 	err := ds.SaveNoteReview(review)
 	require.NoError(t, err)
 
-	err = ds.LockNote("1")
+	err = ds.LockNote("1", "")
 	require.NoError(t, err)
 
 	// Query via GetHourlyDetections (hour 12, duration 1 hour)
@@ -1493,7 +1493,7 @@ func TestV2OnlyDatastore_ReviewAndLockVisibleInGetAllNotes(t *testing.T) {
 	err := ds.SaveNoteReview(review)
 	require.NoError(t, err)
 
-	err = ds.LockNote("1")
+	err = ds.LockNote("1", "")
 	require.NoError(t, err)
 
 	// Retrieve via GetAllNotes
@@ -0,0 +1,89 @@
+package v2only
+
+import (
+	"context"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// deploymentEntity converts configured deployment metadata to its v2 columns.
+// A nil deployment maps to all-NULL columns.
+func deploymentEntity(d *conf.DeploymentConfig) entities.SourceDeployment {
+	var e entities.SourceDeployment
+	if d == nil {
+		return e
+	}
+	if d.Site != "" {
+		e.Site = &d.Site
+	}
+	if d.HasLocation() {
+		e.Latitude = &d.Latitude
+		e.Longitude = &d.Longitude
+	}
+	if d.Habitat != "" {
+		e.Habitat = &d.Habitat
+	}
+	if d.MicrophoneModel != "" {
+		e.MicrophoneModel = &d.MicrophoneModel
+	}
+	if d.MicrophoneHeight > 0 {
+		e.MicrophoneHeight = &d.MicrophoneHeight
+	}
+	return e
+}
+
+// deploymentConfig converts the v2 deployment columns back to deployment
+// metadata. It returns nil when every column is NULL.
+func deploymentConfig(e *entities.SourceDeployment) *conf.DeploymentConfig {
+	var d conf.DeploymentConfig
+	if e.Site != nil {
+		d.Site = *e.Site
+	}
+	if e.Latitude != nil && e.Longitude != nil {
+		d.Latitude, d.Longitude = *e.Latitude, *e.Longitude
+	}
+	if e.Habitat != nil {
+		d.Habitat = *e.Habitat
+	}
+	if e.MicrophoneModel != nil {
+		d.MicrophoneModel = *e.MicrophoneModel
+	}
+	if e.MicrophoneHeight != nil {
+		d.MicrophoneHeight = *e.MicrophoneHeight
+	}
+	if d.IsZero() {
+		return nil
+	}
+	return &d
+}
+
+// syncSourceDeployment stores the source's configured deployment metadata on
+// its audio source row when it changed. The configuration is authoritative, so
+// a source whose deployment was removed has its columns cleared. Failures are
+// logged and not fatal: the detection is saved either way.
+func (ds *Datastore) syncSourceDeployment(ctx context.Context, source *entities.AudioSource, deployment *conf.DeploymentConfig) {
+	stored := deploymentConfig(&source.Deployment)
+	if (stored == nil && deployment == nil) || (stored != nil && deployment != nil && *stored == *deployment) {
+		return
+	}
+
+	e := deploymentEntity(deployment)
+	updates := map[string]any{
+		"deployment_site":              e.Site,
+		"deployment_latitude":          e.Latitude,
+		"deployment_longitude":         e.Longitude,
+		"deployment_habitat":           e.Habitat,
+		"deployment_microphone_model":  e.MicrophoneModel,
+		"deployment_microphone_height": e.MicrophoneHeight,
+	}
+	if err := ds.source.Update(ctx, source.ID, updates); err != nil {
+		if ds.log != nil {
+			ds.log.Warn("audio source deployment update failed during save",
+				logger.Uint64("source_id", uint64(source.ID)),
+				logger.Error(err))
+		}
+		return
+	}
+}
@@ -0,0 +1,58 @@
+package v2only
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+func TestDeploymentEntityRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	d := &conf.DeploymentConfig{Site: "North Meadow", Latitude: 60.5, Longitude: 25.1, Habitat: "wetland edge", MicrophoneModel: "AudioMoth", MicrophoneHeight: 1.5}
+	e := deploymentEntity(d)
+	assert.Equal(t, d, deploymentConfig(&e))
+
+	e = deploymentEntity(nil)
+	assert.Nil(t, e.Site)
+	assert.Nil(t, e.Latitude)
+	assert.Nil(t, deploymentConfig(&e), "all-NULL columns are no deployment")
+}
+
+func TestV2OnlyDatastore_SaveSyncsSourceDeployment(t *testing.T) {
+	ds, cleanup := setupTestDatastore(t)
+	defer cleanup()
+
+	deployment := &conf.DeploymentConfig{Site: "North Meadow", Latitude: 60.5, Longitude: 25.1, Habitat: "wetland edge"}
+	save := func(d *conf.DeploymentConfig) {
+		t.Helper()
+		note := &datastore.Note{
+			Date:           "2024-01-15",
+			Time:           "12:30:00",
+			ScientificName: "Passer domesticus",
+			CommonName:     "House Sparrow",
+			Confidence:     0.85,
+			Source:         datastore.AudioSource{ID: "rtsp_meadow", SafeString: "rtsp://meadow/stream", DisplayName: "Meadow", Deployment: d},
+		}
+		require.NoError(t, ds.Save(note, nil))
+	}
+
+	save(deployment)
+	source, err := ds.source.GetBySourceURI(t.Context(), "rtsp://meadow/stream", "default")
+	require.NoError(t, err)
+	assert.Equal(t, deployment, deploymentConfig(&source.Deployment))
+
+	notes, err := ds.GetAllNotes()
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, deployment, notes[0].Source.Deployment, "reads map the deployment back")
+
+	save(nil)
+	source, err = ds.source.GetBySourceURI(t.Context(), "rtsp://meadow/stream", "default")
+	require.NoError(t, err)
+	assert.Nil(t, deploymentConfig(&source.Deployment), "removing the deployment from the config clears it")
+}
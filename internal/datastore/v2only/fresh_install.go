@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
 	v2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
@@ -17,7 +18,7 @@ import (
 
 // InitializeFreshInstall creates a new v2-only datastore for fresh installations.
 // It creates the database at the configured path with v2 schema.
-// NO _v2 suffix for SQLite, NO v2_ prefix for MySQL tables.
+// NO _v2 suffix for SQLite, NO v2_ prefix for MySQL or PostgreSQL tables.
 //
 // This function should be called when:
 //   - No database exists (fresh installation)
@@ -75,6 +76,15 @@ func InitializeFreshInstall(settings *conf.Settings, log logger.Logger, speciesC
 			Logger:      log,
 		})
 
+	case settings.Output.Postgres.Enabled:
+		// Fresh install: use clean table names, NO v2_ prefix
+		manager, err = v2.NewPostgresManager(&v2.PostgresConfig{
+			PostgresConnection: datastore.PostgresConnectionFromSettings(settings),
+			UseV2Prefix:        false, // NO prefix for fresh installs
+			Debug:              settings.Debug,
+			Logger:             log,
+		})
+
 	default:
 		return nil, errors.Newf("no database configured").
 			Component("datastore").
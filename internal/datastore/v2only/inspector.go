@@ -21,6 +21,9 @@ func (ds *Datastore) GetEngineDetails() (datastore.EngineDetails, error) {
 	if ds.manager.IsMySQL() {
 		return ds.getMySQLEngineDetails()
 	}
+	if ds.manager.IsPostgres() {
+		return datastore.PostgresEngineDetails(ds.manager.DB())
+	}
 	return ds.getSQLiteEngineDetails()
 }
 
@@ -138,6 +141,9 @@ func (ds *Datastore) GetTableStats() ([]datastore.TableStats, error) {
 	if ds.manager.IsMySQL() {
 		return ds.getMySQLTableStats()
 	}
+	if ds.manager.IsPostgres() {
+		return datastore.PostgresTableStats(ds.manager.DB())
+	}
 	return ds.getSQLiteTableStats()
 }
 
@@ -315,7 +321,8 @@ func (ds *Datastore) GetDetectionRate24h() ([]datastore.HourlyCount, error) {
 	var results []datastore.HourlyCount
 	var err error
 
-	if ds.manager.IsMySQL() {
+	switch {
+	case ds.manager.IsMySQL():
 		err = db.Raw(`
 			SELECT DATE_FORMAT(FROM_UNIXTIME(detected_at), '%Y-%m-%dT%H:00:00Z') AS hour,
 			       COUNT(*) AS count
@@ -324,7 +331,16 @@ func (ds *Datastore) GetDetectionRate24h() ([]datastore.HourlyCount, error) {
 			GROUP BY hour
 			ORDER BY hour
 		`).Scan(&results).Error
-	} else {
+	case ds.manager.IsPostgres():
+		err = db.Raw(`
+			SELECT to_char(to_timestamp(detected_at) AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:00:00"Z"') AS hour,
+			       COUNT(*) AS count
+			FROM detections
+			WHERE detected_at > EXTRACT(EPOCH FROM now() - INTERVAL '24 hours')
+			GROUP BY hour
+			ORDER BY hour
+		`).Scan(&results).Error
+	default:
 		err = db.Raw(`
 			SELECT strftime('%Y-%m-%dT%H:00:00Z', detected_at, 'unixepoch') AS hour,
 			       COUNT(*) AS count
@@ -355,7 +371,8 @@ func (ds *Datastore) GetDetectionRateDaily(days int) ([]datastore.DailyCount, er
 	var results []datastore.DailyCount
 	var err error
 
-	if ds.manager.IsMySQL() {
+	switch {
+	case ds.manager.IsMySQL():
 		err = db.Raw(`
 			SELECT DATE_FORMAT(FROM_UNIXTIME(detected_at), '%Y-%m-%d') AS date,
 			       COUNT(*) AS count
@@ -364,7 +381,16 @@ func (ds *Datastore) GetDetectionRateDaily(days int) ([]datastore.DailyCount, er
 			GROUP BY date
 			ORDER BY date
 		`, days).Scan(&results).Error
-	} else {
+	case ds.manager.IsPostgres():
+		err = db.Raw(`
+			SELECT to_char(to_timestamp(detected_at) AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS date,
+			       COUNT(*) AS count
+			FROM detections
+			WHERE detected_at > EXTRACT(EPOCH FROM now() - make_interval(days => ?))
+			GROUP BY date
+			ORDER BY date
+		`, days).Scan(&results).Error
+	default:
 		err = db.Raw(`
 			SELECT strftime('%Y-%m-%d', detected_at, 'unixepoch') AS date,
 			       COUNT(*) AS count
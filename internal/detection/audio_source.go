@@ -1,6 +1,11 @@
 package detection
 
-import "strings"
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
 
 // AudioSource describes where the audio came from.
 // This allows safe separation of concerns: ID for buffer operations,
@@ -11,6 +16,10 @@ type AudioSource struct {
 	DisplayName string // User-friendly name (e.g., "Front Yard Camera")
 	SafeString  string // Connection string with credentials removed (for logging)
 	SampleRate  int    // Source capture sample rate in Hz (0 = default 48kHz)
+
+	// Deployment is the source's configured deployment metadata, nil when the
+	// source has none.
+	Deployment *conf.DeploymentConfig
 }
 
 // NewAudioSource creates an AudioSource with the given ID.
@@ -35,13 +44,16 @@ func NewAudioSourceWithDetails(id, sourceType, displayName, safeString string, s
 }
 
 // DetermineSourceType determines the audio source type from its identifier string.
-// Returns "rtsp", "alsa", "pulseaudio", or "unknown".
+// Returns "rtsp", "alsa", "file", "pulseaudio", or "unknown".
 func DetermineSourceType(safeString string) string {
 	switch {
 	case strings.HasPrefix(safeString, "rtsp://"):
 		return "rtsp"
 	case strings.HasPrefix(safeString, "hw:"):
 		return "alsa"
+	case filepath.IsAbs(safeString):
+		// Offline analysis identifies recordings by their absolute path.
+		return "file"
 	case strings.Contains(safeString, "pulse"):
 		return "pulseaudio"
 	default:
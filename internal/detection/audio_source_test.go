@@ -0,0 +1,32 @@
+package detection
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetermineSourceType(t *testing.T) {
+	t.Parallel()
+	absDir, err := filepath.Abs("recordings")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"rtsp", "rtsp://camera.local/stream", "rtsp"},
+		{"alsa", "hw:1,0", "alsa"},
+		{"file", absDir, "file"},
+		{"pulseaudio", "pulse", "pulseaudio"},
+		{"unknown", "sysdefault", "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, DetermineSourceType(tt.source))
+		})
+	}
+}
@@ -40,7 +40,7 @@ type BootParams struct {
 	ConfigPath      string
 	ConfigExisted   bool
 	ConfigDefaulted bool
-	// Dialect is "sqlite" or "mysql".
+	// Dialect is "sqlite", "mysql" or "postgres".
 	Dialect string
 	// ConfiguredDBPath and V2SidecarPath are the SQLite file paths
 	// (empty for MySQL).
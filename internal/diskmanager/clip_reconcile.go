@@ -6,9 +6,11 @@
 package diskmanager
 
 import (
+	"context"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -75,6 +77,15 @@ type ReconcileStore interface {
 	ClearNoteClipPathsByNames(clipNames []string) (int64, error)
 }
 
+// ArchivedClipLookup reports which clip references the tier retention policy
+// moved to its secondary store. Their files are missing from the export
+// directory by design, so the crawler must not clear them. The v2 datastore's
+// ArchivedClipRepository satisfies it.
+type ArchivedClipLookup interface {
+	// FilterArchived returns the subset of clipPaths that have been archived.
+	FilterArchived(ctx context.Context, clipPaths []string) (map[string]bool, error)
+}
+
 // ReconcileResult summarizes one reconcile pass for logging.
 type ReconcileResult struct {
 	Scanned           int    // clip references read across all chunks
@@ -114,9 +125,13 @@ const (
 //     safely for the old ones.
 //   - Recency guard: rows whose completion time is within ClipRecencyWindow (or is
 //     unknown) are skipped so a clip still being encoded is never cleared.
+//   - Archive guard: when archive is non-nil, missing clips it reports as
+//     archived are skipped, neither cleared nor counted as evidence. If the
+//     lookup fails the pass aborts, since every archived clip would otherwise
+//     read as an orphan.
 //
 // The pass honors quitChan for prompt shutdown.
-func ReconcileClipOrphansPass(quitChan <-chan struct{}, store ReconcileStore, baseDir string) ReconcileResult {
+func ReconcileClipOrphansPass(quitChan <-chan struct{}, store ReconcileStore, baseDir string, archive ArchivedClipLookup) ReconcileResult {
 	log := GetLogger()
 	var result ReconcileResult
 
@@ -173,6 +188,20 @@ func ReconcileClipOrphansPass(quitChan <-chan struct{}, store ReconcileStore, ba
 		// Recompute now per chunk so the recency window tracks wall-clock across a
 		// long, throttled walk.
 		chunk := evaluateClipChunk(root, refs, time.Now())
+
+		// Archive guard.
+		if archive != nil && len(chunk.orphans) > 0 {
+			archived, err := archive.FilterArchived(context.Background(), chunk.orphans)
+			if err != nil {
+				log.Warn("clip reconcile: failed to read archived clips",
+					logger.Error(err),
+					logger.String("operation", "clip_reconcile_read"))
+				result.Aborted = true
+				result.AbortReason = "archive lookup error"
+				return result
+			}
+			chunk.orphans = slices.DeleteFunc(chunk.orphans, func(name string) bool { return archived[name] })
+		}
 		candidates := chunk.positiveCount + len(chunk.orphans)
 
 		// Detached-storage guard.
@@ -1,6 +1,8 @@
 package diskmanager
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -169,13 +171,59 @@ func TestReconcileClipOrphansPass_ClearsOrphansWithPositiveEvidence(t *testing.T
 	}}
 	quit := make(chan struct{})
 
-	result := ReconcileClipOrphansPass(quit, store, baseDir)
+	result := ReconcileClipOrphansPass(quit, store, baseDir, nil)
 
 	assert.False(t, result.Aborted, "should complete: storage evidence present")
 	assert.Equal(t, int64(1), result.Cleared)
 	assert.Equal(t, []string{"2024/01/ghost.wav"}, store.cleared)
 }
 
+// fakeArchiveLookup reports a fixed set of clip names as archived.
+type fakeArchiveLookup struct {
+	archived map[string]bool
+	err      error
+}
+
+func (f *fakeArchiveLookup) FilterArchived(_ context.Context, clipPaths []string) (map[string]bool, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := make(map[string]bool)
+	for _, p := range clipPaths {
+		if f.archived[p] {
+			out[p] = true
+		}
+	}
+	return out, nil
+}
+
+func TestReconcileClipOrphansPass_SkipsArchivedClips(t *testing.T) {
+	withNoChunkPause(t)
+
+	baseDir := t.TempDir()
+	writeClip(t, baseDir, "2024/01/present.wav")
+
+	store := &fakeReconcileStore{refs: []ClipReference{
+		{ID: 1, ClipName: "2024/01/present.wav", CompletionTime: time.Now().Add(testOld)},
+		{ID: 2, ClipName: "2024/01/ghost.wav", CompletionTime: time.Now().Add(testOld)},
+		{ID: 3, ClipName: "2024/01/archived.wav", CompletionTime: time.Now().Add(testOld)},
+	}}
+	archive := &fakeArchiveLookup{archived: map[string]bool{"2024/01/archived.wav": true}}
+
+	result := ReconcileClipOrphansPass(make(chan struct{}), store, baseDir, archive)
+
+	assert.False(t, result.Aborted)
+	assert.Equal(t, []string{"2024/01/ghost.wav"}, store.cleared, "archived clips are missing locally by design")
+
+	// A failed lookup must not fall back to clearing archived clips.
+	store.cleared = nil
+	archive.err = errors.New("database locked")
+	result = ReconcileClipOrphansPass(make(chan struct{}), store, baseDir, archive)
+	assert.True(t, result.Aborted)
+	assert.Equal(t, "archive lookup error", result.AbortReason)
+	assert.Empty(t, store.cleared)
+}
+
 func TestReconcileClipOrphansPass_DetachedStorageGuardAborts(t *testing.T) {
 	withNoChunkPause(t)
 
@@ -189,7 +237,7 @@ func TestReconcileClipOrphansPass_DetachedStorageGuardAborts(t *testing.T) {
 	}}
 	quit := make(chan struct{})
 
-	result := ReconcileClipOrphansPass(quit, store, baseDir)
+	result := ReconcileClipOrphansPass(quit, store, baseDir, nil)
 
 	assert.True(t, result.Aborted)
 	assert.Equal(t, "no attached-storage evidence", result.AbortReason)
@@ -206,7 +254,7 @@ func TestReconcileClipOrphansPass_DirectoryPresentGuard(t *testing.T) {
 	}}
 	quit := make(chan struct{})
 
-	result := ReconcileClipOrphansPass(quit, store, missing)
+	result := ReconcileClipOrphansPass(quit, store, missing, nil)
 
 	assert.True(t, result.Aborted)
 	assert.Equal(t, "export directory inaccessible", result.AbortReason)
@@ -219,7 +267,7 @@ func TestReconcileClipOrphansPass_EmptyBaseDirAborts(t *testing.T) {
 	store := &fakeReconcileStore{}
 	quit := make(chan struct{})
 
-	result := ReconcileClipOrphansPass(quit, store, "   ")
+	result := ReconcileClipOrphansPass(quit, store, "   ", nil)
 
 	assert.True(t, result.Aborted)
 	assert.Equal(t, "export path not configured", result.AbortReason)
@@ -238,7 +286,7 @@ func TestReconcileClipOrphansPass_RecentRowsNotCleared(t *testing.T) {
 	}}
 	quit := make(chan struct{})
 
-	result := ReconcileClipOrphansPass(quit, store, baseDir)
+	result := ReconcileClipOrphansPass(quit, store, baseDir, nil)
 
 	assert.False(t, result.Aborted)
 	assert.Empty(t, store.cleared, "recent missing clip must not be cleared")
@@ -267,7 +315,7 @@ func TestReconcileClipOrphansPass_KeysetPaginationAcrossChunks(t *testing.T) {
 	store := &fakeReconcileStore{refs: refs}
 	quit := make(chan struct{})
 
-	result := ReconcileClipOrphansPass(quit, store, baseDir)
+	result := ReconcileClipOrphansPass(quit, store, baseDir, nil)
 
 	assert.False(t, result.Aborted, "no chunk is all-orphan, so the pass must complete")
 	assert.Equal(t, len(refs), result.Scanned, "must scan every ref across chunks")
@@ -297,7 +345,7 @@ func TestReconcileClipOrphansPass_AbortsMidPassOnAllOrphanChunk(t *testing.T) {
 	store := &fakeReconcileStore{refs: refs}
 	quit := make(chan struct{})
 
-	result := ReconcileClipOrphansPass(quit, store, baseDir)
+	result := ReconcileClipOrphansPass(quit, store, baseDir, nil)
 
 	assert.True(t, result.Aborted, "second, all-orphan chunk must abort the pass")
 	assert.Equal(t, "no attached-storage evidence", result.AbortReason)
@@ -316,7 +364,7 @@ func TestReconcileClipOrphansPass_HonorsQuitChannel(t *testing.T) {
 	quit := make(chan struct{})
 	close(quit) // already shutting down
 
-	result := ReconcileClipOrphansPass(quit, store, baseDir)
+	result := ReconcileClipOrphansPass(quit, store, baseDir, nil)
 
 	assert.True(t, result.Aborted)
 	assert.Equal(t, "shutdown", result.AbortReason)
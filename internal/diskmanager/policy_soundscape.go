@@ -0,0 +1,128 @@
+// policy_soundscape.go - retention for continuous soundscape recordings
+package diskmanager
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// maxSoundscapeRemoveErrors aborts a soundscape cleanup run after this many
+// segments could not be removed, e.g. on a read-only volume.
+const maxSoundscapeRemoveErrors = 10
+
+// SoundscapeStore is the segment index SoundscapeCleanup works through.
+type SoundscapeStore interface {
+	Oldest(ctx context.Context, limit int) ([]entities.SoundscapeSegment, error)
+	TotalSize(ctx context.Context) (int64, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// SoundscapeCleanup deletes the oldest soundscape segments until none is
+// older than maxAge and their total size is at most maxBytes. A zero limit is
+// not enforced. Segments are deleted through the index, so files the index
+// does not know about are never touched, and file paths are resolved inside
+// baseDir with os.Root so an index entry cannot point outside it.
+//
+// Returns a CleanupResult with the number of segments removed; DiskUtilization
+// is not measured.
+func SoundscapeCleanup(quit <-chan struct{}, store SoundscapeStore, baseDir string, maxAge time.Duration, maxBytes int64, now time.Time) CleanupResult {
+	log := GetLogger()
+	var result CleanupResult
+	if maxAge <= 0 && maxBytes <= 0 {
+		return result
+	}
+	if strings.TrimSpace(baseDir) == "" {
+		return result
+	}
+
+	ctx := context.Background()
+	total, err := store.TotalSize(ctx)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	segments, err := store.Oldest(ctx, maxDeletionsPerRun)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if len(segments) == 0 {
+		return result
+	}
+
+	root, err := os.OpenRoot(baseDir)
+	if err != nil {
+		// An unmounted volume must not empty the index.
+		result.Err = err
+		return result
+	}
+	defer func() { _ = root.Close() }()
+
+	cutoff := now.Add(-maxAge)
+	errorCount := 0
+	for i := range segments {
+		select {
+		case <-quit:
+			return result
+		default:
+		}
+
+		seg := &segments[i]
+		expired := maxAge > 0 && seg.EndTime().Before(cutoff)
+		oversize := maxBytes > 0 && total > maxBytes
+		if !expired && !oversize {
+			// Segments are oldest first, so the rest are newer and the
+			// total only shrinks from here.
+			break
+		}
+
+		if err := root.Remove(seg.FilePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Warn("failed to remove soundscape segment",
+				logger.String("path", seg.FilePath),
+				logger.Error(err),
+				logger.String("operation", "soundscape_cleanup"))
+			errorCount++
+			if errorCount > maxSoundscapeRemoveErrors {
+				result.Err = err
+				return result
+			}
+			continue
+		}
+		if err := store.Delete(ctx, seg.ID); err != nil {
+			result.Err = err
+			return result
+		}
+		removeEmptyParents(root, seg.FilePath)
+
+		total -= seg.FileSize
+		result.ClipsRemoved++
+	}
+
+	if result.ClipsRemoved > 0 {
+		log.Info("soundscape cleanup completed",
+			logger.Int("segments_removed", result.ClipsRemoved),
+			logger.Int64("total_bytes", total),
+			logger.String("operation", "soundscape_cleanup"))
+	}
+	return result
+}
+
+// removeEmptyParents removes the now empty day, month, year and source
+// directories above a deleted segment. Removing a non-empty directory fails,
+// which ends the walk.
+func removeEmptyParents(root *os.Root, file string) {
+	for dir := path.Dir(file); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if root.Remove(dir) != nil {
+			return
+		}
+	}
+}
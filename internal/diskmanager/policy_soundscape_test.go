@@ -0,0 +1,112 @@
+package diskmanager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// fakeSoundscapeStore is an in-memory SoundscapeStore ordered oldest first.
+type fakeSoundscapeStore struct {
+	segments []entities.SoundscapeSegment
+}
+
+func (f *fakeSoundscapeStore) Oldest(_ context.Context, limit int) ([]entities.SoundscapeSegment, error) {
+	return slices.Clone(f.segments[:min(limit, len(f.segments))]), nil
+}
+
+func (f *fakeSoundscapeStore) TotalSize(_ context.Context) (int64, error) {
+	var total int64
+	for i := range f.segments {
+		total += f.segments[i].FileSize
+	}
+	return total, nil
+}
+
+func (f *fakeSoundscapeStore) Delete(_ context.Context, id uint) error {
+	f.segments = slices.DeleteFunc(f.segments, func(s entities.SoundscapeSegment) bool { return s.ID == id })
+	return nil
+}
+
+func newSoundscapeFixture(t *testing.T, baseDir string, start time.Time, count int) *fakeSoundscapeStore {
+	t.Helper()
+	store := &fakeSoundscapeStore{}
+	for i := range count {
+		segStart := start.Add(time.Duration(i) * time.Hour)
+		rel := "rtsp_abc/" + segStart.Format("2006/01/02") + "/rtsp_abc_" + segStart.Format("20060102T150405Z") + ".flac"
+		writeClip(t, baseDir, rel)
+		store.segments = append(store.segments, entities.SoundscapeSegment{
+			ID:        uint(i + 1),
+			SourceID:  "rtsp_abc",
+			StartTime: segStart,
+			Duration:  300,
+			FilePath:  rel,
+			FileSize:  100,
+		})
+	}
+	return store
+}
+
+func TestSoundscapeCleanup_MaxAge(t *testing.T) {
+	t.Parallel()
+	baseDir := t.TempDir()
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	store := newSoundscapeFixture(t, baseDir, start, 4)
+
+	// Segments start hourly; the first two end before the cutoff.
+	now := start.Add(2*time.Hour + 24*time.Hour)
+	result := SoundscapeCleanup(make(chan struct{}), store, baseDir, 24*time.Hour+time.Minute, 0, now)
+	require.NoError(t, result.Err)
+	assert.Equal(t, 2, result.ClipsRemoved)
+	require.Len(t, store.segments, 2)
+	assert.Equal(t, uint(3), store.segments[0].ID)
+
+	_, err := os.Stat(filepath.Join(baseDir, "rtsp_abc", "2026", "05", "01"))
+	assert.NoError(t, err, "day directory still holds newer segments")
+}
+
+func TestSoundscapeCleanup_MaxSizeRemovesEmptyDirectories(t *testing.T) {
+	t.Parallel()
+	baseDir := t.TempDir()
+	store := newSoundscapeFixture(t, baseDir, time.Date(2026, 5, 1, 22, 0, 0, 0, time.UTC), 3)
+
+	// 300 bytes indexed, 100 allowed: the two oldest go, emptying May 1st.
+	result := SoundscapeCleanup(make(chan struct{}), store, baseDir, 0, 100, time.Now())
+	require.NoError(t, result.Err)
+	assert.Equal(t, 2, result.ClipsRemoved)
+	require.Len(t, store.segments, 1)
+
+	_, err := os.Stat(filepath.Join(baseDir, "rtsp_abc", "2026", "05", "01"))
+	assert.True(t, os.IsNotExist(err), "empty day directory is removed")
+	_, err = os.Stat(filepath.Join(baseDir, "rtsp_abc", "2026", "05", "02"))
+	assert.NoError(t, err)
+}
+
+func TestSoundscapeCleanup_MissingFilesAndLimits(t *testing.T) {
+	t.Parallel()
+	baseDir := t.TempDir()
+	store := newSoundscapeFixture(t, baseDir, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), 2)
+	require.NoError(t, os.Remove(filepath.Join(baseDir, filepath.FromSlash(store.segments[0].FilePath))))
+
+	// No limits: nothing happens.
+	result := SoundscapeCleanup(make(chan struct{}), store, baseDir, 0, 0, time.Now())
+	assert.Zero(t, result.ClipsRemoved)
+
+	// A segment whose file is already gone is still dropped from the index.
+	result = SoundscapeCleanup(make(chan struct{}), store, baseDir, 0, 100, time.Now())
+	require.NoError(t, result.Err)
+	assert.Equal(t, 1, result.ClipsRemoved)
+
+	// An inaccessible directory aborts without touching the index.
+	result = SoundscapeCleanup(make(chan struct{}), store, filepath.Join(baseDir, "missing"), 0, 1, time.Now())
+	require.Error(t, result.Err)
+	assert.Len(t, store.segments, 1)
+}
@@ -0,0 +1,202 @@
+// policy_tier.go - tier retention policy: move older clips to a secondary store
+package diskmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// recallRetention is how long a clip recalled from the secondary store for
+// playback stays in the clip directory before the tier policy removes the
+// local copy again.
+const recallRetention = 24 * time.Hour
+
+// tierUsageRefreshInterval is how often, in moved clips, the tier policy
+// re-reads disk usage while it moves clips to relieve a full disk.
+const tierUsageRefreshInterval = 50
+
+// ClipArchiver moves clips to the secondary store of the tier policy. Clip
+// paths are relative to the clip export directory with forward slashes, as
+// stored in the database. It is implemented by cliptier.Archiver.
+type ClipArchiver interface {
+	ArchivedClipLookup
+	// Archive moves the clip, and its spectrograms unless keepSpectrograms
+	// is set, to the secondary store and returns the bytes freed locally.
+	Archive(ctx context.Context, clipPath string, keepSpectrograms bool) (int64, error)
+}
+
+// TierCleanup moves clips older than retention.MaxAge to the secondary store
+// instead of deleting them. When retention.MaxUsage is set, the oldest clips
+// are also moved while disk usage is above it. Locked clips stay local, and
+// database clip paths are left untouched so archived clips remain listed and
+// playable. Local copies of archived clips, written when a clip is played,
+// are removed once they are older than recallRetention.
+//
+// Returns a CleanupResult with the number of clips moved or evicted from the
+// local disk.
+func TierCleanup(quit <-chan struct{}, db Interface, archiver ClipArchiver, baseDir string, retention *conf.RetentionSettings, now time.Time) CleanupResult {
+	log := GetLogger()
+	startTime := time.Now()
+
+	maxAgeHours, err := conf.ParseRetentionPeriod(strings.TrimSpace(retention.MaxAge))
+	if err != nil {
+		return CleanupResult{Err: fmt.Errorf("invalid retention period '%s': %w", retention.MaxAge, err)}
+	}
+	cutoff := now.Add(-time.Duration(maxAgeHours) * time.Hour)
+
+	var usageThreshold float64
+	if maxUsage := strings.TrimSpace(retention.MaxUsage); maxUsage != "" {
+		usageThreshold, err = conf.ParsePercentage(maxUsage, configKeyRetentionMaxUsage)
+		if err != nil {
+			return CleanupResult{Err: fmt.Errorf("failed to parse usage threshold '%s': %w", maxUsage, err)}
+		}
+	}
+
+	files, err := GetAudioFiles(baseDir, allowedFileTypes, db)
+	if err != nil {
+		return CleanupResult{Err: fmt.Errorf("failed to get audio files for cleanup: %w", err)}
+	}
+	if len(files) == 0 {
+		return CleanupResult{DiskUtilization: diskUtilization(baseDir)}
+	}
+
+	// Oldest first, so clips leave the local disk in the order they age out.
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Timestamp.Before(files[j].Timestamp)
+	})
+
+	clipPaths := make([]string, len(files))
+	for i := range files {
+		if rel, err := filepath.Rel(baseDir, files[i].Path); err == nil {
+			clipPaths[i] = filepath.ToSlash(rel)
+		}
+	}
+
+	ctx := context.Background()
+	archived, err := archiver.FilterArchived(ctx, clipPaths)
+	if err != nil {
+		return CleanupResult{Err: err, DiskUtilization: diskUtilization(baseDir)}
+	}
+
+	usage := 0.0
+	if usageThreshold > 0 {
+		if usage, err = GetDiskUsage(baseDir); err != nil {
+			log.Warn("Failed to get disk usage, moving clips by age only",
+				logger.String("policy", "tier"),
+				logger.Error(err))
+			usageThreshold = 0
+		}
+	}
+
+	moved, evicted, errorCount := 0, 0, 0
+	var freed int64
+	var loopErr error
+	for i := range files {
+		select {
+		case <-quit:
+			log.Info("Tier cleanup loop interrupted by quit signal",
+				logger.String("policy", "tier"),
+				logger.Int("files_moved", moved))
+			return CleanupResult{ClipsRemoved: moved + evicted, DiskUtilization: diskUtilization(baseDir)}
+		default:
+		}
+		if moved+evicted >= maxDeletionsPerRun {
+			break
+		}
+
+		file := &files[i]
+		clipPath := clipPaths[i]
+		if clipPath == "" {
+			continue
+		}
+
+		if archived[clipPath] {
+			if evictRecalledClip(file, now) {
+				evicted++
+			}
+			continue
+		}
+
+		if checkLocked(file) {
+			continue
+		}
+		expired := file.Timestamp.Before(cutoff)
+		overUsage := usageThreshold > 0 && usage >= usageThreshold
+		if !expired && !overUsage {
+			// Files are oldest first, so the rest are newer; only recalled
+			// copies could still be evicted, which the next run handles.
+			break
+		}
+
+		n, archiveErr := archiver.Archive(ctx, clipPath, retention.KeepSpectrograms)
+		if archiveErr != nil {
+			shouldStop, stopErr := handleDeletionErrorInLoop(file.Path, archiveErr, &errorCount, 10, "tier")
+			if shouldStop {
+				loopErr = stopErr
+				break
+			}
+			continue
+		}
+		moved++
+		freed += n
+		if m := getMetrics(); m != nil {
+			m.RecordFileProcessed("tier", "archived")
+			m.RecordBytesFreed("tier", float64(n))
+		}
+
+		if usageThreshold > 0 && moved%tierUsageRefreshInterval == 0 {
+			if refreshed, err := GetDiskUsage(baseDir); err == nil {
+				usage = refreshed
+			}
+		}
+	}
+
+	utilization := diskUtilization(baseDir)
+	log.Info("Tier cleanup run completed",
+		logger.String("policy", "tier"),
+		logger.Int("files_moved", moved),
+		logger.Int("recalled_copies_removed", evicted),
+		logger.Int64("bytes_freed", freed),
+		logger.Int("disk_utilization", utilization),
+		logger.Int64("duration_ms", time.Since(startTime).Milliseconds()))
+
+	return CleanupResult{Err: loopErr, ClipsRemoved: moved + evicted, DiskUtilization: utilization}
+}
+
+// evictRecalledClip removes the local copy of an archived clip once it has
+// not been recalled for recallRetention. The copy's modification time is the
+// time of the recall.
+func evictRecalledClip(file *FileInfo, now time.Time) bool {
+	info, err := os.Stat(file.Path)
+	if err != nil || now.Sub(info.ModTime()) < recallRetention {
+		return false
+	}
+	if err := os.Remove(file.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		GetLogger().Warn("Failed to remove recalled copy of archived clip",
+			logger.String("policy", "tier"),
+			logger.String("path", file.Path),
+			logger.Error(err))
+		return false
+	}
+	return true
+}
+
+// diskUtilization returns the disk usage of baseDir as a whole percentage,
+// or zero when it cannot be read.
+func diskUtilization(baseDir string) int {
+	usage, err := GetDiskUsage(baseDir)
+	if err != nil {
+		return 0
+	}
+	return int(usage)
+}
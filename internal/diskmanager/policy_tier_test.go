@@ -0,0 +1,84 @@
+package diskmanager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// fakeClipArchiver records archived clips and removes the local file like
+// cliptier.Archiver does.
+type fakeClipArchiver struct {
+	baseDir  string
+	archived map[string]bool
+}
+
+func (f *fakeClipArchiver) FilterArchived(_ context.Context, clipPaths []string) (map[string]bool, error) {
+	out := make(map[string]bool)
+	for _, p := range clipPaths {
+		if f.archived[p] {
+			out[p] = true
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeClipArchiver) Archive(_ context.Context, clipPath string, _ bool) (int64, error) {
+	full := filepath.Join(f.baseDir, filepath.FromSlash(clipPath))
+	info, err := os.Stat(full)
+	if err != nil {
+		return 0, err
+	}
+	f.archived[clipPath] = true
+	return info.Size(), os.Remove(full)
+}
+
+func TestTierCleanup(t *testing.T) {
+	t.Parallel()
+	baseDir := t.TempDir()
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.Local)
+
+	oldClip := "2026/04/parus_major_80p_20260401T060000Z.wav"
+	newClip := "2026/05/parus_major_80p_20260530T060000Z.wav"
+	recalledOld := "2026/03/turdus_merula_90p_20260301T060000Z.wav"
+	recalledNew := "2026/03/turdus_merula_90p_20260302T060000Z.wav"
+	for _, rel := range []string{oldClip, newClip, recalledOld, recalledNew} {
+		writeClip(t, baseDir, rel)
+	}
+	// Recalled copies age by their modification time, the time of the recall.
+	stale := now.Add(-2 * recallRetention)
+	require.NoError(t, os.Chtimes(filepath.Join(baseDir, filepath.FromSlash(recalledOld)), stale, stale))
+	fresh := now.Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(baseDir, filepath.FromSlash(recalledNew)), fresh, fresh))
+
+	archiver := &fakeClipArchiver{baseDir: baseDir, archived: map[string]bool{recalledOld: true, recalledNew: true}}
+	retention := &conf.RetentionSettings{Policy: conf.RetentionPolicyTier, MaxAge: "30d"}
+
+	result := TierCleanup(make(chan struct{}), &MockDB{}, archiver, baseDir, retention, now)
+
+	require.NoError(t, result.Err)
+	assert.Equal(t, 2, result.ClipsRemoved, "one clip moved and one stale recalled copy removed")
+	assert.True(t, archiver.archived[oldClip])
+	assert.False(t, archiver.archived[newClip])
+	assert.NoFileExists(t, filepath.Join(baseDir, filepath.FromSlash(oldClip)))
+	assert.FileExists(t, filepath.Join(baseDir, filepath.FromSlash(newClip)))
+	assert.NoFileExists(t, filepath.Join(baseDir, filepath.FromSlash(recalledOld)))
+	assert.FileExists(t, filepath.Join(baseDir, filepath.FromSlash(recalledNew)), "a recently played clip stays local")
+}
+
+func TestTierCleanup_InvalidMaxAge(t *testing.T) {
+	t.Parallel()
+	archiver := &fakeClipArchiver{baseDir: t.TempDir(), archived: map[string]bool{}}
+	retention := &conf.RetentionSettings{Policy: conf.RetentionPolicyTier, MaxAge: "soon"}
+
+	result := TierCleanup(make(chan struct{}), &MockDB{}, archiver, archiver.baseDir, retention, time.Now())
+	require.Error(t, result.Err)
+	assert.Zero(t, result.ClipsRemoved)
+}
@@ -212,7 +212,7 @@ func (m *mockStore) CountSpeciesDetections(species, date, hour string, duration
 	return 0, nil
 }
 func (m *mockStore) Transaction(fc func(tx *gorm.DB) error) error { return nil }
-func (m *mockStore) LockNote(noteID string) error                 { return nil }
+func (m *mockStore) LockNote(noteID, lockedBy string) error       { return nil }
 func (m *mockStore) UnlockNote(noteID string) error               { return nil }
 func (m *mockStore) GetNoteLock(noteID string) (*datastore.NoteLock, error) {
 	return nil, datastore.ErrNoteLockNotFound
@@ -0,0 +1,129 @@
+// Package analyzer implements the imports.Source interface for BirdNET-Analyzer
+// result CSV files.
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/imports"
+)
+
+// SourceNode identifies BirdNET-Analyzer imports in the detection's model version.
+const SourceNode = "birdnet-analyzer"
+
+const component = "imports/analyzer"
+
+// resultSuffixes are the names BirdNET-Analyzer gives per-recording result
+// files, longest first: the "r" and "kaleidoscope" layouts, then plain csv.
+var resultSuffixes = []string{
+	".BirdNET.results.kaleidoscope.csv",
+	".BirdNET.results.r.csv",
+	".BirdNET.results.csv",
+}
+
+// Column aliases across the csv, r and kaleidoscope result layouts.
+var (
+	colStart      = []string{"Start (s)", "start", "OFFSET"}
+	colEnd        = []string{"End (s)", "end"}
+	colDuration   = []string{"DURATION"}
+	colScientific = []string{"Scientific name", "scientific_name"}
+	colCommon     = []string{"Common name", "common_name"}
+	colConfidence = []string{"Confidence"}
+	colFile       = []string{"File", "filepath", "IN FILE"}
+	colFolder     = []string{"FOLDER"}
+	colLatitude   = []string{"lat"}
+	colLongitude  = []string{"lon"}
+	colMinConf    = []string{"min_conf"}
+	colSens       = []string{"sensitivity"}
+)
+
+// New returns a source over a BirdNET-Analyzer result file, the combined table,
+// or a directory of per-recording result files. Detection times are the
+// recording start parsed from the audio file name plus the detection offset,
+// so recordings must be named with their start time as field recorders do.
+func New(path string, opts imports.FileSourceOptions) (*imports.TableSource, error) {
+	opts = opts.WithDefaults(path)
+	p := &parser{audioDir: opts.AudioDir, paired: make(map[string]string)}
+	return imports.NewTableSource(path, &imports.TableSpec{
+		Component: component,
+		Comma:     ',',
+		Match: func(name string) bool {
+			return strings.EqualFold(filepath.Ext(name), ".csv")
+		},
+		Accept: accept,
+		Parse:  p.parse,
+	})
+}
+
+func accept(h imports.Header) error {
+	switch {
+	case !h.Has(colStart...):
+		return errors.NewStd("missing start time column")
+	case !h.Has(colScientific...) && !h.Has(colCommon...):
+		return errors.NewStd("missing species name column")
+	case !h.Has(colConfidence...):
+		return errors.NewStd("missing confidence column")
+	}
+	return nil
+}
+
+// parser maps result rows to detections. Files are read one at a time, so it
+// needs no locking.
+type parser struct {
+	audioDir string
+	paired   map[string]string // recording -> AudioPath, "" when not found
+}
+
+func (p *parser) parse(row *imports.TableRow) (imports.SourceDetection, bool) {
+	start, ok := row.Float(colStart...)
+	if !ok {
+		return imports.SourceDetection{}, false
+	}
+	end, ok := row.Float(colEnd...)
+	if !ok {
+		if d, hasDuration := row.Float(colDuration...); hasDuration {
+			end = start + d
+		}
+	}
+	confidence, _ := row.Float(colConfidence...)
+
+	det := imports.SourceDetection{
+		ScientificName: row.Get(colScientific...),
+		CommonName:     row.Get(colCommon...),
+		Confidence:     confidence,
+		ClipStart:      start,
+		ClipEnd:        end,
+	}
+	det.Latitude, _ = row.Float(colLatitude...)
+	det.Longitude, _ = row.Float(colLongitude...)
+	det.Cutoff, _ = row.Float(colMinConf...)
+	det.Sensitivity, _ = row.Float(colSens...)
+
+	recorded := row.Get(colFile...)
+	if folder := row.Get(colFolder...); folder != "" && recorded != "" {
+		recorded = filepath.Join(folder, recorded)
+	}
+	name := recorded
+	if name == "" {
+		name = row.File
+	}
+	if recStart, found := imports.RecordingStart(name); found {
+		det.Date, det.Time = imports.WallClock(recStart.Add(time.Duration(start * float64(time.Second))))
+	}
+	det.AudioPath = p.audioPath(row.File, recorded)
+	return det, true
+}
+
+// audioPath pairs a row with its recording, caching the lookup per recording.
+func (p *parser) audioPath(resultFile, recorded string) string {
+	key := resultFile + "\x00" + recorded
+	if path, ok := p.paired[key]; ok {
+		return path
+	}
+	path := imports.PairedAudio(p.audioDir, resultFile, recorded, resultSuffixes...)
+	p.paired[key] = path
+	return path
+}
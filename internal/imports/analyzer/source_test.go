@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/imports"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+// readAll validates the source and returns every detection it yields.
+func readAll(t *testing.T, path string, opts imports.FileSourceOptions) []imports.SourceDetection {
+	t.Helper()
+	src, err := New(path, opts)
+	require.NoError(t, err)
+	require.NoError(t, src.Validate(t.Context()))
+	var rows []imports.SourceDetection
+	require.NoError(t, src.Iterate(t.Context(), 100, func(batch []imports.SourceDetection) error {
+		rows = append(rows, batch...)
+		return nil
+	}))
+	return rows
+}
+
+func TestSource_PerRecordingResults(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "20240501_053000.WAV"), "audio")
+	writeFile(t, filepath.Join(dir, "20240501_053000.BirdNET.results.csv"),
+		"Start (s),End (s),Scientific name,Common name,Confidence\n"+
+			"0.0,3.0,Turdus merula,Eurasian Blackbird,0.8512\n"+
+			"63.0,66.0,Parus major,Great Tit,0.61\n")
+
+	rows := readAll(t, dir, imports.FileSourceOptions{})
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, imports.SourceDetection{
+		Date: "2024-05-01", Time: "05:30:00",
+		ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird", Confidence: 0.8512,
+		AudioPath: "20240501_053000.WAV", ClipStart: 0, ClipEnd: 3,
+	}, rows[0])
+	assert.Equal(t, "05:31:03", rows[1].Time)
+	assert.InDelta(t, 63.0, rows[1].ClipStart, 0.001)
+}
+
+func TestSource_CombinedTableWithFileColumn(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	audioDir := t.TempDir()
+	writeFile(t, filepath.Join(audioDir, "2024", "SMA01_20240502_210000.wav"), "audio")
+	table := filepath.Join(dir, "BirdNET_CombinedTable.csv")
+	writeFile(t, table,
+		"Start (s),End (s),Scientific name,Common name,Confidence,File\n"+
+			`9.0,12.0,Strix aluco,Tawny Owl,0.9,D:\field\2024\SMA01_20240502_210000.wav`+"\n"+
+			"1.5,4.5,Bubo bubo,Eurasian Eagle-Owl,0.7,unnamed.wav\n")
+
+	rows := readAll(t, table, imports.FileSourceOptions{AudioDir: audioDir})
+	require.Len(t, rows, 2)
+	assert.Equal(t, "2024-05-02", rows[0].Date)
+	assert.Equal(t, "21:00:09", rows[0].Time)
+	assert.Equal(t, "2024/SMA01_20240502_210000.wav", rows[0].AudioPath)
+	assert.Empty(t, rows[1].Date, "recording without a start time cannot be placed in time")
+	assert.Empty(t, rows[1].AudioPath)
+}
+
+func TestSource_RAndKaleidoscopeLayouts(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	r := filepath.Join(dir, "20240501_053000.BirdNET.results.r.csv")
+	writeFile(t, r,
+		"filepath,start,end,scientific_name,common_name,confidence,lat,lon,week,overlap,sensitivity,min_conf,species_list,model\n"+
+			"/data/20240501_053000.wav,3.0,6.0,Erithacus rubecula,European Robin,0.72,60.1,24.9,18,0,1.25,0.5,,BirdNET_V2.4\n")
+	k := filepath.Join(dir, "20240501_053000.BirdNET.results.kaleidoscope.csv")
+	writeFile(t, k,
+		"INDIR,FOLDER,IN FILE,OFFSET,DURATION,scientific_name,common_name,confidence,lat,lon,week,overlap,sensitivity\n"+
+			"/data,site,20240501_053000.wav,6.0,3.0,Erithacus rubecula,European Robin,0.66,-1,-1,-1,0,1.0\n")
+
+	rows := readAll(t, r, imports.FileSourceOptions{})
+	require.Len(t, rows, 1)
+	assert.Equal(t, "05:30:03", rows[0].Time)
+	assert.InDelta(t, 60.1, rows[0].Latitude, 0.001)
+	assert.InDelta(t, 1.25, rows[0].Sensitivity, 0.001)
+	assert.InDelta(t, 0.5, rows[0].Cutoff, 0.001)
+
+	rows = readAll(t, k, imports.FileSourceOptions{})
+	require.Len(t, rows, 1)
+	assert.Equal(t, "05:30:06", rows[0].Time)
+	assert.InDelta(t, 9.0, rows[0].ClipEnd, 0.001, "end is offset plus duration")
+}
+
+func TestSource_RejectsOtherCSV(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "species.csv")
+	writeFile(t, file, "Common name,Count\nGreat Tit,3\n")
+
+	src, err := New(file, imports.FileSourceOptions{})
+	require.NoError(t, err)
+	require.Error(t, src.Validate(t.Context()))
+}
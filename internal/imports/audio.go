@@ -14,6 +14,7 @@ import (
 	"golang.org/x/sync/semaphore"
 
 	"github.com/tphakala/birdnet-go/internal/audiocore/audiotemp"
+	"github.com/tphakala/birdnet-go/internal/audiocore/ffmpeg"
 	"github.com/tphakala/birdnet-go/internal/diskmanager"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
@@ -22,6 +23,27 @@ import (
 // audioWorkerLimit is the maximum number of concurrent clip-copy goroutines per batch.
 const audioWorkerLimit = 4
 
+// defaultClipFormat is the format of clips cut from longer recordings.
+const defaultClipFormat = ffmpeg.FormatWAV
+
+// segmentBytesPerSecond estimates the size of a clip cut from a longer recording
+// for the disk-space pre-check: 48 kHz 16-bit mono WAV, the largest format cut.
+const segmentBytesPerSecond = 96_000
+
+// clipFormats are the formats clips can be cut to. Their file extension is the
+// format name.
+var clipFormats = map[string]bool{
+	ffmpeg.FormatWAV:  true,
+	ffmpeg.FormatFLAC: true,
+	ffmpeg.FormatMP3:  true,
+	ffmpeg.FormatOpus: true,
+}
+
+// isSegment reports whether the detection covers part of a longer recording.
+func (d *SourceDetection) isSegment() bool {
+	return d.AudioPath != "" && d.ClipEnd > d.ClipStart
+}
+
 // sanitizePathComponent validates that s is a single safe path component.
 // Returns the component and true if safe, or ("", false) if s contains a path separator,
 // is ".", is "..", or is empty. A crafted DB value containing ".." is treated as "not found".
@@ -106,6 +128,31 @@ func resolveSourceClipRel(root *os.Root, date, comName, fileName string) (string
 	return "", false
 }
 
+// resolveAudioPathRel resolves a source-provided AudioPath within root. It returns
+// the cleaned relative path and true when it names a regular file inside root.
+// Absolute paths and paths climbing out of root are treated as not found; root.Stat
+// also rejects a symlink that escapes the root.
+func resolveAudioPathRel(root *os.Root, audioPath string) (string, bool) {
+	rel := filepath.Clean(filepath.FromSlash(audioPath))
+	if !filepath.IsLocal(rel) {
+		return "", false
+	}
+	info, err := root.Stat(rel)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+	return rel, true
+}
+
+// resolveRowAudio returns the row's source audio within root, using AudioPath when
+// the source provides one and the BirdNET-Pi layout otherwise.
+func resolveRowAudio(root *os.Root, row *SourceDetection) (string, bool) {
+	if row.AudioPath != "" {
+		return resolveAudioPathRel(root, row.AudioPath)
+	}
+	return resolveSourceClipRel(root, row.Date, row.CommonName, row.FileName)
+}
+
 // targetClipRelPath constructs the relative clip path used in the export store,
 // mirroring the format produced by buildClipPath in internal/analysis/processor/processor.go.
 // Format: "YYYY/MM/<scientificName_lowercased_underscored>_<conf>p_<YYYYMMDDTHHMMSSZ>.<srcExt>"
@@ -212,6 +259,76 @@ func copyClipAtomic(root *os.Root, srcRel, destAbsPath string) error {
 	return nil
 }
 
+// cutClipAtomic extracts the row's ClipStart..ClipEnd stretch of the recording at
+// srcRel (within audioSourceDir) into destAbsPath with FFmpeg, via a unique temp
+// file and atomic rename. srcRel has been resolved through an *os.Root, but FFmpeg
+// opens it by path, so the recording is re-checked to still resolve inside
+// audioSourceDir just before extraction.
+func cutClipAtomic(ctx context.Context, opts *ImportOptions, srcRel string, row *SourceDetection, destAbsPath string) error {
+	srcAbs, err := filepath.EvalSymlinks(filepath.Join(opts.AudioSourceDir, srcRel))
+	if err != nil {
+		return errors.New(err).
+			Component("imports/audio").
+			Category(errors.CategoryFileIO).
+			Context("operation", "resolve_src").
+			Context("path", srcRel).
+			Build()
+	}
+	rootAbs, err := filepath.EvalSymlinks(opts.AudioSourceDir)
+	if err != nil || !isWithinDir(rootAbs, srcAbs) {
+		return errors.Newf("source recording escapes the audio source directory").
+			Component("imports/audio").
+			Category(errors.CategoryValidation).
+			Context("operation", "resolve_src").
+			Context("path", srcRel).
+			Build()
+	}
+
+	buf, err := ffmpeg.ExtractClip(ctx, &ffmpeg.ClipOptions{
+		InputPath:  srcAbs,
+		Start:      row.ClipStart,
+		End:        row.ClipEnd,
+		Format:     opts.ClipFormat,
+		FFmpegPath: opts.FFmpegPath,
+	})
+	if err != nil {
+		return errors.New(err).
+			Component("imports/audio").
+			Category(errors.CategoryAudio).
+			Context("operation", "extract_clip").
+			Context("path", srcRel).
+			Build()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destAbsPath), 0o755); err != nil {
+		return errors.New(err).
+			Component("imports/audio").
+			Category(errors.CategoryFileIO).
+			Context("operation", "mkdir").
+			Context("path", filepath.Dir(destAbsPath)).
+			Build()
+	}
+	tmpPath := audiotemp.UniquePath(destAbsPath)
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o644); err != nil { //nolint:gosec // G306: clips are world-readable like exported clips
+		_ = os.Remove(tmpPath)
+		return errors.New(err).
+			Component("imports/audio").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_temp").
+			Context("path", tmpPath).
+			Build()
+	}
+	if err := audiotemp.Finalize(tmpPath, destAbsPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.New(err).
+			Component("imports/audio").
+			Category(errors.CategoryFileIO).
+			Context("operation", "rename").
+			Build()
+	}
+	return nil
+}
+
 // sumSourceClipSizes sums the sizes of all source clips for the given source detections
 // within the audio source directory. Missing clips (and an unopenable source directory)
 // are skipped silently; this is a best-effort size estimate for the disk-space pre-check.
@@ -225,7 +342,11 @@ func sumSourceClipSizes(audioSourceDir string, rows []SourceDetection) uint64 {
 	var total uint64
 	for i := range rows {
 		row := &rows[i]
-		srcRel, ok := resolveSourceClipRel(root, row.Date, row.CommonName, row.FileName)
+		if row.isSegment() {
+			total += uint64((row.ClipEnd - row.ClipStart) * segmentBytesPerSecond)
+			continue
+		}
+		srcRel, ok := resolveRowAudio(root, row)
 		if !ok {
 			continue
 		}
@@ -318,8 +439,14 @@ func (e *Engine) copyCandidateClips(
 			default:
 			}
 
-			srcExt := strings.TrimPrefix(filepath.Ext(capturedRow.FileName), ".")
-			if srcExt == "" {
+			srcName := capturedRow.FileName
+			if capturedRow.AudioPath != "" {
+				srcName = capturedRow.AudioPath
+			}
+			srcExt := strings.TrimPrefix(filepath.Ext(srcName), ".")
+			if capturedRow.isSegment() {
+				srcExt = opts.ClipFormat
+			} else if srcExt == "" {
 				srcExt = "mp3"
 			}
 			relPath := targetClipRelPath(capturedRow.ScientificName, capturedRow.Confidence, capturedTs, srcExt)
@@ -346,19 +473,31 @@ func (e *Engine) copyCandidateClips(
 				return
 			}
 
-			srcRel, found := resolveSourceClipRel(root, capturedRow.Date, capturedRow.CommonName, capturedRow.FileName)
+			srcRel, found := resolveRowAudio(root, &capturedRow)
 			if !found {
 				e.log.Warn("source clip not found, importing detection without audio",
 					logger.String("date", capturedRow.Date),
 					logger.String("common_name", capturedRow.CommonName),
-					logger.String("file_name", capturedRow.FileName))
+					logger.String("file_name", srcName))
 				mu.Lock()
 				*missCount++
 				mu.Unlock()
 				return
 			}
 
-			if copyErr := copyClipAtomic(root, srcRel, destAbs); copyErr != nil {
+			var copyErr error
+			switch {
+			case !capturedRow.isSegment():
+				copyErr = copyClipAtomic(root, srcRel, destAbs)
+			case opts.FFmpegPath == "":
+				copyErr = errors.Newf("FFmpeg is not configured; cannot cut clips from recordings").
+					Component("imports/audio").
+					Category(errors.CategoryConfiguration).
+					Build()
+			default:
+				copyErr = cutClipAtomic(ctx, opts, srcRel, &capturedRow, destAbs)
+			}
+			if copyErr != nil {
 				e.log.Warn("failed to copy audio clip, importing detection without audio",
 					logger.String("src", srcRel),
 					logger.String("dest", destAbs),
@@ -751,3 +751,40 @@ func TestCopyCandidateClips_ConcurrentPool(t *testing.T) {
 	assert.Equal(t, presentCount, withClip, "exactly %d detections must carry a ClipName", presentCount)
 	assert.Equal(t, missingCount, withoutClip, "exactly %d detections must have an empty ClipName", missingCount)
 }
+
+func TestImport_WithAudio_AudioPath(t *testing.T) {
+	audioSrc := t.TempDir()
+	exportDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(audioSrc, "2025", "05"), 0o750))
+	clipContent := []byte("exported clip")
+	require.NoError(t, os.WriteFile(filepath.Join(audioSrc, "2025", "05", "clip.wav"), clipContent, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(audioSrc, "recording.wav"), []byte("long recording"), 0o600))
+
+	ts := time.Date(2025, 5, 1, 6, 0, 0, 0, time.UTC)
+	src := &sliceSource{rows: []imports.SourceDetection{
+		// A whole clip is copied as is.
+		{Date: "2025-05-01", Time: "06:00:00", ScientificName: "Turdus merula", CommonName: "Common Blackbird", Confidence: 0.9, AudioPath: "2025/05/clip.wav"},
+		// A stretch of a longer recording needs FFmpeg, which is not configured.
+		{Date: "2025-05-01", Time: "06:05:00", ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.8, AudioPath: "recording.wav", ClipStart: 300, ClipEnd: 303},
+		// AudioPath must stay inside the audio source directory.
+		{Date: "2025-05-01", Time: "06:10:00", ScientificName: "Erithacus rubecula", CommonName: "European Robin", Confidence: 0.7, AudioPath: "../outside.wav"},
+	}}
+	repo := newDetectionRepo(t, newTestStore(t))
+	opts := imports.ImportOptions{
+		SourceNode:     "birdnet-go",
+		Location:       time.UTC,
+		IncludeAudio:   true,
+		AudioSourceDir: audioSrc,
+		ClipExportPath: exportDir,
+	}
+
+	stats, err := imports.NewEngine(repo).Run(t.Context(), src, &opts, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Inserted, "detections are imported without audio when their clip cannot be made")
+
+	relPath := imports.TargetClipRelPathForTest("Turdus merula", 0.9, ts, "wav")
+	data, err := os.ReadFile(filepath.Join(exportDir, filepath.FromSlash(relPath)))
+	require.NoError(t, err)
+	assert.Equal(t, clipContent, data)
+	assert.Len(t, walkFiles(t, exportDir), 1, "only the whole clip is exported")
+}
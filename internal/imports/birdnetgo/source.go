@@ -0,0 +1,251 @@
+// Package birdnetgo implements the imports.Source interface for detections
+// exported as JSON from another BirdNET-Go instance.
+package birdnetgo
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/imports"
+)
+
+// SourceNode identifies imports from another BirdNET-Go instance in the
+// detection's model version.
+const SourceNode = "birdnet-go"
+
+const (
+	component        = "imports/birdnetgo"
+	defaultBatchSize = 500
+)
+
+// detection is the subset of a v2 API detection that is imported.
+type detection struct {
+	Date           string  `json:"date"`
+	Time           string  `json:"time"`
+	Timestamp      string  `json:"timestamp"`
+	ScientificName string  `json:"scientificName"`
+	CommonName     string  `json:"commonName"`
+	Confidence     float64 `json:"confidence"`
+	ClipName       string  `json:"clipName"`
+}
+
+// Source reads detections from a JSON file holding either an array of
+// detections or a saved detections API response with the array under "data".
+type Source struct {
+	path string
+	opts imports.FileSourceOptions
+}
+
+// New returns a source over the BirdNET-Go export at path. Clips are paired
+// by clip name under opts.AudioDir, in the YYYY/MM layout BirdNET-Go saves
+// them in or directly in the directory.
+func New(path string, opts imports.FileSourceOptions) (*Source, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, errors.New(err).
+			Component(component).
+			Category(errors.CategoryFileIO).
+			Context("operation", "open").
+			Context("path", path).
+			Build()
+	}
+	return &Source{path: path, opts: opts.WithDefaults(path)}, nil
+}
+
+// Validate checks the file holds a detections array and that its first
+// detection has a time and a species.
+func (s *Source) Validate(ctx context.Context) error {
+	var first *detection
+	err := s.stream(ctx, func(d *detection) error {
+		first = d
+		return io.EOF // stop after the first detection
+	})
+	if err != nil && !errors.Is(err, io.EOF) {
+		return s.validationError(err)
+	}
+	if first == nil {
+		return nil
+	}
+	switch {
+	case first.Date == "" && first.Timestamp == "":
+		return s.validationError(errors.NewStd("detection has no date or timestamp"))
+	case first.ScientificName == "" && first.CommonName == "":
+		return s.validationError(errors.NewStd("detection has no species name"))
+	}
+	return nil
+}
+
+// Count returns the number of detections in the file.
+func (s *Source) Count(ctx context.Context) (int, error) {
+	n := 0
+	if err := s.stream(ctx, func(*detection) error { n++; return nil }); err != nil {
+		return 0, s.readError(err, "count")
+	}
+	return n, nil
+}
+
+// Iterate streams detections in file order, in batches.
+func (s *Source) Iterate(ctx context.Context, batchSize int, fn func([]imports.SourceDetection) error) error {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	batch := make([]imports.SourceDetection, 0, batchSize)
+	var fnErr error
+	err := s.stream(ctx, func(d *detection) error {
+		batch = append(batch, s.toSource(d))
+		if len(batch) < batchSize {
+			return nil
+		}
+		fnErr = fn(batch)
+		batch = make([]imports.SourceDetection, 0, batchSize)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return s.readError(err, "iterate")
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// Close releases nothing; the file is opened per pass.
+func (s *Source) Close() error {
+	return nil
+}
+
+// toSource maps an exported detection. Times with a zone offset are converted
+// to the import location; the exported date and time are wall-clock already.
+func (s *Source) toSource(d *detection) imports.SourceDetection {
+	det := imports.SourceDetection{
+		Date:           d.Date,
+		Time:           d.Time,
+		ScientificName: d.ScientificName,
+		CommonName:     d.CommonName,
+		Confidence:     d.Confidence,
+	}
+	if (det.Date == "" || det.Time == "") && d.Timestamp != "" {
+		if ts, err := time.Parse(time.RFC3339, d.Timestamp); err == nil {
+			det.Date, det.Time = imports.WallClock(ts.In(s.opts.Location))
+		}
+	}
+	det.AudioPath = s.clipPath(d.ClipName)
+	return det
+}
+
+// clipPath finds an exported clip under the audio directory and returns its
+// path relative to it, or "".
+func (s *Source) clipPath(clipName string) string {
+	if clipName == "" {
+		return ""
+	}
+	name := path.Base(strings.ReplaceAll(clipName, `\`, "/"))
+	candidates := []string{clipName}
+	if ts, ok := imports.RecordingStart(name); ok {
+		candidates = append(candidates, path.Join(ts.Format("2006"), ts.Format("01"), name))
+	}
+	candidates = append(candidates, name)
+	for _, c := range candidates {
+		if !filepath.IsLocal(filepath.FromSlash(c)) {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(s.opts.AudioDir, filepath.FromSlash(c))); err == nil && info.Mode().IsRegular() {
+			return c
+		}
+	}
+	return ""
+}
+
+// stream decodes the file's detections one at a time. An error from fn stops
+// the pass and is returned.
+func (s *Source) stream(ctx context.Context, fn func(*detection) error) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	dec := json.NewDecoder(f)
+	if err := seekDetections(dec); err != nil {
+		return err
+	}
+	for dec.More() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var d detection
+		if err := dec.Decode(&d); err != nil {
+			return err
+		}
+		if err := fn(&d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seekDetections advances dec into the detections array: the top-level array,
+// or the "data" array of a response object.
+func seekDetections(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('['):
+		return nil
+	case json.Delim('{'):
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if key == "data" {
+				tok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if tok != json.Delim('[') {
+					return errors.NewStd(`"data" is not an array`)
+				}
+				return nil
+			}
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+		return errors.NewStd(`no "data" array`)
+	}
+	return errors.NewStd("not a detections array")
+}
+
+func (s *Source) validationError(err error) error {
+	return errors.New(err).
+		Component(component).
+		Category(errors.CategoryValidation).
+		Context("operation", "validate").
+		Context("path", s.path).
+		Build()
+}
+
+func (s *Source) readError(err error, operation string) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return errors.New(err).
+		Component(component).
+		Category(errors.CategoryFileIO).
+		Context("operation", operation).
+		Context("path", s.path).
+		Build()
+}
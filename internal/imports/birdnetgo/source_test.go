@@ -0,0 +1,95 @@
+package birdnetgo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/imports"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func readAll(t *testing.T, src *Source, batchSize int) []imports.SourceDetection {
+	t.Helper()
+	var rows []imports.SourceDetection
+	require.NoError(t, src.Iterate(t.Context(), batchSize, func(batch []imports.SourceDetection) error {
+		rows = append(rows, batch...)
+		return nil
+	}))
+	return rows
+}
+
+func TestSource_APIResponse(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "2024", "05", "turdus_merula_91p_20240501T053000Z.wav"), "audio")
+	export := filepath.Join(dir, "detections.json")
+	writeFile(t, export, `{"total": 2, "limit": 100, "data": [
+		{"id": 1, "date": "2024-05-01", "time": "05:30:00", "scientificName": "Turdus merula",
+		 "commonName": "Eurasian Blackbird", "confidence": 0.91, "clipName": "turdus_merula_91p_20240501T053000Z.wav",
+		 "comments": [{"id": 1, "entry": "nice"}]},
+		{"id": 2, "timestamp": "2024-05-01T04:00:00Z", "scientificName": "Parus major",
+		 "commonName": "Great Tit", "confidence": 0.7}
+	], "offset": 0}`)
+
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	require.NoError(t, err)
+	src, err := New(export, imports.FileSourceOptions{Location: helsinki})
+	require.NoError(t, err)
+	require.NoError(t, src.Validate(t.Context()))
+
+	n, err := src.Count(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	rows := readAll(t, src, 1)
+	require.Len(t, rows, 2)
+	assert.Equal(t, imports.SourceDetection{
+		Date: "2024-05-01", Time: "05:30:00",
+		ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird", Confidence: 0.91,
+		AudioPath: "2024/05/turdus_merula_91p_20240501T053000Z.wav",
+	}, rows[0])
+	assert.Equal(t, "07:00:00", rows[1].Time, "timestamps are converted to the import location")
+	assert.Empty(t, rows[1].AudioPath)
+}
+
+func TestSource_TopLevelArray(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "parus_major_70p_20240501T060000Z.wav"), "audio")
+	export := filepath.Join(dir, "export.json")
+	writeFile(t, export, `[{"date": "2024-05-01", "time": "06:00:00", "scientificName": "Parus major",
+		"commonName": "Great Tit", "confidence": 0.7, "clipName": "parus_major_70p_20240501T060000Z.wav"}]`)
+
+	src, err := New(export, imports.FileSourceOptions{})
+	require.NoError(t, err)
+	require.NoError(t, src.Validate(t.Context()))
+	rows := readAll(t, src, 100)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "parus_major_70p_20240501T060000Z.wav", rows[0].AudioPath)
+}
+
+func TestSource_ValidateRejects(t *testing.T) {
+	t.Parallel()
+	for name, content := range map[string]string{
+		"not json":        "date,time\n",
+		"no data array":   `{"error": "unauthorized"}`,
+		"not detections":  `[{"name": "x"}]`,
+		"scalar document": `42`,
+	} {
+		export := filepath.Join(t.TempDir(), "export.json")
+		writeFile(t, export, content)
+		src, err := New(export, imports.FileSourceOptions{})
+		require.NoError(t, err)
+		assert.Error(t, src.Validate(t.Context()), name)
+	}
+}
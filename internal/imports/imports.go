@@ -18,6 +18,9 @@ const (
 	// DefaultSourceNode is the provenance tag written to SourceNode for imported rows.
 	DefaultSourceNode = "birdnet-pi"
 
+	// DefaultModelName is the model recorded on imported detections.
+	DefaultModelName = "BirdNET"
+
 	// defaultBatchSize is the number of source rows read per Iterate call.
 	defaultBatchSize = 500
 )
@@ -35,6 +38,14 @@ type SourceDetection struct {
 	Cutoff         float64
 	Sensitivity    float64
 	FileName       string
+
+	// AudioPath, when set, locates the detection's audio relative to
+	// AudioSourceDir instead of the BirdNET-Pi Extracted/By_Date layout.
+	// When ClipEnd is past ClipStart the detection covers ClipStart..ClipEnd
+	// seconds of a longer recording and only that stretch is imported.
+	AudioPath string
+	ClipStart float64
+	ClipEnd   float64
 }
 
 // Source is the interface a source adapter must implement.
@@ -63,6 +74,28 @@ type ImportStats struct {
 	Phase     string
 }
 
+// ImportPreview is the outcome of a dry run: what Run would import.
+// Inserted counts the detections that would be inserted.
+type ImportPreview struct {
+	ImportStats
+
+	// First and Last bound the timestamps of the detections to import.
+	First time.Time
+	Last  time.Time
+
+	// Sample holds the first detections that would be imported, in source order.
+	Sample []PreviewDetection
+}
+
+// PreviewDetection is a detection a dry run would import.
+type PreviewDetection struct {
+	Timestamp      time.Time
+	ScientificName string
+	CommonName     string
+	Confidence     float64
+	HasAudio       bool // the source points at audio for it; not checked on disk
+}
+
 // ProgressReporter receives periodic ImportStats updates from the engine.
 // A nil reporter is safe; the engine performs a nil check before calling.
 type ProgressReporter interface {
@@ -75,6 +108,16 @@ type ImportOptions struct {
 	// Defaults to DefaultSourceNode.
 	SourceNode string
 
+	// ModelName is the model recorded on every imported detection.
+	// Defaults to DefaultModelName.
+	ModelName string
+
+	// LookupScientificName resolves the scientific name of rows whose source
+	// records only a common name or a free-form species label. It returns ""
+	// for unknown names; such rows are counted as errors. Nil leaves those
+	// rows unresolved.
+	LookupScientificName func(commonName string) string
+
 	// Location is the timezone used when parsing Date + Time strings.
 	// Defaults to time.Local.
 	Location *time.Location
@@ -87,8 +130,9 @@ type ImportOptions struct {
 	// When true, audio clips are copied from AudioSourceDir into ClipExportPath alongside detection data.
 	IncludeAudio bool
 
-	// AudioSourceDir is the directory containing the BirdNET-Pi source audio tree.
-	// It must contain an "Extracted/By_Date" subtree. Used only when IncludeAudio is true.
+	// AudioSourceDir is the directory containing the source audio. For BirdNET-Pi it
+	// must contain an "Extracted/By_Date" subtree; rows with an AudioPath resolve
+	// it here. Used only when IncludeAudio is true.
 	AudioSourceDir string
 
 	// ClipExportPath is the root directory where audio clips are written.
@@ -99,12 +143,26 @@ type ImportOptions struct {
 	// If nil, diskmanager.GetAvailableSpace is used. Inject in tests to avoid
 	// filesystem dependencies.
 	DiskSpaceFunc func(path string) (uint64, error)
+
+	// FFmpegPath is used to cut detections out of longer recordings
+	// (SourceDetection.ClipEnd set). Without it those clips are not imported.
+	FFmpegPath string
+
+	// ClipFormat is the format of clips cut from longer recordings, one of
+	// wav, flac, mp3 or opus. Defaults to wav.
+	ClipFormat string
 }
 
 func (o *ImportOptions) withDefaults() {
 	if o.SourceNode == "" {
 		o.SourceNode = DefaultSourceNode
 	}
+	if o.ModelName == "" {
+		o.ModelName = DefaultModelName
+	}
+	if !clipFormats[o.ClipFormat] {
+		o.ClipFormat = defaultClipFormat
+	}
 	if o.Location == nil {
 		o.Location = time.Local
 	}
@@ -143,6 +201,22 @@ func detectionKey(ts time.Time, scientificName string, confidence float64) strin
 // opts may be nil; a nil pointer is replaced with a zero-value ImportOptions so
 // callers are not required to allocate one.
 func (e *Engine) Run(ctx context.Context, src Source, opts *ImportOptions, reporter ProgressReporter) (ImportStats, error) {
+	return e.run(ctx, src, opts, reporter, nil)
+}
+
+// Preview reads src the way Run would, without saving detections or copying
+// audio, and reports how many rows would be inserted, skipped as duplicates
+// or rejected, with up to sampleSize of the detections to import.
+func (e *Engine) Preview(ctx context.Context, src Source, opts *ImportOptions, sampleSize int) (ImportPreview, error) {
+	preview := ImportPreview{Sample: make([]PreviewDetection, 0, min(max(sampleSize, 0), previewSampleLimit))}
+	stats, err := e.run(ctx, src, opts, nil, &preview)
+	preview.ImportStats = stats
+	return preview, err
+}
+
+// run implements Run. With a non-nil preview it is a dry run: new rows are
+// recorded in preview instead of being saved, and no audio is copied.
+func (e *Engine) run(ctx context.Context, src Source, opts *ImportOptions, reporter ProgressReporter, preview *ImportPreview) (ImportStats, error) {
 	if opts == nil {
 		opts = &ImportOptions{}
 	}
@@ -155,7 +229,7 @@ func (e *Engine) Run(ctx context.Context, src Source, opts *ImportOptions, repor
 	// be configured. Failing fast here turns a misconfiguration into a clear error instead
 	// of silently importing detections without their audio (or reading a path relative to
 	// the working directory).
-	if opts.IncludeAudio && (opts.AudioSourceDir == "" || opts.ClipExportPath == "") {
+	if preview == nil && opts.IncludeAudio && (opts.AudioSourceDir == "" || opts.ClipExportPath == "") {
 		return stats, errors.Newf("audio import requires both an audio source directory and a clip export path").
 			Component("imports").
 			Category(errors.CategoryValidation).
@@ -216,6 +290,16 @@ func (e *Engine) Run(ctx context.Context, src Source, opts *ImportOptions, repor
 				return err
 			}
 			row := &batch[i]
+			if row.ScientificName == "" && opts.LookupScientificName != nil {
+				row.ScientificName = opts.LookupScientificName(row.CommonName)
+			}
+			if row.ScientificName == "" {
+				e.log.Debug("skipping row without a scientific name",
+					logger.String("common_name", row.CommonName))
+				stats.Errors++
+				stats.Processed++
+				continue
+			}
 			ts, parseErr := parseTimestamp(row.Date, row.Time, opts.Location)
 			if parseErr != nil {
 				e.log.Debug("skipping row with unparseable timestamp",
@@ -237,6 +321,16 @@ func (e *Engine) Run(ctx context.Context, src Source, opts *ImportOptions, repor
 			pending = append(pending, pendingRow{row: row, ts: ts})
 		}
 
+		if preview != nil {
+			for _, p := range pending {
+				preview.add(p.row, p.ts)
+			}
+			stats.Inserted += len(pending)
+			stats.Processed += len(pending)
+			e.report(reporter, stats)
+			return nil
+		}
+
 		// Second pass: copy audio clips when requested.
 		clipNames := make([]string, len(pending))
 		if opts.IncludeAudio && opts.ClipExportPath != "" && len(pending) > 0 {
@@ -256,7 +350,7 @@ func (e *Engine) Run(ctx context.Context, src Source, opts *ImportOptions, repor
 			if err := ctx.Err(); err != nil {
 				return err
 			}
-			result := mapToResult(p.row, p.ts, opts.SourceNode)
+			result := mapToResult(p.row, p.ts, opts)
 			result.ClipName = clipNames[i]
 			if saveErr := e.repo.Save(ctx, result, nil); saveErr != nil {
 				if ctx.Err() != nil {
@@ -302,6 +396,10 @@ func (e *Engine) Run(ctx context.Context, src Source, opts *ImportOptions, repor
 	stats.Phase = "done"
 	e.report(reporter, stats)
 
+	if preview != nil {
+		return stats, nil
+	}
+
 	e.log.Info("import complete",
 		logger.Int("total", stats.Total),
 		logger.Int("inserted", stats.Inserted),
@@ -403,16 +501,17 @@ func parseTimestamp(date, timeStr string, loc *time.Location) (time.Time, error)
 //   - ClipName: DB-only imports leave ClipName empty; DB+audio imports set it before
 //     Save when a matching source clip is found. An empty ClipName avoids broken
 //     "audio not available" links.
-//   - Model is set to a synthetic marker so imported rows are distinguishable from
-//     live detections in queries or analytics.
+//   - Model is set to a synthetic marker (the source's model name, the source node
+//     as version, variant "import") so imported rows are distinguishable from live
+//     detections in queries or analytics.
 //   - Provenance is carried solely by SourceNode (a persisted column). AudioSource is
 //     gorm:"-" runtime-only and is not persisted on the legacy save path, so it is left
 //     zero rather than used as a provenance marker.
 //   - Week and Overlap from BirdNET-Pi are dropped; birdnet-go does not use them.
-func mapToResult(row *SourceDetection, ts time.Time, sourceNode string) *detection.Result {
+func mapToResult(row *SourceDetection, ts time.Time, opts *ImportOptions) *detection.Result {
 	return &detection.Result{
 		Timestamp:  ts,
-		SourceNode: sourceNode,
+		SourceNode: opts.SourceNode,
 		Species: detection.Species{
 			ScientificName: row.ScientificName,
 			CommonName:     row.CommonName,
@@ -424,14 +523,36 @@ func mapToResult(row *SourceDetection, ts time.Time, sourceNode string) *detecti
 		Threshold:   row.Cutoff,
 		Sensitivity: row.Sensitivity,
 		Model: detection.ModelInfo{
-			Name:    "BirdNET",
-			Version: "birdnet-pi",
+			Name:    opts.ModelName,
+			Version: opts.SourceNode,
 			Variant: "import",
 		},
 		ClipName: "",
 	}
 }
 
+// previewSampleLimit caps the preview sample however large a size is asked for.
+const previewSampleLimit = 100
+
+// add records a detection a dry run would import.
+func (p *ImportPreview) add(row *SourceDetection, ts time.Time) {
+	if p.First.IsZero() || ts.Before(p.First) {
+		p.First = ts
+	}
+	if ts.After(p.Last) {
+		p.Last = ts
+	}
+	if len(p.Sample) < cap(p.Sample) {
+		p.Sample = append(p.Sample, PreviewDetection{
+			Timestamp:      ts,
+			ScientificName: row.ScientificName,
+			CommonName:     row.CommonName,
+			Confidence:     row.Confidence,
+			HasAudio:       row.AudioPath != "" || row.FileName != "",
+		})
+	}
+}
+
 // report calls reporter.Report if reporter is not nil.
 func (e *Engine) report(reporter ProgressReporter, stats ImportStats) {
 	if reporter != nil {
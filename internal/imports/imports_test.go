@@ -492,3 +492,72 @@ func TestImport_CancelDuringSave(t *testing.T) {
 	assert.GreaterOrEqual(t, stats.Inserted, 1, "at least one row must have been inserted before cancel")
 	assert.Equal(t, 0, stats.Errors, "cancelled row must not be counted as a save error")
 }
+
+// sliceSource is an in-memory Source for engine tests.
+type sliceSource struct {
+	rows []imports.SourceDetection
+}
+
+func (s *sliceSource) Validate(context.Context) error { return nil }
+
+func (s *sliceSource) Count(context.Context) (int, error) { return len(s.rows), nil }
+
+func (s *sliceSource) Iterate(_ context.Context, _ int, fn func([]imports.SourceDetection) error) error {
+	return fn(s.rows)
+}
+
+func (s *sliceSource) Close() error { return nil }
+
+func TestImport_LookupScientificName(t *testing.T) {
+	src := &sliceSource{rows: []imports.SourceDetection{
+		{Date: "2025-05-01", Time: "06:00:00", CommonName: "Common Blackbird", Confidence: 0.9},
+		{Date: "2025-05-01", Time: "06:01:00", CommonName: "Unknown Bird", Confidence: 0.9},
+	}}
+	repo := newDetectionRepo(t, newTestStore(t))
+	opts := imports.ImportOptions{
+		SourceNode: "merlin",
+		ModelName:  "Merlin",
+		Location:   time.UTC,
+		LookupScientificName: func(name string) string {
+			if name == "Common Blackbird" {
+				return "Turdus merula"
+			}
+			return ""
+		},
+	}
+
+	stats, err := imports.NewEngine(repo).Run(t.Context(), src, &opts, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Inserted)
+	assert.Equal(t, 1, stats.Errors, "unresolved common name must be counted as error")
+
+	results, _, err := repo.Search(t.Context(), &datastore.DetectionFilters{Location: []string{"merlin"}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Turdus merula", results[0].Species.ScientificName)
+}
+
+func TestPreview_DoesNotSave(t *testing.T) {
+	src := &sliceSource{rows: []imports.SourceDetection{
+		{Date: "2025-05-01", Time: "06:00:00", ScientificName: "Turdus merula", CommonName: "Common Blackbird", Confidence: 0.9, AudioPath: "a.wav"},
+		{Date: "2025-05-03", Time: "07:30:00", ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.8},
+		{Date: "bad", Time: "07:30:00", ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.8},
+	}}
+	repo := newDetectionRepo(t, newTestStore(t))
+	opts := imports.ImportOptions{SourceNode: "birdnet-analyzer", Location: time.UTC, IncludeAudio: true}
+
+	preview, err := imports.NewEngine(repo).Preview(t.Context(), src, &opts, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, preview.Total)
+	assert.Equal(t, 2, preview.Inserted)
+	assert.Equal(t, 1, preview.Errors)
+	assert.Equal(t, time.Date(2025, 5, 1, 6, 0, 0, 0, time.UTC), preview.First)
+	assert.Equal(t, time.Date(2025, 5, 3, 7, 30, 0, 0, time.UTC), preview.Last)
+	require.Len(t, preview.Sample, 1, "sample is capped at the requested size")
+	assert.Equal(t, "Turdus merula", preview.Sample[0].ScientificName)
+	assert.True(t, preview.Sample[0].HasAudio)
+
+	results, _, err := repo.Search(t.Context(), &datastore.DetectionFilters{Location: []string{"birdnet-analyzer"}, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, results, "preview must not save detections")
+}
@@ -0,0 +1,161 @@
+// Package raven implements the imports.Source interface for Raven selection
+// tables, as saved by Raven Pro and by BirdNET-Analyzer's table output.
+package raven
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/imports"
+)
+
+// SourceNode identifies Raven imports in the detection's model version.
+const SourceNode = "raven"
+
+const component = "imports/raven"
+
+// resultSuffixes are the selection table names of BirdNET-Analyzer and of
+// Raven Pro's default "Save Selection Table" name.
+var resultSuffixes = []string{
+	".BirdNET.selection.table.txt",
+	".Table.1.selections.txt",
+	".selections.txt",
+	".txt",
+}
+
+var (
+	colView       = []string{"View"}
+	colBegin      = []string{"Begin Time (s)"}
+	colEnd        = []string{"End Time (s)"}
+	colFileOffset = []string{"File Offset (s)"}
+	colBeginPath  = []string{"Begin Path", "Begin File"}
+	colBeginDate  = []string{"Begin Date"}
+	colBeginClock = []string{"Begin Clock Time"}
+	colScientific = []string{"Scientific Name", "Species Scientific Name"}
+	colCommon     = []string{"Common Name"}
+	colSpecies    = []string{"Species", "Annotation", "Label"}
+	colConfidence = []string{"Confidence", "Score"}
+)
+
+var (
+	dateLayouts  = []string{"2006/01/02", "2006-01-02", "01/02/2006"}
+	clockLayouts = []string{"15:04:05.000", "15:04:05"}
+)
+
+// New returns a source over a Raven selection table or a directory of them.
+// Each spectrogram selection becomes a detection. Its time comes from the
+// Begin Date and Begin Clock Time columns when present, otherwise from the
+// recording start in the audio or table file name plus the selection offset.
+// Selections without a confidence column are manual annotations and import
+// with confidence 1.
+func New(path string, opts imports.FileSourceOptions) (*imports.TableSource, error) {
+	opts = opts.WithDefaults(path)
+	p := &parser{audioDir: opts.AudioDir, paired: make(map[string]string)}
+	return imports.NewTableSource(path, &imports.TableSpec{
+		Component: component,
+		Comma:     '\t',
+		Match: func(name string) bool {
+			ext := strings.ToLower(filepath.Ext(name))
+			return ext == ".txt" || ext == ".tsv"
+		},
+		Accept: accept,
+		Parse:  p.parse,
+	})
+}
+
+func accept(h imports.Header) error {
+	switch {
+	case !h.Has(colBegin...):
+		return errors.NewStd("missing Begin Time (s) column")
+	case !h.Has(colScientific...) && !h.Has(colCommon...) && !h.Has(colSpecies...):
+		return errors.NewStd("missing species column")
+	}
+	return nil
+}
+
+// parser maps selections to detections. Files are read one at a time, so it
+// needs no locking.
+type parser struct {
+	audioDir string
+	paired   map[string]string // recording -> AudioPath, "" when not found
+}
+
+func (p *parser) parse(row *imports.TableRow) (imports.SourceDetection, bool) {
+	// Raven lists every selection once per view; the waveform rows repeat
+	// the spectrogram ones.
+	if view := row.Get(colView...); view != "" && !strings.HasPrefix(strings.ToLower(view), "spectrogram") {
+		return imports.SourceDetection{}, false
+	}
+	begin, ok := row.Float(colBegin...)
+	if !ok {
+		return imports.SourceDetection{}, false
+	}
+	end, _ := row.Float(colEnd...)
+	offset := begin
+	if o, hasOffset := row.Float(colFileOffset...); hasOffset {
+		offset = o
+	}
+
+	det := imports.SourceDetection{
+		ScientificName: row.Get(colScientific...),
+		CommonName:     row.Get(colCommon...),
+		Confidence:     1,
+		ClipStart:      offset,
+		ClipEnd:        offset + max(end-begin, 0),
+	}
+	if det.ScientificName == "" && det.CommonName == "" {
+		// A free-form label, resolved by the import's name lookup, which
+		// also recognizes scientific names.
+		det.CommonName = row.Get(colSpecies...)
+	}
+	if c, hasConfidence := row.Float(colConfidence...); hasConfidence {
+		if c > 1 {
+			c /= 100 // percent
+		}
+		det.Confidence = c
+	}
+
+	recorded := row.Get(colBeginPath...)
+	if ts, found := clockTime(row); found {
+		det.Date, det.Time = imports.WallClock(ts)
+	} else {
+		name := recorded
+		if name == "" {
+			name = row.File
+		}
+		if recStart, found := imports.RecordingStart(name); found {
+			det.Date, det.Time = imports.WallClock(recStart.Add(time.Duration(offset * float64(time.Second))))
+		}
+	}
+	det.AudioPath = p.audioPath(row.File, recorded)
+	return det, true
+}
+
+// clockTime parses the Begin Date and Begin Clock Time columns.
+func clockTime(row *imports.TableRow) (time.Time, bool) {
+	date, clock := row.Get(colBeginDate...), row.Get(colBeginClock...)
+	if date == "" || clock == "" {
+		return time.Time{}, false
+	}
+	for _, dl := range dateLayouts {
+		for _, cl := range clockLayouts {
+			if ts, err := time.Parse(dl+" "+cl, date+" "+clock); err == nil {
+				return ts, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// audioPath pairs a selection with its recording, caching the lookup per recording.
+func (p *parser) audioPath(resultFile, recorded string) string {
+	key := resultFile + "\x00" + recorded
+	if path, ok := p.paired[key]; ok {
+		return path
+	}
+	path := imports.PairedAudio(p.audioDir, resultFile, recorded, resultSuffixes...)
+	p.paired[key] = path
+	return path
+}
@@ -0,0 +1,79 @@
+package raven
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/imports"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func readAll(t *testing.T, path string) []imports.SourceDetection {
+	t.Helper()
+	src, err := New(path, imports.FileSourceOptions{})
+	require.NoError(t, err)
+	require.NoError(t, src.Validate(t.Context()))
+	var rows []imports.SourceDetection
+	require.NoError(t, src.Iterate(t.Context(), 100, func(batch []imports.SourceDetection) error {
+		rows = append(rows, batch...)
+		return nil
+	}))
+	return rows
+}
+
+func TestSource_BirdNETSelectionTable(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "20240501_053000.flac"), "audio")
+	writeFile(t, filepath.Join(dir, "20240501_053000.BirdNET.selection.table.txt"),
+		"Selection\tView\tChannel\tBegin Time (s)\tEnd Time (s)\tLow Freq (Hz)\tHigh Freq (Hz)\tCommon Name\tSpecies Code\tConfidence\n"+
+			"1\tSpectrogram 1\t1\t12.0\t15.0\t0\t15000\tEurasian Blackbird\teurbla\t0.8123\n"+
+			"1\tWaveform 1\t1\t12.0\t15.0\t0\t15000\tEurasian Blackbird\teurbla\t0.8123\n")
+
+	rows := readAll(t, dir)
+	require.Len(t, rows, 1, "waveform view rows repeat the spectrogram rows")
+	assert.Equal(t, imports.SourceDetection{
+		Date: "2024-05-01", Time: "05:30:12",
+		CommonName: "Eurasian Blackbird", Confidence: 0.8123,
+		AudioPath: "20240501_053000.flac", ClipStart: 12, ClipEnd: 15,
+	}, rows[0])
+}
+
+func TestSource_ManualAnnotations(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	table := filepath.Join(dir, "night.Table.1.selections.txt")
+	writeFile(t, table,
+		"Selection\tView\tBegin Time (s)\tEnd Time (s)\tBegin Path\tFile Offset (s)\tBegin Date\tBegin Clock Time\tSpecies\n"+
+			"1\tSpectrogram 1\t3605.5\t3607.0\t/mnt/sd/night.wav\t5.5\t2024/05/02\t22:00:05.500\tStrix aluco\n"+
+			"2\tSpectrogram 1\t3700.0\t3701.0\t/mnt/sd/night.wav\t100.0\t\t\tTawny Owl\n")
+
+	rows := readAll(t, table)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "2024-05-02", rows[0].Date)
+	assert.Equal(t, "22:00:05", rows[0].Time)
+	assert.Equal(t, "Strix aluco", rows[0].CommonName, "free-form labels are left to the name lookup")
+	assert.InDelta(t, 1.0, rows[0].Confidence, 0.001, "manual annotations are certain")
+	assert.InDelta(t, 5.5, rows[0].ClipStart, 0.001, "clip offsets are within the recording")
+	assert.InDelta(t, 7.0, rows[0].ClipEnd, 0.001)
+	assert.Empty(t, rows[1].Date, "no clock time and no start time in the file name")
+}
+
+func TestSource_RejectsOtherText(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "notes.txt")
+	writeFile(t, file, "just some notes\n")
+
+	src, err := New(file, imports.FileSourceOptions{})
+	require.NoError(t, err)
+	require.Error(t, src.Validate(t.Context()))
+}
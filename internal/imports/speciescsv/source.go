@@ -0,0 +1,149 @@
+// Package speciescsv implements the imports.Source interface for per-detection
+// CSV exports of stations that report a species and a time, such as Haikubox
+// and Merlin.
+package speciescsv
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/imports"
+)
+
+// Profile describes one station's export.
+type Profile struct {
+	// SourceNode identifies the station in the detection's model version.
+	SourceNode string
+
+	// ModelName is the model recorded on imported detections.
+	ModelName string
+}
+
+// Profiles of the supported exports.
+var (
+	Haikubox = Profile{SourceNode: "haikubox", ModelName: imports.DefaultModelName}
+	Merlin   = Profile{SourceNode: "merlin", ModelName: "Merlin"}
+)
+
+const component = "imports/speciescsv"
+
+var (
+	colCommon     = []string{"Common Name", "common_name", "CommonName", "Species", "Bird"}
+	colScientific = []string{"Scientific Name", "scientific_name", "ScientificName", "Species Scientific Name"}
+	colTimestamp  = []string{"Timestamp", "DateTime", "Date Time", "Date/Time", "Detection Time", "Detected At", "dt"}
+	colDate       = []string{"Date"}
+	colTime       = []string{"Time"}
+	colConfidence = []string{"Confidence", "Score", "Probability", "Certainty"}
+	colLatitude   = []string{"Latitude", "lat"}
+	colLongitude  = []string{"Longitude", "lon", "lng"}
+)
+
+// zonedLayouts carry a zone offset and are converted to the import location.
+var zonedLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 MST",
+}
+
+// localLayouts are wall-clock times as the station recorded them.
+var localLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"1/2/2006 3:04:05 PM",
+	"1/2/2006 3:04 PM",
+}
+
+// New returns a source over a station's CSV export, or a directory of them.
+// Rows need a species name and a timestamp, either in one column or as separate
+// date and time columns. Confidence is optional and may be a percentage;
+// exports without one import with confidence 1.
+func New(path string, profile Profile, opts imports.FileSourceOptions) (*imports.TableSource, error) {
+	opts = opts.WithDefaults(path)
+	loc := opts.Location
+	return imports.NewTableSource(path, &imports.TableSpec{
+		Component: component + "/" + profile.SourceNode,
+		Comma:     ',',
+		Match: func(name string) bool {
+			return strings.EqualFold(filepath.Ext(name), ".csv")
+		},
+		Accept: accept,
+		Parse: func(row *imports.TableRow) (imports.SourceDetection, bool) {
+			return parse(row, loc)
+		},
+	})
+}
+
+func accept(h imports.Header) error {
+	switch {
+	case !h.Has(colCommon...) && !h.Has(colScientific...):
+		return errors.NewStd("missing species name column")
+	case !h.Has(colTimestamp...) && !h.Has(colDate...):
+		return errors.NewStd("missing timestamp column")
+	}
+	return nil
+}
+
+func parse(row *imports.TableRow, loc *time.Location) (imports.SourceDetection, bool) {
+	det := imports.SourceDetection{
+		ScientificName: row.Get(colScientific...),
+		CommonName:     row.Get(colCommon...),
+		Confidence:     1,
+	}
+	if det.ScientificName == "" && det.CommonName == "" {
+		return imports.SourceDetection{}, false
+	}
+	if c, ok := row.Float(colConfidence...); ok {
+		if c > 1 {
+			c /= 100 // percent
+		}
+		det.Confidence = c
+	}
+	det.Latitude, _ = row.Float(colLatitude...)
+	det.Longitude, _ = row.Float(colLongitude...)
+
+	value := row.Get(colTimestamp...)
+	if value == "" {
+		value = strings.TrimSpace(row.Get(colDate...) + " " + row.Get(colTime...))
+	}
+	// Rows with an unparsable time keep an empty Date and are counted as
+	// errors by the engine.
+	if ts, ok := parseTimestamp(value, loc); ok {
+		det.Date, det.Time = imports.WallClock(ts)
+	}
+	return det, true
+}
+
+// parseTimestamp parses a station timestamp to wall-clock time in loc.
+func parseTimestamp(value string, loc *time.Location) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	// Unix seconds or milliseconds.
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		switch len(value) {
+		case 10:
+			return time.Unix(n, 0).In(loc), true
+		case 13:
+			return time.UnixMilli(n).In(loc), true
+		}
+	}
+	for _, layout := range zonedLayouts {
+		if ts, err := time.Parse(layout, value); err == nil {
+			return ts.In(loc), true
+		}
+	}
+	for _, layout := range localLayouts {
+		if ts, err := time.Parse(layout, value); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,76 @@
+package speciescsv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/imports"
+)
+
+func readAll(t *testing.T, content string, loc *time.Location) []imports.SourceDetection {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "export.csv")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	src, err := New(path, Haikubox, imports.FileSourceOptions{Location: loc})
+	require.NoError(t, err)
+	require.NoError(t, src.Validate(t.Context()))
+	var rows []imports.SourceDetection
+	require.NoError(t, src.Iterate(t.Context(), 100, func(batch []imports.SourceDetection) error {
+		rows = append(rows, batch...)
+		return nil
+	}))
+	return rows
+}
+
+func TestSource_ZonedTimestamps(t *testing.T) {
+	t.Parallel()
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	require.NoError(t, err)
+
+	rows := readAll(t,
+		"Common Name,Scientific Name,Timestamp,Score\n"+
+			"Great Tit,Parus major,2024-05-01T03:00:00.000Z,87\n"+
+			"Eurasian Wren,Troglodytes troglodytes,1714532400,0.6\n", helsinki)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, imports.SourceDetection{
+		Date: "2024-05-01", Time: "06:00:00",
+		ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.87,
+	}, rows[0], "UTC timestamps are converted to the import location; percent scores are scaled")
+	assert.Equal(t, "06:00:00", rows[1].Time, "unix seconds")
+	assert.InDelta(t, 0.6, rows[1].Confidence, 0.001)
+}
+
+func TestSource_DateAndTimeColumns(t *testing.T) {
+	t.Parallel()
+	rows := readAll(t,
+		"Species,Date,Time,Latitude,Longitude\n"+
+			"American Robin,05/01/2024,6:15 AM,42.45,-76.48\n"+
+			"Northern Cardinal,2024-05-01,06:20:30,42.45,-76.48\n"+
+			"Blue Jay,yesterday,,42.45,-76.48\n", time.UTC)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, "2024-05-01", rows[0].Date)
+	assert.Equal(t, "06:15:00", rows[0].Time)
+	assert.Empty(t, rows[0].ScientificName, "resolved by the name lookup")
+	assert.InDelta(t, 1.0, rows[0].Confidence, 0.001, "no confidence column")
+	assert.InDelta(t, 42.45, rows[0].Latitude, 0.001)
+	assert.Equal(t, "06:20:30", rows[1].Time)
+	assert.Empty(t, rows[2].Date, "unparsable times are left for the engine to count")
+}
+
+func TestSource_RejectsWithoutTimestamp(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "life_list.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Common Name,Count\nGreat Tit,3\n"), 0o600))
+
+	src, err := New(path, Merlin, imports.FileSourceOptions{})
+	require.NoError(t, err)
+	require.Error(t, src.Validate(t.Context()))
+}
@@ -0,0 +1,458 @@
+// Package imports table.go: a Source over delimited result files (CSV and
+// tab-separated selection tables) shared by the file-based adapters.
+package imports
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// utf8BOM is stripped from the first header cell; spreadsheet exports often start with it.
+const utf8BOM = "\uFEFF"
+
+// audioExtensions are the recording formats a result file can be paired with,
+// tried in order when looking for the recording next to a result file.
+var audioExtensions = []string{".wav", ".flac", ".mp3", ".ogg", ".opus", ".m4a", ".aac"}
+
+// recordingStartPatterns match the recording start time embedded in recorder
+// file names: AudioMoth and Song Meter (20240501_053000), BirdNET-Go clips
+// (20240501T053000Z) and ISO-like names (2024-05-01_05-30-00).
+var recordingStartPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(\d{4})(\d{2})(\d{2})[_T-]?(\d{2})(\d{2})(\d{2})`),
+	regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})[ _T](\d{2})[-_:.](\d{2})[-_:.](\d{2})`),
+}
+
+// Header maps the normalized column names of a result file to their index.
+type Header map[string]int
+
+// Col returns the index of the first of names present in the header, or -1.
+// Names are matched case-insensitively.
+func (h Header) Col(names ...string) int {
+	for _, n := range names {
+		if i, ok := h[normalizeColumn(n)]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// Has reports whether any of names is a column of the header.
+func (h Header) Has(names ...string) bool {
+	return h.Col(names...) >= 0
+}
+
+// TableRow is one data row of a result file.
+type TableRow struct {
+	Header Header
+	Record []string
+	File   string // path of the result file
+	Line   int    // 1-based line number in the file
+}
+
+// Get returns the trimmed value of the first of names present in the row, or "".
+func (r *TableRow) Get(names ...string) string {
+	i := r.Header.Col(names...)
+	if i < 0 || i >= len(r.Record) {
+		return ""
+	}
+	return strings.TrimSpace(r.Record[i])
+}
+
+// Float returns the value of the first of names present in the row as a
+// number. A decimal comma is accepted.
+func (r *TableRow) Float(names ...string) (float64, bool) {
+	v := r.Get(names...)
+	if v == "" {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.Replace(v, ",", ".", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// TableSpec describes one kind of result file for NewTableSource.
+type TableSpec struct {
+	// Component names the adapter in errors, e.g. "imports/raven".
+	Component string
+
+	// Comma is the field delimiter.
+	Comma rune
+
+	// Match reports whether a file found in a source directory is a result
+	// file of this kind. A source given as a single file is always read.
+	Match func(name string) bool
+
+	// Accept validates a result file's header. Files in a source directory that
+	// fail it are skipped; a source given as a single file must pass.
+	Accept func(Header) error
+
+	// Parse maps a data row to a detection. Rows it returns false for (blank or
+	// non-detection rows) are left out of the count and the import.
+	Parse func(row *TableRow) (SourceDetection, bool)
+}
+
+// TableSource is a Source reading detections from one result file or from every
+// matching result file under a directory.
+type TableSource struct {
+	path  string
+	spec  TableSpec
+	files []string // accepted result files, set by Validate
+}
+
+// NewTableSource returns a source over the result file or directory at path.
+// The files are listed and their headers checked by Validate.
+func NewTableSource(path string, spec *TableSpec) (*TableSource, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, errors.New(err).
+			Component(spec.Component).
+			Category(errors.CategoryFileIO).
+			Context("operation", "open").
+			Context("path", path).
+			Build()
+	}
+	return &TableSource{path: path, spec: *spec}, nil
+}
+
+// Validate lists the result files and checks their headers.
+func (s *TableSource) Validate(ctx context.Context) error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return s.fileError(err, "stat", s.path)
+	}
+
+	if !info.IsDir() {
+		header, err := readTableHeader(s.path, s.spec.Comma)
+		if err == nil {
+			err = s.spec.Accept(header)
+		}
+		if err != nil {
+			return errors.New(err).
+				Component(s.spec.Component).
+				Category(errors.CategoryValidation).
+				Context("operation", "validate").
+				Context("path", s.path).
+				Build()
+		}
+		s.files = []string{s.path}
+		return nil
+	}
+
+	var files []string
+	walkErr := filepath.WalkDir(s.path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d.IsDir() || !d.Type().IsRegular() || !s.spec.Match(d.Name()) {
+			return nil
+		}
+		if header, err := readTableHeader(p, s.spec.Comma); err == nil && s.spec.Accept(header) == nil {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return s.fileError(walkErr, "list_results", s.path)
+	}
+	if len(files) == 0 {
+		return errors.Newf("no result files found").
+			Component(s.spec.Component).
+			Category(errors.CategoryValidation).
+			Context("operation", "validate").
+			Context("path", s.path).
+			Build()
+	}
+	s.files = files
+	return nil
+}
+
+// Count returns the number of detection rows in the result files.
+func (s *TableSource) Count(ctx context.Context) (int, error) {
+	if err := s.ensureFiles(ctx); err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, f := range s.files {
+		err := readTable(ctx, f, s.spec.Comma, func(row *TableRow) error {
+			if _, ok := s.spec.Parse(row); ok {
+				n++
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, s.fileError(err, "count", f)
+		}
+	}
+	return n, nil
+}
+
+// Iterate streams detections file by file, in file-name order, in batches.
+func (s *TableSource) Iterate(ctx context.Context, batchSize int, fn func([]SourceDetection) error) error {
+	if err := s.ensureFiles(ctx); err != nil {
+		return err
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	batch := make([]SourceDetection, 0, batchSize)
+	var fnErr error
+	for _, f := range s.files {
+		err := readTable(ctx, f, s.spec.Comma, func(row *TableRow) error {
+			det, ok := s.spec.Parse(row)
+			if !ok {
+				return nil
+			}
+			batch = append(batch, det)
+			if len(batch) < batchSize {
+				return nil
+			}
+			fnErr = fn(batch)
+			batch = make([]SourceDetection, 0, batchSize)
+			return fnErr
+		})
+		if fnErr != nil {
+			return fnErr
+		}
+		if err != nil {
+			return s.fileError(err, "iterate", f)
+		}
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// Close releases nothing; files are opened per pass.
+func (s *TableSource) Close() error {
+	return nil
+}
+
+// ensureFiles runs Validate when it has not run yet.
+func (s *TableSource) ensureFiles(ctx context.Context) error {
+	if s.files != nil {
+		return nil
+	}
+	return s.Validate(ctx)
+}
+
+func (s *TableSource) fileError(err error, operation, path string) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return errors.New(err).
+		Component(s.spec.Component).
+		Category(errors.CategoryFileIO).
+		Context("operation", operation).
+		Context("path", path).
+		Build()
+}
+
+// normalizeColumn folds a column name for Header lookups.
+func normalizeColumn(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, utf8BOM)))
+}
+
+// newTableReader returns a lenient reader: rows may differ in length and bare
+// quotes inside fields are kept.
+func newTableReader(r io.Reader, comma rune) *csv.Reader {
+	cr := csv.NewReader(bufio.NewReader(r))
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	cr.ReuseRecord = true
+	return cr
+}
+
+func parseHeader(record []string) Header {
+	h := make(Header, len(record))
+	for i, name := range record {
+		key := normalizeColumn(name)
+		if _, dup := h[key]; !dup && key != "" {
+			h[key] = i
+		}
+	}
+	return h
+}
+
+// readTableHeader reads the header row of a result file.
+func readTableHeader(path string, comma rune) (Header, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path comes from the validated import source
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	record, err := newTableReader(f, comma).Read()
+	if err != nil {
+		return nil, err
+	}
+	return parseHeader(record), nil
+}
+
+// readTable calls fn for every non-blank data row of a result file.
+func readTable(ctx context.Context, path string, comma rune, fn func(*TableRow) error) error {
+	f, err := os.Open(path) //nolint:gosec // G304: path comes from the validated import source
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	cr := newTableReader(f, comma)
+	record, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	row := TableRow{Header: parseHeader(record), File: path}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		row.Line, _ = cr.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+		row.Record = record
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ResultRoot returns the directory holding the results at path: path itself
+// when it is a directory, otherwise its parent.
+func ResultRoot(path string) string {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return path
+	}
+	return filepath.Dir(path)
+}
+
+// RecordingStart parses the recording start time embedded in a recorder file
+// name. The result is wall-clock time, returned in UTC.
+func RecordingStart(name string) (time.Time, bool) {
+	base := filepath.Base(filepath.FromSlash(strings.ReplaceAll(name, `\`, "/")))
+	for _, re := range recordingStartPatterns {
+		m := re.FindStringSubmatch(base)
+		if m == nil {
+			continue
+		}
+		ts, err := time.Parse("20060102150405", m[1]+m[2]+m[3]+m[4]+m[5]+m[6])
+		if err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// WallClock formats a wall-clock time as the Date and Time of a SourceDetection.
+func WallClock(ts time.Time) (date, clock string) {
+	return ts.Format(time.DateOnly), ts.Format(time.TimeOnly)
+}
+
+// FileSourceOptions configures the file-based source adapters.
+type FileSourceOptions struct {
+	// AudioDir is where paired recordings are looked for; the AudioPath of the
+	// detections is relative to it. Defaults to ResultRoot of the source path.
+	AudioDir string
+
+	// Location converts source timestamps that carry a zone offset to the
+	// wall-clock Date and Time the engine expects. Defaults to time.Local.
+	Location *time.Location
+}
+
+// WithDefaults returns the options with defaults filled in for the source at path.
+func (o FileSourceOptions) WithDefaults(path string) FileSourceOptions {
+	if o.AudioDir == "" {
+		o.AudioDir = ResultRoot(path)
+	}
+	if o.Location == nil {
+		o.Location = time.Local
+	}
+	return o
+}
+
+// PairedAudio locates the recording a result row refers to and returns its path
+// relative to audioDir, or "" when it is not found inside audioDir. recorded is
+// the recording path written in the result, possibly from another machine; it is
+// tried as written, then by its trailing path components in audioDir and by name
+// next to resultFile. When recorded
+// is empty the recording is looked for under resultFile's name with one of
+// resultSuffixes replaced by an audio extension.
+func PairedAudio(audioDir, resultFile, recorded string, resultSuffixes ...string) string {
+	var candidates []string
+	if recorded != "" {
+		// Paths written on Windows use backslashes.
+		recorded = filepath.FromSlash(strings.ReplaceAll(recorded, `\`, "/"))
+		if filepath.IsAbs(recorded) {
+			candidates = append(candidates, recorded)
+		} else {
+			candidates = append(candidates, filepath.Join(audioDir, recorded))
+		}
+		// A recordings tree copied from elsewhere keeps its tail: try
+		// "site/2024/rec.wav", then "2024/rec.wav", then "rec.wav".
+		parts := strings.Split(filepath.ToSlash(recorded), "/")
+		for i := 1; i < len(parts); i++ {
+			candidates = append(candidates, filepath.Join(audioDir, filepath.Join(parts[i:]...)))
+		}
+		candidates = append(candidates, filepath.Join(filepath.Dir(resultFile), filepath.Base(recorded)))
+	} else {
+		for _, suffix := range resultSuffixes {
+			if !strings.HasSuffix(strings.ToLower(resultFile), strings.ToLower(suffix)) {
+				continue
+			}
+			stem := resultFile[:len(resultFile)-len(suffix)]
+			for _, ext := range audioExtensions {
+				for _, e := range []string{ext, strings.ToUpper(ext)} {
+					candidates = append(candidates, stem+e, filepath.Join(audioDir, filepath.Base(stem)+e))
+				}
+			}
+			break
+		}
+	}
+
+	for _, c := range candidates {
+		rel, err := filepath.Rel(audioDir, c)
+		if err != nil || !filepath.IsLocal(rel) {
+			continue
+		}
+		if info, err := os.Stat(c); err == nil && info.Mode().IsRegular() {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package imports_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/imports"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+// testTableSpec reads "name,time" CSV files and drops rows named "skip".
+func testTableSpec() *imports.TableSpec {
+	return &imports.TableSpec{
+		Component: "imports/test",
+		Comma:     ',',
+		Match:     func(name string) bool { return filepath.Ext(name) == ".csv" },
+		Accept: func(h imports.Header) error {
+			if !h.Has("name") {
+				return errors.NewStd("missing name column")
+			}
+			return nil
+		},
+		Parse: func(row *imports.TableRow) (imports.SourceDetection, bool) {
+			if row.Get("name") == "skip" {
+				return imports.SourceDetection{}, false
+			}
+			return imports.SourceDetection{CommonName: row.Get("Name"), Time: row.Get("time")}, true
+		},
+	}
+}
+
+func TestTableSource_Directory(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.csv"), "\uFEFFName,Time\nRobin,06:00:00\n\nskip,06:01:00\n")
+	writeFile(t, filepath.Join(dir, "sub", "b.csv"), "name,time\nWren,07:00:00\n")
+	writeFile(t, filepath.Join(dir, "other.csv"), "species\nWren\n")
+	writeFile(t, filepath.Join(dir, "notes.txt"), "name\nWren\n")
+
+	src, err := imports.NewTableSource(dir, testTableSpec())
+	require.NoError(t, err)
+	require.NoError(t, src.Validate(t.Context()))
+
+	n, err := src.Count(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n, "blank, dropped and unaccepted rows are not counted")
+
+	var got []string
+	require.NoError(t, src.Iterate(t.Context(), 1, func(batch []imports.SourceDetection) error {
+		for _, d := range batch {
+			got = append(got, d.CommonName+"@"+d.Time)
+		}
+		return nil
+	}))
+	assert.Equal(t, []string{"Robin@06:00:00", "Wren@07:00:00"}, got)
+}
+
+func TestTableSource_ValidateRejects(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "species.csv")
+	writeFile(t, file, "species\nWren\n")
+
+	src, err := imports.NewTableSource(file, testTableSpec())
+	require.NoError(t, err)
+	err = src.Validate(t.Context())
+	require.Error(t, err)
+	assert.True(t, errors.IsCategory(err, errors.CategoryValidation))
+
+	src, err = imports.NewTableSource(dir, testTableSpec())
+	require.NoError(t, err)
+	require.Error(t, src.Validate(t.Context()), "a directory without result files is rejected")
+
+	_, err = imports.NewTableSource(filepath.Join(dir, "missing.csv"), testTableSpec())
+	require.Error(t, err)
+}
+
+func TestRecordingStart(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		want time.Time
+		ok   bool
+	}{
+		{"20240501_053000.WAV", time.Date(2024, 5, 1, 5, 30, 0, 0, time.UTC), true},
+		{`C:\rec\SMA01_20240501_053000.wav`, time.Date(2024, 5, 1, 5, 30, 0, 0, time.UTC), true},
+		{"turdus_merula_91p_20240501T053000Z.wav", time.Date(2024, 5, 1, 5, 30, 0, 0, time.UTC), true},
+		{"2024-05-01 05-30-00.BirdNET.results.csv", time.Date(2024, 5, 1, 5, 30, 0, 0, time.UTC), true},
+		{"recording.wav", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := imports.RecordingStart(tt.name)
+		assert.Equal(t, tt.ok, ok, tt.name)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestPairedAudio(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "site1", "20240501_053000.wav"), "audio")
+	writeFile(t, filepath.Join(root, "20240502_053000.flac"), "audio")
+	result := filepath.Join(root, "site1", "20240501_053000.BirdNET.results.csv")
+
+	assert.Equal(t, "site1/20240501_053000.wav",
+		imports.PairedAudio(root, result, "", ".BirdNET.results.csv"), "recording next to the result file")
+	assert.Equal(t, "site1/20240501_053000.wav",
+		imports.PairedAudio(root, result, `D:\field\20240501_053000.wav`), "path from another machine, found by name")
+	assert.Equal(t, "20240502_053000.flac",
+		imports.PairedAudio(root, result, "20240502_053000.flac"), "found by name in the audio directory")
+	assert.Empty(t, imports.PairedAudio(root, result, "20240503_053000.wav"))
+	assert.Empty(t, imports.PairedAudio(filepath.Join(root, "site1"), result, "../20240502_053000.flac"),
+		"recordings outside the audio directory are not paired")
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/alerting"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/observability"
 )
 
 // GetLogger returns the module logger for system monitor
@@ -42,6 +43,9 @@ type SystemMonitor struct {
 	cancel   context.CancelFunc
 	wg       sync.WaitGroup
 	log      logger.Logger
+
+	// thermalBasePath is where CPU thermal zones are read from.
+	thermalBasePath string
 }
 
 // NewSystemMonitor creates a new system monitor instance
@@ -77,11 +81,12 @@ func NewSystemMonitor(config *conf.Settings) *SystemMonitor {
 	}
 
 	monitor := &SystemMonitor{
-		config:   config,
-		interval: interval,
-		ctx:      ctx,
-		cancel:   cancel,
-		log:      GetLogger(),
+		config:          config,
+		interval:        interval,
+		ctx:             ctx,
+		cancel:          cancel,
+		log:             GetLogger(),
+		thermalBasePath: observability.DefaultThermalBasePath,
 	}
 
 	monitor.log.Info("System monitor instance created",
@@ -194,6 +199,24 @@ func (m *SystemMonitor) checkCPU() {
 		MetricName: alerting.MetricCPUUsage,
 		Properties: map[string]any{alerting.PropertyValue: usage},
 	})
+
+	m.checkCPUTemperature()
+}
+
+// checkCPUTemperature publishes the CPU temperature on systems that expose a
+// thermal sensor. Systems without one are skipped silently.
+func (m *SystemMonitor) checkCPUTemperature() {
+	celsius, _, err := observability.ReadCPUTemperature(m.thermalBasePath)
+	if err != nil {
+		m.log.Debug("CPU temperature not available", logger.Error(err))
+		return
+	}
+
+	alerting.TryPublish(&alerting.AlertEvent{
+		ObjectType: alerting.ObjectTypeSystem,
+		MetricName: alerting.MetricCPUTemperature,
+		Properties: map[string]any{alerting.PropertyValue: celsius},
+	})
 }
 
 // checkMemory monitors memory usage and publishes the metric
@@ -76,6 +76,27 @@ func TestSystemMonitor_CPUCollectsMetrics(t *testing.T) {
 	monitor.checkCPU()
 }
 
+func TestSystemMonitor_CPUTemperatureWithoutSensor(t *testing.T) {
+	t.Parallel()
+
+	config := &conf.Settings{
+		Realtime: conf.RealtimeSettings{
+			Monitoring: conf.MonitoringSettings{
+				Enabled:       true,
+				CheckInterval: 1,
+				CPU:           conf.ResourceEnabled{Enabled: true},
+			},
+		},
+	}
+
+	monitor := NewSystemMonitor(config)
+	require.NotNil(t, monitor)
+	monitor.thermalBasePath = t.TempDir()
+
+	// A system without thermal zones is skipped without panicking
+	monitor.checkCPUTemperature()
+}
+
 func TestSystemMonitor_MemoryCollectsMetrics(t *testing.T) {
 	t.Parallel()
 
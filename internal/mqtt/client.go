@@ -42,6 +42,7 @@ type client struct {
 	metrics           *metrics.MQTTMetrics
 	controlChan       chan string        // Channel for control signals
 	onConnectHandlers []OnConnectHandler // Handlers called on successful connection
+	commandHandler    CommandHandler     // Handler for messages on the command topics, nil when disabled
 	// Reconnection backoff and error suppression state
 	reconnectAttempts  int       // consecutive failed reconnect attempts for backoff calculation
 	lastConnErrMsg     string    // last connection error message for deduplication
@@ -140,6 +141,14 @@ func (c *client) RegisterOnConnectHandler(handler OnConnectHandler) {
 	GetLogger().Debug("Registered OnConnect handler", logger.Int("total_handlers", len(c.onConnectHandlers)))
 }
 
+// SetCommandHandler sets the handler for messages on the command topics.
+func (c *client) SetCommandHandler(handler CommandHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commandHandler = handler
+	GetLogger().Debug("Set MQTT command handler", logger.Bool("enabled", handler != nil))
+}
+
 // IsDebug returns the current debug setting in a thread-safe manner.
 func (c *client) IsDebug() bool {
 	c.mu.RLock()
@@ -953,6 +962,10 @@ func (c *client) onConnect(client mqtt.Client) {
 		}
 	}
 
+	// Subscribe to the command topics. Sessions are clean, so the
+	// subscription is renewed on every connect.
+	c.subscribeCommands(client)
+
 	// Call registered OnConnect handlers
 	c.mu.RLock()
 	handlers := make([]OnConnectHandler, len(c.onConnectHandlers))
@@ -975,6 +988,83 @@ func (c *client) onConnect(client mqtt.Client) {
 	}
 }
 
+// subscribeCommands subscribes to the command topics when a command handler
+// is set. The subscription is confirmed asynchronously so the paho callback
+// is not blocked.
+func (c *client) subscribeCommands(client mqtt.Client) {
+	c.mu.RLock()
+	enabled := c.commandHandler != nil
+	c.mu.RUnlock()
+	if !enabled {
+		return
+	}
+
+	log := GetLogger()
+	topic := CommandTopic(c.config.Topic)
+	token := client.Subscribe(topic, defaultQoS, c.onCommandMessage)
+	go func() {
+		if !token.WaitTimeout(c.config.PublishTimeout) {
+			log.Warn("Timed out subscribing to MQTT command topic", logger.String("topic", topic))
+			return
+		}
+		if err := token.Error(); err != nil {
+			log.Warn("Failed to subscribe to MQTT command topic",
+				logger.String("topic", topic),
+				logger.Error(err))
+			return
+		}
+		log.Info("Subscribed to MQTT command topic", logger.String("topic", topic))
+	}()
+}
+
+// onCommandMessage parses a command message and runs the command handler in
+// its own goroutine, since commands such as backups outlast the paho message
+// callback. Retained button presses are ignored: the broker replays them on
+// every (re)connect, which would otherwise restart or back up in a loop.
+func (c *client) onCommandMessage(_ mqtt.Client, msg mqtt.Message) {
+	log := GetLogger()
+	cmd, ok := ParseCommand(c.config.Topic, msg.Topic(), string(msg.Payload()))
+	if !ok {
+		log.Warn("Ignoring message on unknown MQTT command topic", logger.String("topic", msg.Topic()))
+		return
+	}
+	if msg.Retained() && cmd.IsButton() {
+		log.Warn("Ignoring retained MQTT command; clear the retained message on the broker",
+			logger.String("command", cmd.Name),
+			logger.String("topic", msg.Topic()))
+		return
+	}
+
+	c.mu.RLock()
+	handler := c.commandHandler
+	c.mu.RUnlock()
+	if handler == nil {
+		return
+	}
+
+	log.Info("Received MQTT command",
+		logger.String("command", cmd.Name),
+		logger.String("source_id", cmd.SourceID),
+		logger.String("payload", cmd.Payload))
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("MQTT command handler panicked",
+					logger.String("command", cmd.Name),
+					logger.Any("panic", r))
+			}
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+		if err := handler(ctx, cmd); err != nil {
+			log.Warn("MQTT command failed",
+				logger.String("command", cmd.Name),
+				logger.String("source_id", cmd.SourceID),
+				logger.Error(err))
+		}
+	}()
+}
+
 func (c *client) onConnectionLost(client mqtt.Client, err error) {
 	// Connection loss is expected for long-running MQTT connections (EOF, network changes).
 	// Log as warning, not error, to avoid Sentry noise.
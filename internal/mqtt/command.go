@@ -0,0 +1,108 @@
+// command.go: MQTT command channel for controlling the station from the broker.
+package mqtt
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// commandTopicSegment separates the base topic from command topics. Commands
+// arrive on <topic>/cmd/<command> for the station and on
+// <topic>/cmd/source/<sourceID>/<command> for a single audio source.
+const commandTopicSegment = "/cmd/"
+
+// sourceCommandPrefix marks per-source commands below the command segment.
+const sourceCommandPrefix = "source/"
+
+// Station commands.
+const (
+	CommandRestartAnalysis = "restart_analysis" // button: restart the analysis pipeline
+	CommandReloadModel     = "reload_model"     // button: reload the BirdNET model
+	CommandBackup          = "backup"           // button: run a backup now
+	CommandQuietHours      = "quiet_hours"      // switch: ON enables, OFF suspends quiet hours
+)
+
+// Per-source commands.
+const (
+	CommandRestartSource = "restart" // button: restart the audio source
+	CommandCapture       = "capture" // switch: ON resumes, OFF pauses the audio source
+)
+
+// Switch payloads, matching the Home Assistant defaults.
+const (
+	PayloadOn    = "ON"
+	PayloadOff   = "OFF"
+	PayloadPress = "PRESS"
+)
+
+// commandTimeout bounds a single command. Backups run longer than the other
+// commands, so the handler applies this limit to the whole command.
+const commandTimeout = 10 * time.Minute
+
+// Command is a control message received on a command topic.
+type Command struct {
+	Name     string // command name, e.g. CommandReloadModel
+	SourceID string // target audio source for per-source commands, empty otherwise
+	Payload  string // trimmed message payload, e.g. PayloadOn
+}
+
+// CommandHandler executes a command received from the broker.
+type CommandHandler func(ctx context.Context, cmd Command) error
+
+// CommandTopic returns the topic filter the client subscribes to for commands.
+func CommandTopic(baseTopic string) string {
+	return strings.TrimSuffix(baseTopic, "/") + commandTopicSegment + "#"
+}
+
+// StationCommandTopic returns the topic for a station command.
+func StationCommandTopic(baseTopic, name string) string {
+	return strings.TrimSuffix(baseTopic, "/") + commandTopicSegment + name
+}
+
+// SourceCommandTopic returns the topic for a command addressed to one source.
+func SourceCommandTopic(baseTopic, sourceID, name string) string {
+	return strings.TrimSuffix(baseTopic, "/") + commandTopicSegment + sourceCommandPrefix + sourceID + "/" + name
+}
+
+// ParseCommand maps a message received on topic to a Command. It reports false
+// for topics outside the command tree of baseTopic and for malformed topics.
+func ParseCommand(baseTopic, topic, payload string) (Command, bool) {
+	prefix := strings.TrimSuffix(baseTopic, "/") + commandTopicSegment
+	rest, ok := strings.CutPrefix(topic, prefix)
+	if !ok || rest == "" {
+		return Command{}, false
+	}
+
+	cmd := Command{Payload: strings.TrimSpace(payload)}
+	if target, ok := strings.CutPrefix(rest, sourceCommandPrefix); ok {
+		sourceID, name, found := strings.Cut(target, "/")
+		if !found || sourceID == "" || name == "" || strings.Contains(name, "/") {
+			return Command{}, false
+		}
+		cmd.SourceID = sourceID
+		cmd.Name = name
+		return cmd, true
+	}
+	if strings.Contains(rest, "/") {
+		return Command{}, false
+	}
+	cmd.Name = rest
+	return cmd, true
+}
+
+// IsButton reports whether the command is a one-shot button press rather
+// than a switch carrying state. A retained switch message restores the
+// desired state after a reconnect; a retained button press would run again
+// on every reconnect.
+func (cmd Command) IsButton() bool {
+	if cmd.SourceID != "" {
+		return cmd.Name == CommandRestartSource
+	}
+	switch cmd.Name {
+	case CommandRestartAnalysis, CommandReloadModel, CommandBackup:
+		return true
+	default:
+		return false
+	}
+}
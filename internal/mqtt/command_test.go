@@ -0,0 +1,127 @@
+package mqtt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		topic   string
+		payload string
+		want    Command
+		ok      bool
+	}{
+		{"station command", "birdnet/cmd/reload_model", "PRESS", Command{Name: CommandReloadModel, Payload: PayloadPress}, true},
+		{"switch payload is trimmed", "birdnet/cmd/quiet_hours", " OFF\n", Command{Name: CommandQuietHours, Payload: PayloadOff}, true},
+		{"source command", "birdnet/cmd/source/rtsp_abc123/capture", "ON", Command{Name: CommandCapture, SourceID: "rtsp_abc123", Payload: PayloadOn}, true},
+		{"other base topic", "other/cmd/reload_model", "", Command{}, false},
+		{"state topic", "birdnet/status", "online", Command{}, false},
+		{"empty command", "birdnet/cmd/", "", Command{}, false},
+		{"nested station command", "birdnet/cmd/reload_model/extra", "", Command{}, false},
+		{"source without command", "birdnet/cmd/source/rtsp_abc123", "", Command{}, false},
+		{"source with nested command", "birdnet/cmd/source/rtsp_abc123/capture/x", "", Command{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := ParseCommand("birdnet", tt.topic, tt.payload)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommandTopics(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "birdnet/cmd/#", CommandTopic("birdnet/"))
+	assert.Equal(t, "birdnet/cmd/backup", StationCommandTopic("birdnet", CommandBackup))
+	assert.Equal(t, "birdnet/cmd/source/rtsp_1/restart", SourceCommandTopic("birdnet", "rtsp_1", CommandRestartSource))
+
+	cmd, ok := ParseCommand("birdnet", SourceCommandTopic("birdnet", "rtsp_1", CommandRestartSource), "PRESS")
+	require.True(t, ok, "built topics must parse")
+	assert.Equal(t, "rtsp_1", cmd.SourceID)
+}
+
+// fakeMessage is a minimal paho message for command callback tests.
+type fakeMessage struct {
+	topic    string
+	payload  []byte
+	retained bool
+}
+
+func (m *fakeMessage) Duplicate() bool   { return false }
+func (m *fakeMessage) Qos() byte         { return defaultQoS }
+func (m *fakeMessage) Retained() bool    { return m.retained }
+func (m *fakeMessage) Topic() string     { return m.topic }
+func (m *fakeMessage) MessageID() uint16 { return 1 }
+func (m *fakeMessage) Payload() []byte   { return m.payload }
+func (m *fakeMessage) Ack()              {}
+
+func TestOnCommandMessage(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan Command, 1)
+	c := &client{config: Config{Topic: "birdnet"}}
+	c.SetCommandHandler(func(_ context.Context, cmd Command) error {
+		received <- cmd
+		return nil
+	})
+
+	c.onCommandMessage(nil, &fakeMessage{topic: "birdnet/unknown/topic"})
+	c.onCommandMessage(nil, &fakeMessage{topic: "birdnet/cmd/source/hw_0/restart", payload: []byte("PRESS")})
+
+	select {
+	case cmd := <-received:
+		assert.Equal(t, Command{Name: CommandRestartSource, SourceID: "hw_0", Payload: PayloadPress}, cmd)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "command handler was not called")
+	}
+	assert.Empty(t, received, "messages outside the command tree are ignored")
+}
+
+func TestOnCommandMessage_IgnoresRetainedButtons(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan Command, 4)
+	c := &client{config: Config{Topic: "birdnet"}}
+	c.SetCommandHandler(func(_ context.Context, cmd Command) error {
+		received <- cmd
+		return nil
+	})
+
+	// The broker replays retained messages on every reconnect; button presses
+	// must not run again, while a retained switch restores its state
+	c.onCommandMessage(nil, &fakeMessage{topic: "birdnet/cmd/backup", payload: []byte("PRESS"), retained: true})
+	c.onCommandMessage(nil, &fakeMessage{topic: "birdnet/cmd/restart_analysis", payload: []byte("PRESS"), retained: true})
+	c.onCommandMessage(nil, &fakeMessage{topic: "birdnet/cmd/source/hw_0/restart", payload: []byte("PRESS"), retained: true})
+	c.onCommandMessage(nil, &fakeMessage{topic: "birdnet/cmd/quiet_hours", payload: []byte("ON"), retained: true})
+
+	select {
+	case cmd := <-received:
+		assert.Equal(t, Command{Name: CommandQuietHours, Payload: PayloadOn}, cmd)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "retained switch command was not handled")
+	}
+	assert.Never(t, func() bool { return len(received) > 0 }, 100*time.Millisecond, 10*time.Millisecond,
+		"retained button presses are ignored")
+}
+
+func TestCommandIsButton(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, Command{Name: CommandBackup}.IsButton())
+	assert.True(t, Command{Name: CommandReloadModel}.IsButton())
+	assert.True(t, Command{Name: CommandRestartSource, SourceID: "hw_0"}.IsButton())
+	assert.False(t, Command{Name: CommandQuietHours}.IsButton())
+	assert.False(t, Command{Name: CommandCapture, SourceID: "hw_0"}.IsButton())
+	assert.False(t, Command{Name: CommandRestartSource}.IsButton(), "restart is only a source command")
+}
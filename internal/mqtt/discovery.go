@@ -40,6 +40,35 @@ var AllSensorTypes = []string{
 	SensorSoundLevel,
 }
 
+// Home Assistant components of the command entities.
+const (
+	componentButton = "button"
+	componentSwitch = "switch"
+)
+
+// controlEntity describes a Home Assistant button or switch bound to a
+// command topic.
+type controlEntity struct {
+	component string // componentButton or componentSwitch
+	command   string // command name, also the entity's object ID suffix
+	name      string
+	icon      string
+}
+
+// stationControls are the command entities of the bridge device.
+var stationControls = []controlEntity{
+	{componentButton, CommandRestartAnalysis, "Restart Analysis", "mdi:restart"},
+	{componentButton, CommandReloadModel, "Reload Model", "mdi:reload"},
+	{componentButton, CommandBackup, "Run Backup", "mdi:database-export"},
+	{componentSwitch, CommandQuietHours, "Quiet Hours", "mdi:weather-night"},
+}
+
+// sourceControls are the command entities of each audio source device.
+var sourceControls = []controlEntity{
+	{componentButton, CommandRestartSource, "Restart", "mdi:restart"},
+	{componentSwitch, CommandCapture, "Capture", "mdi:microphone"},
+}
+
 // idSanitizer replaces invalid characters in IDs with underscores.
 // Home Assistant requires IDs to contain only [a-zA-Z0-9_-].
 var idSanitizer = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
@@ -117,7 +146,8 @@ func shortenDisplayName(name string) string {
 type DiscoveryPayload struct {
 	Name                string           `json:"name"`
 	UniqueID            string           `json:"unique_id"`
-	StateTopic          string           `json:"state_topic"`
+	StateTopic          string           `json:"state_topic,omitempty"`
+	CommandTopic        string           `json:"command_topic,omitempty"`
 	ValueTemplate       string           `json:"value_template,omitempty"`
 	UnitOfMeasurement   string           `json:"unit_of_measurement,omitempty"`
 	DeviceClass         string           `json:"device_class,omitempty"`
@@ -157,6 +187,7 @@ type DiscoveryConfig struct {
 	DeviceName      string // Base name for devices (e.g., BirdNET-Go)
 	NodeID          string // Node identifier (typically main.name from config)
 	Version         string // Software version
+	Commands        bool   // true to publish button and switch entities for the command topics
 }
 
 // Publisher handles publishing Home Assistant discovery messages.
@@ -232,7 +263,27 @@ func (p *Publisher) publishBridgeDiscovery(ctx context.Context) error {
 	}
 
 	topic := p.getBridgeTopic(nodeID)
-	return p.publishPayload(ctx, topic, &payload)
+	if err := p.publishPayload(ctx, topic, &payload); err != nil {
+		return err
+	}
+
+	if !p.config.Commands {
+		return nil
+	}
+	for _, control := range stationControls {
+		if err := p.publishControl(ctx, nodeID, nodeID, &control, &DiscoveryPayload{
+			Name:              control.name,
+			UniqueID:          bridgeID + "_" + control.command,
+			CommandTopic:      StationCommandTopic(p.config.BaseTopic, control.command),
+			Icon:              control.icon,
+			EntityCategory:    "config",
+			AvailabilityTopic: p.config.BaseTopic + "/status",
+			Device:            DiscoveryDevice{Identifiers: []string{bridgeID}},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // publishSourceDiscovery publishes discovery for a specific audio source.
@@ -327,9 +378,37 @@ func (p *Publisher) publishSourceDiscovery(ctx context.Context, source datastore
 		}
 	}
 
+	if !p.config.Commands {
+		return nil
+	}
+	// Command topics carry the raw source ID, which the command handler
+	// resolves in the source registry.
+	for _, control := range sourceControls {
+		if err := p.publishControl(ctx, nodeID, nodeID+"_"+sourceID, &control, &DiscoveryPayload{
+			Name:              control.name,
+			UniqueID:          deviceID + "_" + control.command,
+			CommandTopic:      SourceCommandTopic(p.config.BaseTopic, source.ID, control.command),
+			Icon:              control.icon,
+			AvailabilityTopic: availabilityTopic,
+			Device:            device,
+		}); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// publishControl publishes the discovery message of a button or switch. The
+// entities have no state topic, so Home Assistant treats switches as
+// optimistic.
+func (p *Publisher) publishControl(ctx context.Context, nodeID, objectPrefix string, control *controlEntity, payload *DiscoveryPayload) error {
+	if payload.Origin == nil {
+		payload.Origin = p.defaultOrigin()
+	}
+	return p.publishPayload(ctx, p.getControlTopic(nodeID, objectPrefix, control), payload)
+}
+
 // publishSensor publishes a single sensor discovery message.
 func (p *Publisher) publishSensor(ctx context.Context, nodeID, sourceID, sensorType string, payload *DiscoveryPayload) error {
 	// Add origin if not set
@@ -367,6 +446,12 @@ func (p *Publisher) getSensorTopic(nodeID, sourceID, sensorType string) string {
 	return fmt.Sprintf("%s/sensor/%s/%s/config", p.config.DiscoveryPrefix, nodeID, objectID)
 }
 
+// getControlTopic constructs the MQTT discovery topic for a button or switch.
+func (p *Publisher) getControlTopic(nodeID, objectPrefix string, control *controlEntity) string {
+	objectID := fmt.Sprintf("%s_%s", objectPrefix, control.command)
+	return fmt.Sprintf("%s/%s/%s/%s/config", p.config.DiscoveryPrefix, control.component, nodeID, objectID)
+}
+
 // defaultOrigin returns the standard origin block for discovery payloads.
 func (p *Publisher) defaultOrigin() *DiscoveryOrigin {
 	return &DiscoveryOrigin{
@@ -393,6 +478,14 @@ func (p *Publisher) RemoveDiscovery(ctx context.Context, sources []datastore.Aud
 	if err := p.client.PublishWithRetain(ctx, bridgeTopic, "", true); err != nil {
 		log.Warn("Failed to remove bridge discovery", logger.Error(err))
 	}
+	for i := range stationControls {
+		topic := p.getControlTopic(nodeID, nodeID, &stationControls[i])
+		if err := p.client.PublishWithRetain(ctx, topic, "", true); err != nil {
+			log.Warn("Failed to remove control discovery",
+				logger.String("topic", topic),
+				logger.Error(err))
+		}
+	}
 
 	// Remove each source's sensors
 	for _, source := range sources {
@@ -406,6 +499,14 @@ func (p *Publisher) RemoveDiscovery(ctx context.Context, sources []datastore.Aud
 					logger.Error(err))
 			}
 		}
+		for i := range sourceControls {
+			topic := p.getControlTopic(nodeID, nodeID+"_"+sourceID, &sourceControls[i])
+			if err := p.client.PublishWithRetain(ctx, topic, "", true); err != nil {
+				log.Warn("Failed to remove control discovery",
+					logger.String("topic", topic),
+					logger.Error(err))
+			}
+		}
 	}
 
 	return nil
@@ -85,6 +85,7 @@ func (m *mockPublisher) Publish(_ context.Context, _, _ string) error          {
 func (m *mockPublisher) SetControlChannel(_ chan string)                       {}
 func (m *mockPublisher) TestConnection(_ context.Context, _ chan<- TestResult) {}
 func (m *mockPublisher) RegisterOnConnectHandler(_ OnConnectHandler)           {}
+func (m *mockPublisher) SetCommandHandler(_ CommandHandler)                    {}
 
 func (m *mockPublisher) PublishWithRetain(_ context.Context, topic, data string, _ bool) error {
 	if m.publishError != nil {
@@ -359,6 +360,48 @@ func TestRemoveDiscovery(t *testing.T) {
 	}
 }
 
+// TestPublishDiscoveryCommands verifies button and switch entities are
+// published for the command topics only when commands are enabled.
+func TestPublishDiscoveryCommands(t *testing.T) {
+	t.Parallel()
+
+	config := DiscoveryConfig{
+		DiscoveryPrefix: "homeassistant",
+		BaseTopic:       "birdnet",
+		DeviceName:      "BirdNET-Go",
+		NodeID:          "node",
+		Version:         "1.0.0",
+	}
+	sources := []datastore.AudioSource{{ID: "rtsp_abc123", DisplayName: "Garden"}}
+	settings := &conf.Settings{}
+
+	mock := newMockPublisher()
+	require.NoError(t, NewDiscoveryPublisher(mock, &config).PublishDiscovery(t.Context(), sources, settings))
+	assert.NotContains(t, mock.publishedMessages, "homeassistant/button/node/node_reload_model/config",
+		"no command entities without commands enabled")
+
+	config.Commands = true
+	mock = newMockPublisher()
+	require.NoError(t, NewDiscoveryPublisher(mock, &config).PublishDiscovery(t.Context(), sources, settings))
+	assert.Len(t, mock.publishedMessages, 1+len(stationControls)+3+len(sourceControls))
+
+	var button DiscoveryPayload
+	require.NoError(t, json.Unmarshal([]byte(mock.publishedMessages["homeassistant/button/node/node_reload_model/config"]), &button))
+	assert.Equal(t, "birdnet/cmd/reload_model", button.CommandTopic)
+	assert.Empty(t, button.StateTopic)
+	assert.Equal(t, []string{"birdnet_go_node_bridge"}, button.Device.Identifiers)
+
+	var capture DiscoveryPayload
+	require.NoError(t, json.Unmarshal([]byte(mock.publishedMessages["homeassistant/switch/node/node_Garden_capture/config"]), &capture))
+	assert.Equal(t, "birdnet/cmd/source/rtsp_abc123/capture", capture.CommandTopic, "command topics use the raw source ID")
+	assert.Equal(t, "birdnet_go_node_Garden_capture", capture.UniqueID)
+	assert.Contains(t, mock.publishedMessages, "homeassistant/switch/node/node_quiet_hours/config")
+
+	require.NoError(t, NewDiscoveryPublisher(mock, &config).RemoveDiscovery(t.Context(), sources))
+	assert.Empty(t, mock.publishedMessages["homeassistant/switch/node/node_Garden_capture/config"])
+	assert.Empty(t, mock.publishedMessages["homeassistant/button/node/node_backup/config"])
+}
+
 // TestDiscoveryConfigDefaults verifies default configuration values.
 func TestDiscoveryConfigDefaults(t *testing.T) {
 	t.Parallel()
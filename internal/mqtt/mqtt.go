@@ -98,6 +98,11 @@ type Client interface {
 	// the client successfully connects or reconnects to the broker. Multiple handlers
 	// can be registered and will be called in order of registration.
 	RegisterOnConnectHandler(handler OnConnectHandler)
+
+	// SetCommandHandler sets the handler for messages on the command topics
+	// (<topic>/cmd/#). The client subscribes on each connect while a handler is
+	// set; set it before Connect so the initial connection subscribes too.
+	SetCommandHandler(handler CommandHandler)
 }
 
 // Config holds the configuration for the MQTT client.
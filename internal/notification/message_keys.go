@@ -95,6 +95,8 @@ const (
 	MsgErrAlertDuplicateName     = "errors.alert.duplicateName"
 	MsgErrAlertInvalidJSON       = "errors.alert.invalidJSON"
 	MsgErrAlertInvalidEscalation = "errors.alert.invalidEscalation"
+	MsgErrAlertInvalidAction     = "errors.alert.invalidAction"
+	MsgErrAlertInvalidConditions = "errors.alert.invalidConditions"
 	MsgErrAlertEngineUnavailable = "errors.alert.engineUnavailable"
 
 	// Detection errors
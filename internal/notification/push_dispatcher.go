@@ -462,6 +462,12 @@ func (d *pushDispatcher) dispatchEnhanced(ctx context.Context, notif *Notificati
 		return
 	}
 
+	// Webhooks go through the durable outbox when one is set; its worker
+	// retries across restarts and calls back into DeliverQueuedWebhook.
+	if d.enqueueWebhook(ctx, notif, ep) {
+		return
+	}
+
 	// Increment dispatch total and track active dispatches
 	if d.metrics != nil {
 		d.metrics.IncrementDispatchTotal()
@@ -0,0 +1,106 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// ErrWebhookProviderNotFound is returned by DeliverQueuedWebhook when the
+// webhook provider of a queued notification is no longer configured.
+var ErrWebhookProviderNotFound = errors.NewStd("webhook provider not found")
+
+// WebhookOutbox queues a serialized notification for the named webhook
+// provider so the delivery survives restarts and long network outages.
+type WebhookOutbox func(ctx context.Context, provider, summary string, payload []byte) error
+
+// webhookOutbox is the durable queue for webhook deliveries, or nil to send
+// them directly with in-memory retries.
+var webhookOutbox atomic.Pointer[WebhookOutbox]
+
+// SetWebhookOutbox routes webhook deliveries through a durable queue. The
+// queue must hand the payloads back to DeliverQueuedWebhook. Pass nil to send
+// webhooks directly again.
+func SetWebhookOutbox(fn WebhookOutbox) {
+	if fn == nil {
+		webhookOutbox.Store(nil)
+		return
+	}
+	webhookOutbox.Store(&fn)
+}
+
+// enqueueWebhook hands a webhook delivery to the durable queue. It reports
+// false when the provider is not a webhook, no queue is set, or queueing
+// failed, in which case the caller delivers the notification directly.
+func (d *pushDispatcher) enqueueWebhook(ctx context.Context, notif *Notification, ep *enhancedProvider) bool {
+	queue := webhookOutbox.Load()
+	if queue == nil {
+		return false
+	}
+	if _, ok := ep.prov.(*WebhookProvider); !ok {
+		return false
+	}
+
+	payload, err := json.Marshal(notif)
+	if err != nil {
+		d.log.Warn("failed to serialize notification for the outbox, sending directly",
+			logger.String("provider", ep.name),
+			logger.String("notification_id", notif.ID),
+			logger.Error(err))
+		return false
+	}
+	if err := (*queue)(ctx, ep.name, notif.Title, payload); err != nil {
+		d.log.Warn("failed to queue webhook delivery, sending directly",
+			logger.String("provider", ep.name),
+			logger.String("notification_id", notif.ID),
+			logger.Error(err))
+		return false
+	}
+	d.log.Debug("webhook delivery queued",
+		logger.String("provider", ep.name),
+		logger.String("notification_id", notif.ID))
+	return true
+}
+
+// DeliverQueuedWebhook sends a notification queued by the webhook outbox to
+// the named provider, through the provider's circuit breaker. It returns
+// ErrWebhookProviderNotFound when the provider was removed or disabled, in
+// which case retrying cannot succeed.
+func DeliverQueuedWebhook(ctx context.Context, provider string, payload []byte) error {
+	dispatcherMu.Lock()
+	d := globalPushDispatcher
+	dispatcherMu.Unlock()
+
+	var ep *enhancedProvider
+	if d != nil {
+		for i := range d.providers {
+			if d.providers[i].name == provider && d.providers[i].prov.IsEnabled() {
+				ep = &d.providers[i]
+				break
+			}
+		}
+	}
+	if ep == nil {
+		return ErrWebhookProviderNotFound
+	}
+
+	var notif Notification
+	if err := json.Unmarshal(payload, &notif); err != nil {
+		return errors.New(err).
+			Component("notification").
+			Category(errors.CategoryProcessing).
+			Context("operation", "decode_queued_webhook").
+			Build()
+	}
+
+	duration, err := d.attemptSend(ctx, &notif, ep)
+	d.recordAttemptMetrics(ep.name, string(notif.Type), err, duration, 1)
+	if err != nil {
+		return err
+	}
+	d.logSuccess(ep.name, &notif, string(notif.Type), 1, duration)
+	return nil
+}
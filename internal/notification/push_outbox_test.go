@@ -0,0 +1,66 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookOutbox_QueuesAndDelivers(t *testing.T) {
+	resetDispatcherState(t)
+	t.Cleanup(func() { SetWebhookOutbox(nil) })
+
+	var received atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received.Store(string(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	provider, err := NewWebhookProvider("hook", true, []WebhookEndpoint{{URL: server.URL, Method: "POST"}}, nil, "")
+	require.NoError(t, err)
+	require.NoError(t, provider.ValidateConfig())
+
+	d := &pushDispatcher{
+		log:       GetLogger(),
+		enabled:   true,
+		providers: []enhancedProvider{{prov: provider, name: "hook"}},
+	}
+	dispatcherMu.Lock()
+	globalPushDispatcher = d
+	dispatcherMu.Unlock()
+
+	var queued struct {
+		provider, summary string
+		payload           []byte
+	}
+	SetWebhookOutbox(func(_ context.Context, provider, summary string, payload []byte) error {
+		queued.provider, queued.summary, queued.payload = provider, summary, payload
+		return nil
+	})
+
+	notif := &Notification{ID: "n-1", Type: TypeDetection, Priority: PriorityHigh, Title: "Eurasian Wren", Message: "detected"}
+	d.dispatchEnhanced(t.Context(), notif, &d.providers[0])
+
+	assert.Nil(t, received.Load(), "queued webhooks are not sent directly")
+	assert.Equal(t, "hook", queued.provider)
+	assert.Equal(t, "Eurasian Wren", queued.summary)
+
+	require.NoError(t, DeliverQueuedWebhook(t.Context(), "hook", queued.payload))
+	body, ok := received.Load().(string)
+	require.True(t, ok, "webhook should receive the queued notification")
+	var payload WebhookPayload
+	require.NoError(t, json.Unmarshal([]byte(body), &payload))
+	assert.Equal(t, "n-1", payload.ID)
+	assert.Equal(t, "Eurasian Wren", payload.Title)
+
+	require.ErrorIs(t, DeliverQueuedWebhook(t.Context(), "removed", queued.payload), ErrWebhookProviderNotFound)
+}
@@ -0,0 +1,390 @@
+// Package outbox delivers queued payloads to external services with retries
+// that survive restarts and long network outages.
+//
+// Integrations such as BirdWeather uploads, MQTT publishes and push webhooks
+// enqueue a serialized payload instead of sending it themselves. The outbox
+// stores the payload in the v2 database and a worker hands it to the
+// deliverer registered for its destination. Failed deliveries are retried
+// with exponential backoff; items that keep failing, or fail permanently, move
+// to a dead-letter state where they wait for a manual retry or purge.
+//
+// Items of one destination are delivered in the order they were queued: a
+// failing item holds back the items behind it, so a station coming back
+// online replays its backlog oldest first. Destinations are independent of
+// each other.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Destination kinds. A destination is a kind optionally followed by a colon
+// and a target, e.g. "webhook:discord" for the push provider named discord.
+const (
+	KindBirdWeather = "birdweather"
+	KindMQTT        = "mqtt"
+	KindWebhook     = "webhook"
+)
+
+const (
+	// defaultMaxAttempts moves an item to the dead-letter state after this
+	// many failed attempts. With the default delays this is roughly two days
+	// of retries, enough to ride out an LTE link that is down for hours.
+	defaultMaxAttempts = 100
+	// defaultBaseDelay is the delay after the first failed attempt.
+	defaultBaseDelay = 30 * time.Second
+	// defaultMaxDelay caps the exponential backoff.
+	defaultMaxDelay = 30 * time.Minute
+	// pollInterval is how often the worker looks for due items when nothing
+	// wakes it earlier.
+	pollInterval = 15 * time.Second
+	// deliveryTimeout bounds a single delivery attempt.
+	deliveryTimeout = 2 * time.Minute
+	// storeTimeout bounds the database operations of the worker.
+	storeTimeout = 10 * time.Second
+	// maxErrorLength truncates stored error messages.
+	maxErrorLength = 1000
+)
+
+// DeliverFunc sends a payload to the target of a destination. It returns an
+// error wrapped with Permanent when retrying cannot succeed.
+type DeliverFunc func(ctx context.Context, target string, payload []byte) error
+
+// permanentError marks a delivery failure that must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as a failure that retrying cannot fix, such as a
+// rejected payload. The item moves straight to the dead-letter state.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var perr *permanentError
+	return errors.As(err, &perr)
+}
+
+// Destination joins a destination kind and target.
+func Destination(kind, target string) string {
+	if target == "" {
+		return kind
+	}
+	return kind + ":" + target
+}
+
+// splitDestination returns the kind and target of a destination.
+func splitDestination(destination string) (kind, target string) {
+	kind, target, _ = strings.Cut(destination, ":")
+	return kind, target
+}
+
+// Option configures an Outbox.
+type Option func(*Outbox)
+
+// WithRetryPolicy overrides the attempt budget and backoff delays.
+func WithRetryPolicy(maxAttempts int, baseDelay, maxDelay time.Duration) Option {
+	return func(o *Outbox) {
+		o.maxAttempts = maxAttempts
+		o.baseDelay = baseDelay
+		o.maxDelay = maxDelay
+	}
+}
+
+// WithClock sets the time source, for tests.
+func WithClock(now func() time.Time) Option {
+	return func(o *Outbox) {
+		o.now = now
+	}
+}
+
+// Outbox queues deliveries in the database and replays them in the background.
+type Outbox struct {
+	repo repository.OutboxRepository
+	log  logger.Logger
+	now  func() time.Time
+
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+
+	deliverersMu sync.RWMutex
+	deliverers   map[string]DeliverFunc
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates an outbox on top of repo. Register the deliverers and call
+// Start to begin delivering.
+func New(repo repository.OutboxRepository, opts ...Option) *Outbox {
+	o := &Outbox{
+		repo:        repo,
+		log:         logger.Global().Module("outbox"),
+		now:         time.Now,
+		maxAttempts: defaultMaxAttempts,
+		baseDelay:   defaultBaseDelay,
+		maxDelay:    defaultMaxDelay,
+		deliverers:  make(map[string]DeliverFunc),
+		wake:        make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Register sets the deliverer for a destination kind, replacing any previous
+// one. Items of a kind without a deliverer stay queued and are retried.
+func (o *Outbox) Register(kind string, fn DeliverFunc) {
+	o.deliverersMu.Lock()
+	defer o.deliverersMu.Unlock()
+	o.deliverers[kind] = fn
+}
+
+// deliverer returns the deliverer for a destination kind.
+func (o *Outbox) deliverer(kind string) (DeliverFunc, bool) {
+	o.deliverersMu.RLock()
+	defer o.deliverersMu.RUnlock()
+	fn, ok := o.deliverers[kind]
+	return fn, ok
+}
+
+// Enqueue stores a payload for delivery to destination. The summary is shown
+// when the outbox is inspected. The worker is woken so the item is delivered
+// right away when the destination is reachable.
+func (o *Outbox) Enqueue(ctx context.Context, destination, summary string, payload []byte) error {
+	item := &entities.OutboxItem{
+		Destination:   destination,
+		Status:        entities.OutboxStatusPending,
+		Summary:       truncate(summary, 255),
+		Payload:       payload,
+		NextAttemptAt: o.now(),
+	}
+	if err := o.repo.Create(ctx, item); err != nil {
+		return errors.New(err).
+			Component("outbox").
+			Category(errors.CategoryDatabase).
+			Context("operation", "enqueue").
+			Context("destination", destination).
+			Build()
+	}
+	o.Wake()
+	return nil
+}
+
+// Wake makes the worker look for due items now, e.g. after items were
+// retried through the API.
+func (o *Outbox) Wake() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the delivery worker until ctx is cancelled or Stop is called.
+func (o *Outbox) Start(ctx context.Context) {
+	ctx, o.cancel = context.WithCancel(ctx)
+	o.done = make(chan struct{})
+	go o.run(ctx)
+}
+
+// Stop stops the worker and waits for in-flight deliveries to finish.
+func (o *Outbox) Stop() {
+	if o.cancel == nil {
+		return
+	}
+	o.cancel()
+	<-o.done
+}
+
+// run is the worker loop.
+func (o *Outbox) run(ctx context.Context) {
+	defer close(o.done)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		o.drain(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+// drain delivers due items until every destination is empty, waiting for a
+// retry, or failing. Each round attempts the oldest pending item of every due
+// destination concurrently, so a slow destination does not hold back others.
+func (o *Outbox) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		heads, err := o.pendingHeads(ctx)
+		if err != nil {
+			o.log.Warn("failed to load pending outbox items", logger.Error(err))
+			return
+		}
+
+		now := o.now()
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			delivered bool
+		)
+		for i := range heads {
+			item := &heads[i]
+			if item.NextAttemptAt.After(now) {
+				continue
+			}
+			wg.Go(func() {
+				if o.attempt(ctx, item) {
+					mu.Lock()
+					delivered = true
+					mu.Unlock()
+				}
+			})
+		}
+		wg.Wait()
+
+		// Keep going only while deliveries succeed; a failed head is not due
+		// again until its backoff has passed.
+		if !delivered {
+			return
+		}
+	}
+}
+
+// pendingHeads loads the oldest pending item of every destination.
+func (o *Outbox) pendingHeads(ctx context.Context) ([]entities.OutboxItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, storeTimeout)
+	defer cancel()
+	return o.repo.PendingHeads(ctx)
+}
+
+// attempt delivers one item and records the outcome. It reports whether the
+// item left the pending queue, by delivery or by dead-lettering, so the
+// destination can move on.
+func (o *Outbox) attempt(ctx context.Context, item *entities.OutboxItem) bool {
+	err := o.deliver(ctx, item)
+
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), storeTimeout)
+	defer cancel()
+
+	if err == nil {
+		if err := o.repo.Delete(storeCtx, item.ID); err != nil && !errors.Is(err, repository.ErrOutboxItemNotFound) {
+			o.log.Warn("failed to remove delivered outbox item",
+				logger.Uint64("item_id", uint64(item.ID)),
+				logger.String("destination", item.Destination),
+				logger.Error(err))
+			return false
+		}
+		if item.Attempts > 0 {
+			o.log.Info("outbox item delivered after retries",
+				logger.Uint64("item_id", uint64(item.ID)),
+				logger.String("destination", item.Destination),
+				logger.Int("attempts", item.Attempts+1))
+		}
+		return true
+	}
+
+	// A shutdown interrupted the attempt; it does not count.
+	if ctx.Err() != nil {
+		return false
+	}
+
+	attempts := item.Attempts + 1
+	lastErr := truncate(err.Error(), maxErrorLength)
+	if IsPermanent(err) || attempts >= o.maxAttempts {
+		if err := o.repo.MarkDead(storeCtx, item.ID, attempts, lastErr); err != nil {
+			o.log.Warn("failed to dead-letter outbox item",
+				logger.Uint64("item_id", uint64(item.ID)),
+				logger.String("destination", item.Destination),
+				logger.Error(err))
+			return false
+		}
+		o.log.Error("outbox item moved to dead letters",
+			logger.Uint64("item_id", uint64(item.ID)),
+			logger.String("destination", item.Destination),
+			logger.String("summary", item.Summary),
+			logger.Int("attempts", attempts),
+			logger.Bool("permanent", IsPermanent(err)),
+			logger.Error(err))
+		return true
+	}
+
+	next := o.now().Add(o.backoff(attempts))
+	if err := o.repo.RecordFailure(storeCtx, item.ID, attempts, next, lastErr); err != nil {
+		o.log.Warn("failed to record outbox delivery failure",
+			logger.Uint64("item_id", uint64(item.ID)),
+			logger.String("destination", item.Destination),
+			logger.Error(err))
+		return false
+	}
+	o.log.Debug("outbox delivery failed, retry scheduled",
+		logger.Uint64("item_id", uint64(item.ID)),
+		logger.String("destination", item.Destination),
+		logger.Int("attempts", attempts),
+		logger.Time("next_attempt_at", next),
+		logger.Error(err))
+	return false
+}
+
+// deliver hands an item to the deliverer of its destination kind.
+func (o *Outbox) deliver(ctx context.Context, item *entities.OutboxItem) (err error) {
+	kind, target := splitDestination(item.Destination)
+	fn, ok := o.deliverer(kind)
+	if !ok {
+		return fmt.Errorf("no deliverer registered for %q", kind)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			o.log.Error("panic in outbox deliverer",
+				logger.String("destination", item.Destination),
+				logger.Any("panic", r),
+				logger.String("stack", string(debug.Stack())))
+			err = fmt.Errorf("deliverer panicked: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	return fn(ctx, target, item.Payload)
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (o *Outbox) backoff(attempts int) time.Duration {
+	delay := o.baseDelay
+	for i := 1; i < attempts && delay < o.maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, o.maxDelay)
+}
+
+// truncate shortens s to at most n bytes.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,215 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+// testClock is a settable time source.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestOutbox(t *testing.T, opts ...Option) (*Outbox, repository.OutboxRepository, *testClock) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "outbox.db")), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+	require.NoError(t, db.AutoMigrate(&entities.OutboxItem{}))
+
+	repo := repository.NewOutboxRepository(db, nil)
+	clock := &testClock{now: time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)}
+	opts = append([]Option{WithClock(clock.Now), WithRetryPolicy(3, time.Minute, 4*time.Minute)}, opts...)
+	return New(repo, opts...), repo, clock
+}
+
+// recorder is a deliverer that records payloads and fails while down is set.
+type recorder struct {
+	mu        sync.Mutex
+	delivered []string
+	down      bool
+	err       error
+}
+
+func (r *recorder) deliver(_ context.Context, target string, payload []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.down {
+		return r.err
+	}
+	r.delivered = append(r.delivered, target+"="+string(payload))
+	return nil
+}
+
+func (r *recorder) setDown(down bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.down = down
+	r.err = err
+}
+
+func (r *recorder) got() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.delivered...)
+}
+
+func TestOutbox_ReplaysInOrderAfterOutage(t *testing.T) {
+	t.Parallel()
+	box, repo, clock := newTestOutbox(t)
+	ctx := t.Context()
+
+	bw := &recorder{}
+	bw.setDown(true, errors.New("dial tcp: network is unreachable"))
+	box.Register(KindBirdWeather, bw.deliver)
+
+	for _, p := range []string{"1", "2", "3"} {
+		require.NoError(t, box.Enqueue(ctx, KindBirdWeather, "detection "+p, []byte(p)))
+	}
+
+	box.drain(ctx)
+	assert.Empty(t, bw.got())
+	items, err := repo.List(ctx, repository.OutboxFilter{})
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, 1, items[0].Attempts, "only the head is attempted")
+	assert.Zero(t, items[1].Attempts)
+	assert.Contains(t, items[0].LastError, "unreachable")
+
+	// Not due again before the backoff has passed.
+	bw.setDown(false, nil)
+	box.drain(ctx)
+	assert.Empty(t, bw.got())
+
+	clock.Advance(time.Minute)
+	box.drain(ctx)
+	assert.Equal(t, []string{"=1", "=2", "=3"}, bw.got())
+
+	items, err = repo.List(ctx, repository.OutboxFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, items, "delivered items are removed")
+}
+
+func TestOutbox_DeadLetters(t *testing.T) {
+	t.Parallel()
+	box, repo, clock := newTestOutbox(t)
+	ctx := t.Context()
+
+	hook := &recorder{}
+	hook.setDown(true, errors.New("status 503"))
+	box.Register(KindWebhook, hook.deliver)
+
+	dest := Destination(KindWebhook, "discord")
+	require.NoError(t, box.Enqueue(ctx, dest, "first", []byte("a")))
+	require.NoError(t, box.Enqueue(ctx, dest, "second", []byte("b")))
+
+	// Delays of 1, 2 minutes, then the third attempt exhausts the budget.
+	box.drain(ctx)
+	clock.Advance(time.Minute)
+	box.drain(ctx)
+	clock.Advance(2 * time.Minute)
+	hook.setDown(true, Permanent(errors.New("status 400")))
+	box.drain(ctx)
+
+	dead, err := repo.List(ctx, repository.OutboxFilter{Status: entities.OutboxStatusDead})
+	require.NoError(t, err)
+	require.Len(t, dead, 2, "the exhausted head and the permanently failing next item")
+	assert.Equal(t, 3, dead[0].Attempts)
+	assert.Equal(t, 1, dead[1].Attempts, "permanent failures are not retried")
+
+	// A retried dead item is delivered by the next drain.
+	hook.setDown(false, nil)
+	require.NoError(t, repo.Retry(ctx, dead[0].ID, clock.Now()))
+	box.drain(ctx)
+	assert.Equal(t, []string{"discord=a"}, hook.got())
+}
+
+func TestOutbox_DestinationsAreIndependent(t *testing.T) {
+	t.Parallel()
+	box, _, _ := newTestOutbox(t)
+	ctx := t.Context()
+
+	bw := &recorder{}
+	bw.setDown(true, errors.New("offline"))
+	mqtt := &recorder{}
+	box.Register(KindBirdWeather, bw.deliver)
+	box.Register(KindMQTT, mqtt.deliver)
+
+	require.NoError(t, box.Enqueue(ctx, KindBirdWeather, "", []byte("bw")))
+	require.NoError(t, box.Enqueue(ctx, KindMQTT, "", []byte("m1")))
+	require.NoError(t, box.Enqueue(ctx, KindMQTT, "", []byte("m2")))
+
+	box.drain(ctx)
+	assert.Equal(t, []string{"=m1", "=m2"}, mqtt.got())
+	assert.Empty(t, bw.got())
+}
+
+func TestOutbox_WorkerDeliversEnqueuedItems(t *testing.T) {
+	t.Parallel()
+	box, _, _ := newTestOutbox(t)
+
+	delivered := make(chan string, 1)
+	box.Register(KindMQTT, func(_ context.Context, _ string, payload []byte) error {
+		delivered <- string(payload)
+		return nil
+	})
+	box.Start(t.Context())
+	t.Cleanup(box.Stop)
+
+	require.NoError(t, box.Enqueue(t.Context(), KindMQTT, "", []byte("hello")))
+	select {
+	case got := <-delivered:
+		assert.Equal(t, "hello", got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("enqueued item was not delivered")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+	box := New(nil, WithRetryPolicy(10, 30*time.Second, 5*time.Minute))
+	assert.Equal(t, 30*time.Second, box.backoff(1))
+	assert.Equal(t, time.Minute, box.backoff(2))
+	assert.Equal(t, 4*time.Minute, box.backoff(4))
+	assert.Equal(t, 5*time.Minute, box.backoff(5))
+	assert.Equal(t, 5*time.Minute, box.backoff(50))
+}
+
+func TestDestination(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "mqtt", Destination(KindMQTT, ""))
+	assert.Equal(t, "webhook:discord", Destination(KindWebhook, "discord"))
+
+	kind, target := splitDestination("webhook:discord:alerts")
+	assert.Equal(t, KindWebhook, kind)
+	assert.Equal(t, "discord:alerts", target)
+}
@@ -184,11 +184,20 @@ func startOIDCRetry(ctx context.Context, providerConfig conf.OAuthProviderConfig
 type AuthCode struct {
 	Code      string
 	ExpiresAt time.Time
+	// Username and UserAccount carry the identity the code was issued to over
+	// to the access token it is exchanged for (see GenerateAuthCodeFor).
+	Username    string
+	UserAccount bool
 }
 
 type AccessToken struct {
 	Token     string
 	ExpiresAt time.Time
+	// Username is the user the token was issued to. When UserAccount is set it
+	// names a user-store account whose role is resolved through the
+	// UserDirectory on every use; otherwise the token grants admin access.
+	Username    string
+	UserAccount bool
 }
 
 // providerAuthConfig holds the configuration for validating a provider's auth session.
@@ -218,6 +227,10 @@ type OAuth2Server struct {
 
 	// Throttling
 	throttledMessages map[string]time.Time
+
+	// users resolves multi-user accounts; nil when the user store is not
+	// available, in which case only the single configured identity exists.
+	users UserDirectory
 }
 
 // currentSettings returns the latest settings snapshot so security
@@ -240,6 +253,9 @@ var testConfigPath string
 var (
 	ErrTokenNotFound = errors.NewStd("token not found")
 	ErrTokenExpired  = errors.NewStd("token expired")
+	// ErrAccountUnavailable is returned for a token whose user account was
+	// deleted or disabled after the token was issued.
+	ErrAccountUnavailable = errors.NewStd("user account unavailable")
 )
 
 // NewOAuth2Server creates and initializes an OAuth2Server. The supplied context
@@ -744,10 +760,17 @@ func (s *OAuth2Server) checkSocialAuthSessions(r *http.Request, log SecurityLogg
 // checkSocialAuthDirect checks for a valid session using the stored auth_provider key.
 // Returns false if the key is missing or the provider session is invalid.
 func (s *OAuth2Server) checkSocialAuthDirect(r *http.Request, userId string, log SecurityLogger) bool {
+	_, ok := s.socialIdentityDirect(r, userId, log)
+	return ok
+}
+
+// socialIdentityDirect resolves the identity of an OAuth session using the
+// stored auth_provider key.
+func (s *OAuth2Server) socialIdentityDirect(r *http.Request, userId string, log SecurityLogger) (Identity, bool) {
 	activeProvider, err := gothic.GetFromSession(SessionKeyAuthProvider, r)
 	if err != nil || activeProvider == "" {
 		log.Debug("No auth_provider key in session, will try fallback iteration")
-		return false
+		return Identity{}, false
 	}
 
 	log.Debug("Found active auth provider in session", logger.String("provider", activeProvider))
@@ -757,10 +780,10 @@ func (s *OAuth2Server) checkSocialAuthDirect(r *http.Request, userId string, log
 	provider := s.currentSettings().GetOAuthProvider(configProvider)
 	if provider == nil || !provider.Enabled {
 		log.Debug("Active auth provider not enabled or not found in config", logger.String("provider", activeProvider))
-		return false
+		return Identity{}, false
 	}
 
-	return s.checkProviderAuth(r, userId, log, providerAuthConfig{
+	return s.providerIdentity(r, userId, log, providerAuthConfig{
 		providerName:   activeProvider,
 		enabled:        provider.Enabled,
 		allowedUserIds: provider.UserID,
@@ -770,6 +793,13 @@ func (s *OAuth2Server) checkSocialAuthDirect(r *http.Request, userId string, log
 // checkSocialAuthFallback iterates over all configured providers to find a valid session.
 // This handles sessions created before the auth_provider key was introduced.
 func (s *OAuth2Server) checkSocialAuthFallback(r *http.Request, userId string, log SecurityLogger) bool {
+	_, ok := s.socialIdentityFallback(r, userId, log)
+	return ok
+}
+
+// socialIdentityFallback resolves the identity of an OAuth session by
+// iterating over all configured providers.
+func (s *OAuth2Server) socialIdentityFallback(r *http.Request, userId string, log SecurityLogger) (Identity, bool) {
 	settings := s.currentSettings()
 	for configProvider, gothProvider := range ConfigToGothProvider {
 		provider := settings.GetOAuthProvider(configProvider)
@@ -777,28 +807,36 @@ func (s *OAuth2Server) checkSocialAuthFallback(r *http.Request, userId string, l
 			continue
 		}
 
-		if s.checkProviderAuth(r, userId, log, providerAuthConfig{
+		if id, ok := s.providerIdentity(r, userId, log, providerAuthConfig{
 			providerName:   gothProvider,
 			enabled:        provider.Enabled,
 			allowedUserIds: provider.UserID,
-		}) {
-			return true
+		}); ok {
+			return id, true
 		}
 	}
 
-	return false
+	return Identity{}, false
 }
 
 // checkProviderAuth validates an OAuth provider session generically.
 // This is the shared implementation used by all OAuth provider auth checks.
 func (s *OAuth2Server) checkProviderAuth(r *http.Request, userId string, log SecurityLogger, cfg providerAuthConfig) bool {
+	_, ok := s.providerIdentity(r, userId, log, cfg)
+	return ok
+}
+
+// providerIdentity resolves the identity of an OAuth provider session. Users
+// on the provider allow-list are admins; other users are accepted when they are
+// linked to an enabled account in the user directory, with that account's role.
+func (s *OAuth2Server) providerIdentity(r *http.Request, userId string, log SecurityLogger, cfg providerAuthConfig) (Identity, bool) {
 	if !cfg.enabled {
-		return false
+		return Identity{}, false
 	}
 
 	sessionUser, err := gothic.GetFromSession(cfg.providerName, r)
 	if err != nil || sessionUser == "" {
-		return false
+		return Identity{}, false
 	}
 
 	log.Debug("Found provider session key", logger.String("provider", cfg.providerName))
@@ -807,10 +845,66 @@ func (s *OAuth2Server) checkProviderAuth(r *http.Request, userId string, log Sec
 	// the allowed user list using either format.
 	if isValidUserId(cfg.allowedUserIds, userId) || isValidUserId(cfg.allowedUserIds, sessionUser) {
 		log.Info("User authenticated: valid session found for allowed user ID", logger.String("provider", cfg.providerName))
-		return true
+		username := userId
+		if username == "" {
+			username = sessionUser
+		}
+		return Identity{Username: username, Role: RoleAdmin}, true
+	}
+	if users := s.userDirectory(); users != nil {
+		if id, ok := users.LookupExternal(r.Context(), userId, sessionUser); ok {
+			log.Info("User authenticated: provider session linked to user account",
+				logger.String("provider", cfg.providerName),
+				logger.String("role", string(id.Role)))
+			return id, true
+		}
 	}
 	log.Warn("Provider session found, but userId does not match allowed IDs", logger.String("provider", cfg.providerName), logger.String("allowed_ids", cfg.allowedUserIds))
-	return false
+	return Identity{}, false
+}
+
+// SetUserDirectory enables multi-user accounts backed by the given directory.
+// Passing nil disables them again.
+func (s *OAuth2Server) SetUserDirectory(users UserDirectory) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.users = users
+}
+
+// UserDirectory returns the configured user directory, or nil.
+func (s *OAuth2Server) UserDirectory() UserDirectory {
+	return s.userDirectory()
+}
+
+func (s *OAuth2Server) userDirectory() UserDirectory {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.users
+}
+
+// SessionIdentity returns the identity behind an authenticated browser
+// request: subnet bypass clients and basic-auth sessions for the configured
+// credentials are admins, user-account sessions carry the account's current
+// role and OAuth sessions are resolved as in IsUserAuthenticated.
+func (s *OAuth2Server) SessionIdentity(c echo.Context) (Identity, bool) {
+	settings := s.currentSettings()
+	if settings.Security.AllowSubnetBypass.Enabled && IsInLocalSubnet(parseIPWithZone(c.RealIP())) {
+		return Identity{Username: SubnetUsername, Role: RoleAdmin}, true
+	}
+
+	r := c.Request()
+	if token, err := gothic.GetFromSession("access_token", r); err == nil && token != "" {
+		if id, err := s.TokenIdentity(token); err == nil {
+			return id, true
+		}
+	}
+
+	log := GetLogger()
+	userId, _ := gothic.GetFromSession("userId", r)
+	if id, ok := s.socialIdentityDirect(r, userId, log); ok {
+		return id, true
+	}
+	return s.socialIdentityFallback(r, userId, log)
 }
 
 func isValidUserId(configuredIds, providedId string) bool {
@@ -844,8 +938,17 @@ func isValidUserId(configuredIds, providedId string) bool {
 	return false
 }
 
-// GenerateAuthCode generates a new authorization code
+// GenerateAuthCode generates a new authorization code for the configured
+// admin identity.
 func (s *OAuth2Server) GenerateAuthCode() (string, error) {
+	return s.GenerateAuthCodeFor("", false)
+}
+
+// GenerateAuthCodeFor generates a new authorization code bound to username.
+// When account is set, username names a user-store account and the resulting
+// access token is authorized with that account's role; otherwise the token
+// grants admin access like the single configured BasicAuth identity.
+func (s *OAuth2Server) GenerateAuthCodeFor(username string, account bool) (string, error) {
 	secLog := GetLogger()
 	secLog.Debug("Generating new authorization code")
 
@@ -862,8 +965,10 @@ func (s *OAuth2Server) GenerateAuthCode() (string, error) {
 
 	expiresAt := time.Now().Add(s.currentSettings().Security.BasicAuth.AuthCodeExp)
 	s.authCodes[authCode] = AuthCode{
-		Code:      authCode,
-		ExpiresAt: expiresAt,
+		Code:        authCode,
+		ExpiresAt:   expiresAt,
+		Username:    username,
+		UserAccount: account,
 	}
 	// Do not log the authCode itself
 	secLog.Info("Generated and stored new authorization code", logger.Time("expires_at", expiresAt))
@@ -917,8 +1022,10 @@ func (s *OAuth2Server) ExchangeAuthCode(ctx context.Context, code string) (strin
 	expiresAt := time.Now().Add(s.currentSettings().Security.BasicAuth.AccessTokenExp)
 
 	s.accessTokens[accessToken] = AccessToken{
-		Token:     accessToken,
-		ExpiresAt: expiresAt,
+		Token:       accessToken,
+		ExpiresAt:   expiresAt,
+		Username:    authCode.Username,
+		UserAccount: authCode.UserAccount,
 	}
 
 	// Invalidate the auth code after use
@@ -932,27 +1039,52 @@ func (s *OAuth2Server) ExchangeAuthCode(ctx context.Context, code string) (strin
 
 // ValidateAccessToken checks if an access token is valid and returns an error if not.
 func (s *OAuth2Server) ValidateAccessToken(token string) error {
+	_, err := s.TokenIdentity(token)
+	return err
+}
+
+// TokenIdentity validates an access token and returns the identity it was
+// issued to. Tokens bound to a user account are re-checked against the user
+// directory so deleting, disabling or demoting an account takes effect
+// immediately rather than when the token expires.
+func (s *OAuth2Server) TokenIdentity(token string) (Identity, error) {
 	// Do not log the token
 	secLog := GetLogger()
 	secLog.Debug("Validating access token")
 
 	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
 	accessToken, ok := s.accessTokens[token]
+	users := s.users
+	s.mutex.RUnlock()
+
 	if !ok {
 		secLog.Debug("Access token not found")
-		return ErrTokenNotFound // Return specific error
+		return Identity{}, ErrTokenNotFound // Return specific error
 	}
 
 	if time.Now().After(accessToken.ExpiresAt) {
 		secLog.Debug("Access token expired", logger.Time("expired_at", accessToken.ExpiresAt))
 		// No need to delete here, cleanup routine handles it
-		return ErrTokenExpired // Return specific error
+		return Identity{}, ErrTokenExpired // Return specific error
+	}
+
+	if !accessToken.UserAccount {
+		secLog.Debug("Access token is valid")
+		return Identity{Username: accessToken.Username, Role: RoleAdmin}, nil
+	}
+
+	if users == nil {
+		secLog.Debug("Access token belongs to a user account but no user directory is configured")
+		return Identity{}, ErrAccountUnavailable
+	}
+	id, ok := users.LookupUser(context.Background(), accessToken.Username)
+	if !ok {
+		secLog.Debug("Access token belongs to a missing or disabled user account")
+		return Identity{}, ErrAccountUnavailable
 	}
 
 	secLog.Debug("Access token is valid")
-	return nil // Return nil on success
+	return id, nil
 }
 
 // IsAuthenticationEnabled checks if any authentication method is enabled
@@ -0,0 +1,91 @@
+package security
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Role is the access level of a station user. Roles are ordered: every role
+// includes the permissions of the roles below it.
+type Role string
+
+const (
+	// RoleViewer can see the dashboard, detections and analytics.
+	RoleViewer Role = "viewer"
+	// RoleReviewer can additionally verify, comment on and lock detections.
+	RoleReviewer Role = "reviewer"
+	// RoleAdmin can additionally change settings, control the station and use
+	// the terminal. The configured BasicAuth credentials, OAuth user IDs from
+	// the provider allow-lists and subnet bypass clients are all admins.
+	RoleAdmin Role = "admin"
+)
+
+// ErrUnknownRole is returned by ParseRole for names outside the role set.
+var ErrUnknownRole = errors.NewStd("unknown role")
+
+// Roles lists all roles from least to most privileged.
+var Roles = []Role{RoleViewer, RoleReviewer, RoleAdmin}
+
+// rank returns the position of r in Roles, or -1 for an unknown role.
+func (r Role) rank() int {
+	switch r {
+	case RoleViewer:
+		return 0
+	case RoleReviewer:
+		return 1
+	case RoleAdmin:
+		return 2
+	default:
+		return -1
+	}
+}
+
+// Valid reports whether r is one of the defined roles.
+func (r Role) Valid() bool {
+	return r.rank() >= 0
+}
+
+// Allows reports whether r grants at least the permissions of required.
+// An unknown role allows nothing.
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && required.Valid() && r.rank() >= required.rank()
+}
+
+// ParseRole converts a case-insensitive role name to a Role.
+func ParseRole(s string) (Role, error) {
+	r := Role(strings.ToLower(strings.TrimSpace(s)))
+	if !r.Valid() {
+		return "", errors.Newf("%w: %q", ErrUnknownRole, s).
+			Component("security").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+	return r, nil
+}
+
+// Identity is the authenticated principal behind a request.
+type Identity struct {
+	Username string
+	Role     Role
+}
+
+// UserDirectory resolves station user accounts. It is implemented on top of
+// the user store so this package does not depend on the datastore.
+type UserDirectory interface {
+	// Authenticate verifies a username and password against the stored
+	// accounts. It returns ErrInvalidUserCredentials when the account does not
+	// exist, is disabled or the password does not match.
+	Authenticate(ctx context.Context, username, password string) (Identity, error)
+
+	// LookupUser returns the current identity of an enabled account.
+	LookupUser(ctx context.Context, username string) (Identity, bool)
+
+	// LookupExternal returns the enabled account linked to any of the given
+	// OAuth identities (e-mail address or provider subject).
+	LookupExternal(ctx context.Context, externalIDs ...string) (Identity, bool)
+}
+
+// ErrInvalidUserCredentials is returned by UserDirectory.Authenticate.
+var ErrInvalidUserCredentials = errors.NewStd("invalid user credentials")
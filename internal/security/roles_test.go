@@ -0,0 +1,89 @@
+package security
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestParseRole(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{"viewer", "Reviewer", " ADMIN "} {
+		role, err := ParseRole(in)
+		require.NoError(t, err, in)
+		assert.True(t, role.Valid())
+	}
+
+	_, err := ParseRole("owner")
+	require.ErrorIs(t, err, ErrUnknownRole)
+}
+
+func TestRoleAllows(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, RoleAdmin.Allows(RoleReviewer))
+	assert.True(t, RoleReviewer.Allows(RoleReviewer))
+	assert.True(t, RoleReviewer.Allows(RoleViewer))
+	assert.False(t, RoleViewer.Allows(RoleReviewer))
+	assert.False(t, RoleReviewer.Allows(RoleAdmin))
+	assert.False(t, Role("").Allows(RoleViewer), "empty role must allow nothing")
+	assert.False(t, RoleAdmin.Allows(Role("owner")), "unknown required role must fail closed")
+}
+
+// fakeDirectory is a static UserDirectory for token identity tests.
+type fakeDirectory map[string]Identity
+
+func (d fakeDirectory) Authenticate(_ context.Context, username, _ string) (Identity, error) {
+	if id, ok := d[username]; ok {
+		return id, nil
+	}
+	return Identity{}, ErrInvalidUserCredentials
+}
+
+func (d fakeDirectory) LookupUser(_ context.Context, username string) (Identity, bool) {
+	id, ok := d[username]
+	return id, ok
+}
+
+func (d fakeDirectory) LookupExternal(context.Context, ...string) (Identity, bool) {
+	return Identity{}, false
+}
+
+func TestTokenIdentity(t *testing.T) {
+	settings := &conf.Settings{}
+	settings.Security.BasicAuth.Enabled = true
+	settings.Security.BasicAuth.AuthCodeExp = 10 * time.Minute
+	settings.Security.BasicAuth.AccessTokenExp = time.Hour
+	s := newOAuth2ServerForTesting(t, settings)
+	users := fakeDirectory{"anna": {Username: "anna", Role: RoleReviewer}}
+	s.SetUserDirectory(users)
+
+	issue := func(username string, account bool) string {
+		t.Helper()
+		code, err := s.GenerateAuthCodeFor(username, account)
+		require.NoError(t, err)
+		token, err := s.ExchangeAuthCode(t.Context(), code)
+		require.NoError(t, err)
+		return token
+	}
+
+	admin, err := s.TokenIdentity(issue("admin", false))
+	require.NoError(t, err)
+	assert.Equal(t, Identity{Username: "admin", Role: RoleAdmin}, admin)
+
+	annaToken := issue("anna", true)
+	anna, err := s.TokenIdentity(annaToken)
+	require.NoError(t, err)
+	assert.Equal(t, RoleReviewer, anna.Role)
+
+	// Removing the account revokes its outstanding tokens.
+	delete(users, "anna")
+	_, err = s.TokenIdentity(annaToken)
+	require.ErrorIs(t, err, ErrAccountUnavailable)
+	require.Error(t, s.ValidateAccessToken(annaToken))
+}
@@ -0,0 +1,89 @@
+package security
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Scope limits what an API key may do. Unlike roles, scopes are not ordered:
+// a key holds exactly the scopes it was created with.
+type Scope string
+
+const (
+	// ScopeDetectionsRead allows reading detections, analytics and the other
+	// endpoints open to the viewer role.
+	ScopeDetectionsRead Scope = "detections:read"
+	// ScopeReviewsWrite allows reviewing, commenting on and locking
+	// detections, like the reviewer role.
+	ScopeReviewsWrite Scope = "reviews:write"
+	// ScopeControl allows the station control endpoints (restart analysis,
+	// reload the model and similar).
+	ScopeControl Scope = "control"
+	// ScopeSettings allows reading and changing the station settings.
+	ScopeSettings Scope = "settings"
+)
+
+// Scopes lists all scopes.
+var Scopes = []Scope{ScopeDetectionsRead, ScopeReviewsWrite, ScopeControl, ScopeSettings}
+
+// ErrUnknownScope is returned by ParseScopes for names outside the scope set.
+var ErrUnknownScope = errors.NewStd("unknown scope")
+
+// Valid reports whether s is one of the defined scopes.
+func (s Scope) Valid() bool {
+	return slices.Contains(Scopes, s)
+}
+
+// Role returns the role a signed-in user needs for the endpoints guarded by s.
+func (s Scope) Role() Role {
+	switch s {
+	case ScopeDetectionsRead:
+		return RoleViewer
+	case ScopeReviewsWrite:
+		return RoleReviewer
+	default:
+		return RoleAdmin
+	}
+}
+
+// ScopeForRole returns the scope an API key needs for endpoints that require
+// role. Admin endpoints without an explicit scope are not reachable with an
+// API key, so an empty scope is returned for RoleAdmin.
+func ScopeForRole(role Role) Scope {
+	switch role {
+	case RoleViewer:
+		return ScopeDetectionsRead
+	case RoleReviewer:
+		return ScopeReviewsWrite
+	default:
+		return ""
+	}
+}
+
+// ParseScopes converts case-insensitive scope names to a sorted, de-duplicated
+// scope list. At least one scope is required.
+func ParseScopes(names []string) ([]Scope, error) {
+	scopes := make([]Scope, 0, len(names))
+	for _, name := range names {
+		s := Scope(strings.ToLower(strings.TrimSpace(name)))
+		if !s.Valid() {
+			return nil, errors.Newf("%w: %q", ErrUnknownScope, name).
+				Component("security").
+				Category(errors.CategoryValidation).
+				Build()
+		}
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, errors.Newf("%w: at least one scope is required", ErrUnknownScope).
+			Component("security").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+	slices.Sort(scopes)
+	return scopes, nil
+}
@@ -0,0 +1,35 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScopes(t *testing.T) {
+	t.Parallel()
+
+	scopes, err := ParseScopes([]string{" Settings", "detections:read", "settings"})
+	require.NoError(t, err)
+	assert.Equal(t, []Scope{ScopeDetectionsRead, ScopeSettings}, scopes, "scopes are normalized, sorted and de-duplicated")
+
+	_, err = ParseScopes([]string{"detections:read", "admin"})
+	require.ErrorIs(t, err, ErrUnknownScope)
+
+	_, err = ParseScopes(nil)
+	require.ErrorIs(t, err, ErrUnknownScope)
+}
+
+func TestScopeRoles(t *testing.T) {
+	t.Parallel()
+
+	for _, scope := range Scopes {
+		if want := ScopeForRole(scope.Role()); want != "" {
+			assert.Equal(t, scope, want, "ScopeForRole must invert Role for %s", scope)
+		}
+	}
+	assert.Equal(t, RoleAdmin, ScopeControl.Role())
+	assert.Equal(t, RoleAdmin, ScopeSettings.Role())
+	assert.Empty(t, ScopeForRole(RoleAdmin), "plain admin routes are closed to API keys")
+}
@@ -0,0 +1,263 @@
+// Package similarity computes embedding vectors for saved detection clips so
+// reviewers can look up past recordings that sound like a given detection.
+//
+// An Indexer receives the PCM of every clip the processor saves, takes the
+// three second window the detection was made on, runs it through the BirdNET
+// v2.4 embeddings model and stores the vector in the datastore, whose
+// EmbeddingRepository answers the nearest neighbour queries. Only clips saved
+// while realtime.audio.export.embeddings is enabled get a vector; existing
+// clips are not backfilled.
+package similarity
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/audiocore/resample"
+	"github.com/tphakala/birdnet-go/internal/classifier"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// queueSize bounds the clips waiting for an embedding. Embedding a clip
+	// takes tens of milliseconds, so a full queue means the model failed to
+	// keep up and further clips are dropped rather than buffered.
+	queueSize = 100
+	// saveTimeout bounds the datastore write of one vector.
+	saveTimeout = 10 * time.Second
+	// bytesPerSample is the size of one mono 16-bit PCM sample.
+	bytesPerSample = 2
+)
+
+// ErrQueueFull is returned by Submit when the indexer is behind.
+var ErrQueueFull = errors.Newf("embedding queue full").
+	Component("similarity").
+	Category(errors.CategoryLimit).
+	Build()
+
+// Store persists embedding vectors. It is satisfied by
+// repository.EmbeddingRepository.
+type Store interface {
+	Save(ctx context.Context, detectionID uint, model string, vector []float32) error
+}
+
+// Embedder computes the embedding of one 48 kHz window. It is satisfied by
+// *classifier.Embedder.
+type Embedder interface {
+	Embed(samples []float32) ([]float32, error)
+	ModelID() string
+	Close()
+}
+
+// Config wires an Indexer to its model and store.
+type Config struct {
+	Store Store
+	// ModelPath resolves the embeddings model for the current settings;
+	// empty means none is available.
+	ModelPath func(settings *conf.Settings) string
+	// Load creates an Embedder for a model path.
+	Load func(settings *conf.Settings, modelPath string) (Embedder, error)
+}
+
+// job is one saved clip waiting for its embedding.
+type job struct {
+	detectionID uint
+	pcm         []byte
+	sampleRate  int
+	offset      time.Duration
+}
+
+// Indexer computes and stores clip embeddings on a background worker. The
+// model is loaded on the first clip after the option is enabled and
+// reloaded when the model path changes, so enabling the option needs no
+// restart.
+type Indexer struct {
+	cfg  Config
+	log  logger.Logger
+	jobs chan job
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// Worker state, only touched by the worker goroutine.
+	embedder      Embedder
+	modelPath     string
+	failedPath    string // model path that last failed to load, to avoid retrying per clip
+	warnedNoModel bool   // the missing model warning was logged
+}
+
+// NewIndexer creates an Indexer. Call Start to begin processing.
+func NewIndexer(parentCtx context.Context, cfg Config) *Indexer {
+	ctx, cancel := context.WithCancel(parentCtx)
+	return &Indexer{
+		cfg:    cfg,
+		log:    logger.Global().Module("similarity"),
+		jobs:   make(chan job, queueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start launches the worker.
+func (ix *Indexer) Start() {
+	ix.wg.Go(ix.run)
+}
+
+// Stop discards queued clips, waits for the worker and releases the model.
+func (ix *Indexer) Stop() {
+	ix.cancel()
+	ix.wg.Wait()
+}
+
+// Submit queues a saved clip for embedding. pcm is s16le mono at
+// sampleRate and offset is where the detection window starts in the clip.
+// It never blocks; ErrQueueFull is returned when the worker is behind.
+func (ix *Indexer) Submit(detectionID uint, pcm []byte, sampleRate int, offset time.Duration) error {
+	if detectionID == 0 || len(pcm) == 0 || sampleRate <= 0 {
+		return errors.Newf("invalid embedding job").
+			Component("similarity").
+			Category(errors.CategoryValidation).
+			Context("detection_id", detectionID).
+			Context("sample_rate", sampleRate).
+			Build()
+	}
+	if ix.ctx.Err() != nil {
+		return errors.New(ix.ctx.Err()).
+			Component("similarity").
+			Category(errors.CategorySystem).
+			Context("operation", "submit_embedding").
+			Build()
+	}
+	select {
+	case ix.jobs <- job{detectionID: detectionID, pcm: pcm, sampleRate: sampleRate, offset: offset}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// run processes queued clips until the indexer is stopped.
+func (ix *Indexer) run() {
+	defer ix.closeEmbedder()
+	for {
+		select {
+		case <-ix.ctx.Done():
+			return
+		case j := <-ix.jobs:
+			ix.process(&j)
+		}
+	}
+}
+
+// process embeds and stores one clip. Failures are logged; a clip without
+// an embedding is simply missing from search results.
+func (ix *Indexer) process(j *job) {
+	settings := conf.Setting()
+	if !settings.Realtime.Audio.Export.Embeddings.Enabled {
+		ix.closeEmbedder()
+		return
+	}
+	embedder := ix.embedderFor(settings)
+	if embedder == nil {
+		return
+	}
+
+	window, err := detectionWindow(j.pcm, j.sampleRate, j.offset)
+	if err != nil {
+		ix.log.Warn("Failed to prepare clip audio for embedding",
+			logger.Any("detection_id", j.detectionID),
+			logger.Int("sample_rate", j.sampleRate),
+			logger.Error(err))
+		return
+	}
+	vector, err := embedder.Embed(window)
+	if err != nil {
+		ix.log.Warn("Failed to compute clip embedding",
+			logger.Any("detection_id", j.detectionID),
+			logger.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ix.ctx, saveTimeout)
+	defer cancel()
+	if err := ix.cfg.Store.Save(ctx, j.detectionID, embedder.ModelID(), vector); err != nil {
+		ix.log.Warn("Failed to store clip embedding",
+			logger.Any("detection_id", j.detectionID),
+			logger.Error(err))
+		return
+	}
+	ix.log.Debug("Stored clip embedding",
+		logger.Any("detection_id", j.detectionID),
+		logger.String("model", embedder.ModelID()),
+		logger.Int("dim", len(vector)))
+}
+
+// embedderFor returns the embedder for the current model path, loading it
+// when the path changed. It returns nil when no model is available.
+func (ix *Indexer) embedderFor(settings *conf.Settings) Embedder {
+	path := ix.cfg.ModelPath(settings)
+	if ix.embedder != nil && path == ix.modelPath {
+		return ix.embedder
+	}
+	ix.closeEmbedder()
+	if path == "" {
+		if !ix.warnedNoModel {
+			ix.warnedNoModel = true
+			ix.log.Warn("Clip embeddings enabled but no embeddings model is installed or configured")
+		}
+		return nil
+	}
+	if path == ix.failedPath {
+		return nil
+	}
+
+	embedder, err := ix.cfg.Load(settings, path)
+	if err != nil {
+		ix.failedPath = path
+		ix.log.Error("Failed to load clip embeddings model",
+			logger.String("model_path", path),
+			logger.Error(err))
+		return nil
+	}
+	ix.embedder = embedder
+	ix.modelPath = path
+	ix.failedPath = ""
+	ix.warnedNoModel = false
+	return embedder
+}
+
+// closeEmbedder releases the loaded model, if any.
+func (ix *Indexer) closeEmbedder() {
+	if ix.embedder != nil {
+		ix.embedder.Close()
+		ix.embedder = nil
+		ix.modelPath = ""
+	}
+}
+
+// detectionWindow cuts the embedding window starting at offset out of a
+// s16le mono clip, resampled to the embeddings model rate and scaled to
+// [-1, 1). A window running past the end of the clip is moved back so it
+// stays inside; a clip shorter than the window is used whole.
+func detectionWindow(pcm []byte, sampleRate int, offset time.Duration) ([]float32, error) {
+	windowBytes := classifier.EmbedderWindowSamples * sampleRate / classifier.EmbedderSampleRate * bytesPerSample
+	start := int(offset.Seconds()*float64(sampleRate)) * bytesPerSample
+	start = max(0, min(start, len(pcm)-windowBytes))
+	end := min(len(pcm), start+windowBytes)
+	segment := pcm[start : end-(end-start)%bytesPerSample]
+
+	segment, err := resample.ResampleBytes(segment, sampleRate, classifier.EmbedderSampleRate)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]float32, len(segment)/bytesPerSample)
+	for i := range samples {
+		samples[i] = float32(int16(binary.LittleEndian.Uint16(segment[i*bytesPerSample:]))) / 32768.0
+	}
+	return samples, nil
+}
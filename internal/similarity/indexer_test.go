@@ -0,0 +1,145 @@
+package similarity
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/classifier"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/conf/conftest"
+)
+
+// fakeEmbedder returns the first sample of each window as a one-dimensional
+// embedding so tests can check which window was embedded.
+type fakeEmbedder struct {
+	closed bool
+}
+
+func (e *fakeEmbedder) Embed(samples []float32) ([]float32, error) {
+	return []float32{samples[0], float32(len(samples))}, nil
+}
+func (e *fakeEmbedder) ModelID() string { return "fake" }
+func (e *fakeEmbedder) Close()          { e.closed = true }
+
+type savedEmbedding struct {
+	model  string
+	vector []float32
+}
+
+type fakeStore struct {
+	mu    sync.Mutex
+	saved map[uint]savedEmbedding
+}
+
+func (s *fakeStore) Save(_ context.Context, detectionID uint, model string, vector []float32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved[detectionID] = savedEmbedding{model: model, vector: vector}
+	return nil
+}
+
+func (s *fakeStore) get(id uint) (savedEmbedding, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.saved[id]
+	return e, ok
+}
+
+// rampPCM returns s16le mono PCM whose sample i holds the second it falls in,
+// scaled so the value survives conversion to float32.
+func rampPCM(seconds, sampleRate int) []byte {
+	pcm := make([]byte, seconds*sampleRate*bytesPerSample)
+	for i := range seconds * sampleRate {
+		binary.LittleEndian.PutUint16(pcm[i*bytesPerSample:], uint16(int16((i/sampleRate)*1000)))
+	}
+	return pcm
+}
+
+func TestDetectionWindow(t *testing.T) {
+	t.Parallel()
+	const rate = classifier.EmbedderSampleRate
+	pcm := rampPCM(10, rate)
+
+	tests := []struct {
+		name        string
+		offset      time.Duration
+		wantFirstAt int // second the window starts at
+	}{
+		{name: "window at pre-capture offset", offset: 3 * time.Second, wantFirstAt: 3},
+		{name: "window past the end is moved back", offset: 9 * time.Second, wantFirstAt: 7},
+		{name: "zero offset", offset: 0, wantFirstAt: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			window, err := detectionWindow(pcm, rate, tt.offset)
+			require.NoError(t, err)
+			assert.Len(t, window, classifier.EmbedderWindowSamples)
+			assert.InDelta(t, float32(tt.wantFirstAt*1000)/32768, window[0], 1e-6)
+		})
+	}
+
+	t.Run("short clip is used whole", func(t *testing.T) {
+		t.Parallel()
+		window, err := detectionWindow(rampPCM(1, rate), rate, 3*time.Second)
+		require.NoError(t, err)
+		assert.Len(t, window, rate)
+	})
+
+	t.Run("clip is resampled to the model rate", func(t *testing.T) {
+		t.Parallel()
+		window, err := detectionWindow(rampPCM(5, 24000), 24000, 0)
+		require.NoError(t, err)
+		// The resampler's filter delay trims a few milliseconds off the end;
+		// Embed pads the window back to full length.
+		assert.InDelta(t, classifier.EmbedderWindowSamples, len(window), classifier.EmbedderSampleRate/100)
+	})
+}
+
+func TestIndexer_StoresEmbeddingsWhenEnabled(t *testing.T) {
+	orig := conf.GetSettings()
+	t.Cleanup(func() { conftest.SetTestSettings(orig) })
+
+	settings := conftest.GetTestSettings()
+	settings.Realtime.Audio.Export.Embeddings.Enabled = true
+	conftest.SetTestSettings(settings)
+
+	store := &fakeStore{saved: make(map[uint]savedEmbedding)}
+	embedder := &fakeEmbedder{}
+	loads := 0
+	ix := NewIndexer(t.Context(), Config{
+		Store:     store,
+		ModelPath: func(*conf.Settings) string { return "/models/embeddings.onnx" },
+		Load: func(_ *conf.Settings, _ string) (Embedder, error) {
+			loads++
+			return embedder, nil
+		},
+	})
+	ix.Start()
+
+	pcm := rampPCM(6, classifier.EmbedderSampleRate)
+	require.NoError(t, ix.Submit(1, pcm, classifier.EmbedderSampleRate, 3*time.Second))
+	require.NoError(t, ix.Submit(2, pcm, classifier.EmbedderSampleRate, 0))
+	require.Error(t, ix.Submit(0, pcm, classifier.EmbedderSampleRate, 0), "detection ID is required")
+
+	require.Eventually(t, func() bool {
+		_, ok := store.get(2)
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	got, ok := store.get(1)
+	require.True(t, ok)
+	assert.Equal(t, "fake", got.model)
+	assert.InDelta(t, float32(3000)/32768, got.vector[0], 1e-6)
+	assert.Equal(t, 1, loads, "the model is loaded once and reused")
+
+	ix.Stop()
+	assert.True(t, embedder.closed, "stopping releases the model")
+	require.Error(t, ix.Submit(3, pcm, classifier.EmbedderSampleRate, 0), "stopped indexer rejects clips")
+}
@@ -0,0 +1,343 @@
+// Package soundscape records continuous soundscapes: consecutive FLAC or Opus
+// segments of everything an audio source captures, independent of detections.
+//
+// A Recorder is an audiocore.AudioConsumer routed from one source. It collects
+// PCM in memory until the segment is complete and hands it to a background
+// writer that encodes it with the native audiocore/flac or audiocore/opus
+// encoder and indexes the file in the datastore. Segments end on wall-clock
+// boundaries of the configured length (e.g. every five minutes on the five
+// minute mark) so segments of different sources line up, and a gap in the
+// audio, such as a stream reconnect, starts a new segment.
+//
+// Files are laid out as <path>/<source id>/<yyyy>/<mm>/<dd>/<source id>_
+// <start>.<format>, with the start time in UTC. Retention is handled by
+// diskmanager.SoundscapeCleanup through the same index.
+package soundscape
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/audiocore"
+	"github.com/tphakala/birdnet-go/internal/audiocore/flac"
+	"github.com/tphakala/birdnet-go/internal/audiocore/opus"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// consumerPrefix prefixes the router consumer ID of a recorder.
+	consumerPrefix = "soundscape_"
+	// bitDepth and channels are the PCM shape the recorder consumes.
+	bitDepth = 16
+	channels = 1
+	// bytesPerSample is the size of one mono 16-bit sample.
+	bytesPerSample = bitDepth / 8 * channels
+	// gapTolerance is how far a frame timestamp may drift from the time the
+	// recorded samples account for before the recorder assumes audio was
+	// lost and starts a new segment.
+	gapTolerance = 2 * time.Second
+	// minSegmentDuration drops shorter tails, e.g. when a source stops right
+	// after a segment boundary.
+	minSegmentDuration = time.Second
+	// pendingSegments is how many completed segments may wait for the writer.
+	// A writer that falls further behind drops segments rather than blocking
+	// the audio router.
+	pendingSegments = 2
+	// indexTimeout bounds indexing a written segment.
+	indexTimeout = 10 * time.Second
+)
+
+// Index stores the segments a Recorder writes.
+type Index interface {
+	Create(ctx context.Context, segment *entities.SoundscapeSegment) error
+}
+
+// ConsumerID returns the router consumer ID of the recorder for a source.
+func ConsumerID(sourceID string) string {
+	return consumerPrefix + sourceID
+}
+
+// segment is a completed or in-progress span of PCM.
+type segment struct {
+	start time.Time
+	pcm   []byte
+	limit int // bytes at which the segment is complete
+	cfg   conf.SoundscapeSettings
+}
+
+// Recorder writes the audio of one source as consecutive segments.
+type Recorder struct {
+	sourceID   string
+	sourceName string
+	sampleRate int
+	index      Index
+	log        logger.Logger
+
+	// settings returns the current soundscape settings; read at the start of
+	// each segment so changes apply without rebuilding the route.
+	settings func() conf.SoundscapeSettings
+	// now is the fallback time source for frames without a timestamp.
+	now func() time.Time
+
+	mu      sync.Mutex
+	current *segment
+	closed  bool
+
+	queue chan *segment
+	done  chan struct{}
+}
+
+// NewRecorder creates a recorder for a source and starts its writer. The
+// recorder consumes PCM at sampleRate, the source's own rate, so the router
+// does not resample. index may be nil to write segments without indexing
+// them.
+func NewRecorder(sourceID, sourceName string, sampleRate int, index Index) *Recorder {
+	r := &Recorder{
+		sourceID:   sourceID,
+		sourceName: sourceName,
+		sampleRate: sampleRate,
+		index:      index,
+		log:        logger.Global().Module("soundscape"),
+		settings: func() conf.SoundscapeSettings {
+			return conf.Setting().Realtime.Audio.Soundscape
+		},
+		now:   time.Now,
+		queue: make(chan *segment, pendingSegments),
+		done:  make(chan struct{}),
+	}
+	go r.writer()
+	return r
+}
+
+// ID implements audiocore.AudioConsumer.
+func (r *Recorder) ID() string { return ConsumerID(r.sourceID) }
+
+// SampleRate implements audiocore.AudioConsumer.
+func (r *Recorder) SampleRate() int { return r.sampleRate }
+
+// BitDepth implements audiocore.AudioConsumer.
+func (r *Recorder) BitDepth() int { return bitDepth }
+
+// Channels implements audiocore.AudioConsumer.
+func (r *Recorder) Channels() int { return channels }
+
+// Write appends a frame to the current segment, completing segments at their
+// boundaries. The frame data is copied.
+func (r *Recorder) Write(frame audiocore.AudioFrame) error { //nolint:gocritic // hugeParam: signature required by AudioConsumer interface
+	if len(frame.Data) == 0 {
+		return nil
+	}
+	ts := frame.Timestamp
+	if ts.IsZero() {
+		ts = r.now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+
+	if r.current != nil {
+		drift := ts.Sub(r.current.start.Add(r.duration(len(r.current.pcm))))
+		if drift > gapTolerance || drift < -gapTolerance {
+			r.log.Debug("audio gap, starting a new soundscape segment",
+				logger.String("source_id", r.sourceID),
+				logger.Duration("drift", drift))
+			r.flush()
+		}
+	}
+
+	data := frame.Data
+	for len(data) > 0 {
+		if r.current == nil {
+			r.begin(ts)
+		}
+		n := min(r.current.limit-len(r.current.pcm), len(data))
+		r.current.pcm = append(r.current.pcm, data[:n]...)
+		data = data[n:]
+		ts = ts.Add(r.duration(n))
+		if len(r.current.pcm) >= r.current.limit {
+			r.flush()
+		}
+	}
+	return nil
+}
+
+// Close writes the partial segment and waits for the writer to finish.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	r.flush()
+	r.mu.Unlock()
+
+	close(r.queue)
+	<-r.done
+	return nil
+}
+
+// begin starts a segment at ts that ends on the next boundary of the
+// configured segment length.
+func (r *Recorder) begin(ts time.Time) {
+	cfg := r.settings()
+	if cfg.SegmentLength < conf.MinSoundscapeSegmentLength || cfg.SegmentLength > conf.MaxSoundscapeSegmentLength {
+		cfg.SegmentLength = conf.DefaultSoundscapeSegmentLength
+	}
+	length := time.Duration(cfg.SegmentLength) * time.Second
+	end := ts.Truncate(length).Add(length)
+	if end.Sub(ts) < minSegmentDuration {
+		end = end.Add(length)
+	}
+	samples := int(end.Sub(ts).Seconds() * float64(r.sampleRate))
+	limit := samples * bytesPerSample
+	r.current = &segment{
+		start: ts,
+		pcm:   make([]byte, 0, limit),
+		limit: limit,
+		cfg:   cfg,
+	}
+}
+
+// flush hands the current segment to the writer. Must be called with mu held.
+func (r *Recorder) flush() {
+	seg := r.current
+	r.current = nil
+	if seg == nil || r.duration(len(seg.pcm)) < minSegmentDuration {
+		return
+	}
+	select {
+	case r.queue <- seg:
+	default:
+		r.log.Warn("soundscape writer is falling behind, dropping segment",
+			logger.String("source_id", r.sourceID),
+			logger.Time("segment_start", seg.start))
+	}
+}
+
+// duration returns the playing time of n bytes of PCM.
+func (r *Recorder) duration(n int) time.Duration {
+	return time.Duration(n/bytesPerSample) * time.Second / time.Duration(r.sampleRate)
+}
+
+// writer encodes and indexes completed segments until the queue is closed.
+func (r *Recorder) writer() {
+	defer close(r.done)
+	for seg := range r.queue {
+		if err := r.write(seg); err != nil {
+			r.log.Error("failed to write soundscape segment",
+				logger.String("source_id", r.sourceID),
+				logger.Time("segment_start", seg.start),
+				logger.Error(err))
+		}
+	}
+}
+
+// write encodes a segment to disk and indexes it.
+func (r *Recorder) write(seg *segment) error {
+	format := seg.cfg.Format
+	if format == conf.SoundscapeFormatOpus {
+		if err := opus.Supports(r.sampleRate, bitDepth, channels); err != nil {
+			r.log.Debug("Opus cannot encode this source, writing FLAC",
+				logger.String("source_id", r.sourceID),
+				logger.Int("sample_rate", r.sampleRate))
+			format = conf.SoundscapeFormatFLAC
+		}
+	}
+	if format != conf.SoundscapeFormatOpus {
+		format = conf.SoundscapeFormatFLAC
+	}
+
+	rel := SegmentPath(r.sourceID, seg.start, format)
+	path := filepath.Join(seg.cfg.Path, rel)
+	ctx := context.Background()
+
+	var err error
+	if format == conf.SoundscapeFormatOpus {
+		err = opus.EncodePCM(ctx, &opus.Options{
+			PCMData:     seg.pcm,
+			OutputPath:  path,
+			SampleRate:  r.sampleRate,
+			Channels:    channels,
+			BitDepth:    bitDepth,
+			BitrateKbps: seg.cfg.Bitrate,
+		})
+	} else {
+		err = flac.EncodePCM(ctx, &flac.Options{
+			PCMData:    seg.pcm,
+			OutputPath: path,
+			SampleRate: r.sampleRate,
+			Channels:   channels,
+			BitDepth:   bitDepth,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	duration := r.duration(len(seg.pcm))
+	r.log.Debug("soundscape segment written",
+		logger.String("source_id", r.sourceID),
+		logger.String("path", path),
+		logger.Duration("duration", duration),
+		logger.Int64("size", info.Size()))
+
+	if r.index == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, indexTimeout)
+	defer cancel()
+	if err := r.index.Create(ctx, &entities.SoundscapeSegment{
+		SourceID:   r.sourceID,
+		SourceName: r.sourceName,
+		StartTime:  seg.start.UTC(),
+		Duration:   duration.Seconds(),
+		FilePath:   filepath.ToSlash(rel),
+		Format:     format,
+		SampleRate: r.sampleRate,
+		FileSize:   info.Size(),
+	}); err != nil {
+		return fmt.Errorf("index segment %s: %w", rel, err)
+	}
+	return nil
+}
+
+// SegmentPath returns the path of a segment relative to the soundscape
+// directory.
+func SegmentPath(sourceID string, start time.Time, format string) string {
+	dir := sanitize(sourceID)
+	start = start.UTC()
+	return filepath.Join(dir, start.Format("2006"), start.Format("01"), start.Format("02"),
+		fmt.Sprintf("%s_%s.%s", dir, start.Format("20060102T150405Z"), format))
+}
+
+// sanitize keeps letters, digits, dashes and underscores of a source ID so
+// it is safe as a path component.
+func sanitize(sourceID string) string {
+	s := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, sourceID)
+	if s == "" {
+		return "source"
+	}
+	return s
+}
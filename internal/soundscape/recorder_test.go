@@ -0,0 +1,128 @@
+package soundscape
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/audiocore"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+const testSampleRate = 8000
+
+type memIndex struct {
+	mu       sync.Mutex
+	segments []*entities.SoundscapeSegment
+}
+
+func (m *memIndex) Create(_ context.Context, segment *entities.SoundscapeSegment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.segments = append(m.segments, segment)
+	return nil
+}
+
+func newTestRecorder(t *testing.T, index Index) (*Recorder, string) {
+	t.Helper()
+	dir := t.TempDir()
+	r := NewRecorder("rtsp_abc123", "Garden", testSampleRate, index)
+	r.settings = func() conf.SoundscapeSettings {
+		return conf.SoundscapeSettings{
+			Path:          dir,
+			Format:        conf.SoundscapeFormatFLAC,
+			SegmentLength: 60,
+		}
+	}
+	return r, dir
+}
+
+// feed writes d of silence in one-second frames starting at start.
+func feed(t *testing.T, r *Recorder, start time.Time, d time.Duration) {
+	t.Helper()
+	frame := make([]byte, testSampleRate*bytesPerSample)
+	for off := time.Duration(0); off < d; off += time.Second {
+		require.NoError(t, r.Write(audiocore.AudioFrame{
+			SourceID:   r.sourceID,
+			Data:       frame,
+			SampleRate: testSampleRate,
+			BitDepth:   bitDepth,
+			Channels:   channels,
+			Timestamp:  start.Add(off),
+		}))
+	}
+}
+
+func TestRecorderSplitsOnWallClockBoundaries(t *testing.T) {
+	t.Parallel()
+	index := &memIndex{}
+	r, dir := newTestRecorder(t, index)
+
+	// Start 30 s before a minute boundary and record 90 s: the first segment
+	// ends on the boundary, the second is the partial tail written on Close.
+	start := time.Date(2026, 5, 1, 6, 0, 30, 0, time.UTC)
+	feed(t, r, start, 90*time.Second)
+	require.NoError(t, r.Close())
+
+	require.Len(t, index.segments, 2)
+	first, second := index.segments[0], index.segments[1]
+	assert.Equal(t, start, first.StartTime)
+	assert.InDelta(t, 30, first.Duration, 0.001)
+	assert.Equal(t, time.Date(2026, 5, 1, 6, 1, 0, 0, time.UTC), second.StartTime)
+	assert.InDelta(t, 60, second.Duration, 0.001)
+
+	assert.Equal(t, "rtsp_abc123/2026/05/01/rtsp_abc123_20260501T060030Z.flac", first.FilePath)
+	for _, seg := range index.segments {
+		assert.Equal(t, "Garden", seg.SourceName)
+		assert.Equal(t, conf.SoundscapeFormatFLAC, seg.Format)
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(seg.FilePath)))
+		require.NoError(t, err)
+		assert.Equal(t, info.Size(), seg.FileSize)
+	}
+}
+
+func TestRecorderStartsNewSegmentAfterGap(t *testing.T) {
+	t.Parallel()
+	index := &memIndex{}
+	r, _ := newTestRecorder(t, index)
+
+	start := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	feed(t, r, start, 10*time.Second)
+	feed(t, r, start.Add(20*time.Second), 10*time.Second)
+	require.NoError(t, r.Close())
+
+	require.Len(t, index.segments, 2)
+	assert.Equal(t, start, index.segments[0].StartTime)
+	assert.InDelta(t, 10, index.segments[0].Duration, 0.001)
+	assert.Equal(t, start.Add(20*time.Second), index.segments[1].StartTime)
+}
+
+func TestRecorderDropsShortTailAndIgnoresWritesAfterClose(t *testing.T) {
+	t.Parallel()
+	index := &memIndex{}
+	r, _ := newTestRecorder(t, index)
+
+	require.NoError(t, r.Write(audiocore.AudioFrame{
+		Data:      make([]byte, testSampleRate/2*bytesPerSample),
+		Timestamp: time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC),
+	}))
+	require.NoError(t, r.Close())
+	require.NoError(t, r.Close(), "Close is idempotent")
+	feed(t, r, time.Now(), 2*time.Second)
+
+	assert.Empty(t, index.segments)
+}
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "rtsp_abc", sanitize("rtsp_abc"))
+	assert.Equal(t, "______etc", sanitize("../../etc"))
+	assert.Equal(t, "source", sanitize(""))
+}
@@ -59,6 +59,12 @@ func ProfileForModelType(modelType string) FrequencyProfile {
 	return BirdProfile()
 }
 
+// MaxFrequency returns the highest frequency in Hz a render with this profile
+// shows, or 0 when the profile keeps the clip's native rate.
+func (p FrequencyProfile) MaxFrequency() float64 {
+	return float64(p.ResampleRate) / 2
+}
+
 // ProfileSuffix returns a short, stable token identifying the frequency profile
 // for use in spectrogram cache filenames and queue keys, so renders made with
 // different profiles do not collide on disk. The default bird profile returns ""
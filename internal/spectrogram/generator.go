@@ -1,6 +1,6 @@
 // Package spectrogram provides core spectrogram generation logic.
-// This file contains the Generator type that consolidates Sox and FFmpeg generation
-// used by both the pre-renderer (background mode) and API (on-demand mode).
+// This file contains the Generator type that consolidates native, Sox and FFmpeg
+// generation used by both the pre-renderer (background mode) and API (on-demand mode).
 package spectrogram
 
 import (
@@ -261,7 +261,8 @@ func (g *Generator) getDynamicRange(settings *conf.Settings) string {
 
 // GenerateFromFile creates a spectrogram from an audio file path.
 // Used by API on-demand and user-requested modes.
-// Tries Sox first (faster), falls back to FFmpeg if Sox fails.
+// With the native renderer the file is decoded and rendered in-process.
+// Otherwise tries Sox first (faster), falls back to FFmpeg if Sox fails.
 //
 // The audioPath and outputPath must be absolute paths.
 // Width is in pixels, raw controls whether to show axes/legends.
@@ -357,8 +358,16 @@ func (g *Generator) GenerateFromFile(ctx context.Context, audioPath, outputPath
 	soxCtx, soxCancel := context.WithTimeout(ctx, defaultGenerationTimeout)
 	defer soxCancel()
 
-	// Try Sox first (faster, direct processing)
-	if err := g.generateWithSoxFile(soxCtx, settings, audioPath, tempPath, width, raw, options.preValidatedDuration, profile); err != nil {
+	// Render in-process, or try Sox first (faster, direct processing)
+	if settings.Realtime.Dashboard.Spectrogram.UsesNativeRenderer() {
+		if err := g.generateNativeFile(soxCtx, settings, audioPath, tempPath, width, raw, profile); err != nil {
+			g.log().Warn("Native spectrogram rendering failed",
+				logger.String("audio_path", audioPath),
+				logger.Error(err),
+				logger.Int64("elapsed_ms", time.Since(start).Milliseconds()))
+			return err
+		}
+	} else if err := g.generateWithSoxFile(soxCtx, settings, audioPath, tempPath, width, raw, options.preValidatedDuration, profile); err != nil {
 		// If the caller's context is already done (client disconnect, shutdown, or
 		// caller-imposed timeout), the result is no longer wanted. Skip the FFmpeg
 		// fallback: it runs on a context detached from the parent (see
@@ -500,8 +509,12 @@ func (g *Generator) GenerateFromPCM(ctx context.Context, pcmData []byte, outputP
 	ctx, cancel := context.WithTimeout(ctx, defaultGenerationTimeout)
 	defer cancel()
 
-	// Generate directly from PCM stdin (no FFmpeg needed)
-	if err := g.generateWithSoxPCM(ctx, settings, pcmData, tempPath, width, raw, sampleRate, profile); err != nil {
+	// Render in-process, or feed the PCM to Sox stdin (no FFmpeg needed)
+	if settings.Realtime.Dashboard.Spectrogram.UsesNativeRenderer() {
+		if err := g.generateNativePCM(ctx, settings, pcmData, tempPath, width, raw, sampleRate, profile); err != nil {
+			return err
+		}
+	} else if err := g.generateWithSoxPCM(ctx, settings, pcmData, tempPath, width, raw, sampleRate, profile); err != nil {
 		return err
 	}
 
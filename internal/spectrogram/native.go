@@ -0,0 +1,629 @@
+// native.go contains the in-process spectrogram renderer. It computes a
+// short-time Fourier transform of mono audio and paints it with colour maps
+// modelled on the Sox style presets, so spectrograms can be rendered without
+// forking Sox or FFmpeg.
+package spectrogram
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"math/cmplx"
+	"os"
+	"strconv"
+	"time"
+
+	audioresampler "github.com/tphakala/go-audio-resampler"
+
+	"github.com/tphakala/birdnet-go/internal/audiocore/audiofile"
+	"github.com/tphakala/birdnet-go/internal/audiocore/ffmpeg"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// logScaleMinHz is the lowest frequency shown on a log frequency axis.
+	// A log axis cannot start at 0 Hz; bat renders start higher, see
+	// logScaleFloor.
+	logScaleMinHz = 50.0
+
+	// Margins around the plot when axes are drawn (raw == false). The right
+	// margin holds the colour scale.
+	axisMarginLeft   = 12
+	axisMarginRight  = 28
+	axisMarginTop    = 8
+	axisMarginBottom = 12
+	axisTickLength   = 6
+	colorBarWidth    = 10
+	colorBarGap      = 8
+
+	// maxAxisTicks bounds the number of ticks drawn along each axis.
+	maxAxisTicks = 12
+)
+
+// nativeOptions describes one native render.
+type nativeOptions struct {
+	width        int     // plot width in pixels (one STFT frame per column)
+	height       int     // plot height in pixels; FFT size is 2*(height-1)
+	raw          bool    // omit axes and colour scale
+	style        string  // conf.SpectrogramStyle* preset
+	scale        string  // conf.SpectrogramScale* frequency axis
+	dynamicRange float64 // dB below full scale mapped to the darkest colour
+}
+
+// nativeOptionsFromSettings builds render options from the settings snapshot.
+func (g *Generator) nativeOptionsFromSettings(settings *conf.Settings, width int, raw bool) nativeOptions {
+	dr, err := strconv.ParseFloat(g.getDynamicRange(settings), 64)
+	if err != nil || dr <= 0 {
+		dr, _ = strconv.ParseFloat(defaultDynamicRange, 64)
+	}
+	return nativeOptions{
+		width:        width,
+		height:       fftFriendlyHeight(width),
+		raw:          raw,
+		style:        settings.Realtime.Dashboard.Spectrogram.Style,
+		scale:        settings.Realtime.Dashboard.Spectrogram.Scale,
+		dynamicRange: dr,
+	}
+}
+
+// generateNativePCM renders a spectrogram from s16le mono PCM in-process.
+func (g *Generator) generateNativePCM(ctx context.Context, settings *conf.Settings, pcmData []byte, outputPath string, width int, raw bool, sampleRate int, profile FrequencyProfile) error {
+	if sampleRate <= 0 {
+		sampleRate = conf.SampleRate
+	}
+	return g.generateNative(ctx, settings, pcm16ToFloat(pcmData), sampleRate, outputPath, width, raw, profile)
+}
+
+// generateNativeFile renders a spectrogram from an audio file in-process.
+// WAV and FLAC are decoded natively; other formats are decoded to PCM by
+// FFmpeg, which still avoids Sox.
+func (g *Generator) generateNativeFile(ctx context.Context, settings *conf.Settings, audioPath, outputPath string, width int, raw bool, profile FrequencyProfile) error {
+	if audiofile.IsSupported(audioPath) {
+		samples, info, err := audiofile.ReadMono(ctx, audioPath)
+		if err != nil {
+			return err
+		}
+		return g.generateNative(ctx, settings, samples, info.SampleRate, outputPath, width, raw, profile)
+	}
+
+	pcm, rate, err := decodeWithFFmpeg(ctx, settings, audioPath, profile)
+	if err != nil {
+		return err
+	}
+	return g.generateNative(ctx, settings, pcm16ToFloat(pcm), rate, outputPath, width, raw, profile)
+}
+
+// generateNative resamples samples to the profile rate, renders them and
+// writes the PNG to outputPath.
+func (g *Generator) generateNative(ctx context.Context, settings *conf.Settings, samples []float32, sampleRate int, outputPath string, width int, raw bool, profile FrequencyProfile) error {
+	renderStart := time.Now()
+	samples, sampleRate, err := resampleForProfile(samples, sampleRate, profile)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	img := renderNative(samples, sampleRate, g.nativeOptionsFromSettings(settings, width, raw))
+
+	f, err := g.sfs.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return errors.New(err).
+			Component("spectrogram").
+			Category(errors.CategoryFileIO).
+			Context("operation", "generate_native").
+			Context("output_path", outputPath).
+			Build()
+	}
+	if err := encodeNativePNG(f, img); err != nil {
+		_ = f.Close()
+		return errors.New(err).
+			Component("spectrogram").
+			Category(errors.CategoryFileIO).
+			Context("operation", "encode_native_png").
+			Context("output_path", outputPath).
+			Build()
+	}
+	if err := f.Close(); err != nil {
+		return errors.New(err).
+			Component("spectrogram").
+			Category(errors.CategoryFileIO).
+			Context("operation", "generate_native").
+			Context("output_path", outputPath).
+			Build()
+	}
+
+	g.log().Info("Native spectrogram rendering completed",
+		logger.String("output_path", outputPath),
+		logger.Int("width", width),
+		logger.Int("samples", len(samples)),
+		logger.Int64("render_ms", time.Since(renderStart).Milliseconds()))
+	return nil
+}
+
+// resampleForProfile resamples samples to the profile's rate so the frequency
+// axis matches the fixed UI overlay, like the Sox "rate" effect does.
+func resampleForProfile(samples []float32, sampleRate int, profile FrequencyProfile) ([]float32, int, error) {
+	if profile.ResampleRate <= 0 || profile.ResampleRate == sampleRate {
+		return samples, sampleRate, nil
+	}
+	rs, err := audioresampler.NewEngineFloat32(float64(sampleRate), float64(profile.ResampleRate), audioresampler.QualityMedium)
+	if err != nil {
+		return nil, 0, errors.Newf("failed to create resampler from %d Hz to %d Hz: %w", sampleRate, profile.ResampleRate, err).
+			Component("spectrogram").
+			Category(errors.CategoryAudio).
+			Context("operation", "resample_for_profile").
+			Build()
+	}
+	out, err := rs.Process(samples)
+	if err == nil {
+		var tail []float32
+		if tail, err = rs.Flush(); err == nil {
+			out = append(out, tail...)
+		}
+	}
+	if err != nil {
+		return nil, 0, errors.New(err).
+			Component("spectrogram").
+			Category(errors.CategoryAudio).
+			Context("operation", "resample_for_profile").
+			Build()
+	}
+	return out, profile.ResampleRate, nil
+}
+
+// decodeWithFFmpeg decodes an audio file FFmpeg understands to s16le mono
+// PCM, at the profile rate when it sets one. It returns the PCM and its rate.
+func decodeWithFFmpeg(ctx context.Context, settings *conf.Settings, audioPath string, profile FrequencyProfile) (pcm []byte, sampleRate int, err error) {
+	ffmpegBinary := settings.Realtime.Audio.FfmpegPath
+	if err := ffmpeg.ValidateFFmpegPath(ffmpegBinary); err != nil {
+		return nil, 0, errors.Newf("invalid FFmpeg path: %s", err).
+			Component("spectrogram").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "decode_with_ffmpeg").
+			Context("ffmpeg_path", ffmpegBinary).
+			Build()
+	}
+
+	sampleRate = profile.ResampleRate
+	if sampleRate <= 0 {
+		sampleRate = conf.SampleRate
+	}
+	args := []string{
+		"-hide_banner",
+		"-loglevel", "error",
+		"-i", audioPath,
+		"-vn",
+		"-f", "s16le",
+		"-ac", "1",
+		"-ar", strconv.Itoa(sampleRate),
+		"pipe:1",
+	}
+	cmd := createCommandWithNice(ctx, ffmpegBinary, args)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		eb := errors.New(err).
+			Component("spectrogram").
+			Category(errors.CategorySystem).
+			Context("operation", "decode_with_ffmpeg").
+			Context("audio_path", audioPath).
+			Context("input_file_bytes", getFileSizeBytes(audioPath)).
+			Context("ffmpeg_output", stderr.String())
+		if isOperationalExecError(ctx, err) {
+			eb = eb.Priority(errors.PriorityLow)
+		}
+		return nil, 0, eb.Build()
+	}
+	return stdout.Bytes(), sampleRate, nil
+}
+
+// pcm16ToFloat converts little-endian signed 16-bit mono PCM to float32
+// samples in [-1, 1].
+func pcm16ToFloat(pcm []byte) []float32 {
+	samples := make([]float32, len(pcm)/2)
+	for i := range samples {
+		samples[i] = float32(int16(binary.LittleEndian.Uint16(pcm[2*i:]))) / 32768 //nolint:gosec // G115: intentional uint16→int16 reinterpretation for PCM audio
+	}
+	return samples
+}
+
+// renderNative computes the spectrogram of samples (mono, at sampleRate Hz)
+// and returns it as an image. The frequency axis spans 0 Hz to the Nyquist
+// frequency of sampleRate, so callers resample to the profile rate first.
+func renderNative(samples []float32, sampleRate int, opts nativeOptions) *image.NRGBA {
+	fftSize := 2 * (opts.height - 1)
+	window := windowForStyle(opts.style, fftSize)
+	var windowSum float64
+	for _, w := range window {
+		windowSum += w
+	}
+
+	rowBins := frequencyRows(opts.height, fftSize, float64(sampleRate), opts.scale)
+	palette := paletteForStyle(opts.style)
+
+	plot := image.Rect(0, 0, opts.width, opts.height)
+	bounds := plot
+	if !opts.raw {
+		plot = plot.Add(image.Pt(axisMarginLeft, axisMarginTop))
+		bounds = image.Rect(0, 0, plot.Max.X+axisMarginRight, plot.Max.Y+axisMarginBottom)
+	}
+	img := image.NewNRGBA(bounds)
+
+	buf := make([]complex128, fftSize)
+	power := make([]float64, fftSize/2+1)
+	twiddles := fftTwiddles(fftSize)
+	// Amplitudes are relative to a full-scale sine, which peaks at
+	// windowSum/2 in the spectrum.
+	refPower := (windowSum / 2) * (windowSum / 2)
+	n := len(samples)
+	for x := range opts.width {
+		// Centre frame x on its share of the clip; frames reaching past either
+		// end are zero padded.
+		start := (2*x+1)*n/(2*opts.width) - fftSize/2
+		for i := range fftSize {
+			var v float64
+			if j := start + i; j >= 0 && j < n {
+				v = float64(samples[j])
+			}
+			buf[i] = complex(v*window[i], 0)
+		}
+		fftInPlace(buf, twiddles)
+		for k := range power {
+			power[k] = real(buf[k])*real(buf[k]) + imag(buf[k])*imag(buf[k])
+		}
+
+		for y, bins := range rowBins {
+			p := bins.power(power)
+			level := 1.0
+			if p < refPower {
+				level = 1 + 10*math.Log10(p/refPower+1e-30)/opts.dynamicRange
+			}
+			// Row 0 is the lowest frequency, drawn at the bottom
+			img.SetNRGBA(plot.Min.X+x, plot.Max.Y-1-y, palette.at(level))
+		}
+	}
+
+	if !opts.raw {
+		drawAxes(img, plot, float64(n)/float64(sampleRate), float64(sampleRate)/2, opts.scale, palette)
+	}
+	return img
+}
+
+// encodeNativePNG writes img as PNG to w.
+func encodeNativePNG(w io.Writer, img image.Image) error {
+	enc := png.Encoder{CompressionLevel: png.BestSpeed}
+	return enc.Encode(w, img)
+}
+
+// rowBand is the range of FFT bins shown by one image row. When the row is
+// narrower than a bin, frac interpolates between bins lo and lo+1 instead.
+type rowBand struct {
+	lo, hi int
+	frac   float64
+}
+
+// power returns the row's power: the strongest bin in the band, or the
+// interpolated power for sub-bin rows.
+func (b rowBand) power(spectrum []float64) float64 {
+	if b.frac > 0 {
+		return spectrum[b.lo]*(1-b.frac) + spectrum[b.hi]*b.frac
+	}
+	p := spectrum[b.lo]
+	for k := b.lo + 1; k <= b.hi; k++ {
+		p = max(p, spectrum[k])
+	}
+	return p
+}
+
+// frequencyRows maps each image row, bottom first, to the FFT bins it shows.
+// On a linear axis every row is exactly one bin.
+func frequencyRows(height, fftSize int, sampleRate float64, scale string) []rowBand {
+	rows := make([]rowBand, height)
+	nyquist := sampleRate / 2
+	lastBin := fftSize / 2
+	binHz := sampleRate / float64(fftSize)
+	if scale != conf.SpectrogramScaleLog && scale != conf.SpectrogramScaleMel {
+		for y := range rows {
+			rows[y] = rowBand{lo: y, hi: y}
+		}
+		return rows
+	}
+
+	for y := range rows {
+		lo := rowFrequency(float64(y)-0.5, height, nyquist, scale)
+		hi := rowFrequency(float64(y)+0.5, height, nyquist, scale)
+		// The epsilon keeps rounding error from dropping a bin at the band edges
+		loBin := max(int(math.Ceil(lo/binHz-1e-9)), 0)
+		hiBin := min(int(math.Floor(hi/binHz+1e-9)), lastBin)
+		if loBin <= hiBin {
+			rows[y] = rowBand{lo: loBin, hi: hiBin}
+			continue
+		}
+		// The row falls between two bins: interpolate at its centre frequency
+		pos := min(rowFrequency(float64(y), height, nyquist, scale)/binHz, float64(lastBin))
+		k := min(int(pos), lastBin-1)
+		rows[y] = rowBand{lo: k, hi: k + 1, frac: max(pos-float64(k), 1e-9)}
+	}
+	return rows
+}
+
+// rowFrequency returns the frequency in Hz at (fractional) row y of an axis
+// of height rows spanning up to nyquist.
+func rowFrequency(y float64, height int, nyquist float64, scale string) float64 {
+	t := min(max(y/float64(height-1), 0), 1)
+	switch scale {
+	case conf.SpectrogramScaleLog:
+		floor := logScaleFloor(nyquist)
+		return floor * math.Pow(nyquist/floor, t)
+	case conf.SpectrogramScaleMel:
+		return melToHz(t * hzToMel(nyquist))
+	default:
+		return t * nyquist
+	}
+}
+
+// frequencyRow is the inverse of rowFrequency.
+func frequencyRow(f float64, height int, nyquist float64, scale string) float64 {
+	var t float64
+	switch scale {
+	case conf.SpectrogramScaleLog:
+		floor := logScaleFloor(nyquist)
+		t = math.Log(max(f, floor)/floor) / math.Log(nyquist/floor)
+	case conf.SpectrogramScaleMel:
+		t = hzToMel(f) / hzToMel(nyquist)
+	default:
+		t = f / nyquist
+	}
+	return t * float64(height-1)
+}
+
+// logScaleFloor returns the lowest frequency of a log axis: logScaleMinHz,
+// or nine octaves below the top for ultrasonic renders.
+func logScaleFloor(nyquist float64) float64 {
+	return max(logScaleMinHz, nyquist/512)
+}
+
+func hzToMel(f float64) float64 { return 2595 * math.Log10(1+f/700) }
+func melToHz(m float64) float64 { return 700 * (math.Pow(10, m/2595) - 1) }
+
+// windowForStyle returns the analysis window for a style preset. Sox uses a
+// Hann window by default and a Dolph-Chebyshev window for the scientific
+// styles; the 4-term Blackman-Harris window stands in for the latter with
+// similarly low side lobes.
+func windowForStyle(style string, n int) []float64 {
+	w := make([]float64, n)
+	switch style {
+	case conf.SpectrogramStyleScientific, conf.SpectrogramStyleScientificDark:
+		for i := range w {
+			x := 2 * math.Pi * float64(i) / float64(n-1)
+			w[i] = 0.35875 - 0.48829*math.Cos(x) + 0.14128*math.Cos(2*x) - 0.01168*math.Cos(3*x)
+		}
+	default:
+		for i := range w {
+			w[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(n-1)))
+		}
+	}
+	return w
+}
+
+// fftTwiddles precomputes the twiddle factors for an n-point FFT.
+func fftTwiddles(n int) []complex128 {
+	tw := make([]complex128, n/2)
+	for k := range tw {
+		tw[k] = cmplx.Rect(1, -2*math.Pi*float64(k)/float64(n))
+	}
+	return tw
+}
+
+// fftInPlace performs an in-place iterative radix-2 FFT. len(data) must be a
+// power of 2 and twiddles must come from fftTwiddles(len(data)).
+func fftInPlace(data, twiddles []complex128) {
+	n := len(data)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		half := size >> 1
+		step := n / size
+		for start := 0; start < n; start += size {
+			for k := range half {
+				v := twiddles[k*step] * data[start+k+half]
+				u := data[start+k]
+				data[start+k] = u + v
+				data[start+k+half] = u - v
+			}
+		}
+	}
+}
+
+// palette maps a level in [0, 1] (silence to full scale) to a colour.
+type palette struct {
+	colors     [256]color.NRGBA
+	background color.NRGBA // axis margin colour
+	foreground color.NRGBA // axis line colour
+}
+
+// at returns the colour for level, clamped to [0, 1].
+func (p *palette) at(level float64) color.NRGBA {
+	return p.colors[int(min(max(level, 0), 1)*255+0.5)]
+}
+
+// paletteForStyle builds the colour map of a style preset after the Sox
+// palettes: the default heat map, the high-colour map (-h) and monochrome
+// (-m) on a dark or light (-l) background.
+func paletteForStyle(style string) *palette {
+	p := &palette{
+		background: color.NRGBA{A: 255},
+		foreground: color.NRGBA{R: 192, G: 192, B: 192, A: 255},
+	}
+	for i := range p.colors {
+		x := float64(i) / 255
+		var r, g, b float64
+		switch style {
+		case conf.SpectrogramStyleScientificDark:
+			r, g, b = x, x, x
+		case conf.SpectrogramStyleScientific:
+			r, g, b = 1-x, 1-x, 1-x
+		case conf.SpectrogramStyleHighContrastDark:
+			r, g, b = highColor(x)
+		default:
+			r, g, b = soxColor(x)
+		}
+		p.colors[i] = color.NRGBA{R: channel(r), G: channel(g), B: channel(b), A: 255}
+	}
+	if style == conf.SpectrogramStyleScientific {
+		p.background = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+		p.foreground = color.NRGBA{R: 64, G: 64, B: 64, A: 255}
+	}
+	return p
+}
+
+// soxColor is the Sox default palette: black through purple and red to
+// yellow and white.
+func soxColor(x float64) (r, g, b float64) {
+	switch {
+	case x < 0.13:
+	case x < 0.73:
+		r = math.Sin((x - 0.13) / 0.60 * math.Pi / 2)
+	default:
+		r = 1
+	}
+	switch {
+	case x < 0.60:
+	case x < 0.91:
+		g = math.Sin((x - 0.60) / 0.31 * math.Pi / 2)
+	default:
+		g = 1
+	}
+	switch {
+	case x < 0.60:
+		b = 0.5 * math.Sin(x/0.60*math.Pi)
+	case x < 0.78:
+	default:
+		b = (x - 0.78) / 0.22
+	}
+	return r, g, b
+}
+
+// highColorStates drives highColor: for each of seven equal phases of the
+// level, how each channel behaves (see highColor).
+var highColorStates = [3][7]int{
+	{4, 5, 0, 0, 2, 1, 1},
+	{0, 0, 2, 1, 1, 3, 2},
+	{4, 1, 1, 3, 0, 0, 2},
+}
+
+// highColor is the Sox high-colour palette, which cycles through more hues
+// than the default to make faint signals stand out.
+func highColor(x float64) (r, g, b float64) {
+	phase := min(int(7*x), 6)
+	t := 7*x - float64(phase)
+	var c [3]float64
+	for j := range c {
+		switch highColorStates[j][phase] {
+		case 1:
+			c[j] = 1
+		case 2:
+			c[j] = math.Sin(t * math.Pi / 2)
+		case 3:
+			c[j] = math.Cos(t * math.Pi / 2)
+		case 4:
+			c[j] = t
+		case 5:
+			c[j] = 1 - t
+		}
+	}
+	return c[0], c[1], c[2]
+}
+
+// channel converts a colour intensity in [0, 1] to 8 bits.
+func channel(v float64) uint8 {
+	return uint8(min(max(v, 0), 1)*255 + 0.5)
+}
+
+// drawAxes frames the plot, draws frequency ticks on the left and time ticks
+// along the bottom, and paints the colour scale in the right margin. The
+// native renderer has no font, so ticks are unlabelled; the UI overlays its
+// own frequency axis.
+func drawAxes(img *image.NRGBA, plot image.Rectangle, seconds, nyquist float64, scale string, p *palette) {
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if !(image.Point{X: x, Y: y}).In(plot) {
+				img.SetNRGBA(x, y, p.background)
+			}
+		}
+	}
+
+	fg := p.foreground
+	for x := plot.Min.X - 1; x <= plot.Max.X; x++ {
+		img.SetNRGBA(x, plot.Min.Y-1, fg)
+		img.SetNRGBA(x, plot.Max.Y, fg)
+	}
+	for y := plot.Min.Y - 1; y <= plot.Max.Y; y++ {
+		img.SetNRGBA(plot.Min.X-1, y, fg)
+		img.SetNRGBA(plot.Max.X, y, fg)
+	}
+
+	height := plot.Dy()
+	step := tickStep(nyquist)
+	for f := step; f < nyquist; f += step {
+		row := frequencyRow(f, height, nyquist, scale)
+		if row < 1 {
+			continue
+		}
+		y := plot.Max.Y - 1 - int(row+0.5)
+		for x := plot.Min.X - 1 - axisTickLength; x < plot.Min.X-1; x++ {
+			img.SetNRGBA(x, y, fg)
+		}
+	}
+
+	if seconds > 0 {
+		step := tickStep(seconds)
+		for s := step; s < seconds; s += step {
+			x := plot.Min.X + int(s/seconds*float64(plot.Dx())+0.5)
+			for y := plot.Max.Y + 1; y <= plot.Max.Y+axisTickLength; y++ {
+				img.SetNRGBA(x, y, fg)
+			}
+		}
+	}
+
+	// Colour scale, full scale at the top
+	barLeft := plot.Max.X + colorBarGap
+	for y := plot.Min.Y; y < plot.Max.Y; y++ {
+		c := p.at(float64(plot.Max.Y-1-y) / float64(height-1))
+		for x := barLeft; x < barLeft+colorBarWidth && x < b.Max.X; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+}
+
+// tickStep returns a 1-2-5 step that divides span into at most maxAxisTicks
+// intervals.
+func tickStep(span float64) float64 {
+	raw := span / maxAxisTicks
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*mag >= raw {
+			return m * mag
+		}
+	}
+	return 10 * mag
+}
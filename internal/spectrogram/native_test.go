@@ -0,0 +1,170 @@
+package spectrogram
+
+import (
+	"encoding/binary"
+	"image"
+	"image/png"
+	"math"
+	"math/cmplx"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// newNativeGenerator returns a generator configured for the native renderer
+// with no Sox or FFmpeg paths, so any attempt to run them fails.
+func newNativeGenerator(t *testing.T) (*Generator, *testEnv) {
+	t.Helper()
+	env := setupTestEnv(t)
+	env.Settings.Realtime.Dashboard.Spectrogram.Renderer = conf.SpectrogramRendererNative
+	return NewGenerator(env.Settings, env.SFS, logger.Global().Module("spectrogram.test")), env
+}
+
+// decodePNG reads the PNG at path.
+func decodePNG(t *testing.T, path string) image.Image {
+	t.Helper()
+	f, err := os.Open(path) //nolint:gosec // G304: test output path
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	img, err := png.Decode(f)
+	require.NoError(t, err)
+	return img
+}
+
+// brightestRow returns the row of column x with the highest luminance.
+func brightestRow(img image.Image, x int) int {
+	best, bestLum := 0, -1.0
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		r, g, b, _ := img.At(x, y).RGBA()
+		if lum := 0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b); lum > bestLum {
+			best, bestLum = y, lum
+		}
+	}
+	return best
+}
+
+func TestGenerator_GenerateFromPCM_Native(t *testing.T) {
+	t.Parallel()
+	gen, env := newNativeGenerator(t)
+
+	// A 3 kHz tone sits at a quarter of the 0-12 kHz bird axis
+	pcm := generateTestPCMData(&PCMOptions{SampleRate: defaultSampleRate, Duration: 2, Frequency: 3000, Amplitude: 16000})
+	outputPath := filepath.Join(env.TempDir, "native.png")
+	require.NoError(t, gen.GenerateFromPCM(t.Context(), pcm, outputPath, 400, true, defaultSampleRate))
+	assertNoSpectrogramTemp(t, outputPath)
+
+	img := decodePNG(t, outputPath)
+	height := fftFriendlyHeight(400)
+	assert.Equal(t, image.Rect(0, 0, 400, height), img.Bounds(), "raw renders are exactly width x height")
+
+	wantRow := height - 1 - (height-1)/4
+	assert.InDelta(t, wantRow, brightestRow(img, 200), 2, "tone must land on the 3 kHz row")
+}
+
+func TestGenerator_GenerateFromFile_NativeWAV(t *testing.T) {
+	t.Parallel()
+	gen, env := newNativeGenerator(t)
+
+	pcm := generateTestPCMData(&PCMOptions{SampleRate: 32000, Duration: 1, Frequency: 6000, Amplitude: 16000})
+	audioPath := filepath.Join(env.TempDir, "clip.wav")
+	hdr := make([]byte, 0, 44)
+	hdr = append(hdr, "RIFF"...)
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(36+len(pcm))) //nolint:gosec // test data is small
+	hdr = append(hdr, "WAVEfmt "...)
+	hdr = binary.LittleEndian.AppendUint32(hdr, 16)
+	hdr = binary.LittleEndian.AppendUint16(hdr, 1)     // PCM
+	hdr = binary.LittleEndian.AppendUint16(hdr, 1)     // mono
+	hdr = binary.LittleEndian.AppendUint32(hdr, 32000) // sample rate
+	hdr = binary.LittleEndian.AppendUint32(hdr, 64000) // byte rate
+	hdr = binary.LittleEndian.AppendUint16(hdr, 2)     // block align
+	hdr = binary.LittleEndian.AppendUint16(hdr, 16)    // bits per sample
+	hdr = append(hdr, "data"...)
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(len(pcm))) //nolint:gosec // test data is small
+	require.NoError(t, os.WriteFile(audioPath, append(hdr, pcm...), 0o600))
+
+	outputPath := filepath.Join(env.TempDir, "clip.png")
+	require.NoError(t, gen.GenerateFromFile(t.Context(), audioPath, outputPath, 400, false))
+
+	img := decodePNG(t, outputPath)
+	height := fftFriendlyHeight(400)
+	assert.Equal(t, image.Rect(0, 0, axisMarginLeft+400+axisMarginRight, axisMarginTop+height+axisMarginBottom), img.Bounds(),
+		"axes add margins around the plot")
+
+	// The 32 kHz clip is resampled to 24 kHz, so 6 kHz is half way up
+	wantRow := axisMarginTop + height - 1 - (height-1)/2
+	assert.InDelta(t, wantRow, brightestRow(img, axisMarginLeft+200), 2)
+}
+
+func TestFrequencyRows(t *testing.T) {
+	t.Parallel()
+	const height, fftSize, rate = 257, 512, 24000.0
+
+	linear := frequencyRows(height, fftSize, rate, conf.SpectrogramScaleLinear)
+	for y, band := range linear {
+		assert.Equal(t, rowBand{lo: y, hi: y}, band, "linear rows map to one bin each")
+	}
+
+	for _, scale := range []string{conf.SpectrogramScaleLog, conf.SpectrogramScaleMel} {
+		rows := frequencyRows(height, fftSize, rate, scale)
+		require.Len(t, rows, height, scale)
+		for y := 1; y < height; y++ {
+			assert.GreaterOrEqual(t, rows[y].lo, rows[y-1].lo, "%s rows must not go down in frequency", scale)
+		}
+		assert.Equal(t, fftSize/2, rows[height-1].hi, "%s axis must reach the Nyquist bin", scale)
+		assert.Positive(t, rows[1].frac, "%s rows near 0 Hz are narrower than a bin", scale)
+		assert.Greater(t, rows[height-1].hi, rows[height-1].lo, "%s rows near the top span several bins", scale)
+		assert.Zero(t, rows[height-1].frac, scale)
+
+		for _, f := range []float64{500, 3000, 11000} {
+			row := frequencyRow(f, height, rate/2, scale)
+			assert.InDelta(t, f, rowFrequency(row, height, rate/2, scale), 1e-6, "%s row mapping must round-trip", scale)
+		}
+	}
+}
+
+func TestPaletteForStyle(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		style        string
+		silent, full [3]uint8
+	}{
+		{conf.SpectrogramStyleDefault, [3]uint8{0, 0, 0}, [3]uint8{255, 255, 255}},
+		{conf.SpectrogramStyleScientificDark, [3]uint8{0, 0, 0}, [3]uint8{255, 255, 255}},
+		{conf.SpectrogramStyleScientific, [3]uint8{255, 255, 255}, [3]uint8{0, 0, 0}},
+		{conf.SpectrogramStyleHighContrastDark, [3]uint8{0, 0, 0}, [3]uint8{255, 255, 255}},
+	}
+	for _, tc := range tests {
+		p := paletteForStyle(tc.style)
+		silent, full := p.at(0), p.at(1)
+		assert.Equal(t, tc.silent, [3]uint8{silent.R, silent.G, silent.B}, "%s silence", tc.style)
+		assert.Equal(t, tc.full, [3]uint8{full.R, full.G, full.B}, "%s full scale", tc.style)
+	}
+
+	assert.Equal(t, paletteForStyle(conf.SpectrogramStyleDefault).at(0), paletteForStyle("").at(0),
+		"an unset style uses the default palette")
+	mid := paletteForStyle(conf.SpectrogramStyleDefault).at(0.5)
+	assert.Greater(t, mid.R, mid.G, "the default palette is red before it turns yellow")
+}
+
+func TestFFTInPlace_KnownSinusoid(t *testing.T) {
+	t.Parallel()
+	const n, bin = 64, 5
+	data := make([]complex128, n)
+	for i := range data {
+		data[i] = complex(math.Cos(2*math.Pi*bin*float64(i)/n), 0)
+	}
+	fftInPlace(data, fftTwiddles(n))
+	for k, v := range data {
+		want := 0.0
+		if k == bin || k == n-bin {
+			want = n / 2
+		}
+		assert.InDelta(t, want, cmplx.Abs(v), 1e-9, "bin %d", k)
+	}
+}
@@ -1,5 +1,6 @@
 // Package spectrogram provides background pre-rendering of spectrograms to eliminate UI lag.
-// Pre-rendering feeds PCM data directly to Sox (bypassing FFmpeg) in a background worker pool.
+// Pre-rendering feeds PCM data to the native renderer or directly to Sox (bypassing FFmpeg)
+// in a background worker pool.
 package spectrogram
 
 import (
@@ -75,6 +75,8 @@ func (c *GormDatabaseInfoCollector) CollectDatabaseInfo(ctx context.Context) (*D
 		c.collectSQLiteInfo(ctx, info, &collectionErrors, log)
 	case datastore.DialectMySQL:
 		c.collectMySQLInfo(ctx, info, &collectionErrors, log)
+	case datastore.DialectPostgres:
+		c.collectPostgresInfo(ctx, info, &collectionErrors, log)
 	default:
 		collectionErrors = append(collectionErrors, fmt.Sprintf("unsupported dialect: %s", c.dialect))
 	}
@@ -509,6 +511,164 @@ func (c *GormDatabaseInfoCollector) collectMySQLTableIndexes(ctx context.Context
 	ts.Indexes = indexes
 }
 
+// collectPostgresInfo gathers PostgreSQL-specific diagnostics.
+func (c *GormDatabaseInfoCollector) collectPostgresInfo(ctx context.Context, info *DatabaseInfo, errs *[]string, log logger.Logger) {
+	db := c.db.WithContext(ctx)
+
+	// Engine version
+	var version string
+	if err := db.Raw("SHOW server_version").Row().Scan(&version); err != nil {
+		*errs = append(*errs, fmt.Sprintf("postgres version: %v", err))
+	} else {
+		info.EngineVersion = version
+	}
+
+	// Integrity check not available for PostgreSQL
+	info.IntegrityCheck = "not_available"
+
+	// Database size
+	var dbSize int64
+	if err := db.Raw("SELECT pg_database_size(current_database())").Row().Scan(&dbSize); err != nil {
+		*errs = append(*errs, fmt.Sprintf("postgres db size: %v", err))
+	} else {
+		info.DatabaseSizeBytes = dbSize
+	}
+
+	// Tables
+	c.collectPostgresTables(ctx, info, errs, log)
+}
+
+// collectPostgresTables enumerates PostgreSQL tables, columns, and indexes.
+// Row counts are planner estimates from pg_stat_user_tables (not exact COUNT(*)) to avoid full table scans.
+func (c *GormDatabaseInfoCollector) collectPostgresTables(ctx context.Context, info *DatabaseInfo, errs *[]string, _ logger.Logger) {
+	db := c.db.WithContext(ctx)
+
+	type tableRow struct {
+		TableName string `gorm:"column:table_name"`
+		TableRows int64  `gorm:"column:table_rows"`
+	}
+
+	var tableRows []tableRow
+	query := "SELECT relname AS table_name, n_live_tup AS table_rows FROM pg_stat_user_tables WHERE schemaname = current_schema()"
+	rawQuery := db.Raw(query + " ORDER BY relname")
+	if c.tablePrefix != "" {
+		rawQuery = db.Raw(query+" AND relname LIKE ? ORDER BY relname", c.tablePrefix+"%")
+	}
+	if err := rawQuery.Scan(&tableRows).Error; err != nil {
+		*errs = append(*errs, fmt.Sprintf("list postgres tables: %v", err))
+		return
+	}
+
+	tables := make([]TableSchema, 0, len(tableRows))
+	for _, tr := range tableRows {
+		ts := TableSchema{Name: tr.TableName, RowCount: tr.TableRows}
+		c.collectPostgresTableColumns(ctx, &ts, errs)
+		c.collectPostgresTableIndexes(ctx, &ts, errs)
+		tables = append(tables, ts)
+	}
+	info.Tables = tables
+}
+
+// collectPostgresTableColumns reads columns from information_schema, marking
+// primary key members from the table's constraints.
+func (c *GormDatabaseInfoCollector) collectPostgresTableColumns(ctx context.Context, ts *TableSchema, errs *[]string) {
+	db := c.db.WithContext(ctx)
+
+	rows, err := db.Raw(`
+		SELECT col.column_name, col.data_type, col.is_nullable, col.column_default,
+			EXISTS (
+				SELECT 1
+				FROM information_schema.table_constraints tc
+				JOIN information_schema.key_column_usage kcu
+					ON kcu.constraint_name = tc.constraint_name AND kcu.table_schema = tc.table_schema
+				WHERE tc.constraint_type = 'PRIMARY KEY'
+					AND tc.table_schema = col.table_schema
+					AND tc.table_name = col.table_name
+					AND kcu.column_name = col.column_name
+			) AS is_pk
+		FROM information_schema.columns col
+		WHERE col.table_schema = current_schema() AND col.table_name = ?
+		ORDER BY col.ordinal_position`, ts.Name).Rows()
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("postgres columns(%s): %v", ts.Name, err))
+		return
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var name, colType, nullable string
+		var dfltValue *string
+		var isPK bool
+		if err := rows.Scan(&name, &colType, &nullable, &dfltValue, &isPK); err != nil {
+			*errs = append(*errs, fmt.Sprintf("scan postgres columns(%s): %v", ts.Name, err))
+			continue
+		}
+		columns = append(columns, ColumnInfo{
+			Name:         name,
+			Type:         colType,
+			Nullable:     nullable == "YES",
+			PrimaryKey:   isPK,
+			DefaultValue: dfltValue,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		*errs = append(*errs, fmt.Sprintf("iterate postgres columns(%s): %v", ts.Name, err))
+	}
+	ts.Columns = columns
+}
+
+// collectPostgresTableIndexes reads indexes and their key columns from pg_index.
+func (c *GormDatabaseInfoCollector) collectPostgresTableIndexes(ctx context.Context, ts *TableSchema, errs *[]string) {
+	db := c.db.WithContext(ctx)
+
+	rows, err := db.Raw(`
+		SELECT ic.relname, a.attname, ix.indisunique
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_class ic ON ic.oid = ix.indexrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(ix.indkey)
+		WHERE n.nspname = current_schema() AND t.relname = ?
+		ORDER BY ic.relname, array_position(ix.indkey, a.attnum)`, ts.Name).Rows()
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("postgres indexes(%s): %v", ts.Name, err))
+		return
+	}
+	defer func() { _ = rows.Close() }()
+
+	indexMap := make(map[string]*IndexInfo)
+	var indexOrder []string
+	for rows.Next() {
+		var indexName, colName string
+		var unique bool
+		if err := rows.Scan(&indexName, &colName, &unique); err != nil {
+			*errs = append(*errs, fmt.Sprintf("scan postgres indexes(%s): %v", ts.Name, err))
+			continue
+		}
+		if existing, ok := indexMap[indexName]; ok {
+			existing.Columns = append(existing.Columns, colName)
+		} else {
+			indexMap[indexName] = &IndexInfo{
+				Name:    indexName,
+				Columns: []string{colName},
+				Unique:  unique,
+			}
+			indexOrder = append(indexOrder, indexName)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		*errs = append(*errs, fmt.Sprintf("iterate postgres indexes(%s): %v", ts.Name, err))
+	}
+
+	indexes := make([]IndexInfo, 0, len(indexOrder))
+	for _, name := range indexOrder {
+		indexes = append(indexes, *indexMap[name])
+	}
+	ts.Indexes = indexes
+}
+
 // collectMigrationState reads the migration_states singleton row.
 func (c *GormDatabaseInfoCollector) collectMigrationState(ctx context.Context, info *DatabaseInfo, errs *[]string, _ logger.Logger) {
 	db := c.db.WithContext(ctx)
@@ -536,7 +696,7 @@ func (c *GormDatabaseInfoCollector) collectMigrationState(ctx context.Context, i
 		msi.CompletedAt = &s
 	}
 
-	// Count dirty records if the table exists (apply prefix for MySQL migration mode)
+	// Count dirty records if the table exists (apply prefix for MySQL/PostgreSQL migration mode)
 	var dirtyCount int64
 	if err := db.Table(c.tablePrefix + "migration_dirty_ids").Count(&dirtyCount).Error; err == nil {
 		msi.DirtyRecordCount = dirtyCount
@@ -0,0 +1,151 @@
+//go:build integration
+
+package containers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// PostgresContainer wraps a testcontainers PostgreSQL instance with helper methods.
+type PostgresContainer struct {
+	container *postgres.PostgresContainer
+	db        *sql.DB
+	dsn       string
+}
+
+// PostgresConfig holds configuration for PostgreSQL container creation.
+type PostgresConfig struct {
+	// Database name (default: "birdnet_test")
+	Database string
+	// Username (default: "testuser")
+	Username string
+	// Password (default: "testpass")
+	Password string
+	// Image tag (default: "16-alpine")
+	ImageTag string
+}
+
+// defaultPostgresImageTag is the PostgreSQL image tag used when a config does not set one.
+const defaultPostgresImageTag = "16-alpine"
+
+// DefaultPostgresConfig returns a PostgresConfig with sensible defaults.
+func DefaultPostgresConfig() PostgresConfig {
+	return PostgresConfig{
+		Database: "birdnet_test",
+		Username: "testuser",
+		Password: "testpass",
+		ImageTag: defaultPostgresImageTag,
+	}
+}
+
+// NewPostgresContainer creates and starts a PostgreSQL container with the given config.
+// If config is nil, uses DefaultPostgresConfig().
+func NewPostgresContainer(ctx context.Context, config *PostgresConfig) (*PostgresContainer, error) {
+	if config == nil {
+		defaultCfg := DefaultPostgresConfig()
+		config = &defaultCfg
+	}
+
+	opts := []testcontainers.ContainerCustomizer{
+		postgres.WithDatabase(config.Database),
+		postgres.WithUsername(config.Username),
+		postgres.WithPassword(config.Password),
+		// The server restarts once after running the init scripts, so wait
+		// for the second readiness log line and the listening port.
+		postgres.BasicWaitStrategies(),
+	}
+
+	imageTag := config.ImageTag
+	if imageTag == "" {
+		imageTag = defaultPostgresImageTag
+	}
+	image := fmt.Sprintf("postgres:%s", imageTag)
+	pgContainer, err := postgres.Run(ctx, image, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start PostgreSQL container: %w", err)
+	}
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		// Use background context for cleanup to ensure it succeeds even if parent ctx expired
+		_ = pgContainer.Terminate(context.Background())
+		return nil, fmt.Errorf("failed to get connection string: %w", err)
+	}
+
+	db, err := sql.Open("pgx", connStr)
+	if err != nil {
+		// Use background context for cleanup to ensure it succeeds even if parent ctx expired
+		_ = pgContainer.Terminate(context.Background())
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetConnMaxIdleTime(1 * time.Minute)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		// Use background context for cleanup to ensure it succeeds even if parent ctx expired
+		_ = pgContainer.Terminate(context.Background())
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &PostgresContainer{
+		container: pgContainer,
+		db:        db,
+		dsn:       connStr,
+	}, nil
+}
+
+// DB returns the database connection without requiring a *testing.T.
+// Returns nil if the database connection was not established.
+func (c *PostgresContainer) DB() *sql.DB {
+	return c.db
+}
+
+// GetDSN returns the PostgreSQL connection URL for the container.
+func (c *PostgresContainer) GetDSN() string {
+	return c.dsn
+}
+
+// GetHost returns the host address where the container is accessible.
+func (c *PostgresContainer) GetHost(ctx context.Context) (string, error) {
+	return c.container.Host(ctx)
+}
+
+// GetPort returns the mapped port where PostgreSQL is accessible.
+func (c *PostgresContainer) GetPort(ctx context.Context) (int, error) {
+	mappedPort, err := c.container.MappedPort(ctx, "5432")
+	if err != nil {
+		return 0, err
+	}
+	return int(mappedPort.Num()), nil
+}
+
+// Terminate stops and removes the PostgreSQL container.
+// It also closes the database connection if open.
+func (c *PostgresContainer) Terminate(ctx context.Context) error {
+	if c.db != nil {
+		if err := c.db.Close(); err != nil {
+			// Log error but continue with container termination
+			fmt.Printf("Warning: failed to close database connection: %v\n", err)
+		}
+		c.db = nil
+	}
+
+	if c.container != nil {
+		if err := c.container.Terminate(ctx); err != nil {
+			return fmt.Errorf("failed to terminate container: %w", err)
+		}
+	}
+
+	return nil
+}
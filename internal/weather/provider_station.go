@@ -0,0 +1,285 @@
+package weather
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const stationProviderName = "station"
+
+// ErrStationNotActive indicates a station upload arrived while the weather
+// service is not running with the local station provider.
+var ErrStationNotActive = errors.Newf("local weather station provider is not active").
+	Component("weather").Category(errors.CategoryConfiguration).Build()
+
+// StationReading is a single observation pushed by a local weather station,
+// converted to metric units: Celsius, percent, hPa, m/s, degrees, mm/h and
+// W/m². Measurements the station did not report are NaN.
+type StationReading struct {
+	Time           time.Time
+	Temperature    float64
+	HeatIndex      float64
+	WindChill      float64
+	Humidity       float64
+	Pressure       float64
+	WindSpeed      float64
+	WindGust       float64
+	WindDir        float64
+	RainRate       float64
+	SolarRadiation float64
+}
+
+// NewStationReading returns a reading taken at t with every measurement unset.
+func NewStationReading(t time.Time) StationReading {
+	nan := math.NaN()
+	return StationReading{
+		Time:           t,
+		Temperature:    nan,
+		HeatIndex:      nan,
+		WindChill:      nan,
+		Humidity:       nan,
+		Pressure:       nan,
+		WindSpeed:      nan,
+		WindGust:       nan,
+		WindDir:        nan,
+		RainRate:       nan,
+		SolarRadiation: nan,
+	}
+}
+
+// hasMeasurements reports whether the reading carries any measurement.
+func (r *StationReading) hasMeasurements() bool {
+	for _, v := range []float64{
+		r.Temperature, r.Humidity, r.Pressure, r.WindSpeed, r.WindGust,
+		r.WindDir, r.RainRate, r.SolarRadiation,
+	} {
+		if !math.IsNaN(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// stationMean accumulates the mean of a measurement, skipping unset values.
+type stationMean struct {
+	sum float64
+	n   int
+}
+
+func (m *stationMean) add(v float64) {
+	if !math.IsNaN(v) {
+		m.sum += v
+		m.n++
+	}
+}
+
+// value returns the mean, or NaN when no value was added.
+func (m *stationMean) value() float64 {
+	if m.n == 0 {
+		return math.NaN()
+	}
+	return m.sum / float64(m.n)
+}
+
+// stationAggregate holds the running aggregate of the readings received
+// since the previous fetch. Keeping running sums rather than the readings
+// bounds memory regardless of how often the station pushes.
+type stationAggregate struct {
+	readings       int
+	latest         time.Time
+	temperature    stationMean
+	tempMin        float64
+	tempMax        float64
+	heatIndex      stationMean
+	windChill      stationMean
+	humidity       stationMean
+	pressure       stationMean
+	windSpeed      stationMean
+	windGust       float64
+	windX, windY   stationMean // unit vector of the wind direction
+	rainRate       stationMean
+	solarRadiation stationMean
+}
+
+func (a *stationAggregate) add(r *StationReading) {
+	if a.readings == 0 {
+		a.tempMin, a.tempMax, a.windGust = math.NaN(), math.NaN(), math.NaN()
+	}
+	a.readings++
+	if r.Time.After(a.latest) {
+		a.latest = r.Time
+	}
+
+	a.temperature.add(r.Temperature)
+	if !math.IsNaN(r.Temperature) {
+		// math.Min/Max propagate NaN, so seed them with the first value
+		if math.IsNaN(a.tempMin) {
+			a.tempMin, a.tempMax = r.Temperature, r.Temperature
+		}
+		a.tempMin = math.Min(a.tempMin, r.Temperature)
+		a.tempMax = math.Max(a.tempMax, r.Temperature)
+	}
+	a.heatIndex.add(r.HeatIndex)
+	a.windChill.add(r.WindChill)
+	a.humidity.add(r.Humidity)
+	a.pressure.add(r.Pressure)
+	a.windSpeed.add(r.WindSpeed)
+
+	// The strongest gust wins; a gust is never weaker than the mean speed
+	gust := r.WindGust
+	if math.IsNaN(gust) {
+		gust = r.WindSpeed
+	}
+	if !math.IsNaN(gust) && (math.IsNaN(a.windGust) || gust > a.windGust) {
+		a.windGust = gust
+	}
+
+	// Directions are averaged as vectors so 350° and 10° average to 0°, not 180°
+	if !math.IsNaN(r.WindDir) {
+		rad := r.WindDir * math.Pi / 180
+		a.windX.add(math.Sin(rad))
+		a.windY.add(math.Cos(rad))
+	}
+	a.rainRate.add(r.RainRate)
+	a.solarRadiation.add(r.SolarRadiation)
+}
+
+// windDirection returns the mean wind direction in whole degrees [0, 360).
+func (a *stationAggregate) windDirection() int {
+	if a.windX.n == 0 {
+		return 0
+	}
+	deg := math.Atan2(a.windX.value(), a.windY.value()) * 180 / math.Pi
+	return int(math.Round(deg+360)) % 360
+}
+
+// StationProvider implements the Provider interface for a local weather
+// station. Instead of calling an API it aggregates the readings pushed to it
+// since the previous fetch, so each poll stores the mean conditions of the
+// last interval.
+type StationProvider struct {
+	mu  sync.Mutex
+	agg stationAggregate
+}
+
+// NewStationProvider creates a new local weather station provider.
+func NewStationProvider() *StationProvider {
+	return &StationProvider{}
+}
+
+// Receive adds a reading to the current interval. Readings without any
+// measurement are ignored.
+func (p *StationProvider) Receive(r StationReading) {
+	if !r.hasMeasurements() {
+		return
+	}
+	p.mu.Lock()
+	p.agg.add(&r)
+	p.mu.Unlock()
+}
+
+// FetchWeather returns the aggregate of the readings received since the
+// previous call and starts a new interval. It returns ErrWeatherNoData when
+// no reading with a temperature arrived in the interval.
+func (p *StationProvider) FetchWeather(ctx context.Context, settings *conf.Settings) (*WeatherData, error) {
+	p.mu.Lock()
+	agg := p.agg
+	p.agg = stationAggregate{}
+	p.mu.Unlock()
+
+	temp := agg.temperature.value()
+	if math.IsNaN(temp) {
+		if agg.readings > 0 {
+			getLogger().Debug("Discarding station readings without temperature",
+				logger.Int("readings", agg.readings))
+		}
+		return nil, ErrWeatherNoData
+	}
+
+	measurements := weatherMeasurements{
+		temp:      temp,
+		heatIndex: agg.heatIndex.value(),
+		windChill: agg.windChill.value(),
+		windSpeed: orZero(agg.windSpeed.value()),
+		windGust:  orZero(agg.windGust),
+		pressure:  orZero(agg.pressure.value()),
+	}
+	humidity := orZero(agg.humidity.value())
+	solarRadiation := orZero(agg.solarRadiation.value())
+	rainRate := max(0, orZero(agg.rainRate.value()))
+
+	iconCode := InferWundergroundIcon(temp, rainRate, humidity, solarRadiation, measurements.windGust)
+	precipType := ""
+	if rainRate > 0 {
+		precipType = precipTypeFromIconCode(iconCode)
+	}
+
+	if settings.Realtime.Weather.Debug {
+		getLogger().Debug("Aggregated station readings",
+			logger.Int("readings", agg.readings),
+			logger.Float64("temperature", temp),
+			logger.Float64("rain_rate", rainRate))
+	}
+
+	return &WeatherData{
+		Time: agg.latest,
+		Location: Location{
+			Latitude:  settings.BirdNET.Latitude,
+			Longitude: settings.BirdNET.Longitude,
+		},
+		Temperature: Temperature{
+			Current:   temp,
+			FeelsLike: calculateFeelsLike(measurements),
+			Min:       agg.tempMin,
+			Max:       agg.tempMax,
+		},
+		Wind: Wind{
+			Speed: measurements.windSpeed,
+			Deg:   agg.windDirection(),
+			Gust:  measurements.windGust,
+		},
+		Precipitation: Precipitation{
+			Amount: rainRate,
+			Type:   precipType,
+		},
+		Pressure:       int(math.Round(measurements.pressure)),
+		Humidity:       int(math.Round(humidity)),
+		SolarRadiation: solarRadiation,
+		WeatherMain:    weatherMainFromIconCode(iconCode),
+		Description:    GetIconDescription(iconCode),
+		Icon:           string(iconCode),
+	}, nil
+}
+
+// orZero maps an unset (NaN) measurement to zero for storage.
+func orZero(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return v
+}
+
+// ReceiveStationReading hands a reading pushed by a local weather station to
+// the running weather service. It returns ErrStationNotActive when the
+// service is not running or uses another provider.
+func ReceiveStationReading(r StationReading) error {
+	globalServiceMu.RLock()
+	svc := globalService
+	globalServiceMu.RUnlock()
+
+	if svc == nil {
+		return ErrStationNotActive
+	}
+	station, ok := svc.provider.(*StationProvider)
+	if !ok {
+		return ErrStationNotActive
+	}
+	station.Receive(r)
+	return nil
+}
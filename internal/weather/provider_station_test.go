@@ -0,0 +1,114 @@
+package weather
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStationReading(t time.Time, opts ...func(*StationReading)) StationReading {
+	r := NewStationReading(t)
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+func TestStationProvider_AggregatesInterval(t *testing.T) {
+	t.Parallel()
+
+	settings := createTestSettings(t, "station")
+	provider := NewStationProvider()
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	provider.Receive(testStationReading(start, func(r *StationReading) {
+		r.Temperature, r.Humidity, r.Pressure = 10, 80, 1010
+		r.WindSpeed, r.WindGust, r.WindDir = 2, 4, 350
+		r.RainRate, r.SolarRadiation = 0, 100
+	}))
+	provider.Receive(testStationReading(start.Add(time.Minute), func(r *StationReading) {
+		r.Temperature, r.Humidity, r.Pressure = 14, 70, 1012
+		r.WindSpeed, r.WindGust, r.WindDir = 4, 9, 10
+		r.RainRate, r.SolarRadiation = 2, 300
+	}))
+	// A reading missing most sensors only contributes what it has
+	provider.Receive(testStationReading(start.Add(2*time.Minute), func(r *StationReading) {
+		r.Temperature = 12
+	}))
+	// Readings without any measurement are ignored
+	provider.Receive(NewStationReading(start.Add(3 * time.Minute)))
+
+	data, err := provider.FetchWeather(t.Context(), settings)
+	require.NoError(t, err)
+
+	assert.Equal(t, start.Add(2*time.Minute), data.Time, "the record is stamped with the latest reading")
+	assert.InDelta(t, 12.0, data.Temperature.Current, 0.001)
+	assert.InDelta(t, 10.0, data.Temperature.Min, 0.001)
+	assert.InDelta(t, 14.0, data.Temperature.Max, 0.001)
+	assert.Equal(t, 75, data.Humidity)
+	assert.Equal(t, 1011, data.Pressure)
+	assert.InDelta(t, 3.0, data.Wind.Speed, 0.001)
+	assert.InDelta(t, 9.0, data.Wind.Gust, 0.001, "the strongest gust of the interval")
+	assert.Equal(t, 0, data.Wind.Deg, "350° and 10° average to north")
+	assert.InDelta(t, 1.0, data.Precipitation.Amount, 0.001)
+	assert.Equal(t, "rain", data.Precipitation.Type)
+	assert.InDelta(t, 200.0, data.SolarRadiation, 0.001)
+	assert.InDelta(t, settings.BirdNET.Latitude, data.Location.Latitude, 0.0001)
+	assert.NotEmpty(t, data.Icon)
+
+	// The next interval starts empty
+	_, err = provider.FetchWeather(t.Context(), settings)
+	require.ErrorIs(t, err, ErrWeatherNoData)
+}
+
+func TestStationProvider_RequiresTemperature(t *testing.T) {
+	t.Parallel()
+
+	provider := NewStationProvider()
+	provider.Receive(testStationReading(time.Now(), func(r *StationReading) {
+		r.WindSpeed = 3
+	}))
+
+	_, err := provider.FetchWeather(t.Context(), createTestSettings(t, "station"))
+	require.ErrorIs(t, err, ErrWeatherNoData)
+}
+
+func TestStationProvider_FeelsLikeUsesWindChill(t *testing.T) {
+	t.Parallel()
+
+	provider := NewStationProvider()
+	provider.Receive(testStationReading(time.Now(), func(r *StationReading) {
+		r.Temperature, r.WindSpeed, r.WindChill = -5, 6, -11
+	}))
+
+	data, err := provider.FetchWeather(t.Context(), createTestSettings(t, "station"))
+	require.NoError(t, err)
+	assert.InDelta(t, -11.0, data.Temperature.FeelsLike, 0.001)
+	assert.Equal(t, 0, data.Wind.Deg, "no direction reported")
+}
+
+func TestReceiveStationReading(t *testing.T) {
+	// Not parallel: swaps the package-level service registration
+	reading := testStationReading(time.Now(), func(r *StationReading) { r.Temperature = 5 })
+
+	UnregisterService()
+	require.ErrorIs(t, ReceiveStationReading(reading), ErrStationNotActive)
+
+	yrno, err := NewService(createTestSettings(t, "yrno"), nil, nil)
+	require.NoError(t, err)
+	RegisterService(yrno)
+	t.Cleanup(UnregisterService)
+	require.ErrorIs(t, ReceiveStationReading(reading), ErrStationNotActive,
+		"readings are refused while another provider is active")
+
+	station, err := NewService(createTestSettings(t, "station"), nil, nil)
+	require.NoError(t, err)
+	RegisterService(station)
+	require.NoError(t, ReceiveStationReading(reading))
+
+	data, err := station.provider.FetchWeather(t.Context(), station.settings)
+	require.NoError(t, err)
+	assert.InDelta(t, 5.0, data.Temperature.Current, 0.001)
+}
@@ -459,11 +459,12 @@ func mapWundergroundResponse(wuResp *wundergroundResponse, log logger.Logger) *W
 			Amount: precipMMH,
 			Type:   precipType,
 		},
-		Pressure:    int(math.Round(measurements.pressure)),
-		Humidity:    int(math.Round(obs.Humidity)),
-		WeatherMain: weatherMainFromIconCode(iconCode),
-		Description: GetIconDescription(iconCode),
-		Icon:        string(iconCode),
+		Pressure:       int(math.Round(measurements.pressure)),
+		Humidity:       int(math.Round(obs.Humidity)),
+		SolarRadiation: max(0, obs.SolarRadiation),
+		WeatherMain:    weatherMainFromIconCode(iconCode),
+		Description:    GetIconDescription(iconCode),
+		Icon:           string(iconCode),
 	}
 }
 
@@ -0,0 +1,89 @@
+package weather
+
+import (
+	"context"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// stationMQTTRetryInterval is the delay between broker connection attempts.
+	stationMQTTRetryInterval = 30 * time.Second
+	// stationMQTTDisconnectQuiesce is how long in-flight work may take on shutdown, in milliseconds.
+	stationMQTTDisconnectQuiesce = 250
+)
+
+// subscribeMQTT subscribes to WeeWX loop packets when station MQTT is
+// enabled, feeding each packet to the provider until ctx is cancelled. An
+// empty broker reuses the broker and credentials of the MQTT integration.
+// The connection is retried in the background, so an unreachable broker
+// does not hold up the polling loop.
+func (p *StationProvider) subscribeMQTT(ctx context.Context, settings *conf.Settings) {
+	cfg := settings.Realtime.Weather.Station.MQTT
+	if !cfg.Enabled {
+		return
+	}
+
+	broker, username, password := cfg.Broker, cfg.Username, cfg.Password
+	if broker == "" {
+		broker = settings.Realtime.MQTT.Broker
+		username, password = settings.Realtime.MQTT.Username, settings.Realtime.MQTT.Password
+	}
+	if broker == "" {
+		getLogger().Warn("Weather station MQTT is enabled but no broker is configured")
+		return
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(settings.Main.Name + "-weather-station").
+		SetUsername(username).
+		SetPassword(password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(stationMQTTRetryInterval)
+
+	// Subscribe on every (re)connect since the session is not persistent
+	opts.SetOnConnectHandler(func(client mqtt.Client) {
+		token := client.Subscribe(cfg.Topic, 0, p.handleMQTTMessage)
+		go func() {
+			if token.Wait() && token.Error() != nil {
+				getLogger().Warn("Failed to subscribe to weather station topic",
+					logger.String("topic", cfg.Topic),
+					logger.Error(token.Error()))
+				return
+			}
+			getLogger().Info("Subscribed to weather station topic",
+				logger.String("broker", broker),
+				logger.String("topic", cfg.Topic))
+		}()
+	})
+	opts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		getLogger().Warn("Weather station MQTT connection lost, reconnecting",
+			logger.Error(err))
+	})
+
+	client := mqtt.NewClient(opts)
+	client.Connect()
+
+	go func() {
+		<-ctx.Done()
+		client.Disconnect(stationMQTTDisconnectQuiesce)
+	}()
+}
+
+// handleMQTTMessage parses a WeeWX loop packet and adds it to the current interval.
+func (p *StationProvider) handleMQTTMessage(_ mqtt.Client, msg mqtt.Message) {
+	reading, err := ParseWeeWXPacket(msg.Payload(), time.Now())
+	if err != nil {
+		getLogger().Debug("Ignoring weather station MQTT message",
+			logger.String("topic", msg.Topic()),
+			logger.Error(err))
+		return
+	}
+	p.Receive(reading)
+}
@@ -0,0 +1,304 @@
+package weather
+
+import (
+	"encoding/json"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Unit conversion factors for station uploads
+const (
+	MphToMs   = 0.44704  // Convert mph to m/s
+	KnotToMs  = 0.514444 // Convert knots to m/s
+	InHgToHPa = 33.8639  // Convert inches of mercury to hPa
+	MmHgToHPa = 1.33322  // Convert millimetres of mercury to hPa
+	InchToMM  = 25.4     // Convert inches to millimetres
+
+	// stationMissingValue is the placeholder some stations send for a sensor
+	// that is not connected; anything at or below it is treated as unset.
+	stationMissingValue = -9999
+
+	// stationClockSkew is how far a station timestamp may be from the time
+	// of receipt before the receipt time is used instead.
+	stationClockSkew = 10 * time.Minute
+
+	// stationUploadTimeLayout is the layout of the Ecowitt/Ambient dateutc field.
+	stationUploadTimeLayout = "2006-01-02 15:04:05"
+)
+
+// WeeWX unit systems as sent in the usUnits field of a loop packet
+const (
+	weewxUnitsUS       = 1
+	weewxUnitsMetric   = 16
+	weewxUnitsMetricWX = 17
+)
+
+// ErrStationNoReadings indicates a station upload that carried no known measurement.
+var ErrStationNoReadings = errors.Newf("weather station upload contains no readings").
+	Component("weather").Category(errors.CategoryValidation).Build()
+
+// ParseStationUpload parses an Ecowitt or Ambient Weather "custom server"
+// upload. Both send the same imperial form fields (tempf, humidity,
+// baromrelin, windspeedmph, windgustmph, winddir, rainratein, solarradiation
+// and dateutc), Ecowitt as a POST form and Ambient as a GET query string.
+// now is the time of receipt, used when the upload has no usable timestamp.
+func ParseStationUpload(values url.Values, now time.Time) (StationReading, error) {
+	r := NewStationReading(stationTime(parseUploadTime(values.Get("dateutc")), now))
+
+	if f := formValue(values, "tempf"); !math.IsNaN(f) {
+		r.Temperature = FahrenheitToCelsius(f)
+	}
+	if f := formValue(values, "heatindexf"); !math.IsNaN(f) {
+		r.HeatIndex = FahrenheitToCelsius(f)
+	}
+	if f := formValue(values, "windchillf"); !math.IsNaN(f) {
+		r.WindChill = FahrenheitToCelsius(f)
+	}
+	r.Humidity = formValue(values, "humidity")
+	r.Pressure = formValue(values, "baromrelin", "baromabsin") * InHgToHPa
+	r.WindSpeed = formValue(values, "windspeedmph") * MphToMs
+	r.WindGust = formValue(values, "windgustmph") * MphToMs
+	r.WindDir = formValue(values, "winddir")
+	// Ambient reports the rain rate as hourlyrainin
+	r.RainRate = formValue(values, "rainratein", "hourlyrainin") * InchToMM
+	r.SolarRadiation = formValue(values, "solarradiation")
+
+	if !r.hasMeasurements() {
+		return r, ErrStationNoReadings
+	}
+	return r, nil
+}
+
+// formValue returns the first of the given form fields holding a valid
+// number, or NaN when none does.
+func formValue(values url.Values, keys ...string) float64 {
+	for _, key := range keys {
+		if v, ok := stationNumber(values.Get(key)); ok {
+			return v
+		}
+	}
+	return math.NaN()
+}
+
+// parseUploadTime parses the dateutc field, returning the zero time when it
+// is absent, "now" or malformed.
+func parseUploadTime(s string) time.Time {
+	t, err := time.ParseInLocation(stationUploadTimeLayout, strings.TrimSpace(s), time.UTC)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// ParseWeeWXPacket parses a WeeWX loop packet as published in JSON by the
+// weewx-mqtt extension with aggregation enabled. Field names may carry a
+// unit suffix (outTemp_F, windSpeed_kph, barometer_mbar); unsuffixed fields
+// are in the unit system named by usUnits. Values may be numbers or strings.
+// now is the time of receipt, used when the packet has no usable dateTime.
+func ParseWeeWXPacket(data []byte, now time.Time) (StationReading, error) {
+	var packet map[string]any
+	if err := json.Unmarshal(data, &packet); err != nil {
+		return NewStationReading(now), errors.New(err).
+			Component("weather").
+			Category(errors.CategoryValidation).
+			Context("operation", "parse_weewx_packet").
+			Build()
+	}
+
+	system := weewxUnitsUS
+	if v, _, ok := weewxValue(packet, "usUnits"); ok {
+		system = int(v)
+	}
+	var packetTime time.Time
+	if v, _, ok := weewxValue(packet, "dateTime"); ok {
+		sec, frac := math.Modf(v)
+		packetTime = time.Unix(int64(sec), int64(frac*1e9)).UTC()
+	}
+
+	r := NewStationReading(stationTime(packetTime, now))
+	r.Temperature = weewxMeasurement(packet, system, weewxTemperature, "outTemp")
+	r.HeatIndex = weewxMeasurement(packet, system, weewxTemperature, "heatindex")
+	r.WindChill = weewxMeasurement(packet, system, weewxTemperature, "windchill")
+	r.Humidity = weewxMeasurement(packet, system, weewxPlain, "outHumidity")
+	r.Pressure = weewxMeasurement(packet, system, weewxPressure, "barometer", "pressure", "altimeter")
+	r.WindSpeed = weewxMeasurement(packet, system, weewxSpeed, "windSpeed")
+	r.WindGust = weewxMeasurement(packet, system, weewxSpeed, "windGust")
+	r.WindDir = weewxMeasurement(packet, system, weewxPlain, "windDir")
+	r.RainRate = weewxMeasurement(packet, system, weewxRainRate, "rainRate")
+	r.SolarRadiation = weewxMeasurement(packet, system, weewxPlain, "radiation")
+
+	if !r.hasMeasurements() {
+		return r, ErrStationNoReadings
+	}
+	return r, nil
+}
+
+// weewxQuantity selects the unit conversion applied to a WeeWX field.
+type weewxQuantity int
+
+const (
+	weewxPlain weewxQuantity = iota // percent, degrees and W/m², used as is
+	weewxTemperature
+	weewxSpeed
+	weewxPressure
+	weewxRainRate
+)
+
+// weewxMeasurement returns the first of the given fields present in the
+// packet, converted to metric, or NaN when none is present in a known unit.
+func weewxMeasurement(packet map[string]any, system int, quantity weewxQuantity, names ...string) float64 {
+	for _, name := range names {
+		v, unit, ok := weewxValue(packet, name)
+		if !ok {
+			continue
+		}
+		if unit == "" {
+			unit = weewxDefaultUnit(quantity, system)
+		}
+		if converted, ok := weewxConvert(quantity, v, unit); ok {
+			return converted
+		}
+	}
+	return math.NaN()
+}
+
+// weewxValue looks up a field by its bare name or by its name followed by a
+// unit suffix, returning the value and the suffix.
+func weewxValue(packet map[string]any, name string) (value float64, unit string, ok bool) {
+	if raw, found := packet[name]; found {
+		if v, ok := weewxNumber(raw); ok {
+			return v, "", true
+		}
+	}
+	prefix := name + "_"
+	for key, raw := range packet {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if v, ok := weewxNumber(raw); ok {
+			return v, key[len(prefix):], true
+		}
+	}
+	return 0, "", false
+}
+
+// weewxNumber converts a JSON number or numeric string to a float64.
+func weewxNumber(raw any) (float64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		return v, v > stationMissingValue
+	case string:
+		return stationNumber(v)
+	default:
+		return 0, false
+	}
+}
+
+// weewxDefaultUnit returns the unit of an unsuffixed field in a unit system.
+func weewxDefaultUnit(quantity weewxQuantity, system int) string {
+	switch quantity {
+	case weewxTemperature:
+		if system == weewxUnitsUS {
+			return "F"
+		}
+		return "C"
+	case weewxSpeed:
+		switch system {
+		case weewxUnitsMetric:
+			return "kph"
+		case weewxUnitsMetricWX:
+			return "mps"
+		default:
+			return "mph"
+		}
+	case weewxPressure:
+		if system == weewxUnitsUS {
+			return "inHg"
+		}
+		return "mbar"
+	case weewxRainRate:
+		switch system {
+		case weewxUnitsMetric:
+			return "cm_per_hour"
+		case weewxUnitsMetricWX:
+			return "mm_per_hour"
+		default:
+			return "inch_per_hour"
+		}
+	default:
+		return ""
+	}
+}
+
+// weewxConvert converts a value in a WeeWX unit, either the full unit name
+// or the short label weewx-mqtt appends, to metric. It reports false for a
+// unit it does not know.
+func weewxConvert(quantity weewxQuantity, v float64, unit string) (float64, bool) {
+	switch quantity {
+	case weewxTemperature:
+		switch unit {
+		case "F", "degree_F":
+			return FahrenheitToCelsius(v), true
+		case "C", "degree_C":
+			return v, true
+		}
+	case weewxSpeed:
+		switch unit {
+		case "mph", "mile_per_hour", "mile_per_hour2":
+			return v * MphToMs, true
+		case "kph", "km_per_hour", "km_per_hour2":
+			return v * KmhToMs, true
+		case "mps", "meter_per_second", "meter_per_second2":
+			return v, true
+		case "knot", "knot2":
+			return v * KnotToMs, true
+		}
+	case weewxPressure:
+		switch unit {
+		case "inHg", "inch_Hg":
+			return v * InHgToHPa, true
+		case "mbar", "hPa":
+			return v, true
+		case "mmHg", "mm_Hg":
+			return v * MmHgToHPa, true
+		case "kPa":
+			return v * 10, true
+		}
+	case weewxRainRate:
+		switch unit {
+		case "inch_per_hour":
+			return v * InchToMM, true
+		case "mm_per_hour":
+			return v, true
+		case "cm_per_hour":
+			return v * 10, true
+		}
+	case weewxPlain:
+		return v, true
+	}
+	return 0, false
+}
+
+// stationNumber parses a numeric upload value, rejecting empty, malformed,
+// non-finite and missing-sensor values.
+func stationNumber(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) || v <= stationMissingValue {
+		return 0, false
+	}
+	return v, true
+}
+
+// stationTime returns the station timestamp t, or now when t is unset or
+// further than stationClockSkew from now.
+func stationTime(t, now time.Time) time.Time {
+	if t.IsZero() || t.Sub(now).Abs() > stationClockSkew {
+		return now
+	}
+	return t
+}
@@ -0,0 +1,191 @@
+package weather
+
+import (
+	"math"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStationUpload_Ecowitt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 35, 0, 0, time.UTC)
+	values := url.Values{
+		"PASSKEY":        {"ABCDEF"},
+		"stationtype":    {"GW1000B_V1.7.3"},
+		"dateutc":        {"2024-05-01 12:34:56"},
+		"tempf":          {"68.0"},
+		"humidity":       {"55"},
+		"baromrelin":     {"29.921"},
+		"baromabsin":     {"29.5"},
+		"winddir":        {"270"},
+		"windspeedmph":   {"10.0"},
+		"windgustmph":    {"20.0"},
+		"rainratein":     {"0.100"},
+		"solarradiation": {"512.3"},
+	}
+
+	r, err := ParseStationUpload(values, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 34, 56, 0, time.UTC), r.Time)
+	assert.InDelta(t, 20.0, r.Temperature, 0.01)
+	assert.InDelta(t, 55.0, r.Humidity, 0.01)
+	assert.InDelta(t, 1013.2, r.Pressure, 0.1, "relative pressure takes precedence")
+	assert.InDelta(t, 270.0, r.WindDir, 0.01)
+	assert.InDelta(t, 4.47, r.WindSpeed, 0.01)
+	assert.InDelta(t, 8.94, r.WindGust, 0.01)
+	assert.InDelta(t, 2.54, r.RainRate, 0.01)
+	assert.InDelta(t, 512.3, r.SolarRadiation, 0.01)
+	assert.True(t, math.IsNaN(r.HeatIndex), "unreported measurements stay unset")
+}
+
+func TestParseStationUpload_Ambient(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	// Ambient reports the rain rate as hourlyrainin and may send dateutc=now
+	values, err := url.ParseQuery("PASSKEY=00:11:22:33:44:55&dateutc=now&tempf=32&hourlyrainin=0.5&baromabsin=30&solarradiation=-9999")
+	require.NoError(t, err)
+
+	r, err := ParseStationUpload(values, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, now, r.Time)
+	assert.InDelta(t, 0.0, r.Temperature, 0.01)
+	assert.InDelta(t, 12.7, r.RainRate, 0.01)
+	assert.InDelta(t, 1015.9, r.Pressure, 0.1)
+	assert.True(t, math.IsNaN(r.SolarRadiation), "-9999 marks a missing sensor")
+	assert.True(t, math.IsNaN(r.WindSpeed))
+}
+
+func TestParseStationUpload_Rejects(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	tests := []struct {
+		name   string
+		values url.Values
+	}{
+		{"empty", url.Values{}},
+		{"only metadata", url.Values{"PASSKEY": {"x"}, "stationtype": {"WS2900"}}},
+		{"malformed values", url.Values{"tempf": {"warm"}, "humidity": {""}}},
+		{"non-finite values", url.Values{"tempf": {"NaN"}, "humidity": {"Inf"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseStationUpload(tt.values, now)
+			require.ErrorIs(t, err, ErrStationNoReadings)
+		})
+	}
+}
+
+func TestParseStationUpload_ClockSkew(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	values := url.Values{"tempf": {"50"}, "dateutc": {"2024-05-01 13:00:00"}}
+
+	r, err := ParseStationUpload(values, now)
+	require.NoError(t, err)
+	assert.Equal(t, now, r.Time, "a station clock an hour off falls back to the receipt time")
+}
+
+func TestParseWeeWXPacket(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1714567890, 0).UTC()
+	tests := []struct {
+		name   string
+		packet string
+		check  func(t *testing.T, r StationReading)
+	}{
+		{
+			name: "weewx-mqtt string values with unit labels",
+			packet: `{"dateTime": "1714567890.0", "usUnits": "1.0", "outTemp_F": "68.0",
+				"outHumidity": "55.0", "barometer_inHg": "29.921", "windSpeed_mph": "10.0",
+				"windGust_mph": "20.0", "windDir": "90.0", "rainRate_inch_per_hour": "0.1",
+				"radiation_Wpm2": "300.0", "heatindex_F": "68.0", "windchill_F": "68.0"}`,
+			check: func(t *testing.T, r StationReading) {
+				t.Helper()
+				assert.Equal(t, now, r.Time)
+				assert.InDelta(t, 20.0, r.Temperature, 0.01)
+				assert.InDelta(t, 20.0, r.HeatIndex, 0.01)
+				assert.InDelta(t, 55.0, r.Humidity, 0.01)
+				assert.InDelta(t, 1013.2, r.Pressure, 0.1)
+				assert.InDelta(t, 4.47, r.WindSpeed, 0.01)
+				assert.InDelta(t, 8.94, r.WindGust, 0.01)
+				assert.InDelta(t, 90.0, r.WindDir, 0.01)
+				assert.InDelta(t, 2.54, r.RainRate, 0.01)
+				assert.InDelta(t, 300.0, r.SolarRadiation, 0.01)
+			},
+		},
+		{
+			name: "metric labels",
+			packet: `{"dateTime": 1714567890, "outTemp_C": 21.5, "barometer_mbar": 1008.4,
+				"windSpeed_kph": 36, "windGust_meter_per_second": 12, "rainRate_mm_per_hour": 3.2}`,
+			check: func(t *testing.T, r StationReading) {
+				t.Helper()
+				assert.InDelta(t, 21.5, r.Temperature, 0.01)
+				assert.InDelta(t, 1008.4, r.Pressure, 0.01)
+				assert.InDelta(t, 10.0, r.WindSpeed, 0.01)
+				assert.InDelta(t, 12.0, r.WindGust, 0.01)
+				assert.InDelta(t, 3.2, r.RainRate, 0.01)
+			},
+		},
+		{
+			name:   "unlabelled METRIC values follow usUnits",
+			packet: `{"dateTime": 1714567890, "usUnits": 16, "outTemp": 10.0, "windSpeed": 18.0, "rainRate": 0.5, "pressure": 990.0}`,
+			check: func(t *testing.T, r StationReading) {
+				t.Helper()
+				assert.InDelta(t, 10.0, r.Temperature, 0.01)
+				assert.InDelta(t, 5.0, r.WindSpeed, 0.01, "METRIC speeds are km/h")
+				assert.InDelta(t, 5.0, r.RainRate, 0.01, "METRIC rain rates are cm/h")
+				assert.InDelta(t, 990.0, r.Pressure, 0.01, "station pressure is used without a barometer field")
+			},
+		},
+		{
+			name:   "unlabelled METRICWX values follow usUnits",
+			packet: `{"usUnits": 17, "outTemp": 10.0, "windSpeed": 3.0, "rainRate": 0.5}`,
+			check: func(t *testing.T, r StationReading) {
+				t.Helper()
+				assert.Equal(t, now, r.Time, "a packet without dateTime uses the receipt time")
+				assert.InDelta(t, 3.0, r.WindSpeed, 0.01)
+				assert.InDelta(t, 0.5, r.RainRate, 0.01)
+			},
+		},
+		{
+			name:   "null and unknown-unit fields stay unset",
+			packet: `{"outTemp_F": null, "outHumidity": 80, "windSpeed_furlong": 3}`,
+			check: func(t *testing.T, r StationReading) {
+				t.Helper()
+				assert.True(t, math.IsNaN(r.Temperature))
+				assert.True(t, math.IsNaN(r.WindSpeed))
+				assert.InDelta(t, 80.0, r.Humidity, 0.01)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := ParseWeeWXPacket([]byte(tt.packet), now)
+			require.NoError(t, err)
+			tt.check(t, r)
+		})
+	}
+}
+
+func TestParseWeeWXPacket_Rejects(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseWeeWXPacket([]byte(`{"outTemp_F": `), time.Now())
+	require.Error(t, err)
+
+	_, err = ParseWeeWXPacket([]byte(`{"dateTime": 1714567890, "usUnits": 1}`), time.Now())
+	require.ErrorIs(t, err, ErrStationNoReadings)
+}
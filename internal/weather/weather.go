@@ -250,9 +250,11 @@ type WeatherData struct {
 	Visibility    int
 	Pressure      int
 	Humidity      int
-	WeatherMain   string // high-level condition category, e.g. "Rain", "Clouds", "Clear"
-	Description   string
-	Icon          string
+	// SolarRadiation is in W/m², zero when the provider does not measure it
+	SolarRadiation float64
+	WeatherMain    string // high-level condition category, e.g. "Rain", "Clouds", "Clear"
+	Description    string
+	Icon           string
 }
 
 type Location struct {
@@ -305,6 +307,10 @@ func NewService(settings *conf.Settings, db datastore.Interface, weatherMetrics
 	case conf.WeatherWunderground:
 		provider = NewWundergroundProvider(weatherClient)
 		providerName = wundergroundProviderName
+	case conf.WeatherStation:
+		// Readings are pushed by the station; polling only stores them
+		provider = NewStationProvider()
+		providerName = stationProviderName
 	case "":
 		// Not configured - default to yr.no
 		provider = NewYrNoProvider(weatherClient)
@@ -452,6 +458,7 @@ func (s *Service) saveWeatherData(data *WeatherData) error {
 		Clouds:            data.Clouds,
 		Precipitation:     data.Precipitation.Amount,
 		PrecipitationType: data.Precipitation.Type,
+		SolarRadiation:    data.SolarRadiation,
 		WeatherMain:       data.WeatherMain,
 		WeatherDesc:       data.Description,
 		WeatherIcon:       data.Icon,
@@ -521,17 +528,23 @@ func validateWeatherData(data *datastore.HourlyWeather) error {
 // StartPolling starts the weather polling service
 func (s *Service) StartPolling(stopChan <-chan struct{}) {
 	// Poll interval is read once at startup; the ticker cadence is not
-	// hot-reloadable without a service restart.
-	interval := time.Duration(s.settings.Realtime.Weather.PollInterval) * time.Minute
+	// hot-reloadable without a service restart. A local station pushes its
+	// readings, so its shorter record interval sets the cadence instead.
+	intervalMinutes, defaultMinutes := s.settings.Realtime.Weather.PollInterval, conf.DefaultWeatherPollInterval
+	if s.providerName == stationProviderName {
+		intervalMinutes, defaultMinutes = s.settings.Realtime.Weather.Station.Interval, conf.DefaultWeatherStationInterval
+	}
+	interval := time.Duration(intervalMinutes) * time.Minute
 	if interval <= 0 {
 		// PollInterval is normally validated to >= 15 minutes (conf
 		// validate_realtime), but StartPolling reads the raw setting and
 		// time.NewTicker panics on a non-positive interval, which would crash
 		// this long-lived goroutine. Fall back to the default instead.
 		getLogger().Warn("Invalid weather poll interval, using default",
-			logger.Int("configured_minutes", s.settings.Realtime.Weather.PollInterval),
-			logger.Int("default_minutes", conf.DefaultWeatherPollInterval))
-		interval = time.Duration(conf.DefaultWeatherPollInterval) * time.Minute
+			logger.Int("configured_minutes", intervalMinutes),
+			logger.Int("default_minutes", defaultMinutes))
+		intervalMinutes = defaultMinutes
+		interval = time.Duration(defaultMinutes) * time.Minute
 	}
 
 	// Derive a context that is cancelled when stopChan closes (or when this
@@ -553,7 +566,16 @@ func (s *Service) StartPolling(stopChan <-chan struct{}) {
 	// Use the dedicated weather logger
 	getLogger().Info("Starting weather polling service",
 		logger.String("provider", s.providerName),
-		logger.Int("interval_minutes", s.settings.Realtime.Weather.PollInterval))
+		logger.Int("interval_minutes", intervalMinutes))
+
+	// WeeWX loop packets arrive over MQTT for as long as the service runs
+	if station, ok := s.provider.(*StationProvider); ok {
+		if s.settings.Realtime.Weather.Station.PassKey == "" {
+			getLogger().Warn("Weather station PASSKEY is not configured, HTTP uploads will be refused",
+				logger.String("setting", "realtime.weather.station.passkey"))
+		}
+		station.subscribeMQTT(ctx, s.settings)
+	}
 
 	// Delay initial fetch to reduce startup DB contention with other services
 	if s.startupDelay > 0 {
@@ -36,6 +36,7 @@ func TestNewService(t *testing.T) {
 		{"yrno_provider", "yrno", false},
 		{"openweather_provider", "openweather", false},
 		{"wunderground_provider", "wunderground", false},
+		{"station_provider", "station", false},
 		{"invalid_provider_disabled", "invalid", true},
 		{"empty_provider_defaults_to_yrno", "", false},
 		{"none_provider_disabled", "none", true},